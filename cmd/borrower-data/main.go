@@ -0,0 +1,110 @@
+// Command borrower-data answers data-protection requests from the command
+// line. It exports everything held about a borrower as a ZIP archive, or
+// erases the borrower's personal data:
+//
+//	borrower-data [-tenant slug] [-o file] export <borrower-id>
+//	borrower-data [-tenant slug] erase <borrower-id>
+//
+// It reads DATABASE_URL like the server does.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"library-management-go/internal/audit"
+	"library-management-go/internal/config"
+	"library-management-go/internal/database"
+	"library-management-go/internal/export"
+	"library-management-go/internal/repository/gormstore"
+	"library-management-go/internal/reqctx"
+	"library-management-go/internal/services"
+	"library-management-go/internal/tenant"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"gorm.io/gorm/logger"
+)
+
+func main() {
+	tenantSlug := flag.String("tenant", "", "tenant slug (required in multi-tenant mode)")
+	output := flag.String("o", "", "file to write the export to (default standard output)")
+	actor := flag.String("actor", "borrower-data", "actor recorded in the audit log")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: borrower-data [flags] export|erase <borrower-id>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	id, err := uuid.Parse(flag.Arg(1))
+	if err != nil {
+		log.Fatal("Invalid borrower ID:", err)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+	cfg := config.Load()
+
+	db, err := database.Open(cfg.DatabaseURL, logger.Default.LogMode(logger.Silent))
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	if err := tenant.Register(db); err != nil {
+		log.Fatal("Failed to register tenant callbacks:", err)
+	}
+	if err := audit.Register(db); err != nil {
+		log.Fatal("Failed to register audit callbacks:", err)
+	}
+
+	store := gormstore.New(db)
+	ctx := reqctx.WithActor(context.Background(), *actor)
+	if *tenantSlug != "" {
+		t, err := services.NewTenantService(store).GetTenantBySlug(*tenantSlug)
+		if err != nil {
+			log.Fatal("Failed to resolve tenant:", err)
+		}
+		ctx = reqctx.WithTenantID(ctx, t.ID)
+	} else if cfg.MultiTenant {
+		log.Fatal("-tenant is required in multi-tenant mode")
+	}
+
+	privacyService := services.NewPrivacyService(store, cfg.HistoryRetention).WithContext(ctx)
+
+	switch flag.Arg(0) {
+	case "export":
+		data, err := privacyService.GetBorrowerData(id)
+		if err != nil {
+			log.Fatal("Failed to export borrower data:", err)
+		}
+
+		var w io.WriteCloser = os.Stdout
+		if *output != "" {
+			if w, err = os.Create(*output); err != nil {
+				log.Fatal("Failed to create output file:", err)
+			}
+		}
+		if err := export.WriteBorrowerArchive(w, data); err != nil {
+			log.Fatal("Failed to write archive:", err)
+		}
+		if err := w.Close(); err != nil {
+			log.Fatal("Failed to write archive:", err)
+		}
+	case "erase":
+		if _, err := privacyService.EraseBorrower(id); err != nil {
+			log.Fatal("Failed to erase borrower:", err)
+		}
+		log.Printf("Borrower %s erased", id)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,436 @@
+// Package audit records every create, update and delete of the library's
+// entities as models.AuditLog rows. It hooks into GORM's callback chain so
+// all writes made by the services package are captured without each
+// service having to remember to log them.
+//
+// The actor recorded is whatever the transport put in the request context
+// (see package reqctx). For staff requests that is the unauthenticated
+// X-Actor header, so it is advisory: it says who the caller claims to be.
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/reqctx"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+
+	beforeKey   = "audit:before"
+	affectedKey = "audit:affected"
+)
+
+// auditedTables maps the audited tables to the entity type recorded for them.
+var auditedTables = map[string]string{
+	"authors":             "author",
+	"books":               "book",
+	"borrowers":           "borrower",
+	"borrowings":          "borrowing",
+	"branches":            "branch",
+	"locations":           "location",
+	"transfers":           "transfer",
+	"tenants":             "tenant",
+	"borrower_categories": "borrower_category",
+	"borrower_blocks":     "borrower_block",
+	"holds":               "hold",
+	"fines":               "fine",
+	"subjects":            "subject",
+	"publishers":          "publisher",
+	"series":              "series",
+	"works":               "work",
+	"sip_terminals":       "sip_terminal",
+	"book_contributors":   "book_contributor",
+	"book_subjects":       "book_subject",
+}
+
+// ownerFields names the field that identifies the rows of audited join
+// tables, which have no ID of their own: their entries are recorded
+// against the record they belong to.
+var ownerFields = map[string]string{
+	"book_contributors": "BookID",
+	"book_subjects":     "BookID",
+}
+
+// ignoredFields are left out of diffs because they change on every write.
+var ignoredFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+// fieldChange is one entry of the JSON diff stored in AuditLog.Changes.
+type fieldChange struct {
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+}
+
+// Register installs the audit callbacks on db. Audit rows are written inside
+// the same transaction as the change they describe, so a failure to record
+// one rolls the change back.
+func Register(db *gorm.DB) error {
+	cb := db.Callback()
+
+	if err := cb.Create().After("gorm:after_create").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_create", afterCreate); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("audit:before_update", captureBefore); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:after_update").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_update", afterUpdate); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("audit:before_delete", captureBefore); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:after_delete").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_delete", afterDelete)
+}
+
+func entityType(tx *gorm.DB) (string, bool) {
+	if tx.Statement.Schema == nil {
+		return "", false
+	}
+	entity, ok := auditedTables[tx.Statement.Schema.Table]
+	return entity, ok
+}
+
+func afterCreate(tx *gorm.DB) {
+	entity, ok := entityType(tx)
+	if !ok || tx.Error != nil || tx.Statement.RowsAffected == 0 {
+		return
+	}
+
+	eachRecord(tx, func(id string, record reflect.Value) {
+		write(tx, ActionCreate, entity, id, diff(nil, snapshot(tx, record)))
+	})
+}
+
+// captureBefore loads the stored state of the rows about to change so the
+// after callbacks can diff against it. Statements that name no record by
+// primary key (tx.Where(...).Delete(&T{}) and the like) have the rows
+// their conditions match loaded instead, see loadAffected.
+func captureBefore(tx *gorm.DB) {
+	if _, ok := entityType(tx); !ok || tx.Error != nil {
+		return
+	}
+
+	if !hasPrimaryKeys(tx) {
+		tx.InstanceSet(affectedKey, loadAffected(tx))
+		return
+	}
+
+	before := map[string]map[string]interface{}{}
+	eachRecord(tx, func(id string, _ reflect.Value) {
+		if id == "" {
+			return
+		}
+		stored := reflect.New(tx.Statement.Schema.ModelType)
+		err := tx.Session(&gorm.Session{NewDB: true}).Unscoped().
+			Where(tx.Statement.Schema.PrioritizedPrimaryField.DBName+" = ?", id).
+			Take(stored.Interface()).Error
+		if err == nil {
+			before[id] = snapshot(tx, stored.Elem())
+		}
+	})
+	tx.InstanceSet(beforeKey, before)
+}
+
+func afterUpdate(tx *gorm.DB) {
+	entity, ok := entityType(tx)
+	if !ok || tx.Error != nil || tx.Statement.RowsAffected == 0 {
+		return
+	}
+
+	before := storedBefore(tx)
+	recorded := false
+	eachRecord(tx, func(id string, record reflect.Value) {
+		if id == "" {
+			return
+		}
+		recorded = true
+		changes := diff(before[id], snapshot(tx, record))
+		if len(changes) == 0 {
+			return
+		}
+		write(tx, updateAction(changes), entity, id, changes)
+	})
+
+	// Bulk updates (Model(&T{}).Where(...).Update(...)) carry no primary
+	// key; diff the rows they matched against their state now.
+	if !recorded {
+		affected := affectedRows(tx)
+		after := reload(tx, affected)
+		for _, row := range affected {
+			if changes := diff(row.state, after[row.id]); len(changes) > 0 {
+				write(tx, updateAction(changes), entity, row.id, changes)
+			}
+		}
+	}
+}
+
+func updateAction(changes map[string]fieldChange) string {
+	if change, ok := changes["deleted_at"]; ok && change.Old != nil && change.New == nil {
+		return ActionRestore
+	}
+	return ActionUpdate
+}
+
+func afterDelete(tx *gorm.DB) {
+	entity, ok := entityType(tx)
+	if !ok || tx.Error != nil || tx.Statement.RowsAffected == 0 {
+		return
+	}
+
+	// Unscoped deletes remove the row for good; the rest are soft deletes
+	action := ActionDelete
+	if tx.Statement.Unscoped {
+		action = ActionPurge
+	}
+
+	before := storedBefore(tx)
+	eachRecord(tx, func(id string, record reflect.Value) {
+		if id == "" {
+			return
+		}
+		old, ok := before[id]
+		if !ok {
+			old = snapshot(tx, record)
+		}
+		write(tx, action, entity, id, diff(old, nil))
+	})
+
+	// Bulk deletes name no record; record each row their conditions matched
+	for _, row := range affectedRows(tx) {
+		write(tx, action, entity, row.id, diff(row.state, nil))
+	}
+}
+
+// affectedRow is the stored state of a row a bulk statement matched.
+type affectedRow struct {
+	id    string
+	state map[string]interface{}
+}
+
+// hasPrimaryKeys reports whether the statement names its records by
+// primary key, as Save, Delete(&record) and Model(&record).Updates do.
+func hasPrimaryKeys(tx *gorm.DB) bool {
+	if tx.Statement.Schema.PrioritizedPrimaryField == nil {
+		return false
+	}
+	found := false
+	eachRecord(tx, func(id string, _ reflect.Value) {
+		found = found || id != ""
+	})
+	return found
+}
+
+// loadAffected loads the rows a bulk statement is about to change by
+// running its conditions as a query. Statements without conditions are
+// refused by gorm, so they change nothing.
+func loadAffected(tx *gorm.DB) []affectedRow {
+	where, ok := tx.Statement.Clauses["WHERE"]
+	if !ok {
+		return nil
+	}
+
+	query := tx.Session(&gorm.Session{NewDB: true})
+	if tx.Statement.Unscoped {
+		query = query.Unscoped()
+	}
+	records := reflect.New(reflect.SliceOf(tx.Statement.Schema.ModelType))
+	if err := query.Clauses(where.Expression).Find(records.Interface()).Error; err != nil {
+		tx.AddError(err)
+		return nil
+	}
+
+	var rows []affectedRow
+	field := recordIDField(tx)
+	for i := 0; i < records.Elem().Len(); i++ {
+		record := records.Elem().Index(i)
+		value, _ := field.ValueOf(tx.Statement.Context, record)
+		rows = append(rows, affectedRow{id: stringify(value), state: snapshot(tx, record)})
+	}
+	return rows
+}
+
+func affectedRows(tx *gorm.DB) []affectedRow {
+	if value, ok := tx.InstanceGet(affectedKey); ok {
+		if rows, ok := value.([]affectedRow); ok {
+			return rows
+		}
+	}
+	return nil
+}
+
+// reload returns the current state of the rows a bulk update matched, by
+// primary key.
+func reload(tx *gorm.DB, rows []affectedRow) map[string]map[string]interface{} {
+	field := tx.Statement.Schema.PrioritizedPrimaryField
+	if len(rows) == 0 || field == nil {
+		return nil
+	}
+
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.id
+	}
+	records := reflect.New(reflect.SliceOf(tx.Statement.Schema.ModelType))
+	err := tx.Session(&gorm.Session{NewDB: true}).Unscoped().
+		Where(field.DBName+" IN ?", ids).
+		Find(records.Interface()).Error
+	if err != nil {
+		tx.AddError(err)
+		return nil
+	}
+
+	after := map[string]map[string]interface{}{}
+	for i := 0; i < records.Elem().Len(); i++ {
+		record := records.Elem().Index(i)
+		value, _ := field.ValueOf(tx.Statement.Context, record)
+		after[stringify(value)] = snapshot(tx, record)
+	}
+	return after
+}
+
+// recordIDField returns the field whose value is recorded as the entity ID
+// of the statement's rows.
+func recordIDField(tx *gorm.DB) *schema.Field {
+	if name, ok := ownerFields[tx.Statement.Schema.Table]; ok {
+		return tx.Statement.Schema.LookUpField(name)
+	}
+	return tx.Statement.Schema.PrioritizedPrimaryField
+}
+
+func storedBefore(tx *gorm.DB) map[string]map[string]interface{} {
+	if value, ok := tx.InstanceGet(beforeKey); ok {
+		if before, ok := value.(map[string]map[string]interface{}); ok {
+			return before
+		}
+	}
+	return nil
+}
+
+// eachRecord calls fn for every struct the statement operates on, with its
+// primary key (empty when unset).
+func eachRecord(tx *gorm.DB, fn func(id string, record reflect.Value)) {
+	rv := reflect.Indirect(tx.Statement.ReflectValue)
+	field := recordIDField(tx)
+	if field == nil {
+		return
+	}
+
+	visit := func(record reflect.Value) {
+		record = reflect.Indirect(record)
+		if record.Kind() != reflect.Struct {
+			return
+		}
+		value, zero := field.ValueOf(tx.Statement.Context, record)
+		id := ""
+		if !zero {
+			id = stringify(value)
+		}
+		fn(id, record)
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			visit(rv.Index(i))
+		}
+	case reflect.Struct:
+		visit(rv)
+	}
+}
+
+func stringify(value interface{}) string {
+	if s, ok := value.(interface{ String() string }); ok {
+		return s.String()
+	}
+	b, _ := json.Marshal(value)
+	return string(b)
+}
+
+// snapshot returns the diffable state of record: its JSON fields plus the
+// soft-delete timestamp, which is hidden from JSON but matters for restores.
+func snapshot(tx *gorm.DB, record reflect.Value) map[string]interface{} {
+	m := toMap(record.Interface())
+	if m == nil {
+		return nil
+	}
+	if field := tx.Statement.Schema.LookUpField("DeletedAt"); field != nil {
+		value, zero := field.ValueOf(tx.Statement.Context, record)
+		if deletedAt, ok := value.(gorm.DeletedAt); ok && !zero && deletedAt.Valid {
+			m["deleted_at"] = deletedAt.Time
+		}
+	}
+	return m
+}
+
+// toMap flattens a model to its JSON representation, dropping nested
+// relations so only the entity's own columns are diffed.
+func toMap(v interface{}) map[string]interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil
+	}
+	for key, value := range m {
+		if _, nested := value.(map[string]interface{}); nested || ignoredFields[key] {
+			delete(m, key)
+		}
+	}
+	return m
+}
+
+func diff(before, after map[string]interface{}) map[string]fieldChange {
+	changes := map[string]fieldChange{}
+	for key, oldValue := range before {
+		newValue, ok := after[key]
+		if after != nil && ok && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		changes[key] = fieldChange{Old: oldValue, New: newValue}
+	}
+	for key, newValue := range after {
+		if _, seen := before[key]; !seen {
+			changes[key] = fieldChange{New: newValue}
+		}
+	}
+	return changes
+}
+
+func write(tx *gorm.DB, action, entity, id string, changes map[string]fieldChange) {
+	body, err := json.Marshal(changes)
+	if err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	ctx := tx.Statement.Context
+	entry := &models.AuditLog{
+		Actor:      reqctx.Actor(ctx),
+		Action:     action,
+		EntityType: entity,
+		EntityID:   id,
+		RequestID:  reqctx.RequestID(ctx),
+		Changes:    body,
+	}
+
+	if err := tx.Session(&gorm.Session{NewDB: true}).Create(entry).Error; err != nil {
+		tx.AddError(err)
+	}
+}
@@ -0,0 +1,164 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"library-management-go/internal/audit"
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/repository/gormstore"
+	"library-management-go/internal/reqctx"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func setup(t *testing.T) (*gorm.DB, repository.Store, *testutil.Fixtures) {
+	t.Helper()
+	db := testutil.NewDB(t)
+	store := gormstore.New(db)
+	return db, store, testutil.NewFixtures(t, store)
+}
+
+// entries returns the audit entries of one record, oldest first.
+func entries(t *testing.T, store repository.Store, entityType string, id uuid.UUID) []models.AuditLog {
+	t.Helper()
+	list, err := store.AuditLogs().ListForEntities(entityType, []uuid.UUID{id})
+	if err != nil {
+		t.Fatalf("list audit entries: %v", err)
+	}
+	return list
+}
+
+// changes decodes the diff of entry.
+func changes(t *testing.T, entry models.AuditLog) map[string]struct{ Old, New interface{} } {
+	t.Helper()
+	var diff map[string]struct{ Old, New interface{} }
+	if err := json.Unmarshal(entry.Changes, &diff); err != nil {
+		t.Fatalf("decode changes %s: %v", entry.Changes, err)
+	}
+	return diff
+}
+
+func actions(list []models.AuditLog) []string {
+	names := make([]string, len(list))
+	for i, entry := range list {
+		names[i] = entry.Action
+	}
+	return names
+}
+
+func TestRecordChanges(t *testing.T) {
+	_, store, _ := setup(t)
+	ctx := reqctx.WithRequestID(reqctx.WithActor(context.Background(), "librarian"), "req-1")
+	authors := store.WithContext(ctx).Authors()
+
+	author := &models.Author{Name: "Ursula Le Guin"}
+	if err := authors.Create(author); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	author.Biography = "Wrote Earthsea"
+	if err := authors.Update(author); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := authors.Delete(author); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := authors.Restore(author); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+
+	list := entries(t, store, "author", author.ID)
+	want := []string{audit.ActionCreate, audit.ActionUpdate, audit.ActionDelete, audit.ActionRestore}
+	if got := actions(list); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] || got[3] != want[3] {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+	if list[0].Actor != "librarian" || list[0].RequestID != "req-1" {
+		t.Fatalf("entry recorded for %q in %q", list[0].Actor, list[0].RequestID)
+	}
+	if diff := changes(t, list[1]); len(diff) != 1 || diff["biography"].New != "Wrote Earthsea" {
+		t.Fatalf("update changes = %s", list[1].Changes)
+	}
+}
+
+func TestRecordBulkStatements(t *testing.T) {
+	db, store, fx := setup(t)
+	db = db.WithContext(reqctx.WithActor(context.Background(), "cleanup"))
+	first := fx.Author(func(a *models.Author) { a.Name = "Bulk one" })
+	second := fx.Author(func(a *models.Author) { a.Name = "Bulk two" })
+	other := fx.Author(func(a *models.Author) { a.Name = "Left alone" })
+
+	err := db.Model(&models.Author{}).Where("name LIKE ?", "Bulk%").Update("biography", "rewritten").Error
+	if err != nil {
+		t.Fatalf("bulk update: %v", err)
+	}
+	for _, author := range []*models.Author{first, second} {
+		list := entries(t, store, "author", author.ID)
+		last := list[len(list)-1]
+		if last.Action != audit.ActionUpdate || last.Actor != "cleanup" || changes(t, last)["biography"].New != "rewritten" {
+			t.Fatalf("bulk update of %s recorded as %s %s", author.Name, last.Action, last.Changes)
+		}
+	}
+	if list := entries(t, store, "author", other.ID); len(list) != 1 {
+		t.Fatalf("expected the unmatched author to have only its create entry, got %v", actions(list))
+	}
+
+	if err := db.Where("name LIKE ?", "Bulk%").Delete(&models.Author{}).Error; err != nil {
+		t.Fatalf("bulk delete: %v", err)
+	}
+	list := entries(t, store, "author", first.ID)
+	if last := list[len(list)-1]; last.Action != audit.ActionDelete || changes(t, last)["name"].Old != "Bulk one" {
+		t.Fatalf("bulk delete recorded as %s %s", last.Action, last.Changes)
+	}
+
+	// Purging the trash is a bulk unscoped delete
+	purged, err := store.Authors().PurgeDeletedBefore(time.Now().Add(time.Minute))
+	if err != nil || purged != 2 {
+		t.Fatalf("purged %d authors, %v", purged, err)
+	}
+	list = entries(t, store, "author", second.ID)
+	if last := list[len(list)-1]; last.Action != audit.ActionPurge {
+		t.Fatalf("purge recorded as %s", last.Action)
+	}
+}
+
+func TestRecordJoinTables(t *testing.T) {
+	_, store, fx := setup(t)
+	book := fx.Book(nil)
+	original := book.Contributors[0].AuthorID
+	translator := fx.Author()
+
+	err := store.Books().SetContributors(book.ID, []models.BookContributor{
+		{AuthorID: original, Role: models.RoleAuthor},
+		{AuthorID: translator.ID, Role: models.RoleTranslator},
+	})
+	if err != nil {
+		t.Fatalf("set contributors: %v", err)
+	}
+	if err := store.Books().SetSubjects(book.ID, []uuid.UUID{fx.Subject(nil).ID}); err != nil {
+		t.Fatalf("set subjects: %v", err)
+	}
+	if err := store.Books().SetSubjects(book.ID, nil); err != nil {
+		t.Fatalf("clear subjects: %v", err)
+	}
+
+	// Replacing the credits deletes the old rows and creates the new ones,
+	// all recorded against the book
+	counts := map[string]int{}
+	translated := false
+	for _, entry := range entries(t, store, "book_contributor", book.ID) {
+		counts[entry.Action]++
+		translated = translated || entry.Action == audit.ActionCreate && changes(t, entry)["role"].New == models.RoleTranslator
+	}
+	if counts[audit.ActionCreate] != 3 || counts[audit.ActionDelete] != 1 || !translated {
+		t.Fatalf("contributor actions = %v, translator credited: %v", counts, translated)
+	}
+
+	if got := actions(entries(t, store, "book_subject", book.ID)); len(got) != 2 || got[0] != audit.ActionCreate || got[1] != audit.ActionDelete {
+		t.Fatalf("subject actions = %v", got)
+	}
+}
@@ -0,0 +1,132 @@
+// Package barcode encodes item and library card numbers as Code 128 or
+// Code 39 linear barcodes and renders them as images.
+//
+// A barcode is a row of modules, the narrowest bar or space width, each
+// either dark or light. Renderers scale the modules to the output and add
+// the quiet zone scanners need on either side.
+package barcode
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+)
+
+// Symbologies
+const (
+	Code128 = "code128"
+	Code39  = "code39"
+)
+
+// QuietZone is the number of light modules required before and after the
+// bars.
+const QuietZone = 10
+
+var (
+	// ErrUnsupportedSymbology is returned for a symbology other than
+	// Code128 or Code39.
+	ErrUnsupportedSymbology = errors.New("barcode: unsupported symbology")
+	// ErrInvalidData is returned for text the symbology cannot encode.
+	ErrInvalidData = errors.New("barcode: text cannot be encoded")
+)
+
+// Barcode is text encoded in a symbology.
+type Barcode struct {
+	Symbology string
+	Text      string
+	// Modules are the bars and spaces from left to right, true for a bar,
+	// without quiet zones.
+	Modules []bool
+}
+
+// Encode encodes text in symbology.
+func Encode(symbology, text string) (*Barcode, error) {
+	if text == "" {
+		return nil, ErrInvalidData
+	}
+	var (
+		modules []bool
+		err     error
+	)
+	switch symbology {
+	case Code128:
+		modules, err = encode128(text)
+	case Code39:
+		modules, err = encode39(text)
+	default:
+		return nil, ErrUnsupportedSymbology
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Barcode{Symbology: symbology, Text: text, Modules: modules}, nil
+}
+
+// Width is the width of the barcode in modules, including quiet zones.
+func (b *Barcode) Width() int {
+	return len(b.Modules) + 2*QuietZone
+}
+
+// Bars calls fn with the start and width, in modules from the left edge
+// of the quiet zone, of each bar.
+func (b *Barcode) Bars(fn func(start, width int)) {
+	start := -1
+	for i, dark := range b.Modules {
+		if dark && start < 0 {
+			start = i
+		}
+		if !dark && start >= 0 {
+			fn(QuietZone+start, i-start)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		fn(QuietZone+start, len(b.Modules)-start)
+	}
+}
+
+// Image renders the barcode in black on white with modules moduleWidth
+// pixels wide and bars height pixels tall.
+func (b *Barcode) Image(moduleWidth, height int) image.Image {
+	img := image.NewGray(image.Rect(0, 0, b.Width()*moduleWidth, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	b.Bars(func(start, width int) {
+		for y := 0; y < height; y++ {
+			for x := start * moduleWidth; x < (start+width)*moduleWidth; x++ {
+				img.SetGray(x, y, color.Gray{})
+			}
+		}
+	})
+	return img
+}
+
+// SVG renders the barcode as an SVG document with modules moduleWidth
+// units wide and bars height units tall.
+func (b *Barcode) SVG(moduleWidth, height int) []byte {
+	var sb strings.Builder
+	width := b.Width() * moduleWidth
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		width, height, width, height)
+	fmt.Fprintf(&sb, `<rect width="%d" height="%d" fill="#fff"/>`, width, height)
+	b.Bars(func(start, w int) {
+		fmt.Fprintf(&sb, `<rect x="%d" width="%d" height="%d"/>`, start*moduleWidth, w*moduleWidth, height)
+	})
+	sb.WriteString("</svg>\n")
+	return []byte(sb.String())
+}
+
+// appendWidths appends to modules the elements whose widths, in modules,
+// are the digits of widths, alternating bar and space and starting with a
+// bar.
+func appendWidths(modules []bool, widths string) []bool {
+	for i, w := range widths {
+		for n := 0; n < int(w-'0'); n++ {
+			modules = append(modules, i%2 == 0)
+		}
+	}
+	return modules
+}
@@ -0,0 +1,184 @@
+package barcode
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// widths returns the run lengths of modules, alternating bar and space.
+func widths(modules []bool) []int {
+	var runs []int
+	for i, m := range modules {
+		if i == 0 || m != modules[i-1] {
+			runs = append(runs, 0)
+		}
+		runs[len(runs)-1]++
+	}
+	return runs
+}
+
+func TestCode128Patterns(t *testing.T) {
+	seen := map[string]bool{}
+	for v, p := range code128Patterns {
+		bars, total := 0, 0
+		for i, w := range p {
+			total += int(w - '0')
+			if i%2 == 0 {
+				bars += int(w - '0')
+			}
+		}
+		// Every symbol is 11 modules with an even number of them dark; the
+		// stop pattern has a 2 module termination bar
+		want := 11
+		if v == code128Stop {
+			want = 13
+		}
+		if total != want || bars%2 != 0 || seen[p] {
+			t.Errorf("pattern %d %q is malformed or repeated", v, p)
+		}
+		seen[p] = true
+	}
+}
+
+func TestCode128Values(t *testing.T) {
+	tests := []struct {
+		text string
+		want []int
+	}{
+		// Short digit runs stay in code set B
+		{"PJJ123C", []int{104, 48, 42, 42, 17, 18, 19, 35}},
+		{"12345678", []int{105, 12, 34, 56, 78}},
+		{"1234567", []int{105, 12, 34, 56, 100, 23}},
+		{"AB123456", []int{104, 33, 34, 99, 12, 34, 56}},
+		{"AB12345", []int{104, 33, 34, 17, 99, 23, 45}},
+		{"A12345B", []int{104, 33, 17, 18, 19, 20, 21, 34}},
+		{"X1234567Y", []int{104, 56, 17, 99, 23, 45, 67, 100, 57}},
+	}
+	for _, tt := range tests {
+		got, err := code128Values(tt.text)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("code128Values(%q) = %v, %v; want %v", tt.text, got, err, tt.want)
+		}
+	}
+}
+
+func TestEncode128(t *testing.T) {
+	b, err := Encode(Code128, "PJJ123C")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Read the symbols back from the bar widths
+	runs := widths(b.Modules)
+	var values []int
+	for len(runs) > 0 {
+		n := 6
+		if len(runs) == 7 {
+			n = 7
+		}
+		var p strings.Builder
+		for _, w := range runs[:n] {
+			p.WriteByte(byte('0' + w))
+		}
+		runs = runs[n:]
+		found := false
+		for v, pattern := range code128Patterns {
+			if pattern == p.String() {
+				values = append(values, v)
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("no symbol with widths %s", p.String())
+		}
+	}
+	// The check symbol of the example in the Code 128 literature is 55
+	want := []int{104, 48, 42, 42, 17, 18, 19, 35, 55, code128Stop}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("decoded %v, want %v", values, want)
+	}
+
+	for _, text := range []string{"", "café", "tab\there"} {
+		if _, err := Encode(Code128, text); !errors.Is(err, ErrInvalidData) {
+			t.Errorf("Encode(%q) error = %v, want ErrInvalidData", text, err)
+		}
+	}
+}
+
+func TestCode39Patterns(t *testing.T) {
+	seen := map[string]bool{}
+	for c, p := range code39Patterns {
+		if len(p) != 9 || strings.Count(p, "w") != 3 || seen[p] {
+			t.Errorf("pattern for %q %q is malformed or repeated", c, p)
+		}
+		seen[p] = true
+	}
+	if len(seen) != 44 {
+		t.Errorf("%d patterns, want 44", len(seen))
+	}
+}
+
+func TestEncode39(t *testing.T) {
+	b, err := Encode(Code39, "LIB-42")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each character is 9 elements followed by a narrow gap
+	runs := widths(b.Modules)
+	var text strings.Builder
+	for i := 0; i < len(runs); i += 10 {
+		var p strings.Builder
+		for _, w := range runs[i : i+9] {
+			if w == code39Wide {
+				p.WriteByte('w')
+			} else {
+				p.WriteByte('n')
+			}
+		}
+		for c, pattern := range code39Patterns {
+			if pattern == p.String() {
+				text.WriteByte(c)
+			}
+		}
+	}
+	if text.String() != "*LIB-42*" {
+		t.Fatalf("decoded %q, want *LIB-42*", text.String())
+	}
+
+	for _, text := range []string{"lowercase", "A*B", "A_B"} {
+		if _, err := Encode(Code39, text); !errors.Is(err, ErrInvalidData) {
+			t.Errorf("Encode(%q) error = %v, want ErrInvalidData", text, err)
+		}
+	}
+	if _, err := Encode("qr", "A"); !errors.Is(err, ErrUnsupportedSymbology) {
+		t.Errorf("unknown symbology error = %v", err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	b, err := Encode(Code128, "30000000000018")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := b.Image(2, 40)
+	if img.Bounds().Dx() != b.Width()*2 || img.Bounds().Dy() != 40 {
+		t.Fatalf("image is %v", img.Bounds())
+	}
+	white := color.GrayModel.Convert(color.White)
+	if img.At(2*QuietZone-1, 0) != white || img.At(2*QuietZone, 39) == white {
+		t.Fatal("expected the bars to start after the quiet zone")
+	}
+
+	bars := 0
+	b.Bars(func(start, width int) { bars++ })
+	svg := b.SVG(1, 30)
+	if !bytes.HasPrefix(svg, []byte("<svg ")) || bytes.Count(svg, []byte("<rect")) != bars+1 {
+		t.Fatalf("unexpected SVG %s", svg)
+	}
+}
@@ -0,0 +1,103 @@
+package barcode
+
+// code128Patterns are the bar and space widths of the Code 128 symbol
+// values, with the stop pattern last.
+var code128Patterns = [...]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+// Code 128 function values
+const (
+	code128CodeC  = 99
+	code128CodeB  = 100
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// digitRun returns the number of consecutive digits in text from i.
+func digitRun(text string, i int) int {
+	n := 0
+	for i+n < len(text) && text[i+n] >= '0' && text[i+n] <= '9' {
+		n++
+	}
+	return n
+}
+
+// code128Values encodes text as Code 128 symbol values, starting with a
+// start code and without the check symbol. Printable ASCII is encoded in
+// code set B, switching to code set C, which packs two digits into a
+// symbol, for runs of digits long enough to pay for the switch.
+func code128Values(text string) ([]int, error) {
+	var values []int
+	inC := false
+	for i := 0; i < len(text); {
+		run := digitRun(text, i)
+		// Switching costs a symbol each way, so a run must save more
+		// than that; at either end of the text only one switch is needed.
+		// An odd digit is left over for code set B, before the run or,
+		// when the text starts in code set C, after it.
+		worthC := run >= 6 || (run >= 4 && (i == 0 || i+run == len(text)))
+
+		switch {
+		case inC && run >= 2:
+			values = append(values, int(text[i]-'0')*10+int(text[i+1]-'0'))
+			i += 2
+			continue
+		case !inC && worthC && (run%2 == 0 || i == 0):
+			if i == 0 {
+				values = append(values, code128StartC)
+			} else {
+				values = append(values, code128CodeC)
+			}
+			inC = true
+			continue
+		case inC:
+			values = append(values, code128CodeB)
+			inC = false
+			continue
+		}
+
+		if i == 0 {
+			values = append(values, code128StartB)
+		}
+		c := text[i]
+		if c < ' ' || c > '~' {
+			return nil, ErrInvalidData
+		}
+		values = append(values, int(c-' '))
+		i++
+	}
+	return values, nil
+}
+
+func encode128(text string) ([]bool, error) {
+	values, err := code128Values(text)
+	if err != nil {
+		return nil, err
+	}
+
+	// The check symbol is the sum of the values weighted by position,
+	// with the start code counting once
+	sum := values[0]
+	for i, v := range values[1:] {
+		sum += (i + 1) * v
+	}
+	values = append(values, sum%103, code128Stop)
+
+	var modules []bool
+	for _, v := range values {
+		modules = appendWidths(modules, code128Patterns[v])
+	}
+	return modules, nil
+}
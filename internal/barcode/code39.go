@@ -0,0 +1,53 @@
+package barcode
+
+import "strings"
+
+// code39Patterns are the Code 39 characters' bars and spaces, alternating
+// and starting with a bar, as n for narrow and w for wide.
+var code39Patterns = map[byte]string{
+	'0': "nnnwwnwnn", '1': "wnnwnnnnw", '2': "nnwwnnnnw", '3': "wnwwnnnnn", '4': "nnnwwnnnw",
+	'5': "wnnwwnnnn", '6': "nnwwwnnnn", '7': "nnnwnnwnw", '8': "wnnwnnwnn", '9': "nnwwnnwnn",
+	'A': "wnnnnwnnw", 'B': "nnwnnwnnw", 'C': "wnwnnwnnn", 'D': "nnnnwwnnw", 'E': "wnnnwwnnn",
+	'F': "nnwnwwnnn", 'G': "nnnnnwwnw", 'H': "wnnnnwwnn", 'I': "nnwnnwwnn", 'J': "nnnnwwwnn",
+	'K': "wnnnnnnww", 'L': "nnwnnnnww", 'M': "wnwnnnnwn", 'N': "nnnnwnnww", 'O': "wnnnwnnwn",
+	'P': "nnwnwnnwn", 'Q': "nnnnnnwww", 'R': "wnnnnnwwn", 'S': "nnwnnnwwn", 'T': "nnnnwnwwn",
+	'U': "wwnnnnnnw", 'V': "nwwnnnnnw", 'W': "wwwnnnnnn", 'X': "nwnnwnnnw", 'Y': "wwnnwnnnn",
+	'Z': "nwwnwnnnn", '-': "nwnnnnwnw", '.': "wwnnnnwnn", ' ': "nwwnnnwnn", '$': "nwnwnwnnn",
+	'/': "nwnwnnnwn", '+': "nwnnnwnwn", '%': "nnnwnwnwn", '*': "nwnnwnwnn",
+}
+
+// code39Wide is the width of a wide element in modules. The specification
+// allows two to three times the narrow width; the wider ratio reads more
+// reliably.
+const code39Wide = 3
+
+// encode39 encodes text between the '*' start and stop characters, which
+// text itself may not contain. Code 39 has no check character by default,
+// and library systems rarely use the optional one.
+func encode39(text string) ([]bool, error) {
+	if strings.Contains(text, "*") {
+		return nil, ErrInvalidData
+	}
+
+	var modules []bool
+	for _, c := range []byte("*" + text + "*") {
+		pattern, ok := code39Patterns[c]
+		if !ok {
+			return nil, ErrInvalidData
+		}
+		// Characters are separated by a narrow space
+		if len(modules) > 0 {
+			modules = append(modules, false)
+		}
+		for i, e := range pattern {
+			width := 1
+			if e == 'w' {
+				width = code39Wide
+			}
+			for n := 0; n < width; n++ {
+				modules = append(modules, i%2 == 0)
+			}
+		}
+	}
+	return modules, nil
+}
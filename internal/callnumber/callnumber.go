@@ -0,0 +1,172 @@
+// Package callnumber parses Dewey Decimal and Library of Congress call
+// numbers into sort keys. Call numbers do not sort as plain strings:
+// "QA76.73 .G63" is shelved before "QA76.9" because the class number is a
+// decimal, and "QA76.9" before "QA761" because the whole part is a number.
+// A sort key pads and separates the parts of a call number so that keys
+// compared byte by byte, as the database does, follow shelf order.
+package callnumber
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Classification schemes
+const (
+	Dewey = "dewey"
+	LC    = "lc"
+	// Local call numbers, such as "FIC ROW", sort by their words
+	Local = "local"
+)
+
+// ErrInvalid reports a call number that does not follow its scheme.
+var ErrInvalid = errors.New("invalid call number")
+
+var (
+	// deweyClass is the class number: three digits, optionally followed by
+	// a decimal fraction. Prime marks ("823/.914") are dropped beforehand.
+	deweyClass = regexp.MustCompile(`^(\d{1,3})(?:\.(\d+))?(?:\s+|$)`)
+	// lcClass is the class letters and number, as in "QA76.73"
+	lcClass = regexp.MustCompile(`^([A-Z]{1,3})\s*(\d{1,4})(?:\.(\d+))?`)
+	// cutterDot is the period that introduces a cutter, as in ".G63"
+	cutterDot = regexp.MustCompile(`\.([A-Z])`)
+	// enumeration is a volume, part or copy number such as "V.2"
+	enumeration = regexp.MustCompile(`^([A-Z]*)\.?(\d+)$`)
+)
+
+// Detect guesses the scheme callNumber is written in: Dewey if it starts
+// with a class number, LC if it starts with class letters and a number,
+// and Local otherwise.
+func Detect(callNumber string) string {
+	s := normalize(callNumber)
+	if deweyClass.MatchString(strings.ReplaceAll(s, "/", "")) {
+		return Dewey
+	}
+	if lcClass.MatchString(s) {
+		return LC
+	}
+	return Local
+}
+
+// SortKey returns the key that orders callNumber on the shelf among call
+// numbers of the same scheme.
+func SortKey(scheme, callNumber string) (string, error) {
+	s := normalize(callNumber)
+	if s == "" {
+		return "", ErrInvalid
+	}
+	switch scheme {
+	case Dewey:
+		return deweyKey(s)
+	case LC:
+		return lcKey(s)
+	case Local:
+		return tokensKey(strings.Fields(s)), nil
+	}
+	return "", fmt.Errorf("unknown classification scheme %q", scheme)
+}
+
+// normalize upper-cases s and collapses its whitespace.
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToUpper(s)), " ")
+}
+
+// deweyKey builds the key of a Dewey call number such as "823.914 ROW":
+// the whole part of the class number padded to three digits, its fraction,
+// then the remaining words.
+func deweyKey(s string) (string, error) {
+	s = strings.ReplaceAll(s, "/", "")
+	m := deweyClass.FindStringSubmatch(s)
+	if m == nil {
+		return "", ErrInvalid
+	}
+
+	key := fmt.Sprintf("%03s", m[1])
+	if m[2] != "" {
+		key += "." + m[2]
+	}
+	if rest := tokensKey(strings.Fields(s[len(m[0]):])); rest != "" {
+		key += " " + rest
+	}
+	return key, nil
+}
+
+// lcKey builds the key of a Library of Congress call number such as
+// "QA76.73.J38 S65 2019": the class letters padded to three, the whole part
+// of the class number padded to four digits, its fraction, then each
+// cutter and any date or volume.
+func lcKey(s string) (string, error) {
+	m := lcClass.FindStringSubmatch(s)
+	if m == nil {
+		return "", ErrInvalid
+	}
+	// The class number must end at a space or a cutter
+	rest := s[len(m[0]):]
+	if rest != "" && rest[0] != ' ' && rest[0] != '.' {
+		return "", ErrInvalid
+	}
+
+	key := fmt.Sprintf("%-3s%04s", m[1], m[2])
+	if m[3] != "" {
+		key += "." + m[3]
+	}
+	if rest := tokensKey(strings.Fields(cutterDot.ReplaceAllString(rest, " $1"))); rest != "" {
+		key += " " + rest
+	}
+	return key, nil
+}
+
+// tokensKey joins the words of a call number after its class number.
+// Cutters such as "G63" are decimals and already sort as strings; years
+// and enumerations such as "V.10" are whole numbers, padded so "V.2" sorts
+// before "V.10".
+func tokensKey(tokens []string) string {
+	keys := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		token = strings.Map(func(r rune) rune {
+			if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' {
+				return r
+			}
+			return -1
+		}, token)
+		token = strings.Trim(token, ".")
+		if token == "" {
+			continue
+		}
+		if m := enumeration.FindStringSubmatch(token); m != nil && (m[1] == "" || strings.Contains(token, ".")) {
+			token = fmt.Sprintf("%s%06s", m[1], m[2])
+		}
+		keys = append(keys, token)
+	}
+	return strings.Join(keys, " ")
+}
+
+// Range returns the keys bounding the call numbers from from up to and
+// including to, either of which may be empty. to also covers the call
+// numbers that extend it with further words: "QA76.9" covers "QA76.9 .G63"
+// but not "QA76.95". Call numbers of different schemes do not compare, so
+// when scheme is empty the bounds are taken to be in the scheme of the
+// first, which is returned. A call number is in range if its key is at
+// least start and less than end.
+func Range(scheme, from, to string) (rangeScheme, start, end string, err error) {
+	if scheme == "" && from != "" {
+		scheme = Detect(from)
+	} else if scheme == "" {
+		scheme = Detect(to)
+	}
+	if from != "" {
+		if start, err = SortKey(scheme, from); err != nil {
+			return "", "", "", err
+		}
+	}
+	if to != "" {
+		if end, err = SortKey(scheme, to); err != nil {
+			return "", "", "", err
+		}
+		// Keys use no byte below '!' but the space separating their parts
+		end += "!"
+	}
+	return scheme, start, end, nil
+}
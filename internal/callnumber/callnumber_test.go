@@ -0,0 +1,131 @@
+package callnumber
+
+import "testing"
+
+// checkShelfOrder fails unless the keys of callNumbers sort in the order
+// given.
+func checkShelfOrder(t *testing.T, scheme string, callNumbers []string) {
+	t.Helper()
+	prev := ""
+	for i, callNumber := range callNumbers {
+		key, err := SortKey(scheme, callNumber)
+		if err != nil {
+			t.Fatalf("SortKey(%q): %v", callNumber, err)
+		}
+		if i > 0 && key <= prev {
+			t.Fatalf("%q (key %q) does not sort after %q (key %q)", callNumber, key, callNumbers[i-1], prev)
+		}
+		prev = key
+	}
+}
+
+func TestLCShelfOrder(t *testing.T) {
+	checkShelfOrder(t, LC, []string{
+		"Q1 .A2",
+		"QA9 .B3",
+		"QA76 .C5",
+		"QA76.73 .G63",
+		"QA76.73.J38 S65 2014",
+		"qa76.73.j38 s65 2019",
+		"QA76.73.J38 S65 2019 v.2",
+		"QA76.73.J38 S65 2019 v.10",
+		"QA76.9",
+		"QA76.9 .D3",
+		"QA761 .B7",
+		"QB54 .S3",
+		"QH31 .D2",
+	})
+}
+
+func TestDeweyShelfOrder(t *testing.T) {
+	checkShelfOrder(t, Dewey, []string{
+		"5 ABC",
+		"5.1 ABC",
+		"005.133 J38",
+		"005.133 J38 2019",
+		"020",
+		"823 ROW",
+		"823/.914 AUS",
+		"823.914 ROW",
+		"823.914 ROW v.2",
+		"823.914 ROW v.10",
+		"823.92",
+	})
+}
+
+func TestSortKey(t *testing.T) {
+	tests := []struct {
+		scheme, callNumber, want string
+	}{
+		{LC, "QA76.73 .G63", "QA 0076.73 G63"},
+		{LC, "QA 76.73.G63  2019", "QA 0076.73 G63 002019"},
+		{Dewey, "823.914 Row", "823.914 ROW"},
+		{Local, "Fic  row", "FIC ROW"},
+	}
+	for _, tt := range tests {
+		got, err := SortKey(tt.scheme, tt.callNumber)
+		if err != nil || got != tt.want {
+			t.Fatalf("SortKey(%q, %q) = %q, %v; want %q", tt.scheme, tt.callNumber, got, err, tt.want)
+		}
+	}
+
+	invalid := []struct {
+		scheme, callNumber string
+	}{
+		{LC, "823.914 ROW"},
+		{LC, "QA"},
+		{LC, "QA76734"},
+		{Dewey, "QA76.73 .G63"},
+		{Dewey, "8234"},
+		{Local, "  "},
+		{"udc", "53"},
+	}
+	for _, tt := range invalid {
+		if key, err := SortKey(tt.scheme, tt.callNumber); err == nil {
+			t.Fatalf("SortKey(%q, %q) = %q, want an error", tt.scheme, tt.callNumber, key)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := map[string]string{
+		"823.914 ROW":  Dewey,
+		"005.133 J38":  Dewey,
+		"QA76.73 .G63": LC,
+		"PR 6068.O93":  LC,
+		"FIC ROW":      Local,
+		"J FIC SMI":    Local,
+	}
+	for callNumber, want := range tests {
+		if got := Detect(callNumber); got != want {
+			t.Fatalf("Detect(%q) = %q, want %q", callNumber, got, want)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	scheme, start, end, err := Range("", "QA76", "QA76.9")
+	if err != nil || scheme != LC {
+		t.Fatalf("Range = %q, %v; want the lc scheme", scheme, err)
+	}
+	for _, callNumber := range []string{"QA76", "QA76.9", "QA76.9 .D3", "QA76.73 .G63"} {
+		if key, _ := SortKey(LC, callNumber); key < start || key >= end {
+			t.Fatalf("%q should fall within QA76 to QA76.9", callNumber)
+		}
+	}
+	for _, callNumber := range []string{"QA75 .B2", "QA76.95", "QA77"} {
+		if key, _ := SortKey(LC, callNumber); key >= start && key < end {
+			t.Fatalf("%q should fall outside QA76 to QA76.9", callNumber)
+		}
+	}
+
+	if _, _, _, err := Range(Dewey, "", "QA76"); err == nil {
+		t.Fatal("expected an LC bound to be rejected in a Dewey range")
+	}
+	if scheme, _, _, err := Range("", "823", "824"); err != nil || scheme != Dewey {
+		t.Fatalf("Dewey range = %q, %v", scheme, err)
+	}
+	if scheme, start, _, _ := Range("", "", "823"); scheme != Dewey || start != "" {
+		t.Fatalf("open range = %q, %q", scheme, start)
+	}
+}
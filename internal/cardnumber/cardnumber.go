@@ -0,0 +1,73 @@
+// Package cardnumber generates and validates library card numbers. A card
+// number is 14 digits: the prefix 2 conventionally used for patron
+// barcodes, 12 random digits and a Luhn check digit that catches mistyped
+// or misread digits. Item barcodes are generated the same way with the
+// prefix 3.
+package cardnumber
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+const (
+	prefix     = "2"
+	itemPrefix = "3"
+	// Length is the number of digits in a card number.
+	Length = 14
+)
+
+// Generate returns a new random card number. Callers must still check it
+// is not already in use.
+func Generate() (string, error) {
+	return generate(prefix)
+}
+
+// GenerateItem returns a new random item barcode. Callers must still check
+// it is not already in use.
+func GenerateItem() (string, error) {
+	return generate(itemPrefix)
+}
+
+func generate(prefix string) (string, error) {
+	payload := []byte(prefix)
+	for len(payload) < Length-1 {
+		d, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		payload = append(payload, byte('0'+d.Int64()))
+	}
+	return string(append(payload, checkDigit(payload))), nil
+}
+
+// Valid reports whether number is a well-formed card number with a
+// correct check digit.
+func Valid(number string) bool {
+	if len(number) != Length || number[:len(prefix)] != prefix {
+		return false
+	}
+	for i := 0; i < len(number); i++ {
+		if number[i] < '0' || number[i] > '9' {
+			return false
+		}
+	}
+	return checkDigit([]byte(number[:Length-1])) == number[Length-1]
+}
+
+// checkDigit computes the Luhn check digit of payload.
+func checkDigit(payload []byte) byte {
+	sum := 0
+	for i := len(payload) - 1; i >= 0; i-- {
+		d := int(payload[i] - '0')
+		// Double every other digit, starting with the rightmost
+		if (len(payload)-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
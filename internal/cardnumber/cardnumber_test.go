@@ -0,0 +1,58 @@
+package cardnumber
+
+import "testing"
+
+func TestGenerate(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		number, err := Generate()
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if !Valid(number) {
+			t.Fatalf("generated card number %q is not valid", number)
+		}
+		seen[number] = true
+	}
+	if len(seen) < 100 {
+		t.Fatalf("expected 100 distinct card numbers, got %d", len(seen))
+	}
+}
+
+func TestGenerateItem(t *testing.T) {
+	number, err := GenerateItem()
+	if err != nil {
+		t.Fatalf("GenerateItem: %v", err)
+	}
+	if len(number) != Length || number[0] != '3' {
+		t.Fatalf("unexpected item barcode %q", number)
+	}
+	if checkDigit([]byte(number[:Length-1])) != number[Length-1] {
+		t.Fatalf("item barcode %q has a wrong check digit", number)
+	}
+	// Items and cards cannot be confused
+	if Valid(number) {
+		t.Fatalf("item barcode %q is a valid card number", number)
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		// 2000000000001 has Luhn check digit 4
+		{"20000000000014", true},
+		{"20000000000015", false},
+		{"20000000000041", false}, // transposed digits
+		{"10000000000014", false}, // wrong prefix
+		{"2000000000001", false},  // too short
+		{"2000000000001A", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := Valid(tt.number); got != tt.want {
+			t.Errorf("Valid(%q) = %v, want %v", tt.number, got, tt.want)
+		}
+	}
+}
@@ -3,22 +3,53 @@ package database
 import (
 	"fmt"
 	"log"
+	"reflect"
+	"strings"
 
+	"library-management-go/internal/cardnumber"
 	"library-management-go/internal/models"
 
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
+// Initialize connects to the database named by databaseURL. The scheme picks
+// the backend: postgres:// or postgresql:// for PostgreSQL, sqlite://<path>
+// for a SQLite file and sqlite://:memory: for a throwaway in-memory database.
 func Initialize(databaseURL string) (*gorm.DB, error) {
-	db, err := gorm.Open(postgres.Open(databaseURL), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+	db, err := Open(databaseURL, logger.Default.LogMode(logger.Info))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Println("Database connected successfully")
+	return db, nil
+}
+
+// Open connects like Initialize but logs SQL through gormLogger, which lets
+// tests silence it.
+func Open(databaseURL string, gormLogger logger.Interface) (*gorm.DB, error) {
+	dialector, err := dialectorFor(databaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: gormLogger,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Primary keys are generated here rather than by a column default so
+	// every backend assigns them the same way
+	if err := db.Callback().Create().Before("gorm:create").Register("database:assign_uuid", assignUUID); err != nil {
+		return nil, fmt.Errorf("failed to register callbacks: %w", err)
+	}
+
 	// Test the connection
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -29,21 +60,244 @@ func Initialize(databaseURL string) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Println("Database connected successfully")
+	if db.Dialector.Name() == "sqlite" {
+		// SQLite allows a single writer, and every connection to an
+		// in-memory database would otherwise see its own empty database
+		sqlDB.SetMaxOpenConns(1)
+		if err := db.Exec("PRAGMA foreign_keys = ON").Error; err != nil {
+			return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
+		}
+	}
+
 	return db, nil
 }
 
-func Migrate(db *gorm.DB) error {
-	err := db.AutoMigrate(
+func dialectorFor(databaseURL string) (gorm.Dialector, error) {
+	scheme, rest, ok := strings.Cut(databaseURL, "://")
+	if !ok {
+		return nil, fmt.Errorf("invalid DATABASE_URL %q: missing scheme", databaseURL)
+	}
+
+	switch scheme {
+	case "postgres", "postgresql":
+		return postgres.Open(databaseURL), nil
+	case "sqlite", "sqlite3":
+		if rest == "" {
+			return nil, fmt.Errorf("invalid DATABASE_URL %q: missing database path", databaseURL)
+		}
+		return sqlite.Open(rest), nil
+	default:
+		return nil, fmt.Errorf("unsupported DATABASE_URL scheme %q", scheme)
+	}
+}
+
+// assignUUID fills in zero uuid.UUID primary keys before an insert.
+func assignUUID(db *gorm.DB) {
+	if db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	if field == nil || field.FieldType != reflect.TypeOf(uuid.UUID{}) {
+		return
+	}
+
+	assign := func(record reflect.Value) {
+		if _, zero := field.ValueOf(db.Statement.Context, record); zero {
+			if err := field.Set(db.Statement.Context, record, uuid.New()); err != nil {
+				db.AddError(err)
+			}
+		}
+	}
+
+	switch rv := reflect.Indirect(db.Statement.ReflectValue); rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			assign(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		assign(rv)
+	}
+}
+
+// Models lists every model Migrate creates a table for.
+func Models() []interface{} {
+	return []interface{}{
+		&models.Tenant{},
 		&models.Author{},
+		&models.Branch{},
+		&models.Location{},
+		&models.Subject{},
+		&models.Publisher{},
+		&models.Series{},
+		&models.Work{},
 		&models.Book{},
+		&models.BookContributor{},
+		&models.BorrowerCategory{},
 		&models.Borrower{},
+		&models.BorrowerBlock{},
 		&models.Borrowing{},
-	)
+		&models.Hold{},
+		&models.Fine{},
+		&models.Transfer{},
+		&models.IdempotencyKey{},
+		&models.AuditLog{},
+		&models.SIPTerminal{},
+	}
+}
+
+func Migrate(db *gorm.DB) error {
+	// SIP terminals were first migrated under gorm's default table name
+	if db.Migrator().HasTable("s_ip_terminals") && !db.Migrator().HasTable(&models.SIPTerminal{}) {
+		if err := db.Migrator().RenameTable("s_ip_terminals", &models.SIPTerminal{}); err != nil {
+			return fmt.Errorf("failed to rename s_ip_terminals: %w", err)
+		}
+	}
+
+	err := db.AutoMigrate(Models()...)
 	if err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	// Unique indexes used to cover soft-deleted rows too; they have been
+	// replaced by partial indexes so a deleted ISBN or email can be reused,
+	// and those in turn by per-tenant indexes so two libraries can hold the
+	// same ISBN or register the same borrower.
+	legacyIndexes := []struct {
+		model interface{}
+		name  string
+	}{
+		{&models.Book{}, "idx_books_isbn"},
+		{&models.Borrower{}, "idx_borrowers_email"},
+		{&models.Book{}, "idx_books_isbn_active"},
+		{&models.Borrower{}, "idx_borrowers_email_active"},
+		{&models.Branch{}, "idx_branches_code_active"},
+	}
+	for _, idx := range legacyIndexes {
+		if db.Migrator().HasIndex(idx.model, idx.name) {
+			if err := db.Migrator().DropIndex(idx.model, idx.name); err != nil {
+				return fmt.Errorf("failed to drop legacy index %s: %w", idx.name, err)
+			}
+		}
+	}
+
+	if err := migrateBookAuthors(db); err != nil {
+		return fmt.Errorf("failed to migrate book authors: %w", err)
+	}
+
+	if err := backfillBorrowers(db); err != nil {
+		return fmt.Errorf("failed to backfill borrowers: %w", err)
+	}
+
+	if err := backfillBooks(db); err != nil {
+		return fmt.Errorf("failed to backfill books: %w", err)
+	}
+
+	if err := backfillTenantSettings(db); err != nil {
+		return fmt.Errorf("failed to backfill tenant settings: %w", err)
+	}
+
 	log.Println("Database migration completed successfully")
 	return nil
 }
+
+// migrateBookAuthors credits the single author books used to reference
+// through books.author_id as their author in book_contributors, then drops
+// the column.
+func migrateBookAuthors(db *gorm.DB) error {
+	db = db.Unscoped().Session(&gorm.Session{})
+	if !db.Migrator().HasColumn(&models.Book{}, "author_id") {
+		return nil
+	}
+
+	err := db.Exec(`INSERT INTO book_contributors (book_id, author_id, role, position)
+		SELECT id, author_id, ?, 0 FROM books
+		WHERE NOT EXISTS (SELECT 1 FROM book_contributors WHERE book_contributors.book_id = books.id)`,
+		models.RoleAuthor).Error
+	if err != nil {
+		return err
+	}
+
+	// SQLite drops a column by rebuilding the table, which would trip the
+	// foreign keys that point at books
+	if db.Dialector.Name() == "sqlite" {
+		if err := db.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+			return err
+		}
+		defer db.Exec("PRAGMA foreign_keys = ON")
+	}
+
+	if db.Migrator().HasConstraint(&models.Book{}, "fk_books_author") {
+		if err := db.Migrator().DropConstraint(&models.Book{}, "fk_books_author"); err != nil {
+			return err
+		}
+	}
+	if err := db.Migrator().DropColumn(&models.Book{}, "author_id"); err != nil {
+		return err
+	}
+
+	// Rebuilding the table loses its indexes
+	return db.AutoMigrate(&models.Book{})
+}
+
+// backfillBorrowers gives borrowers registered before library cards were
+// introduced a card number, and dates their membership from registration.
+func backfillBorrowers(db *gorm.DB) error {
+	db = db.Unscoped().Session(&gorm.Session{})
+
+	if err := db.Model(&models.Borrower{}).Where("membership_start IS NULL").
+		Update("membership_start", gorm.Expr("created_at")).Error; err != nil {
+		return err
+	}
+
+	var borrowers []models.Borrower
+	return db.Select("id").Where("card_number = ''").
+		FindInBatches(&borrowers, 100, func(tx *gorm.DB, batch int) error {
+			for _, borrower := range borrowers {
+				number, err := cardnumber.Generate()
+				if err != nil {
+					return err
+				}
+				if err := db.Model(&borrower).Update("card_number", number).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// backfillBooks gives books catalogued before item barcodes were
+// introduced a barcode.
+func backfillBooks(db *gorm.DB) error {
+	db = db.Unscoped().Session(&gorm.Session{})
+
+	var books []models.Book
+	return db.Select("id").Where("barcode = ''").
+		FindInBatches(&books, 100, func(tx *gorm.DB, batch int) error {
+			for _, book := range books {
+				barcode, err := cardnumber.GenerateItem()
+				if err != nil {
+					return err
+				}
+				if err := db.Model(&book).Update("barcode", barcode).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// backfillTenantSettings gives tenants created before a setting existed
+// its default value.
+func backfillTenantSettings(db *gorm.DB) error {
+	defaults := map[string]interface{}{
+		"loan_period_days": models.DefaultTenantSettings.LoanPeriodDays,
+		"max_renewals":     models.DefaultTenantSettings.MaxRenewals,
+		"fine_per_day":     models.DefaultTenantSettings.FinePerDay,
+	}
+	for column, value := range defaults {
+		if err := db.Model(&models.Tenant{}).Where(column+" IS NULL").Update(column, value).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"time"
+
+	"library-management-go/internal/models"
+)
+
+// ArchiveContentType is the MIME type of WriteBorrowerArchive's output.
+const ArchiveContentType = "application/zip"
+
+// archiveManifest describes the contents of a borrower archive.
+type archiveManifest struct {
+	BorrowerID  string    `json:"borrower_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Files       []string  `json:"files"`
+}
+
+// WriteBorrowerArchive writes data as a ZIP archive holding one JSON
+// document per kind of record, plus a manifest.json listing them.
+func WriteBorrowerArchive(w io.Writer, data *models.BorrowerData) error {
+	files := []struct {
+		name  string
+		value interface{}
+	}{
+		{"borrower.json", data.Borrower},
+		{"loans.json", orEmpty(data.Loans)},
+		{"fines.json", orEmpty(data.Fines)},
+		{"holds.json", orEmpty(data.Holds)},
+		{"blocks.json", orEmpty(data.Blocks)},
+		{"audit_log.json", orEmpty(data.AuditLog)},
+	}
+
+	manifest := archiveManifest{BorrowerID: data.Borrower.ID.String(), GeneratedAt: data.GeneratedAt}
+	for _, file := range files {
+		manifest.Files = append(manifest.Files, file.name)
+	}
+
+	zw := zip.NewWriter(w)
+	if err := writeJSONEntry(zw, "manifest.json", data.GeneratedAt, manifest); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := writeJSONEntry(zw, file.name, data.GeneratedAt, file.value); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeJSONEntry(zw *zip.Writer, name string, modified time.Time, value interface{}) error {
+	entry, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(entry)
+	enc.SetIndent("", "  ")
+	return enc.Encode(value)
+}
+
+// orEmpty keeps empty sections as [] rather than null.
+func orEmpty[T any](records []T) []T {
+	if records == nil {
+		return []T{}
+	}
+	return records
+}
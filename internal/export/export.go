@@ -0,0 +1,118 @@
+// Package export streams records as CSV, JSON Lines or XLSX without
+// buffering the whole result set in memory.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"strings"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+	FormatXLSX  = "xlsx"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+var contentTypes = map[string]string{
+	FormatCSV:   "text/csv; charset=utf-8",
+	FormatJSONL: "application/x-ndjson",
+	FormatXLSX:  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+var acceptedMediaTypes = map[string]string{
+	"text/csv":             FormatCSV,
+	"application/x-ndjson": FormatJSONL,
+	"application/jsonl":    FormatJSONL,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": FormatXLSX,
+}
+
+// Writer receives one record at a time. Tabular formats use row, JSON Lines
+// marshals value.
+type Writer interface {
+	Write(row []string, value interface{}) error
+	Close() error
+}
+
+// NegotiateFormat picks an export format from an explicit format parameter,
+// falling back to the Accept header and finally to CSV.
+func NegotiateFormat(format, accept string) (string, error) {
+	if format != "" {
+		format = strings.ToLower(format)
+		if _, ok := contentTypes[format]; !ok {
+			return "", ErrUnsupportedFormat
+		}
+		return format, nil
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if f, ok := acceptedMediaTypes[mediaType]; ok {
+			return f, nil
+		}
+	}
+
+	return FormatCSV, nil
+}
+
+// ContentType returns the MIME type served for format.
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// NewWriter returns a Writer for format. The header is written immediately
+// for tabular formats.
+func NewWriter(format string, w io.Writer, header []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return nil, err
+		}
+		return &csvWriter{w: cw}, nil
+	case FormatJSONL:
+		return &jsonlWriter{enc: json.NewEncoder(w)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w, header)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Write(row []string, _ interface{}) error {
+	if err := c.w.Write(row); err != nil {
+		return err
+	}
+	// Flush per row so output streams to the client as it is produced
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func (j *jsonlWriter) Write(_ []string, value interface{}) error {
+	return j.enc.Encode(value)
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
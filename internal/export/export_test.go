@@ -0,0 +1,96 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		accept string
+		want   string
+		err    error
+	}{
+		{"", "", FormatCSV, nil},
+		{"XLSX", "text/csv", FormatXLSX, nil},
+		{"pdf", "", "", ErrUnsupportedFormat},
+		{"", "application/json, application/x-ndjson;q=0.9", FormatJSONL, nil},
+		{"", "application/jsonl", FormatJSONL, nil},
+		{"", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", FormatXLSX, nil},
+		{"", "text/html, */*", FormatCSV, nil},
+	}
+	for _, tt := range tests {
+		got, err := NegotiateFormat(tt.format, tt.accept)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("NegotiateFormat(%q, %q) = %q, %v; want %q, %v", tt.format, tt.accept, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+type record struct {
+	Name string `json:"name"`
+}
+
+// write streams the records in format and returns the output.
+func write(t *testing.T, format string, names ...string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf, []string{"name"})
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	for _, name := range names {
+		if err := w.Write([]string{name}, record{name}); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestWriters(t *testing.T) {
+	names := []string{"Le Guin, Ursula", `Say "hello"`, "<Butler & co>"}
+
+	rows, err := csv.NewReader(bytes.NewReader(write(t, FormatCSV, names...))).ReadAll()
+	if err != nil || len(rows) != 4 || rows[0][0] != "name" || rows[2][0] != names[1] {
+		t.Fatalf("CSV rows %v, %v", rows, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(write(t, FormatJSONL, names...))), "\n")
+	var last record
+	if len(lines) != 3 || json.Unmarshal([]byte(lines[2]), &last) != nil || last.Name != names[2] {
+		t.Fatalf("JSON Lines %q", lines)
+	}
+
+	data := write(t, FormatXLSX, names...)
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("open XLSX: %v", err)
+	}
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			rc, _ := f.Open()
+			raw, _ := io.ReadAll(rc)
+			rc.Close()
+			sheet = string(raw)
+		}
+	}
+	if strings.Count(sheet, "<row>") != 4 || !strings.Contains(sheet, "&lt;Butler &amp; co&gt;") {
+		t.Fatalf("unexpected sheet %s", sheet)
+	}
+
+	if _, err := NewWriter("pdf", io.Discard, nil); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("NewWriter(pdf) error = %v", err)
+	}
+}
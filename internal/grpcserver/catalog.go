@@ -0,0 +1,355 @@
+package grpcserver
+
+import (
+	"context"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/pb/libraryv1"
+	"library-management-go/internal/services"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type CatalogServer struct {
+	libraryv1.UnimplementedCatalogServiceServer
+	authorService *services.AuthorService
+	bookService   *services.BookService
+}
+
+func NewCatalogServer(authorService *services.AuthorService, bookService *services.BookService) *CatalogServer {
+	return &CatalogServer{authorService: authorService, bookService: bookService}
+}
+
+func (s *CatalogServer) CreateAuthor(ctx context.Context, in *libraryv1.CreateAuthorRequest) (*libraryv1.Author, error) {
+	req := models.CreateAuthorRequest{
+		Name:      in.GetName(),
+		Biography: in.GetBiography(),
+	}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	author, err := s.authorService.WithContext(ctx).CreateAuthor(&req)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return authorToProto(author), nil
+}
+
+func (s *CatalogServer) GetAuthor(ctx context.Context, in *libraryv1.GetAuthorRequest) (*libraryv1.Author, error) {
+	id, err := parseID(in.GetId(), "invalid author ID")
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := s.authorService.WithContext(ctx).GetAuthor(id)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return authorToProto(author), nil
+}
+
+func (s *CatalogServer) ListAuthors(ctx context.Context, in *libraryv1.ListAuthorsRequest) (*libraryv1.ListAuthorsResponse, error) {
+	page, limit := pagination(in.GetPage())
+
+	var authors []models.Author
+	var total int64
+	var err error
+
+	if in.GetSearch() != "" {
+		authors, total, err = s.authorService.WithContext(ctx).SearchAuthors(in.GetSearch(), page, limit)
+	} else {
+		authors, total, err = s.authorService.WithContext(ctx).GetAllAuthors(page, limit)
+	}
+
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	resp := &libraryv1.ListAuthorsResponse{Pagination: pageInfo(page, limit, total)}
+	for i := range authors {
+		resp.Authors = append(resp.Authors, authorToProto(&authors[i]))
+	}
+	return resp, nil
+}
+
+func (s *CatalogServer) UpdateAuthor(ctx context.Context, in *libraryv1.UpdateAuthorRequest) (*libraryv1.Author, error) {
+	id, err := parseID(in.GetId(), "invalid author ID")
+	if err != nil {
+		return nil, err
+	}
+
+	req := models.UpdateAuthorRequest{
+		Name:      in.GetName(),
+		Biography: in.GetBiography(),
+	}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	author, err := s.authorService.WithContext(ctx).UpdateAuthor(id, &req)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return authorToProto(author), nil
+}
+
+func (s *CatalogServer) DeleteAuthor(ctx context.Context, in *libraryv1.DeleteAuthorRequest) (*emptypb.Empty, error) {
+	id, err := parseID(in.GetId(), "invalid author ID")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorService.WithContext(ctx).DeleteAuthor(id); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *CatalogServer) CreateBook(ctx context.Context, in *libraryv1.CreateBookRequest) (*libraryv1.Book, error) {
+	contributors, err := contributorsFromProto(in.GetContributors())
+	if err != nil {
+		return nil, err
+	}
+
+	subjectIDs, err := subjectIDsFromProto(in.GetSubjectIds())
+	if err != nil {
+		return nil, err
+	}
+
+	homeLocationID, err := parseOptionalID(in.GetHomeLocationId(), "invalid home location ID")
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := editionRefsFromProto(in.GetPublisherId(), in.GetSeriesId(), in.GetWorkId())
+	if err != nil {
+		return nil, err
+	}
+
+	req := models.CreateBookRequest{
+		Title:          in.GetTitle(),
+		ISBN:           in.GetIsbn(),
+		Barcode:        in.GetBarcode(),
+		Description:    in.GetDescription(),
+		Contributors:   contributors,
+		SubjectIDs:     subjectIDs,
+		PublishedAt:    timeFromProto(in.GetPublishedAt()),
+		HomeLocationID: homeLocationID,
+		PublisherID:    refs.publisherID,
+		Edition:        in.GetEdition(),
+		Format:         in.GetFormat(),
+		Pages:          int(in.GetPages()),
+		Language:       in.GetLanguage(),
+		SeriesID:       refs.seriesID,
+		SeriesVolume:   int(in.GetSeriesVolume()),
+		WorkID:         refs.workID,
+
+		CallNumber:           in.GetCallNumber(),
+		ClassificationScheme: in.GetClassificationScheme(),
+	}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	book, err := s.bookService.WithContext(ctx).CreateBook(&req)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return bookToProto(book), nil
+}
+
+// contributorsFromProto converts the requested credits, leaving them nil
+// when none are given.
+func contributorsFromProto(in []*libraryv1.ContributorInput) ([]models.ContributorRequest, error) {
+	var contributors []models.ContributorRequest
+	for _, c := range in {
+		authorID, err := parseID(c.GetAuthorId(), "invalid author ID")
+		if err != nil {
+			return nil, err
+		}
+		contributors = append(contributors, models.ContributorRequest{AuthorID: authorID, Role: c.GetRole()})
+	}
+	return contributors, nil
+}
+
+// editionRefs are the publisher, series and work a book request refers to.
+type editionRefs struct {
+	publisherID uuid.UUID
+	seriesID    uuid.UUID
+	workID      uuid.UUID
+}
+
+// editionRefsFromProto parses the optional publisher, series and work IDs
+// of a book request.
+func editionRefsFromProto(publisherID, seriesID, workID string) (editionRefs, error) {
+	var refs editionRefs
+	var err error
+	if refs.publisherID, err = parseOptionalID(publisherID, "invalid publisher ID"); err != nil {
+		return refs, err
+	}
+	if refs.seriesID, err = parseOptionalID(seriesID, "invalid series ID"); err != nil {
+		return refs, err
+	}
+	if refs.workID, err = parseOptionalID(workID, "invalid work ID"); err != nil {
+		return refs, err
+	}
+	return refs, nil
+}
+
+// subjectIDsFromProto parses the requested subject IDs, leaving them nil
+// when none are given.
+func subjectIDsFromProto(in []string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, value := range in {
+		id, err := parseID(value, "invalid subject ID")
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (s *CatalogServer) GetBook(ctx context.Context, in *libraryv1.GetBookRequest) (*libraryv1.Book, error) {
+	id, err := parseID(in.GetId(), "invalid book ID")
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := s.bookService.WithContext(ctx).GetBook(id)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return bookToProto(book), nil
+}
+
+func (s *CatalogServer) ListBooks(ctx context.Context, in *libraryv1.ListBooksRequest) (*libraryv1.ListBooksResponse, error) {
+	page, limit := pagination(in.GetPage())
+
+	branchID, err := parseOptionalID(in.GetBranchId(), "invalid branch ID")
+	if err != nil {
+		return nil, err
+	}
+
+	homeBranchID, err := parseOptionalID(in.GetHomeBranchId(), "invalid home branch ID")
+	if err != nil {
+		return nil, err
+	}
+
+	subjectID, err := parseOptionalID(in.GetSubjectId(), "invalid subject ID")
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := editionRefsFromProto(in.GetPublisherId(), in.GetSeriesId(), in.GetWorkId())
+	if err != nil {
+		return nil, err
+	}
+
+	filter := models.BookFilter{
+		Search:       in.GetSearch(),
+		BranchID:     branchID,
+		HomeBranchID: homeBranchID,
+		SubjectID:    subjectID,
+		PublisherID:  refs.publisherID,
+		SeriesID:     refs.seriesID,
+		WorkID:       refs.workID,
+		Format:       in.GetFormat(),
+		Language:     in.GetLanguage(),
+
+		Classification: in.GetClassification(),
+		CallNumberFrom: in.GetCallNumberFrom(),
+		CallNumberTo:   in.GetCallNumberTo(),
+		ShelfOrder:     in.GetShelfOrder(),
+	}
+	books, total, err := s.bookService.WithContext(ctx).FindBooks(filter, page, limit)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	resp := &libraryv1.ListBooksResponse{Pagination: pageInfo(page, limit, total)}
+	for i := range books {
+		resp.Books = append(resp.Books, bookToProto(&books[i]))
+	}
+	return resp, nil
+}
+
+func (s *CatalogServer) UpdateBook(ctx context.Context, in *libraryv1.UpdateBookRequest) (*libraryv1.Book, error) {
+	id, err := parseID(in.GetId(), "invalid book ID")
+	if err != nil {
+		return nil, err
+	}
+
+	contributors, err := contributorsFromProto(in.GetContributors())
+	if err != nil {
+		return nil, err
+	}
+
+	subjectIDs, err := subjectIDsFromProto(in.GetSubjectIds())
+	if err != nil {
+		return nil, err
+	}
+
+	homeLocationID, err := parseOptionalID(in.GetHomeLocationId(), "invalid home location ID")
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := editionRefsFromProto(in.GetPublisherId(), in.GetSeriesId(), in.GetWorkId())
+	if err != nil {
+		return nil, err
+	}
+
+	req := models.UpdateBookRequest{
+		Title:          in.GetTitle(),
+		ISBN:           in.GetIsbn(),
+		Barcode:        in.GetBarcode(),
+		Description:    in.GetDescription(),
+		Contributors:   contributors,
+		SubjectIDs:     subjectIDs,
+		PublishedAt:    timeFromProto(in.GetPublishedAt()),
+		HomeLocationID: homeLocationID,
+		PublisherID:    refs.publisherID,
+		Edition:        in.GetEdition(),
+		Format:         in.GetFormat(),
+		Pages:          optionalInt(in.Pages),
+		Language:       in.GetLanguage(),
+		SeriesID:       refs.seriesID,
+		SeriesVolume:   optionalInt(in.SeriesVolume),
+		WorkID:         refs.workID,
+
+		CallNumber:           in.GetCallNumber(),
+		ClassificationScheme: in.GetClassificationScheme(),
+	}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	book, err := s.bookService.WithContext(ctx).UpdateBook(id, &req)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return bookToProto(book), nil
+}
+
+func (s *CatalogServer) DeleteBook(ctx context.Context, in *libraryv1.DeleteBookRequest) (*emptypb.Empty, error) {
+	id, err := parseID(in.GetId(), "invalid book ID")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.bookService.WithContext(ctx).DeleteBook(id); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
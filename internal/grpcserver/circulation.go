@@ -0,0 +1,160 @@
+package grpcserver
+
+import (
+	"context"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/pb/libraryv1"
+	"library-management-go/internal/services"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type CirculationServer struct {
+	libraryv1.UnimplementedCirculationServiceServer
+	borrowingService *services.BorrowingService
+}
+
+func NewCirculationServer(borrowingService *services.BorrowingService) *CirculationServer {
+	return &CirculationServer{borrowingService: borrowingService}
+}
+
+func (s *CirculationServer) BorrowBook(ctx context.Context, in *libraryv1.BorrowBookRequest) (*libraryv1.Borrowing, error) {
+	bookID, err := parseOptionalID(in.GetBookId(), "invalid book ID")
+	if err != nil {
+		return nil, err
+	}
+
+	borrowerID, err := parseOptionalID(in.GetBorrowerId(), "invalid borrower ID")
+	if err != nil {
+		return nil, err
+	}
+
+	branchID, err := parseOptionalID(in.GetBranchId(), "invalid branch ID")
+	if err != nil {
+		return nil, err
+	}
+
+	req := models.BorrowBookRequest{
+		BookID:     bookID,
+		BorrowerID: borrowerID,
+		DueDate:    timeFromProto(in.GetDueDate()),
+		BranchID:   branchID,
+
+		OverrideBlocks: in.GetOverrideBlocks(),
+	}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	borrowing, err := s.borrowingService.WithContext(ctx).BorrowBook(&req)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return borrowingToProto(borrowing), nil
+}
+
+func (s *CirculationServer) ReturnBook(ctx context.Context, in *libraryv1.ReturnBookRequest) (*libraryv1.Borrowing, error) {
+	borrowingID, err := parseOptionalID(in.GetBorrowingId(), "invalid borrowing ID")
+	if err != nil {
+		return nil, err
+	}
+
+	branchID, err := parseOptionalID(in.GetBranchId(), "invalid branch ID")
+	if err != nil {
+		return nil, err
+	}
+
+	req := models.ReturnBookRequest{BorrowingID: borrowingID, BranchID: branchID}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	borrowing, err := s.borrowingService.WithContext(ctx).ReturnBook(&req)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return borrowingToProto(borrowing), nil
+}
+
+func (s *CirculationServer) GetBorrowing(ctx context.Context, in *libraryv1.GetBorrowingRequest) (*libraryv1.Borrowing, error) {
+	id, err := parseID(in.GetId(), "invalid borrowing ID")
+	if err != nil {
+		return nil, err
+	}
+
+	borrowing, err := s.borrowingService.WithContext(ctx).GetBorrowing(id)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return borrowingToProto(borrowing), nil
+}
+
+func (s *CirculationServer) ListBorrowings(ctx context.Context, in *libraryv1.ListBorrowingsRequest) (*libraryv1.ListBorrowingsResponse, error) {
+	page, limit := pagination(in.GetPage())
+
+	branchID, err := parseOptionalID(in.GetBranchId(), "invalid branch ID")
+	if err != nil {
+		return nil, err
+	}
+
+	filter := models.BorrowingFilter{BranchID: branchID}
+	borrowings, total, err := s.borrowingService.WithContext(ctx).FindBorrowings(filter, page, limit)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return borrowingsResponse(borrowings, page, limit, total), nil
+}
+
+func (s *CirculationServer) ListBorrowingsByBorrower(ctx context.Context, in *libraryv1.ListBorrowingsByBorrowerRequest) (*libraryv1.ListBorrowingsResponse, error) {
+	borrowerID, err := parseID(in.GetBorrowerId(), "invalid borrower ID")
+	if err != nil {
+		return nil, err
+	}
+
+	page, limit := pagination(in.GetPage())
+
+	borrowings, total, err := s.borrowingService.WithContext(ctx).GetBorrowingsByBorrower(borrowerID, page, limit)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return borrowingsResponse(borrowings, page, limit, total), nil
+}
+
+func (s *CirculationServer) ListOverdueBorrowings(ctx context.Context, in *libraryv1.ListOverdueBorrowingsRequest) (*libraryv1.ListBorrowingsResponse, error) {
+	page, limit := pagination(in.GetPage())
+
+	branchID, err := parseOptionalID(in.GetBranchId(), "invalid branch ID")
+	if err != nil {
+		return nil, err
+	}
+
+	filter := models.BorrowingFilter{BranchID: branchID, OverdueOnly: true}
+	borrowings, total, err := s.borrowingService.WithContext(ctx).FindBorrowings(filter, page, limit)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return borrowingsResponse(borrowings, page, limit, total), nil
+}
+
+func (s *CirculationServer) UpdateOverdueStatus(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	if err := s.borrowingService.WithContext(ctx).UpdateOverdueStatus(); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func borrowingsResponse(borrowings []models.Borrowing, page, limit int, total int64) *libraryv1.ListBorrowingsResponse {
+	resp := &libraryv1.ListBorrowingsResponse{Pagination: pageInfo(page, limit, total)}
+	for i := range borrowings {
+		resp.Borrowings = append(resp.Borrowings, borrowingToProto(&borrowings[i]))
+	}
+	return resp
+}
@@ -0,0 +1,220 @@
+package grpcserver
+
+import (
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/pb/libraryv1"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func timeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func authorToProto(a *models.Author) *libraryv1.Author {
+	if a == nil || a.ID == uuid.Nil {
+		return nil
+	}
+	return &libraryv1.Author{
+		Id:        a.ID.String(),
+		Name:      a.Name,
+		Biography: a.Biography,
+		CreatedAt: timestamp(a.CreatedAt),
+		UpdatedAt: timestamp(a.UpdatedAt),
+	}
+}
+
+// optionalInt converts an optional proto field, leaving it nil when unset.
+func optionalInt(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	n := int(*v)
+	return &n
+}
+
+// optionalID formats a nullable ID, leaving it empty when unset.
+func optionalID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func bookToProto(b *models.Book) *libraryv1.Book {
+	return &libraryv1.Book{
+		Id:          b.ID.String(),
+		Title:       b.Title,
+		Isbn:        b.ISBN,
+		Barcode:     b.Barcode,
+		Description: b.Description,
+		PublishedAt: timestamp(b.PublishedAt),
+		Available:   b.Available,
+		CreatedAt:   timestamp(b.CreatedAt),
+		UpdatedAt:   timestamp(b.UpdatedAt),
+
+		HomeLocationId:    optionalID(b.HomeLocationID),
+		CurrentLocationId: optionalID(b.CurrentLocationID),
+		InTransit:         b.InTransit,
+		Contributors:      contributorsToProto(b.Contributors),
+		Subjects:          subjectsToProto(b.Subjects),
+
+		Publisher:    publisherToProto(b.Publisher),
+		Edition:      b.Edition,
+		Format:       b.Format,
+		Pages:        int32(b.Pages),
+		Language:     b.Language,
+		Series:       seriesToProto(b.Series),
+		SeriesVolume: int32(b.SeriesVolume),
+		WorkId:       optionalID(b.WorkID),
+
+		CallNumber:           b.CallNumber,
+		ClassificationScheme: b.ClassificationScheme,
+		Cover:                coverToProto(b.Cover),
+	}
+}
+
+func coverToProto(c *models.BookCover) *libraryv1.BookCover {
+	if c == nil {
+		return nil
+	}
+	return &libraryv1.BookCover{
+		Url:         c.URL,
+		ContentType: c.ContentType,
+		Width:       int32(c.Width),
+		Height:      int32(c.Height),
+		Thumbnails:  c.Thumbnails,
+	}
+}
+
+func publisherToProto(p *models.Publisher) *libraryv1.Publisher {
+	if p == nil {
+		return nil
+	}
+	return &libraryv1.Publisher{
+		Id:      p.ID.String(),
+		Name:    p.Name,
+		Place:   p.Place,
+		Website: p.Website,
+	}
+}
+
+func seriesToProto(s *models.Series) *libraryv1.Series {
+	if s == nil {
+		return nil
+	}
+	return &libraryv1.Series{
+		Id:          s.ID.String(),
+		Name:        s.Name,
+		Description: s.Description,
+	}
+}
+
+func subjectsToProto(subjects []models.Subject) []*libraryv1.Subject {
+	pb := make([]*libraryv1.Subject, len(subjects))
+	for i := range subjects {
+		s := &subjects[i]
+		pb[i] = &libraryv1.Subject{
+			Id:       s.ID.String(),
+			ParentId: optionalID(s.ParentID),
+			Name:     s.Name,
+			Kind:     s.Kind,
+			Source:   s.Source,
+		}
+	}
+	return pb
+}
+
+func contributorsToProto(contributors []models.BookContributor) []*libraryv1.Contributor {
+	pb := make([]*libraryv1.Contributor, len(contributors))
+	for i := range contributors {
+		c := &contributors[i]
+		pb[i] = &libraryv1.Contributor{
+			AuthorId: c.AuthorID.String(),
+			Author:   authorToProto(&c.Author),
+			Role:     c.Role,
+			Position: int32(c.Position),
+		}
+	}
+	return pb
+}
+
+func borrowerToProto(b *models.Borrower) *libraryv1.Borrower {
+	pb := &libraryv1.Borrower{
+		Id:        b.ID.String(),
+		Name:      b.Name,
+		Email:     b.Email,
+		Phone:     b.Phone,
+		Address:   b.Address,
+		CreatedAt: timestamp(b.CreatedAt),
+		UpdatedAt: timestamp(b.UpdatedAt),
+
+		CardNumber:      b.CardNumber,
+		CategoryId:      optionalID(b.CategoryID),
+		Status:          b.Status,
+		MembershipStart: timestamp(b.MembershipStart),
+		KeepHistory:     b.KeepHistory,
+	}
+	if b.MembershipExpiresAt != nil {
+		pb.MembershipExpiresAt = timestamp(*b.MembershipExpiresAt)
+	}
+	for i := range b.Blocks {
+		pb.Blocks = append(pb.Blocks, borrowerBlockToProto(&b.Blocks[i]))
+	}
+	return pb
+}
+
+func borrowerBlockToProto(b *models.BorrowerBlock) *libraryv1.BorrowerBlock {
+	pb := &libraryv1.BorrowerBlock{
+		Id:          b.ID.String(),
+		Reason:      b.Reason,
+		Note:        b.Note,
+		CreatedBy:   b.CreatedBy,
+		Overridable: b.Overridable,
+		CreatedAt:   timestamp(b.CreatedAt),
+	}
+	if b.ExpiresAt != nil {
+		pb.ExpiresAt = timestamp(*b.ExpiresAt)
+	}
+	return pb
+}
+
+func borrowingToProto(b *models.Borrowing) *libraryv1.Borrowing {
+	pb := &libraryv1.Borrowing{
+		Id:         b.ID.String(),
+		BookId:     b.BookID.String(),
+		Book:       bookToProto(&b.Book),
+		BorrowerId: optionalID(b.BorrowerID),
+		BorrowedAt: timestamp(b.BorrowedAt),
+		DueDate:    timestamp(b.DueDate),
+		Status:     b.Status,
+		CreatedAt:  timestamp(b.CreatedAt),
+		UpdatedAt:  timestamp(b.UpdatedAt),
+
+		BranchId:       optionalID(b.BranchID),
+		ReturnBranchId: optionalID(b.ReturnBranchID),
+	}
+	if b.Borrower != nil {
+		pb.Borrower = borrowerToProto(b.Borrower)
+	}
+	if b.ReturnedAt != nil {
+		pb.ReturnedAt = timestamp(*b.ReturnedAt)
+	}
+	if b.AnonymizedAt != nil {
+		pb.AnonymizedAt = timestamp(*b.AnonymizedAt)
+	}
+	return pb
+}
@@ -0,0 +1,82 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+
+	"library-management-go/internal/reqctx"
+	"library-management-go/internal/services"
+	"library-management-go/internal/tenant"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestContext mirrors middleware.RequestContext for gRPC calls, reading
+// the request ID and actor from the x-request-id and x-actor metadata keys.
+func requestContext(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := firstValue(md, "x-request-id")
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs("x-request-id", requestID))
+
+	ctx = reqctx.WithRequestID(ctx, requestID)
+	ctx = reqctx.WithActor(ctx, firstValue(md, "x-actor"))
+
+	return handler(ctx, req)
+}
+
+// blockOverride mirrors middleware.BlockOverride for gRPC calls, reading
+// the credential from the x-override-token metadata key.
+func blockOverride(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if token != "" && subtle.ConstantTimeCompare([]byte(firstValue(md, "x-override-token")), []byte(token)) == 1 {
+			ctx = reqctx.WithBlockOverride(ctx)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// tenantScope mirrors middleware.Tenant for gRPC calls, resolving the
+// tenant from the x-tenant and authorization metadata keys or the
+// :authority pseudo-header.
+func tenantScope(resolver *tenant.Resolver, tenantService *services.TenantService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		slug, err := resolver.Slug(firstValue(md, ":authority"), firstValue(md, "x-tenant"), firstValue(md, "authorization"))
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		if slug == "" {
+			return nil, status.Error(codes.InvalidArgument, "request does not identify a tenant")
+		}
+
+		t, err := tenantService.WithContext(ctx).GetTenantBySlug(slug)
+		switch {
+		case errors.Is(err, services.ErrTenantNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case err != nil:
+			return nil, status.Error(codes.Internal, "failed to resolve tenant")
+		case !t.Active:
+			return nil, status.Error(codes.PermissionDenied, "tenant is suspended")
+		}
+
+		return handler(reqctx.WithTenantID(ctx, t.ID), req)
+	}
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
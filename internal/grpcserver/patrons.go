@@ -0,0 +1,131 @@
+package grpcserver
+
+import (
+	"context"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/pb/libraryv1"
+	"library-management-go/internal/services"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type PatronServer struct {
+	libraryv1.UnimplementedPatronServiceServer
+	borrowerService *services.BorrowerService
+}
+
+func NewPatronServer(borrowerService *services.BorrowerService) *PatronServer {
+	return &PatronServer{borrowerService: borrowerService}
+}
+
+func (s *PatronServer) CreateBorrower(ctx context.Context, in *libraryv1.CreateBorrowerRequest) (*libraryv1.Borrower, error) {
+	categoryID, err := parseOptionalID(in.GetCategoryId(), "invalid category ID")
+	if err != nil {
+		return nil, err
+	}
+
+	req := models.CreateBorrowerRequest{
+		Name:       in.GetName(),
+		Email:      in.GetEmail(),
+		Phone:      in.GetPhone(),
+		Address:    in.GetAddress(),
+		CategoryID: categoryID,
+
+		KeepHistory: in.GetKeepHistory(),
+	}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	borrower, err := s.borrowerService.WithContext(ctx).CreateBorrower(&req)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return borrowerToProto(borrower), nil
+}
+
+func (s *PatronServer) GetBorrower(ctx context.Context, in *libraryv1.GetBorrowerRequest) (*libraryv1.Borrower, error) {
+	id, err := parseID(in.GetId(), "invalid borrower ID")
+	if err != nil {
+		return nil, err
+	}
+
+	borrower, err := s.borrowerService.WithContext(ctx).GetBorrower(id)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return borrowerToProto(borrower), nil
+}
+
+func (s *PatronServer) ListBorrowers(ctx context.Context, in *libraryv1.ListBorrowersRequest) (*libraryv1.ListBorrowersResponse, error) {
+	page, limit := pagination(in.GetPage())
+
+	var borrowers []models.Borrower
+	var total int64
+	var err error
+
+	if in.GetSearch() != "" {
+		borrowers, total, err = s.borrowerService.WithContext(ctx).SearchBorrowers(in.GetSearch(), page, limit)
+	} else {
+		borrowers, total, err = s.borrowerService.WithContext(ctx).GetAllBorrowers(page, limit)
+	}
+
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	resp := &libraryv1.ListBorrowersResponse{Pagination: pageInfo(page, limit, total)}
+	for i := range borrowers {
+		resp.Borrowers = append(resp.Borrowers, borrowerToProto(&borrowers[i]))
+	}
+	return resp, nil
+}
+
+func (s *PatronServer) UpdateBorrower(ctx context.Context, in *libraryv1.UpdateBorrowerRequest) (*libraryv1.Borrower, error) {
+	id, err := parseID(in.GetId(), "invalid borrower ID")
+	if err != nil {
+		return nil, err
+	}
+
+	categoryID, err := parseOptionalID(in.GetCategoryId(), "invalid category ID")
+	if err != nil {
+		return nil, err
+	}
+
+	req := models.UpdateBorrowerRequest{
+		Name:       in.GetName(),
+		Email:      in.GetEmail(),
+		Phone:      in.GetPhone(),
+		Address:    in.GetAddress(),
+		CategoryID: categoryID,
+		Status:     in.GetStatus(),
+
+		KeepHistory: in.KeepHistory,
+	}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	borrower, err := s.borrowerService.WithContext(ctx).UpdateBorrower(id, &req)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return borrowerToProto(borrower), nil
+}
+
+func (s *PatronServer) DeleteBorrower(ctx context.Context, in *libraryv1.DeleteBorrowerRequest) (*emptypb.Empty, error) {
+	id, err := parseID(in.GetId(), "invalid borrower ID")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.borrowerService.WithContext(ctx).DeleteBorrower(id); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
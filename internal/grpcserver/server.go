@@ -0,0 +1,97 @@
+package grpcserver
+
+import (
+	"library-management-go/internal/config"
+	"library-management-go/internal/pb/libraryv1"
+	"library-management-go/internal/repository/gormstore"
+	"library-management-go/internal/services"
+	"library-management-go/internal/tenant"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// New builds a gRPC server exposing the catalog, patron and circulation
+// services on top of the same service layer used by the REST handlers.
+func New(db *gorm.DB, cfg *config.Config) *grpc.Server {
+	store := gormstore.New(db)
+
+	interceptors := []grpc.UnaryServerInterceptor{requestContext, blockOverride(cfg.BlockOverrideToken)}
+	if cfg.MultiTenant {
+		resolver := &tenant.Resolver{Domain: cfg.TenantDomain, Secret: cfg.JWTSecret}
+		interceptors = append(interceptors, tenantScope(resolver, services.NewTenantService(store)))
+	}
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+
+	libraryv1.RegisterCatalogServiceServer(server, NewCatalogServer(
+		services.NewAuthorService(store),
+		services.NewBookService(store),
+	))
+	libraryv1.RegisterPatronServiceServer(server, NewPatronServer(services.NewBorrowerService(store)))
+	libraryv1.RegisterCirculationServiceServer(server, NewCirculationServer(services.NewBorrowingService(store)))
+
+	return server
+}
+
+// statusFromError maps a service error onto a gRPC status, mirroring the
+// HTTP status mapping used by the gin handlers.
+func statusFromError(err error) error {
+	switch services.KindOf(err) {
+	case services.KindInvalid:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case services.KindNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case services.KindConflict:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case services.KindFailedPrecondition:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case services.KindUnauthenticated:
+		return status.Error(codes.Unauthenticated, err.Error())
+	case services.KindPermissionDenied:
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// validate runs the same binding rules gin applies to JSON request bodies.
+func validate(req any) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+func parseID(value, message string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, message)
+	}
+	return id, nil
+}
+
+// parseOptionalID treats an empty string as "not provided", matching the
+// zero-value semantics of the REST update requests.
+func parseOptionalID(value, message string) (uuid.UUID, error) {
+	if value == "" {
+		return uuid.Nil, nil
+	}
+	return parseID(value, message)
+}
+
+func pagination(req *libraryv1.PageRequest) (int, int) {
+	return services.NormalizePagination(int(req.GetPage()), int(req.GetLimit()))
+}
+
+func pageInfo(page, limit int, total int64) *libraryv1.PageInfo {
+	return &libraryv1.PageInfo{
+		Page:       int32(page),
+		Limit:      int32(limit),
+		Total:      total,
+		TotalPages: (total + int64(limit) - 1) / int64(limit),
+	}
+}
@@ -0,0 +1,210 @@
+package grpcserver_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"library-management-go/internal/config"
+	"library-management-go/internal/grpcserver"
+	"library-management-go/internal/models"
+	"library-management-go/internal/pb/libraryv1"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/repository/gormstore"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// client is a connection to a gRPC server over an in-memory listener,
+// backed by a fresh database.
+type client struct {
+	store       repository.Store
+	fx          *testutil.Fixtures
+	catalog     libraryv1.CatalogServiceClient
+	patrons     libraryv1.PatronServiceClient
+	circulation libraryv1.CirculationServiceClient
+}
+
+func newClient(t *testing.T, configure func(*config.Config)) *client {
+	t.Helper()
+
+	cfg := &config.Config{BlockOverrideToken: "override-secret"}
+	configure(cfg)
+	db := testutil.NewDB(t)
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpcserver.New(db, cfg)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	store := gormstore.New(db)
+	return &client{
+		store:       store,
+		fx:          testutil.NewFixtures(t, store),
+		catalog:     libraryv1.NewCatalogServiceClient(conn),
+		patrons:     libraryv1.NewPatronServiceClient(conn),
+		circulation: libraryv1.NewCirculationServiceClient(conn),
+	}
+}
+
+// withMetadata returns a context sending the given key/value pairs.
+func withMetadata(pairs ...string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), pairs...)
+}
+
+func expectCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("got %v (%v), want %v", got, err, want)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	c := newClient(t, func(*config.Config) {})
+	ctx := context.Background()
+
+	var header metadata.MD
+	author, err := c.catalog.CreateAuthor(withMetadata("x-actor", "cataloguer", "x-request-id", "req-42"),
+		&libraryv1.CreateAuthorRequest{Name: "Octavia E. Butler"}, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("create author: %v", err)
+	}
+	if got := header.Get("x-request-id"); len(got) != 1 || got[0] != "req-42" {
+		t.Fatalf("x-request-id header = %v, want req-42", got)
+	}
+	entries, err := c.store.AuditLogs().ListByActor("cataloguer")
+	if err != nil || len(entries) != 1 || entries[0].RequestID != "req-42" {
+		t.Fatalf("expected the creation to be audited for the caller, got %+v, %v", entries, err)
+	}
+
+	book, err := c.catalog.CreateBook(ctx, &libraryv1.CreateBookRequest{
+		Title: "Kindred", Isbn: "9780807083697",
+		Contributors: []*libraryv1.ContributorInput{{AuthorId: author.GetId()}},
+	})
+	if err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	if !book.GetAvailable() || book.GetTitle() != "Kindred" {
+		t.Fatalf("unexpected book %+v", book)
+	}
+	list, err := c.catalog.ListBooks(ctx, &libraryv1.ListBooksRequest{Search: "kindred"})
+	if err != nil || len(list.GetBooks()) != 1 || list.GetPagination().GetTotal() != 1 {
+		t.Fatalf("list books: %+v, %v", list, err)
+	}
+
+	_, err = c.catalog.CreateBook(ctx, &libraryv1.CreateBookRequest{
+		Title: "Kindred again", Isbn: "9780807083697",
+		Contributors: []*libraryv1.ContributorInput{{AuthorId: author.GetId()}},
+	})
+	expectCode(t, err, codes.AlreadyExists)
+	_, err = c.catalog.CreateBook(ctx, &libraryv1.CreateBookRequest{Isbn: "9780807083698"})
+	expectCode(t, err, codes.InvalidArgument)
+	_, err = c.catalog.GetAuthor(ctx, &libraryv1.GetAuthorRequest{Id: "not-a-uuid"})
+	expectCode(t, err, codes.InvalidArgument)
+	_, err = c.catalog.GetBook(ctx, &libraryv1.GetBookRequest{Id: "00000000-0000-0000-0000-000000000001"})
+	expectCode(t, err, codes.NotFound)
+	_, err = c.catalog.DeleteAuthor(ctx, &libraryv1.DeleteAuthorRequest{Id: author.GetId()})
+	expectCode(t, err, codes.FailedPrecondition)
+}
+
+func TestCirculation(t *testing.T) {
+	c := newClient(t, func(*config.Config) {})
+	ctx := context.Background()
+
+	borrower, err := c.patrons.CreateBorrower(ctx, &libraryv1.CreateBorrowerRequest{Name: "Lauren Olamina", Email: "lauren@example.com"})
+	if err != nil {
+		t.Fatalf("create borrower: %v", err)
+	}
+	_, err = c.patrons.CreateBorrower(ctx, &libraryv1.CreateBorrowerRequest{Name: "Copy", Email: "lauren@example.com"})
+	expectCode(t, err, codes.AlreadyExists)
+
+	book := c.fx.Book(nil)
+	loan, err := c.circulation.BorrowBook(ctx, &libraryv1.BorrowBookRequest{
+		BookId: book.ID.String(), BorrowerId: borrower.GetId(), DueDate: timestamppb.New(time.Now().Add(14 * 24 * time.Hour)),
+	})
+	if err != nil {
+		t.Fatalf("borrow: %v", err)
+	}
+	_, err = c.circulation.BorrowBook(ctx, &libraryv1.BorrowBookRequest{
+		BookId: book.ID.String(), BorrowerId: borrower.GetId(), DueDate: timestamppb.New(time.Now().Add(time.Hour)),
+	})
+	expectCode(t, err, codes.FailedPrecondition)
+
+	loans, err := c.circulation.ListBorrowingsByBorrower(ctx, &libraryv1.ListBorrowingsByBorrowerRequest{BorrowerId: borrower.GetId()})
+	if err != nil || len(loans.GetBorrowings()) != 1 {
+		t.Fatalf("list loans: %+v, %v", loans, err)
+	}
+
+	returned, err := c.circulation.ReturnBook(ctx, &libraryv1.ReturnBookRequest{BorrowingId: loan.GetId()})
+	if err != nil || returned.GetStatus() != "returned" || returned.GetReturnedAt() == nil {
+		t.Fatalf("return: %+v, %v", returned, err)
+	}
+}
+
+func TestOverrideBlocks(t *testing.T) {
+	c := newClient(t, func(*config.Config) {})
+	borrower := c.fx.Borrower()
+	c.fx.Block(borrower, func(b *models.BorrowerBlock) { b.Overridable = true })
+	req := &libraryv1.BorrowBookRequest{
+		BookId: c.fx.Book(nil).ID.String(), BorrowerId: borrower.ID.String(),
+		DueDate: timestamppb.New(time.Now().Add(24 * time.Hour)), OverrideBlocks: true,
+	}
+
+	_, err := c.circulation.BorrowBook(context.Background(), req)
+	expectCode(t, err, codes.PermissionDenied)
+	_, err = c.circulation.BorrowBook(withMetadata("x-override-token", "wrong"), req)
+	expectCode(t, err, codes.PermissionDenied)
+	if _, err := c.circulation.BorrowBook(withMetadata("x-override-token", "override-secret"), req); err != nil {
+		t.Fatalf("borrow with override: %v", err)
+	}
+}
+
+func TestTenantScope(t *testing.T) {
+	c := newClient(t, func(cfg *config.Config) { cfg.MultiTenant = true })
+	tenants := services.NewTenantService(c.store)
+	if _, err := tenants.CreateTenant(&models.CreateTenantRequest{Slug: "north", Name: "North"}); err != nil {
+		t.Fatalf("create tenant: %v", err)
+	}
+	south, err := tenants.CreateTenant(&models.CreateTenantRequest{Slug: "south", Name: "South"})
+	if err != nil {
+		t.Fatalf("create tenant: %v", err)
+	}
+	active := false
+	if _, err := tenants.UpdateTenant(south.ID, &models.UpdateTenantRequest{Active: &active}); err != nil {
+		t.Fatalf("suspend tenant: %v", err)
+	}
+
+	req := &libraryv1.CreateAuthorRequest{Name: "Scoped"}
+	_, err = c.catalog.CreateAuthor(context.Background(), req)
+	expectCode(t, err, codes.InvalidArgument)
+	_, err = c.catalog.CreateAuthor(withMetadata("x-tenant", "west"), req)
+	expectCode(t, err, codes.NotFound)
+	_, err = c.catalog.CreateAuthor(withMetadata("x-tenant", "south"), req)
+	expectCode(t, err, codes.PermissionDenied)
+
+	author, err := c.catalog.CreateAuthor(withMetadata("x-tenant", "north"), req)
+	if err != nil {
+		t.Fatalf("create author: %v", err)
+	}
+	list, err := c.catalog.ListAuthors(withMetadata("x-tenant", "north"), &libraryv1.ListAuthorsRequest{})
+	if err != nil || len(list.GetAuthors()) != 1 || list.GetAuthors()[0].GetId() != author.GetId() {
+		t.Fatalf("list authors: %+v, %v", list, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"library-management-go/internal/export"
 	"library-management-go/internal/models"
 	"library-management-go/internal/services"
 
@@ -26,9 +27,9 @@ func (h *AuthorHandler) CreateAuthor(c *gin.Context) {
 		return
 	}
 
-	author, err := h.authorService.CreateAuthor(&req)
+	author, err := h.authorService.WithContext(c.Request.Context()).CreateAuthor(&req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -43,9 +44,9 @@ func (h *AuthorHandler) GetAuthor(c *gin.Context) {
 		return
 	}
 
-	author, err := h.authorService.GetAuthor(id)
+	author, err := h.authorService.WithContext(c.Request.Context()).GetAuthor(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -57,25 +58,20 @@ func (h *AuthorHandler) GetAllAuthors(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	search := c.Query("search")
 
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 10
-	}
+	page, limit = services.NormalizePagination(page, limit)
 
 	var authors []models.Author
 	var total int64
 	var err error
 
 	if search != "" {
-		authors, total, err = h.authorService.SearchAuthors(search, page, limit)
+		authors, total, err = h.authorService.WithContext(c.Request.Context()).SearchAuthors(search, page, limit)
 	} else {
-		authors, total, err = h.authorService.GetAllAuthors(page, limit)
+		authors, total, err = h.authorService.WithContext(c.Request.Context()).GetAllAuthors(page, limit)
 	}
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -104,9 +100,9 @@ func (h *AuthorHandler) UpdateAuthor(c *gin.Context) {
 		return
 	}
 
-	author, err := h.authorService.UpdateAuthor(id, &req)
+	author, err := h.authorService.WithContext(c.Request.Context()).UpdateAuthor(id, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -121,11 +117,85 @@ func (h *AuthorHandler) DeleteAuthor(c *gin.Context) {
 		return
 	}
 
-	err = h.authorService.DeleteAuthor(id)
+	err = h.authorService.WithContext(c.Request.Context()).DeleteAuthor(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "author deleted successfully"})
 }
+
+func (h *AuthorHandler) ExportAuthors(c *gin.Context) {
+	header := []string{"id", "name", "biography", "created_at", "updated_at"}
+
+	streamExport(c, "authors", header, func(w export.Writer) error {
+		return h.authorService.WithContext(c.Request.Context()).ExportAuthors(c.Query("search"), func(authors []models.Author) error {
+			for _, a := range authors {
+				row := []string{a.ID.String(), a.Name, a.Biography, formatTime(a.CreatedAt), formatTime(a.UpdatedAt)}
+				if err := w.Write(row, a); err != nil {
+					return err
+				}
+			}
+			c.Writer.Flush()
+			return nil
+		})
+	})
+}
+
+func (h *AuthorHandler) GetDeletedAuthors(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	page, limit = services.NormalizePagination(page, limit)
+
+	authors, total, err := h.authorService.WithContext(c.Request.Context()).GetDeletedAuthors(page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": authors,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+func (h *AuthorHandler) RestoreAuthor(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid author ID"})
+		return
+	}
+
+	author, err := h.authorService.WithContext(c.Request.Context()).RestoreAuthor(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": author})
+}
+
+func (h *AuthorHandler) PurgeAuthor(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid author ID"})
+		return
+	}
+
+	err = h.authorService.WithContext(c.Request.Context()).PurgeAuthor(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "author permanently deleted"})
+}
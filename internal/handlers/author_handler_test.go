@@ -0,0 +1,141 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestAuthorHandlerCreate(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   interface{}
+		status int
+	}{
+		{"valid", models.CreateAuthorRequest{Name: "Zadie Smith"}, http.StatusCreated},
+		{"missing name", models.CreateAuthorRequest{Biography: "no name"}, http.StatusBadRequest},
+		{"malformed JSON", `{"name":`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer(t)
+
+			var resp envelope[models.Author]
+			rec := s.do(http.MethodPost, "/api/v1/authors", tt.body)
+			if tt.status != http.StatusCreated {
+				expect(t, rec, tt.status, nil)
+				return
+			}
+			expect(t, rec, tt.status, &resp)
+			if resp.Data.ID == uuid.Nil || resp.Data.Name != "Zadie Smith" {
+				t.Fatalf("unexpected author %+v", resp.Data)
+			}
+		})
+	}
+}
+
+func TestAuthorHandlerGet(t *testing.T) {
+	s := newServer(t)
+	author := s.fx.Author()
+
+	tests := []struct {
+		name   string
+		id     string
+		status int
+	}{
+		{"existing", author.ID.String(), http.StatusOK},
+		{"unknown", uuid.NewString(), http.StatusNotFound},
+		{"invalid ID", "not-a-uuid", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect(t, s.do(http.MethodGet, "/api/v1/authors/"+tt.id, nil), tt.status, nil)
+		})
+	}
+}
+
+func TestAuthorHandlerList(t *testing.T) {
+	s := newServer(t)
+	for i := 0; i < 3; i++ {
+		s.fx.Author()
+	}
+	s.fx.Author(func(a *models.Author) { a.Name = "Findable" })
+
+	tests := []struct {
+		name      string
+		query     string
+		wantRows  int
+		wantTotal int64
+		wantPages int64
+	}{
+		{"first page", "?page=1&limit=3", 3, 4, 2},
+		{"second page", "?page=2&limit=3", 1, 4, 2},
+		{"search", "?search=findable", 1, 1, 1},
+		{"limit above maximum is capped", "?limit=1000", 4, 4, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp page[models.Author]
+			expect(t, s.do(http.MethodGet, "/api/v1/authors"+tt.query, nil), http.StatusOK, &resp)
+			if len(resp.Data) != tt.wantRows || resp.Pagination.Total != tt.wantTotal || resp.Pagination.TotalPages != tt.wantPages {
+				t.Fatalf("got %d rows, pagination %+v", len(resp.Data), resp.Pagination)
+			}
+		})
+	}
+}
+
+func TestAuthorHandlerUpdate(t *testing.T) {
+	s := newServer(t)
+	author := s.fx.Author()
+
+	var resp envelope[models.Author]
+	expect(t, s.do(http.MethodPut, "/api/v1/authors/"+author.ID.String(),
+		models.UpdateAuthorRequest{Name: "Renamed"}), http.StatusOK, &resp)
+	if resp.Data.Name != "Renamed" || resp.Data.Biography != author.Biography {
+		t.Fatalf("unexpected author %+v", resp.Data)
+	}
+
+	expect(t, s.do(http.MethodPut, "/api/v1/authors/"+uuid.NewString(),
+		models.UpdateAuthorRequest{Name: "x"}), http.StatusNotFound, nil)
+}
+
+func TestAuthorHandlerDelete(t *testing.T) {
+	s := newServer(t)
+	withBooks := s.fx.Author()
+	s.fx.Book(withBooks)
+	without := s.fx.Author()
+
+	var body errorBody
+	expect(t, s.do(http.MethodDelete, "/api/v1/authors/"+withBooks.ID.String(), nil), http.StatusBadRequest, &body)
+	if body.Error != "cannot delete author with existing books" {
+		t.Fatalf("error = %q", body.Error)
+	}
+
+	expect(t, s.do(http.MethodDelete, "/api/v1/authors/"+without.ID.String(), nil), http.StatusOK, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/authors/"+without.ID.String(), nil), http.StatusNotFound, nil)
+}
+
+func TestAuthorHandlerTrash(t *testing.T) {
+	s := newServer(t)
+	author := s.fx.Author()
+	id := author.ID.String()
+
+	expect(t, s.do(http.MethodPost, "/api/v1/authors/"+id+"/restore", nil), http.StatusNotFound, nil)
+	expect(t, s.do(http.MethodDelete, "/api/v1/authors/"+id, nil), http.StatusOK, nil)
+
+	var trash page[models.Author]
+	expect(t, s.do(http.MethodGet, "/api/v1/authors/trash", nil), http.StatusOK, &trash)
+	if trash.Pagination.Total != 1 || trash.Data[0].ID != author.ID {
+		t.Fatalf("unexpected trash %+v", trash)
+	}
+
+	expect(t, s.do(http.MethodPost, "/api/v1/authors/"+id+"/restore", nil), http.StatusOK, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/authors/"+id, nil), http.StatusOK, nil)
+
+	expect(t, s.do(http.MethodDelete, "/api/v1/authors/"+id, nil), http.StatusOK, nil)
+	expect(t, s.do(http.MethodDelete, "/api/v1/authors/"+id+"/purge", nil), http.StatusOK, nil)
+	expect(t, s.do(http.MethodDelete, "/api/v1/authors/"+id+"/purge", nil), http.StatusNotFound, nil)
+}
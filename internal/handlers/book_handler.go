@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"library-management-go/internal/callnumber"
+	"library-management-go/internal/export"
 	"library-management-go/internal/models"
 	"library-management-go/internal/services"
 
@@ -26,9 +30,9 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 		return
 	}
 
-	book, err := h.bookService.CreateBook(&req)
+	book, err := h.bookService.WithContext(c.Request.Context()).CreateBook(&req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -43,9 +47,9 @@ func (h *BookHandler) GetBook(c *gin.Context) {
 		return
 	}
 
-	book, err := h.bookService.GetBook(id)
+	book, err := h.bookService.WithContext(c.Request.Context()).GetBook(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -55,41 +59,82 @@ func (h *BookHandler) GetBook(c *gin.Context) {
 func (h *BookHandler) GetAllBooks(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	search := c.Query("search")
-
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 10
-	}
 
-	var books []models.Book
-	var total int64
-	var err error
+	page, limit = services.NormalizePagination(page, limit)
 
-	if search != "" {
-		books, total, err = h.bookService.SearchBooks(search, page, limit)
-	} else {
-		books, total, err = h.bookService.GetAllBooks(page, limit)
+	filter, ok := bookFilter(c)
+	if !ok {
+		return
 	}
 
+	books, total, err := h.bookService.WithContext(c.Request.Context()).FindBooks(filter, page, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"data": books,
 		"pagination": gin.H{
-			"page":       page,
-			"limit":      limit,
-			"total":      total,
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
 			"total_pages": (total + int64(limit) - 1) / int64(limit),
 		},
 	})
 }
 
+// bookFilter reads the search, branch, subject and edition query
+// parameters shared by the list and export endpoints.
+func bookFilter(c *gin.Context) (models.BookFilter, bool) {
+	filter := models.BookFilter{
+		Search:         c.Query("search"),
+		Format:         c.Query("format"),
+		Language:       c.Query("language"),
+		Classification: c.Query("classification"),
+		CallNumberFrom: c.Query("call_number_from"),
+		CallNumberTo:   c.Query("call_number_to"),
+	}
+
+	// Check the range here, as exports cannot report errors once streaming
+	if filter.CallNumberFrom != "" || filter.CallNumberTo != "" {
+		if _, _, _, err := callnumber.Range(filter.Classification, filter.CallNumberFrom, filter.CallNumberTo); err != nil {
+			respondError(c, services.ErrInvalidCallNumber)
+			return filter, false
+		}
+	}
+
+	switch c.Query("sort") {
+	case "":
+	case "call_number":
+		filter.ShelfOrder = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be call_number"})
+		return filter, false
+	}
+
+	var ok bool
+	if filter.BranchID, ok = queryUUID(c, "branch_id", "branch"); !ok {
+		return filter, false
+	}
+	if filter.HomeBranchID, ok = queryUUID(c, "home_branch_id", "home branch"); !ok {
+		return filter, false
+	}
+	if filter.SubjectID, ok = queryUUID(c, "subject_id", "subject"); !ok {
+		return filter, false
+	}
+	if filter.PublisherID, ok = queryUUID(c, "publisher_id", "publisher"); !ok {
+		return filter, false
+	}
+	if filter.SeriesID, ok = queryUUID(c, "series_id", "series"); !ok {
+		return filter, false
+	}
+	if filter.WorkID, ok = queryUUID(c, "work_id", "work"); !ok {
+		return filter, false
+	}
+	return filter, true
+}
+
 func (h *BookHandler) UpdateBook(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -104,9 +149,9 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 		return
 	}
 
-	book, err := h.bookService.UpdateBook(id, &req)
+	book, err := h.bookService.WithContext(c.Request.Context()).UpdateBook(id, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -121,11 +166,147 @@ func (h *BookHandler) DeleteBook(c *gin.Context) {
 		return
 	}
 
-	err = h.bookService.DeleteBook(id)
+	err = h.bookService.WithContext(c.Request.Context()).DeleteBook(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "book deleted successfully"})
 }
+
+func (h *BookHandler) BatchBooks(c *gin.Context) {
+	var req models.BookBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.bookService.WithContext(c.Request.Context()).BatchBooks(&req)
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+func (h *BookHandler) ExportBooks(c *gin.Context) {
+	filter, ok := bookFilter(c)
+	if !ok {
+		return
+	}
+	// format picks the export format here, so the book format filter is
+	// named book_format instead
+	filter.Format = c.Query("book_format")
+
+	header := []string{"id", "title", "isbn", "barcode", "description", "contributors", "subjects",
+		"published_at", "publisher", "edition", "format", "pages", "language", "series", "series_volume", "work_id",
+		"call_number", "classification_scheme", "cover_url", "available", "home_location_id", "current_location_id", "in_transit",
+		"created_at", "updated_at"}
+
+	streamExport(c, "books", header, func(w export.Writer) error {
+		return h.bookService.WithContext(c.Request.Context()).ExportBooks(filter, func(books []models.Book) error {
+			for _, b := range books {
+				var publisher, series string
+				if b.Publisher != nil {
+					publisher = b.Publisher.Name
+				}
+				if b.Series != nil {
+					series = b.Series.Name
+				}
+				var coverURL string
+				if b.Cover != nil {
+					coverURL = b.Cover.URL
+				}
+				row := []string{b.ID.String(), b.Title, b.ISBN, b.Barcode, b.Description, formatContributors(b.Contributors),
+					formatSubjects(b.Subjects), formatTime(b.PublishedAt), publisher, b.Edition, b.Format,
+					strconv.Itoa(b.Pages), b.Language, series, strconv.Itoa(b.SeriesVolume), formatUUID(b.WorkID),
+					b.CallNumber, b.ClassificationScheme, coverURL, formatBool(b.Available), formatUUID(b.HomeLocationID),
+					formatUUID(b.CurrentLocationID), formatBool(b.InTransit), formatTime(b.CreatedAt), formatTime(b.UpdatedAt)}
+				if err := w.Write(row, b); err != nil {
+					return err
+				}
+			}
+			c.Writer.Flush()
+			return nil
+		})
+	})
+}
+
+// formatContributors lists a book's credits in order as "Name (role)",
+// separated by semicolons.
+func formatContributors(contributors []models.BookContributor) string {
+	credits := make([]string, len(contributors))
+	for i, c := range contributors {
+		credits[i] = fmt.Sprintf("%s (%s)", c.Author.Name, c.Role)
+	}
+	return strings.Join(credits, "; ")
+}
+
+// formatSubjects lists the names of a book's subjects, separated by
+// semicolons.
+func formatSubjects(subjects []models.Subject) string {
+	names := make([]string, len(subjects))
+	for i, s := range subjects {
+		names[i] = s.Name
+	}
+	return strings.Join(names, "; ")
+}
+
+func (h *BookHandler) GetDeletedBooks(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	page, limit = services.NormalizePagination(page, limit)
+
+	books, total, err := h.bookService.WithContext(c.Request.Context()).GetDeletedBooks(page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": books,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+func (h *BookHandler) RestoreBook(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid book ID"})
+		return
+	}
+
+	book, err := h.bookService.WithContext(c.Request.Context()).RestoreBook(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": book})
+}
+
+func (h *BookHandler) PurgeBook(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid book ID"})
+		return
+	}
+
+	err = h.bookService.WithContext(c.Request.Context()).PurgeBook(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "book permanently deleted"})
+}
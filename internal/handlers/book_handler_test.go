@@ -0,0 +1,235 @@
+package handlers_test
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"testing"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestBookHandlerCreate(t *testing.T) {
+	s := newServer(t)
+	author := s.fx.Author()
+	existing := s.fx.Book(author)
+
+	tests := []struct {
+		name   string
+		body   interface{}
+		status int
+	}{
+		{"valid", models.CreateBookRequest{Title: "White Teeth", ISBN: "9780375703867", Contributors: []models.ContributorRequest{{AuthorID: author.ID}}}, http.StatusCreated},
+		{"missing ISBN", models.CreateBookRequest{Title: "White Teeth", Contributors: []models.ContributorRequest{{AuthorID: author.ID}}}, http.StatusBadRequest},
+		{"no contributors", models.CreateBookRequest{Title: "White Teeth", ISBN: "9780375703867"}, http.StatusBadRequest},
+		{"unknown role", models.CreateBookRequest{Title: "White Teeth", ISBN: "9780375703867",
+			Contributors: []models.ContributorRequest{{AuthorID: author.ID, Role: "narrator"}}}, http.StatusBadRequest},
+		{"unknown author", models.CreateBookRequest{Title: "NW", ISBN: "9781594203978", Contributors: []models.ContributorRequest{{AuthorID: uuid.New()}}}, http.StatusNotFound},
+		{"duplicate ISBN", models.CreateBookRequest{Title: "Copy", ISBN: existing.ISBN, Contributors: []models.ContributorRequest{{AuthorID: author.ID}}}, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect(t, s.do(http.MethodPost, "/api/v1/books", tt.body), tt.status, nil)
+		})
+	}
+}
+
+func TestBookHandlerGetAndList(t *testing.T) {
+	s := newServer(t)
+	author := s.fx.Author(func(a *models.Author) { a.Name = "Kazuo Ishiguro" })
+	shelf := s.fx.Location(nil)
+	book := s.fx.Book(author, testutil.ShelvedAt(shelf), func(b *models.Book) { b.Title = "The Remains of the Day" })
+	s.fx.Book(nil)
+
+	var got envelope[models.Book]
+	expect(t, s.do(http.MethodGet, "/api/v1/books/"+book.ID.String(), nil), http.StatusOK, &got)
+	if len(got.Data.Contributors) != 1 || got.Data.Contributors[0].Author.Name != "Kazuo Ishiguro" ||
+		got.Data.Contributors[0].Role != models.RoleAuthor {
+		t.Fatalf("expected author in response, got %+v", got.Data.Contributors)
+	}
+	if got.Data.HomeLocation == nil || got.Data.HomeLocation.Branch == nil || got.Data.HomeLocation.Branch.ID != shelf.BranchID {
+		t.Fatalf("expected home location and branch in response, got %+v", got.Data.HomeLocation)
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/books/"+uuid.NewString(), nil), http.StatusNotFound, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/books/nope", nil), http.StatusBadRequest, nil)
+
+	tests := []struct {
+		query string
+		want  int64
+	}{
+		{"", 2},
+		{"?search=ishiguro", 1},
+		{"?search=remains", 1},
+		{"?branch_id=" + shelf.BranchID.String(), 1},
+		{"?home_branch_id=" + shelf.BranchID.String(), 1},
+		{"?branch_id=" + uuid.NewString(), 0},
+	}
+	for _, tt := range tests {
+		var list page[models.Book]
+		expect(t, s.do(http.MethodGet, "/api/v1/books"+tt.query, nil), http.StatusOK, &list)
+		if list.Pagination.Total != tt.want {
+			t.Fatalf("%q: total = %d, want %d", tt.query, list.Pagination.Total, tt.want)
+		}
+	}
+
+	expect(t, s.do(http.MethodGet, "/api/v1/books?branch_id=nope", nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/books?home_branch_id=nope", nil), http.StatusBadRequest, nil)
+}
+
+func TestBookHandlerUpdateAndDelete(t *testing.T) {
+	s := newServer(t)
+	book := s.fx.Book(nil)
+	borrowed := s.fx.Book(nil)
+	s.fx.Borrowing(borrowed, nil)
+
+	var updated envelope[models.Book]
+	expect(t, s.do(http.MethodPut, "/api/v1/books/"+book.ID.String(),
+		models.UpdateBookRequest{Title: "Updated Title"}), http.StatusOK, &updated)
+	if updated.Data.Title != "Updated Title" || updated.Data.ISBN != book.ISBN {
+		t.Fatalf("unexpected book %+v", updated.Data)
+	}
+
+	expect(t, s.do(http.MethodPut, "/api/v1/books/"+book.ID.String(),
+		models.UpdateBookRequest{ISBN: borrowed.ISBN}), http.StatusConflict, nil)
+
+	tests := []struct {
+		name   string
+		id     string
+		status int
+	}{
+		{"currently borrowed", borrowed.ID.String(), http.StatusBadRequest},
+		{"available", book.ID.String(), http.StatusOK},
+		{"already deleted", book.ID.String(), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect(t, s.do(http.MethodDelete, "/api/v1/books/"+tt.id, nil), tt.status, nil)
+		})
+	}
+}
+
+func TestBookHandlerBatch(t *testing.T) {
+	s := newServer(t)
+	author := s.fx.Author()
+
+	var failed errorBody
+	expect(t, s.do(http.MethodPost, "/api/v1/books/batch", models.BookBatchRequest{
+		Mode: models.BatchModeAtomic,
+		Operations: []models.BookBatchOperation{
+			{Op: "create", Create: &models.CreateBookRequest{Title: "A", ISBN: "9781000000001", Contributors: []models.ContributorRequest{{AuthorID: author.ID}}}},
+			{Op: "delete", ID: uuid.New()},
+		},
+	}), http.StatusNotFound, &failed)
+	if failed.Index == nil || *failed.Index != 1 {
+		t.Fatalf("expected failing index 1, got %+v", failed)
+	}
+
+	var resp envelope[models.BatchResponse]
+	expect(t, s.do(http.MethodPost, "/api/v1/books/batch", models.BookBatchRequest{
+		Mode: models.BatchModeIndependent,
+		Operations: []models.BookBatchOperation{
+			{Op: "create", Create: &models.CreateBookRequest{Title: "A", ISBN: "9781000000001", Contributors: []models.ContributorRequest{{AuthorID: author.ID}}}},
+			{Op: "delete", ID: uuid.New()},
+		},
+	}), http.StatusOK, &resp)
+	if resp.Data.Succeeded != 1 || resp.Data.Failed != 1 {
+		t.Fatalf("unexpected batch response %+v", resp.Data)
+	}
+
+	expect(t, s.do(http.MethodPost, "/api/v1/books/batch", models.BookBatchRequest{
+		Operations: []models.BookBatchOperation{{Op: "rename"}},
+	}), http.StatusBadRequest, nil)
+}
+
+func TestBookHandlerExport(t *testing.T) {
+	s := newServer(t)
+	author := s.fx.Author(func(a *models.Author) { a.Name = "Hilary Mantel" })
+	s.fx.Book(author, func(b *models.Book) { b.Title = "Wolf Hall" })
+	s.fx.Book(nil, func(b *models.Book) { b.Format = models.FormatPaperback })
+
+	rec := s.do(http.MethodGet, "/api/v1/books/export?format=csv&search=mantel", nil)
+	expect(t, rec, http.StatusOK, nil)
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+	if len(rows) != 2 || rows[0][1] != "title" || rows[1][1] != "Wolf Hall" || rows[1][3] == "" || rows[1][5] != "Hilary Mantel (author)" {
+		t.Fatalf("unexpected rows %v", rows)
+	}
+
+	// format picks the file format, so exports filter on book_format
+	rec = s.do(http.MethodGet, "/api/v1/books/export?format=csv&book_format=paperback", nil)
+	expect(t, rec, http.StatusOK, nil)
+	if rows, err := csv.NewReader(rec.Body).ReadAll(); err != nil || len(rows) != 2 || rows[1][10] != models.FormatPaperback {
+		t.Fatalf("unexpected rows %v, %v", rows, err)
+	}
+
+	expect(t, s.do(http.MethodGet, "/api/v1/books/export?format=pdf", nil), http.StatusNotAcceptable, nil)
+}
+
+func TestBookHandlerShelfList(t *testing.T) {
+	s := newServer(t)
+	author := s.fx.Author()
+	s.fx.Book(nil, testutil.CallNumber("QA76.9 .K58"))
+	s.fx.Book(nil, testutil.CallNumber("823.914 ROW"))
+	s.fx.Book(nil)
+
+	var created envelope[models.Book]
+	expect(t, s.do(http.MethodPost, "/api/v1/books", models.CreateBookRequest{Title: "Go", ISBN: "9780134190440",
+		Contributors: []models.ContributorRequest{{AuthorID: author.ID}}, CallNumber: "QA76.73 .G63"}), http.StatusCreated, &created)
+	if created.Data.ClassificationScheme != "lc" {
+		t.Fatalf("scheme = %q, want lc", created.Data.ClassificationScheme)
+	}
+	expect(t, s.do(http.MethodPost, "/api/v1/books", models.CreateBookRequest{Title: "Odd", ISBN: "9780134190441",
+		Contributors: []models.ContributorRequest{{AuthorID: author.ID}}, CallNumber: "QA76", ClassificationScheme: "dewey"}),
+		http.StatusBadRequest, nil)
+
+	var list page[models.Book]
+	expect(t, s.do(http.MethodGet, "/api/v1/books?sort=call_number&call_number_from=QA76&call_number_to=QA76.9", nil), http.StatusOK, &list)
+	if list.Pagination.Total != 2 || list.Data[0].CallNumber != "QA76.73 .G63" || list.Data[1].CallNumber != "QA76.9 .K58" {
+		t.Fatalf("unexpected shelf list %+v", list.Data)
+	}
+
+	rec := s.do(http.MethodGet, "/api/v1/books/export?format=csv&sort=call_number", nil)
+	expect(t, rec, http.StatusOK, nil)
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+	var callNumbers []string
+	for _, row := range rows[1:] {
+		callNumbers = append(callNumbers, row[16])
+	}
+	if want := []string{"823.914 ROW", "QA76.73 .G63", "QA76.9 .K58", ""}; strings.Join(callNumbers, "|") != strings.Join(want, "|") {
+		t.Fatalf("exported call numbers %q, want %q", callNumbers, want)
+	}
+
+	expect(t, s.do(http.MethodGet, "/api/v1/books?sort=title", nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/books/export?classification=dewey&call_number_from=QA76", nil), http.StatusBadRequest, nil)
+}
+
+func TestBookHandlerTrash(t *testing.T) {
+	s := newServer(t)
+	book := s.fx.Book(nil)
+	id := book.ID.String()
+
+	expect(t, s.do(http.MethodDelete, "/api/v1/books/"+id, nil), http.StatusOK, nil)
+
+	var trash page[models.Book]
+	expect(t, s.do(http.MethodGet, "/api/v1/books/trash", nil), http.StatusOK, &trash)
+	if trash.Pagination.Total != 1 {
+		t.Fatalf("unexpected trash %+v", trash)
+	}
+
+	// The ISBN is free again while the book is in the trash
+	s.fx.Book(nil, func(b *models.Book) { b.ISBN = book.ISBN })
+	expect(t, s.do(http.MethodPost, "/api/v1/books/"+id+"/restore", nil), http.StatusConflict, nil)
+	expect(t, s.do(http.MethodDelete, "/api/v1/books/"+id+"/purge", nil), http.StatusOK, nil)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+
+	"library-management-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (h *BorrowerHandler) CreateBlock(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrower ID"})
+		return
+	}
+
+	var req models.CreateBorrowerBlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	block, err := h.borrowerService.WithContext(c.Request.Context()).CreateBlock(id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": block})
+}
+
+func (h *BorrowerHandler) GetBlocks(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrower ID"})
+		return
+	}
+
+	blocks, err := h.borrowerService.WithContext(c.Request.Context()).GetBlocks(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": blocks})
+}
+
+func (h *BorrowerHandler) LiftBlock(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrower ID"})
+		return
+	}
+
+	blockID, err := uuid.Parse(c.Param("blockId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid block ID"})
+		return
+	}
+
+	block, err := h.borrowerService.WithContext(c.Request.Context()).LiftBlock(id, blockID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": block})
+}
@@ -0,0 +1,63 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"library-management-go/internal/config"
+	"library-management-go/internal/middleware"
+	"library-management-go/internal/models"
+)
+
+func TestBorrowerHandlerBlocks(t *testing.T) {
+	s := newServerWithConfig(t, func(cfg *config.Config) { cfg.BlockOverrideToken = "override-secret" })
+	borrower := s.fx.Borrower()
+	base := "/api/v1/borrowers/" + borrower.ID.String()
+
+	var block envelope[models.BorrowerBlock]
+	expect(t, s.do(http.MethodPost, base+"/blocks",
+		models.CreateBorrowerBlockRequest{Reason: models.BlockUnpaidFees, Note: "owes 12.50", Overridable: true},
+		middleware.ActorHeader, "circulation-desk"), http.StatusCreated, &block)
+	if block.Data.CreatedBy != "circulation-desk" {
+		t.Fatalf("created_by = %q, want circulation-desk", block.Data.CreatedBy)
+	}
+	expect(t, s.do(http.MethodPost, base+"/blocks", map[string]interface{}{"reason": "bad-mood"}), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodPost, base+"/blocks", models.CreateBorrowerBlockRequest{
+		Reason: models.BlockOther, ExpiresAt: ptr(time.Now().Add(-time.Hour)),
+	}), http.StatusBadRequest, nil)
+
+	var found envelope[models.Borrower]
+	expect(t, s.do(http.MethodGet, base, nil), http.StatusOK, &found)
+	if len(found.Data.Blocks) != 1 || found.Data.Blocks[0].Reason != models.BlockUnpaidFees {
+		t.Fatalf("expected the block in the borrower response, got %+v", found.Data.Blocks)
+	}
+
+	// The block stops borrowing unless staff override it
+	borrow := models.BorrowBookRequest{BookID: s.fx.Book(nil).ID, BorrowerID: borrower.ID, DueDate: time.Now().Add(7 * 24 * time.Hour)}
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/borrow", borrow), http.StatusBadRequest, nil)
+	borrow.OverrideBlocks = true
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/borrow", borrow), http.StatusForbidden, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/borrow", borrow, middleware.OverrideTokenHeader, "wrong"), http.StatusForbidden, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/borrow", borrow, middleware.OverrideTokenHeader, "override-secret"), http.StatusCreated, nil)
+
+	var lifted envelope[models.BorrowerBlock]
+	expect(t, s.do(http.MethodPost, base+"/blocks/"+block.Data.ID.String()+"/lift", nil), http.StatusOK, &lifted)
+	if lifted.Data.LiftedAt == nil {
+		t.Fatal("expected lifted_at to be set")
+	}
+	expect(t, s.do(http.MethodPost, base+"/blocks/"+block.Data.ID.String()+"/lift", nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodPost, base+"/blocks/not-a-uuid/lift", nil), http.StatusBadRequest, nil)
+
+	var history envelope[[]models.BorrowerBlock]
+	expect(t, s.do(http.MethodGet, base+"/blocks", nil), http.StatusOK, &history)
+	if len(history.Data) != 1 {
+		t.Fatalf("expected 1 block in history, got %d", len(history.Data))
+	}
+
+	var unblocked envelope[models.Borrower]
+	expect(t, s.do(http.MethodGet, base, nil), http.StatusOK, &unblocked)
+	if len(unblocked.Data.Blocks) != 0 {
+		t.Fatalf("expected no blocks in force, got %+v", unblocked.Data.Blocks)
+	}
+}
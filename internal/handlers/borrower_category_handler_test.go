@@ -0,0 +1,90 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/testutil"
+)
+
+func TestBorrowerCategoryHandlerCRUD(t *testing.T) {
+	s := newServer(t)
+
+	var created envelope[models.BorrowerCategory]
+	expect(t, s.do(http.MethodPost, "/api/v1/borrower-categories",
+		models.CreateBorrowerCategoryRequest{Code: "CHILD", Name: "Child", MembershipDays: 365, MaxActiveLoans: 3}),
+		http.StatusCreated, &created)
+	id := created.Data.ID.String()
+
+	expect(t, s.do(http.MethodPost, "/api/v1/borrower-categories",
+		models.CreateBorrowerCategoryRequest{Code: "CHILD", Name: "Copy"}), http.StatusConflict, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/borrower-categories",
+		map[string]interface{}{"code": "NEG", "name": "Negative", "membership_days": -1}), http.StatusBadRequest, nil)
+
+	var list page[models.BorrowerCategory]
+	expect(t, s.do(http.MethodGet, "/api/v1/borrower-categories", nil), http.StatusOK, &list)
+	if list.Pagination.Total != 1 {
+		t.Fatalf("total = %d, want 1", list.Pagination.Total)
+	}
+
+	var updated envelope[models.BorrowerCategory]
+	expect(t, s.do(http.MethodPut, "/api/v1/borrower-categories/"+id,
+		map[string]interface{}{"max_active_loans": 4}), http.StatusOK, &updated)
+	if updated.Data.MaxActiveLoans != 4 || updated.Data.MembershipDays != 365 {
+		t.Fatalf("unexpected category %+v", updated.Data)
+	}
+
+	// Borrowers join the category when they register
+	var borrower envelope[models.Borrower]
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowers", models.CreateBorrowerRequest{
+		Name: "Pippi", Email: "pippi@example.com", CategoryID: created.Data.ID,
+	}), http.StatusCreated, &borrower)
+	if borrower.Data.MembershipExpiresAt == nil || borrower.Data.CardNumber == "" {
+		t.Fatalf("expected a card number and membership expiry, got %+v", borrower.Data)
+	}
+
+	expect(t, s.do(http.MethodDelete, "/api/v1/borrower-categories/"+id, nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodDelete, "/api/v1/borrower-categories/not-a-uuid", nil), http.StatusBadRequest, nil)
+}
+
+func TestBorrowerHandlerMembership(t *testing.T) {
+	s := newServer(t)
+	lapsed := s.fx.Borrower(testutil.MembershipExpired(time.Hour))
+
+	var found envelope[models.Borrower]
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowers/card/"+lapsed.CardNumber, nil), http.StatusOK, &found)
+	if found.Data.ID != lapsed.ID {
+		t.Fatalf("card lookup returned %v, want %v", found.Data.ID, lapsed.ID)
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowers/card/12345", nil), http.StatusBadRequest, nil)
+
+	// Lapsed members cannot borrow until they renew
+	borrow := models.BorrowBookRequest{BookID: s.fx.Book(nil).ID, BorrowerID: lapsed.ID, DueDate: time.Now().Add(7 * 24 * time.Hour)}
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/borrow", borrow), http.StatusBadRequest, nil)
+
+	expect(t, s.do(http.MethodPut, "/api/v1/borrowers/update-expired", nil), http.StatusOK, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowers/"+lapsed.ID.String(), nil), http.StatusOK, &found)
+	if found.Data.Status != models.BorrowerExpired {
+		t.Fatalf("status = %q, want expired", found.Data.Status)
+	}
+
+	var renewed envelope[models.Borrower]
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowers/"+lapsed.ID.String()+"/renew",
+		models.RenewMembershipRequest{ExpiresAt: ptr(time.Now().AddDate(1, 0, 0))}), http.StatusOK, &renewed)
+	if renewed.Data.Status != models.BorrowerActive {
+		t.Fatalf("status after renewal = %q, want active", renewed.Data.Status)
+	}
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/borrow", borrow), http.StatusCreated, nil)
+
+	// Suspending a member stops them borrowing again
+	expect(t, s.do(http.MethodPut, "/api/v1/borrowers/"+lapsed.ID.String(),
+		models.UpdateBorrowerRequest{Status: models.BorrowerSuspended}), http.StatusOK, nil)
+	borrow.BookID = s.fx.Book(nil).ID
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/borrow", borrow), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodPut, "/api/v1/borrowers/"+lapsed.ID.String(),
+		models.UpdateBorrowerRequest{Status: "banned"}), http.StatusBadRequest, nil)
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"library-management-go/internal/export"
 	"library-management-go/internal/models"
 	"library-management-go/internal/services"
 
@@ -26,9 +27,9 @@ func (h *BorrowerHandler) CreateBorrower(c *gin.Context) {
 		return
 	}
 
-	borrower, err := h.borrowerService.CreateBorrower(&req)
+	borrower, err := h.borrowerService.WithContext(c.Request.Context()).CreateBorrower(&req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -43,9 +44,9 @@ func (h *BorrowerHandler) GetBorrower(c *gin.Context) {
 		return
 	}
 
-	borrower, err := h.borrowerService.GetBorrower(id)
+	borrower, err := h.borrowerService.WithContext(c.Request.Context()).GetBorrower(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -57,25 +58,20 @@ func (h *BorrowerHandler) GetAllBorrowers(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	search := c.Query("search")
 
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 10
-	}
+	page, limit = services.NormalizePagination(page, limit)
 
 	var borrowers []models.Borrower
 	var total int64
 	var err error
 
 	if search != "" {
-		borrowers, total, err = h.borrowerService.SearchBorrowers(search, page, limit)
+		borrowers, total, err = h.borrowerService.WithContext(c.Request.Context()).SearchBorrowers(search, page, limit)
 	} else {
-		borrowers, total, err = h.borrowerService.GetAllBorrowers(page, limit)
+		borrowers, total, err = h.borrowerService.WithContext(c.Request.Context()).GetAllBorrowers(page, limit)
 	}
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -104,9 +100,9 @@ func (h *BorrowerHandler) UpdateBorrower(c *gin.Context) {
 		return
 	}
 
-	borrower, err := h.borrowerService.UpdateBorrower(id, &req)
+	borrower, err := h.borrowerService.WithContext(c.Request.Context()).UpdateBorrower(id, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -121,11 +117,177 @@ func (h *BorrowerHandler) DeleteBorrower(c *gin.Context) {
 		return
 	}
 
-	err = h.borrowerService.DeleteBorrower(id)
+	err = h.borrowerService.WithContext(c.Request.Context()).DeleteBorrower(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "borrower deleted successfully"})
 }
+
+func (h *BorrowerHandler) GetBorrowerByCard(c *gin.Context) {
+	borrower, err := h.borrowerService.WithContext(c.Request.Context()).GetBorrowerByCard(c.Param("number"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": borrower})
+}
+
+func (h *BorrowerHandler) RenewMembership(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrower ID"})
+		return
+	}
+
+	// The body is optional; without one the membership is renewed for a
+	// term of the borrower's category
+	var req models.RenewMembershipRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	borrower, err := h.borrowerService.WithContext(c.Request.Context()).RenewMembership(id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": borrower})
+}
+
+func (h *BorrowerHandler) UpdateExpiredStatus(c *gin.Context) {
+	err := h.borrowerService.WithContext(c.Request.Context()).UpdateExpiredStatus()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "membership status updated successfully"})
+}
+
+func (h *BorrowerHandler) BatchBorrowers(c *gin.Context) {
+	var req models.BorrowerBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.borrowerService.WithContext(c.Request.Context()).BatchBorrowers(&req)
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+func (h *BorrowerHandler) ExportBorrowers(c *gin.Context) {
+	header := []string{"id", "name", "email", "phone", "address", "card_number", "category_id", "status",
+		"membership_start", "membership_expires_at", "created_at", "updated_at"}
+
+	streamExport(c, "borrowers", header, func(w export.Writer) error {
+		return h.borrowerService.WithContext(c.Request.Context()).ExportBorrowers(c.Query("search"), func(borrowers []models.Borrower) error {
+			for _, b := range borrowers {
+				expiresAt := ""
+				if b.MembershipExpiresAt != nil {
+					expiresAt = formatTime(*b.MembershipExpiresAt)
+				}
+				row := []string{b.ID.String(), b.Name, b.Email, b.Phone, b.Address, b.CardNumber, formatUUID(b.CategoryID),
+					b.Status, formatTime(b.MembershipStart), expiresAt, formatTime(b.CreatedAt), formatTime(b.UpdatedAt)}
+				if err := w.Write(row, b); err != nil {
+					return err
+				}
+			}
+			c.Writer.Flush()
+			return nil
+		})
+	})
+}
+
+func (h *BorrowerHandler) GetDeletedBorrowers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	page, limit = services.NormalizePagination(page, limit)
+
+	borrowers, total, err := h.borrowerService.WithContext(c.Request.Context()).GetDeletedBorrowers(page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": borrowers,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+func (h *BorrowerHandler) RestoreBorrower(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrower ID"})
+		return
+	}
+
+	borrower, err := h.borrowerService.WithContext(c.Request.Context()).RestoreBorrower(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": borrower})
+}
+
+func (h *BorrowerHandler) PurgeBorrower(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrower ID"})
+		return
+	}
+
+	err = h.borrowerService.WithContext(c.Request.Context()).PurgeBorrower(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "borrower permanently deleted"})
+}
+
+func (h *BorrowerHandler) SetPIN(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrower ID"})
+		return
+	}
+
+	var req models.SetPINRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.borrowerService.WithContext(c.Request.Context()).SetPIN(id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "PIN updated successfully"})
+}
@@ -0,0 +1,118 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestBorrowerHandlerCreate(t *testing.T) {
+	s := newServer(t)
+	existing := s.fx.Borrower()
+
+	tests := []struct {
+		name   string
+		body   interface{}
+		status int
+	}{
+		{"valid", models.CreateBorrowerRequest{Name: "Anne Shirley", Email: "anne@example.com"}, http.StatusCreated},
+		{"invalid email", models.CreateBorrowerRequest{Name: "Anne Shirley", Email: "not-an-email"}, http.StatusBadRequest},
+		{"missing name", models.CreateBorrowerRequest{Email: "anne2@example.com"}, http.StatusBadRequest},
+		{"duplicate email", models.CreateBorrowerRequest{Name: "Copy", Email: existing.Email}, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect(t, s.do(http.MethodPost, "/api/v1/borrowers", tt.body), tt.status, nil)
+		})
+	}
+}
+
+func TestBorrowerHandlerCRUD(t *testing.T) {
+	s := newServer(t)
+	borrower := s.fx.Borrower(func(b *models.Borrower) { b.Name = "Jo March" })
+	active := s.fx.Borrower()
+	s.fx.Borrowing(nil, active)
+	id := borrower.ID.String()
+
+	var got envelope[models.Borrower]
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowers/"+id, nil), http.StatusOK, &got)
+	if got.Data.Name != "Jo March" {
+		t.Fatalf("unexpected borrower %+v", got.Data)
+	}
+
+	var list page[models.Borrower]
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowers?search=march", nil), http.StatusOK, &list)
+	if list.Pagination.Total != 1 {
+		t.Fatalf("search total = %d", list.Pagination.Total)
+	}
+
+	var updated envelope[models.Borrower]
+	expect(t, s.do(http.MethodPut, "/api/v1/borrowers/"+id,
+		models.UpdateBorrowerRequest{Phone: "555-1868"}), http.StatusOK, &updated)
+	if updated.Data.Phone != "555-1868" || updated.Data.Name != "Jo March" {
+		t.Fatalf("unexpected borrower %+v", updated.Data)
+	}
+
+	tests := []struct {
+		name   string
+		id     string
+		status int
+	}{
+		{"active borrowings", active.ID.String(), http.StatusBadRequest},
+		{"no borrowings", id, http.StatusOK},
+		{"unknown", uuid.NewString(), http.StatusNotFound},
+		{"invalid ID", "42", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect(t, s.do(http.MethodDelete, "/api/v1/borrowers/"+tt.id, nil), tt.status, nil)
+		})
+	}
+}
+
+func TestBorrowerHandlerTrash(t *testing.T) {
+	s := newServer(t)
+	borrower := s.fx.Borrower()
+	id := borrower.ID.String()
+
+	expect(t, s.do(http.MethodDelete, "/api/v1/borrowers/"+id, nil), http.StatusOK, nil)
+
+	var trash page[models.Borrower]
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowers/trash", nil), http.StatusOK, &trash)
+	if trash.Pagination.Total != 1 {
+		t.Fatalf("unexpected trash %+v", trash)
+	}
+
+	var restored envelope[models.Borrower]
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowers/"+id+"/restore", nil), http.StatusOK, &restored)
+	if restored.Data.ID != borrower.ID {
+		t.Fatalf("restored %v", restored.Data.ID)
+	}
+	expect(t, s.do(http.MethodDelete, "/api/v1/borrowers/"+id+"/purge", nil), http.StatusNotFound, nil)
+}
+
+func TestBorrowerHandlerBatch(t *testing.T) {
+	s := newServer(t)
+	first := s.fx.Borrower()
+	second := s.fx.Borrower()
+
+	var resp envelope[models.BatchResponse]
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowers/batch", models.BorrowerBatchRequest{
+		Operations: []models.BorrowerBatchOperation{
+			{Op: "delete", ID: first.ID},
+			{Op: "delete", ID: second.ID},
+		},
+	}), http.StatusOK, &resp)
+	if resp.Data.Mode != models.BatchModeAtomic || resp.Data.Succeeded != 2 {
+		t.Fatalf("unexpected batch response %+v", resp.Data)
+	}
+
+	var list page[models.Borrower]
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowers", nil), http.StatusOK, &list)
+	if list.Pagination.Total != 0 {
+		t.Fatalf("expected every borrower deleted, %d left", list.Pagination.Total)
+	}
+}
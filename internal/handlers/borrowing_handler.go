@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"library-management-go/internal/export"
 	"library-management-go/internal/models"
 	"library-management-go/internal/services"
 
@@ -26,9 +27,9 @@ func (h *BorrowingHandler) BorrowBook(c *gin.Context) {
 		return
 	}
 
-	borrowing, err := h.borrowingService.BorrowBook(&req)
+	borrowing, err := h.borrowingService.WithContext(c.Request.Context()).BorrowBook(&req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -42,9 +43,9 @@ func (h *BorrowingHandler) ReturnBook(c *gin.Context) {
 		return
 	}
 
-	borrowing, err := h.borrowingService.ReturnBook(&req)
+	borrowing, err := h.borrowingService.WithContext(c.Request.Context()).ReturnBook(&req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -59,9 +60,9 @@ func (h *BorrowingHandler) GetBorrowing(c *gin.Context) {
 		return
 	}
 
-	borrowing, err := h.borrowingService.GetBorrowing(id)
+	borrowing, err := h.borrowingService.WithContext(c.Request.Context()).GetBorrowing(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -72,16 +73,17 @@ func (h *BorrowingHandler) GetAllBorrowings(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 10
+	page, limit = services.NormalizePagination(page, limit)
+
+	branchID, ok := queryUUID(c, "branch_id", "branch")
+	if !ok {
+		return
 	}
 
-	borrowings, total, err := h.borrowingService.GetAllBorrowings(page, limit)
+	filter := models.BorrowingFilter{BranchID: branchID}
+	borrowings, total, err := h.borrowingService.WithContext(c.Request.Context()).FindBorrowings(filter, page, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -107,16 +109,11 @@ func (h *BorrowingHandler) GetBorrowingsByBorrower(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 10
-	}
+	page, limit = services.NormalizePagination(page, limit)
 
-	borrowings, total, err := h.borrowingService.GetBorrowingsByBorrower(borrowerID, page, limit)
+	borrowings, total, err := h.borrowingService.WithContext(c.Request.Context()).GetBorrowingsByBorrower(borrowerID, page, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -135,16 +132,17 @@ func (h *BorrowingHandler) GetOverdueBorrowings(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 10
+	page, limit = services.NormalizePagination(page, limit)
+
+	branchID, ok := queryUUID(c, "branch_id", "branch")
+	if !ok {
+		return
 	}
 
-	borrowings, total, err := h.borrowingService.GetOverdueBorrowings(page, limit)
+	filter := models.BorrowingFilter{BranchID: branchID, OverdueOnly: true}
+	borrowings, total, err := h.borrowingService.WithContext(c.Request.Context()).FindBorrowings(filter, page, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -160,11 +158,77 @@ func (h *BorrowingHandler) GetOverdueBorrowings(c *gin.Context) {
 }
 
 func (h *BorrowingHandler) UpdateOverdueStatus(c *gin.Context) {
-	err := h.borrowingService.UpdateOverdueStatus()
+	err := h.borrowingService.WithContext(c.Request.Context()).UpdateOverdueStatus()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "overdue status updated successfully"})
 }
+
+func (h *BorrowingHandler) ExportBorrowings(c *gin.Context) {
+	filter := models.BorrowingFilter{OverdueOnly: c.Query("overdue") == "true"}
+
+	var ok bool
+	if filter.BorrowerID, ok = queryUUID(c, "borrower_id", "borrower"); !ok {
+		return
+	}
+	if filter.BranchID, ok = queryUUID(c, "branch_id", "branch"); !ok {
+		return
+	}
+
+	header := []string{"id", "book_id", "book_title", "borrower_id", "borrower_name",
+		"borrowed_at", "due_date", "returned_at", "status", "branch_id", "return_branch_id",
+		"created_at", "updated_at"}
+
+	streamExport(c, "borrowings", header, func(w export.Writer) error {
+		return h.borrowingService.WithContext(c.Request.Context()).ExportBorrowings(filter, func(borrowings []models.Borrowing) error {
+			for _, b := range borrowings {
+				returnedAt := ""
+				if b.ReturnedAt != nil {
+					returnedAt = formatTime(*b.ReturnedAt)
+				}
+				// Anonymized loans have no borrower
+				borrowerName := ""
+				if b.Borrower != nil {
+					borrowerName = b.Borrower.Name
+				}
+				row := []string{b.ID.String(), b.BookID.String(), b.Book.Title, formatUUID(b.BorrowerID), borrowerName,
+					formatTime(b.BorrowedAt), formatTime(b.DueDate), returnedAt, b.Status, formatUUID(b.BranchID),
+					formatUUID(b.ReturnBranchID), formatTime(b.CreatedAt), formatTime(b.UpdatedAt)}
+				if err := w.Write(row, b); err != nil {
+					return err
+				}
+			}
+			c.Writer.Flush()
+			return nil
+		})
+	})
+}
+
+func (h *BorrowingHandler) RenewBorrowing(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrowing ID"})
+		return
+	}
+
+	// The body is optional; it is only needed to override blocks
+	var req models.RenewBorrowingRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	borrowing, err := h.borrowingService.WithContext(c.Request.Context()).RenewBorrowing(id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": borrowing})
+}
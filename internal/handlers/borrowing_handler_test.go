@@ -0,0 +1,168 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestBorrowingHandlerBorrow(t *testing.T) {
+	due := time.Now().Add(14 * 24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		prepare func(s *server) interface{}
+		status  int
+		wantErr string
+	}{
+		{"valid", func(s *server) interface{} {
+			return models.BorrowBookRequest{BookID: s.fx.Book(nil).ID, BorrowerID: s.fx.Borrower().ID, DueDate: due}
+		}, http.StatusCreated, ""},
+		{"missing due date", func(s *server) interface{} {
+			return models.BorrowBookRequest{BookID: s.fx.Book(nil).ID, BorrowerID: s.fx.Borrower().ID}
+		}, http.StatusBadRequest, ""},
+		{"unknown book", func(s *server) interface{} {
+			return models.BorrowBookRequest{BookID: uuid.New(), BorrowerID: s.fx.Borrower().ID, DueDate: due}
+		}, http.StatusNotFound, "book not found"},
+		{"book not available", func(s *server) interface{} {
+			loan := s.fx.Borrowing(nil, nil)
+			return models.BorrowBookRequest{BookID: loan.BookID, BorrowerID: s.fx.Borrower().ID, DueDate: due}
+		}, http.StatusBadRequest, "book is not available for borrowing"},
+		{"overdue books", func(s *server) interface{} {
+			borrower := s.fx.Borrower()
+			s.fx.Borrowing(nil, borrower, testutil.Overdue(time.Hour))
+			return models.BorrowBookRequest{BookID: s.fx.Book(nil).ID, BorrowerID: borrower.ID, DueDate: due}
+		}, http.StatusBadRequest, "borrower has overdue books and cannot borrow new books"},
+		{"limit reached", func(s *server) interface{} {
+			borrower := s.fx.Borrower()
+			for i := 0; i < 5; i++ {
+				s.fx.Borrowing(nil, borrower)
+			}
+			return models.BorrowBookRequest{BookID: s.fx.Book(nil).ID, BorrowerID: borrower.ID, DueDate: due}
+		}, http.StatusBadRequest, "borrower has reached maximum borrowing limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer(t)
+			body := tt.prepare(s)
+
+			var resp errorBody
+			expect(t, s.do(http.MethodPost, "/api/v1/borrowings/borrow", body), tt.status, &resp)
+			if tt.wantErr != "" && resp.Error != tt.wantErr {
+				t.Fatalf("error = %q, want %q", resp.Error, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBorrowingHandlerReturn(t *testing.T) {
+	s := newServer(t)
+	loan := s.fx.Borrowing(nil, nil)
+
+	var resp envelope[models.Borrowing]
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/return",
+		models.ReturnBookRequest{BorrowingID: loan.ID}), http.StatusOK, &resp)
+	if resp.Data.Status != "returned" || resp.Data.ReturnedAt == nil {
+		t.Fatalf("unexpected borrowing %+v", resp.Data)
+	}
+
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/return",
+		models.ReturnBookRequest{BorrowingID: loan.ID}), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/return",
+		models.ReturnBookRequest{BorrowingID: uuid.New()}), http.StatusNotFound, nil)
+
+	var book envelope[models.Book]
+	expect(t, s.do(http.MethodGet, "/api/v1/books/"+loan.BookID.String(), nil), http.StatusOK, &book)
+	if !book.Data.Available {
+		t.Fatal("expected returned book to be available")
+	}
+}
+
+func TestBorrowingHandlerQueries(t *testing.T) {
+	s := newServer(t)
+	reader := s.fx.Borrower()
+	branch := s.fx.Branch()
+	current := s.fx.Borrowing(nil, reader)
+	s.fx.Borrowing(nil, reader, testutil.Overdue(time.Hour), func(b *models.Borrowing) { b.BranchID = &branch.ID })
+	s.fx.Borrowing(nil, nil)
+
+	var got envelope[models.Borrowing]
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowings/"+current.ID.String(), nil), http.StatusOK, &got)
+	if got.Data.Borrower.ID != reader.ID || got.Data.Book.ID != current.BookID {
+		t.Fatalf("unexpected borrowing %+v", got.Data)
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowings/"+uuid.NewString(), nil), http.StatusNotFound, nil)
+
+	tests := []struct {
+		name string
+		path string
+		want int64
+	}{
+		{"all", "/api/v1/borrowings", 3},
+		{"by borrower", "/api/v1/borrowings/borrower/" + reader.ID.String(), 2},
+		{"overdue", "/api/v1/borrowings/overdue", 1},
+		{"by branch", "/api/v1/borrowings?branch_id=" + branch.ID.String(), 1},
+		{"overdue by branch", "/api/v1/borrowings/overdue?branch_id=" + branch.ID.String(), 1},
+		{"overdue elsewhere", "/api/v1/borrowings/overdue?branch_id=" + uuid.NewString(), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var list page[models.Borrowing]
+			expect(t, s.do(http.MethodGet, tt.path, nil), http.StatusOK, &list)
+			if list.Pagination.Total != tt.want {
+				t.Fatalf("total = %d, want %d", list.Pagination.Total, tt.want)
+			}
+		})
+	}
+
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowings/borrower/abc", nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowings?branch_id=abc", nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowings/export?branch_id=abc", nil), http.StatusBadRequest, nil)
+}
+
+func TestBorrowingHandlerUpdateOverdue(t *testing.T) {
+	s := newServer(t)
+	late := s.fx.Borrowing(nil, nil, testutil.Overdue(time.Hour))
+
+	expect(t, s.do(http.MethodPut, "/api/v1/borrowings/update-overdue", nil), http.StatusOK, nil)
+
+	var got envelope[models.Borrowing]
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowings/"+late.ID.String(), nil), http.StatusOK, &got)
+	if got.Data.Status != "overdue" {
+		t.Fatalf("status = %q, want overdue", got.Data.Status)
+	}
+}
+
+func TestBorrowingHandlerIdempotentBorrow(t *testing.T) {
+	s := newServer(t)
+	req := models.BorrowBookRequest{
+		BookID:     s.fx.Book(nil).ID,
+		BorrowerID: s.fx.Borrower().ID,
+		DueDate:    time.Now().Add(time.Hour),
+	}
+
+	var first, replay envelope[models.Borrowing]
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/borrow", req, "Idempotency-Key", "kiosk-1"), http.StatusCreated, &first)
+
+	// A retry replays the original response instead of failing as unavailable
+	rec := s.do(http.MethodPost, "/api/v1/borrowings/borrow", req, "Idempotency-Key", "kiosk-1")
+	expect(t, rec, http.StatusCreated, &replay)
+	if rec.Header().Get("Idempotent-Replayed") != "true" || replay.Data.ID != first.Data.ID {
+		t.Fatalf("expected replay of %v, got %v", first.Data.ID, replay.Data.ID)
+	}
+
+	req.DueDate = req.DueDate.Add(time.Hour)
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/borrow", req, "Idempotency-Key", "kiosk-1"),
+		http.StatusUnprocessableEntity, nil)
+
+	var list page[models.Borrowing]
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowings", nil), http.StatusOK, &list)
+	if list.Pagination.Total != 1 {
+		t.Fatalf("got %d borrowings, want 1", list.Pagination.Total)
+	}
+}
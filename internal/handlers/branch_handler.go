@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BranchHandler struct {
+	branchService *services.BranchService
+}
+
+func NewBranchHandler(branchService *services.BranchService) *BranchHandler {
+	return &BranchHandler{branchService: branchService}
+}
+
+func (h *BranchHandler) CreateBranch(c *gin.Context) {
+	var req models.CreateBranchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	branch, err := h.branchService.WithContext(c.Request.Context()).CreateBranch(&req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": branch})
+}
+
+func (h *BranchHandler) GetBranch(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid branch ID"})
+		return
+	}
+
+	branch, err := h.branchService.WithContext(c.Request.Context()).GetBranch(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": branch})
+}
+
+func (h *BranchHandler) GetAllBranches(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	page, limit = services.NormalizePagination(page, limit)
+
+	branches, total, err := h.branchService.WithContext(c.Request.Context()).GetAllBranches(page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": branches,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+func (h *BranchHandler) UpdateBranch(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid branch ID"})
+		return
+	}
+
+	var req models.UpdateBranchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	branch, err := h.branchService.WithContext(c.Request.Context()).UpdateBranch(id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": branch})
+}
+
+func (h *BranchHandler) DeleteBranch(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid branch ID"})
+		return
+	}
+
+	err = h.branchService.WithContext(c.Request.Context()).DeleteBranch(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "branch deleted successfully"})
+}
+
+func (h *BranchHandler) CreateLocation(c *gin.Context) {
+	idStr := c.Param("id")
+	branchID, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid branch ID"})
+		return
+	}
+
+	var req models.CreateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	location, err := h.branchService.WithContext(c.Request.Context()).CreateLocation(branchID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": location})
+}
+
+func (h *BranchHandler) GetLocations(c *gin.Context) {
+	idStr := c.Param("id")
+	branchID, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid branch ID"})
+		return
+	}
+
+	locations, err := h.branchService.WithContext(c.Request.Context()).GetLocations(branchID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": locations})
+}
+
+func (h *BranchHandler) GetLocation(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid location ID"})
+		return
+	}
+
+	location, err := h.branchService.WithContext(c.Request.Context()).GetLocation(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": location})
+}
+
+func (h *BranchHandler) UpdateLocation(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid location ID"})
+		return
+	}
+
+	var req models.UpdateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	location, err := h.branchService.WithContext(c.Request.Context()).UpdateLocation(id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": location})
+}
+
+func (h *BranchHandler) DeleteLocation(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid location ID"})
+		return
+	}
+
+	err = h.branchService.WithContext(c.Request.Context()).DeleteLocation(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "location deleted successfully"})
+}
@@ -0,0 +1,91 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestBranchHandlerCRUD(t *testing.T) {
+	s := newServer(t)
+	existing := s.fx.Branch()
+
+	tests := []struct {
+		name   string
+		body   interface{}
+		status int
+	}{
+		{"valid", models.CreateBranchRequest{Code: "CEN", Name: "Central"}, http.StatusCreated},
+		{"missing name", models.CreateBranchRequest{Code: "NONAME"}, http.StatusBadRequest},
+		{"duplicate code", models.CreateBranchRequest{Code: existing.Code, Name: "Copy"}, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect(t, s.do(http.MethodPost, "/api/v1/branches", tt.body), tt.status, nil)
+		})
+	}
+
+	var list page[models.Branch]
+	expect(t, s.do(http.MethodGet, "/api/v1/branches", nil), http.StatusOK, &list)
+	if list.Pagination.Total != 2 {
+		t.Fatalf("total = %d, want 2", list.Pagination.Total)
+	}
+
+	id := existing.ID.String()
+	var got envelope[models.Branch]
+	expect(t, s.do(http.MethodPut, "/api/v1/branches/"+id, models.UpdateBranchRequest{Name: "Renamed"}), http.StatusOK, &got)
+	if got.Data.Name != "Renamed" || got.Data.Code != existing.Code {
+		t.Fatalf("unexpected branch %+v", got.Data)
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/branches/"+uuid.NewString(), nil), http.StatusNotFound, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/branches/nope", nil), http.StatusBadRequest, nil)
+
+	expect(t, s.do(http.MethodDelete, "/api/v1/branches/"+id, nil), http.StatusOK, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/branches/"+id, nil), http.StatusNotFound, nil)
+}
+
+func TestBranchHandlerLocations(t *testing.T) {
+	s := newServer(t)
+	branch := s.fx.Branch()
+	path := "/api/v1/branches/" + branch.ID.String()
+
+	var created envelope[models.Location]
+	expect(t, s.do(http.MethodPost, path+"/locations", models.CreateLocationRequest{Code: "FIC", Name: "Fiction"}),
+		http.StatusCreated, &created)
+	if created.Data.Branch == nil || created.Data.Branch.ID != branch.ID {
+		t.Fatalf("expected branch in response, got %+v", created.Data)
+	}
+	expect(t, s.do(http.MethodPost, path+"/locations", models.CreateLocationRequest{Code: "FIC", Name: "Again"}),
+		http.StatusConflict, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/branches/"+uuid.NewString()+"/locations",
+		models.CreateLocationRequest{Code: "FIC", Name: "Fiction"}), http.StatusNotFound, nil)
+
+	var locations envelope[[]models.Location]
+	expect(t, s.do(http.MethodGet, path+"/locations", nil), http.StatusOK, &locations)
+	if len(locations.Data) != 1 {
+		t.Fatalf("got %d locations, want 1", len(locations.Data))
+	}
+
+	// A branch with locations cannot be deleted
+	var body errorBody
+	expect(t, s.do(http.MethodDelete, path, nil), http.StatusBadRequest, &body)
+	if body.Error != "cannot delete branch with existing locations" {
+		t.Fatalf("error = %q", body.Error)
+	}
+
+	id := created.Data.ID.String()
+	expect(t, s.do(http.MethodPut, "/api/v1/locations/"+id, models.UpdateLocationRequest{Name: "Fiction A-Z"}), http.StatusOK, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/locations/"+id, nil), http.StatusOK, nil)
+
+	// Nor can a location holding books
+	s.fx.Book(nil, testutil.ShelvedAt(&created.Data))
+	expect(t, s.do(http.MethodDelete, "/api/v1/locations/"+id, nil), http.StatusBadRequest, nil)
+
+	empty := s.fx.Location(branch)
+	expect(t, s.do(http.MethodDelete, "/api/v1/locations/"+empty.ID.String(), nil), http.StatusOK, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/locations/"+empty.ID.String(), nil), http.StatusNotFound, nil)
+}
@@ -0,0 +1,124 @@
+package handlers_test
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"library-management-go/internal/config"
+	"library-management-go/internal/models"
+	"library-management-go/internal/storage/storagetest"
+
+	"github.com/google/uuid"
+)
+
+// coverForm encodes data as the "cover" file of a multipart form and
+// returns the body and its content type.
+func coverForm(t *testing.T, filename string, data []byte) (string, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("cover", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(data)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String(), w.FormDataContentType()
+}
+
+// jpegCover encodes a w×h grey JPEG.
+func jpegCover(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = 0x80
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestCoverHandler(t *testing.T) {
+	s := newServer(t)
+	book := s.fx.Book(nil)
+	path := "/api/v1/books/" + book.ID.String() + "/cover"
+
+	// The type is sniffed, whatever the file is called
+	body, contentType := coverForm(t, "cover.png", jpegCover(t, 300, 450))
+	var got envelope[models.Book]
+	expect(t, s.do(http.MethodPost, path, body, "Content-Type", contentType), http.StatusOK, &got)
+	cover := got.Data.Cover
+	if cover == nil || cover.ContentType != "image/jpeg" || !strings.HasPrefix(cover.URL, "/media/covers/"+book.ID.String()+"/") {
+		t.Fatalf("unexpected cover %+v", cover)
+	}
+
+	// Locally stored covers are served by the application
+	rec := s.do(http.MethodGet, cover.Thumbnails["small"], nil)
+	expect(t, rec, http.StatusOK, nil)
+	config, err := jpeg.DecodeConfig(rec.Body)
+	if err != nil || config.Width != 64 || config.Height != 96 {
+		t.Fatalf("small thumbnail is %dx%d (%v), want 64x96", config.Width, config.Height, err)
+	}
+
+	expect(t, s.do(http.MethodGet, "/api/v1/books/"+book.ID.String(), nil), http.StatusOK, &got)
+	if got.Data.Cover == nil || got.Data.Cover.URL != cover.URL {
+		t.Fatalf("expected the cover on the book, got %+v", got.Data.Cover)
+	}
+
+	body, contentType = coverForm(t, "cover.jpg", []byte("<html>not an image</html>"))
+	expect(t, s.do(http.MethodPost, path, body, "Content-Type", contentType), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodPost, path, "{}", "Content-Type", "application/json"), http.StatusBadRequest, nil)
+	body, contentType = coverForm(t, "cover.jpg", jpegCover(t, 10, 10))
+	expect(t, s.do(http.MethodPost, "/api/v1/books/"+uuid.NewString()+"/cover", body, "Content-Type", contentType), http.StatusNotFound, nil)
+
+	expect(t, s.do(http.MethodDelete, path, nil), http.StatusOK, nil)
+	expect(t, s.do(http.MethodGet, cover.Thumbnails["small"], nil), http.StatusNotFound, nil)
+	expect(t, s.do(http.MethodDelete, path, nil), http.StatusNotFound, nil)
+}
+
+func TestCoverHandlerTooLarge(t *testing.T) {
+	s := newServer(t)
+	book := s.fx.Book(nil)
+
+	body, contentType := coverForm(t, "cover.jpg", bytes.Repeat([]byte{0xff}, 11<<20))
+	expect(t, s.do(http.MethodPost, "/api/v1/books/"+book.ID.String()+"/cover", body, "Content-Type", contentType),
+		http.StatusRequestEntityTooLarge, nil)
+}
+
+func TestCoverHandlerS3(t *testing.T) {
+	s3 := storagetest.NewS3Server(t, "library")
+	s := newServerWithConfig(t, func(cfg *config.Config) {
+		bucket := s3.Config()
+		cfg.StorageBackend = "s3"
+		cfg.S3Endpoint = bucket.Endpoint
+		cfg.S3Region = bucket.Region
+		cfg.S3Bucket = bucket.Bucket
+		cfg.S3AccessKeyID = bucket.AccessKeyID
+		cfg.S3SecretAccessKey = bucket.SecretAccessKey
+		cfg.S3PublicURL = "https://covers.example.org"
+	})
+	book := s.fx.Book(nil)
+
+	body, contentType := coverForm(t, "cover.jpg", jpegCover(t, 40, 40))
+	var got envelope[models.Book]
+	expect(t, s.do(http.MethodPost, "/api/v1/books/"+book.ID.String()+"/cover", body, "Content-Type", contentType), http.StatusOK, &got)
+	if !strings.HasPrefix(got.Data.Cover.URL, "https://covers.example.org/covers/") {
+		t.Fatalf("cover URL = %q", got.Data.Cover.URL)
+	}
+	key := strings.TrimPrefix(got.Data.Cover.URL, "https://covers.example.org/")
+	if _, contentType, ok := s3.Object(key); !ok || contentType != "image/jpeg" {
+		t.Fatalf("original not in the bucket: found %v, type %q", ok, contentType)
+	}
+	if s3.Len() != 4 {
+		t.Fatalf("bucket holds %d objects, want 4", s3.Len())
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statusForError maps a service error onto the HTTP status returned to clients.
+func statusForError(err error) int {
+	switch services.KindOf(err) {
+	case services.KindInvalid, services.KindFailedPrecondition:
+		return http.StatusBadRequest
+	case services.KindNotFound:
+		return http.StatusNotFound
+	case services.KindConflict:
+		return http.StatusConflict
+	case services.KindUnauthenticated:
+		return http.StatusUnauthorized
+	case services.KindPermissionDenied:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func respondError(c *gin.Context, err error) {
+	c.JSON(statusForError(err), gin.H{"error": err.Error()})
+}
+
+// respondBatchError reports the operation that aborted an atomic batch
+// alongside the error, so callers can fix and resubmit it.
+func respondBatchError(c *gin.Context, err error) {
+	var opErr *services.BatchOperationError
+	if errors.As(err, &opErr) {
+		c.JSON(statusForError(err), gin.H{"error": err.Error(), "index": opErr.Index})
+		return
+	}
+	respondError(c, err)
+}
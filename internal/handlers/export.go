@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"library-management-go/internal/export"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// streamExport negotiates the export format, writes the download headers and
+// lets produce stream records into the response. Once the first byte is
+// written the status can no longer change, so later failures are logged and
+// the connection is cut short, leaving the client with a failed transfer
+// rather than a truncated file that looks complete.
+func streamExport(c *gin.Context, name string, header []string, produce func(w export.Writer) error) {
+	format, err := export.NegotiateFormat(c.Query("format"), c.GetHeader("Accept"))
+	if err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+	c.Status(http.StatusOK)
+
+	w, err := export.NewWriter(format, c.Writer, header)
+	if err == nil {
+		err = produce(w)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		log.Printf("export of %s failed: %v", name, err)
+		c.Abort()
+		abortConnection(c)
+		return
+	}
+	c.Writer.Flush()
+}
+
+// abortConnection closes the client connection without ending the response,
+// so the chunked body is never terminated. Connections that cannot be taken
+// over are aborted with http.ErrAbortHandler instead.
+func abortConnection(c *gin.Context) {
+	conn, _, err := c.Writer.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	conn.Close()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatBool(b bool) string {
+	return strconv.FormatBool(b)
+}
+
+func formatUUID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
@@ -0,0 +1,85 @@
+package handlers_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"library-management-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func TestExportFormats(t *testing.T) {
+	s := newServer(t)
+	s.fx.Author()
+
+	tests := []struct {
+		name        string
+		path        string
+		accept      string
+		contentType string
+		filename    string
+	}{
+		{"csv by default", "/api/v1/authors/export", "", "text/csv; charset=utf-8", "authors.csv"},
+		{"format parameter", "/api/v1/authors/export?format=jsonl", "text/csv", "application/x-ndjson", "authors.jsonl"},
+		{"accept header", "/api/v1/authors/export", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "authors.xlsx"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(http.MethodGet, tt.path, nil, "Accept", tt.accept)
+			if w.Code != http.StatusOK || w.Header().Get("Content-Type") != tt.contentType {
+				t.Fatalf("status %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+			}
+			if want := `attachment; filename="` + tt.filename + `"`; w.Header().Get("Content-Disposition") != want {
+				t.Fatalf("Content-Disposition = %q, want %q", w.Header().Get("Content-Disposition"), want)
+			}
+		})
+	}
+}
+
+func TestExportFailsMidStream(t *testing.T) {
+	s := newServer(t)
+
+	// More authors than one export batch, so the export queries twice
+	authors := make([]models.Author, 1001)
+	for i := range authors {
+		authors[i].Name = fmt.Sprintf("Author %04d", i)
+	}
+	if err := s.db.CreateInBatches(authors, 200).Error; err != nil {
+		t.Fatalf("create authors: %v", err)
+	}
+
+	queries := 0
+	err := s.db.Callback().Query().Before("gorm:query").Register("test:fail_second_batch", func(tx *gorm.DB) {
+		if tx.Statement.Table == "authors" {
+			if queries++; queries == 2 {
+				tx.AddError(errors.New("connection lost"))
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+
+	srv := httptest.NewServer(s.router)
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/api/v1/authors/export")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err == nil {
+		t.Fatalf("transfer completed with %d bytes despite the failure", len(body))
+	}
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(string(body), "id,name") {
+		t.Fatalf("status %d, body starting %.20q", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+
+	"library-management-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type FineHandler struct {
+	fineService *services.FineService
+}
+
+func NewFineHandler(fineService *services.FineService) *FineHandler {
+	return &FineHandler{fineService: fineService}
+}
+
+// GetBorrowerFines lists a borrower's fines, optionally filtered by
+// status, with the total still outstanding.
+func (h *FineHandler) GetBorrowerFines(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrower ID"})
+		return
+	}
+
+	fines, outstanding, err := h.fineService.WithContext(c.Request.Context()).GetFinesByBorrower(id, c.Query("status"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": fines, "outstanding_total": outstanding})
+}
+
+func (h *FineHandler) PayFine(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid fine ID"})
+		return
+	}
+
+	fine, err := h.fineService.WithContext(c.Request.Context()).PayFine(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": fine})
+}
+
+func (h *FineHandler) WaiveFine(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid fine ID"})
+		return
+	}
+
+	fine, err := h.fineService.WithContext(c.Request.Context()).WaiveFine(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": fine})
+}
@@ -0,0 +1,148 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"library-management-go/internal/config"
+	"library-management-go/internal/repository/gormstore"
+	"library-management-go/internal/routes"
+	"library-management-go/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// testStaffToken is the staff credential test servers are configured with.
+const testStaffToken = "staff-secret"
+
+// server is the full REST API over an in-memory database.
+type server struct {
+	t      *testing.T
+	router *gin.Engine
+	db     *gorm.DB
+	fx     *testutil.Fixtures
+}
+
+func newServer(t *testing.T) *server {
+	t.Helper()
+	return newServerWithConfig(t, func(*config.Config) {})
+}
+
+// newServerWithConfig is newServer with the configuration adjusted by
+// configure.
+func newServerWithConfig(t *testing.T, configure func(*config.Config)) *server {
+	t.Helper()
+
+	cfg := &config.Config{
+		IdempotencyRetention: time.Hour,
+		TrashRetention:       time.Hour,
+		MediaDir:             t.TempDir(),
+		MediaURL:             "/media",
+		StaffToken:           testStaffToken,
+	}
+	configure(cfg)
+
+	db := testutil.NewDB(t)
+	router := gin.New()
+	routes.SetupRoutes(router, db, cfg)
+
+	return &server{t: t, router: router, db: db, fx: testutil.NewFixtures(t, gormstore.New(db))}
+}
+
+// do sends a request with body encoded as JSON (unless nil) and the given
+// header name/value pairs.
+func (s *server) do(method, path string, body interface{}, headers ...string) *httptest.ResponseRecorder {
+	s.t.Helper()
+	return s.serve(s.request(method, path, body, headers...))
+}
+
+// request builds the request do sends, for tests that need to adjust it.
+func (s *server) request(method, path string, body interface{}, headers ...string) *http.Request {
+	s.t.Helper()
+
+	var reader *bytes.Reader
+	switch b := body.(type) {
+	case nil:
+		reader = bytes.NewReader(nil)
+	case string:
+		reader = bytes.NewReader([]byte(b))
+	default:
+		encoded, err := json.Marshal(b)
+		if err != nil {
+			s.t.Fatalf("encode request body: %v", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	return req
+}
+
+func (s *server) serve(req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// expect checks the status code and decodes the JSON body into dest when
+// dest is non-nil.
+func expect(t *testing.T, rec *httptest.ResponseRecorder, status int, dest interface{}) {
+	t.Helper()
+
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, status, rec.Body.String())
+	}
+	if dest != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), dest); err != nil {
+			t.Fatalf("decode response: %v; body: %s", err, rec.Body.String())
+		}
+	}
+}
+
+// envelope is the {"data": ...} wrapper of single-record responses.
+type envelope[T any] struct {
+	Data T `json:"data"`
+}
+
+// page is the response of paginated list endpoints.
+type page[T any] struct {
+	Data       []T `json:"data"`
+	Pagination struct {
+		Page       int   `json:"page"`
+		Limit      int   `json:"limit"`
+		Total      int64 `json:"total"`
+		TotalPages int64 `json:"total_pages"`
+	} `json:"pagination"`
+}
+
+type errorBody struct {
+	Error string `json:"error"`
+	Index *int   `json:"index"`
+}
+
+func TestHealth(t *testing.T) {
+	s := newServer(t)
+
+	var body struct {
+		Status string `json:"status"`
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/health", nil), http.StatusOK, &body)
+	if body.Status != "ok" {
+		t.Fatalf("status = %q", body.Status)
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type HoldHandler struct {
+	holdService *services.HoldService
+}
+
+func NewHoldHandler(holdService *services.HoldService) *HoldHandler {
+	return &HoldHandler{holdService: holdService}
+}
+
+func (h *HoldHandler) PlaceHold(c *gin.Context) {
+	var req models.PlaceHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hold, err := h.holdService.WithContext(c.Request.Context()).PlaceHold(&req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": hold})
+}
+
+func (h *HoldHandler) GetHold(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hold ID"})
+		return
+	}
+
+	hold, err := h.holdService.WithContext(c.Request.Context()).GetHold(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": hold})
+}
+
+func (h *HoldHandler) CancelHold(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hold ID"})
+		return
+	}
+
+	hold, err := h.holdService.WithContext(c.Request.Context()).CancelHold(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": hold})
+}
+
+// GetBookHolds lists the queue of waiting holds on a book.
+func (h *HoldHandler) GetBookHolds(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid book ID"})
+		return
+	}
+
+	holds, err := h.holdService.WithContext(c.Request.Context()).GetHoldQueue(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": holds})
+}
+
+func (h *HoldHandler) GetBorrowerHolds(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrower ID"})
+		return
+	}
+
+	holds, err := h.holdService.WithContext(c.Request.Context()).GetHoldsByBorrower(id, c.Query("status") == models.HoldWaiting)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": holds})
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"strconv"
+
+	"library-management-go/internal/barcode"
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type LabelHandler struct {
+	labelService *services.LabelService
+}
+
+func NewLabelHandler(labelService *services.LabelService) *LabelHandler {
+	return &LabelHandler{labelService: labelService}
+}
+
+// barcodeHeight is the height of rendered bars in modules; with the
+// default scale of 2 pixels a module, bars are 60 pixels tall.
+const barcodeHeight = 30
+
+// writeBarcode renders b as a PNG, or an SVG with format=svg, with each
+// module scale pixels wide.
+func writeBarcode(c *gin.Context, b *barcode.Barcode) {
+	scale := 2
+	if s := c.Query("scale"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > 10 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "scale must be between 1 and 10"})
+			return
+		}
+		scale = n
+	}
+
+	switch c.DefaultQuery("format", "png") {
+	case "png":
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, b.Image(scale, barcodeHeight*scale)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "image/png", buf.Bytes())
+	case "svg":
+		c.Data(http.StatusOK, "image/svg+xml", b.SVG(scale, barcodeHeight*scale))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be png or svg"})
+	}
+}
+
+// GetItemBarcode renders the book's barcode, in Code 128 unless the
+// symbology parameter asks for code39.
+func (h *LabelHandler) GetItemBarcode(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid book ID"})
+		return
+	}
+
+	b, err := h.labelService.WithContext(c.Request.Context()).ItemBarcode(id, c.Query("symbology"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	writeBarcode(c, b)
+}
+
+// GetCardBarcode renders the borrower's library card number as a barcode.
+func (h *LabelHandler) GetCardBarcode(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrower ID"})
+		return
+	}
+
+	b, err := h.labelService.WithContext(c.Request.Context()).CardBarcode(id, c.Query("symbology"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	writeBarcode(c, b)
+}
+
+func (h *LabelHandler) GetLayouts(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.labelService.Layouts()})
+}
+
+// writePDF sends a label sheet for download as filename.
+func writePDF(c *gin.Context, filename string, data []byte) {
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+func (h *LabelHandler) PrintBookLabels(c *gin.Context) {
+	var req models.BookLabelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := h.labelService.WithContext(c.Request.Context()).BookLabels(&req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	writePDF(c, "book-labels.pdf", data)
+}
+
+func (h *LabelHandler) PrintBorrowerLabels(c *gin.Context) {
+	var req models.BorrowerLabelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := h.labelService.WithContext(c.Request.Context()).BorrowerLabels(&req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	writePDF(c, "borrower-labels.pdf", data)
+}
@@ -0,0 +1,82 @@
+package handlers_test
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"strings"
+	"testing"
+
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestLabelHandlerBarcodes(t *testing.T) {
+	s := newServer(t)
+	book := s.fx.Book(nil)
+	borrower := s.fx.Borrower()
+	path := "/api/v1/books/" + book.ID.String() + "/barcode"
+
+	rec := s.do(http.MethodGet, path+"?scale=1", nil)
+	expect(t, rec, http.StatusOK, nil)
+	if ct := rec.Header().Get("Content-Type"); ct != "image/png" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	img, err := png.Decode(rec.Body)
+	if err != nil {
+		t.Fatalf("decode PNG: %v", err)
+	}
+	if img.Bounds().Dy() != 30 {
+		t.Fatalf("barcode is %d pixels tall, want 30", img.Bounds().Dy())
+	}
+
+	rec = s.do(http.MethodGet, path+"?format=svg&symbology=code39", nil)
+	expect(t, rec, http.StatusOK, nil)
+	if !strings.HasPrefix(rec.Body.String(), "<svg ") || rec.Header().Get("Content-Type") != "image/svg+xml" {
+		t.Fatalf("unexpected SVG response %q", rec.Body.String())
+	}
+
+	expect(t, s.do(http.MethodGet, path+"?format=gif", nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodGet, path+"?scale=0", nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodGet, path+"?symbology=qr", nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/books/"+uuid.NewString()+"/barcode", nil), http.StatusNotFound, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowers/"+borrower.ID.String()+"/barcode", nil), http.StatusOK, nil)
+}
+
+func TestLabelHandlerSheets(t *testing.T) {
+	s := newServer(t)
+	book := s.fx.Book(nil)
+	borrower := s.fx.Borrower()
+
+	var layouts envelope[[]models.LabelLayout]
+	expect(t, s.do(http.MethodGet, "/api/v1/labels/layouts", nil), http.StatusOK, &layouts)
+	found := false
+	for _, layout := range layouts.Data {
+		found = found || (layout.Name == "avery-5160" && layout.Columns == 3 && layout.Rows == 10)
+	}
+	if !found {
+		t.Fatalf("avery-5160 not listed in %+v", layouts.Data)
+	}
+
+	rec := s.do(http.MethodPost, "/api/v1/labels/books", models.BookLabelsRequest{BookIDs: []uuid.UUID{book.ID},
+		LabelSheetOptions: models.LabelSheetOptions{Layout: "avery-l7160", Skip: 3}})
+	expect(t, rec, http.StatusOK, nil)
+	if rec.Header().Get("Content-Type") != "application/pdf" || !bytes.HasPrefix(rec.Body.Bytes(), []byte("%PDF-")) {
+		t.Fatalf("expected a PDF, got %q", rec.Header().Get("Content-Type"))
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, "book-labels.pdf") {
+		t.Fatalf("Content-Disposition = %q", cd)
+	}
+
+	expect(t, s.do(http.MethodPost, "/api/v1/labels/borrowers", models.BorrowerLabelsRequest{BorrowerIDs: []uuid.UUID{borrower.ID}}),
+		http.StatusOK, nil)
+
+	expect(t, s.do(http.MethodPost, "/api/v1/labels/books", models.BookLabelsRequest{}), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/labels/books", models.BookLabelsRequest{BookIDs: []uuid.UUID{book.ID},
+		LabelSheetOptions: models.LabelSheetOptions{Symbology: "ean13"}}), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/labels/books", models.BookLabelsRequest{BookIDs: []uuid.UUID{book.ID},
+		LabelSheetOptions: models.LabelSheetOptions{Layout: "avery-9999"}}), http.StatusNotFound, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/labels/borrowers", models.BorrowerLabelsRequest{BorrowerIDs: []uuid.UUID{uuid.New()}}),
+		http.StatusNotFound, nil)
+}
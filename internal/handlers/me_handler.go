@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"library-management-go/internal/middleware"
+	"library-management-go/internal/models"
+	"library-management-go/internal/reqctx"
+	"library-management-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// MeHandler serves the self-service API, where signed-in borrowers act on
+// their own record only. Records belonging to other borrowers are reported
+// as not found.
+type MeHandler struct {
+	borrowerService  *services.BorrowerService
+	borrowingService *services.BorrowingService
+	holdService      *services.HoldService
+	fineService      *services.FineService
+	tenantService    *services.TenantService
+	secret           string
+}
+
+func NewMeHandler(borrowerService *services.BorrowerService, borrowingService *services.BorrowingService,
+	holdService *services.HoldService, fineService *services.FineService, tenantService *services.TenantService,
+	secret string) *MeHandler {
+	return &MeHandler{
+		borrowerService:  borrowerService,
+		borrowingService: borrowingService,
+		holdService:      holdService,
+		fineService:      fineService,
+		tenantService:    tenantService,
+		secret:           secret,
+	}
+}
+
+// me returns the signed-in borrower's ID.
+func me(c *gin.Context) uuid.UUID {
+	return reqctx.BorrowerID(c.Request.Context())
+}
+
+// Login exchanges a library card number and PIN for a bearer token.
+func (h *MeHandler) Login(c *gin.Context) {
+	if h.secret == "" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "self-service is disabled"})
+		return
+	}
+
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	borrower, err := h.borrowerService.WithContext(ctx).Authenticate(&req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// In multi-tenant mode the token names the tenant, so later requests
+	// need nothing else to identify it
+	var slug string
+	tenant, err := h.tenantService.WithContext(ctx).GetCurrentTenant()
+	switch {
+	case err == nil:
+		slug = tenant.Slug
+	case !errors.Is(err, services.ErrTenantNotFound):
+		respondError(c, err)
+		return
+	}
+
+	token, expiresAt, err := middleware.IssueBorrowerToken(h.secret, borrower, slug, time.Now())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"token":      token,
+		"expires_at": expiresAt,
+		"borrower":   borrower,
+	}})
+}
+
+func (h *MeHandler) GetProfile(c *gin.Context) {
+	borrower, err := h.borrowerService.WithContext(c.Request.Context()).GetBorrower(me(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": borrower})
+}
+
+func (h *MeHandler) UpdateProfile(c *gin.Context) {
+	var req models.UpdateContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	borrower, err := h.borrowerService.WithContext(c.Request.Context()).UpdateContact(me(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": borrower})
+}
+
+// GetLoans lists the borrower's loans that have not been returned.
+func (h *MeHandler) GetLoans(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	page, limit = services.NormalizePagination(page, limit)
+
+	borrowings, total, err := h.borrowingService.WithContext(c.Request.Context()).GetActiveBorrowingsByBorrower(me(c), page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": borrowings,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetHistory lists all of the borrower's loans, newest first.
+func (h *MeHandler) GetHistory(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	page, limit = services.NormalizePagination(page, limit)
+
+	borrowings, total, err := h.borrowingService.WithContext(c.Request.Context()).GetBorrowingsByBorrower(me(c), page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": borrowings,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+func (h *MeHandler) RenewLoan(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrowing ID"})
+		return
+	}
+
+	borrowingService := h.borrowingService.WithContext(c.Request.Context())
+
+	// Check if the loan is the borrower's own
+	borrowing, err := borrowingService.GetBorrowing(id)
+	if err == nil && (borrowing.BorrowerID == nil || *borrowing.BorrowerID != me(c)) {
+		err = services.ErrBorrowingNotFound
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Borrowers cannot override their own blocks
+	borrowing, err = borrowingService.RenewBorrowing(id, &models.RenewBorrowingRequest{})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": borrowing})
+}
+
+func (h *MeHandler) GetHolds(c *gin.Context) {
+	holds, err := h.holdService.WithContext(c.Request.Context()).GetHoldsByBorrower(me(c), c.Query("status") == models.HoldWaiting)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": holds})
+}
+
+func (h *MeHandler) PlaceHold(c *gin.Context) {
+	var req models.PlaceOwnHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hold, err := h.holdService.WithContext(c.Request.Context()).PlaceHold(&models.PlaceHoldRequest{
+		BookID:     req.BookID,
+		BorrowerID: me(c),
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": hold})
+}
+
+func (h *MeHandler) CancelHold(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hold ID"})
+		return
+	}
+
+	holdService := h.holdService.WithContext(c.Request.Context())
+
+	// Check if the hold is the borrower's own
+	hold, err := holdService.GetHold(id)
+	if err == nil && (hold.BorrowerID == nil || *hold.BorrowerID != me(c)) {
+		err = services.ErrHoldNotFound
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	hold, err = holdService.CancelHold(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": hold})
+}
+
+func (h *MeHandler) GetFines(c *gin.Context) {
+	fines, outstanding, err := h.fineService.WithContext(c.Request.Context()).GetFinesByBorrower(me(c), c.Query("status"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": fines, "outstanding_total": outstanding})
+}
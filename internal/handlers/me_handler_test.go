@@ -0,0 +1,250 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"library-management-go/internal/config"
+	"library-management-go/internal/middleware"
+	"library-management-go/internal/models"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+const testSecret = "self-service-secret"
+
+// login sets a PIN for borrower and signs them in, returning the
+// Authorization header value for their token.
+func (s *server) login(borrower *models.Borrower) string {
+	s.t.Helper()
+
+	expect(s.t, s.do(http.MethodPut, "/api/v1/borrowers/"+borrower.ID.String()+"/pin",
+		models.SetPINRequest{PIN: "2468"}, middleware.StaffTokenHeader, testStaffToken), http.StatusOK, nil)
+
+	var session envelope[struct {
+		Token     string          `json:"token"`
+		ExpiresAt time.Time       `json:"expires_at"`
+		Borrower  models.Borrower `json:"borrower"`
+	}]
+	expect(s.t, s.do(http.MethodPost, "/api/v1/me/login",
+		models.LoginRequest{CardNumber: borrower.CardNumber, PIN: "2468"}), http.StatusOK, &session)
+	if session.Data.Token == "" || session.Data.Borrower.ID != borrower.ID {
+		s.t.Fatalf("unexpected session %+v", session.Data)
+	}
+	return "Bearer " + session.Data.Token
+}
+
+func newSelfServiceServer(t *testing.T) *server {
+	return newServerWithConfig(t, func(cfg *config.Config) { cfg.JWTSecret = testSecret })
+}
+
+func TestMeHandlerLogin(t *testing.T) {
+	s := newSelfServiceServer(t)
+	borrower := s.fx.Borrower()
+	s.login(borrower)
+
+	expect(t, s.do(http.MethodPost, "/api/v1/me/login",
+		models.LoginRequest{CardNumber: borrower.CardNumber, PIN: "1357"}), http.StatusUnauthorized, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/me/login", map[string]string{"card_number": borrower.CardNumber}),
+		http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodPut, "/api/v1/borrowers/"+borrower.ID.String()+"/pin",
+		models.SetPINRequest{PIN: "12ab"}, middleware.StaffTokenHeader, testStaffToken), http.StatusBadRequest, nil)
+
+	// Only staff may set a PIN, or anyone could sign in as the borrower
+	expect(t, s.do(http.MethodPut, "/api/v1/borrowers/"+borrower.ID.String()+"/pin",
+		models.SetPINRequest{PIN: "1357"}), http.StatusUnauthorized, nil)
+	expect(t, s.do(http.MethodPut, "/api/v1/borrowers/"+borrower.ID.String()+"/pin",
+		models.SetPINRequest{PIN: "1357"}, middleware.StaffTokenHeader, "guess"), http.StatusUnauthorized, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/me/login",
+		models.LoginRequest{CardNumber: borrower.CardNumber, PIN: "1357"}), http.StatusUnauthorized, nil)
+}
+
+func TestMeHandlerAuthentication(t *testing.T) {
+	s := newSelfServiceServer(t)
+	borrower := s.fx.Borrower()
+
+	expect(t, s.do(http.MethodGet, "/api/v1/me", nil), http.StatusUnauthorized, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/me", nil, "Authorization", "Bearer not-a-token"), http.StatusUnauthorized, nil)
+
+	expired, _, err := middleware.IssueBorrowerToken(testSecret, borrower, "", time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/me", nil, "Authorization", "Bearer "+expired), http.StatusUnauthorized, nil)
+
+	forged, _, err := middleware.IssueBorrowerToken("another-secret", borrower, "", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/me", nil, "Authorization", "Bearer "+forged), http.StatusUnauthorized, nil)
+
+	// Sessions end when the PIN changes or the borrower is deleted
+	auth := s.login(borrower)
+	expect(t, s.do(http.MethodGet, "/api/v1/me", nil, "Authorization", auth), http.StatusOK, nil)
+	expect(t, s.do(http.MethodPut, "/api/v1/borrowers/"+borrower.ID.String()+"/pin",
+		models.SetPINRequest{PIN: "8642"}, middleware.StaffTokenHeader, testStaffToken), http.StatusOK, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/me", nil, "Authorization", auth), http.StatusUnauthorized, nil)
+	auth = s.login(borrower)
+	expect(t, s.do(http.MethodDelete, "/api/v1/borrowers/"+borrower.ID.String(), nil), http.StatusOK, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/me", nil, "Authorization", auth), http.StatusUnauthorized, nil)
+
+	// Without a secret the self-service API is switched off
+	disabled := newServer(t)
+	expect(t, disabled.do(http.MethodGet, "/api/v1/me", nil), http.StatusForbidden, nil)
+	expect(t, disabled.do(http.MethodPost, "/api/v1/me/login",
+		models.LoginRequest{CardNumber: borrower.CardNumber, PIN: "2468"}), http.StatusForbidden, nil)
+}
+
+func TestMeHandlerProfile(t *testing.T) {
+	s := newSelfServiceServer(t)
+	borrower := s.fx.Borrower()
+	auth := s.login(borrower)
+
+	var profile envelope[models.Borrower]
+	expect(t, s.do(http.MethodGet, "/api/v1/me", nil, "Authorization", auth), http.StatusOK, &profile)
+	if profile.Data.ID != borrower.ID {
+		t.Fatalf("profile of %v, want %v", profile.Data.ID, borrower.ID)
+	}
+
+	var updated envelope[models.Borrower]
+	expect(t, s.do(http.MethodPut, "/api/v1/me", models.UpdateContactRequest{Phone: "555-0199", Address: "1 Main St"},
+		"Authorization", auth), http.StatusOK, &updated)
+	if updated.Data.Phone != "555-0199" || updated.Data.Address != "1 Main St" || updated.Data.Name != borrower.Name {
+		t.Fatalf("unexpected profile %+v", updated.Data)
+	}
+	expect(t, s.do(http.MethodPut, "/api/v1/me", map[string]string{"email": "not-an-email"}, "Authorization", auth),
+		http.StatusBadRequest, nil)
+}
+
+func TestMeHandlerLoans(t *testing.T) {
+	s := newSelfServiceServer(t)
+	borrower := s.fx.Borrower()
+	auth := s.login(borrower)
+
+	active := s.fx.Borrowing(nil, borrower)
+	s.fx.Borrowing(nil, borrower, testutil.Returned)
+	other := s.fx.Borrowing(nil, nil)
+
+	var loans page[models.Borrowing]
+	expect(t, s.do(http.MethodGet, "/api/v1/me/loans", nil, "Authorization", auth), http.StatusOK, &loans)
+	if len(loans.Data) != 1 || loans.Data[0].ID != active.ID {
+		t.Fatalf("expected the active loan only, got %+v", loans.Data)
+	}
+
+	var history page[models.Borrowing]
+	expect(t, s.do(http.MethodGet, "/api/v1/me/history", nil, "Authorization", auth), http.StatusOK, &history)
+	if history.Pagination.Total != 2 {
+		t.Fatalf("history total = %d, want 2", history.Pagination.Total)
+	}
+
+	var renewed envelope[models.Borrowing]
+	expect(t, s.do(http.MethodPost, "/api/v1/me/loans/"+active.ID.String()+"/renew", nil, "Authorization", auth),
+		http.StatusOK, &renewed)
+	if renewed.Data.RenewalCount != 1 || !renewed.Data.DueDate.After(active.DueDate) {
+		t.Fatalf("unexpected renewal %+v", renewed.Data)
+	}
+
+	// Another borrower's loan is not visible
+	expect(t, s.do(http.MethodPost, "/api/v1/me/loans/"+other.ID.String()+"/renew", nil, "Authorization", auth),
+		http.StatusNotFound, nil)
+}
+
+func TestMeHandlerHolds(t *testing.T) {
+	s := newSelfServiceServer(t)
+	borrower := s.fx.Borrower()
+	auth := s.login(borrower)
+	book := s.fx.Borrowing(nil, nil).Book
+
+	var hold envelope[models.Hold]
+	expect(t, s.do(http.MethodPost, "/api/v1/me/holds", models.PlaceOwnHoldRequest{BookID: book.ID},
+		"Authorization", auth), http.StatusCreated, &hold)
+	if hold.Data.BorrowerID == nil || *hold.Data.BorrowerID != borrower.ID || hold.Data.Status != models.HoldWaiting {
+		t.Fatalf("unexpected hold %+v", hold.Data)
+	}
+	expect(t, s.do(http.MethodPost, "/api/v1/me/holds", models.PlaceOwnHoldRequest{BookID: book.ID},
+		"Authorization", auth), http.StatusConflict, nil)
+
+	var holds envelope[[]models.Hold]
+	expect(t, s.do(http.MethodGet, "/api/v1/me/holds", nil, "Authorization", auth), http.StatusOK, &holds)
+	if len(holds.Data) != 1 {
+		t.Fatalf("expected 1 hold, got %d", len(holds.Data))
+	}
+
+	other := s.fx.Hold(nil, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/me/holds/"+other.ID.String()+"/cancel", nil, "Authorization", auth),
+		http.StatusNotFound, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/me/holds/"+uuid.NewString()+"/cancel", nil, "Authorization", auth),
+		http.StatusNotFound, nil)
+
+	var cancelled envelope[models.Hold]
+	expect(t, s.do(http.MethodPost, "/api/v1/me/holds/"+hold.Data.ID.String()+"/cancel", nil, "Authorization", auth),
+		http.StatusOK, &cancelled)
+	if cancelled.Data.Status != models.HoldCancelled {
+		t.Fatalf("status = %q, want cancelled", cancelled.Data.Status)
+	}
+}
+
+func TestMeHandlerFines(t *testing.T) {
+	s := newSelfServiceServer(t)
+	borrower := s.fx.Borrower()
+	auth := s.login(borrower)
+	s.fx.Fine(borrower, 150)
+	s.fx.Fine(s.fx.Borrower(), 900)
+
+	var fines struct {
+		Data             []models.Fine `json:"data"`
+		OutstandingTotal int64         `json:"outstanding_total"`
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/me/fines", nil, "Authorization", auth), http.StatusOK, &fines)
+	if len(fines.Data) != 1 || fines.OutstandingTotal != 150 {
+		t.Fatalf("got %d fines and %d outstanding, want 1 and 150", len(fines.Data), fines.OutstandingTotal)
+	}
+}
+
+func TestHoldAndFineHandlers(t *testing.T) {
+	s := newServer(t)
+	borrower := s.fx.Borrower()
+	book := s.fx.Borrowing(nil, nil).Book
+
+	var hold envelope[models.Hold]
+	expect(t, s.do(http.MethodPost, "/api/v1/holds", models.PlaceHoldRequest{BookID: book.ID, BorrowerID: borrower.ID}),
+		http.StatusCreated, &hold)
+	expect(t, s.do(http.MethodGet, "/api/v1/holds/"+hold.Data.ID.String(), nil), http.StatusOK, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/holds/"+uuid.NewString(), nil), http.StatusNotFound, nil)
+
+	var queue envelope[[]models.Hold]
+	expect(t, s.do(http.MethodGet, "/api/v1/books/"+book.ID.String()+"/holds", nil), http.StatusOK, &queue)
+	if len(queue.Data) != 1 || queue.Data[0].ID != hold.Data.ID {
+		t.Fatalf("unexpected queue %+v", queue.Data)
+	}
+	expect(t, s.do(http.MethodPost, "/api/v1/holds/"+hold.Data.ID.String()+"/cancel", nil), http.StatusOK, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/holds/"+hold.Data.ID.String()+"/cancel", nil), http.StatusBadRequest, nil)
+
+	var borrowerHolds envelope[[]models.Hold]
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowers/"+borrower.ID.String()+"/holds?status=waiting", nil),
+		http.StatusOK, &borrowerHolds)
+	if len(borrowerHolds.Data) != 0 {
+		t.Fatalf("expected no waiting holds, got %+v", borrowerHolds.Data)
+	}
+
+	fine := s.fx.Fine(borrower, 75)
+	var paid envelope[models.Fine]
+	expect(t, s.do(http.MethodPost, "/api/v1/fines/"+fine.ID.String()+"/pay", nil, middleware.ActorHeader, "front-desk"),
+		http.StatusOK, &paid)
+	if paid.Data.Status != models.FinePaid || paid.Data.SettledBy != "front-desk" {
+		t.Fatalf("unexpected fine %+v", paid.Data)
+	}
+	expect(t, s.do(http.MethodPost, "/api/v1/fines/"+fine.ID.String()+"/waive", nil), http.StatusBadRequest, nil)
+
+	var fines struct {
+		Data             []models.Fine `json:"data"`
+		OutstandingTotal int64         `json:"outstanding_total"`
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowers/"+borrower.ID.String()+"/fines?status=paid", nil),
+		http.StatusOK, &fines)
+	if len(fines.Data) != 1 || fines.OutstandingTotal != 0 {
+		t.Fatalf("unexpected fines %+v", fines)
+	}
+}
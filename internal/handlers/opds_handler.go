@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/opds"
+	"library-management-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// OPDS versions the catalog is served in.
+const (
+	OPDS1 = "1.2"
+	OPDS2 = "2.0"
+)
+
+// defaultCatalogTitle names the catalog of a library without a tenant name.
+const defaultCatalogTitle = "Library catalog"
+
+// opdsCatalogKey holds the catalog a request is for in the gin context.
+const opdsCatalogKey = "opds_catalog"
+
+// opdsCatalog is one of the OPDS catalogs, served under basePath.
+type opdsCatalog struct {
+	version  string
+	basePath string
+}
+
+// OPDSHandler serves the catalog to e-reader apps as OPDS feeds, see
+// package opds.
+type OPDSHandler struct {
+	bookService    *services.BookService
+	authorService  *services.AuthorService
+	subjectService *services.SubjectService
+	holdService    *services.HoldService
+	tenantService  *services.TenantService
+}
+
+func NewOPDSHandler(bookService *services.BookService, authorService *services.AuthorService, subjectService *services.SubjectService,
+	holdService *services.HoldService, tenantService *services.TenantService) *OPDSHandler {
+	return &OPDSHandler{
+		bookService:    bookService,
+		authorService:  authorService,
+		subjectService: subjectService,
+		holdService:    holdService,
+		tenantService:  tenantService,
+	}
+}
+
+// Catalog serves the routes it is used on as the OPDS catalog of version
+// rooted at basePath.
+func (h *OPDSHandler) Catalog(version, basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(opdsCatalogKey, opdsCatalog{version: version, basePath: basePath})
+		c.Next()
+	}
+}
+
+// opdsRoot returns the absolute URL of the catalog the request is for, which
+// feeds link to so that apps can follow them from anywhere.
+func opdsRoot(c *gin.Context) string {
+	return requestScheme(c) + "://" + c.Request.Host + c.MustGet(opdsCatalogKey).(opdsCatalog).basePath
+}
+
+func isOPDS2(c *gin.Context) bool {
+	return c.MustGet(opdsCatalogKey).(opdsCatalog).version == OPDS2
+}
+
+// catalogTitle names the catalog after the library.
+func catalogTitle(c *gin.Context, tenantService *services.TenantService) (string, error) {
+	tenant, err := tenantService.WithContext(c.Request.Context()).GetCurrentTenant()
+	if errors.Is(err, services.ErrTenantNotFound) {
+		return defaultCatalogTitle, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return tenant.Name, nil
+}
+
+// opdsPage reads the page and limit query parameters.
+func opdsPage(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(opds.DefaultLimit)))
+	return services.NormalizePagination(page, limit)
+}
+
+// holds counts the patrons waiting for each of books.
+func (h *OPDSHandler) holds(c *gin.Context, books []models.Book) (map[uuid.UUID]int64, error) {
+	ids := make([]uuid.UUID, len(books))
+	for i := range books {
+		ids[i] = books[i].ID
+	}
+	return h.holdService.WithContext(c.Request.Context()).CountWaiting(ids)
+}
+
+// render writes f in the catalog's format.
+func (h *OPDSHandler) render(c *gin.Context, f *opds.Feed) {
+	var data []byte
+	var err error
+	var contentType string
+	switch {
+	case isOPDS2(c):
+		data, err = opds.JSON(f, opdsRoot(c))
+		contentType = opds.FeedJSONType
+	case f.Kind == opds.Navigation:
+		data, err = opds.Atom(f, opdsRoot(c))
+		contentType = opds.NavigationType
+	default:
+		data, err = opds.Atom(f, opdsRoot(c))
+		contentType = opds.AcquisitionType
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// books renders a page of an acquisition feed built by feed from books,
+// or the error that stopped them being listed.
+func (h *OPDSHandler) books(c *gin.Context, books []models.Book, err error, feed func(holds map[uuid.UUID]int64) *opds.Feed) {
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	holds, err := h.holds(c, books)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.render(c, feed(holds))
+}
+
+// Start serves the start feed, leading to new additions, authors and
+// subjects.
+func (h *OPDSHandler) Start(c *gin.Context) {
+	title, err := catalogTitle(c, h.tenantService)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.render(c, opds.Start(title))
+}
+
+// New serves every book, newest first.
+func (h *OPDSHandler) New(c *gin.Context) {
+	page, limit := opdsPage(c)
+	books, total, err := h.bookService.WithContext(c.Request.Context()).FindBooks(models.BookFilter{NewestFirst: true}, page, limit)
+	h.books(c, books, err, func(holds map[uuid.UUID]int64) *opds.Feed {
+		return opds.New(books, holds, opds.Page{Number: page, Limit: limit, Total: total})
+	})
+}
+
+// Authors serves the navigation feed of authors.
+func (h *OPDSHandler) Authors(c *gin.Context) {
+	page, limit := opdsPage(c)
+	authors, total, err := h.authorService.WithContext(c.Request.Context()).GetAllAuthors(page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.render(c, opds.Authors(authors, opds.Page{Number: page, Limit: limit, Total: total}))
+}
+
+// AuthorBooks serves the books an author is credited on, newest first.
+func (h *OPDSHandler) AuthorBooks(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid author ID"})
+		return
+	}
+
+	author, err := h.authorService.WithContext(c.Request.Context()).GetAuthor(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, limit := opdsPage(c)
+	filter := models.BookFilter{AuthorID: id, NewestFirst: true}
+	books, total, err := h.bookService.WithContext(c.Request.Context()).FindBooks(filter, page, limit)
+	h.books(c, books, err, func(holds map[uuid.UUID]int64) *opds.Feed {
+		return opds.AuthorBooks(author, books, holds, opds.Page{Number: page, Limit: limit, Total: total})
+	})
+}
+
+// Subjects serves the navigation feed of top-level subjects.
+func (h *OPDSHandler) Subjects(c *gin.Context) {
+	page, limit := opdsPage(c)
+	subjects, total, err := h.subjectService.WithContext(c.Request.Context()).FindSubjects(models.SubjectFilter{TopLevel: true}, page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.render(c, opds.Subjects(subjects, opds.Page{Number: page, Limit: limit, Total: total}))
+}
+
+// SubjectBooks serves the books under a subject or any narrower one,
+// newest first.
+func (h *OPDSHandler) SubjectBooks(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subject ID"})
+		return
+	}
+
+	subject, err := h.subjectService.WithContext(c.Request.Context()).GetSubject(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, limit := opdsPage(c)
+	filter := models.BookFilter{SubjectID: id, NewestFirst: true}
+	books, total, err := h.bookService.WithContext(c.Request.Context()).FindBooks(filter, page, limit)
+	h.books(c, books, err, func(holds map[uuid.UUID]int64) *opds.Feed {
+		return opds.SubjectBooks(subject, books, holds, opds.Page{Number: page, Limit: limit, Total: total})
+	})
+}
+
+// Search serves the books matching the q parameter.
+func (h *OPDSHandler) Search(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "search query is required"})
+		return
+	}
+
+	page, limit := opdsPage(c)
+	books, total, err := h.bookService.WithContext(c.Request.Context()).SearchBooks(query, page, limit)
+	h.books(c, books, err, func(holds map[uuid.UUID]int64) *opds.Feed {
+		return opds.SearchResults(query, books, holds, opds.Page{Number: page, Limit: limit, Total: total})
+	})
+}
+
+// OpenSearch serves the OpenSearch description of the OPDS 1.2 search.
+func (h *OPDSHandler) OpenSearch(c *gin.Context) {
+	title, err := catalogTitle(c, h.tenantService)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	data, err := opds.OpenSearch(title, opdsRoot(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, opds.OpenSearchType, data)
+}
+
+// GetBook serves the complete entry of a book.
+func (h *OPDSHandler) GetBook(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid book ID"})
+		return
+	}
+
+	book, err := h.bookService.WithContext(c.Request.Context()).GetBook(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	holds, err := h.holds(c, []models.Book{*book})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	publication := opds.NewPublication(book, holds[book.ID])
+	var data []byte
+	contentType := opds.EntryType
+	if isOPDS2(c) {
+		data, err = opds.JSONPublication(publication, opdsRoot(c))
+		contentType = opds.PublicationJSONType
+	} else {
+		data, err = opds.AtomEntry(publication, opdsRoot(c))
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, contentType, data)
+}
@@ -0,0 +1,188 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"testing"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/opds"
+
+	"github.com/google/uuid"
+)
+
+const opdsURL = "http://example.com/api/v1/opds/v1.2"
+
+type opdsFeed struct {
+	Title string `xml:"title"`
+	Links []struct {
+		Rel  string `xml:"rel,attr"`
+		Href string `xml:"href,attr"`
+	} `xml:"link"`
+	Entries []struct {
+		Title string `xml:"title"`
+		Links []struct {
+			Rel  string `xml:"rel,attr"`
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+	} `xml:"entry"`
+}
+
+func (f opdsFeed) link(rel string) string {
+	for _, l := range f.Links {
+		if l.Rel == rel {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// getFeed fetches an OPDS 1.2 feed, checking its content type.
+func (s *server) getFeed(t *testing.T, path, contentType string) opdsFeed {
+	t.Helper()
+
+	w := s.do(http.MethodGet, "/api/v1/opds/v1.2"+path, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s: status %d: %s", path, w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != contentType {
+		t.Fatalf("GET %s: content type %q, want %q", path, got, contentType)
+	}
+	var feed opdsFeed
+	if err := xml.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	return feed
+}
+
+func TestOPDSHandlerFeeds(t *testing.T) {
+	s := newServer(t)
+	author := s.fx.Author(func(a *models.Author) { a.Name = "Octavia Butler" })
+	kindred := s.fx.Book(author, func(b *models.Book) { b.Title = "Kindred" })
+	dawn := s.fx.Book(author, func(b *models.Book) { b.Title = "Dawn" })
+	s.fx.Hold(dawn, s.fx.Borrower())
+	s.fx.Book(nil)
+
+	start := s.getFeed(t, "", opds.NavigationType)
+	if start.Title != "Library catalog" || len(start.Entries) != 3 {
+		t.Fatalf("unexpected start feed %+v", start)
+	}
+	if got := start.link("search"); got != opdsURL+"/opensearch.xml" {
+		t.Fatalf("search link = %q", got)
+	}
+
+	authors := s.getFeed(t, "/authors", opds.NavigationType)
+	if len(authors.Entries) != 2 || authors.Entries[1].Title != "Octavia Butler" {
+		t.Fatalf("unexpected authors %+v", authors.Entries)
+	}
+
+	books := s.getFeed(t, "/authors/"+author.ID.String(), opds.AcquisitionType)
+	if len(books.Entries) != 2 {
+		t.Fatalf("got %d books by the author, want 2", len(books.Entries))
+	}
+	if got := books.link("up"); got != opdsURL+"/authors" {
+		t.Fatalf("up link = %q", got)
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/opds/v1.2/authors/"+uuid.NewString(), nil), http.StatusNotFound, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/opds/v1.2/authors/nope", nil), http.StatusBadRequest, nil)
+
+	// New additions page through every book
+	page := s.getFeed(t, "/new?limit=2", opds.AcquisitionType)
+	if len(page.Entries) != 2 || page.link("next") != opdsURL+"/new?limit=2&page=2" {
+		t.Fatalf("unexpected first page %+v", page)
+	}
+	page = s.getFeed(t, "/new?limit=2&page=2", opds.AcquisitionType)
+	if len(page.Entries) != 1 || page.link("next") != "" || page.link("previous") != opdsURL+"/new?limit=2" {
+		t.Fatalf("unexpected last page %+v", page)
+	}
+
+	w := s.do(http.MethodGet, "/api/v1/opds/v1.2/books/"+dawn.ID.String(), nil)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != opds.EntryType {
+		t.Fatalf("book entry: status %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Body.String(), `<opds:availability status="reserved">`) {
+		t.Fatalf("held book is not reserved:\n%s", w.Body.String())
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/opds/v1.2/books/"+uuid.NewString(), nil), http.StatusNotFound, nil)
+
+	search := s.getFeed(t, "/search?q=kindred", opds.AcquisitionType)
+	if len(search.Entries) != 1 || search.Entries[0].Title != kindred.Title {
+		t.Fatalf("unexpected search results %+v", search.Entries)
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/opds/v1.2/search", nil), http.StatusBadRequest, nil)
+
+	w = s.do(http.MethodGet, "/api/v1/opds/v1.2/opensearch.xml", nil)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != opds.OpenSearchType {
+		t.Fatalf("opensearch: status %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Body.String(), opdsURL+"/search?q={searchTerms}") {
+		t.Fatalf("unexpected description:\n%s", w.Body.String())
+	}
+}
+
+func TestOPDSHandlerSubjects(t *testing.T) {
+	s := newServer(t)
+	fiction := s.fx.Subject(nil, func(sub *models.Subject) { sub.Name = "Fiction" })
+	scifi := s.fx.Subject(fiction, func(sub *models.Subject) { sub.Name = "Science fiction" })
+	s.fx.Tag(s.fx.Book(nil), scifi)
+
+	subjects := s.getFeed(t, "/subjects", opds.NavigationType)
+	if len(subjects.Entries) != 1 || subjects.Entries[0].Title != "Fiction" {
+		t.Fatalf("unexpected subjects %+v", subjects.Entries)
+	}
+
+	// The parent subject lists its children's books and offers them as facets
+	feed := s.getFeed(t, "/subjects/"+fiction.ID.String(), opds.AcquisitionType)
+	if len(feed.Entries) != 1 {
+		t.Fatalf("got %d books under the subject, want 1", len(feed.Entries))
+	}
+	if got := feed.link("http://opds-spec.org/facet"); got != opdsURL+"/subjects/"+scifi.ID.String() {
+		t.Fatalf("facet link = %q", got)
+	}
+	child := s.getFeed(t, "/subjects/"+scifi.ID.String(), opds.AcquisitionType)
+	if got := child.link("up"); got != opdsURL+"/subjects/"+fiction.ID.String() {
+		t.Fatalf("up link = %q", got)
+	}
+}
+
+func TestOPDSHandlerJSON(t *testing.T) {
+	s := newServer(t)
+	book := s.fx.Book(nil)
+
+	var start struct {
+		Metadata   struct{ Title string }
+		Navigation []struct{ Href, Title string }
+	}
+	w := s.do(http.MethodGet, "/api/v1/opds/v2", nil)
+	if w.Header().Get("Content-Type") != opds.FeedJSONType {
+		t.Fatalf("content type %q", w.Header().Get("Content-Type"))
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &start); err != nil {
+		t.Fatal(err)
+	}
+	if len(start.Navigation) != 3 || start.Navigation[0].Href != "http://example.com/api/v1/opds/v2/new" {
+		t.Fatalf("unexpected start feed %+v", start)
+	}
+
+	var feed struct {
+		Publications []struct {
+			Metadata struct{ Title string }
+		}
+	}
+	w = s.do(http.MethodGet, "/api/v1/opds/v2/new", nil)
+	if err := json.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatal(err)
+	}
+	if len(feed.Publications) != 1 || feed.Publications[0].Metadata.Title != book.Title {
+		t.Fatalf("unexpected publications %+v", feed.Publications)
+	}
+
+	w = s.do(http.MethodGet, "/api/v1/opds/v2/books/"+book.ID.String(), nil)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != opds.PublicationJSONType {
+		t.Fatalf("publication: status %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	// OpenSearch descriptions are OPDS 1.2 only
+	expect(t, s.do(http.MethodGet, "/api/v1/opds/v2/opensearch.xml", nil), http.StatusNotFound, nil)
+}
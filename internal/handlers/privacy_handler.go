@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"library-management-go/internal/export"
+	"library-management-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PrivacyHandler struct {
+	privacyService *services.PrivacyService
+}
+
+func NewPrivacyHandler(privacyService *services.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{privacyService: privacyService}
+}
+
+func (h *PrivacyHandler) AnonymizeHistory(c *gin.Context) {
+	loans, holds, err := h.privacyService.WithContext(c.Request.Context()).AnonymizeHistory()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"anonymized": loans, "anonymized_holds": holds}})
+}
+
+// ExportBorrowerData downloads a ZIP archive of everything held about the
+// borrower, for answering subject access requests.
+func (h *PrivacyHandler) ExportBorrowerData(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrower ID"})
+		return
+	}
+
+	data, err := h.privacyService.WithContext(c.Request.Context()).GetBorrowerData(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", export.ArchiveContentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="borrower-%s.zip"`, id))
+	c.Status(http.StatusOK)
+	if err := export.WriteBorrowerArchive(c.Writer, data); err != nil {
+		log.Printf("export of borrower %s failed: %v", id, err)
+		c.Abort()
+	}
+}
+
+func (h *PrivacyHandler) EraseBorrower(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrower ID"})
+		return
+	}
+
+	borrower, err := h.privacyService.WithContext(c.Request.Context()).EraseBorrower(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": borrower})
+}
@@ -0,0 +1,147 @@
+package handlers_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"library-management-go/internal/config"
+	"library-management-go/internal/middleware"
+	"library-management-go/internal/models"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestPrivacyHandlerAnonymizeHistory(t *testing.T) {
+	s := newSelfServiceServer(t)
+	borrower := s.fx.Borrower()
+	auth := s.login(borrower)
+	old := s.fx.Borrowing(nil, borrower, testutil.ReturnedAgo(200*24*time.Hour))
+
+	// Opting in keeps the history
+	var profile envelope[models.Borrower]
+	expect(t, s.do(http.MethodPut, "/api/v1/me", map[string]bool{"keep_history": true}, "Authorization", auth),
+		http.StatusOK, &profile)
+	if !profile.Data.KeepHistory {
+		t.Fatal("expected keep_history to be set")
+	}
+
+	var result envelope[struct {
+		Anonymized int64 `json:"anonymized"`
+	}]
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/anonymize", nil), http.StatusOK, &result)
+	if result.Data.Anonymized != 0 {
+		t.Fatalf("anonymized %d loans of a borrower keeping their history", result.Data.Anonymized)
+	}
+
+	expect(t, s.do(http.MethodPut, "/api/v1/me", map[string]bool{"keep_history": false}, "Authorization", auth),
+		http.StatusOK, nil)
+	var second envelope[struct {
+		Anonymized int64 `json:"anonymized"`
+	}]
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/anonymize", nil), http.StatusOK, &second)
+	if second.Data.Anonymized != 1 {
+		t.Fatalf("anonymized %d loans, want 1", second.Data.Anonymized)
+	}
+
+	var history page[models.Borrowing]
+	expect(t, s.do(http.MethodGet, "/api/v1/me/history", nil, "Authorization", auth), http.StatusOK, &history)
+	if history.Pagination.Total != 0 {
+		t.Fatalf("expected an empty history, got %d loans", history.Pagination.Total)
+	}
+
+	var loan envelope[models.Borrowing]
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowings/"+old.ID.String(), nil), http.StatusOK, &loan)
+	if loan.Data.BorrowerID != nil || loan.Data.Borrower != nil || loan.Data.Book.ID != old.BookID {
+		t.Fatalf("unexpected loan %+v", loan.Data)
+	}
+
+	// The audit trail no longer names the borrower either
+	var entries page[models.AuditLog]
+	expect(t, s.do(http.MethodGet, "/api/v1/audit?entity_type=borrowing&entity_id="+old.ID.String(), nil),
+		http.StatusOK, &entries)
+	if len(entries.Data) == 0 {
+		t.Fatal("expected the loan's audit entries to be kept")
+	}
+	for _, entry := range entries.Data {
+		var changes map[string]json.RawMessage
+		if err := json.Unmarshal(entry.Changes, &changes); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := changes["borrower_id"]; ok {
+			t.Fatalf("audit entry %v still records the borrower: %s", entry.ID, entry.Changes)
+		}
+	}
+}
+
+func TestPrivacyHandlerBorrowerData(t *testing.T) {
+	s := newServer(t)
+	borrower := s.fx.Borrower()
+	s.fx.Borrowing(nil, borrower, testutil.Returned)
+	base := "/api/v1/borrowers/" + borrower.ID.String()
+
+	// Only staff can see or erase a borrower's data
+	expect(t, s.do(http.MethodGet, base+"/data-export", nil), http.StatusUnauthorized, nil)
+	expect(t, s.do(http.MethodPost, base+"/erase", nil, middleware.StaffTokenHeader, "guess"), http.StatusUnauthorized, nil)
+	disabled := newServerWithConfig(t, func(cfg *config.Config) { cfg.StaffToken = "" })
+	expect(t, disabled.do(http.MethodGet, base+"/data-export", nil, middleware.StaffTokenHeader, ""), http.StatusForbidden, nil)
+
+	rec := s.do(http.MethodGet, base+"/data-export", nil, middleware.StaffTokenHeader, testStaffToken)
+	expect(t, rec, http.StatusOK, nil)
+	if got := rec.Header().Get("Content-Type"); got != "application/zip" {
+		t.Fatalf("Content-Type = %q", got)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	files := map[string]*zip.File{}
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+	for _, name := range []string{"manifest.json", "borrower.json", "loans.json", "fines.json", "holds.json", "blocks.json", "audit_log.json"} {
+		if files[name] == nil {
+			t.Fatalf("archive is missing %s", name)
+		}
+	}
+	var loans []models.Borrowing
+	readArchiveJSON(t, files["loans.json"], &loans)
+	if len(loans) != 1 {
+		t.Fatalf("expected 1 loan in the archive, got %d", len(loans))
+	}
+	var fines []models.Fine
+	readArchiveJSON(t, files["fines.json"], &fines)
+	if fines == nil || len(fines) != 0 {
+		t.Fatalf("expected an empty fines list, got %v", fines)
+	}
+
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowers/"+uuid.NewString()+"/data-export", nil, middleware.StaffTokenHeader, testStaffToken), http.StatusNotFound, nil)
+
+	var erased envelope[models.Borrower]
+	expect(t, s.do(http.MethodPost, base+"/erase", nil, middleware.StaffTokenHeader, testStaffToken), http.StatusOK, &erased)
+	if erased.Data.Status != models.BorrowerErased || erased.Data.Email == borrower.Email {
+		t.Fatalf("unexpected borrower %+v", erased.Data)
+	}
+	expect(t, s.do(http.MethodPost, base+"/erase", nil, middleware.StaffTokenHeader, testStaffToken), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodPut, base, models.UpdateBorrowerRequest{Name: "Back again"}), http.StatusBadRequest, nil)
+
+	owing := s.fx.Borrower()
+	s.fx.Fine(owing, 250)
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowers/"+owing.ID.String()+"/erase", nil, middleware.StaffTokenHeader, testStaffToken), http.StatusBadRequest, nil)
+}
+
+func readArchiveJSON(t *testing.T, f *zip.File, dest interface{}) {
+	t.Helper()
+	r, err := f.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if err := json.NewDecoder(r).Decode(dest); err != nil {
+		t.Fatalf("decode %s: %v", f.Name, err)
+	}
+}
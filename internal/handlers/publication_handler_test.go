@@ -0,0 +1,100 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestPublisherHandlerCRUD(t *testing.T) {
+	s := newServer(t)
+	penguin := s.fx.Publisher(func(p *models.Publisher) { p.Name = "Penguin" })
+
+	tests := []struct {
+		name   string
+		body   interface{}
+		status int
+	}{
+		{"valid", models.CreatePublisherRequest{Name: "Faber & Faber", Place: "London", Website: "https://www.faber.co.uk"}, http.StatusCreated},
+		{"missing name", models.CreatePublisherRequest{Place: "London"}, http.StatusBadRequest},
+		{"bad website", models.CreatePublisherRequest{Name: "Vintage", Website: "not a url"}, http.StatusBadRequest},
+		{"duplicate name", models.CreatePublisherRequest{Name: "penguin"}, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect(t, s.do(http.MethodPost, "/api/v1/publishers", tt.body), tt.status, nil)
+		})
+	}
+
+	var list page[models.Publisher]
+	expect(t, s.do(http.MethodGet, "/api/v1/publishers?search=fab", nil), http.StatusOK, &list)
+	if list.Pagination.Total != 1 || list.Data[0].Name != "Faber & Faber" {
+		t.Fatalf("unexpected publishers %+v", list.Data)
+	}
+
+	id := penguin.ID.String()
+	var got envelope[models.Publisher]
+	expect(t, s.do(http.MethodPut, "/api/v1/publishers/"+id, models.UpdatePublisherRequest{Place: "Harmondsworth"}), http.StatusOK, &got)
+	if got.Data.Place != "Harmondsworth" || got.Data.Name != "Penguin" {
+		t.Fatalf("unexpected publisher %+v", got.Data)
+	}
+
+	s.fx.Book(nil, func(b *models.Book) { b.PublisherID = &penguin.ID })
+	expect(t, s.do(http.MethodDelete, "/api/v1/publishers/"+id, nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/publishers/"+uuid.NewString(), nil), http.StatusNotFound, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/publishers/nope", nil), http.StatusBadRequest, nil)
+}
+
+func TestPublicationHandlerBooks(t *testing.T) {
+	s := newServer(t)
+	series := s.fx.Series()
+	work := s.fx.Work()
+	second := s.fx.Book(nil, testutil.Volume(series, 2), testutil.EditionOf(work), func(b *models.Book) {
+		b.Format = models.FormatPaperback
+		b.Language = "en"
+	})
+	first := s.fx.Book(nil, testutil.Volume(series, 1), testutil.EditionOf(work), func(b *models.Book) {
+		b.Format = models.FormatHardcover
+		b.Language = "fr"
+	})
+	s.fx.Book(nil)
+
+	// Listing a series returns its books in volume order
+	var list page[models.Book]
+	expect(t, s.do(http.MethodGet, "/api/v1/books?series_id="+series.ID.String(), nil), http.StatusOK, &list)
+	if list.Pagination.Total != 2 || list.Data[0].ID != first.ID || list.Data[1].ID != second.ID {
+		t.Fatalf("unexpected series listing %+v", list.Data)
+	}
+	if list.Data[0].Series == nil || list.Data[0].Series.ID != series.ID {
+		t.Fatalf("expected series to be embedded, got %+v", list.Data[0].Series)
+	}
+
+	filters := []struct {
+		query string
+		want  uuid.UUID
+	}{
+		{"format=paperback", second.ID},
+		{"language=FR", first.ID},
+	}
+	for _, f := range filters {
+		expect(t, s.do(http.MethodGet, "/api/v1/books?"+f.query, nil), http.StatusOK, &list)
+		if list.Pagination.Total != 1 || list.Data[0].ID != f.want {
+			t.Fatalf("%s: unexpected books %+v", f.query, list.Data)
+		}
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/books?work_id=nope", nil), http.StatusBadRequest, nil)
+
+	var got envelope[models.Work]
+	expect(t, s.do(http.MethodGet, "/api/v1/works/"+work.ID.String(), nil), http.StatusOK, &got)
+	if len(got.Data.Editions) != 2 {
+		t.Fatalf("got %d editions, want 2", len(got.Data.Editions))
+	}
+
+	expect(t, s.do(http.MethodPut, "/api/v1/books/"+first.ID.String(), models.UpdateBookRequest{Language: "not a tag!"}), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodDelete, "/api/v1/series/"+series.ID.String(), nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodDelete, "/api/v1/works/"+work.ID.String(), nil), http.StatusBadRequest, nil)
+}
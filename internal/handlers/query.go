@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// queryUUID parses the optional UUID query parameter name, returning
+// uuid.Nil when it is absent. On a malformed value it responds with 400 and
+// returns false.
+func queryUUID(c *gin.Context, name, what string) (uuid.UUID, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return uuid.Nil, true
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + what + " ID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// requestScheme returns the scheme clients reached the server with, as
+// reported by a proxy in X-Forwarded-Proto.
+func requestScheme(c *gin.Context) string {
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		return proto
+	}
+	if c.Request.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
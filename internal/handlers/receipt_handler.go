@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"library-management-go/internal/receipt"
+	"library-management-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReceiptHandler struct {
+	receiptService *services.ReceiptService
+}
+
+func NewReceiptHandler(receiptService *services.ReceiptService) *ReceiptHandler {
+	return &ReceiptHandler{receiptService: receiptService}
+}
+
+// GetReceipt returns the receipt for a loan's checkout, renewal or return,
+// chosen by the kind parameter and by default its latest transaction, as
+// JSON or, with the format parameter, as text, html or pdf. Text receipts
+// are width columns wide.
+func (h *ReceiptHandler) GetReceipt(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrowing ID"})
+		return
+	}
+
+	width := receipt.DefaultWidth
+	if w := c.Query("width"); w != "" {
+		n, err := strconv.Atoi(w)
+		if err != nil || n < receipt.MinWidth || n > receipt.MaxWidth {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "width must be between 24 and 80"})
+			return
+		}
+		width = n
+	}
+
+	format := c.DefaultQuery("format", "json")
+	switch format {
+	case "json", "text", "html", "pdf":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json, text, html or pdf"})
+		return
+	}
+
+	r, err := h.receiptService.WithContext(c.Request.Context()).GetReceipt(id, c.Query("kind"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	switch format {
+	case "json":
+		c.JSON(http.StatusOK, gin.H{"data": r})
+	case "text":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", receipt.Text(r, width))
+	case "html":
+		data, err := receipt.HTML(r)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", data)
+	case "pdf":
+		data, err := receipt.PDF(r)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		// Shown in the browser, ready to print, rather than downloaded
+		c.Header("Content-Disposition", `inline; filename="receipt-`+r.Kind+`-`+r.BorrowingID.String()+`.pdf"`)
+		c.Data(http.StatusOK, "application/pdf", data)
+	}
+}
+
+// EmailReceipt emails the receipt chosen by the kind parameter to the
+// borrower and returns it.
+func (h *ReceiptHandler) EmailReceipt(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid borrowing ID"})
+		return
+	}
+
+	r, err := h.receiptService.WithContext(c.Request.Context()).EmailReceipt(id, c.Query("kind"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": r})
+}
@@ -0,0 +1,80 @@
+package handlers_test
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"library-management-go/internal/config"
+	"library-management-go/internal/mail/mailtest"
+	"library-management-go/internal/models"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestReceiptHandlerGetReceipt(t *testing.T) {
+	s := newServer(t)
+	loan := s.fx.Borrowing(nil, nil, testutil.Returned)
+	path := "/api/v1/borrowings/" + loan.ID.String() + "/receipt"
+
+	var r envelope[models.Receipt]
+	expect(t, s.do(http.MethodGet, path, nil), http.StatusOK, &r)
+	if r.Data.Kind != models.ReceiptReturn || r.Data.Item.Title != loan.Book.Title {
+		t.Fatalf("unexpected receipt %+v", r.Data)
+	}
+
+	rec := s.do(http.MethodGet, path+"?kind=checkout&format=text&width=32", nil)
+	expect(t, rec, http.StatusOK, nil)
+	if rec.Header().Get("Content-Type") != "text/plain; charset=utf-8" || !strings.Contains(rec.Body.String(), "Checkout receipt") {
+		t.Fatalf("unexpected text receipt %q", rec.Body.String())
+	}
+
+	rec = s.do(http.MethodGet, path+"?format=html", nil)
+	expect(t, rec, http.StatusOK, nil)
+	if !strings.HasPrefix(rec.Body.String(), "<!DOCTYPE html>") {
+		t.Fatalf("unexpected HTML receipt %q", rec.Body.String())
+	}
+
+	rec = s.do(http.MethodGet, path+"?format=pdf", nil)
+	expect(t, rec, http.StatusOK, nil)
+	if rec.Header().Get("Content-Type") != "application/pdf" || !bytes.HasPrefix(rec.Body.Bytes(), []byte("%PDF-")) {
+		t.Fatalf("expected a PDF, got %q", rec.Header().Get("Content-Type"))
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "inline") {
+		t.Fatalf("Content-Disposition = %q", cd)
+	}
+
+	expect(t, s.do(http.MethodGet, path+"?format=docx", nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodGet, path+"?format=text&width=10", nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodGet, path+"?kind=refund", nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodGet, path+"?kind=renewal", nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowings/"+uuid.NewString()+"/receipt", nil), http.StatusNotFound, nil)
+}
+
+func TestReceiptHandlerEmailReceipt(t *testing.T) {
+	smtp := mailtest.NewServer(t)
+	s := newServerWithConfig(t, func(cfg *config.Config) {
+		cfg.SMTPHost = smtp.Host()
+		cfg.SMTPPort = smtp.Port()
+		cfg.MailFrom = "desk@library.example"
+	})
+	borrower := s.fx.Borrower(func(b *models.Borrower) { b.Email = "reader@example.com" })
+	loan := s.fx.Borrowing(nil, borrower)
+
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/"+loan.ID.String()+"/receipt/email", nil), http.StatusOK, nil)
+	messages := smtp.Messages()
+	if len(messages) != 1 || messages[0].To[0] != "reader@example.com" {
+		t.Fatalf("unexpected messages %+v", messages)
+	}
+	msg, err := messages[0].Parse()
+	if err != nil || msg.Header.Get("Subject") != "Checkout receipt" {
+		t.Fatalf("unexpected message %v, %v", msg, err)
+	}
+
+	// Without an SMTP server receipts cannot be emailed
+	unconfigured := newServer(t)
+	loan = unconfigured.fx.Borrowing(nil, nil)
+	expect(t, unconfigured.do(http.MethodPost, "/api/v1/borrowings/"+loan.ID.String()+"/receipt/email", nil), http.StatusBadRequest, nil)
+}
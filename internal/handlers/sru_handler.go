@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+	"library-management-go/internal/sru"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SRUHandler answers SRU explain and searchRetrieve requests, see package
+// sru.
+type SRUHandler struct {
+	bookService   *services.BookService
+	tenantService *services.TenantService
+}
+
+func NewSRUHandler(bookService *services.BookService, tenantService *services.TenantService) *SRUHandler {
+	return &SRUHandler{
+		bookService:   bookService,
+		tenantService: tenantService,
+	}
+}
+
+// SRU answers a request given as query parameters or, as SRU allows, a
+// form-encoded POST. Failures are reported as SRU diagnostics.
+func (h *SRUHandler) SRU(c *gin.Context) {
+	if err := c.Request.ParseForm(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid form data"})
+		return
+	}
+
+	req, err := sru.ParseRequest(c.Request.Form)
+	if err == nil {
+		if req.Operation == sru.OpExplain {
+			err = h.explain(c, req)
+		} else {
+			err = h.searchRetrieve(c, req)
+		}
+	}
+	if err == nil {
+		return
+	}
+
+	var diagnostic *sru.Diagnostic
+	if !errors.As(err, &diagnostic) {
+		diagnostic = sru.NewDiagnostic(sru.DiagGeneral, "")
+	}
+	data, err := sru.Failure(req, diagnostic)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, sru.ContentType, data)
+}
+
+// explain describes the server at the address the request was made to.
+func (h *SRUHandler) explain(c *gin.Context, req *sru.Request) error {
+	title, err := catalogTitle(c, h.tenantService)
+	if err != nil {
+		return err
+	}
+
+	scheme := requestScheme(c)
+	host, port, err := net.SplitHostPort(c.Request.Host)
+	if err != nil {
+		host, port = c.Request.Host, "80"
+		if scheme == "https" {
+			port = "443"
+		}
+	}
+	portNumber, _ := strconv.Atoi(port)
+
+	data, err := sru.Explain(req, sru.Server{
+		Host:     host,
+		Port:     portNumber,
+		Database: strings.TrimPrefix(c.Request.URL.Path, "/"),
+		Title:    title,
+	})
+	if err != nil {
+		return err
+	}
+	c.Data(http.StatusOK, sru.ContentType, data)
+	return nil
+}
+
+// searchRetrieve runs the request's CQL query, newest books first so that
+// result positions stay put between requests.
+func (h *SRUHandler) searchRetrieve(c *gin.Context, req *sru.Request) error {
+	query, err := sru.ParseCQL(req.Query)
+	if err != nil {
+		return err
+	}
+
+	filter := models.BookFilter{Query: query, NewestFirst: true}
+	books, total, err := h.bookService.WithContext(c.Request.Context()).FindBooksFrom(filter, req.StartRecord-1, req.MaximumRecords)
+	if err != nil {
+		return err
+	}
+	data, err := sru.SearchRetrieve(req, books, total)
+	if err != nil {
+		return err
+	}
+	c.Data(http.StatusOK, sru.ContentType, data)
+	return nil
+}
@@ -0,0 +1,155 @@
+package handlers_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/sru"
+)
+
+type sruResponse struct {
+	XMLName            xml.Name
+	NumberOfRecords    int64 `xml:"numberOfRecords"`
+	NextRecordPosition int   `xml:"nextRecordPosition"`
+	Records            []struct {
+		Title    string `xml:"recordData>dc>title"`
+		MARC     string `xml:",innerxml"`
+		Position int    `xml:"recordPosition"`
+	} `xml:"records>record"`
+	Diagnostics []struct {
+		URI string `xml:"uri"`
+	} `xml:"diagnostics>diagnostic"`
+}
+
+// searchRetrieve runs an SRU 1.2 search for query with the extra params.
+func (s *server) searchRetrieve(t *testing.T, query, params string) sruResponse {
+	t.Helper()
+
+	w := s.do(http.MethodGet, "/api/v1/sru?version=1.2&operation=searchRetrieve&query="+url.QueryEscape(query)+params, nil)
+	return parseSRU(t, w)
+}
+
+func parseSRU(t *testing.T, w *httptest.ResponseRecorder) sruResponse {
+	t.Helper()
+
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != sru.ContentType {
+		t.Fatalf("status %d, content type %q: %s", w.Code, w.Header().Get("Content-Type"), w.Body.String())
+	}
+	var resp sruResponse
+	if err := xml.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("parse response: %v\n%s", err, w.Body.String())
+	}
+	return resp
+}
+
+func TestSRUHandlerSearchRetrieve(t *testing.T) {
+	s := newServer(t)
+	herbert := s.fx.Author(func(a *models.Author) { a.Name = "Frank Herbert" })
+	leGuin := s.fx.Author(func(a *models.Author) { a.Name = "Ursula K. Le Guin" })
+	dune := s.fx.Book(herbert, func(b *models.Book) { b.Title = "Dune"; b.ISBN = "978-0-441-17271-9" })
+	s.fx.Book(herbert, func(b *models.Book) { b.Title = "Dune Messiah" })
+	s.fx.Book(leGuin, func(b *models.Book) { b.Title = "The Dispossessed" })
+	fiction := s.fx.Subject(nil, func(sub *models.Subject) { sub.Name = "Science fiction" })
+	s.fx.Tag(dune, fiction)
+
+	tests := []struct {
+		query string
+		want  int64
+	}{
+		{"dune", 2},
+		{"dc.title == dune", 1},
+		{`dc.creator = "le guin"`, 1},
+		{"bath.isbn = 9780441172719", 1},
+		{`dc.subject = "science fiction"`, 1},
+		{"dc.creator = herbert not dc.title = messiah", 1},
+		{"dc.title = dune or dc.creator = guin", 3},
+		{"title any \"messiah dispossessed\"", 2},
+		{"cql.allRecords = 1", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			resp := s.searchRetrieve(t, tt.query, "")
+			if len(resp.Diagnostics) != 0 || resp.NumberOfRecords != tt.want {
+				t.Fatalf("numberOfRecords = %d, diagnostics %+v", resp.NumberOfRecords, resp.Diagnostics)
+			}
+		})
+	}
+
+	// Records are paged by position
+	first := s.searchRetrieve(t, "cql.allRecords = 1", "&maximumRecords=2")
+	if len(first.Records) != 2 || first.NextRecordPosition != 3 {
+		t.Fatalf("%d records, next %d", len(first.Records), first.NextRecordPosition)
+	}
+	last := s.searchRetrieve(t, "cql.allRecords = 1", "&maximumRecords=2&startRecord=3")
+	if len(last.Records) != 1 || last.Records[0].Position != 3 || last.NextRecordPosition != 0 {
+		t.Fatalf("unexpected last page %+v", last)
+	}
+	for _, r := range first.Records {
+		if r.Title == last.Records[0].Title {
+			t.Fatalf("%q is on both pages", r.Title)
+		}
+	}
+	count := s.searchRetrieve(t, "dune", "&maximumRecords=0")
+	if count.NumberOfRecords != 2 || len(count.Records) != 0 {
+		t.Fatalf("count only: %d records of %d", len(count.Records), count.NumberOfRecords)
+	}
+
+	marc := s.searchRetrieve(t, "dc.title == dune", "&recordSchema=marcxml")
+	if len(marc.Records) != 1 || !strings.Contains(marc.Records[0].MARC, `<subfield code="a">Frank Herbert</subfield>`) {
+		t.Fatalf("unexpected MARCXML records %+v", marc.Records)
+	}
+
+	// Errors are diagnostics, not HTTP errors
+	for query, uri := range map[string]string{
+		"dc.date = 1965":   "info:srw/diagnostic/1/16",
+		"dune and":         "info:srw/diagnostic/1/10",
+		"dune sortby date": "info:srw/diagnostic/1/80",
+	} {
+		resp := s.searchRetrieve(t, query, "")
+		if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].URI != uri {
+			t.Fatalf("%s: diagnostics %+v, want %s", query, resp.Diagnostics, uri)
+		}
+	}
+	resp := s.searchRetrieve(t, "dune", "&startRecord=10")
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].URI != "info:srw/diagnostic/1/61" {
+		t.Fatalf("diagnostics %+v", resp.Diagnostics)
+	}
+}
+
+func TestSRUHandlerVersions(t *testing.T) {
+	s := newServer(t)
+	s.fx.Book(nil, func(b *models.Book) { b.Title = "Dune" })
+
+	// SRU 2.0 needs no operation
+	resp := parseSRU(t, s.do(http.MethodGet, "/api/v1/sru?query=dune", nil))
+	if resp.XMLName.Space != "http://docs.oasis-open.org/ns/search-ws/sruResponse" || resp.NumberOfRecords != 1 {
+		t.Fatalf("unexpected 2.0 response %+v", resp)
+	}
+
+	// Requests may be posted as forms
+	form := url.Values{"version": {"1.2"}, "operation": {"searchRetrieve"}, "query": {"title=dune"}}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/sru", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp = parseSRU(t, s.serve(req))
+	if resp.XMLName.Space != "http://www.loc.gov/zing/srw/" || resp.NumberOfRecords != 1 {
+		t.Fatalf("unexpected posted response %+v", resp)
+	}
+
+	w := s.do(http.MethodGet, "/api/v1/sru?version=1.2&operation=explain", nil)
+	body := w.Body.String()
+	if w.Code != http.StatusOK || !strings.Contains(body, "<explainResponse") ||
+		!strings.Contains(body, "<host>example.com</host>") || !strings.Contains(body, "<port>80</port>") ||
+		!strings.Contains(body, "<database>api/v1/sru</database>") || !strings.Contains(body, "Library catalog") {
+		t.Fatalf("unexpected explain response:\n%s", body)
+	}
+
+	resp = parseSRU(t, s.do(http.MethodGet, "/api/v1/sru?version=1.2&operation=scan", nil))
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].URI != "info:srw/diagnostic/1/4" {
+		t.Fatalf("diagnostics %+v", resp.Diagnostics)
+	}
+}
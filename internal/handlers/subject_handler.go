@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"library-management-go/internal/marc"
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SubjectHandler struct {
+	subjectService *services.SubjectService
+}
+
+func NewSubjectHandler(subjectService *services.SubjectService) *SubjectHandler {
+	return &SubjectHandler{subjectService: subjectService}
+}
+
+func (h *SubjectHandler) CreateSubject(c *gin.Context) {
+	var req models.CreateSubjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	subject, err := h.subjectService.WithContext(c.Request.Context()).CreateSubject(&req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": subject})
+}
+
+func (h *SubjectHandler) GetSubject(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subject ID"})
+		return
+	}
+
+	subject, err := h.subjectService.WithContext(c.Request.Context()).GetSubject(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": subject})
+}
+
+func (h *SubjectHandler) GetSubjects(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	page, limit = services.NormalizePagination(page, limit)
+
+	filter := models.SubjectFilter{Search: c.Query("search"), TopLevel: c.Query("top_level") == "true"}
+	var ok bool
+	if filter.ParentID, ok = queryUUID(c, "parent_id", "parent subject"); !ok {
+		return
+	}
+
+	subjects, total, err := h.subjectService.WithContext(c.Request.Context()).FindSubjects(filter, page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": subjects,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+func (h *SubjectHandler) UpdateSubject(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subject ID"})
+		return
+	}
+
+	var req models.UpdateSubjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	subject, err := h.subjectService.WithContext(c.Request.Context()).UpdateSubject(id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": subject})
+}
+
+func (h *SubjectHandler) DeleteSubject(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subject ID"})
+		return
+	}
+
+	err = h.subjectService.WithContext(c.Request.Context()).DeleteSubject(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "subject deleted successfully"})
+}
+
+// marcXMLTypes are the content types an import is read as MARCXML for;
+// anything else is read as binary MARC (ISO 2709).
+var marcXMLTypes = map[string]bool{
+	"application/marcxml+xml": true,
+	"application/xml":         true,
+	"text/xml":                true,
+}
+
+// ImportMARC adds the subject headings of the MARC records in the request
+// body to the vocabulary and tags the matching books with them.
+func (h *SubjectHandler) ImportMARC(c *gin.Context) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+
+	var records []*marc.Record
+	var err error
+	if marcXMLTypes[mediaType] {
+		records, err = marc.ReadXML(c.Request.Body)
+	} else {
+		records, err = marc.ReadAll(c.Request.Body)
+	}
+	if err != nil {
+		if errors.Is(err, marc.ErrInvalidRecord) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondError(c, err)
+		return
+	}
+
+	result, err := h.subjectService.WithContext(c.Request.Context()).ImportMARC(records)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
@@ -0,0 +1,117 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestSubjectHandlerCRUD(t *testing.T) {
+	s := newServer(t)
+	science := s.fx.Subject(nil, func(sub *models.Subject) { sub.Name = "Science" })
+
+	tests := []struct {
+		name   string
+		body   interface{}
+		status int
+	}{
+		{"valid", models.CreateSubjectRequest{Name: "Physics", ParentID: science.ID}, http.StatusCreated},
+		{"missing name", models.CreateSubjectRequest{ParentID: science.ID}, http.StatusBadRequest},
+		{"unknown kind", models.CreateSubjectRequest{Name: "Odd", Kind: "place"}, http.StatusBadRequest},
+		{"duplicate name", models.CreateSubjectRequest{Name: "science"}, http.StatusConflict},
+		{"unknown parent", models.CreateSubjectRequest{Name: "Orphan", ParentID: uuid.New()}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect(t, s.do(http.MethodPost, "/api/v1/subjects", tt.body), tt.status, nil)
+		})
+	}
+
+	var list page[models.Subject]
+	expect(t, s.do(http.MethodGet, "/api/v1/subjects?top_level=true", nil), http.StatusOK, &list)
+	if list.Pagination.Total != 1 {
+		t.Fatalf("top-level total = %d, want 1", list.Pagination.Total)
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/subjects?parent_id="+science.ID.String(), nil), http.StatusOK, &list)
+	if list.Pagination.Total != 1 || list.Data[0].Name != "Physics" {
+		t.Fatalf("unexpected children %+v", list.Data)
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/subjects?parent_id=nope", nil), http.StatusBadRequest, nil)
+
+	id := science.ID.String()
+	var got envelope[models.Subject]
+	expect(t, s.do(http.MethodGet, "/api/v1/subjects/"+id, nil), http.StatusOK, &got)
+	if len(got.Data.Children) != 1 {
+		t.Fatalf("expected narrower subjects, got %+v", got.Data.Children)
+	}
+
+	// Moving a subject under its own child is rejected
+	childID := got.Data.Children[0].ID
+	expect(t, s.do(http.MethodPut, "/api/v1/subjects/"+id, models.UpdateSubjectRequest{ParentID: &childID}), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodPut, "/api/v1/subjects/"+id, models.UpdateSubjectRequest{Name: "Sciences"}), http.StatusOK, &got)
+	if got.Data.Name != "Sciences" {
+		t.Fatalf("unexpected subject %+v", got.Data)
+	}
+
+	expect(t, s.do(http.MethodDelete, "/api/v1/subjects/"+id, nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodDelete, "/api/v1/subjects/"+childID.String(), nil), http.StatusOK, nil)
+	expect(t, s.do(http.MethodDelete, "/api/v1/subjects/"+id, nil), http.StatusOK, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/subjects/"+id, nil), http.StatusNotFound, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/subjects/nope", nil), http.StatusBadRequest, nil)
+}
+
+func TestSubjectHandlerBooks(t *testing.T) {
+	s := newServer(t)
+	science := s.fx.Subject(nil)
+	physics := s.fx.Subject(science)
+	book := s.fx.Book(nil)
+	s.fx.Book(nil)
+
+	// Tag through the book endpoint, then filter by the broader subject
+	var updated envelope[models.Book]
+	expect(t, s.do(http.MethodPut, "/api/v1/books/"+book.ID.String(), models.UpdateBookRequest{SubjectIDs: []uuid.UUID{physics.ID}}),
+		http.StatusOK, &updated)
+	if len(updated.Data.Subjects) != 1 || updated.Data.Subjects[0].ID != physics.ID {
+		t.Fatalf("unexpected subjects %+v", updated.Data.Subjects)
+	}
+	expect(t, s.do(http.MethodPut, "/api/v1/books/"+book.ID.String(), models.UpdateBookRequest{SubjectIDs: []uuid.UUID{uuid.New()}}),
+		http.StatusNotFound, nil)
+
+	var list page[models.Book]
+	expect(t, s.do(http.MethodGet, "/api/v1/books?subject_id="+science.ID.String(), nil), http.StatusOK, &list)
+	if list.Pagination.Total != 1 || list.Data[0].ID != book.ID {
+		t.Fatalf("unexpected books %+v", list.Data)
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/books?subject_id=nope", nil), http.StatusBadRequest, nil)
+
+	expect(t, s.do(http.MethodDelete, "/api/v1/subjects/"+physics.ID.String(), nil), http.StatusBadRequest, nil)
+}
+
+func TestSubjectHandlerImport(t *testing.T) {
+	s := newServer(t)
+	book := s.fx.Book(nil, func(b *models.Book) { b.ISBN = "9780141439518" })
+
+	doc := `<record xmlns="http://www.loc.gov/MARC21/slim">
+  <datafield tag="020" ind1=" " ind2=" "><subfield code="a">9780141439518</subfield></datafield>
+  <datafield tag="650" ind1=" " ind2="0"><subfield code="a">Courtship</subfield><subfield code="v">Fiction.</subfield></datafield>
+</record>`
+
+	var result envelope[models.SubjectImportResult]
+	expect(t, s.do(http.MethodPost, "/api/v1/subjects/import", doc, "Content-Type", "application/marcxml+xml"), http.StatusOK, &result)
+	want := models.SubjectImportResult{Records: 1, Headings: 1, SubjectsCreated: 2, BooksTagged: 1}
+	if result.Data != want {
+		t.Fatalf("result = %+v, want %+v", result.Data, want)
+	}
+
+	var got envelope[models.Book]
+	expect(t, s.do(http.MethodGet, "/api/v1/books/"+book.ID.String(), nil), http.StatusOK, &got)
+	if len(got.Data.Subjects) != 1 || got.Data.Subjects[0].Name != "Fiction" {
+		t.Fatalf("unexpected subjects %+v", got.Data.Subjects)
+	}
+
+	expect(t, s.do(http.MethodPost, "/api/v1/subjects/import", "not marc", "Content-Type", "application/marc"), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/subjects/import", "<record>", "Content-Type", "text/xml"), http.StatusBadRequest, nil)
+}
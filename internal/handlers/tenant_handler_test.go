@@ -0,0 +1,116 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"library-management-go/internal/config"
+	"library-management-go/internal/models"
+)
+
+const adminToken = "admin-secret"
+
+func newMultiTenantServer(t *testing.T) *server {
+	t.Helper()
+	return newServerWithConfig(t, func(cfg *config.Config) {
+		cfg.MultiTenant = true
+		cfg.TenantDomain = "library.example.org"
+		cfg.TenantAdminToken = adminToken
+		cfg.JWTSecret = "jwt-secret"
+	})
+}
+
+// provision creates a tenant through the provisioning API.
+func (s *server) provision(slug string) models.Tenant {
+	s.t.Helper()
+
+	var created envelope[models.Tenant]
+	expect(s.t, s.do(http.MethodPost, "/api/v1/tenants",
+		map[string]interface{}{"slug": slug, "name": "Library " + slug}, "X-Admin-Token", adminToken),
+		http.StatusCreated, &created)
+	return created.Data
+}
+
+func TestTenantProvisioningAuth(t *testing.T) {
+	body := map[string]interface{}{"slug": "north", "name": "North"}
+
+	disabled := newServer(t)
+	expect(t, disabled.do(http.MethodPost, "/api/v1/tenants", body, "X-Admin-Token", ""), http.StatusForbidden, nil)
+
+	s := newMultiTenantServer(t)
+	expect(t, s.do(http.MethodPost, "/api/v1/tenants", body), http.StatusUnauthorized, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/tenants", body, "X-Admin-Token", "wrong"), http.StatusUnauthorized, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/tenants", body, "X-Admin-Token", adminToken), http.StatusCreated, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/tenants", body, "X-Admin-Token", adminToken), http.StatusConflict, nil)
+	expect(t, s.do(http.MethodPost, "/api/v1/tenants",
+		map[string]interface{}{"slug": "Not Valid", "name": "Bad"}, "X-Admin-Token", adminToken), http.StatusBadRequest, nil)
+
+	var list page[models.Tenant]
+	expect(t, s.do(http.MethodGet, "/api/v1/tenants", nil, "X-Admin-Token", adminToken), http.StatusOK, &list)
+	if list.Pagination.Total != 1 {
+		t.Fatalf("total = %d, want 1", list.Pagination.Total)
+	}
+}
+
+func TestTenantResolution(t *testing.T) {
+	s := newMultiTenantServer(t)
+	s.provision("north")
+
+	tests := []struct {
+		name    string
+		host    string
+		headers []string
+		want    int
+	}{
+		{"no tenant", "localhost", nil, http.StatusBadRequest},
+		{"unknown tenant", "localhost", []string{"X-Tenant", "south"}, http.StatusNotFound},
+		{"header", "localhost", []string{"X-Tenant", "north"}, http.StatusOK},
+		{"subdomain", "north.library.example.org", nil, http.StatusOK},
+		{"invalid token", "localhost", []string{"Authorization", "Bearer a.b.c"}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := s.request(http.MethodGet, "/api/v1/borrowers", nil, tt.headers...)
+			req.Host = tt.host
+			expect(t, s.serve(req), tt.want, nil)
+		})
+	}
+
+	// Health checks are not scoped to a tenant
+	expect(t, s.do(http.MethodGet, "/api/v1/health", nil), http.StatusOK, nil)
+}
+
+func TestTenantIsolation(t *testing.T) {
+	s := newMultiTenantServer(t)
+	s.provision("north")
+	east := s.provision("east")
+
+	borrower := map[string]interface{}{"name": "Snufkin", "email": "snufkin@example.com"}
+	var created envelope[models.Borrower]
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowers", borrower, "X-Tenant", "north"), http.StatusCreated, &created)
+	// The same email can be registered by another library
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowers", borrower, "X-Tenant", "east"), http.StatusCreated, nil)
+
+	var list page[models.Borrower]
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowers", nil, "X-Tenant", "east"), http.StatusOK, &list)
+	if list.Pagination.Total != 1 {
+		t.Fatalf("east sees %d borrowers, want 1", list.Pagination.Total)
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowers/"+created.Data.ID.String(), nil, "X-Tenant", "east"),
+		http.StatusNotFound, nil)
+	expect(t, s.do(http.MethodDelete, "/api/v1/borrowers/"+created.Data.ID.String(), nil, "X-Tenant", "east"),
+		http.StatusNotFound, nil)
+
+	// Each library reads its own configuration
+	var current envelope[models.Tenant]
+	expect(t, s.do(http.MethodGet, "/api/v1/tenant", nil, "X-Tenant", "east"), http.StatusOK, &current)
+	if current.Data.ID != east.ID || current.Data.Settings.MaxActiveLoans != 5 {
+		t.Fatalf("unexpected current tenant %+v", current.Data)
+	}
+
+	// A suspended library is locked out
+	expect(t, s.do(http.MethodPut, "/api/v1/tenants/"+east.ID.String(),
+		map[string]interface{}{"active": false}, "X-Admin-Token", adminToken), http.StatusOK, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowers", nil, "X-Tenant", "east"), http.StatusForbidden, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/borrowers", nil, "X-Tenant", "north"), http.StatusOK, nil)
+}
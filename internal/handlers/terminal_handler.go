@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TerminalHandler manages the credentials self-check terminals sign in to
+// the SIP2 server with.
+type TerminalHandler struct {
+	terminalService *services.TerminalService
+}
+
+func NewTerminalHandler(terminalService *services.TerminalService) *TerminalHandler {
+	return &TerminalHandler{terminalService: terminalService}
+}
+
+func (h *TerminalHandler) CreateTerminal(c *gin.Context) {
+	var req models.CreateSIPTerminalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	terminal, err := h.terminalService.WithContext(c.Request.Context()).CreateTerminal(&req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": terminal})
+}
+
+func (h *TerminalHandler) GetTerminal(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid terminal ID"})
+		return
+	}
+
+	terminal, err := h.terminalService.WithContext(c.Request.Context()).GetTerminal(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": terminal})
+}
+
+func (h *TerminalHandler) GetAllTerminals(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	page, limit = services.NormalizePagination(page, limit)
+
+	terminals, total, err := h.terminalService.WithContext(c.Request.Context()).GetTerminals(page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": terminals,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+func (h *TerminalHandler) UpdateTerminal(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid terminal ID"})
+		return
+	}
+
+	var req models.UpdateSIPTerminalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	terminal, err := h.terminalService.WithContext(c.Request.Context()).UpdateTerminal(id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": terminal})
+}
+
+func (h *TerminalHandler) DeleteTerminal(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid terminal ID"})
+		return
+	}
+
+	err = h.terminalService.WithContext(c.Request.Context()).DeleteTerminal(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "terminal deleted successfully"})
+}
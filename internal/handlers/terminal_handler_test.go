@@ -0,0 +1,63 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestTerminalHandlerCRUD(t *testing.T) {
+	s := newServer(t)
+	branch := s.fx.Branch()
+
+	var created envelope[models.SIPTerminal]
+	w := s.do(http.MethodPost, "/api/v1/sip-terminals", models.CreateSIPTerminalRequest{
+		Name: "Lobby kiosk", Login: "lobby", Password: "password", BranchID: branch.ID,
+	})
+	expect(t, w, http.StatusCreated, &created)
+	if created.Data.BranchID == nil || *created.Data.BranchID != branch.ID || !created.Data.Active {
+		t.Fatalf("unexpected terminal %+v", created.Data)
+	}
+	// The password hash is never returned
+	if strings.Contains(w.Body.String(), "password") {
+		t.Fatalf("response exposes the password: %s", w.Body.String())
+	}
+
+	tests := []struct {
+		name   string
+		body   models.CreateSIPTerminalRequest
+		status int
+	}{
+		{"short password", models.CreateSIPTerminalRequest{Name: "Gate", Login: "gate", Password: "short"}, http.StatusBadRequest},
+		{"duplicate login", models.CreateSIPTerminalRequest{Name: "Gate", Login: "lobby", Password: "password"}, http.StatusConflict},
+		{"unknown branch", models.CreateSIPTerminalRequest{Name: "Gate", Login: "gate", Password: "password", BranchID: uuid.New()}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect(t, s.do(http.MethodPost, "/api/v1/sip-terminals", tt.body), tt.status, nil)
+		})
+	}
+
+	var list page[models.SIPTerminal]
+	expect(t, s.do(http.MethodGet, "/api/v1/sip-terminals", nil), http.StatusOK, &list)
+	if list.Pagination.Total != 1 {
+		t.Fatalf("total = %d, want 1", list.Pagination.Total)
+	}
+
+	id := created.Data.ID.String()
+	inactive := false
+	var got envelope[models.SIPTerminal]
+	expect(t, s.do(http.MethodPut, "/api/v1/sip-terminals/"+id, models.UpdateSIPTerminalRequest{Active: &inactive}), http.StatusOK, &got)
+	if got.Data.Active || got.Data.Login != "lobby" {
+		t.Fatalf("unexpected terminal %+v", got.Data)
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/sip-terminals/"+uuid.NewString(), nil), http.StatusNotFound, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/sip-terminals/nope", nil), http.StatusBadRequest, nil)
+
+	expect(t, s.do(http.MethodDelete, "/api/v1/sip-terminals/"+id, nil), http.StatusOK, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/sip-terminals/"+id, nil), http.StatusNotFound, nil)
+}
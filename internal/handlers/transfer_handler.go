@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TransferHandler struct {
+	transferService *services.TransferService
+}
+
+func NewTransferHandler(transferService *services.TransferService) *TransferHandler {
+	return &TransferHandler{transferService: transferService}
+}
+
+func (h *TransferHandler) GetTransfers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	page, limit = services.NormalizePagination(page, limit)
+
+	filter := models.TransferFilter{Status: c.Query("status")}
+	switch filter.Status {
+	case "", models.TransferInTransit, models.TransferReceived:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be in_transit or received"})
+		return
+	}
+
+	var ok bool
+	if filter.FromBranchID, ok = queryUUID(c, "from_branch_id", "from branch"); !ok {
+		return
+	}
+	if filter.ToBranchID, ok = queryUUID(c, "to_branch_id", "to branch"); !ok {
+		return
+	}
+
+	transfers, total, err := h.transferService.WithContext(c.Request.Context()).GetTransfers(filter, page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": transfers,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+func (h *TransferHandler) GetTransfer(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transfer ID"})
+		return
+	}
+
+	transfer, err := h.transferService.WithContext(c.Request.Context()).GetTransfer(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": transfer})
+}
+
+func (h *TransferHandler) ReceiveTransfer(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transfer ID"})
+		return
+	}
+
+	transfer, err := h.transferService.WithContext(c.Request.Context()).ReceiveTransfer(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": transfer})
+}
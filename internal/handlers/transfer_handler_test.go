@@ -0,0 +1,73 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestTransferHandlerAwayReturn(t *testing.T) {
+	s := newServer(t)
+	home := s.fx.Location(nil)
+	away := s.fx.Branch()
+	loan := s.fx.Borrowing(s.fx.Book(nil, testutil.ShelvedAt(home)), nil)
+
+	// Checking the item in away from home sends it back in transit
+	var returned envelope[models.Borrowing]
+	expect(t, s.do(http.MethodPost, "/api/v1/borrowings/return",
+		models.ReturnBookRequest{BorrowingID: loan.ID, BranchID: away.ID}), http.StatusOK, &returned)
+	if returned.Data.ReturnBranchID == nil || *returned.Data.ReturnBranchID != away.ID {
+		t.Fatalf("return_branch_id = %v, want %v", returned.Data.ReturnBranchID, away.ID)
+	}
+
+	var book envelope[models.Book]
+	expect(t, s.do(http.MethodGet, "/api/v1/books/"+loan.BookID.String(), nil), http.StatusOK, &book)
+	if !book.Data.InTransit || book.Data.Available {
+		t.Fatalf("in_transit = %v, available = %v", book.Data.InTransit, book.Data.Available)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  int64
+	}{
+		{"all", "", 1},
+		{"to home", "?to_branch_id=" + home.BranchID.String(), 1},
+		{"from home", "?from_branch_id=" + home.BranchID.String(), 0},
+		{"in transit", "?status=in_transit", 1},
+		{"received", "?status=received", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var list page[models.Transfer]
+			expect(t, s.do(http.MethodGet, "/api/v1/transfers"+tt.query, nil), http.StatusOK, &list)
+			if list.Pagination.Total != tt.want {
+				t.Fatalf("total = %d, want %d", list.Pagination.Total, tt.want)
+			}
+		})
+	}
+	expect(t, s.do(http.MethodGet, "/api/v1/transfers?status=lost", nil), http.StatusBadRequest, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/transfers?to_branch_id=nope", nil), http.StatusBadRequest, nil)
+
+	var list page[models.Transfer]
+	expect(t, s.do(http.MethodGet, "/api/v1/transfers", nil), http.StatusOK, &list)
+	id := list.Data[0].ID.String()
+	expect(t, s.do(http.MethodGet, "/api/v1/transfers/"+id, nil), http.StatusOK, nil)
+	expect(t, s.do(http.MethodGet, "/api/v1/transfers/"+uuid.NewString(), nil), http.StatusNotFound, nil)
+
+	var received envelope[models.Transfer]
+	expect(t, s.do(http.MethodPost, "/api/v1/transfers/"+id+"/receive", nil), http.StatusOK, &received)
+	if received.Data.Status != models.TransferReceived {
+		t.Fatalf("status = %q", received.Data.Status)
+	}
+	expect(t, s.do(http.MethodPost, "/api/v1/transfers/"+id+"/receive", nil), http.StatusBadRequest, nil)
+
+	expect(t, s.do(http.MethodGet, "/api/v1/books/"+loan.BookID.String(), nil), http.StatusOK, &book)
+	if book.Data.InTransit || !book.Data.Available || book.Data.CurrentLocationID == nil || *book.Data.CurrentLocationID != home.ID {
+		t.Fatalf("expected book back on its home shelf, got %+v", book.Data)
+	}
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"library-management-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	trashService *services.TrashService
+}
+
+func NewTrashHandler(trashService *services.TrashService) *TrashHandler {
+	return &TrashHandler{trashService: trashService}
+}
+
+func (h *TrashHandler) PurgeExpired(c *gin.Context) {
+	purged, err := h.trashService.WithContext(c.Request.Context()).PurgeExpired()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": purged})
+}
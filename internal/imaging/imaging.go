@@ -0,0 +1,144 @@
+// Package imaging decodes uploaded images and scales them down into
+// thumbnails, using only the standard library's image packages.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"net/http"
+
+	// Register the decoders for the formats ContentType accepts
+	_ "image/gif"
+	_ "image/png"
+)
+
+// MaxPixels bounds the decoded size of an image, so a small file that
+// expands into a huge bitmap is rejected before it is decoded.
+const MaxPixels = 40_000_000
+
+var (
+	// ErrUnsupported reports data that is not a JPEG, PNG or GIF image.
+	ErrUnsupported = errors.New("image must be a JPEG, PNG or GIF")
+	// ErrTooLarge reports an image with more than MaxPixels pixels.
+	ErrTooLarge = errors.New("image has too many pixels")
+)
+
+// extensions maps the supported content types to their file extensions
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// ContentType sniffs the type of data from its leading bytes, ignoring any
+// type the client claimed, and returns ErrUnsupported unless it is a
+// format Decode reads.
+func ContentType(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	if _, ok := extensions[contentType]; !ok {
+		return "", ErrUnsupported
+	}
+	return contentType, nil
+}
+
+// Extension returns the file extension for a content type ContentType
+// accepts, including the dot.
+func Extension(contentType string) string {
+	return extensions[contentType]
+}
+
+// Decode decodes a JPEG, PNG or GIF image, checking its dimensions first.
+func Decode(data []byte) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// Fit scales img down, keeping its aspect ratio, so neither side is longer
+// than size. Each pixel of the result averages the block of source pixels
+// it covers, which keeps detail that sampling single pixels would alias.
+// Transparent areas are flattened onto white, ready to be saved as JPEG.
+func Fit(img image.Image, size int) *image.RGBA {
+	return scale(flatten(img), size)
+}
+
+// Thumbnails returns img fitted to each of sizes, as Fit does. The image is
+// flattened once for all of them, since the flattened copy is as large as
+// the image itself.
+func Thumbnails(img image.Image, sizes ...int) []*image.RGBA {
+	src := flatten(img)
+	thumbnails := make([]*image.RGBA, len(sizes))
+	for i, size := range sizes {
+		thumbnails[i] = scale(src, size)
+	}
+	return thumbnails
+}
+
+// scale does the work of Fit on an image flatten returned.
+func scale(src *image.RGBA, size int) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	if w <= size && h <= size {
+		return src
+	}
+
+	dw, dh := size, size
+	if w > h {
+		dh = max(1, h*size/w)
+	} else {
+		dw = max(1, w*size/h)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*h/dh, max((y+1)*h/dh, y*h/dh+1)
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*w/dw, max((x+1)*w/dw, x*w/dw+1)
+
+			var r, g, b, n int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					r += int(row[sx*4])
+					g += int(row[sx*4+1])
+					b += int(row[sx*4+2])
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+	return dst
+}
+
+// flatten draws img onto an opaque white canvas whose bounds start at the
+// origin.
+func flatten(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+	return dst
+}
+
+// EncodeJPEG encodes img as a JPEG for a thumbnail.
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,125 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"runtime"
+	"testing"
+)
+
+// encodePNG encodes a w×h image, left half red and right half blue.
+func encodePNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.NRGBA{R: 0xff, A: 0xff}
+			if x >= w/2 {
+				c = color.NRGBA{B: 0xff, A: 0xff}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestContentType(t *testing.T) {
+	contentType, err := ContentType(encodePNG(t, 2, 2))
+	if err != nil || contentType != "image/png" || Extension(contentType) != ".png" {
+		t.Fatalf("ContentType = %q, %v", contentType, err)
+	}
+
+	for _, data := range [][]byte{[]byte("%PDF-1.4 not an image"), []byte("<svg xmlns='http://www.w3.org/2000/svg'/>"), nil} {
+		if _, err := ContentType(data); !errors.Is(err, ErrUnsupported) {
+			t.Fatalf("ContentType(%q) = %v, want ErrUnsupported", data, err)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	img, err := Decode(encodePNG(t, 30, 20))
+	if err != nil || img.Bounds().Dx() != 30 {
+		t.Fatalf("Decode = %v, %v", img, err)
+	}
+
+	// A PNG header claiming a huge bitmap is rejected before decoding
+	huge := encodePNG(t, 1, 1)
+	copy(huge[16:24], []byte{0, 0, 0x4e, 0x20, 0, 0, 0x4e, 0x20}) // 20000×20000
+	binary.BigEndian.PutUint32(huge[29:33], crc32.ChecksumIEEE(huge[12:29]))
+	if _, err := Decode(huge); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("Decode(huge) = %v, want ErrTooLarge", err)
+	}
+
+	if _, err := Decode([]byte("\x89PNG\r\n\x1a\ntruncated")); err == nil {
+		t.Fatal("expected a truncated image to fail")
+	}
+}
+
+func TestFit(t *testing.T) {
+	img, err := Decode(encodePNG(t, 400, 600))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	thumb := Fit(img, 60)
+	if thumb.Bounds().Dx() != 40 || thumb.Bounds().Dy() != 60 {
+		t.Fatalf("thumbnail is %v, want 40×60", thumb.Bounds())
+	}
+	if r, _, b, _ := thumb.At(5, 30).RGBA(); r>>8 != 0xff || b != 0 {
+		t.Fatalf("left of thumbnail should stay red, got %v", thumb.At(5, 30))
+	}
+	if r, _, b, _ := thumb.At(35, 30).RGBA(); r != 0 || b>>8 != 0xff {
+		t.Fatalf("right of thumbnail should stay blue, got %v", thumb.At(35, 30))
+	}
+
+	if small := Fit(img, 1000); small.Bounds().Dx() != 400 {
+		t.Fatalf("an image that fits should keep its size, got %v", small.Bounds())
+	}
+
+	data, err := EncodeJPEG(thumb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType, _ := ContentType(data); contentType != "image/jpeg" {
+		t.Fatalf("thumbnail content type = %q", contentType)
+	}
+}
+
+func TestThumbnails(t *testing.T) {
+	img, err := Decode(encodePNG(t, 1000, 1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	thumbs := Thumbnails(img, 96, 240, 480)
+	runtime.ReadMemStats(&after)
+
+	for i, size := range []int{96, 240, 480} {
+		if want := Fit(img, size); thumbs[i].Bounds() != want.Bounds() || !bytes.Equal(thumbs[i].Pix, want.Pix) {
+			t.Fatalf("thumbnail %d differs from Fit(img, %d)", i, size)
+		}
+	}
+	// The image is flattened once, not once per size
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 2*1000*1000*4 {
+		t.Fatalf("allocated %d bytes for a 1000×1000 image", allocated)
+	}
+}
+
+func TestFitFlattensTransparency(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(10, 10, 20, 20))
+	thumb := Fit(img, 5)
+	if c := thumb.RGBAAt(2, 2); c != (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+		t.Fatalf("transparent pixel = %v, want white", c)
+	}
+}
@@ -0,0 +1,230 @@
+// Package labels lays out item and library card labels on sheets of
+// adhesive labels, such as Avery's, and renders them as PDF for printing.
+package labels
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"library-management-go/internal/barcode"
+	"library-management-go/internal/pdf"
+)
+
+// Layout describes a sheet of labels arranged in a grid. Lengths are in
+// millimetres; the margins are from the top left corner of the page to
+// that of the first label, and the pitches from one label to the start of
+// the next.
+type Layout struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	PageWidth   float64 `json:"page_width"`
+	PageHeight  float64 `json:"page_height"`
+	Columns     int     `json:"columns"`
+	Rows        int     `json:"rows"`
+	LabelWidth  float64 `json:"label_width"`
+	LabelHeight float64 `json:"label_height"`
+	TopMargin   float64 `json:"top_margin"`
+	LeftMargin  float64 `json:"left_margin"`
+	ColumnPitch float64 `json:"column_pitch"`
+	RowPitch    float64 `json:"row_pitch"`
+}
+
+const (
+	letterWidth  = 215.9
+	letterHeight = 279.4
+	a4Width      = 210
+	a4Height     = 297
+)
+
+// layouts are the label sheets available by name.
+var layouts = map[string]Layout{
+	"avery-5160": {Description: "Letter, 30 address labels, 2 5/8 x 1 in",
+		PageWidth: letterWidth, PageHeight: letterHeight, Columns: 3, Rows: 10, LabelWidth: 66.675, LabelHeight: 25.4,
+		TopMargin: 12.7, LeftMargin: 4.7625, ColumnPitch: 69.85, RowPitch: 25.4},
+	"avery-5161": {Description: "Letter, 20 address labels, 4 x 1 in",
+		PageWidth: letterWidth, PageHeight: letterHeight, Columns: 2, Rows: 10, LabelWidth: 101.6, LabelHeight: 25.4,
+		TopMargin: 12.7, LeftMargin: 3.96875, ColumnPitch: 104.775, RowPitch: 25.4},
+	"avery-5163": {Description: "Letter, 10 shipping labels, 4 x 2 in",
+		PageWidth: letterWidth, PageHeight: letterHeight, Columns: 2, Rows: 5, LabelWidth: 101.6, LabelHeight: 50.8,
+		TopMargin: 12.7, LeftMargin: 3.96875, ColumnPitch: 104.775, RowPitch: 50.8},
+	"avery-l7160": {Description: "A4, 21 address labels, 63.5 x 38.1 mm",
+		PageWidth: a4Width, PageHeight: a4Height, Columns: 3, Rows: 7, LabelWidth: 63.5, LabelHeight: 38.1,
+		TopMargin: 15.15, LeftMargin: 7.25, ColumnPitch: 66.04, RowPitch: 38.1},
+	"avery-l7651": {Description: "A4, 65 mini labels, 38.1 x 21.2 mm",
+		PageWidth: a4Width, PageHeight: a4Height, Columns: 5, Rows: 13, LabelWidth: 38.1, LabelHeight: 21.2,
+		TopMargin: 10.7, LeftMargin: 4.75, ColumnPitch: 40.64, RowPitch: 21.2},
+}
+
+// DefaultLayout is the layout used when none is chosen.
+const DefaultLayout = "avery-5160"
+
+// Lookup returns the named layout.
+func Lookup(name string) (Layout, bool) {
+	layout, ok := layouts[name]
+	layout.Name = name
+	return layout, ok
+}
+
+// Layouts returns the named layouts, sorted by name.
+func Layouts() []Layout {
+	all := make([]Layout, 0, len(layouts))
+	for name := range layouts {
+		layout, _ := Lookup(name)
+		all = append(all, layout)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+var (
+	// ErrInvalidLayout is returned for a layout whose labels overlap or do
+	// not fit on the page.
+	ErrInvalidLayout = errors.New("labels: labels overlap or do not fit on the page")
+	// ErrBarcodeTooWide is returned when a barcode's bars would be too
+	// thin to scan at the width of the label.
+	ErrBarcodeTooWide = errors.New("labels: barcode too wide for the label")
+	// ErrInvalidSkip is returned for a number of labels to skip that is
+	// negative or would skip the whole first sheet.
+	ErrInvalidSkip = errors.New("labels: skip must be less than the labels on a sheet")
+)
+
+// tolerance absorbs rounding in layouts given in inches.
+const tolerance = 0.01
+
+// Validate checks that the labels are positive in size, do not overlap and
+// fit on the page.
+func (l Layout) Validate() error {
+	if l.PageWidth <= 0 || l.PageHeight <= 0 || l.LabelWidth <= 0 || l.LabelHeight <= 0 || l.Columns < 1 || l.Rows < 1 {
+		return ErrInvalidLayout
+	}
+	if l.Columns > 1 && l.ColumnPitch < l.LabelWidth-tolerance {
+		return ErrInvalidLayout
+	}
+	if l.Rows > 1 && l.RowPitch < l.LabelHeight-tolerance {
+		return ErrInvalidLayout
+	}
+	right := l.LeftMargin + float64(l.Columns-1)*l.ColumnPitch + l.LabelWidth
+	bottom := l.TopMargin + float64(l.Rows-1)*l.RowPitch + l.LabelHeight
+	if right > l.PageWidth+tolerance || bottom > l.PageHeight+tolerance {
+		return ErrInvalidLayout
+	}
+	return nil
+}
+
+// PerPage is the number of labels on a sheet.
+func (l Layout) PerPage() int {
+	return l.Columns * l.Rows
+}
+
+// Label is the content of one label: lines of text, the first set a
+// little larger, and an optional barcode printed below them with its text.
+type Label struct {
+	Lines   []string
+	Barcode *barcode.Barcode
+}
+
+const (
+	// padding keeps the content off the edges of the label, in points
+	padding = 2 * pdf.MM
+	// minModule and maxModule bound the width of a barcode module, in
+	// points. Most scanners need modules at least 0.19 mm wide.
+	minModule = 0.19 * pdf.MM
+	maxModule = 0.5 * pdf.MM
+)
+
+// Render lays out labels in reading order, filling each sheet before
+// starting the next, and returns them as a PDF. The first skip positions
+// of the first sheet are left blank so a partly used sheet can be printed
+// on again.
+func Render(layout Layout, labels []Label, skip int) ([]byte, error) {
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+	if skip < 0 || skip >= layout.PerPage() {
+		return nil, ErrInvalidSkip
+	}
+
+	doc := pdf.New(layout.PageWidth*pdf.MM, layout.PageHeight*pdf.MM)
+	var page *pdf.Page
+	for i, label := range labels {
+		pos := skip + i
+		if page == nil || pos%layout.PerPage() == 0 {
+			page = doc.AddPage()
+		}
+		pos %= layout.PerPage()
+		col, row := pos%layout.Columns, pos/layout.Columns
+
+		// The label's top left corner, in points from the bottom left of
+		// the page
+		x := (layout.LeftMargin + float64(col)*layout.ColumnPitch) * pdf.MM
+		top := (layout.PageHeight - layout.TopMargin - float64(row)*layout.RowPitch) * pdf.MM
+		if err := draw(page, label, x, top, layout.LabelWidth*pdf.MM, layout.LabelHeight*pdf.MM); err != nil {
+			return nil, err
+		}
+	}
+	return doc.Bytes()
+}
+
+// draw draws label in the width by height points whose top left corner is
+// at x, top.
+func draw(page *pdf.Page, label Label, x, top, width, height float64) error {
+	x += padding
+	top -= padding
+	width -= 2 * padding
+	height -= 2 * padding
+
+	// Size the type to the label, within readable bounds
+	size := math.Max(5, math.Min(10, height*0.14))
+	leading := size * 1.2
+
+	// Text gets the top of the label, leaving the barcode at least 40% of
+	// it
+	lines := label.Lines
+	if label.Barcode != nil {
+		if max := int(height * 0.6 / leading); len(lines) > max {
+			lines = lines[:max]
+		}
+	}
+	y := top
+	for i, line := range lines {
+		y -= leading
+		lineSize := size
+		if i == 0 {
+			lineSize = size * 1.15
+		}
+		page.Text(x, y+(leading-lineSize)/2, lineSize, fit(line, lineSize, width))
+	}
+
+	if label.Barcode == nil {
+		return nil
+	}
+	module := math.Min(maxModule, width/float64(label.Barcode.Width()))
+	if module < minModule {
+		return ErrBarcodeTooWide
+	}
+	captionSize := size * 0.9
+	bottom := top - height
+	barsBottom := bottom + captionSize*1.2
+	barsHeight := y - barsBottom - size*0.3
+	left := x + (width-module*float64(label.Barcode.Width()))/2
+	label.Barcode.Bars(func(start, w int) {
+		page.Rect(left+float64(start)*module, barsBottom, float64(w)*module, barsHeight)
+	})
+	caption := label.Barcode.Text
+	page.Text(x+(width-pdf.TextWidth(caption, captionSize))/2, bottom+captionSize*0.2, captionSize, caption)
+	return nil
+}
+
+// fit shortens s with an ellipsis until it is at most width points wide in
+// type of size points.
+func fit(s string, size, width float64) string {
+	if pdf.TextWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.TextWidth(string(runes)+"…", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
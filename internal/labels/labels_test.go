@@ -0,0 +1,93 @@
+package labels
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"library-management-go/internal/barcode"
+)
+
+func TestLayouts(t *testing.T) {
+	for _, layout := range Layouts() {
+		if err := layout.Validate(); err != nil {
+			t.Errorf("layout %s: %v", layout.Name, err)
+		}
+	}
+	if _, ok := Lookup(DefaultLayout); !ok {
+		t.Fatal("default layout not found")
+	}
+	if _, ok := Lookup("avery-0000"); ok {
+		t.Fatal("expected an unknown layout not to be found")
+	}
+
+	tests := []struct {
+		name   string
+		change func(*Layout)
+	}{
+		{"too many columns", func(l *Layout) { l.Columns = 4 }},
+		{"too many rows", func(l *Layout) { l.Rows = 11 }},
+		{"overlapping columns", func(l *Layout) { l.ColumnPitch = 60 }},
+		{"no rows", func(l *Layout) { l.Rows = 0 }},
+		{"no label height", func(l *Layout) { l.LabelHeight = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, _ := Lookup("avery-5160")
+			tt.change(&layout)
+			if err := layout.Validate(); !errors.Is(err, ErrInvalidLayout) {
+				t.Fatalf("Validate() = %v, want ErrInvalidLayout", err)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	layout, _ := Lookup("avery-l7651")
+	item, err := barcode.Encode(barcode.Code128, "30000000000018")
+	if err != nil {
+		t.Fatal(err)
+	}
+	label := Label{Lines: []string{"A Very Long Title That Cannot Fit On A Mini Label", "QA76.73 .G63"}, Barcode: item}
+
+	// 70 labels after 10 skipped fill the first sheet of 65 and start a
+	// second
+	sheet := make([]Label, 70)
+	for i := range sheet {
+		sheet[i] = label
+	}
+	data, err := Render(layout, sheet, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte("/Count 2 ")) {
+		t.Fatal("expected two pages")
+	}
+
+	if _, err := Render(layout, sheet, 65); !errors.Is(err, ErrInvalidSkip) {
+		t.Fatalf("skipping a whole sheet: %v", err)
+	}
+
+	// Code 39 needs about twice the width of Code 128 for the same digits
+	wide, err := barcode.Encode(barcode.Code39, "30000000000018")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Render(layout, []Label{{Barcode: wide}}, 0); !errors.Is(err, ErrBarcodeTooWide) {
+		t.Fatalf("Code 39 on a mini label: %v", err)
+	}
+	wider, _ := Lookup("avery-5160")
+	if _, err := Render(wider, []Label{{Barcode: wide}}, 0); err != nil {
+		t.Fatalf("Code 39 on an address label: %v", err)
+	}
+}
+
+func TestFit(t *testing.T) {
+	if got := fit("Dune", 10, 100); got != "Dune" {
+		t.Fatalf("fit shortened a title that fits to %q", got)
+	}
+	// At 10 points "Dun…" is 28.34 points wide and "Dune…" 33.9
+	if got := fit("Dune Messiah", 10, 30); got != "Dun…" {
+		t.Fatalf("fit = %q, want Dun…", got)
+	}
+}
@@ -0,0 +1,86 @@
+package mail_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"strings"
+	"testing"
+
+	"library-management-go/internal/mail"
+	"library-management-go/internal/mail/mailtest"
+)
+
+func TestSMTPSend(t *testing.T) {
+	server := mailtest.NewServer(t)
+	sender := mail.NewSMTP(mail.SMTPConfig{Host: server.Host(), Port: server.Port(), From: "Library <desk@library.example>"})
+
+	err := sender.Send(context.Background(), &mail.Message{
+		To:      "Ada Lovelace <ada@example.com>",
+		Subject: "Your receipt – Café",
+		Text:    "Dune\nDue 2026-11-01\n",
+		HTML:    "<p>Dune</p>",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	messages := server.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("server received %d messages, want 1", len(messages))
+	}
+	got := messages[0]
+	if got.From != "desk@library.example" || len(got.To) != 1 || got.To[0] != "ada@example.com" {
+		t.Fatalf("envelope from %q to %v", got.From, got.To)
+	}
+
+	msg, err := got.Parse()
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "Your receipt – Café" {
+		t.Fatalf("Subject = %q, %v", subject, err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q", msg.Header.Get("Content-Type"))
+	}
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := parts.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		if err != nil {
+			t.Fatalf("decode part: %v", err)
+		}
+		// The server hands the data over with LF line endings
+		bodies = append(bodies, string(body))
+	}
+	if len(bodies) != 2 || !strings.HasPrefix(bodies[0], "Dune\nDue 2026-11-01") || bodies[1] != "<p>Dune</p>" {
+		t.Fatalf("unexpected bodies %q", bodies)
+	}
+}
+
+func TestSMTPSendInvalidAddress(t *testing.T) {
+	server := mailtest.NewServer(t)
+	sender := mail.NewSMTP(mail.SMTPConfig{Host: server.Host(), Port: server.Port(), From: "desk@library.example"})
+
+	err := sender.Send(context.Background(), &mail.Message{To: "not an address", Text: "hello"})
+	if !errors.Is(err, mail.ErrInvalidAddress) {
+		t.Fatalf("Send = %v, want ErrInvalidAddress", err)
+	}
+	if len(server.Messages()) != 0 {
+		t.Fatal("expected nothing to be sent")
+	}
+}
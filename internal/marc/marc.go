@@ -0,0 +1,55 @@
+// Package marc reads MARC 21 bibliographic records, either in the ISO 2709
+// exchange format used by most catalogue exports or as MARCXML, and writes
+// them as MARCXML.
+package marc
+
+import "strings"
+
+// Record is one bibliographic record.
+type Record struct {
+	Leader string
+	Fields []Field
+}
+
+// Field is a control field (tags 001 to 009), which only has a Value, or a
+// data field with two indicators and its subfields.
+type Field struct {
+	Tag       string
+	Ind1      byte
+	Ind2      byte
+	Value     string
+	Subfields []Subfield
+}
+
+// Subfield is a coded element of a data field.
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// IsControl reports whether tag names a control field.
+func IsControl(tag string) bool {
+	return strings.HasPrefix(tag, "00")
+}
+
+// FieldsByTag returns the record's fields with tag, in record order.
+func (r *Record) FieldsByTag(tag string) []Field {
+	var fields []Field
+	for _, f := range r.Fields {
+		if f.Tag == tag {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Subfield returns the value of the first subfield with code, or "" if
+// there is none.
+func (f Field) Subfield(code byte) string {
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			return sf.Value
+		}
+	}
+	return ""
+}
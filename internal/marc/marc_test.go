@@ -0,0 +1,154 @@
+package marc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// encode builds an ISO 2709 record from fields given as tag and raw value,
+// with subfields written as "$a..." and data fields starting with their
+// indicators.
+func encode(fields ...[2]string) string {
+	var directory, data strings.Builder
+	for _, f := range fields {
+		value := strings.ReplaceAll(f[1], "$", "\x1f") + "\x1e"
+		fmt.Fprintf(&directory, "%s%04d%05d", f[0], len(value), data.Len())
+		data.WriteString(value)
+	}
+	base := leaderLength + directory.Len() + 1
+	length := base + data.Len() + 1
+	leader := fmt.Sprintf("%05dnam a22%05d a 4500", length, base)
+	return leader + directory.String() + "\x1e" + data.String() + "\x1d"
+}
+
+func TestReadAll(t *testing.T) {
+	first := encode(
+		[2]string{"001", "ocm123"},
+		[2]string{"020", "  $a9780316769488 (pbk.)"},
+		[2]string{"650", " 0$aTeenagers$vFiction."},
+		[2]string{"650", " 0$aNew York (N.Y.)$vFiction."},
+	)
+	second := encode([2]string{"245", "10$aSecond record"})
+
+	records, err := ReadAll(strings.NewReader(first + "\n" + second))
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+
+	r := records[0]
+	if r.Fields[0].Tag != "001" || r.Fields[0].Value != "ocm123" {
+		t.Fatalf("control field = %+v", r.Fields[0])
+	}
+	subjects := r.FieldsByTag("650")
+	if len(subjects) != 2 {
+		t.Fatalf("got %d 650 fields, want 2", len(subjects))
+	}
+	if subjects[0].Ind2 != '0' || subjects[0].Subfield('a') != "Teenagers" || subjects[0].Subfield('v') != "Fiction." {
+		t.Fatalf("650 = %+v", subjects[0])
+	}
+	if got := r.FieldsByTag("020")[0].Subfield('a'); got != "9780316769488 (pbk.)" {
+		t.Fatalf("020$a = %q", got)
+	}
+	if got := records[1].FieldsByTag("245")[0].Subfield('a'); got != "Second record" {
+		t.Fatalf("245$a = %q", got)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	valid := encode([2]string{"245", "10$aTitle"})
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not a record", "hello world"},
+		{"truncated", valid[:len(valid)-5]},
+		{"missing terminator", valid[:len(valid)-1] + "x"},
+		{"bad base address", valid[:12] + "abcde" + valid[17:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadAll(strings.NewReader(tt.data)); !errors.Is(err, ErrInvalidRecord) {
+				t.Fatalf("got %v, want ErrInvalidRecord", err)
+			}
+		})
+	}
+}
+
+func TestReadXML(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<collection xmlns="http://www.loc.gov/MARC21/slim">
+  <record>
+    <leader>00000nam a2200000 a 4500</leader>
+    <controlfield tag="001">ocm456</controlfield>
+    <datafield tag="650" ind1=" " ind2="7">
+      <subfield code="a">Dragons</subfield>
+      <subfield code="2">fast</subfield>
+    </datafield>
+  </record>
+  <record>
+    <datafield tag="245" ind1="1" ind2="0"><subfield code="a">Other</subfield></datafield>
+  </record>
+</collection>`
+
+	records, err := ReadXML(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("ReadXML: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	field := records[0].FieldsByTag("650")[0]
+	if field.Ind1 != ' ' || field.Ind2 != '7' || field.Subfield('a') != "Dragons" || field.Subfield('2') != "fast" {
+		t.Fatalf("650 = %+v", field)
+	}
+	if records[0].Fields[0].Value != "ocm456" {
+		t.Fatalf("control field = %+v", records[0].Fields[0])
+	}
+
+	if _, err := ReadXML(strings.NewReader("<collection><record>")); !errors.Is(err, ErrInvalidRecord) {
+		t.Fatalf("got %v, want ErrInvalidRecord", err)
+	}
+}
+
+func TestWriteXML(t *testing.T) {
+	record := &Record{
+		Leader: "00000nam a2200000   4500",
+		Fields: []Field{
+			{Tag: "001", Value: "book-1"},
+			{Tag: "245", Ind1: '1', Ind2: '0', Subfields: []Subfield{{Code: 'a', Value: "Fish & Chips <revised>"}}},
+			{Tag: "650", Ind2: '4', Subfields: []Subfield{{Code: 'a', Value: "Cooking"}}},
+		},
+	}
+
+	var buf strings.Builder
+	if err := WriteXML(&buf, []*Record{record, record}); err != nil {
+		t.Fatalf("WriteXML: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "<?xml") || !strings.Contains(buf.String(), `<collection xmlns="`+Namespace+`">`) {
+		t.Fatalf("unexpected document:\n%s", buf.String())
+	}
+
+	records, err := ReadXML(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("ReadXML: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	got := records[0]
+	if got.Leader != record.Leader || got.Fields[0].Value != "book-1" {
+		t.Fatalf("record = %+v", got)
+	}
+	if title := got.FieldsByTag("245")[0]; title.Ind1 != '1' || title.Subfield('a') != "Fish & Chips <revised>" {
+		t.Fatalf("245 = %+v", title)
+	}
+	// Unset indicators are written blank
+	if subject := got.FieldsByTag("650")[0]; subject.Ind1 != ' ' || subject.Ind2 != '4' {
+		t.Fatalf("650 = %+v", subject)
+	}
+}
@@ -0,0 +1,140 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	leaderLength   = 24
+	entryLength    = 12
+	fieldTerm      = 0x1e
+	recordTerm     = 0x1d
+	subfieldDelim  = 0x1f
+	maxRecordBytes = 99999
+)
+
+// ErrInvalidRecord is returned for data that is not a well-formed ISO 2709
+// record.
+var ErrInvalidRecord = errors.New("invalid MARC record")
+
+// Reader reads ISO 2709 records one at a time.
+type Reader struct {
+	r *bufio.Reader
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read returns the next record, or io.EOF once there are no more. Line
+// breaks between records, which some tools add, are skipped.
+func (r *Reader) Read() (*Record, error) {
+	for {
+		b, err := r.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b != '\n' && b != '\r' {
+			if err := r.r.UnreadByte(); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	prefix := make([]byte, 5)
+	if _, err := io.ReadFull(r.r, prefix); err != nil {
+		return nil, invalid("truncated leader")
+	}
+	length, err := strconv.Atoi(string(prefix))
+	if err != nil || length < leaderLength+1 || length > maxRecordBytes {
+		return nil, invalid("bad record length %q", prefix)
+	}
+
+	data := make([]byte, length)
+	copy(data, prefix)
+	if _, err := io.ReadFull(r.r, data[5:]); err != nil {
+		return nil, invalid("truncated record")
+	}
+	return parse(data)
+}
+
+// ReadAll reads every record from r.
+func ReadAll(r io.Reader) ([]*Record, error) {
+	reader := NewReader(r)
+	var records []*Record
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+}
+
+func parse(data []byte) (*Record, error) {
+	if data[len(data)-1] != recordTerm {
+		return nil, invalid("missing record terminator")
+	}
+
+	leader := data[:leaderLength]
+	base, err := strconv.Atoi(string(leader[12:17]))
+	if err != nil || base <= leaderLength || base > len(data) {
+		return nil, invalid("bad base address %q", leader[12:17])
+	}
+
+	directory := data[leaderLength : base-1]
+	if data[base-1] != fieldTerm || len(directory)%entryLength != 0 {
+		return nil, invalid("malformed directory")
+	}
+
+	record := &Record{Leader: string(leader)}
+	for i := 0; i < len(directory); i += entryLength {
+		entry := directory[i : i+entryLength]
+		tag := string(entry[:3])
+		length, err1 := strconv.Atoi(string(entry[3:7]))
+		start, err2 := strconv.Atoi(string(entry[7:12]))
+		if err1 != nil || err2 != nil || length < 1 || base+start+length > len(data) {
+			return nil, invalid("bad directory entry for field %s", tag)
+		}
+
+		// Drop the field terminator
+		value := data[base+start : base+start+length-1]
+		field, err := parseField(tag, value)
+		if err != nil {
+			return nil, err
+		}
+		record.Fields = append(record.Fields, field)
+	}
+	return record, nil
+}
+
+func parseField(tag string, value []byte) (Field, error) {
+	if IsControl(tag) {
+		return Field{Tag: tag, Value: string(value)}, nil
+	}
+	if len(value) < 2 {
+		return Field{}, invalid("field %s has no indicators", tag)
+	}
+
+	field := Field{Tag: tag, Ind1: value[0], Ind2: value[1]}
+	for _, part := range bytes.Split(value[2:], []byte{subfieldDelim})[1:] {
+		if len(part) == 0 {
+			continue
+		}
+		field.Subfields = append(field.Subfields, Subfield{Code: part[0], Value: string(part[1:])})
+	}
+	return field, nil
+}
+
+func invalid(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRecord, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,132 @@
+package marc
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Namespace is the MARCXML namespace.
+const Namespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlRecord struct {
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ReadXML reads the records of a MARCXML document, whose root is either a
+// collection or a single record.
+func ReadXML(r io.Reader) ([]*Record, error) {
+	decoder := xml.NewDecoder(r)
+	var records []*Record
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+		var x xmlRecord
+		if err := decoder.DecodeElement(&x, &start); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+		}
+		records = append(records, x.record())
+	}
+}
+
+// record converts the decoded element, keeping control fields ahead of
+// data fields as ISO 2709 does.
+func (x *xmlRecord) record() *Record {
+	record := &Record{Leader: x.Leader}
+	for _, cf := range x.ControlFields {
+		record.Fields = append(record.Fields, Field{Tag: cf.Tag, Value: cf.Value})
+	}
+	for _, df := range x.DataFields {
+		field := Field{Tag: df.Tag, Ind1: indicator(df.Ind1), Ind2: indicator(df.Ind2)}
+		for _, sf := range df.Subfields {
+			if sf.Code == "" {
+				continue
+			}
+			field.Subfields = append(field.Subfields, Subfield{Code: sf.Code[0], Value: sf.Value})
+		}
+		record.Fields = append(record.Fields, field)
+	}
+	return record
+}
+
+// MarshalXML writes r as a MARCXML record element, declaring the MARCXML
+// namespace so that it can be embedded in other documents.
+func (r *Record) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	x := xmlRecord{Leader: r.Leader}
+	for _, f := range r.Fields {
+		if IsControl(f.Tag) {
+			x.ControlFields = append(x.ControlFields, xmlControlField{Tag: f.Tag, Value: f.Value})
+			continue
+		}
+		df := xmlDataField{Tag: f.Tag, Ind1: indicatorValue(f.Ind1), Ind2: indicatorValue(f.Ind2)}
+		for _, sf := range f.Subfields {
+			df.Subfields = append(df.Subfields, xmlSubfield{Code: string(sf.Code), Value: sf.Value})
+		}
+		x.DataFields = append(x.DataFields, df)
+	}
+	start.Name = xml.Name{Space: Namespace, Local: "record"}
+	start.Attr = nil
+	return e.EncodeElement(x, start)
+}
+
+// WriteXML writes records as a MARCXML collection document.
+func WriteXML(w io.Writer, records []*Record) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	collection := struct {
+		XMLName xml.Name  `xml:"collection"`
+		Xmlns   string    `xml:"xmlns,attr"`
+		Records []*Record `xml:"record"`
+	}{Xmlns: Namespace, Records: records}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(collection)
+}
+
+// indicator returns the indicator in value, blank when it is missing.
+func indicator(value string) byte {
+	if value == "" {
+		return ' '
+	}
+	return value[0]
+}
+
+// indicatorValue returns the attribute value of indicator, blank when it is
+// unset.
+func indicatorValue(indicator byte) string {
+	if indicator == 0 {
+		return " "
+	}
+	return string(indicator)
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"library-management-go/internal/reqctx"
+	"library-management-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// MaxIdempotentBodySize is the largest request body read to check an
+// Idempotency-Key, enough for a cover upload with its multipart framing.
+const MaxIdempotentBodySize = services.MaxCoverSize + 64<<10
+
+// responseRecorder tees the response body so it can be stored once the
+// handler has finished.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency replays the stored response for POST requests that repeat an
+// Idempotency-Key header, and rejects reuse of a key with a different body.
+// Keys are scoped to the tenant, method, path and credentials, so the same
+// key sent to two endpoints, or by two callers, names two requests.
+// Authentication failures are not stored, so they cannot be replayed to
+// the caller's retry.
+func Idempotency(idempotencyService *services.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, MaxIdempotentBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Keys are stored per tenant, endpoint and credentials so two
+		// libraries, endpoints or borrowers cannot collide. The middleware
+		// runs before authentication, so it keys on the Authorization
+		// header rather than on who it names
+		key = c.Request.Method + " " + c.Request.URL.Path + " " + key
+		if authorization := c.GetHeader("Authorization"); authorization != "" {
+			hash := sha256.Sum256([]byte(authorization))
+			key = hex.EncodeToString(hash[:]) + " " + key
+		}
+		if tenantID := reqctx.TenantID(c.Request.Context()); tenantID != uuid.Nil {
+			key = tenantID.String() + ":" + key
+		}
+
+		hash := sha256.Sum256(body)
+		requestHash := hex.EncodeToString(hash[:])
+
+		stored, err := idempotencyService.Reserve(key, c.Request.Method, c.Request.URL.Path, requestHash)
+		switch {
+		case errors.Is(err, services.ErrIdempotencyKeyMismatch):
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, services.ErrIdempotencyKeyInProgress):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if stored != nil {
+			// Keys stored before content types were recorded all held JSON
+			contentType := stored.ContentType
+			if contentType == "" {
+				contentType = "application/json; charset=utf-8"
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(stored.StatusCode, contentType, stored.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		release := func() {
+			if err := idempotencyService.Release(key); err != nil {
+				log.Printf("failed to release idempotency key %s: %v", key, err)
+			}
+		}
+
+		// A handler that panics has not finished the request, so its key
+		// is freed for the retry before the panic carries on to recovery
+		defer func() {
+			if r := recover(); r != nil {
+				release()
+				panic(r)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		// Server errors are not cached so the client can retry them, nor
+		// are authentication failures, which say nothing about the request
+		status := c.Writer.Status()
+		if status >= http.StatusInternalServerError || status == http.StatusUnauthorized || status == http.StatusForbidden {
+			release()
+			return
+		}
+
+		if err := idempotencyService.Complete(key, status, c.Writer.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+			log.Printf("failed to store idempotent response for key %s: %v", key, err)
+		}
+	}
+}
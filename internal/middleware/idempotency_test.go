@@ -0,0 +1,186 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"library-management-go/internal/middleware"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// idempotentRouter serves handler at POST /things and /others behind the
+// idempotency middleware, recovering from panics as the API does.
+func idempotentRouter(t *testing.T, handler gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+
+	router := gin.New()
+	router.Use(gin.RecoveryWithWriter(io.Discard))
+	router.Use(middleware.Idempotency(services.NewIdempotencyService(testutil.NewDB(t), time.Hour)))
+	router.POST("/things", handler)
+	router.POST("/others", handler)
+	return router
+}
+
+// post sends body to path with the idempotency key and any other header
+// key/value pairs.
+func post(router http.Handler, path, key, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	calls := 0
+	router := idempotentRouter(t, func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	first := post(router, "/things", "k1", `{"a":1}`)
+	second := post(router, "/things", "k1", `{"a":1}`)
+	if calls != 1 || second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Fatalf("calls = %d, replay %d %s; want 1 call and %s", calls, second.Code, second.Body, first.Body)
+	}
+	if second.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatal("expected the replay to be marked")
+	}
+
+	// The same key on another endpoint is another request
+	if w := post(router, "/others", "k1", `{"a":1}`); w.Code != http.StatusCreated || calls != 2 {
+		t.Fatalf("other endpoint: status %d after %d calls", w.Code, calls)
+	}
+	// Requests without a key are never replayed
+	if w := post(router, "/things", "", `{"a":1}`); w.Code != http.StatusCreated || calls != 3 {
+		t.Fatalf("no key: status %d after %d calls", w.Code, calls)
+	}
+}
+
+func TestIdempotencyScopedToCredentials(t *testing.T) {
+	calls := 0
+	router := idempotentRouter(t, func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "Bearer alice" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	// A failed sign-in is not stored for the real caller's retry
+	if w := post(router, "/things", "k1", `{}`); w.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous: status = %d", w.Code)
+	}
+	if w := post(router, "/things", "k1", `{}`, "Authorization", "Bearer alice"); w.Code != http.StatusCreated || calls != 1 {
+		t.Fatalf("signed in: status %d after %d calls", w.Code, calls)
+	}
+
+	// Nor is the caller's response replayed to anyone else
+	if w := post(router, "/things", "k1", `{}`); w.Code != http.StatusUnauthorized || w.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("anonymous retry: status %d, replayed %q", w.Code, w.Header().Get("Idempotent-Replayed"))
+	}
+	if w := post(router, "/things", "k1", `{}`, "Authorization", "Bearer alice"); w.Header().Get("Idempotent-Replayed") != "true" || calls != 1 {
+		t.Fatalf("signed in retry: replayed %q after %d calls", w.Header().Get("Idempotent-Replayed"), calls)
+	}
+}
+
+func TestIdempotencyReplaysContentType(t *testing.T) {
+	router := idempotentRouter(t, func(c *gin.Context) {
+		c.Data(http.StatusCreated, "text/csv", []byte("id\n1\n"))
+	})
+
+	post(router, "/things", "k1", `{}`)
+	w := post(router, "/things", "k1", `{}`)
+	if w.Header().Get("Idempotent-Replayed") != "true" || w.Header().Get("Content-Type") != "text/csv" || w.Body.String() != "id\n1\n" {
+		t.Fatalf("replayed %q as %q", w.Body, w.Header().Get("Content-Type"))
+	}
+}
+
+func TestIdempotencyBodyTooLarge(t *testing.T) {
+	calls := 0
+	router := idempotentRouter(t, func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	body := strings.Repeat("x", middleware.MaxIdempotentBodySize+1)
+	if w := post(router, "/things", "k1", body); w.Code != http.StatusRequestEntityTooLarge || calls != 0 {
+		t.Fatalf("status %d after %d calls, want %d", w.Code, calls, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestIdempotencyMismatchedBody(t *testing.T) {
+	router := idempotentRouter(t, func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	post(router, "/things", "k1", `{"a":1}`)
+	if w := post(router, "/things", "k1", `{"a":2}`); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestIdempotencyConcurrentDuplicate(t *testing.T) {
+	entered, finish := make(chan struct{}), make(chan struct{})
+	router := idempotentRouter(t, func(c *gin.Context) {
+		close(entered)
+		<-finish
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- post(router, "/things", "k1", `{}`) }()
+	<-entered
+
+	if w := post(router, "/things", "k1", `{}`); w.Code != http.StatusConflict {
+		t.Fatalf("duplicate in flight: status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	close(finish)
+	if w := <-done; w.Code != http.StatusCreated {
+		t.Fatalf("original: status = %d, want %d", w.Code, http.StatusCreated)
+	}
+}
+
+func TestIdempotencyReleasesFailures(t *testing.T) {
+	tests := []struct {
+		name string
+		fail func(c *gin.Context)
+	}{
+		{"server error", func(c *gin.Context) { c.JSON(http.StatusServiceUnavailable, gin.H{}) }},
+		{"panic", func(*gin.Context) { panic("handler crashed") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			router := idempotentRouter(t, func(c *gin.Context) {
+				if calls++; calls == 1 {
+					tt.fail(c)
+					return
+				}
+				c.JSON(http.StatusCreated, gin.H{})
+			})
+
+			if w := post(router, "/things", "k1", `{}`); w.Code < http.StatusInternalServerError {
+				t.Fatalf("first attempt: status = %d, want a server error", w.Code)
+			}
+			if w := post(router, "/things", "k1", `{}`); w.Code != http.StatusCreated || calls != 2 {
+				t.Fatalf("retry: status %d after %d calls, want %d after 2", w.Code, calls, http.StatusCreated)
+			}
+		})
+	}
+}
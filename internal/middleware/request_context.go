@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"library-management-go/internal/reqctx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	ActorHeader     = "X-Actor"
+
+	OverrideTokenHeader = "X-Override-Token"
+)
+
+// RequestContext assigns every request an ID (reusing the caller's
+// X-Request-ID when present) and records the acting user from X-Actor, so
+// both reach the audit log through the request context. X-Actor is taken
+// on trust, so the recorded actor is only as reliable as the caller.
+func RequestContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		ctx := reqctx.WithRequestID(c.Request.Context(), requestID)
+		ctx = reqctx.WithActor(ctx, c.GetHeader(ActorHeader))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// BlockOverride lets the request override borrower blocks when it carries
+// the configured X-Override-Token. Requests without it are served as usual
+// and only refused if they ask for an override; no request may override
+// while no token is configured.
+func BlockOverride(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token != "" && subtle.ConstantTimeCompare([]byte(c.GetHeader(OverrideTokenHeader)), []byte(token)) == 1 {
+			c.Request = c.Request.WithContext(reqctx.WithBlockOverride(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
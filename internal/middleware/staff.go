@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const StaffTokenHeader = "X-Staff-Token"
+
+// StaffToken guards routes that hand out a borrower's credentials or data,
+// such as setting their PIN, so only staff can call them. It refuses every
+// request when no token is configured.
+func StaffToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "staff access is disabled"})
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader(StaffTokenHeader)), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid staff token"})
+			return
+		}
+		c.Next()
+	}
+}
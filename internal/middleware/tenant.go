@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"library-management-go/internal/reqctx"
+	"library-management-go/internal/services"
+	"library-management-go/internal/tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	TenantHeader     = "X-Tenant"
+	AdminTokenHeader = "X-Admin-Token"
+)
+
+// Tenant scopes the request to the tenant it names (see tenant.Resolver),
+// rejecting requests that name none, an unknown tenant or a suspended one.
+func Tenant(resolver *tenant.Resolver, tenantService *services.TenantService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		slug, err := resolver.Slug(c.Request.Host, c.GetHeader(TenantHeader), c.GetHeader("Authorization"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if slug == "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "request does not identify a tenant"})
+			return
+		}
+
+		t, err := tenantService.WithContext(c.Request.Context()).GetTenantBySlug(slug)
+		switch {
+		case errors.Is(err, services.ErrTenantNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve tenant"})
+			return
+		case !t.Active:
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "tenant is suspended"})
+			return
+		}
+
+		c.Request = c.Request.WithContext(reqctx.WithTenantID(c.Request.Context(), t.ID))
+		c.Next()
+	}
+}
+
+// AdminToken guards the tenant provisioning API. It refuses every request
+// when no token is configured.
+func AdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "tenant provisioning is disabled"})
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader(AdminTokenHeader)), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid admin token"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -1,15 +1,52 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// Tenant is one library served by a multi-tenant deployment. Every other
+// model carries the TenantID of the library that owns it.
+type Tenant struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	Slug      string         `json:"slug" gorm:"uniqueIndex;not null"`
+	Name      string         `json:"name" gorm:"not null"`
+	Active    bool           `json:"active" gorm:"not null"`
+	Settings  TenantSettings `json:"settings" gorm:"embedded"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// TenantSettings holds the circulation rules a tenant can configure
+type TenantSettings struct {
+	MaxActiveLoans        int  `json:"max_active_loans" gorm:"not null"`
+	BlockOverdueBorrowers bool `json:"block_overdue_borrowers" gorm:"not null"`
+	// LoanPeriodDays is how far a renewal pushes the due date, counted
+	// from the day of renewal
+	LoanPeriodDays int `json:"loan_period_days"`
+	MaxRenewals    int `json:"max_renewals"`
+	// FinePerDay is charged for each day a loan is returned late, in the
+	// currency's minor unit; zero charges no fines
+	FinePerDay int64 `json:"fine_per_day"`
+}
+
+// DefaultTenantSettings apply to new tenants and to requests that are not
+// scoped to a tenant
+var DefaultTenantSettings = TenantSettings{
+	MaxActiveLoans:        5,
+	BlockOverdueBorrowers: true,
+	LoanPeriodDays:        14,
+	MaxRenewals:           2,
+	FinePerDay:            25,
+}
+
 // Author represents a book author
 type Author struct {
-	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID  uuid.UUID `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index"`
 	Name      string    `json:"name" gorm:"not null"`
 	Biography string    `json:"biography"`
 	CreatedAt time.Time `json:"created_at"`
@@ -17,49 +54,404 @@ type Author struct {
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
+// Branch is one of the library's physical sites
+type Branch struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID  uuid.UUID      `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index;uniqueIndex:idx_branches_tenant_code_active,where:deleted_at IS NULL"`
+	Code      string         `json:"code" gorm:"uniqueIndex:idx_branches_tenant_code_active,where:deleted_at IS NULL;not null"`
+	Name      string         `json:"name" gorm:"not null"`
+	Address   string         `json:"address"`
+	Phone     string         `json:"phone"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Location is a shelf or collection area within a branch
+type Location struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID  uuid.UUID      `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index"`
+	BranchID  uuid.UUID      `json:"branch_id" gorm:"type:uuid;not null;uniqueIndex:idx_locations_branch_code_active,where:deleted_at IS NULL"`
+	Branch    *Branch        `json:"branch,omitempty" gorm:"foreignKey:BranchID"`
+	Code      string         `json:"code" gorm:"not null;uniqueIndex:idx_locations_branch_code_active,where:deleted_at IS NULL"`
+	Name      string         `json:"name" gorm:"not null"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
 // Book represents a book in the library
 type Book struct {
-	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID    uuid.UUID `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index;uniqueIndex:idx_books_tenant_isbn_active,where:deleted_at IS NULL;uniqueIndex:idx_books_tenant_barcode_active,where:deleted_at IS NULL AND barcode <> ''"`
 	Title       string    `json:"title" gorm:"not null"`
-	ISBN        string    `json:"isbn" gorm:"uniqueIndex;not null"`
+	ISBN        string    `json:"isbn" gorm:"uniqueIndex:idx_books_tenant_isbn_active,where:deleted_at IS NULL;not null"`
+	// Barcode identifies the physical item, see package cardnumber
+	Barcode     string    `json:"barcode" gorm:"uniqueIndex:idx_books_tenant_barcode_active,where:deleted_at IS NULL AND barcode <> '';not null;default:''"`
 	Description string    `json:"description"`
-	AuthorID    uuid.UUID `json:"author_id" gorm:"type:uuid;not null"`
-	Author      Author    `json:"author" gorm:"foreignKey:AuthorID"`
+	// Contributors credits the book's authors, editors, translators and
+	// illustrators in title page order.
+	Contributors []BookContributor `json:"contributors" gorm:"foreignKey:BookID;constraint:OnDelete:CASCADE"`
+	// Subjects tags the book with terms from the subject vocabulary
+	Subjects []Subject `json:"subjects" gorm:"many2many:book_subjects;constraint:OnDelete:CASCADE"`
 	PublishedAt time.Time `json:"published_at"`
 	Available   bool      `json:"available" gorm:"default:true"`
+	// HomeLocationID is where the item is shelved; CurrentLocationID is
+	// where it is now, empty while on loan or in transit.
+	HomeLocationID    *uuid.UUID `json:"home_location_id" gorm:"type:uuid;index"`
+	HomeLocation      *Location  `json:"home_location,omitempty" gorm:"foreignKey:HomeLocationID"`
+	CurrentLocationID *uuid.UUID `json:"current_location_id" gorm:"type:uuid;index"`
+	CurrentLocation   *Location  `json:"current_location,omitempty" gorm:"foreignKey:CurrentLocationID"`
+	InTransit         bool       `json:"in_transit" gorm:"not null;default:false"`
+	// Edition metadata. Language is a BCP 47 tag such as "en" or "pt-BR".
+	PublisherID *uuid.UUID `json:"publisher_id" gorm:"type:uuid;index"`
+	Publisher   *Publisher `json:"publisher,omitempty" gorm:"foreignKey:PublisherID"`
+	Edition     string     `json:"edition"`
+	Format      string     `json:"format" gorm:"index"`
+	Pages       int        `json:"pages"`
+	Language    string     `json:"language" gorm:"index"`
+	// SeriesVolume numbers the book within its series, 0 when unnumbered
+	SeriesID     *uuid.UUID `json:"series_id" gorm:"type:uuid;index"`
+	Series       *Series    `json:"series,omitempty" gorm:"foreignKey:SeriesID"`
+	SeriesVolume int        `json:"series_volume"`
+	// WorkID links the editions of the same title
+	WorkID *uuid.UUID `json:"work_id" gorm:"type:uuid;index"`
+	Work   *Work      `json:"work,omitempty" gorm:"foreignKey:WorkID"`
+	// CallNumber is written in ClassificationScheme ("dewey", "lc" or
+	// "local"); CallNumberSort is the key that puts it in shelf order.
+	CallNumber           string `json:"call_number"`
+	ClassificationScheme string `json:"classification_scheme" gorm:"index:idx_books_call_number,priority:1"`
+	CallNumberSort       string `json:"-" gorm:"index:idx_books_call_number,priority:2"`
+	// CoverKey prefixes the storage keys of the cover image and its
+	// thumbnails, whose URLs Cover holds.
+	CoverKey string     `json:"-"`
+	Cover    *BookCover `json:"cover" gorm:"serializer:json;type:text"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
+// BookCover is where clients fetch a book's cover image, as uploaded, and
+// its JPEG thumbnails, keyed by size name.
+type BookCover struct {
+	URL         string            `json:"url"`
+	ContentType string            `json:"content_type"`
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+	Thumbnails  map[string]string `json:"thumbnails"`
+}
+
+// Contributor roles
+const (
+	RoleAuthor      = "author"
+	RoleEditor      = "editor"
+	RoleTranslator  = "translator"
+	RoleIllustrator = "illustrator"
+)
+
+// BookContributor credits an author with a role on a book. Position orders
+// the credits, starting from 0.
+type BookContributor struct {
+	BookID   uuid.UUID `json:"-" gorm:"type:uuid;primaryKey"`
+	AuthorID uuid.UUID `json:"author_id" gorm:"type:uuid;primaryKey;index"`
+	Author   Author    `json:"author" gorm:"foreignKey:AuthorID"`
+	Role     string    `json:"role" gorm:"primaryKey"`
+	Position int       `json:"position" gorm:"not null"`
+}
+
+// Subject kinds
+const (
+	SubjectTopic = "topic"
+	SubjectGenre = "genre"
+)
+
+// Subject is a term in the controlled vocabulary books are classified by.
+// Terms form a hierarchy, and a book tagged with a narrower term is found
+// under every broader one.
+type Subject struct {
+	ID       uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID uuid.UUID  `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index"`
+	ParentID *uuid.UUID `json:"parent_id" gorm:"type:uuid;index"`
+	Parent   *Subject   `json:"parent,omitempty" gorm:"foreignKey:ParentID"`
+	Children []Subject  `json:"children,omitempty" gorm:"foreignKey:ParentID"`
+	Name     string     `json:"name" gorm:"not null"`
+	Kind     string     `json:"kind" gorm:"not null;default:'topic'"`
+	// Source names the thesaurus the term was taken from, such as lcsh,
+	// and is empty for local terms
+	Source    string         `json:"source"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// BookSubject tags a book with a subject. It is the join table behind
+// Book.Subjects.
+type BookSubject struct {
+	BookID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	SubjectID uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+// Book formats
+const (
+	FormatHardcover = "hardcover"
+	FormatPaperback = "paperback"
+	FormatEbook     = "ebook"
+	FormatAudiobook = "audiobook"
+)
+
+// Publisher issues the editions in the catalogue
+type Publisher struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID uuid.UUID `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index"`
+	Name     string    `json:"name" gorm:"not null"`
+	// Place is the place of publication, as given in an imprint
+	Place     string         `json:"place"`
+	Website   string         `json:"website"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Series groups books published as numbered volumes
+type Series struct {
+	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID    uuid.UUID      `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index"`
+	Name        string         `json:"name" gorm:"not null"`
+	Description string         `json:"description"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Work is a title independent of its editions, linking a hardcover first
+// edition to a later paperback reprint or a translation
+type Work struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID uuid.UUID `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index"`
+	Title    string    `json:"title" gorm:"not null"`
+	// Editions are the work's books, loaded when a single work is fetched
+	Editions  []Book         `json:"editions,omitempty" gorm:"foreignKey:WorkID"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// BorrowerCategory groups borrowers that share membership rules, such as
+// children, adults, staff or visiting researchers
+type BorrowerCategory struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID uuid.UUID `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index;uniqueIndex:idx_borrower_categories_tenant_code_active,where:deleted_at IS NULL"`
+	Code     string    `json:"code" gorm:"uniqueIndex:idx_borrower_categories_tenant_code_active,where:deleted_at IS NULL;not null"`
+	Name     string    `json:"name" gorm:"not null"`
+	// MembershipDays is the length of a membership term; memberships in
+	// categories without one never expire
+	MembershipDays int `json:"membership_days" gorm:"not null;default:0"`
+	// MaxActiveLoans overrides the tenant's loan limit when set
+	MaxActiveLoans int            `json:"max_active_loans" gorm:"not null;default:0"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Borrower status values. A borrower is expired once their membership
+// passes its expiry date, even before the status has been updated, and
+// erased once their personal data has been removed on request.
+const (
+	BorrowerActive    = "active"
+	BorrowerSuspended = "suspended"
+	BorrowerExpired   = "expired"
+	BorrowerErased    = "erased"
+)
+
 // Borrower represents a library member
 type Borrower struct {
-	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID  uuid.UUID `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index;uniqueIndex:idx_borrowers_tenant_email_active,where:deleted_at IS NULL;uniqueIndex:idx_borrowers_tenant_card_active,where:deleted_at IS NULL AND card_number <> ''"`
 	Name      string    `json:"name" gorm:"not null"`
-	Email     string    `json:"email" gorm:"uniqueIndex;not null"`
+	Email     string    `json:"email" gorm:"uniqueIndex:idx_borrowers_tenant_email_active,where:deleted_at IS NULL;not null"`
 	Phone     string    `json:"phone"`
 	Address   string    `json:"address"`
+	// CardNumber is the library card barcode, see package cardnumber
+	CardNumber          string            `json:"card_number" gorm:"uniqueIndex:idx_borrowers_tenant_card_active,where:deleted_at IS NULL AND card_number <> '';not null;default:''"`
+	CategoryID          *uuid.UUID        `json:"category_id" gorm:"type:uuid;index"`
+	Category            *BorrowerCategory `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	Status              string            `json:"status" gorm:"not null;default:'active';index"` // active, suspended, expired, erased
+	MembershipStart     time.Time         `json:"membership_start"`
+	MembershipExpiresAt *time.Time        `json:"membership_expires_at" gorm:"index"`
+	// PINHash is the bcrypt hash of the PIN the borrower signs in to the
+	// self-service API with; borrowers without one cannot sign in
+	PINHash   string          `json:"-"`
+	// PINVersion counts PIN changes; self-service tokens carry it and stop
+	// working once it moves on
+	PINVersion int `json:"-" gorm:"not null;default:0"`
+	// FailedPINAttempts counts wrong PINs since the last sign-in or lockout;
+	// too many lock the card out of PIN checks until PINLockedUntil
+	FailedPINAttempts int        `json:"-" gorm:"not null;default:0"`
+	PINLockedUntil    *time.Time `json:"-"`
+	// KeepHistory opts the borrower in to keeping their reading history;
+	// otherwise returned loans are anonymized after the retention period
+	KeepHistory bool          `json:"keep_history" gorm:"not null;default:false"`
+	ErasedAt    *time.Time    `json:"erased_at,omitempty"`
+	// Blocks lists the borrower's blocks in force; it is only loaded for
+	// single-borrower lookups
+	Blocks    []BorrowerBlock `json:"blocks,omitempty" gorm:"foreignKey:BorrowerID"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
+// Block reasons
+const (
+	BlockLostCard   = "lost_card"
+	BlockBehaviour  = "behaviour"
+	BlockUnpaidFees = "unpaid_fees"
+	BlockOther      = "other"
+)
+
+// BorrowerBlock stops a borrower from borrowing until it expires or is
+// lifted. Staff may override an overridable block at checkout
+type BorrowerBlock struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID    uuid.UUID  `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index"`
+	BorrowerID  uuid.UUID  `json:"borrower_id" gorm:"type:uuid;not null;index"`
+	Reason      string     `json:"reason" gorm:"not null"` // lost_card, behaviour, unpaid_fees, other
+	Note        string     `json:"note"`
+	CreatedBy   string     `json:"created_by" gorm:"not null"`
+	Overridable bool       `json:"overridable" gorm:"not null;default:false"`
+	// ExpiresAt is optional; blocks without one stay until lifted
+	ExpiresAt *time.Time `json:"expires_at"`
+	LiftedAt  *time.Time `json:"lifted_at"`
+	LiftedBy  string     `json:"lifted_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
 // Borrowing represents a book borrowing record
 type Borrowing struct {
-	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID   uuid.UUID `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index"`
 	BookID     uuid.UUID `json:"book_id" gorm:"type:uuid;not null"`
 	Book       Book      `json:"book" gorm:"foreignKey:BookID"`
-	BorrowerID uuid.UUID `json:"borrower_id" gorm:"type:uuid;not null"`
-	Borrower   Borrower  `json:"borrower" gorm:"foreignKey:BorrowerID"`
+	// BorrowerID is nil once the loan has been anonymized
+	BorrowerID *uuid.UUID `json:"borrower_id" gorm:"type:uuid;index"`
+	Borrower   *Borrower  `json:"borrower,omitempty" gorm:"foreignKey:BorrowerID"`
 	BorrowedAt time.Time `json:"borrowed_at" gorm:"not null"`
 	DueDate    time.Time `json:"due_date" gorm:"not null"`
 	ReturnedAt *time.Time `json:"returned_at"`
 	Status     string    `json:"status" gorm:"default:'borrowed'"` // borrowed, returned, overdue
+	// BranchID is where the loan was checked out, ReturnBranchID where it
+	// was checked in
+	BranchID       *uuid.UUID `json:"branch_id" gorm:"type:uuid;index"`
+	ReturnBranchID *uuid.UUID `json:"return_branch_id" gorm:"type:uuid"`
+	RenewalCount   int        `json:"renewal_count" gorm:"not null;default:0"`
+	RenewedAt      *time.Time `json:"renewed_at"`
+	AnonymizedAt   *time.Time `json:"anonymized_at,omitempty"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
+// Hold status values
+const (
+	HoldWaiting   = "waiting"
+	HoldFulfilled = "fulfilled"
+	HoldCancelled = "cancelled"
+)
+
+// Hold is a borrower's place in the queue for a book. While a book has
+// waiting holds only the borrower at the head of the queue may borrow it,
+// and loans of it cannot be renewed
+type Hold struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID uuid.UUID `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index"`
+	BookID   uuid.UUID `json:"book_id" gorm:"type:uuid;not null;index"`
+	Book     *Book     `json:"book,omitempty" gorm:"foreignKey:BookID"`
+	// BorrowerID is nil once the closed hold has been anonymized
+	BorrowerID   *uuid.UUID `json:"borrower_id" gorm:"type:uuid;index"`
+	Status       string     `json:"status" gorm:"not null;index"` // waiting, fulfilled, cancelled
+	PlacedAt     time.Time  `json:"placed_at" gorm:"not null"`
+	FulfilledAt  *time.Time `json:"fulfilled_at"`
+	CancelledAt  *time.Time `json:"cancelled_at"`
+	AnonymizedAt *time.Time `json:"anonymized_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Fine status values
+const (
+	FineOutstanding = "outstanding"
+	FinePaid        = "paid"
+	FineWaived      = "waived"
+)
+
+// Fine is money a borrower owes, charged when a loan is returned late.
+// Amount is in the currency's minor unit
+type Fine struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID    uuid.UUID  `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index"`
+	BorrowerID  uuid.UUID  `json:"borrower_id" gorm:"type:uuid;not null;index"`
+	BorrowingID *uuid.UUID `json:"borrowing_id" gorm:"type:uuid;index"`
+	Amount      int64      `json:"amount" gorm:"not null"`
+	Reason      string     `json:"reason" gorm:"not null"`
+	Status      string     `json:"status" gorm:"not null;index"` // outstanding, paid, waived
+	SettledAt   *time.Time `json:"settled_at"`
+	SettledBy   string     `json:"settled_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Transfer status values
+const (
+	TransferInTransit = "in_transit"
+	TransferReceived  = "received"
+)
+
+// Transfer tracks an item travelling back to its home branch after it was
+// checked in at another branch
+type Transfer struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID     uuid.UUID  `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index"`
+	BookID       uuid.UUID  `json:"book_id" gorm:"type:uuid;not null;index"`
+	Book         Book       `json:"book" gorm:"foreignKey:BookID"`
+	BorrowingID  *uuid.UUID `json:"borrowing_id" gorm:"type:uuid"`
+	FromBranchID uuid.UUID  `json:"from_branch_id" gorm:"type:uuid;not null;index"`
+	FromBranch   *Branch    `json:"from_branch,omitempty" gorm:"foreignKey:FromBranchID"`
+	ToBranchID   uuid.UUID  `json:"to_branch_id" gorm:"type:uuid;not null;index"`
+	ToBranch     *Branch    `json:"to_branch,omitempty" gorm:"foreignKey:ToBranchID"`
+	ToLocationID uuid.UUID  `json:"to_location_id" gorm:"type:uuid;not null"`
+	Status       string     `json:"status" gorm:"not null;index"` // in_transit, received
+	SentAt       time.Time  `json:"sent_at" gorm:"not null"`
+	ReceivedAt   *time.Time `json:"received_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// SIPTerminal is a self-check kiosk, RFID gate or other device that signs
+// in to the SIP2 server, see package sip2. Logins are unique across
+// tenants since a terminal's login is all that tells the server which
+// library it belongs to
+type SIPTerminal struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID uuid.UUID `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index"`
+	Name     string    `json:"name" gorm:"not null"`
+	Login    string    `json:"login" gorm:"uniqueIndex;not null"`
+	// PasswordHash is the bcrypt hash of the password the terminal signs in
+	// with
+	PasswordHash string `json:"-" gorm:"not null"`
+	// BranchID is where the terminal stands; items it checks out and in are
+	// recorded at that branch
+	BranchID    *uuid.UUID `json:"branch_id" gorm:"type:uuid;index"`
+	Active      bool       `json:"active" gorm:"not null"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName keeps gorm from naming the table s_ip_terminals.
+func (SIPTerminal) TableName() string {
+	return "sip_terminals"
+}
+
 // Request DTOs
 type CreateAuthorRequest struct {
 	Name      string `json:"name" binding:"required"`
@@ -71,20 +463,60 @@ type UpdateAuthorRequest struct {
 	Biography string `json:"biography"`
 }
 
+// ContributorRequest credits an author on a book; Role defaults to author
+type ContributorRequest struct {
+	AuthorID uuid.UUID `json:"author_id" binding:"required"`
+	Role     string    `json:"role" binding:"omitempty,oneof=author editor translator illustrator"`
+}
+
 type CreateBookRequest struct {
 	Title       string    `json:"title" binding:"required"`
 	ISBN        string    `json:"isbn" binding:"required"`
+	// Barcode is generated when omitted
+	Barcode     string    `json:"barcode" binding:"omitempty,max=32,printascii"`
 	Description string    `json:"description"`
-	AuthorID    uuid.UUID `json:"author_id" binding:"required"`
+	// Contributors are credited in the order given
+	Contributors []ContributorRequest `json:"contributors" binding:"required,min=1,dive"`
+	SubjectIDs  []uuid.UUID `json:"subject_ids"`
 	PublishedAt time.Time `json:"published_at"`
+	// HomeLocationID is optional; the item starts out shelved there
+	HomeLocationID uuid.UUID `json:"home_location_id"`
+	PublisherID    uuid.UUID `json:"publisher_id"`
+	Edition        string    `json:"edition"`
+	Format         string    `json:"format" binding:"omitempty,oneof=hardcover paperback ebook audiobook"`
+	Pages          int       `json:"pages" binding:"min=0"`
+	Language       string    `json:"language" binding:"omitempty,bcp47_language_tag"`
+	SeriesID       uuid.UUID `json:"series_id"`
+	SeriesVolume   int       `json:"series_volume" binding:"min=0"`
+	WorkID         uuid.UUID `json:"work_id"`
+	// ClassificationScheme is detected from the call number when omitted
+	CallNumber           string `json:"call_number"`
+	ClassificationScheme string `json:"classification_scheme" binding:"omitempty,oneof=dewey lc local"`
 }
 
 type UpdateBookRequest struct {
 	Title       string    `json:"title"`
 	ISBN        string    `json:"isbn"`
+	Barcode     string    `json:"barcode" binding:"omitempty,max=32,printascii"`
 	Description string    `json:"description"`
-	AuthorID    uuid.UUID `json:"author_id"`
+	// Contributors replaces the book's credits when given
+	Contributors []ContributorRequest `json:"contributors" binding:"omitempty,dive"`
+	// SubjectIDs replaces the book's subjects when given; an empty list
+	// removes them all
+	SubjectIDs  []uuid.UUID `json:"subject_ids"`
 	PublishedAt time.Time `json:"published_at"`
+	HomeLocationID uuid.UUID `json:"home_location_id"`
+	PublisherID    uuid.UUID `json:"publisher_id"`
+	Edition        string    `json:"edition"`
+	Format         string    `json:"format" binding:"omitempty,oneof=hardcover paperback ebook audiobook"`
+	Pages          *int      `json:"pages" binding:"omitempty,min=0"`
+	Language       string    `json:"language" binding:"omitempty,bcp47_language_tag"`
+	SeriesID       uuid.UUID `json:"series_id"`
+	SeriesVolume   *int      `json:"series_volume" binding:"omitempty,min=0"`
+	WorkID         uuid.UUID `json:"work_id"`
+	// ClassificationScheme alone reclassifies the current call number
+	CallNumber           string `json:"call_number"`
+	ClassificationScheme string `json:"classification_scheme" binding:"omitempty,oneof=dewey lc local"`
 }
 
 type CreateBorrowerRequest struct {
@@ -92,6 +524,10 @@ type CreateBorrowerRequest struct {
 	Email   string `json:"email" binding:"required,email"`
 	Phone   string `json:"phone"`
 	Address string `json:"address"`
+	CategoryID uuid.UUID `json:"category_id"`
+	// MembershipExpiresAt overrides the expiry given by the category
+	MembershipExpiresAt *time.Time `json:"membership_expires_at"`
+	KeepHistory bool `json:"keep_history"`
 }
 
 type UpdateBorrowerRequest struct {
@@ -99,14 +535,483 @@ type UpdateBorrowerRequest struct {
 	Email   string `json:"email"`
 	Phone   string `json:"phone"`
 	Address string `json:"address"`
+	CategoryID uuid.UUID `json:"category_id"`
+	Status     string    `json:"status" binding:"omitempty,oneof=active suspended"`
+	KeepHistory *bool    `json:"keep_history"`
+}
+
+// RenewMembershipRequest extends a membership by one term of the
+// borrower's category, or to ExpiresAt when given
+type RenewMembershipRequest struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type CreateBorrowerCategoryRequest struct {
+	Code           string `json:"code" binding:"required"`
+	Name           string `json:"name" binding:"required"`
+	MembershipDays int    `json:"membership_days" binding:"min=0"`
+	MaxActiveLoans int    `json:"max_active_loans" binding:"min=0"`
+}
+
+type UpdateBorrowerCategoryRequest struct {
+	Code           string `json:"code"`
+	Name           string `json:"name"`
+	MembershipDays *int   `json:"membership_days" binding:"omitempty,min=0"`
+	MaxActiveLoans *int   `json:"max_active_loans" binding:"omitempty,min=0"`
+}
+
+type CreateBorrowerBlockRequest struct {
+	Reason      string     `json:"reason" binding:"required,oneof=lost_card behaviour unpaid_fees other"`
+	Note        string     `json:"note"`
+	Overridable bool       `json:"overridable"`
+	ExpiresAt   *time.Time `json:"expires_at"`
 }
 
 type BorrowBookRequest struct {
 	BookID     uuid.UUID `json:"book_id" binding:"required"`
 	BorrowerID uuid.UUID `json:"borrower_id" binding:"required"`
 	DueDate    time.Time `json:"due_date" binding:"required"`
+	BranchID   uuid.UUID `json:"branch_id"`
+	// OverrideBlocks lets the loan go ahead despite overridable blocks
+	OverrideBlocks bool `json:"override_blocks"`
+}
+
+type RenewBorrowingRequest struct {
+	// OverrideBlocks lets the renewal go ahead despite overridable blocks
+	OverrideBlocks bool `json:"override_blocks"`
+}
+
+type PlaceHoldRequest struct {
+	BookID     uuid.UUID `json:"book_id" binding:"required"`
+	BorrowerID uuid.UUID `json:"borrower_id" binding:"required"`
+}
+
+// PlaceOwnHoldRequest is a signed-in borrower placing a hold for themselves
+type PlaceOwnHoldRequest struct {
+	BookID uuid.UUID `json:"book_id" binding:"required"`
+}
+
+type SetPINRequest struct {
+	PIN string `json:"pin" binding:"required,numeric,min=4,max=8"`
+}
+
+// LoginRequest signs a borrower in to the self-service API
+type LoginRequest struct {
+	CardNumber string `json:"card_number" binding:"required"`
+	PIN        string `json:"pin" binding:"required"`
+}
+
+// UpdateContactRequest holds the details borrowers may change themselves,
+// including whether their reading history is kept
+type UpdateContactRequest struct {
+	Email   string `json:"email" binding:"omitempty,email"`
+	Phone   string `json:"phone"`
+	Address string `json:"address"`
+	KeepHistory *bool `json:"keep_history"`
 }
 
 type ReturnBookRequest struct {
 	BorrowingID uuid.UUID `json:"borrowing_id" binding:"required"`
+	// BranchID is where the item is checked in; an item returned away from
+	// its home branch is sent back in transit
+	BranchID uuid.UUID `json:"branch_id"`
+}
+
+type CreateBranchRequest struct {
+	Code    string `json:"code" binding:"required"`
+	Name    string `json:"name" binding:"required"`
+	Address string `json:"address"`
+	Phone   string `json:"phone"`
+}
+
+type UpdateBranchRequest struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Phone   string `json:"phone"`
+}
+
+type CreateSIPTerminalRequest struct {
+	Name     string    `json:"name" binding:"required"`
+	Login    string    `json:"login" binding:"required,max=64"`
+	Password string    `json:"password" binding:"required,min=8"`
+	BranchID uuid.UUID `json:"branch_id"`
+}
+
+// UpdateSIPTerminalRequest leaves fields that are omitted unchanged
+type UpdateSIPTerminalRequest struct {
+	Name     string    `json:"name"`
+	Login    string    `json:"login" binding:"max=64"`
+	Password string    `json:"password" binding:"omitempty,min=8"`
+	BranchID uuid.UUID `json:"branch_id"`
+	Active   *bool     `json:"active"`
+}
+
+type CreateLocationRequest struct {
+	Code string `json:"code" binding:"required"`
+	Name string `json:"name" binding:"required"`
+}
+
+type UpdateLocationRequest struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type CreateSubjectRequest struct {
+	Name     string    `json:"name" binding:"required"`
+	ParentID uuid.UUID `json:"parent_id"`
+	Kind     string    `json:"kind" binding:"omitempty,oneof=topic genre"`
+	Source   string    `json:"source"`
+}
+
+// UpdateSubjectRequest leaves fields that are omitted unchanged. ParentID
+// moves the subject under another one, or to the top level when it is the
+// nil UUID.
+type UpdateSubjectRequest struct {
+	Name     string     `json:"name"`
+	ParentID *uuid.UUID `json:"parent_id"`
+	Kind     string     `json:"kind" binding:"omitempty,oneof=topic genre"`
+	Source   *string    `json:"source"`
+}
+
+type CreatePublisherRequest struct {
+	Name    string `json:"name" binding:"required"`
+	Place   string `json:"place"`
+	Website string `json:"website" binding:"omitempty,url"`
+}
+
+type UpdatePublisherRequest struct {
+	Name    string `json:"name"`
+	Place   string `json:"place"`
+	Website string `json:"website" binding:"omitempty,url"`
+}
+
+type CreateSeriesRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+type UpdateSeriesRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type CreateWorkRequest struct {
+	Title string `json:"title" binding:"required"`
+}
+
+type UpdateWorkRequest struct {
+	Title string `json:"title"`
+}
+
+// TenantSettingsRequest leaves settings that are omitted unchanged, or at
+// their defaults for a new tenant
+type TenantSettingsRequest struct {
+	MaxActiveLoans        *int   `json:"max_active_loans" binding:"omitempty,min=1"`
+	BlockOverdueBorrowers *bool  `json:"block_overdue_borrowers"`
+	LoanPeriodDays        *int   `json:"loan_period_days" binding:"omitempty,min=1"`
+	MaxRenewals           *int   `json:"max_renewals" binding:"omitempty,min=0"`
+	FinePerDay            *int64 `json:"fine_per_day" binding:"omitempty,min=0"`
+}
+
+type CreateTenantRequest struct {
+	Slug string `json:"slug" binding:"required"`
+	Name string `json:"name" binding:"required"`
+	TenantSettingsRequest
+}
+
+// UpdateTenantRequest leaves fields that are omitted unchanged
+type UpdateTenantRequest struct {
+	Name   string `json:"name"`
+	Active *bool  `json:"active"`
+	TenantSettingsRequest
+}
+
+// BookFilter narrows book listings and exports. Zero values do not filter.
+type BookFilter struct {
+	Search string
+	// AuthorID matches books the author is credited on in any role
+	AuthorID uuid.UUID
+	// BranchID matches books currently shelved at the branch, HomeBranchID
+	// books that belong to it
+	BranchID     uuid.UUID
+	HomeBranchID uuid.UUID
+	// SubjectID matches books tagged with the subject or any narrower one
+	SubjectID uuid.UUID
+	PublisherID uuid.UUID
+	// SeriesID lists a series, in volume order
+	SeriesID uuid.UUID
+	WorkID   uuid.UUID
+	Format   string
+	Language string
+	// Classification matches books whose call numbers are in the scheme.
+	// CallNumberFrom and CallNumberTo bound the call numbers inclusively,
+	// CallNumberTo covering any that extend it, and restrict the listing to
+	// the scheme they are written in.
+	Classification string
+	CallNumberFrom string
+	CallNumberTo   string
+	// ShelfOrder lists books by call number, NewestFirst by when they were
+	// added to the catalog
+	ShelfOrder  bool
+	NewestFirst bool
+	// Query matches books against a boolean search
+	Query *BookQuery
+}
+
+// Fields a BookQuery term searches
+const (
+	SearchAny     = "any"
+	SearchTitle   = "title"
+	SearchAuthor  = "author"
+	SearchISBN    = "isbn"
+	SearchSubject = "subject"
+)
+
+// Boolean operators of a BookQuery
+const (
+	QueryAnd = "and"
+	QueryOr  = "or"
+	QueryNot = "not"
+)
+
+// BookQuery is a boolean search of the catalog, such as a parsed CQL
+// query. A term (no Op) matches books whose Field contains Term, or equals
+// it when Exact, ignoring case; * in Term stands for any characters. An
+// operator combines Left and Right, QueryNot matching the books Left
+// matches and Right does not.
+type BookQuery struct {
+	Op    string
+	Left  *BookQuery
+	Right *BookQuery
+	Field string
+	Term  string
+	Exact bool
+}
+
+// SubjectFilter narrows subject listings. Zero values do not filter.
+type SubjectFilter struct {
+	// Search matches subject names
+	Search string
+	// ParentID lists the children of a subject; TopLevel lists subjects
+	// without a parent
+	ParentID uuid.UUID
+	TopLevel bool
+}
+
+// SubjectImportResult summarises a MARC subject import
+type SubjectImportResult struct {
+	Records         int `json:"records"`
+	Headings        int `json:"headings"`
+	SubjectsCreated int `json:"subjects_created"`
+	BooksTagged     int `json:"books_tagged"`
+}
+
+// BorrowingFilter narrows borrowing listings and exports. Zero values do
+// not filter.
+type BorrowingFilter struct {
+	BorrowerID uuid.UUID
+	BookID     uuid.UUID
+	// BranchID matches loans checked out at the branch
+	BranchID    uuid.UUID
+	OverdueOnly bool
+	// ActiveOnly matches loans that have not been returned
+	ActiveOnly bool
+}
+
+// BorrowerStanding sums up a borrower's account the way self-check
+// terminals report it: whether they may borrow and renew, and the loans
+// and fines that count against them
+type BorrowerStanding struct {
+	CanBorrow bool `json:"can_borrow"`
+	CanRenew  bool `json:"can_renew"`
+	// Reason says what stops the borrower borrowing
+	Reason         string `json:"reason,omitempty"`
+	CardLost       bool   `json:"card_lost"`
+	ActiveLoans    int64  `json:"active_loans"`
+	MaxActiveLoans int    `json:"max_active_loans"`
+	OverdueLoans   int64  `json:"overdue_loans"`
+	// OutstandingFines totals the borrower's unpaid fines
+	OutstandingFines int64 `json:"outstanding_fines"`
+}
+
+// TransferFilter narrows transfer listings. Zero values do not filter.
+type TransferFilter struct {
+	FromBranchID uuid.UUID
+	ToBranchID   uuid.UUID
+	Status       string
+}
+
+// BorrowerData is everything held about a borrower, as exported in answer
+// to a subject access request
+type BorrowerData struct {
+	GeneratedAt time.Time       `json:"generated_at"`
+	Borrower    *Borrower       `json:"borrower"`
+	Loans       []Borrowing     `json:"loans"`
+	Fines       []Fine          `json:"fines"`
+	Holds       []Hold          `json:"holds"`
+	Blocks      []BorrowerBlock `json:"blocks"`
+	AuditLog    []AuditLog      `json:"audit_log"`
+}
+
+type AuditLogFilter struct {
+	EntityType string
+	EntityID   string
+	Actor      string
+	From       time.Time
+	To         time.Time
+}
+
+// IdempotencyKey stores the outcome of a POST request so that retries
+// carrying the same Idempotency-Key header replay the original response
+type IdempotencyKey struct {
+	Key          string    `json:"key" gorm:"primary_key"`
+	Method       string    `json:"method" gorm:"not null"`
+	Path         string    `json:"path" gorm:"not null"`
+	RequestHash  string    `json:"request_hash" gorm:"not null"`
+	StatusCode   int       `json:"status_code"` // 0 while the original request is still in flight
+	ContentType  string    `json:"content_type"`
+	ResponseBody []byte    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at" gorm:"index;not null"`
+}
+
+// Batch DTOs
+const (
+	BatchModeAtomic      = "atomic"      // all operations succeed or none are applied
+	BatchModeIndependent = "independent" // each operation is applied on its own
+)
+
+type BookBatchOperation struct {
+	Op     string             `json:"op" binding:"required,oneof=create update delete"`
+	ID     uuid.UUID          `json:"id"`
+	Create *CreateBookRequest `json:"create"`
+	Update *UpdateBookRequest `json:"update"`
+}
+
+type BookBatchRequest struct {
+	Mode       string               `json:"mode" binding:"omitempty,oneof=atomic independent"`
+	Operations []BookBatchOperation `json:"operations" binding:"required,min=1,max=1000,dive"`
+}
+
+type BorrowerBatchOperation struct {
+	Op     string                 `json:"op" binding:"required,oneof=create update delete"`
+	ID     uuid.UUID              `json:"id"`
+	Create *CreateBorrowerRequest `json:"create"`
+	Update *UpdateBorrowerRequest `json:"update"`
+}
+
+type BorrowerBatchRequest struct {
+	Mode       string                   `json:"mode" binding:"omitempty,oneof=atomic independent"`
+	Operations []BorrowerBatchOperation `json:"operations" binding:"required,min=1,max=1000,dive"`
+}
+
+type BatchItemResult struct {
+	Index   int         `json:"index"`
+	Op      string      `json:"op"`
+	Success bool        `json:"success"`
+	Error   string      `json:"error,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+type BatchResponse struct {
+	Mode      string            `json:"mode"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
+
+// Label DTOs
+
+// LabelLayout describes a sheet of labels arranged in a grid, in
+// millimetres; see package labels
+type LabelLayout struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	PageWidth   float64 `json:"page_width"`
+	PageHeight  float64 `json:"page_height"`
+	Columns     int     `json:"columns"`
+	Rows        int     `json:"rows"`
+	LabelWidth  float64 `json:"label_width"`
+	LabelHeight float64 `json:"label_height"`
+	TopMargin   float64 `json:"top_margin"`
+	LeftMargin  float64 `json:"left_margin"`
+	ColumnPitch float64 `json:"column_pitch"`
+	RowPitch    float64 `json:"row_pitch"`
+}
+
+// LabelSheetOptions chooses the sheet labels are printed on: a named
+// Layout, or CustomLayout for a sheet that is not built in. Skip leaves
+// the first labels of a partly used sheet blank.
+type LabelSheetOptions struct {
+	Layout       string       `json:"layout"`
+	CustomLayout *LabelLayout `json:"custom_layout"`
+	Symbology    string       `json:"symbology" binding:"omitempty,oneof=code128 code39"`
+	Skip         int          `json:"skip" binding:"min=0"`
+}
+
+type BookLabelsRequest struct {
+	BookIDs []uuid.UUID `json:"book_ids" binding:"required,min=1,max=1000"`
+	LabelSheetOptions
+}
+
+type BorrowerLabelsRequest struct {
+	BorrowerIDs []uuid.UUID `json:"borrower_ids" binding:"required,min=1,max=1000"`
+	LabelSheetOptions
+}
+
+// Receipt DTOs
+
+// Receipt kinds, one for each circulation transaction
+const (
+	ReceiptCheckout = "checkout"
+	ReceiptRenewal  = "renewal"
+	ReceiptReturn   = "return"
+)
+
+// Receipt is the slip given to a borrower for checking out, renewing or
+// returning an item, built from the loan as it stands; see package
+// receipt. Once the loan is anonymized the borrower, their other loans and
+// their balance are left out
+type Receipt struct {
+	Kind        string    `json:"kind"` // checkout, renewal, return
+	BorrowingID uuid.UUID `json:"borrowing_id"`
+	Library     string    `json:"library"`
+	Branch      string    `json:"branch,omitempty"`
+	// Date is when the item was checked out, last renewed or returned
+	Date         time.Time `json:"date"`
+	BorrowerName string    `json:"borrower_name,omitempty"`
+	// CardNumber shows only the last four digits of the library card
+	CardNumber string      `json:"card_number,omitempty"`
+	Item       ReceiptItem `json:"item"`
+	// Fine is what returning the item late was charged
+	Fine int64 `json:"fine"`
+	// OnLoan lists the borrower's other items that are still out
+	OnLoan []ReceiptItem `json:"on_loan"`
+	// Balance totals the borrower's outstanding fines
+	Balance int64 `json:"balance"`
+}
+
+// ReceiptItem is a book on a receipt
+type ReceiptItem struct {
+	Title        string     `json:"title"`
+	Barcode      string     `json:"barcode"`
+	CallNumber   string     `json:"call_number,omitempty"`
+	DueDate      time.Time  `json:"due_date"`
+	ReturnedAt   *time.Time `json:"returned_at,omitempty"`
+	RenewalCount int        `json:"renewal_count"`
+	Overdue      bool       `json:"overdue"`
+}
+
+// AuditLog records a single create, update or delete of an audited entity
+type AuditLog struct {
+	ID         uuid.UUID       `json:"id" gorm:"type:uuid;primary_key"`
+	TenantID   uuid.UUID       `json:"-" gorm:"type:uuid;not null;default:'00000000-0000-0000-0000-000000000000';index"`
+	Actor      string          `json:"actor" gorm:"index;not null"`
+	Action     string          `json:"action" gorm:"not null"` // create, update, delete
+	EntityType string          `json:"entity_type" gorm:"index:idx_audit_entity;not null"`
+	EntityID   string          `json:"entity_id" gorm:"index:idx_audit_entity"` // for join tables, the record they belong to
+	RequestID  string          `json:"request_id" gorm:"index"`
+	Changes    json.RawMessage `json:"changes" gorm:"type:jsonb"`
+	CreatedAt  time.Time       `json:"created_at" gorm:"index"`
 }
@@ -0,0 +1,299 @@
+package opds
+
+import (
+	"encoding/xml"
+	"time"
+	"unicode/utf8"
+)
+
+// Media types of OPDS 1.2 documents.
+const (
+	NavigationType  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	AcquisitionType = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	EntryType       = "application/atom+xml;type=entry;profile=opds-catalog"
+	OpenSearchType  = "application/opensearchdescription+xml"
+)
+
+const (
+	atomNS       = "http://www.w3.org/2005/Atom"
+	dcNS         = "http://purl.org/dc/terms/"
+	opdsNS       = "http://opds-spec.org/2010/catalog"
+	openSearchNS = "http://a9.com/-/spec/opensearch/1.1/"
+)
+
+// openSearchPath is where the OpenSearch description of an OPDS 1.2
+// catalog is served.
+const openSearchPath = "/opensearch.xml"
+
+// namespaces declares the namespaces of a feed or entry document.
+type namespaces struct {
+	Atom       string `xml:"xmlns,attr"`
+	DC         string `xml:"xmlns:dc,attr"`
+	OPDS       string `xml:"xmlns:opds,attr"`
+	OpenSearch string `xml:"xmlns:opensearch,attr"`
+}
+
+var declared = namespaces{Atom: atomNS, DC: dcNS, OPDS: opdsNS, OpenSearch: openSearchNS}
+
+type atomFeed struct {
+	XMLName xml.Name `xml:"feed"`
+	namespaces
+	ID           string      `xml:"id"`
+	Title        string      `xml:"title"`
+	Updated      string      `xml:"updated"`
+	TotalResults *int64      `xml:"opensearch:totalResults"`
+	ItemsPerPage *int        `xml:"opensearch:itemsPerPage"`
+	StartIndex   *int        `xml:"opensearch:startIndex"`
+	Links        []atomLink  `xml:"link"`
+	Entries      []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title        string         `xml:"title"`
+	ID           string         `xml:"id"`
+	Updated      string         `xml:"updated"`
+	Authors      []atomPerson   `xml:"author"`
+	Contributors []atomPerson   `xml:"contributor"`
+	Identifier   string         `xml:"dc:identifier,omitempty"`
+	Language     string         `xml:"dc:language,omitempty"`
+	Publisher    string         `xml:"dc:publisher,omitempty"`
+	Issued       string         `xml:"dc:issued,omitempty"`
+	Categories   []atomCategory `xml:"category"`
+	Summary      *atomText      `xml:"summary"`
+	Content      *atomText      `xml:"content"`
+	Links        []atomLink     `xml:"link"`
+}
+
+// atomEntryDocument is a complete entry served on its own.
+type atomEntryDocument struct {
+	XMLName xml.Name `xml:"entry"`
+	namespaces
+	atomEntry
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type atomLink struct {
+	Rel          string            `xml:"rel,attr,omitempty"`
+	Href         string            `xml:"href,attr"`
+	Type         string            `xml:"type,attr,omitempty"`
+	Title        string            `xml:"title,attr,omitempty"`
+	FacetGroup   string            `xml:"opds:facetGroup,attr,omitempty"`
+	Availability *atomAvailability `xml:"opds:availability"`
+	Holds        *atomHolds        `xml:"opds:holds"`
+	Copies       *atomCopies       `xml:"opds:copies"`
+}
+
+type atomAvailability struct {
+	Status string `xml:"status,attr"`
+}
+
+type atomHolds struct {
+	Total int64 `xml:"total,attr"`
+}
+
+type atomCopies struct {
+	Total     int `xml:"total,attr"`
+	Available int `xml:"available,attr"`
+}
+
+// atomTypes are the media types of the catalog's documents in OPDS 1.2.
+var atomTypes = map[string]string{
+	Navigation:  NavigationType,
+	Acquisition: AcquisitionType,
+	Entry:       EntryType,
+	Search:      OpenSearchType,
+}
+
+// resolve returns the URL of the catalog path under root, the catalog's
+// own URL for the start feed.
+func resolve(root, path string) string {
+	if path == "/" {
+		return root
+	}
+	return root + path
+}
+
+func (l Link) atom(root string) atomLink {
+	if l.Path == "" {
+		return atomLink{Rel: l.Rel, Href: l.Href, Type: l.Type, Title: l.Title}
+	}
+	path := l.Path
+	if l.Kind == Search {
+		path = openSearchPath
+	}
+	return atomLink{Rel: l.Rel, Href: resolve(root, path), Type: atomTypes[l.Kind], Title: l.Title, FacetGroup: l.FacetGroup}
+}
+
+func timestamp(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// commonLinks returns the links every feed has besides its own: self,
+// start, search and pagination.
+func commonLinks(f *Feed) []Link {
+	links := []Link{
+		{Rel: RelSelf, Path: f.Path, Kind: f.Kind},
+		{Rel: RelStart, Path: "/", Kind: Navigation},
+	}
+	links = append(links, f.Links...)
+	links = append(links, Link{Rel: RelSearch, Path: "/search", Kind: Search})
+	return append(links, pageLinks(f)...)
+}
+
+// Atom renders f as an OPDS 1.2 feed of the catalog at root.
+func Atom(f *Feed, root string) ([]byte, error) {
+	feed := atomFeed{
+		namespaces: declared,
+		ID:         resolve(root, f.Path),
+		Title:      f.Title,
+		Updated:    timestamp(f.Updated),
+	}
+	if p := f.Page; p != nil {
+		start := (p.Number-1)*p.Limit + 1
+		feed.TotalResults, feed.ItemsPerPage, feed.StartIndex = &p.Total, &p.Limit, &start
+	}
+	for _, l := range commonLinks(f) {
+		feed.Links = append(feed.Links, l.atom(root))
+	}
+	for _, l := range f.Facets {
+		feed.Links = append(feed.Links, l.atom(root))
+	}
+
+	for _, e := range f.Navigation {
+		entry := atomEntry{
+			Title:   e.Title,
+			ID:      e.ID,
+			Updated: timestamp(latest(e.Updated, f.Updated)),
+			Links:   []atomLink{e.Link.atom(root)},
+		}
+		if e.Summary != "" {
+			entry.Content = &atomText{Type: "text", Text: e.Summary}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	for _, p := range f.Publications {
+		feed.Entries = append(feed.Entries, p.atom(root))
+	}
+
+	return marshalXML(feed)
+}
+
+// AtomEntry renders p as a complete OPDS 1.2 entry of the catalog at root.
+func AtomEntry(p Publication, root string) ([]byte, error) {
+	entry := p.atom(root)
+	entry.Links = append([]atomLink{Link{Rel: RelSelf, Path: p.Path, Kind: Entry}.atom(root)}, entry.Links...)
+	return marshalXML(atomEntryDocument{namespaces: declared, atomEntry: entry})
+}
+
+func (p Publication) atom(root string) atomEntry {
+	entry := atomEntry{
+		Title:      p.Title,
+		ID:         p.ID,
+		Updated:    timestamp(p.Updated),
+		Identifier: p.ISBN,
+		Language:   p.Language,
+		Publisher:  p.Publisher,
+	}
+	for _, c := range p.Authors {
+		entry.Authors = append(entry.Authors, atomPerson{Name: c.Name, URI: resolve(root, c.Path)})
+	}
+	for _, c := range p.Contributors {
+		entry.Contributors = append(entry.Contributors, atomPerson{Name: c.Name, URI: resolve(root, c.Path)})
+	}
+	if !p.Issued.IsZero() {
+		entry.Issued = p.Issued.Format("2006-01-02")
+	}
+	for _, s := range p.Subjects {
+		entry.Categories = append(entry.Categories, atomCategory{Term: s.Name, Label: s.Name})
+	}
+	if p.Summary != "" {
+		entry.Summary = &atomText{Type: "text", Text: p.Summary}
+	}
+
+	if p.Cover != "" {
+		entry.Links = append(entry.Links, atomLink{Rel: RelImage, Href: p.Cover, Type: p.CoverType})
+	}
+	if p.Thumbnail != "" {
+		entry.Links = append(entry.Links, atomLink{Rel: RelThumbnail, Href: p.Thumbnail, Type: "image/jpeg"})
+	}
+	borrow := Link{Rel: RelBorrow, Path: p.Path, Kind: Entry}.atom(root)
+	borrow.Availability = &atomAvailability{Status: p.Availability.State}
+	borrow.Holds = &atomHolds{Total: p.Availability.Holds}
+	borrow.Copies = &atomCopies{Total: 1, Available: p.Availability.copies()}
+	entry.Links = append(entry.Links, borrow)
+	return entry
+}
+
+// copies is how many copies of the book are on the shelf.
+func (a Availability) copies() int {
+	if a.State == Available {
+		return 1
+	}
+	return 0
+}
+
+type openSearchDescription struct {
+	XMLName        xml.Name      `xml:"OpenSearchDescription"`
+	Xmlns          string        `xml:"xmlns,attr"`
+	ShortName      string        `xml:"ShortName"`
+	Description    string        `xml:"Description"`
+	InputEncoding  string        `xml:"InputEncoding"`
+	OutputEncoding string        `xml:"OutputEncoding"`
+	URL            openSearchURL `xml:"Url"`
+}
+
+type openSearchURL struct {
+	Type       string `xml:"type,attr"`
+	Template   string `xml:"template,attr"`
+	PageOffset int    `xml:"pageOffset,attr"`
+}
+
+// maxShortName is the longest short name OpenSearch allows.
+const maxShortName = 16
+
+// OpenSearch renders the OpenSearch description of the search of the
+// OPDS 1.2 catalog called title at root.
+func OpenSearch(title, root string) ([]byte, error) {
+	short := title
+	for utf8.RuneCountInString(short) > maxShortName {
+		_, size := utf8.DecodeLastRuneInString(short)
+		short = short[:len(short)-size]
+	}
+	return marshalXML(openSearchDescription{
+		Xmlns:          openSearchNS,
+		ShortName:      short,
+		Description:    "Search the catalog of " + title,
+		InputEncoding:  "UTF-8",
+		OutputEncoding: "UTF-8",
+		URL: openSearchURL{
+			Type:       AcquisitionType,
+			Template:   root + "/search?q={searchTerms}&page={startPage?}&limit={count?}",
+			PageOffset: 1,
+		},
+	})
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,233 @@
+package opds
+
+import (
+	"encoding/json"
+
+	"library-management-go/internal/models"
+)
+
+// Media types of OPDS 2.0 documents.
+const (
+	FeedJSONType        = "application/opds+json"
+	PublicationJSONType = "application/opds-publication+json"
+)
+
+// bookType is the schema.org type of every publication.
+const bookType = "http://schema.org/Book"
+
+type jsonFeed struct {
+	Metadata   jsonFeedMetadata `json:"metadata"`
+	Links      []jsonLink       `json:"links"`
+	Facets     []jsonFacet      `json:"facets,omitempty"`
+	Navigation []jsonLink       `json:"navigation,omitempty"`
+	// Publications is present in every acquisition feed, even an empty one
+	Publications *[]jsonPublication `json:"publications,omitempty"`
+}
+
+type jsonFeedMetadata struct {
+	Title         string `json:"title"`
+	Modified      string `json:"modified"`
+	NumberOfItems *int64 `json:"numberOfItems,omitempty"`
+	ItemsPerPage  int    `json:"itemsPerPage,omitempty"`
+	CurrentPage   int    `json:"currentPage,omitempty"`
+}
+
+type jsonFacet struct {
+	Metadata struct {
+		Title string `json:"title"`
+	} `json:"metadata"`
+	Links []jsonLink `json:"links"`
+}
+
+type jsonLink struct {
+	Rel        string          `json:"rel,omitempty"`
+	Href       string          `json:"href"`
+	Type       string          `json:"type,omitempty"`
+	Title      string          `json:"title,omitempty"`
+	Templated  bool            `json:"templated,omitempty"`
+	Properties *jsonProperties `json:"properties,omitempty"`
+}
+
+type jsonProperties struct {
+	Availability *jsonAvailability `json:"availability,omitempty"`
+	Holds        *jsonHolds        `json:"holds,omitempty"`
+	Copies       *jsonCopies       `json:"copies,omitempty"`
+}
+
+type jsonAvailability struct {
+	State string `json:"state"`
+}
+
+type jsonHolds struct {
+	Total int64 `json:"total"`
+}
+
+type jsonCopies struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+}
+
+type jsonPublication struct {
+	Metadata jsonMetadata `json:"metadata"`
+	Links    []jsonLink   `json:"links"`
+	Images   []jsonLink   `json:"images,omitempty"`
+}
+
+type jsonMetadata struct {
+	Type        string            `json:"@type"`
+	Identifier  string            `json:"identifier"`
+	Title       string            `json:"title"`
+	Author      []jsonContributor `json:"author,omitempty"`
+	Editor      []jsonContributor `json:"editor,omitempty"`
+	Translator  []jsonContributor `json:"translator,omitempty"`
+	Illustrator []jsonContributor `json:"illustrator,omitempty"`
+	Contributor []jsonContributor `json:"contributor,omitempty"`
+	Publisher   string            `json:"publisher,omitempty"`
+	Language    string            `json:"language,omitempty"`
+	Published   string            `json:"published,omitempty"`
+	Modified    string            `json:"modified"`
+	Description string            `json:"description,omitempty"`
+	Subject     []jsonContributor `json:"subject,omitempty"`
+	BelongsTo   *jsonBelongsTo    `json:"belongsTo,omitempty"`
+}
+
+// jsonContributor names a contributor or subject, linking to its books.
+type jsonContributor struct {
+	Name  string     `json:"name"`
+	Links []jsonLink `json:"links,omitempty"`
+}
+
+type jsonBelongsTo struct {
+	Series []jsonSeries `json:"series"`
+}
+
+type jsonSeries struct {
+	Name     string `json:"name"`
+	Position int    `json:"position,omitempty"`
+}
+
+// jsonTypes are the media types of the catalog's documents in OPDS 2.0.
+var jsonTypes = map[string]string{
+	Navigation:  FeedJSONType,
+	Acquisition: FeedJSONType,
+	Entry:       PublicationJSONType,
+	Search:      FeedJSONType,
+}
+
+func (l Link) json(root string) jsonLink {
+	if l.Path == "" {
+		return jsonLink{Rel: l.Rel, Href: l.Href, Type: l.Type, Title: l.Title}
+	}
+	link := jsonLink{Rel: l.Rel, Href: resolve(root, l.Path), Type: jsonTypes[l.Kind], Title: l.Title}
+	// Search is a templated link rather than a description of one
+	if l.Kind == Search {
+		link.Href, link.Templated = root+"/search{?q}", true
+	}
+	return link
+}
+
+// JSON renders f as an OPDS 2.0 feed of the catalog at root.
+func JSON(f *Feed, root string) ([]byte, error) {
+	feed := jsonFeed{Metadata: jsonFeedMetadata{Title: f.Title, Modified: timestamp(f.Updated)}}
+	if p := f.Page; p != nil {
+		feed.Metadata.NumberOfItems, feed.Metadata.ItemsPerPage, feed.Metadata.CurrentPage = &p.Total, p.Limit, p.Number
+	}
+	for _, l := range commonLinks(f) {
+		feed.Links = append(feed.Links, l.json(root))
+	}
+
+	// Facets are listed by group, in the order the groups first appear
+	for _, l := range f.Facets {
+		i := len(feed.Facets) - 1
+		if i < 0 || feed.Facets[i].Metadata.Title != l.FacetGroup {
+			feed.Facets = append(feed.Facets, jsonFacet{})
+			i++
+			feed.Facets[i].Metadata.Title = l.FacetGroup
+		}
+		link := l.json(root)
+		link.Rel = ""
+		feed.Facets[i].Links = append(feed.Facets[i].Links, link)
+	}
+
+	for _, e := range f.Navigation {
+		link := e.Link.json(root)
+		link.Title = e.Title
+		feed.Navigation = append(feed.Navigation, link)
+	}
+	if f.Kind == Acquisition {
+		publications := []jsonPublication{}
+		for _, p := range f.Publications {
+			publications = append(publications, p.json(root))
+		}
+		feed.Publications = &publications
+	}
+
+	return json.Marshal(feed)
+}
+
+// JSONPublication renders p as an OPDS 2.0 publication of the catalog at
+// root.
+func JSONPublication(p Publication, root string) ([]byte, error) {
+	return json.Marshal(p.json(root))
+}
+
+func (p Publication) json(root string) jsonPublication {
+	meta := jsonMetadata{
+		Type:        bookType,
+		Identifier:  p.ID,
+		Title:       p.Title,
+		Publisher:   p.Publisher,
+		Language:    p.Language,
+		Modified:    timestamp(p.Updated),
+		Description: p.Summary,
+	}
+	if p.ISBN != "" {
+		meta.Identifier = p.ISBN
+	}
+	if !p.Issued.IsZero() {
+		meta.Published = p.Issued.Format("2006-01-02")
+	}
+	// Contributors and subjects link to the feeds of their books
+	contributor := func(name, path string) jsonContributor {
+		return jsonContributor{Name: name, Links: []jsonLink{{Href: resolve(root, path), Type: FeedJSONType}}}
+	}
+	for _, c := range p.Authors {
+		meta.Author = append(meta.Author, contributor(c.Name, c.Path))
+	}
+	for _, c := range p.Contributors {
+		role := &meta.Contributor
+		switch c.Role {
+		case models.RoleEditor:
+			role = &meta.Editor
+		case models.RoleTranslator:
+			role = &meta.Translator
+		case models.RoleIllustrator:
+			role = &meta.Illustrator
+		}
+		*role = append(*role, contributor(c.Name, c.Path))
+	}
+	for _, s := range p.Subjects {
+		meta.Subject = append(meta.Subject, contributor(s.Name, s.Path))
+	}
+	if p.Series != "" {
+		meta.BelongsTo = &jsonBelongsTo{Series: []jsonSeries{{Name: p.Series, Position: p.SeriesPosition}}}
+	}
+
+	pub := jsonPublication{Metadata: meta}
+	pub.Links = append(pub.Links, Link{Rel: RelSelf, Path: p.Path, Kind: Entry}.json(root))
+	borrow := Link{Rel: RelBorrow, Path: p.Path, Kind: Entry}.json(root)
+	borrow.Properties = &jsonProperties{
+		Availability: &jsonAvailability{State: p.Availability.State},
+		Holds:        &jsonHolds{Total: p.Availability.Holds},
+		Copies:       &jsonCopies{Total: 1, Available: p.Availability.copies()},
+	}
+	pub.Links = append(pub.Links, borrow)
+
+	if p.Cover != "" {
+		pub.Images = append(pub.Images, jsonLink{Href: p.Cover, Type: p.CoverType})
+	}
+	if p.Thumbnail != "" {
+		pub.Images = append(pub.Images, jsonLink{Href: p.Thumbnail, Type: "image/jpeg"})
+	}
+	return pub
+}
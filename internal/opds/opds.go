@@ -0,0 +1,381 @@
+// Package opds builds catalog feeds for e-reader apps and renders them as
+// OPDS 1.2 (Atom) and OPDS 2.0 (JSON).
+//
+// Feeds are built once from the catalog and rendered in either format, so
+// both carry the same entries. Links within the catalog are paths relative
+// to the catalog's root, which each renderer resolves to its own URLs and
+// media types:
+//
+//	/                  the start feed, navigating to the feeds below
+//	/new               every book, newest first
+//	/authors           authors by name
+//	/authors/{id}      the books an author is credited on
+//	/subjects          the top-level subjects
+//	/subjects/{id}     the books under a subject, with narrower subjects as facets
+//	/search?q={terms}  books matching a search
+//	/books/{id}        a single book
+//	/opensearch.xml    the OpenSearch description of /search (OPDS 1.2)
+package opds
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of catalog documents a link can lead to.
+const (
+	Navigation  = "navigation"
+	Acquisition = "acquisition"
+	Entry       = "entry"
+	// Search links to the catalog's search: an OpenSearch description in
+	// OPDS 1.2, a templated link in OPDS 2.0
+	Search = "search"
+)
+
+// Link relations.
+const (
+	RelSelf       = "self"
+	RelStart      = "start"
+	RelUp         = "up"
+	RelSubsection = "subsection"
+	RelSearch     = "search"
+	RelFirst      = "first"
+	RelPrevious   = "previous"
+	RelNext       = "next"
+	RelLast       = "last"
+	RelSortNew    = "http://opds-spec.org/sort/new"
+	RelFacet      = "http://opds-spec.org/facet"
+	RelBorrow     = "http://opds-spec.org/acquisition/borrow"
+	RelImage      = "http://opds-spec.org/image"
+	RelThumbnail  = "http://opds-spec.org/image/thumbnail"
+)
+
+// DefaultLimit is the number of entries of a page of a feed unless the
+// client asks for another.
+const DefaultLimit = 25
+
+// thumbnailSize is the cover thumbnail feeds link to.
+const thumbnailSize = "small"
+
+// Availability states of a book.
+const (
+	Available   = "available"
+	Unavailable = "unavailable"
+	// Reserved books have patrons waiting for them
+	Reserved = "reserved"
+)
+
+// Link is a link of a feed or entry. Links within the catalog have a Path
+// and the Kind of document it leads to; links elsewhere an Href and Type.
+type Link struct {
+	Rel   string
+	Path  string
+	Kind  string
+	Href  string
+	Type  string
+	Title string
+	// FacetGroup groups facet links
+	FacetGroup string
+}
+
+// Page places a feed within a longer list.
+type Page struct {
+	Number int
+	Limit  int
+	Total  int64
+}
+
+// Feed is a navigation or acquisition feed.
+type Feed struct {
+	// Path is the feed's own path, query included
+	Path    string
+	Title   string
+	Kind    string
+	Updated time.Time
+	// Links holds any links besides self, start, search and pagination,
+	// which every feed gets
+	Links        []Link
+	Navigation   []NavigationEntry
+	Publications []Publication
+	Facets       []Link
+	// Page is set when the feed is one page of a longer list
+	Page *Page
+}
+
+// NavigationEntry leads from a navigation feed to another feed.
+type NavigationEntry struct {
+	ID      string
+	Title   string
+	Summary string
+	Updated time.Time
+	Link    Link
+}
+
+// Contributor is a person credited on a book, with a path to their books.
+type Contributor struct {
+	Name string
+	Role string
+	Path string
+}
+
+// Subject is a subject a book is tagged with.
+type Subject struct {
+	Name string
+	Path string
+}
+
+// Availability is whether a book is on the shelf, and how many patrons
+// are waiting for it. Each book is a single copy.
+type Availability struct {
+	State string
+	Holds int64
+}
+
+// Publication is an entry of an acquisition feed: a book.
+type Publication struct {
+	ID           string
+	Path         string
+	Title        string
+	Updated      time.Time
+	Authors      []Contributor
+	Contributors []Contributor
+	Summary      string
+	ISBN         string
+	Language     string
+	Publisher    string
+	Issued       time.Time
+	Subjects     []Subject
+	Series       string
+	// SeriesPosition numbers the book within its series, 0 when unnumbered
+	SeriesPosition int
+	// Cover and Thumbnail are image URLs, CoverType the cover's media type
+	Cover        string
+	CoverType    string
+	Thumbnail    string
+	Availability Availability
+}
+
+func bookPath(book *models.Book) string {
+	return "/books/" + book.ID.String()
+}
+
+func authorPath(author *models.Author) string {
+	return "/authors/" + author.ID.String()
+}
+
+func subjectPath(subject *models.Subject) string {
+	return "/subjects/" + subject.ID.String()
+}
+
+// NewPublication describes book, on which holds patrons are waiting.
+func NewPublication(book *models.Book, holds int64) Publication {
+	p := Publication{
+		ID:             "urn:uuid:" + book.ID.String(),
+		Path:           bookPath(book),
+		Title:          book.Title,
+		Updated:        book.UpdatedAt,
+		Summary:        book.Description,
+		Language:       book.Language,
+		Issued:         book.PublishedAt,
+		SeriesPosition: book.SeriesVolume,
+	}
+	if book.ISBN != "" {
+		p.ISBN = "urn:isbn:" + book.ISBN
+	}
+	if book.Publisher != nil {
+		p.Publisher = book.Publisher.Name
+	}
+	if book.Series != nil {
+		p.Series = book.Series.Name
+	}
+	for _, c := range book.Contributors {
+		contributor := Contributor{Name: c.Author.Name, Role: c.Role, Path: authorPath(&c.Author)}
+		if c.Role == models.RoleAuthor {
+			p.Authors = append(p.Authors, contributor)
+		} else {
+			p.Contributors = append(p.Contributors, contributor)
+		}
+	}
+	for i := range book.Subjects {
+		p.Subjects = append(p.Subjects, Subject{Name: book.Subjects[i].Name, Path: subjectPath(&book.Subjects[i])})
+	}
+	if book.Cover != nil {
+		p.Cover, p.CoverType = book.Cover.URL, book.Cover.ContentType
+		p.Thumbnail = book.Cover.Thumbnails[thumbnailSize]
+	}
+
+	switch {
+	case holds > 0:
+		p.Availability = Availability{State: Reserved, Holds: holds}
+	case book.Available && !book.InTransit:
+		p.Availability = Availability{State: Available}
+	default:
+		p.Availability = Availability{State: Unavailable}
+	}
+	return p
+}
+
+// Start returns the start feed of the catalog called title.
+func Start(title string) *Feed {
+	return &Feed{
+		Path:  "/",
+		Title: title,
+		Kind:  Navigation,
+		Navigation: []NavigationEntry{
+			{
+				ID: "urn:opds:new", Title: "New additions", Summary: "Every book in the catalog, most recently added first",
+				Link: Link{Rel: RelSortNew, Path: "/new", Kind: Acquisition},
+			},
+			{
+				ID: "urn:opds:authors", Title: "Authors", Summary: "Browse the catalog by author",
+				Link: Link{Rel: RelSubsection, Path: "/authors", Kind: Navigation},
+			},
+			{
+				ID: "urn:opds:subjects", Title: "Subjects", Summary: "Browse the catalog by subject",
+				Link: Link{Rel: RelSubsection, Path: "/subjects", Kind: Navigation},
+			},
+		},
+	}
+}
+
+// Authors returns a page of the navigation feed of authors.
+func Authors(authors []models.Author, page Page) *Feed {
+	f := &Feed{Path: pagePath("/authors", nil, page.Number, page.Limit), Title: "Authors", Kind: Navigation, Page: &page}
+	for i := range authors {
+		a := &authors[i]
+		f.Navigation = append(f.Navigation, NavigationEntry{
+			ID:      "urn:uuid:" + a.ID.String(),
+			Title:   a.Name,
+			Updated: a.UpdatedAt,
+			Link:    Link{Rel: RelSubsection, Path: authorPath(a), Kind: Acquisition},
+		})
+		f.Updated = latest(f.Updated, a.UpdatedAt)
+	}
+	f.Links = append(f.Links, Link{Rel: RelUp, Path: "/", Kind: Navigation})
+	return f
+}
+
+// Subjects returns a page of the navigation feed of top-level subjects.
+func Subjects(subjects []models.Subject, page Page) *Feed {
+	f := &Feed{Path: pagePath("/subjects", nil, page.Number, page.Limit), Title: "Subjects", Kind: Navigation, Page: &page}
+	for i := range subjects {
+		s := &subjects[i]
+		f.Navigation = append(f.Navigation, NavigationEntry{
+			ID:      "urn:uuid:" + s.ID.String(),
+			Title:   s.Name,
+			Updated: s.UpdatedAt,
+			Link:    Link{Rel: RelSubsection, Path: subjectPath(s), Kind: Acquisition},
+		})
+		f.Updated = latest(f.Updated, s.UpdatedAt)
+	}
+	f.Links = append(f.Links, Link{Rel: RelUp, Path: "/", Kind: Navigation})
+	return f
+}
+
+// Books returns a page of the acquisition feed at path, whose query is
+// kept in pagination links, listing books with the holds waiting on each.
+func Books(path string, query url.Values, title string, books []models.Book, holds map[uuid.UUID]int64, page Page) *Feed {
+	f := &Feed{Path: pagePath(path, query, page.Number, page.Limit), Title: title, Kind: Acquisition, Page: &page}
+	for i := range books {
+		f.Publications = append(f.Publications, NewPublication(&books[i], holds[books[i].ID]))
+		f.Updated = latest(f.Updated, books[i].UpdatedAt)
+	}
+	f.Links = append(f.Links, Link{Rel: RelUp, Path: "/", Kind: Navigation})
+	return f
+}
+
+// New returns a page of the feed of every book, newest first.
+func New(books []models.Book, holds map[uuid.UUID]int64, page Page) *Feed {
+	return Books("/new", nil, "New additions", books, holds, page)
+}
+
+// AuthorBooks returns a page of the feed of the books author is credited
+// on.
+func AuthorBooks(author *models.Author, books []models.Book, holds map[uuid.UUID]int64, page Page) *Feed {
+	f := Books(authorPath(author), nil, author.Name, books, holds, page)
+	f.Links[0] = Link{Rel: RelUp, Path: "/authors", Kind: Navigation}
+	return f
+}
+
+// SubjectBooks returns a page of the feed of the books under subject,
+// linking up to its parent and offering its children, the narrower
+// subjects, as facets.
+func SubjectBooks(subject *models.Subject, books []models.Book, holds map[uuid.UUID]int64, page Page) *Feed {
+	f := Books(subjectPath(subject), nil, subject.Name, books, holds, page)
+	f.Links[0] = Link{Rel: RelUp, Path: "/subjects", Kind: Navigation}
+	if subject.Parent != nil {
+		f.Links[0] = Link{Rel: RelUp, Path: subjectPath(subject.Parent), Kind: Acquisition, Title: subject.Parent.Name}
+	}
+	for i := range subject.Children {
+		f.Facets = append(f.Facets, Link{
+			Rel: RelFacet, Path: subjectPath(&subject.Children[i]), Kind: Acquisition,
+			Title: subject.Children[i].Name, FacetGroup: "Narrower subjects",
+		})
+	}
+	return f
+}
+
+// SearchResults returns a page of the feed of books matching terms.
+func SearchResults(terms string, books []models.Book, holds map[uuid.UUID]int64, page Page) *Feed {
+	return Books("/search", url.Values{"q": {terms}}, "Search results for “"+terms+"”", books, holds, page)
+}
+
+// pagePath returns path with query and, past the first page or for other
+// than the default limit, the page and limit parameters.
+func pagePath(path string, query url.Values, page, limit int) string {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	if page > 1 {
+		q.Set("page", strconv.Itoa(page))
+	}
+	if limit != DefaultLimit {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if len(q) == 0 {
+		return path
+	}
+	return path + "?" + q.Encode()
+}
+
+// pageLinks returns the first, previous, next and last links of f.
+func pageLinks(f *Feed) []Link {
+	if f.Page == nil {
+		return nil
+	}
+	path, rawQuery, _ := strings.Cut(f.Path, "?")
+	query, _ := url.ParseQuery(rawQuery)
+	query.Del("page")
+	query.Del("limit")
+
+	p := f.Page
+	last := int((p.Total + int64(p.Limit) - 1) / int64(p.Limit))
+	if last < 1 {
+		last = 1
+	}
+	link := func(rel string, page int) Link {
+		return Link{Rel: rel, Path: pagePath(path, query, page, p.Limit), Kind: f.Kind}
+	}
+	links := []Link{link(RelFirst, 1)}
+	if p.Number > 1 {
+		links = append(links, link(RelPrevious, min(p.Number-1, last)))
+	}
+	if p.Number < last {
+		links = append(links, link(RelNext, p.Number+1))
+	}
+	return append(links, link(RelLast, last))
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,353 @@
+package opds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const testRoot = "http://example.com/opds"
+
+func sampleBooks() []models.Book {
+	author := models.Author{ID: uuid.New(), Name: "Ursula K. Le Guin"}
+	translator := models.Author{ID: uuid.New(), Name: "Jane Doe"}
+	updated := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	return []models.Book{
+		{
+			ID:          uuid.New(),
+			Title:       "The Dispossessed",
+			ISBN:        "9780060512750",
+			Description: "An ambiguous utopia",
+			Language:    "en",
+			PublishedAt: time.Date(1974, 5, 1, 0, 0, 0, 0, time.UTC),
+			Available:   true,
+			Contributors: []models.BookContributor{
+				{AuthorID: author.ID, Author: author, Role: models.RoleAuthor},
+				{AuthorID: translator.ID, Author: translator, Role: models.RoleTranslator},
+			},
+			Subjects: []models.Subject{{ID: uuid.New(), Name: "Science fiction"}},
+			Cover: &models.BookCover{
+				URL: "http://example.com/covers/1.jpg", ContentType: "image/jpeg",
+				Thumbnails: map[string]string{"small": "http://example.com/covers/1-small.jpg"},
+			},
+			UpdatedAt: updated,
+		},
+		{
+			ID:        uuid.New(),
+			Title:     "The Lathe of Heaven",
+			Available: false,
+			UpdatedAt: updated.Add(-time.Hour),
+		},
+	}
+}
+
+func TestNewPublicationAvailability(t *testing.T) {
+	tests := []struct {
+		name      string
+		available bool
+		inTransit bool
+		holds     int64
+		want      string
+	}{
+		{"on the shelf", true, false, 0, Available},
+		{"in transit", true, true, 0, Unavailable},
+		{"on loan", false, false, 0, Unavailable},
+		{"held", true, false, 2, Reserved},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := &models.Book{ID: uuid.New(), Available: tt.available, InTransit: tt.inTransit}
+			got := NewPublication(book, tt.holds).Availability
+			if got.State != tt.want || got.Holds != tt.holds {
+				t.Fatalf("availability = %+v, want %s with %d holds", got, tt.want, tt.holds)
+			}
+		})
+	}
+}
+
+func TestPagePath(t *testing.T) {
+	tests := []struct {
+		query url.Values
+		page  int
+		limit int
+		want  string
+	}{
+		{nil, 1, DefaultLimit, "/new"},
+		{nil, 2, DefaultLimit, "/new?page=2"},
+		{nil, 1, 10, "/new?limit=10"},
+		{url.Values{"q": {"le guin"}}, 3, 10, "/new?limit=10&page=3&q=le+guin"},
+	}
+	for _, tt := range tests {
+		if got := pagePath("/new", tt.query, tt.page, tt.limit); got != tt.want {
+			t.Errorf("pagePath(%v, %d, %d) = %q, want %q", tt.query, tt.page, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestPageLinks(t *testing.T) {
+	rels := func(links []Link) map[string]string {
+		paths := map[string]string{}
+		for _, l := range links {
+			paths[l.Rel] = l.Path
+		}
+		return paths
+	}
+
+	f := SearchResults("dune", nil, nil, Page{Number: 2, Limit: 10, Total: 35})
+	got := rels(pageLinks(f))
+	want := map[string]string{
+		RelFirst:    "/search?limit=10&q=dune",
+		RelPrevious: "/search?limit=10&q=dune",
+		RelNext:     "/search?limit=10&page=3&q=dune",
+		RelLast:     "/search?limit=10&page=4&q=dune",
+	}
+	for rel, path := range want {
+		if got[rel] != path {
+			t.Errorf("%s = %q, want %q", rel, got[rel], path)
+		}
+	}
+
+	// A single page has neither previous nor next
+	got = rels(pageLinks(New(nil, nil, Page{Number: 1, Limit: DefaultLimit, Total: 0})))
+	if _, ok := got[RelPrevious]; ok {
+		t.Errorf("first page links to a previous page")
+	}
+	if _, ok := got[RelNext]; ok {
+		t.Errorf("last page links to a next page")
+	}
+	if got[RelLast] != "/new" {
+		t.Errorf("last = %q, want /new", got[RelLast])
+	}
+}
+
+// parsedFeed reads back the parts of an Atom feed the tests check.
+type parsedFeed struct {
+	Title        string `xml:"title"`
+	TotalResults int64  `xml:"totalResults"`
+	StartIndex   int    `xml:"startIndex"`
+	Links        []struct {
+		Rel        string `xml:"rel,attr"`
+		Href       string `xml:"href,attr"`
+		Type       string `xml:"type,attr"`
+		FacetGroup string `xml:"facetGroup,attr"`
+	} `xml:"link"`
+	Entries []struct {
+		Title      string `xml:"title"`
+		Identifier string `xml:"identifier"`
+		Authors    []struct {
+			Name string `xml:"name"`
+			URI  string `xml:"uri"`
+		} `xml:"author"`
+		Links []struct {
+			Rel          string `xml:"rel,attr"`
+			Href         string `xml:"href,attr"`
+			Availability struct {
+				Status string `xml:"status,attr"`
+			} `xml:"availability"`
+			Holds struct {
+				Total int64 `xml:"total,attr"`
+			} `xml:"holds"`
+			Copies struct {
+				Available int `xml:"available,attr"`
+			} `xml:"copies"`
+		} `xml:"link"`
+	} `xml:"entry"`
+}
+
+func TestAtom(t *testing.T) {
+	books := sampleBooks()
+	holds := map[uuid.UUID]int64{books[1].ID: 3}
+	f := Books("/new", nil, "New additions", books, holds, Page{Number: 1, Limit: 2, Total: 5})
+
+	data, err := Atom(f, testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ns := range []string{atomNS, dcNS, opdsNS, openSearchNS} {
+		if !strings.Contains(string(data), ns) {
+			t.Errorf("feed does not declare %s", ns)
+		}
+	}
+
+	var feed parsedFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		t.Fatalf("parse feed: %v\n%s", err, data)
+	}
+	if feed.TotalResults != 5 || feed.StartIndex != 1 {
+		t.Errorf("totalResults = %d, startIndex = %d", feed.TotalResults, feed.StartIndex)
+	}
+
+	links := map[string]string{}
+	for _, l := range feed.Links {
+		links[l.Rel] = l.Href
+	}
+	want := map[string]string{
+		RelSelf:   testRoot + "/new?limit=2",
+		RelStart:  testRoot,
+		RelUp:     testRoot,
+		RelSearch: testRoot + "/opensearch.xml",
+		RelNext:   testRoot + "/new?limit=2&page=2",
+		RelLast:   testRoot + "/new?limit=2&page=3",
+	}
+	for rel, href := range want {
+		if links[rel] != href {
+			t.Errorf("%s = %q, want %q", rel, links[rel], href)
+		}
+	}
+
+	if len(feed.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(feed.Entries))
+	}
+	first := feed.Entries[0]
+	if first.Identifier != "urn:isbn:9780060512750" {
+		t.Errorf("identifier = %q", first.Identifier)
+	}
+	if len(first.Authors) != 1 || first.Authors[0].URI != testRoot+"/authors/"+books[0].Contributors[0].AuthorID.String() {
+		t.Errorf("authors = %+v", first.Authors)
+	}
+	rels := map[string]bool{}
+	for _, l := range first.Links {
+		rels[l.Rel] = true
+	}
+	if !rels[RelImage] || !rels[RelThumbnail] {
+		t.Errorf("cover links missing: %+v", first.Links)
+	}
+
+	tests := []struct {
+		entry     int
+		status    string
+		holds     int64
+		available int
+	}{
+		{0, Available, 0, 1},
+		{1, Reserved, 3, 0},
+	}
+	for _, tt := range tests {
+		var found bool
+		for _, l := range feed.Entries[tt.entry].Links {
+			if l.Rel != RelBorrow {
+				continue
+			}
+			found = true
+			if l.Href != testRoot+"/books/"+books[tt.entry].ID.String() {
+				t.Errorf("borrow href = %q", l.Href)
+			}
+			if l.Availability.Status != tt.status || l.Holds.Total != tt.holds || l.Copies.Available != tt.available {
+				t.Errorf("entry %d: availability %s, %d holds, %d available", tt.entry, l.Availability.Status, l.Holds.Total, l.Copies.Available)
+			}
+		}
+		if !found {
+			t.Errorf("entry %d has no borrow link", tt.entry)
+		}
+	}
+}
+
+func TestAtomFacets(t *testing.T) {
+	parent := &models.Subject{ID: uuid.New(), Name: "Fiction"}
+	subject := &models.Subject{
+		ID: uuid.New(), Name: "Science fiction", Parent: parent,
+		Children: []models.Subject{{ID: uuid.New(), Name: "Space opera"}},
+	}
+	data, err := Atom(SubjectBooks(subject, nil, nil, Page{Number: 1, Limit: DefaultLimit}), testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var feed parsedFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		t.Fatal(err)
+	}
+	var up, facet bool
+	for _, l := range feed.Links {
+		switch l.Rel {
+		case RelUp:
+			up = l.Href == testRoot+"/subjects/"+parent.ID.String()
+		case RelFacet:
+			facet = l.Href == testRoot+"/subjects/"+subject.Children[0].ID.String() && l.FacetGroup == "Narrower subjects"
+		}
+	}
+	if !up || !facet {
+		t.Fatalf("up to parent %t, narrower facet %t:\n%s", up, facet, data)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	books := sampleBooks()
+	data, err := JSON(New(books, nil, Page{Number: 1, Limit: DefaultLimit, Total: 2}), testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var feed jsonFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		t.Fatal(err)
+	}
+	if feed.Metadata.NumberOfItems == nil || *feed.Metadata.NumberOfItems != 2 {
+		t.Errorf("numberOfItems = %v", feed.Metadata.NumberOfItems)
+	}
+	var search *jsonLink
+	for i := range feed.Links {
+		if feed.Links[i].Rel == RelSearch {
+			search = &feed.Links[i]
+		}
+	}
+	if search == nil || search.Href != testRoot+"/search{?q}" || !search.Templated {
+		t.Errorf("search link = %+v", search)
+	}
+
+	if feed.Publications == nil || len(*feed.Publications) != 2 {
+		t.Fatalf("publications = %v", feed.Publications)
+	}
+	meta := (*feed.Publications)[0].Metadata
+	if meta.Identifier != "urn:isbn:9780060512750" || meta.Published != "1974-05-01" {
+		t.Errorf("metadata = %+v", meta)
+	}
+	if len(meta.Author) != 1 || len(meta.Translator) != 1 || meta.Translator[0].Name != "Jane Doe" {
+		t.Errorf("author = %+v, translator = %+v", meta.Author, meta.Translator)
+	}
+
+	// An empty acquisition feed still lists its (no) publications
+	data, err = JSON(New(nil, nil, Page{Number: 1, Limit: DefaultLimit}), testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"publications":[]`) {
+		t.Errorf("empty feed has no publications: %s", data)
+	}
+	// Navigation feeds have none
+	data, err = JSON(Start("Library"), testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "publications") || !strings.Contains(string(data), `"navigation"`) {
+		t.Errorf("unexpected start feed: %s", data)
+	}
+}
+
+func TestOpenSearch(t *testing.T) {
+	data, err := OpenSearch("Springfield Public Library", testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var desc openSearchDescription
+	if err := xml.Unmarshal(data, &desc); err != nil {
+		t.Fatal(err)
+	}
+	if desc.ShortName != "Springfield Publ" {
+		t.Errorf("short name = %q", desc.ShortName)
+	}
+	if want := testRoot + "/search?q={searchTerms}&page={startPage?}&limit={count?}"; desc.URL.Template != want {
+		t.Errorf("template = %q, want %q", desc.URL.Template, want)
+	}
+	if desc.URL.Type != AcquisitionType {
+		t.Errorf("type = %q", desc.URL.Type)
+	}
+}
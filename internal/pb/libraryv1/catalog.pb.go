@@ -0,0 +1,2027 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: library/v1/catalog.proto
+
+package libraryv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Author struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Biography     string                 `protobuf:"bytes,3,opt,name=biography,proto3" json:"biography,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Author) Reset() {
+	*x = Author{}
+	mi := &file_library_v1_catalog_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Author) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Author) ProtoMessage() {}
+
+func (x *Author) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Author.ProtoReflect.Descriptor instead.
+func (*Author) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Author) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Author) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Author) GetBiography() string {
+	if x != nil {
+		return x.Biography
+	}
+	return ""
+}
+
+func (x *Author) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Author) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// Contributor credits an author with a role on a book: author, editor,
+// translator or illustrator.
+type Contributor struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AuthorId      string                 `protobuf:"bytes,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Author        *Author                `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	Position      int32                  `protobuf:"varint,4,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Contributor) Reset() {
+	*x = Contributor{}
+	mi := &file_library_v1_catalog_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Contributor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Contributor) ProtoMessage() {}
+
+func (x *Contributor) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Contributor.ProtoReflect.Descriptor instead.
+func (*Contributor) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Contributor) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *Contributor) GetAuthor() *Author {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+func (x *Contributor) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Contributor) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+// ContributorInput credits an author on a book; role defaults to author.
+type ContributorInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AuthorId      string                 `protobuf:"bytes,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContributorInput) Reset() {
+	*x = ContributorInput{}
+	mi := &file_library_v1_catalog_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContributorInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContributorInput) ProtoMessage() {}
+
+func (x *ContributorInput) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContributorInput.ProtoReflect.Descriptor instead.
+func (*ContributorInput) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ContributorInput) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *ContributorInput) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+// Subject is a term in the subject vocabulary; parent_id is empty for a
+// top-level term.
+type Subject struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ParentId      string                 `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Kind          string                 `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	Source        string                 `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Subject) Reset() {
+	*x = Subject{}
+	mi := &file_library_v1_catalog_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subject) ProtoMessage() {}
+
+func (x *Subject) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subject.ProtoReflect.Descriptor instead.
+func (*Subject) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Subject) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Subject) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Subject) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Subject) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Subject) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type Publisher struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Place         string                 `protobuf:"bytes,3,opt,name=place,proto3" json:"place,omitempty"`
+	Website       string                 `protobuf:"bytes,4,opt,name=website,proto3" json:"website,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Publisher) Reset() {
+	*x = Publisher{}
+	mi := &file_library_v1_catalog_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Publisher) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Publisher) ProtoMessage() {}
+
+func (x *Publisher) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Publisher.ProtoReflect.Descriptor instead.
+func (*Publisher) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Publisher) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Publisher) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Publisher) GetPlace() string {
+	if x != nil {
+		return x.Place
+	}
+	return ""
+}
+
+func (x *Publisher) GetWebsite() string {
+	if x != nil {
+		return x.Website
+	}
+	return ""
+}
+
+type Series struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Series) Reset() {
+	*x = Series{}
+	mi := &file_library_v1_catalog_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Series) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Series) ProtoMessage() {}
+
+func (x *Series) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Series.ProtoReflect.Descriptor instead.
+func (*Series) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Series) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Series) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Series) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type Book struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title       string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Isbn        string                 `protobuf:"bytes,3,opt,name=isbn,proto3" json:"isbn,omitempty"`
+	Description string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	PublishedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	Available   bool                   `protobuf:"varint,8,opt,name=available,proto3" json:"available,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// home_location_id is where the item is shelved; current_location_id is
+	// where it is now, empty while on loan or in transit.
+	HomeLocationId    string `protobuf:"bytes,11,opt,name=home_location_id,json=homeLocationId,proto3" json:"home_location_id,omitempty"`
+	CurrentLocationId string `protobuf:"bytes,12,opt,name=current_location_id,json=currentLocationId,proto3" json:"current_location_id,omitempty"`
+	InTransit         bool   `protobuf:"varint,13,opt,name=in_transit,json=inTransit,proto3" json:"in_transit,omitempty"`
+	// contributors are in title page order.
+	Contributors []*Contributor `protobuf:"bytes,14,rep,name=contributors,proto3" json:"contributors,omitempty"`
+	Subjects     []*Subject     `protobuf:"bytes,15,rep,name=subjects,proto3" json:"subjects,omitempty"`
+	// Edition metadata. format is hardcover, paperback, ebook or audiobook;
+	// language is a BCP 47 tag.
+	Publisher    *Publisher `protobuf:"bytes,16,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	Edition      string     `protobuf:"bytes,17,opt,name=edition,proto3" json:"edition,omitempty"`
+	Format       string     `protobuf:"bytes,18,opt,name=format,proto3" json:"format,omitempty"`
+	Pages        int32      `protobuf:"varint,19,opt,name=pages,proto3" json:"pages,omitempty"`
+	Language     string     `protobuf:"bytes,20,opt,name=language,proto3" json:"language,omitempty"`
+	Series       *Series    `protobuf:"bytes,21,opt,name=series,proto3" json:"series,omitempty"`
+	SeriesVolume int32      `protobuf:"varint,22,opt,name=series_volume,json=seriesVolume,proto3" json:"series_volume,omitempty"`
+	// work_id links the editions of the same title.
+	WorkId string `protobuf:"bytes,23,opt,name=work_id,json=workId,proto3" json:"work_id,omitempty"`
+	// call_number is written in classification_scheme: dewey, lc or local.
+	CallNumber           string `protobuf:"bytes,24,opt,name=call_number,json=callNumber,proto3" json:"call_number,omitempty"`
+	ClassificationScheme string `protobuf:"bytes,25,opt,name=classification_scheme,json=classificationScheme,proto3" json:"classification_scheme,omitempty"`
+	// cover is unset until a cover image is uploaded.
+	Cover *BookCover `protobuf:"bytes,26,opt,name=cover,proto3" json:"cover,omitempty"`
+	// barcode identifies the physical item.
+	Barcode       string `protobuf:"bytes,27,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Book) Reset() {
+	*x = Book{}
+	mi := &file_library_v1_catalog_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Book) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Book) ProtoMessage() {}
+
+func (x *Book) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Book.ProtoReflect.Descriptor instead.
+func (*Book) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Book) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Book) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Book) GetIsbn() string {
+	if x != nil {
+		return x.Isbn
+	}
+	return ""
+}
+
+func (x *Book) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Book) GetPublishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishedAt
+	}
+	return nil
+}
+
+func (x *Book) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+func (x *Book) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Book) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Book) GetHomeLocationId() string {
+	if x != nil {
+		return x.HomeLocationId
+	}
+	return ""
+}
+
+func (x *Book) GetCurrentLocationId() string {
+	if x != nil {
+		return x.CurrentLocationId
+	}
+	return ""
+}
+
+func (x *Book) GetInTransit() bool {
+	if x != nil {
+		return x.InTransit
+	}
+	return false
+}
+
+func (x *Book) GetContributors() []*Contributor {
+	if x != nil {
+		return x.Contributors
+	}
+	return nil
+}
+
+func (x *Book) GetSubjects() []*Subject {
+	if x != nil {
+		return x.Subjects
+	}
+	return nil
+}
+
+func (x *Book) GetPublisher() *Publisher {
+	if x != nil {
+		return x.Publisher
+	}
+	return nil
+}
+
+func (x *Book) GetEdition() string {
+	if x != nil {
+		return x.Edition
+	}
+	return ""
+}
+
+func (x *Book) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *Book) GetPages() int32 {
+	if x != nil {
+		return x.Pages
+	}
+	return 0
+}
+
+func (x *Book) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Book) GetSeries() *Series {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+func (x *Book) GetSeriesVolume() int32 {
+	if x != nil {
+		return x.SeriesVolume
+	}
+	return 0
+}
+
+func (x *Book) GetWorkId() string {
+	if x != nil {
+		return x.WorkId
+	}
+	return ""
+}
+
+func (x *Book) GetCallNumber() string {
+	if x != nil {
+		return x.CallNumber
+	}
+	return ""
+}
+
+func (x *Book) GetClassificationScheme() string {
+	if x != nil {
+		return x.ClassificationScheme
+	}
+	return ""
+}
+
+func (x *Book) GetCover() *BookCover {
+	if x != nil {
+		return x.Cover
+	}
+	return nil
+}
+
+func (x *Book) GetBarcode() string {
+	if x != nil {
+		return x.Barcode
+	}
+	return ""
+}
+
+// BookCover locates a book's cover image, as uploaded, and its JPEG
+// thumbnails keyed by size name (small, medium, large).
+type BookCover struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Width         int32                  `protobuf:"varint,3,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int32                  `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	Thumbnails    map[string]string      `protobuf:"bytes,5,rep,name=thumbnails,proto3" json:"thumbnails,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookCover) Reset() {
+	*x = BookCover{}
+	mi := &file_library_v1_catalog_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookCover) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookCover) ProtoMessage() {}
+
+func (x *BookCover) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookCover.ProtoReflect.Descriptor instead.
+func (*BookCover) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BookCover) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *BookCover) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *BookCover) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *BookCover) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *BookCover) GetThumbnails() map[string]string {
+	if x != nil {
+		return x.Thumbnails
+	}
+	return nil
+}
+
+type CreateAuthorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Biography     string                 `protobuf:"bytes,2,opt,name=biography,proto3" json:"biography,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAuthorRequest) Reset() {
+	*x = CreateAuthorRequest{}
+	mi := &file_library_v1_catalog_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAuthorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAuthorRequest) ProtoMessage() {}
+
+func (x *CreateAuthorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAuthorRequest.ProtoReflect.Descriptor instead.
+func (*CreateAuthorRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateAuthorRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateAuthorRequest) GetBiography() string {
+	if x != nil {
+		return x.Biography
+	}
+	return ""
+}
+
+type GetAuthorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAuthorRequest) Reset() {
+	*x = GetAuthorRequest{}
+	mi := &file_library_v1_catalog_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAuthorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAuthorRequest) ProtoMessage() {}
+
+func (x *GetAuthorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAuthorRequest.ProtoReflect.Descriptor instead.
+func (*GetAuthorRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetAuthorRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListAuthorsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          *PageRequest           `protobuf:"bytes,1,opt,name=page,proto3" json:"page,omitempty"`
+	Search        string                 `protobuf:"bytes,2,opt,name=search,proto3" json:"search,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuthorsRequest) Reset() {
+	*x = ListAuthorsRequest{}
+	mi := &file_library_v1_catalog_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuthorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuthorsRequest) ProtoMessage() {}
+
+func (x *ListAuthorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuthorsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuthorsRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListAuthorsRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+func (x *ListAuthorsRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+type ListAuthorsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Authors       []*Author              `protobuf:"bytes,1,rep,name=authors,proto3" json:"authors,omitempty"`
+	Pagination    *PageInfo              `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuthorsResponse) Reset() {
+	*x = ListAuthorsResponse{}
+	mi := &file_library_v1_catalog_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuthorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuthorsResponse) ProtoMessage() {}
+
+func (x *ListAuthorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuthorsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuthorsResponse) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListAuthorsResponse) GetAuthors() []*Author {
+	if x != nil {
+		return x.Authors
+	}
+	return nil
+}
+
+func (x *ListAuthorsResponse) GetPagination() *PageInfo {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+type UpdateAuthorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Biography     string                 `protobuf:"bytes,3,opt,name=biography,proto3" json:"biography,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAuthorRequest) Reset() {
+	*x = UpdateAuthorRequest{}
+	mi := &file_library_v1_catalog_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAuthorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAuthorRequest) ProtoMessage() {}
+
+func (x *UpdateAuthorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAuthorRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAuthorRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateAuthorRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateAuthorRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateAuthorRequest) GetBiography() string {
+	if x != nil {
+		return x.Biography
+	}
+	return ""
+}
+
+type DeleteAuthorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAuthorRequest) Reset() {
+	*x = DeleteAuthorRequest{}
+	mi := &file_library_v1_catalog_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAuthorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAuthorRequest) ProtoMessage() {}
+
+func (x *DeleteAuthorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAuthorRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAuthorRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DeleteAuthorRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CreateBookRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Title          string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Isbn           string                 `protobuf:"bytes,2,opt,name=isbn,proto3" json:"isbn,omitempty"`
+	Description    string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	PublishedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	HomeLocationId string                 `protobuf:"bytes,6,opt,name=home_location_id,json=homeLocationId,proto3" json:"home_location_id,omitempty"`
+	Contributors   []*ContributorInput    `protobuf:"bytes,7,rep,name=contributors,proto3" json:"contributors,omitempty"`
+	SubjectIds     []string               `protobuf:"bytes,8,rep,name=subject_ids,json=subjectIds,proto3" json:"subject_ids,omitempty"`
+	PublisherId    string                 `protobuf:"bytes,9,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	Edition        string                 `protobuf:"bytes,10,opt,name=edition,proto3" json:"edition,omitempty"`
+	Format         string                 `protobuf:"bytes,11,opt,name=format,proto3" json:"format,omitempty"`
+	Pages          int32                  `protobuf:"varint,12,opt,name=pages,proto3" json:"pages,omitempty"`
+	Language       string                 `protobuf:"bytes,13,opt,name=language,proto3" json:"language,omitempty"`
+	SeriesId       string                 `protobuf:"bytes,14,opt,name=series_id,json=seriesId,proto3" json:"series_id,omitempty"`
+	SeriesVolume   int32                  `protobuf:"varint,15,opt,name=series_volume,json=seriesVolume,proto3" json:"series_volume,omitempty"`
+	WorkId         string                 `protobuf:"bytes,16,opt,name=work_id,json=workId,proto3" json:"work_id,omitempty"`
+	// classification_scheme is detected from call_number when empty.
+	CallNumber           string `protobuf:"bytes,17,opt,name=call_number,json=callNumber,proto3" json:"call_number,omitempty"`
+	ClassificationScheme string `protobuf:"bytes,18,opt,name=classification_scheme,json=classificationScheme,proto3" json:"classification_scheme,omitempty"`
+	// barcode is generated when empty.
+	Barcode       string `protobuf:"bytes,19,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBookRequest) Reset() {
+	*x = CreateBookRequest{}
+	mi := &file_library_v1_catalog_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBookRequest) ProtoMessage() {}
+
+func (x *CreateBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBookRequest.ProtoReflect.Descriptor instead.
+func (*CreateBookRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateBookRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateBookRequest) GetIsbn() string {
+	if x != nil {
+		return x.Isbn
+	}
+	return ""
+}
+
+func (x *CreateBookRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateBookRequest) GetPublishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishedAt
+	}
+	return nil
+}
+
+func (x *CreateBookRequest) GetHomeLocationId() string {
+	if x != nil {
+		return x.HomeLocationId
+	}
+	return ""
+}
+
+func (x *CreateBookRequest) GetContributors() []*ContributorInput {
+	if x != nil {
+		return x.Contributors
+	}
+	return nil
+}
+
+func (x *CreateBookRequest) GetSubjectIds() []string {
+	if x != nil {
+		return x.SubjectIds
+	}
+	return nil
+}
+
+func (x *CreateBookRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *CreateBookRequest) GetEdition() string {
+	if x != nil {
+		return x.Edition
+	}
+	return ""
+}
+
+func (x *CreateBookRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *CreateBookRequest) GetPages() int32 {
+	if x != nil {
+		return x.Pages
+	}
+	return 0
+}
+
+func (x *CreateBookRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *CreateBookRequest) GetSeriesId() string {
+	if x != nil {
+		return x.SeriesId
+	}
+	return ""
+}
+
+func (x *CreateBookRequest) GetSeriesVolume() int32 {
+	if x != nil {
+		return x.SeriesVolume
+	}
+	return 0
+}
+
+func (x *CreateBookRequest) GetWorkId() string {
+	if x != nil {
+		return x.WorkId
+	}
+	return ""
+}
+
+func (x *CreateBookRequest) GetCallNumber() string {
+	if x != nil {
+		return x.CallNumber
+	}
+	return ""
+}
+
+func (x *CreateBookRequest) GetClassificationScheme() string {
+	if x != nil {
+		return x.ClassificationScheme
+	}
+	return ""
+}
+
+func (x *CreateBookRequest) GetBarcode() string {
+	if x != nil {
+		return x.Barcode
+	}
+	return ""
+}
+
+type GetBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBookRequest) Reset() {
+	*x = GetBookRequest{}
+	mi := &file_library_v1_catalog_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBookRequest) ProtoMessage() {}
+
+func (x *GetBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBookRequest.ProtoReflect.Descriptor instead.
+func (*GetBookRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetBookRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListBooksRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Page   *PageRequest           `protobuf:"bytes,1,opt,name=page,proto3" json:"page,omitempty"`
+	Search string                 `protobuf:"bytes,2,opt,name=search,proto3" json:"search,omitempty"`
+	// branch_id matches books currently at the branch, home_branch_id books
+	// that belong to it.
+	BranchId     string `protobuf:"bytes,3,opt,name=branch_id,json=branchId,proto3" json:"branch_id,omitempty"`
+	HomeBranchId string `protobuf:"bytes,4,opt,name=home_branch_id,json=homeBranchId,proto3" json:"home_branch_id,omitempty"`
+	// subject_id matches books tagged with the subject or a narrower one.
+	SubjectId   string `protobuf:"bytes,5,opt,name=subject_id,json=subjectId,proto3" json:"subject_id,omitempty"`
+	PublisherId string `protobuf:"bytes,6,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	// series_id lists a series in volume order.
+	SeriesId string `protobuf:"bytes,7,opt,name=series_id,json=seriesId,proto3" json:"series_id,omitempty"`
+	WorkId   string `protobuf:"bytes,8,opt,name=work_id,json=workId,proto3" json:"work_id,omitempty"`
+	Format   string `protobuf:"bytes,9,opt,name=format,proto3" json:"format,omitempty"`
+	Language string `protobuf:"bytes,10,opt,name=language,proto3" json:"language,omitempty"`
+	// call_number_from and call_number_to bound call numbers inclusively,
+	// in classification or else the scheme they are written in.
+	Classification string `protobuf:"bytes,11,opt,name=classification,proto3" json:"classification,omitempty"`
+	CallNumberFrom string `protobuf:"bytes,12,opt,name=call_number_from,json=callNumberFrom,proto3" json:"call_number_from,omitempty"`
+	CallNumberTo   string `protobuf:"bytes,13,opt,name=call_number_to,json=callNumberTo,proto3" json:"call_number_to,omitempty"`
+	// shelf_order lists books by call number.
+	ShelfOrder    bool `protobuf:"varint,14,opt,name=shelf_order,json=shelfOrder,proto3" json:"shelf_order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBooksRequest) Reset() {
+	*x = ListBooksRequest{}
+	mi := &file_library_v1_catalog_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBooksRequest) ProtoMessage() {}
+
+func (x *ListBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBooksRequest.ProtoReflect.Descriptor instead.
+func (*ListBooksRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListBooksRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+func (x *ListBooksRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetBranchId() string {
+	if x != nil {
+		return x.BranchId
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetHomeBranchId() string {
+	if x != nil {
+		return x.HomeBranchId
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetSubjectId() string {
+	if x != nil {
+		return x.SubjectId
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetSeriesId() string {
+	if x != nil {
+		return x.SeriesId
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetWorkId() string {
+	if x != nil {
+		return x.WorkId
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetClassification() string {
+	if x != nil {
+		return x.Classification
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetCallNumberFrom() string {
+	if x != nil {
+		return x.CallNumberFrom
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetCallNumberTo() string {
+	if x != nil {
+		return x.CallNumberTo
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetShelfOrder() bool {
+	if x != nil {
+		return x.ShelfOrder
+	}
+	return false
+}
+
+type ListBooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Books         []*Book                `protobuf:"bytes,1,rep,name=books,proto3" json:"books,omitempty"`
+	Pagination    *PageInfo              `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBooksResponse) Reset() {
+	*x = ListBooksResponse{}
+	mi := &file_library_v1_catalog_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBooksResponse) ProtoMessage() {}
+
+func (x *ListBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBooksResponse.ProtoReflect.Descriptor instead.
+func (*ListBooksResponse) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListBooksResponse) GetBooks() []*Book {
+	if x != nil {
+		return x.Books
+	}
+	return nil
+}
+
+func (x *ListBooksResponse) GetPagination() *PageInfo {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+type UpdateBookRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title          string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Isbn           string                 `protobuf:"bytes,3,opt,name=isbn,proto3" json:"isbn,omitempty"`
+	Description    string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	PublishedAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	HomeLocationId string                 `protobuf:"bytes,7,opt,name=home_location_id,json=homeLocationId,proto3" json:"home_location_id,omitempty"`
+	// contributors replace the book's credits when not empty.
+	Contributors []*ContributorInput `protobuf:"bytes,8,rep,name=contributors,proto3" json:"contributors,omitempty"`
+	// subject_ids replace the book's subjects when not empty.
+	SubjectIds   []string `protobuf:"bytes,9,rep,name=subject_ids,json=subjectIds,proto3" json:"subject_ids,omitempty"`
+	PublisherId  string   `protobuf:"bytes,10,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	Edition      string   `protobuf:"bytes,11,opt,name=edition,proto3" json:"edition,omitempty"`
+	Format       string   `protobuf:"bytes,12,opt,name=format,proto3" json:"format,omitempty"`
+	Pages        *int32   `protobuf:"varint,13,opt,name=pages,proto3,oneof" json:"pages,omitempty"`
+	Language     string   `protobuf:"bytes,14,opt,name=language,proto3" json:"language,omitempty"`
+	SeriesId     string   `protobuf:"bytes,15,opt,name=series_id,json=seriesId,proto3" json:"series_id,omitempty"`
+	SeriesVolume *int32   `protobuf:"varint,16,opt,name=series_volume,json=seriesVolume,proto3,oneof" json:"series_volume,omitempty"`
+	WorkId       string   `protobuf:"bytes,17,opt,name=work_id,json=workId,proto3" json:"work_id,omitempty"`
+	// classification_scheme alone reclassifies the current call number.
+	CallNumber           string `protobuf:"bytes,18,opt,name=call_number,json=callNumber,proto3" json:"call_number,omitempty"`
+	ClassificationScheme string `protobuf:"bytes,19,opt,name=classification_scheme,json=classificationScheme,proto3" json:"classification_scheme,omitempty"`
+	Barcode              string `protobuf:"bytes,20,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *UpdateBookRequest) Reset() {
+	*x = UpdateBookRequest{}
+	mi := &file_library_v1_catalog_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateBookRequest) ProtoMessage() {}
+
+func (x *UpdateBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateBookRequest.ProtoReflect.Descriptor instead.
+func (*UpdateBookRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UpdateBookRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateBookRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *UpdateBookRequest) GetIsbn() string {
+	if x != nil {
+		return x.Isbn
+	}
+	return ""
+}
+
+func (x *UpdateBookRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateBookRequest) GetPublishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishedAt
+	}
+	return nil
+}
+
+func (x *UpdateBookRequest) GetHomeLocationId() string {
+	if x != nil {
+		return x.HomeLocationId
+	}
+	return ""
+}
+
+func (x *UpdateBookRequest) GetContributors() []*ContributorInput {
+	if x != nil {
+		return x.Contributors
+	}
+	return nil
+}
+
+func (x *UpdateBookRequest) GetSubjectIds() []string {
+	if x != nil {
+		return x.SubjectIds
+	}
+	return nil
+}
+
+func (x *UpdateBookRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *UpdateBookRequest) GetEdition() string {
+	if x != nil {
+		return x.Edition
+	}
+	return ""
+}
+
+func (x *UpdateBookRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *UpdateBookRequest) GetPages() int32 {
+	if x != nil && x.Pages != nil {
+		return *x.Pages
+	}
+	return 0
+}
+
+func (x *UpdateBookRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *UpdateBookRequest) GetSeriesId() string {
+	if x != nil {
+		return x.SeriesId
+	}
+	return ""
+}
+
+func (x *UpdateBookRequest) GetSeriesVolume() int32 {
+	if x != nil && x.SeriesVolume != nil {
+		return *x.SeriesVolume
+	}
+	return 0
+}
+
+func (x *UpdateBookRequest) GetWorkId() string {
+	if x != nil {
+		return x.WorkId
+	}
+	return ""
+}
+
+func (x *UpdateBookRequest) GetCallNumber() string {
+	if x != nil {
+		return x.CallNumber
+	}
+	return ""
+}
+
+func (x *UpdateBookRequest) GetClassificationScheme() string {
+	if x != nil {
+		return x.ClassificationScheme
+	}
+	return ""
+}
+
+func (x *UpdateBookRequest) GetBarcode() string {
+	if x != nil {
+		return x.Barcode
+	}
+	return ""
+}
+
+type DeleteBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBookRequest) Reset() {
+	*x = DeleteBookRequest{}
+	mi := &file_library_v1_catalog_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBookRequest) ProtoMessage() {}
+
+func (x *DeleteBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_catalog_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBookRequest.ProtoReflect.Descriptor instead.
+func (*DeleteBookRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_catalog_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteBookRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_library_v1_catalog_proto protoreflect.FileDescriptor
+
+const file_library_v1_catalog_proto_rawDesc = "" +
+	"\n" +
+	"\x18library/v1/catalog.proto\x12\n" +
+	"library.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17library/v1/common.proto\"\xc0\x01\n" +
+	"\x06Author\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\tbiography\x18\x03 \x01(\tR\tbiography\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x86\x01\n" +
+	"\vContributor\x12\x1b\n" +
+	"\tauthor_id\x18\x01 \x01(\tR\bauthorId\x12*\n" +
+	"\x06author\x18\x02 \x01(\v2\x12.library.v1.AuthorR\x06author\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12\x1a\n" +
+	"\bposition\x18\x04 \x01(\x05R\bposition\"C\n" +
+	"\x10ContributorInput\x12\x1b\n" +
+	"\tauthor_id\x18\x01 \x01(\tR\bauthorId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\"v\n" +
+	"\aSubject\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\tR\bparentId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\"_\n" +
+	"\tPublisher\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05place\x18\x03 \x01(\tR\x05place\x12\x18\n" +
+	"\awebsite\x18\x04 \x01(\tR\awebsite\"N\n" +
+	"\x06Series\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"\xdb\a\n" +
+	"\x04Book\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04isbn\x18\x03 \x01(\tR\x04isbn\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12=\n" +
+	"\fpublished_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vpublishedAt\x12\x1c\n" +
+	"\tavailable\x18\b \x01(\bR\tavailable\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12(\n" +
+	"\x10home_location_id\x18\v \x01(\tR\x0ehomeLocationId\x12.\n" +
+	"\x13current_location_id\x18\f \x01(\tR\x11currentLocationId\x12\x1d\n" +
+	"\n" +
+	"in_transit\x18\r \x01(\bR\tinTransit\x12;\n" +
+	"\fcontributors\x18\x0e \x03(\v2\x17.library.v1.ContributorR\fcontributors\x12/\n" +
+	"\bsubjects\x18\x0f \x03(\v2\x13.library.v1.SubjectR\bsubjects\x123\n" +
+	"\tpublisher\x18\x10 \x01(\v2\x15.library.v1.PublisherR\tpublisher\x12\x18\n" +
+	"\aedition\x18\x11 \x01(\tR\aedition\x12\x16\n" +
+	"\x06format\x18\x12 \x01(\tR\x06format\x12\x14\n" +
+	"\x05pages\x18\x13 \x01(\x05R\x05pages\x12\x1a\n" +
+	"\blanguage\x18\x14 \x01(\tR\blanguage\x12*\n" +
+	"\x06series\x18\x15 \x01(\v2\x12.library.v1.SeriesR\x06series\x12#\n" +
+	"\rseries_volume\x18\x16 \x01(\x05R\fseriesVolume\x12\x17\n" +
+	"\awork_id\x18\x17 \x01(\tR\x06workId\x12\x1f\n" +
+	"\vcall_number\x18\x18 \x01(\tR\n" +
+	"callNumber\x123\n" +
+	"\x15classification_scheme\x18\x19 \x01(\tR\x14classificationScheme\x12+\n" +
+	"\x05cover\x18\x1a \x01(\v2\x15.library.v1.BookCoverR\x05cover\x12\x18\n" +
+	"\abarcode\x18\x1b \x01(\tR\abarcodeJ\x04\b\x05\x10\x06J\x04\b\x06\x10\aR\tauthor_idR\x06author\"\xf4\x01\n" +
+	"\tBookCover\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x14\n" +
+	"\x05width\x18\x03 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x04 \x01(\x05R\x06height\x12E\n" +
+	"\n" +
+	"thumbnails\x18\x05 \x03(\v2%.library.v1.BookCover.ThumbnailsEntryR\n" +
+	"thumbnails\x1a=\n" +
+	"\x0fThumbnailsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"G\n" +
+	"\x13CreateAuthorRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\tbiography\x18\x02 \x01(\tR\tbiography\"\"\n" +
+	"\x10GetAuthorRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"Y\n" +
+	"\x12ListAuthorsRequest\x12+\n" +
+	"\x04page\x18\x01 \x01(\v2\x17.library.v1.PageRequestR\x04page\x12\x16\n" +
+	"\x06search\x18\x02 \x01(\tR\x06search\"y\n" +
+	"\x13ListAuthorsResponse\x12,\n" +
+	"\aauthors\x18\x01 \x03(\v2\x12.library.v1.AuthorR\aauthors\x124\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x14.library.v1.PageInfoR\n" +
+	"pagination\"W\n" +
+	"\x13UpdateAuthorRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\tbiography\x18\x03 \x01(\tR\tbiography\"%\n" +
+	"\x13DeleteAuthorRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x8e\x05\n" +
+	"\x11CreateBookRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x12\n" +
+	"\x04isbn\x18\x02 \x01(\tR\x04isbn\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12=\n" +
+	"\fpublished_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vpublishedAt\x12(\n" +
+	"\x10home_location_id\x18\x06 \x01(\tR\x0ehomeLocationId\x12@\n" +
+	"\fcontributors\x18\a \x03(\v2\x1c.library.v1.ContributorInputR\fcontributors\x12\x1f\n" +
+	"\vsubject_ids\x18\b \x03(\tR\n" +
+	"subjectIds\x12!\n" +
+	"\fpublisher_id\x18\t \x01(\tR\vpublisherId\x12\x18\n" +
+	"\aedition\x18\n" +
+	" \x01(\tR\aedition\x12\x16\n" +
+	"\x06format\x18\v \x01(\tR\x06format\x12\x14\n" +
+	"\x05pages\x18\f \x01(\x05R\x05pages\x12\x1a\n" +
+	"\blanguage\x18\r \x01(\tR\blanguage\x12\x1b\n" +
+	"\tseries_id\x18\x0e \x01(\tR\bseriesId\x12#\n" +
+	"\rseries_volume\x18\x0f \x01(\x05R\fseriesVolume\x12\x17\n" +
+	"\awork_id\x18\x10 \x01(\tR\x06workId\x12\x1f\n" +
+	"\vcall_number\x18\x11 \x01(\tR\n" +
+	"callNumber\x123\n" +
+	"\x15classification_scheme\x18\x12 \x01(\tR\x14classificationScheme\x12\x18\n" +
+	"\abarcode\x18\x13 \x01(\tR\abarcodeJ\x04\b\x04\x10\x05R\tauthor_id\" \n" +
+	"\x0eGetBookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xdf\x03\n" +
+	"\x10ListBooksRequest\x12+\n" +
+	"\x04page\x18\x01 \x01(\v2\x17.library.v1.PageRequestR\x04page\x12\x16\n" +
+	"\x06search\x18\x02 \x01(\tR\x06search\x12\x1b\n" +
+	"\tbranch_id\x18\x03 \x01(\tR\bbranchId\x12$\n" +
+	"\x0ehome_branch_id\x18\x04 \x01(\tR\fhomeBranchId\x12\x1d\n" +
+	"\n" +
+	"subject_id\x18\x05 \x01(\tR\tsubjectId\x12!\n" +
+	"\fpublisher_id\x18\x06 \x01(\tR\vpublisherId\x12\x1b\n" +
+	"\tseries_id\x18\a \x01(\tR\bseriesId\x12\x17\n" +
+	"\awork_id\x18\b \x01(\tR\x06workId\x12\x16\n" +
+	"\x06format\x18\t \x01(\tR\x06format\x12\x1a\n" +
+	"\blanguage\x18\n" +
+	" \x01(\tR\blanguage\x12&\n" +
+	"\x0eclassification\x18\v \x01(\tR\x0eclassification\x12(\n" +
+	"\x10call_number_from\x18\f \x01(\tR\x0ecallNumberFrom\x12$\n" +
+	"\x0ecall_number_to\x18\r \x01(\tR\fcallNumberTo\x12\x1f\n" +
+	"\vshelf_order\x18\x0e \x01(\bR\n" +
+	"shelfOrder\"q\n" +
+	"\x11ListBooksResponse\x12&\n" +
+	"\x05books\x18\x01 \x03(\v2\x10.library.v1.BookR\x05books\x124\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x14.library.v1.PageInfoR\n" +
+	"pagination\"\xc4\x05\n" +
+	"\x11UpdateBookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04isbn\x18\x03 \x01(\tR\x04isbn\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12=\n" +
+	"\fpublished_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vpublishedAt\x12(\n" +
+	"\x10home_location_id\x18\a \x01(\tR\x0ehomeLocationId\x12@\n" +
+	"\fcontributors\x18\b \x03(\v2\x1c.library.v1.ContributorInputR\fcontributors\x12\x1f\n" +
+	"\vsubject_ids\x18\t \x03(\tR\n" +
+	"subjectIds\x12!\n" +
+	"\fpublisher_id\x18\n" +
+	" \x01(\tR\vpublisherId\x12\x18\n" +
+	"\aedition\x18\v \x01(\tR\aedition\x12\x16\n" +
+	"\x06format\x18\f \x01(\tR\x06format\x12\x19\n" +
+	"\x05pages\x18\r \x01(\x05H\x00R\x05pages\x88\x01\x01\x12\x1a\n" +
+	"\blanguage\x18\x0e \x01(\tR\blanguage\x12\x1b\n" +
+	"\tseries_id\x18\x0f \x01(\tR\bseriesId\x12(\n" +
+	"\rseries_volume\x18\x10 \x01(\x05H\x01R\fseriesVolume\x88\x01\x01\x12\x17\n" +
+	"\awork_id\x18\x11 \x01(\tR\x06workId\x12\x1f\n" +
+	"\vcall_number\x18\x12 \x01(\tR\n" +
+	"callNumber\x123\n" +
+	"\x15classification_scheme\x18\x13 \x01(\tR\x14classificationScheme\x12\x18\n" +
+	"\abarcode\x18\x14 \x01(\tR\abarcodeB\b\n" +
+	"\x06_pagesB\x10\n" +
+	"\x0e_series_volumeJ\x04\b\x05\x10\x06R\tauthor_id\"#\n" +
+	"\x11DeleteBookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id2\xb8\x05\n" +
+	"\x0eCatalogService\x12C\n" +
+	"\fCreateAuthor\x12\x1f.library.v1.CreateAuthorRequest\x1a\x12.library.v1.Author\x12=\n" +
+	"\tGetAuthor\x12\x1c.library.v1.GetAuthorRequest\x1a\x12.library.v1.Author\x12N\n" +
+	"\vListAuthors\x12\x1e.library.v1.ListAuthorsRequest\x1a\x1f.library.v1.ListAuthorsResponse\x12C\n" +
+	"\fUpdateAuthor\x12\x1f.library.v1.UpdateAuthorRequest\x1a\x12.library.v1.Author\x12G\n" +
+	"\fDeleteAuthor\x12\x1f.library.v1.DeleteAuthorRequest\x1a\x16.google.protobuf.Empty\x12=\n" +
+	"\n" +
+	"CreateBook\x12\x1d.library.v1.CreateBookRequest\x1a\x10.library.v1.Book\x127\n" +
+	"\aGetBook\x12\x1a.library.v1.GetBookRequest\x1a\x10.library.v1.Book\x12H\n" +
+	"\tListBooks\x12\x1c.library.v1.ListBooksRequest\x1a\x1d.library.v1.ListBooksResponse\x12=\n" +
+	"\n" +
+	"UpdateBook\x12\x1d.library.v1.UpdateBookRequest\x1a\x10.library.v1.Book\x12C\n" +
+	"\n" +
+	"DeleteBook\x12\x1d.library.v1.DeleteBookRequest\x1a\x16.google.protobuf.EmptyB7Z5library-management-go/internal/pb/libraryv1;libraryv1b\x06proto3"
+
+var (
+	file_library_v1_catalog_proto_rawDescOnce sync.Once
+	file_library_v1_catalog_proto_rawDescData []byte
+)
+
+func file_library_v1_catalog_proto_rawDescGZIP() []byte {
+	file_library_v1_catalog_proto_rawDescOnce.Do(func() {
+		file_library_v1_catalog_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_library_v1_catalog_proto_rawDesc), len(file_library_v1_catalog_proto_rawDesc)))
+	})
+	return file_library_v1_catalog_proto_rawDescData
+}
+
+var file_library_v1_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_library_v1_catalog_proto_goTypes = []any{
+	(*Author)(nil),                // 0: library.v1.Author
+	(*Contributor)(nil),           // 1: library.v1.Contributor
+	(*ContributorInput)(nil),      // 2: library.v1.ContributorInput
+	(*Subject)(nil),               // 3: library.v1.Subject
+	(*Publisher)(nil),             // 4: library.v1.Publisher
+	(*Series)(nil),                // 5: library.v1.Series
+	(*Book)(nil),                  // 6: library.v1.Book
+	(*BookCover)(nil),             // 7: library.v1.BookCover
+	(*CreateAuthorRequest)(nil),   // 8: library.v1.CreateAuthorRequest
+	(*GetAuthorRequest)(nil),      // 9: library.v1.GetAuthorRequest
+	(*ListAuthorsRequest)(nil),    // 10: library.v1.ListAuthorsRequest
+	(*ListAuthorsResponse)(nil),   // 11: library.v1.ListAuthorsResponse
+	(*UpdateAuthorRequest)(nil),   // 12: library.v1.UpdateAuthorRequest
+	(*DeleteAuthorRequest)(nil),   // 13: library.v1.DeleteAuthorRequest
+	(*CreateBookRequest)(nil),     // 14: library.v1.CreateBookRequest
+	(*GetBookRequest)(nil),        // 15: library.v1.GetBookRequest
+	(*ListBooksRequest)(nil),      // 16: library.v1.ListBooksRequest
+	(*ListBooksResponse)(nil),     // 17: library.v1.ListBooksResponse
+	(*UpdateBookRequest)(nil),     // 18: library.v1.UpdateBookRequest
+	(*DeleteBookRequest)(nil),     // 19: library.v1.DeleteBookRequest
+	nil,                           // 20: library.v1.BookCover.ThumbnailsEntry
+	(*timestamppb.Timestamp)(nil), // 21: google.protobuf.Timestamp
+	(*PageRequest)(nil),           // 22: library.v1.PageRequest
+	(*PageInfo)(nil),              // 23: library.v1.PageInfo
+	(*emptypb.Empty)(nil),         // 24: google.protobuf.Empty
+}
+var file_library_v1_catalog_proto_depIdxs = []int32{
+	21, // 0: library.v1.Author.created_at:type_name -> google.protobuf.Timestamp
+	21, // 1: library.v1.Author.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: library.v1.Contributor.author:type_name -> library.v1.Author
+	21, // 3: library.v1.Book.published_at:type_name -> google.protobuf.Timestamp
+	21, // 4: library.v1.Book.created_at:type_name -> google.protobuf.Timestamp
+	21, // 5: library.v1.Book.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 6: library.v1.Book.contributors:type_name -> library.v1.Contributor
+	3,  // 7: library.v1.Book.subjects:type_name -> library.v1.Subject
+	4,  // 8: library.v1.Book.publisher:type_name -> library.v1.Publisher
+	5,  // 9: library.v1.Book.series:type_name -> library.v1.Series
+	7,  // 10: library.v1.Book.cover:type_name -> library.v1.BookCover
+	20, // 11: library.v1.BookCover.thumbnails:type_name -> library.v1.BookCover.ThumbnailsEntry
+	22, // 12: library.v1.ListAuthorsRequest.page:type_name -> library.v1.PageRequest
+	0,  // 13: library.v1.ListAuthorsResponse.authors:type_name -> library.v1.Author
+	23, // 14: library.v1.ListAuthorsResponse.pagination:type_name -> library.v1.PageInfo
+	21, // 15: library.v1.CreateBookRequest.published_at:type_name -> google.protobuf.Timestamp
+	2,  // 16: library.v1.CreateBookRequest.contributors:type_name -> library.v1.ContributorInput
+	22, // 17: library.v1.ListBooksRequest.page:type_name -> library.v1.PageRequest
+	6,  // 18: library.v1.ListBooksResponse.books:type_name -> library.v1.Book
+	23, // 19: library.v1.ListBooksResponse.pagination:type_name -> library.v1.PageInfo
+	21, // 20: library.v1.UpdateBookRequest.published_at:type_name -> google.protobuf.Timestamp
+	2,  // 21: library.v1.UpdateBookRequest.contributors:type_name -> library.v1.ContributorInput
+	8,  // 22: library.v1.CatalogService.CreateAuthor:input_type -> library.v1.CreateAuthorRequest
+	9,  // 23: library.v1.CatalogService.GetAuthor:input_type -> library.v1.GetAuthorRequest
+	10, // 24: library.v1.CatalogService.ListAuthors:input_type -> library.v1.ListAuthorsRequest
+	12, // 25: library.v1.CatalogService.UpdateAuthor:input_type -> library.v1.UpdateAuthorRequest
+	13, // 26: library.v1.CatalogService.DeleteAuthor:input_type -> library.v1.DeleteAuthorRequest
+	14, // 27: library.v1.CatalogService.CreateBook:input_type -> library.v1.CreateBookRequest
+	15, // 28: library.v1.CatalogService.GetBook:input_type -> library.v1.GetBookRequest
+	16, // 29: library.v1.CatalogService.ListBooks:input_type -> library.v1.ListBooksRequest
+	18, // 30: library.v1.CatalogService.UpdateBook:input_type -> library.v1.UpdateBookRequest
+	19, // 31: library.v1.CatalogService.DeleteBook:input_type -> library.v1.DeleteBookRequest
+	0,  // 32: library.v1.CatalogService.CreateAuthor:output_type -> library.v1.Author
+	0,  // 33: library.v1.CatalogService.GetAuthor:output_type -> library.v1.Author
+	11, // 34: library.v1.CatalogService.ListAuthors:output_type -> library.v1.ListAuthorsResponse
+	0,  // 35: library.v1.CatalogService.UpdateAuthor:output_type -> library.v1.Author
+	24, // 36: library.v1.CatalogService.DeleteAuthor:output_type -> google.protobuf.Empty
+	6,  // 37: library.v1.CatalogService.CreateBook:output_type -> library.v1.Book
+	6,  // 38: library.v1.CatalogService.GetBook:output_type -> library.v1.Book
+	17, // 39: library.v1.CatalogService.ListBooks:output_type -> library.v1.ListBooksResponse
+	6,  // 40: library.v1.CatalogService.UpdateBook:output_type -> library.v1.Book
+	24, // 41: library.v1.CatalogService.DeleteBook:output_type -> google.protobuf.Empty
+	32, // [32:42] is the sub-list for method output_type
+	22, // [22:32] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_library_v1_catalog_proto_init() }
+func file_library_v1_catalog_proto_init() {
+	if File_library_v1_catalog_proto != nil {
+		return
+	}
+	file_library_v1_common_proto_init()
+	file_library_v1_catalog_proto_msgTypes[18].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_library_v1_catalog_proto_rawDesc), len(file_library_v1_catalog_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   21,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_library_v1_catalog_proto_goTypes,
+		DependencyIndexes: file_library_v1_catalog_proto_depIdxs,
+		MessageInfos:      file_library_v1_catalog_proto_msgTypes,
+	}.Build()
+	File_library_v1_catalog_proto = out.File
+	file_library_v1_catalog_proto_goTypes = nil
+	file_library_v1_catalog_proto_depIdxs = nil
+}
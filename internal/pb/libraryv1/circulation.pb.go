@@ -0,0 +1,714 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: library/v1/circulation.proto
+
+package libraryv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Borrowing struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	BookId string                 `protobuf:"bytes,2,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Book   *Book                  `protobuf:"bytes,3,opt,name=book,proto3" json:"book,omitempty"`
+	// Empty, like borrower, once the loan has been anonymized.
+	BorrowerId     string                 `protobuf:"bytes,4,opt,name=borrower_id,json=borrowerId,proto3" json:"borrower_id,omitempty"`
+	Borrower       *Borrower              `protobuf:"bytes,5,opt,name=borrower,proto3" json:"borrower,omitempty"`
+	BorrowedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=borrowed_at,json=borrowedAt,proto3" json:"borrowed_at,omitempty"`
+	DueDate        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	ReturnedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=returned_at,json=returnedAt,proto3" json:"returned_at,omitempty"`
+	Status         string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	BranchId       string                 `protobuf:"bytes,12,opt,name=branch_id,json=branchId,proto3" json:"branch_id,omitempty"`
+	ReturnBranchId string                 `protobuf:"bytes,13,opt,name=return_branch_id,json=returnBranchId,proto3" json:"return_branch_id,omitempty"`
+	AnonymizedAt   *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=anonymized_at,json=anonymizedAt,proto3" json:"anonymized_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Borrowing) Reset() {
+	*x = Borrowing{}
+	mi := &file_library_v1_circulation_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Borrowing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Borrowing) ProtoMessage() {}
+
+func (x *Borrowing) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_circulation_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Borrowing.ProtoReflect.Descriptor instead.
+func (*Borrowing) Descriptor() ([]byte, []int) {
+	return file_library_v1_circulation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Borrowing) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Borrowing) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *Borrowing) GetBook() *Book {
+	if x != nil {
+		return x.Book
+	}
+	return nil
+}
+
+func (x *Borrowing) GetBorrowerId() string {
+	if x != nil {
+		return x.BorrowerId
+	}
+	return ""
+}
+
+func (x *Borrowing) GetBorrower() *Borrower {
+	if x != nil {
+		return x.Borrower
+	}
+	return nil
+}
+
+func (x *Borrowing) GetBorrowedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BorrowedAt
+	}
+	return nil
+}
+
+func (x *Borrowing) GetDueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueDate
+	}
+	return nil
+}
+
+func (x *Borrowing) GetReturnedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReturnedAt
+	}
+	return nil
+}
+
+func (x *Borrowing) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Borrowing) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Borrowing) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Borrowing) GetBranchId() string {
+	if x != nil {
+		return x.BranchId
+	}
+	return ""
+}
+
+func (x *Borrowing) GetReturnBranchId() string {
+	if x != nil {
+		return x.ReturnBranchId
+	}
+	return ""
+}
+
+func (x *Borrowing) GetAnonymizedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AnonymizedAt
+	}
+	return nil
+}
+
+type BorrowBookRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	BookId     string                 `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	BorrowerId string                 `protobuf:"bytes,2,opt,name=borrower_id,json=borrowerId,proto3" json:"borrower_id,omitempty"`
+	DueDate    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	BranchId   string                 `protobuf:"bytes,4,opt,name=branch_id,json=branchId,proto3" json:"branch_id,omitempty"`
+	// override_blocks lets the loan go ahead despite overridable blocks.
+	OverrideBlocks bool `protobuf:"varint,5,opt,name=override_blocks,json=overrideBlocks,proto3" json:"override_blocks,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BorrowBookRequest) Reset() {
+	*x = BorrowBookRequest{}
+	mi := &file_library_v1_circulation_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BorrowBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BorrowBookRequest) ProtoMessage() {}
+
+func (x *BorrowBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_circulation_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BorrowBookRequest.ProtoReflect.Descriptor instead.
+func (*BorrowBookRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_circulation_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BorrowBookRequest) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *BorrowBookRequest) GetBorrowerId() string {
+	if x != nil {
+		return x.BorrowerId
+	}
+	return ""
+}
+
+func (x *BorrowBookRequest) GetDueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueDate
+	}
+	return nil
+}
+
+func (x *BorrowBookRequest) GetBranchId() string {
+	if x != nil {
+		return x.BranchId
+	}
+	return ""
+}
+
+func (x *BorrowBookRequest) GetOverrideBlocks() bool {
+	if x != nil {
+		return x.OverrideBlocks
+	}
+	return false
+}
+
+type ReturnBookRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	BorrowingId string                 `protobuf:"bytes,1,opt,name=borrowing_id,json=borrowingId,proto3" json:"borrowing_id,omitempty"`
+	// branch_id is where the item is checked in.
+	BranchId      string `protobuf:"bytes,2,opt,name=branch_id,json=branchId,proto3" json:"branch_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReturnBookRequest) Reset() {
+	*x = ReturnBookRequest{}
+	mi := &file_library_v1_circulation_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReturnBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReturnBookRequest) ProtoMessage() {}
+
+func (x *ReturnBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_circulation_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReturnBookRequest.ProtoReflect.Descriptor instead.
+func (*ReturnBookRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_circulation_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ReturnBookRequest) GetBorrowingId() string {
+	if x != nil {
+		return x.BorrowingId
+	}
+	return ""
+}
+
+func (x *ReturnBookRequest) GetBranchId() string {
+	if x != nil {
+		return x.BranchId
+	}
+	return ""
+}
+
+type GetBorrowingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBorrowingRequest) Reset() {
+	*x = GetBorrowingRequest{}
+	mi := &file_library_v1_circulation_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBorrowingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBorrowingRequest) ProtoMessage() {}
+
+func (x *GetBorrowingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_circulation_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBorrowingRequest.ProtoReflect.Descriptor instead.
+func (*GetBorrowingRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_circulation_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetBorrowingRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListBorrowingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          *PageRequest           `protobuf:"bytes,1,opt,name=page,proto3" json:"page,omitempty"`
+	BranchId      string                 `protobuf:"bytes,2,opt,name=branch_id,json=branchId,proto3" json:"branch_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBorrowingsRequest) Reset() {
+	*x = ListBorrowingsRequest{}
+	mi := &file_library_v1_circulation_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBorrowingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBorrowingsRequest) ProtoMessage() {}
+
+func (x *ListBorrowingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_circulation_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBorrowingsRequest.ProtoReflect.Descriptor instead.
+func (*ListBorrowingsRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_circulation_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListBorrowingsRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+func (x *ListBorrowingsRequest) GetBranchId() string {
+	if x != nil {
+		return x.BranchId
+	}
+	return ""
+}
+
+type ListBorrowingsByBorrowerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BorrowerId    string                 `protobuf:"bytes,1,opt,name=borrower_id,json=borrowerId,proto3" json:"borrower_id,omitempty"`
+	Page          *PageRequest           `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBorrowingsByBorrowerRequest) Reset() {
+	*x = ListBorrowingsByBorrowerRequest{}
+	mi := &file_library_v1_circulation_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBorrowingsByBorrowerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBorrowingsByBorrowerRequest) ProtoMessage() {}
+
+func (x *ListBorrowingsByBorrowerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_circulation_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBorrowingsByBorrowerRequest.ProtoReflect.Descriptor instead.
+func (*ListBorrowingsByBorrowerRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_circulation_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListBorrowingsByBorrowerRequest) GetBorrowerId() string {
+	if x != nil {
+		return x.BorrowerId
+	}
+	return ""
+}
+
+func (x *ListBorrowingsByBorrowerRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type ListOverdueBorrowingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          *PageRequest           `protobuf:"bytes,1,opt,name=page,proto3" json:"page,omitempty"`
+	BranchId      string                 `protobuf:"bytes,2,opt,name=branch_id,json=branchId,proto3" json:"branch_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOverdueBorrowingsRequest) Reset() {
+	*x = ListOverdueBorrowingsRequest{}
+	mi := &file_library_v1_circulation_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOverdueBorrowingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOverdueBorrowingsRequest) ProtoMessage() {}
+
+func (x *ListOverdueBorrowingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_circulation_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOverdueBorrowingsRequest.ProtoReflect.Descriptor instead.
+func (*ListOverdueBorrowingsRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_circulation_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListOverdueBorrowingsRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+func (x *ListOverdueBorrowingsRequest) GetBranchId() string {
+	if x != nil {
+		return x.BranchId
+	}
+	return ""
+}
+
+type ListBorrowingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Borrowings    []*Borrowing           `protobuf:"bytes,1,rep,name=borrowings,proto3" json:"borrowings,omitempty"`
+	Pagination    *PageInfo              `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBorrowingsResponse) Reset() {
+	*x = ListBorrowingsResponse{}
+	mi := &file_library_v1_circulation_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBorrowingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBorrowingsResponse) ProtoMessage() {}
+
+func (x *ListBorrowingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_circulation_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBorrowingsResponse.ProtoReflect.Descriptor instead.
+func (*ListBorrowingsResponse) Descriptor() ([]byte, []int) {
+	return file_library_v1_circulation_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListBorrowingsResponse) GetBorrowings() []*Borrowing {
+	if x != nil {
+		return x.Borrowings
+	}
+	return nil
+}
+
+func (x *ListBorrowingsResponse) GetPagination() *PageInfo {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+var File_library_v1_circulation_proto protoreflect.FileDescriptor
+
+const file_library_v1_circulation_proto_rawDesc = "" +
+	"\n" +
+	"\x1clibrary/v1/circulation.proto\x12\n" +
+	"library.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x18library/v1/catalog.proto\x1a\x17library/v1/common.proto\x1a\x18library/v1/patrons.proto\"\xf4\x04\n" +
+	"\tBorrowing\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\abook_id\x18\x02 \x01(\tR\x06bookId\x12$\n" +
+	"\x04book\x18\x03 \x01(\v2\x10.library.v1.BookR\x04book\x12\x1f\n" +
+	"\vborrower_id\x18\x04 \x01(\tR\n" +
+	"borrowerId\x120\n" +
+	"\bborrower\x18\x05 \x01(\v2\x14.library.v1.BorrowerR\bborrower\x12;\n" +
+	"\vborrowed_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"borrowedAt\x125\n" +
+	"\bdue_date\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\adueDate\x12;\n" +
+	"\vreturned_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"returnedAt\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1b\n" +
+	"\tbranch_id\x18\f \x01(\tR\bbranchId\x12(\n" +
+	"\x10return_branch_id\x18\r \x01(\tR\x0ereturnBranchId\x12?\n" +
+	"\ranonymized_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\fanonymizedAt\"\xca\x01\n" +
+	"\x11BorrowBookRequest\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\tR\x06bookId\x12\x1f\n" +
+	"\vborrower_id\x18\x02 \x01(\tR\n" +
+	"borrowerId\x125\n" +
+	"\bdue_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\adueDate\x12\x1b\n" +
+	"\tbranch_id\x18\x04 \x01(\tR\bbranchId\x12'\n" +
+	"\x0foverride_blocks\x18\x05 \x01(\bR\x0eoverrideBlocks\"S\n" +
+	"\x11ReturnBookRequest\x12!\n" +
+	"\fborrowing_id\x18\x01 \x01(\tR\vborrowingId\x12\x1b\n" +
+	"\tbranch_id\x18\x02 \x01(\tR\bbranchId\"%\n" +
+	"\x13GetBorrowingRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"a\n" +
+	"\x15ListBorrowingsRequest\x12+\n" +
+	"\x04page\x18\x01 \x01(\v2\x17.library.v1.PageRequestR\x04page\x12\x1b\n" +
+	"\tbranch_id\x18\x02 \x01(\tR\bbranchId\"o\n" +
+	"\x1fListBorrowingsByBorrowerRequest\x12\x1f\n" +
+	"\vborrower_id\x18\x01 \x01(\tR\n" +
+	"borrowerId\x12+\n" +
+	"\x04page\x18\x02 \x01(\v2\x17.library.v1.PageRequestR\x04page\"h\n" +
+	"\x1cListOverdueBorrowingsRequest\x12+\n" +
+	"\x04page\x18\x01 \x01(\v2\x17.library.v1.PageRequestR\x04page\x12\x1b\n" +
+	"\tbranch_id\x18\x02 \x01(\tR\bbranchId\"\x85\x01\n" +
+	"\x16ListBorrowingsResponse\x125\n" +
+	"\n" +
+	"borrowings\x18\x01 \x03(\v2\x15.library.v1.BorrowingR\n" +
+	"borrowings\x124\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x14.library.v1.PageInfoR\n" +
+	"pagination2\xd8\x04\n" +
+	"\x12CirculationService\x12B\n" +
+	"\n" +
+	"BorrowBook\x12\x1d.library.v1.BorrowBookRequest\x1a\x15.library.v1.Borrowing\x12B\n" +
+	"\n" +
+	"ReturnBook\x12\x1d.library.v1.ReturnBookRequest\x1a\x15.library.v1.Borrowing\x12F\n" +
+	"\fGetBorrowing\x12\x1f.library.v1.GetBorrowingRequest\x1a\x15.library.v1.Borrowing\x12W\n" +
+	"\x0eListBorrowings\x12!.library.v1.ListBorrowingsRequest\x1a\".library.v1.ListBorrowingsResponse\x12k\n" +
+	"\x18ListBorrowingsByBorrower\x12+.library.v1.ListBorrowingsByBorrowerRequest\x1a\".library.v1.ListBorrowingsResponse\x12e\n" +
+	"\x15ListOverdueBorrowings\x12(.library.v1.ListOverdueBorrowingsRequest\x1a\".library.v1.ListBorrowingsResponse\x12E\n" +
+	"\x13UpdateOverdueStatus\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.EmptyB7Z5library-management-go/internal/pb/libraryv1;libraryv1b\x06proto3"
+
+var (
+	file_library_v1_circulation_proto_rawDescOnce sync.Once
+	file_library_v1_circulation_proto_rawDescData []byte
+)
+
+func file_library_v1_circulation_proto_rawDescGZIP() []byte {
+	file_library_v1_circulation_proto_rawDescOnce.Do(func() {
+		file_library_v1_circulation_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_library_v1_circulation_proto_rawDesc), len(file_library_v1_circulation_proto_rawDesc)))
+	})
+	return file_library_v1_circulation_proto_rawDescData
+}
+
+var file_library_v1_circulation_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_library_v1_circulation_proto_goTypes = []any{
+	(*Borrowing)(nil),                       // 0: library.v1.Borrowing
+	(*BorrowBookRequest)(nil),               // 1: library.v1.BorrowBookRequest
+	(*ReturnBookRequest)(nil),               // 2: library.v1.ReturnBookRequest
+	(*GetBorrowingRequest)(nil),             // 3: library.v1.GetBorrowingRequest
+	(*ListBorrowingsRequest)(nil),           // 4: library.v1.ListBorrowingsRequest
+	(*ListBorrowingsByBorrowerRequest)(nil), // 5: library.v1.ListBorrowingsByBorrowerRequest
+	(*ListOverdueBorrowingsRequest)(nil),    // 6: library.v1.ListOverdueBorrowingsRequest
+	(*ListBorrowingsResponse)(nil),          // 7: library.v1.ListBorrowingsResponse
+	(*Book)(nil),                            // 8: library.v1.Book
+	(*Borrower)(nil),                        // 9: library.v1.Borrower
+	(*timestamppb.Timestamp)(nil),           // 10: google.protobuf.Timestamp
+	(*PageRequest)(nil),                     // 11: library.v1.PageRequest
+	(*PageInfo)(nil),                        // 12: library.v1.PageInfo
+	(*emptypb.Empty)(nil),                   // 13: google.protobuf.Empty
+}
+var file_library_v1_circulation_proto_depIdxs = []int32{
+	8,  // 0: library.v1.Borrowing.book:type_name -> library.v1.Book
+	9,  // 1: library.v1.Borrowing.borrower:type_name -> library.v1.Borrower
+	10, // 2: library.v1.Borrowing.borrowed_at:type_name -> google.protobuf.Timestamp
+	10, // 3: library.v1.Borrowing.due_date:type_name -> google.protobuf.Timestamp
+	10, // 4: library.v1.Borrowing.returned_at:type_name -> google.protobuf.Timestamp
+	10, // 5: library.v1.Borrowing.created_at:type_name -> google.protobuf.Timestamp
+	10, // 6: library.v1.Borrowing.updated_at:type_name -> google.protobuf.Timestamp
+	10, // 7: library.v1.Borrowing.anonymized_at:type_name -> google.protobuf.Timestamp
+	10, // 8: library.v1.BorrowBookRequest.due_date:type_name -> google.protobuf.Timestamp
+	11, // 9: library.v1.ListBorrowingsRequest.page:type_name -> library.v1.PageRequest
+	11, // 10: library.v1.ListBorrowingsByBorrowerRequest.page:type_name -> library.v1.PageRequest
+	11, // 11: library.v1.ListOverdueBorrowingsRequest.page:type_name -> library.v1.PageRequest
+	0,  // 12: library.v1.ListBorrowingsResponse.borrowings:type_name -> library.v1.Borrowing
+	12, // 13: library.v1.ListBorrowingsResponse.pagination:type_name -> library.v1.PageInfo
+	1,  // 14: library.v1.CirculationService.BorrowBook:input_type -> library.v1.BorrowBookRequest
+	2,  // 15: library.v1.CirculationService.ReturnBook:input_type -> library.v1.ReturnBookRequest
+	3,  // 16: library.v1.CirculationService.GetBorrowing:input_type -> library.v1.GetBorrowingRequest
+	4,  // 17: library.v1.CirculationService.ListBorrowings:input_type -> library.v1.ListBorrowingsRequest
+	5,  // 18: library.v1.CirculationService.ListBorrowingsByBorrower:input_type -> library.v1.ListBorrowingsByBorrowerRequest
+	6,  // 19: library.v1.CirculationService.ListOverdueBorrowings:input_type -> library.v1.ListOverdueBorrowingsRequest
+	13, // 20: library.v1.CirculationService.UpdateOverdueStatus:input_type -> google.protobuf.Empty
+	0,  // 21: library.v1.CirculationService.BorrowBook:output_type -> library.v1.Borrowing
+	0,  // 22: library.v1.CirculationService.ReturnBook:output_type -> library.v1.Borrowing
+	0,  // 23: library.v1.CirculationService.GetBorrowing:output_type -> library.v1.Borrowing
+	7,  // 24: library.v1.CirculationService.ListBorrowings:output_type -> library.v1.ListBorrowingsResponse
+	7,  // 25: library.v1.CirculationService.ListBorrowingsByBorrower:output_type -> library.v1.ListBorrowingsResponse
+	7,  // 26: library.v1.CirculationService.ListOverdueBorrowings:output_type -> library.v1.ListBorrowingsResponse
+	13, // 27: library.v1.CirculationService.UpdateOverdueStatus:output_type -> google.protobuf.Empty
+	21, // [21:28] is the sub-list for method output_type
+	14, // [14:21] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_library_v1_circulation_proto_init() }
+func file_library_v1_circulation_proto_init() {
+	if File_library_v1_circulation_proto != nil {
+		return
+	}
+	file_library_v1_catalog_proto_init()
+	file_library_v1_common_proto_init()
+	file_library_v1_patrons_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_library_v1_circulation_proto_rawDesc), len(file_library_v1_circulation_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_library_v1_circulation_proto_goTypes,
+		DependencyIndexes: file_library_v1_circulation_proto_depIdxs,
+		MessageInfos:      file_library_v1_circulation_proto_msgTypes,
+	}.Build()
+	File_library_v1_circulation_proto = out.File
+	file_library_v1_circulation_proto_goTypes = nil
+	file_library_v1_circulation_proto_depIdxs = nil
+}
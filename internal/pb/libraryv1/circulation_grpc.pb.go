@@ -0,0 +1,356 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: library/v1/circulation.proto
+
+package libraryv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CirculationService_BorrowBook_FullMethodName               = "/library.v1.CirculationService/BorrowBook"
+	CirculationService_ReturnBook_FullMethodName               = "/library.v1.CirculationService/ReturnBook"
+	CirculationService_GetBorrowing_FullMethodName             = "/library.v1.CirculationService/GetBorrowing"
+	CirculationService_ListBorrowings_FullMethodName           = "/library.v1.CirculationService/ListBorrowings"
+	CirculationService_ListBorrowingsByBorrower_FullMethodName = "/library.v1.CirculationService/ListBorrowingsByBorrower"
+	CirculationService_ListOverdueBorrowings_FullMethodName    = "/library.v1.CirculationService/ListOverdueBorrowings"
+	CirculationService_UpdateOverdueStatus_FullMethodName      = "/library.v1.CirculationService/UpdateOverdueStatus"
+)
+
+// CirculationServiceClient is the client API for CirculationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CirculationService handles loans. It mirrors BorrowingService and the
+// /borrowings REST routes.
+type CirculationServiceClient interface {
+	BorrowBook(ctx context.Context, in *BorrowBookRequest, opts ...grpc.CallOption) (*Borrowing, error)
+	ReturnBook(ctx context.Context, in *ReturnBookRequest, opts ...grpc.CallOption) (*Borrowing, error)
+	GetBorrowing(ctx context.Context, in *GetBorrowingRequest, opts ...grpc.CallOption) (*Borrowing, error)
+	ListBorrowings(ctx context.Context, in *ListBorrowingsRequest, opts ...grpc.CallOption) (*ListBorrowingsResponse, error)
+	ListBorrowingsByBorrower(ctx context.Context, in *ListBorrowingsByBorrowerRequest, opts ...grpc.CallOption) (*ListBorrowingsResponse, error)
+	ListOverdueBorrowings(ctx context.Context, in *ListOverdueBorrowingsRequest, opts ...grpc.CallOption) (*ListBorrowingsResponse, error)
+	UpdateOverdueStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type circulationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCirculationServiceClient(cc grpc.ClientConnInterface) CirculationServiceClient {
+	return &circulationServiceClient{cc}
+}
+
+func (c *circulationServiceClient) BorrowBook(ctx context.Context, in *BorrowBookRequest, opts ...grpc.CallOption) (*Borrowing, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Borrowing)
+	err := c.cc.Invoke(ctx, CirculationService_BorrowBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationServiceClient) ReturnBook(ctx context.Context, in *ReturnBookRequest, opts ...grpc.CallOption) (*Borrowing, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Borrowing)
+	err := c.cc.Invoke(ctx, CirculationService_ReturnBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationServiceClient) GetBorrowing(ctx context.Context, in *GetBorrowingRequest, opts ...grpc.CallOption) (*Borrowing, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Borrowing)
+	err := c.cc.Invoke(ctx, CirculationService_GetBorrowing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationServiceClient) ListBorrowings(ctx context.Context, in *ListBorrowingsRequest, opts ...grpc.CallOption) (*ListBorrowingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBorrowingsResponse)
+	err := c.cc.Invoke(ctx, CirculationService_ListBorrowings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationServiceClient) ListBorrowingsByBorrower(ctx context.Context, in *ListBorrowingsByBorrowerRequest, opts ...grpc.CallOption) (*ListBorrowingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBorrowingsResponse)
+	err := c.cc.Invoke(ctx, CirculationService_ListBorrowingsByBorrower_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationServiceClient) ListOverdueBorrowings(ctx context.Context, in *ListOverdueBorrowingsRequest, opts ...grpc.CallOption) (*ListBorrowingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBorrowingsResponse)
+	err := c.cc.Invoke(ctx, CirculationService_ListOverdueBorrowings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationServiceClient) UpdateOverdueStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, CirculationService_UpdateOverdueStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CirculationServiceServer is the server API for CirculationService service.
+// All implementations must embed UnimplementedCirculationServiceServer
+// for forward compatibility.
+//
+// CirculationService handles loans. It mirrors BorrowingService and the
+// /borrowings REST routes.
+type CirculationServiceServer interface {
+	BorrowBook(context.Context, *BorrowBookRequest) (*Borrowing, error)
+	ReturnBook(context.Context, *ReturnBookRequest) (*Borrowing, error)
+	GetBorrowing(context.Context, *GetBorrowingRequest) (*Borrowing, error)
+	ListBorrowings(context.Context, *ListBorrowingsRequest) (*ListBorrowingsResponse, error)
+	ListBorrowingsByBorrower(context.Context, *ListBorrowingsByBorrowerRequest) (*ListBorrowingsResponse, error)
+	ListOverdueBorrowings(context.Context, *ListOverdueBorrowingsRequest) (*ListBorrowingsResponse, error)
+	UpdateOverdueStatus(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	mustEmbedUnimplementedCirculationServiceServer()
+}
+
+// UnimplementedCirculationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCirculationServiceServer struct{}
+
+func (UnimplementedCirculationServiceServer) BorrowBook(context.Context, *BorrowBookRequest) (*Borrowing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BorrowBook not implemented")
+}
+func (UnimplementedCirculationServiceServer) ReturnBook(context.Context, *ReturnBookRequest) (*Borrowing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReturnBook not implemented")
+}
+func (UnimplementedCirculationServiceServer) GetBorrowing(context.Context, *GetBorrowingRequest) (*Borrowing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBorrowing not implemented")
+}
+func (UnimplementedCirculationServiceServer) ListBorrowings(context.Context, *ListBorrowingsRequest) (*ListBorrowingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBorrowings not implemented")
+}
+func (UnimplementedCirculationServiceServer) ListBorrowingsByBorrower(context.Context, *ListBorrowingsByBorrowerRequest) (*ListBorrowingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBorrowingsByBorrower not implemented")
+}
+func (UnimplementedCirculationServiceServer) ListOverdueBorrowings(context.Context, *ListOverdueBorrowingsRequest) (*ListBorrowingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOverdueBorrowings not implemented")
+}
+func (UnimplementedCirculationServiceServer) UpdateOverdueStatus(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateOverdueStatus not implemented")
+}
+func (UnimplementedCirculationServiceServer) mustEmbedUnimplementedCirculationServiceServer() {}
+func (UnimplementedCirculationServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeCirculationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CirculationServiceServer will
+// result in compilation errors.
+type UnsafeCirculationServiceServer interface {
+	mustEmbedUnimplementedCirculationServiceServer()
+}
+
+func RegisterCirculationServiceServer(s grpc.ServiceRegistrar, srv CirculationServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCirculationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CirculationService_ServiceDesc, srv)
+}
+
+func _CirculationService_BorrowBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BorrowBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServiceServer).BorrowBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CirculationService_BorrowBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServiceServer).BorrowBook(ctx, req.(*BorrowBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CirculationService_ReturnBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReturnBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServiceServer).ReturnBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CirculationService_ReturnBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServiceServer).ReturnBook(ctx, req.(*ReturnBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CirculationService_GetBorrowing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBorrowingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServiceServer).GetBorrowing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CirculationService_GetBorrowing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServiceServer).GetBorrowing(ctx, req.(*GetBorrowingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CirculationService_ListBorrowings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBorrowingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServiceServer).ListBorrowings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CirculationService_ListBorrowings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServiceServer).ListBorrowings(ctx, req.(*ListBorrowingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CirculationService_ListBorrowingsByBorrower_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBorrowingsByBorrowerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServiceServer).ListBorrowingsByBorrower(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CirculationService_ListBorrowingsByBorrower_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServiceServer).ListBorrowingsByBorrower(ctx, req.(*ListBorrowingsByBorrowerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CirculationService_ListOverdueBorrowings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOverdueBorrowingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServiceServer).ListOverdueBorrowings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CirculationService_ListOverdueBorrowings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServiceServer).ListOverdueBorrowings(ctx, req.(*ListOverdueBorrowingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CirculationService_UpdateOverdueStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServiceServer).UpdateOverdueStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CirculationService_UpdateOverdueStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServiceServer).UpdateOverdueStatus(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CirculationService_ServiceDesc is the grpc.ServiceDesc for CirculationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CirculationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "library.v1.CirculationService",
+	HandlerType: (*CirculationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "BorrowBook",
+			Handler:    _CirculationService_BorrowBook_Handler,
+		},
+		{
+			MethodName: "ReturnBook",
+			Handler:    _CirculationService_ReturnBook_Handler,
+		},
+		{
+			MethodName: "GetBorrowing",
+			Handler:    _CirculationService_GetBorrowing_Handler,
+		},
+		{
+			MethodName: "ListBorrowings",
+			Handler:    _CirculationService_ListBorrowings_Handler,
+		},
+		{
+			MethodName: "ListBorrowingsByBorrower",
+			Handler:    _CirculationService_ListBorrowingsByBorrower_Handler,
+		},
+		{
+			MethodName: "ListOverdueBorrowings",
+			Handler:    _CirculationService_ListOverdueBorrowings_Handler,
+		},
+		{
+			MethodName: "UpdateOverdueStatus",
+			Handler:    _CirculationService_UpdateOverdueStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "library/v1/circulation.proto",
+}
@@ -0,0 +1,805 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: library/v1/patrons.proto
+
+package libraryv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Borrower struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name       string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email      string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Phone      string                 `protobuf:"bytes,4,opt,name=phone,proto3" json:"phone,omitempty"`
+	Address    string                 `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	CardNumber string                 `protobuf:"bytes,8,opt,name=card_number,json=cardNumber,proto3" json:"card_number,omitempty"`
+	CategoryId string                 `protobuf:"bytes,9,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	// active, suspended or expired
+	Status          string                 `protobuf:"bytes,10,opt,name=status,proto3" json:"status,omitempty"`
+	MembershipStart *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=membership_start,json=membershipStart,proto3" json:"membership_start,omitempty"`
+	// Unset for memberships that do not expire.
+	MembershipExpiresAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=membership_expires_at,json=membershipExpiresAt,proto3" json:"membership_expires_at,omitempty"`
+	// Blocks currently in force; only set by GetBorrower.
+	Blocks []*BorrowerBlock `protobuf:"bytes,13,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	// Whether returned loans are kept in the borrower's history rather than
+	// anonymized after the retention period.
+	KeepHistory   bool `protobuf:"varint,14,opt,name=keep_history,json=keepHistory,proto3" json:"keep_history,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Borrower) Reset() {
+	*x = Borrower{}
+	mi := &file_library_v1_patrons_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Borrower) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Borrower) ProtoMessage() {}
+
+func (x *Borrower) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_patrons_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Borrower.ProtoReflect.Descriptor instead.
+func (*Borrower) Descriptor() ([]byte, []int) {
+	return file_library_v1_patrons_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Borrower) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Borrower) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Borrower) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Borrower) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Borrower) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Borrower) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Borrower) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Borrower) GetCardNumber() string {
+	if x != nil {
+		return x.CardNumber
+	}
+	return ""
+}
+
+func (x *Borrower) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *Borrower) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Borrower) GetMembershipStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MembershipStart
+	}
+	return nil
+}
+
+func (x *Borrower) GetMembershipExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MembershipExpiresAt
+	}
+	return nil
+}
+
+func (x *Borrower) GetBlocks() []*BorrowerBlock {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+func (x *Borrower) GetKeepHistory() bool {
+	if x != nil {
+		return x.KeepHistory
+	}
+	return false
+}
+
+type BorrowerBlock struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// lost_card, behaviour, unpaid_fees or other
+	Reason      string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Note        string `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	CreatedBy   string `protobuf:"bytes,4,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	Overridable bool   `protobuf:"varint,5,opt,name=overridable,proto3" json:"overridable,omitempty"`
+	// Unset for blocks that stay until lifted.
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BorrowerBlock) Reset() {
+	*x = BorrowerBlock{}
+	mi := &file_library_v1_patrons_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BorrowerBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BorrowerBlock) ProtoMessage() {}
+
+func (x *BorrowerBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_patrons_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BorrowerBlock.ProtoReflect.Descriptor instead.
+func (*BorrowerBlock) Descriptor() ([]byte, []int) {
+	return file_library_v1_patrons_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BorrowerBlock) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BorrowerBlock) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BorrowerBlock) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *BorrowerBlock) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *BorrowerBlock) GetOverridable() bool {
+	if x != nil {
+		return x.Overridable
+	}
+	return false
+}
+
+func (x *BorrowerBlock) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *BorrowerBlock) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateBorrowerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Phone         string                 `protobuf:"bytes,3,opt,name=phone,proto3" json:"phone,omitempty"`
+	Address       string                 `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	CategoryId    string                 `protobuf:"bytes,5,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	KeepHistory   bool                   `protobuf:"varint,6,opt,name=keep_history,json=keepHistory,proto3" json:"keep_history,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBorrowerRequest) Reset() {
+	*x = CreateBorrowerRequest{}
+	mi := &file_library_v1_patrons_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBorrowerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBorrowerRequest) ProtoMessage() {}
+
+func (x *CreateBorrowerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_patrons_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBorrowerRequest.ProtoReflect.Descriptor instead.
+func (*CreateBorrowerRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_patrons_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateBorrowerRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateBorrowerRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateBorrowerRequest) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *CreateBorrowerRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *CreateBorrowerRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *CreateBorrowerRequest) GetKeepHistory() bool {
+	if x != nil {
+		return x.KeepHistory
+	}
+	return false
+}
+
+type GetBorrowerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBorrowerRequest) Reset() {
+	*x = GetBorrowerRequest{}
+	mi := &file_library_v1_patrons_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBorrowerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBorrowerRequest) ProtoMessage() {}
+
+func (x *GetBorrowerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_patrons_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBorrowerRequest.ProtoReflect.Descriptor instead.
+func (*GetBorrowerRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_patrons_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetBorrowerRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListBorrowersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          *PageRequest           `protobuf:"bytes,1,opt,name=page,proto3" json:"page,omitempty"`
+	Search        string                 `protobuf:"bytes,2,opt,name=search,proto3" json:"search,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBorrowersRequest) Reset() {
+	*x = ListBorrowersRequest{}
+	mi := &file_library_v1_patrons_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBorrowersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBorrowersRequest) ProtoMessage() {}
+
+func (x *ListBorrowersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_patrons_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBorrowersRequest.ProtoReflect.Descriptor instead.
+func (*ListBorrowersRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_patrons_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListBorrowersRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+func (x *ListBorrowersRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+type ListBorrowersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Borrowers     []*Borrower            `protobuf:"bytes,1,rep,name=borrowers,proto3" json:"borrowers,omitempty"`
+	Pagination    *PageInfo              `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBorrowersResponse) Reset() {
+	*x = ListBorrowersResponse{}
+	mi := &file_library_v1_patrons_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBorrowersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBorrowersResponse) ProtoMessage() {}
+
+func (x *ListBorrowersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_patrons_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBorrowersResponse.ProtoReflect.Descriptor instead.
+func (*ListBorrowersResponse) Descriptor() ([]byte, []int) {
+	return file_library_v1_patrons_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListBorrowersResponse) GetBorrowers() []*Borrower {
+	if x != nil {
+		return x.Borrowers
+	}
+	return nil
+}
+
+func (x *ListBorrowersResponse) GetPagination() *PageInfo {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+type UpdateBorrowerRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name       string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email      string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Phone      string                 `protobuf:"bytes,4,opt,name=phone,proto3" json:"phone,omitempty"`
+	Address    string                 `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	CategoryId string                 `protobuf:"bytes,6,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	// active or suspended; empty leaves the status unchanged.
+	Status string `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	// Unset leaves the choice unchanged.
+	KeepHistory   *bool `protobuf:"varint,8,opt,name=keep_history,json=keepHistory,proto3,oneof" json:"keep_history,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateBorrowerRequest) Reset() {
+	*x = UpdateBorrowerRequest{}
+	mi := &file_library_v1_patrons_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateBorrowerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateBorrowerRequest) ProtoMessage() {}
+
+func (x *UpdateBorrowerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_patrons_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateBorrowerRequest.ProtoReflect.Descriptor instead.
+func (*UpdateBorrowerRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_patrons_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateBorrowerRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateBorrowerRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateBorrowerRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UpdateBorrowerRequest) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *UpdateBorrowerRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *UpdateBorrowerRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *UpdateBorrowerRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateBorrowerRequest) GetKeepHistory() bool {
+	if x != nil && x.KeepHistory != nil {
+		return *x.KeepHistory
+	}
+	return false
+}
+
+type DeleteBorrowerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBorrowerRequest) Reset() {
+	*x = DeleteBorrowerRequest{}
+	mi := &file_library_v1_patrons_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBorrowerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBorrowerRequest) ProtoMessage() {}
+
+func (x *DeleteBorrowerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_library_v1_patrons_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBorrowerRequest.ProtoReflect.Descriptor instead.
+func (*DeleteBorrowerRequest) Descriptor() ([]byte, []int) {
+	return file_library_v1_patrons_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteBorrowerRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_library_v1_patrons_proto protoreflect.FileDescriptor
+
+const file_library_v1_patrons_proto_rawDesc = "" +
+	"\n" +
+	"\x18library/v1/patrons.proto\x12\n" +
+	"library.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17library/v1/common.proto\"\xb1\x04\n" +
+	"\bBorrower\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x14\n" +
+	"\x05phone\x18\x04 \x01(\tR\x05phone\x12\x18\n" +
+	"\aaddress\x18\x05 \x01(\tR\aaddress\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1f\n" +
+	"\vcard_number\x18\b \x01(\tR\n" +
+	"cardNumber\x12\x1f\n" +
+	"\vcategory_id\x18\t \x01(\tR\n" +
+	"categoryId\x12\x16\n" +
+	"\x06status\x18\n" +
+	" \x01(\tR\x06status\x12E\n" +
+	"\x10membership_start\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\x0fmembershipStart\x12N\n" +
+	"\x15membership_expires_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\x13membershipExpiresAt\x121\n" +
+	"\x06blocks\x18\r \x03(\v2\x19.library.v1.BorrowerBlockR\x06blocks\x12!\n" +
+	"\fkeep_history\x18\x0e \x01(\bR\vkeepHistory\"\x82\x02\n" +
+	"\rBorrowerBlock\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x04 \x01(\tR\tcreatedBy\x12 \n" +
+	"\voverridable\x18\x05 \x01(\bR\voverridable\x129\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xb5\x01\n" +
+	"\x15CreateBorrowerRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x14\n" +
+	"\x05phone\x18\x03 \x01(\tR\x05phone\x12\x18\n" +
+	"\aaddress\x18\x04 \x01(\tR\aaddress\x12\x1f\n" +
+	"\vcategory_id\x18\x05 \x01(\tR\n" +
+	"categoryId\x12!\n" +
+	"\fkeep_history\x18\x06 \x01(\bR\vkeepHistory\"$\n" +
+	"\x12GetBorrowerRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"[\n" +
+	"\x14ListBorrowersRequest\x12+\n" +
+	"\x04page\x18\x01 \x01(\v2\x17.library.v1.PageRequestR\x04page\x12\x16\n" +
+	"\x06search\x18\x02 \x01(\tR\x06search\"\x81\x01\n" +
+	"\x15ListBorrowersResponse\x122\n" +
+	"\tborrowers\x18\x01 \x03(\v2\x14.library.v1.BorrowerR\tborrowers\x124\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x14.library.v1.PageInfoR\n" +
+	"pagination\"\xf3\x01\n" +
+	"\x15UpdateBorrowerRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x14\n" +
+	"\x05phone\x18\x04 \x01(\tR\x05phone\x12\x18\n" +
+	"\aaddress\x18\x05 \x01(\tR\aaddress\x12\x1f\n" +
+	"\vcategory_id\x18\x06 \x01(\tR\n" +
+	"categoryId\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12&\n" +
+	"\fkeep_history\x18\b \x01(\bH\x00R\vkeepHistory\x88\x01\x01B\x0f\n" +
+	"\r_keep_history\"'\n" +
+	"\x15DeleteBorrowerRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id2\x8d\x03\n" +
+	"\rPatronService\x12I\n" +
+	"\x0eCreateBorrower\x12!.library.v1.CreateBorrowerRequest\x1a\x14.library.v1.Borrower\x12C\n" +
+	"\vGetBorrower\x12\x1e.library.v1.GetBorrowerRequest\x1a\x14.library.v1.Borrower\x12T\n" +
+	"\rListBorrowers\x12 .library.v1.ListBorrowersRequest\x1a!.library.v1.ListBorrowersResponse\x12I\n" +
+	"\x0eUpdateBorrower\x12!.library.v1.UpdateBorrowerRequest\x1a\x14.library.v1.Borrower\x12K\n" +
+	"\x0eDeleteBorrower\x12!.library.v1.DeleteBorrowerRequest\x1a\x16.google.protobuf.EmptyB7Z5library-management-go/internal/pb/libraryv1;libraryv1b\x06proto3"
+
+var (
+	file_library_v1_patrons_proto_rawDescOnce sync.Once
+	file_library_v1_patrons_proto_rawDescData []byte
+)
+
+func file_library_v1_patrons_proto_rawDescGZIP() []byte {
+	file_library_v1_patrons_proto_rawDescOnce.Do(func() {
+		file_library_v1_patrons_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_library_v1_patrons_proto_rawDesc), len(file_library_v1_patrons_proto_rawDesc)))
+	})
+	return file_library_v1_patrons_proto_rawDescData
+}
+
+var file_library_v1_patrons_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_library_v1_patrons_proto_goTypes = []any{
+	(*Borrower)(nil),              // 0: library.v1.Borrower
+	(*BorrowerBlock)(nil),         // 1: library.v1.BorrowerBlock
+	(*CreateBorrowerRequest)(nil), // 2: library.v1.CreateBorrowerRequest
+	(*GetBorrowerRequest)(nil),    // 3: library.v1.GetBorrowerRequest
+	(*ListBorrowersRequest)(nil),  // 4: library.v1.ListBorrowersRequest
+	(*ListBorrowersResponse)(nil), // 5: library.v1.ListBorrowersResponse
+	(*UpdateBorrowerRequest)(nil), // 6: library.v1.UpdateBorrowerRequest
+	(*DeleteBorrowerRequest)(nil), // 7: library.v1.DeleteBorrowerRequest
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+	(*PageRequest)(nil),           // 9: library.v1.PageRequest
+	(*PageInfo)(nil),              // 10: library.v1.PageInfo
+	(*emptypb.Empty)(nil),         // 11: google.protobuf.Empty
+}
+var file_library_v1_patrons_proto_depIdxs = []int32{
+	8,  // 0: library.v1.Borrower.created_at:type_name -> google.protobuf.Timestamp
+	8,  // 1: library.v1.Borrower.updated_at:type_name -> google.protobuf.Timestamp
+	8,  // 2: library.v1.Borrower.membership_start:type_name -> google.protobuf.Timestamp
+	8,  // 3: library.v1.Borrower.membership_expires_at:type_name -> google.protobuf.Timestamp
+	1,  // 4: library.v1.Borrower.blocks:type_name -> library.v1.BorrowerBlock
+	8,  // 5: library.v1.BorrowerBlock.expires_at:type_name -> google.protobuf.Timestamp
+	8,  // 6: library.v1.BorrowerBlock.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 7: library.v1.ListBorrowersRequest.page:type_name -> library.v1.PageRequest
+	0,  // 8: library.v1.ListBorrowersResponse.borrowers:type_name -> library.v1.Borrower
+	10, // 9: library.v1.ListBorrowersResponse.pagination:type_name -> library.v1.PageInfo
+	2,  // 10: library.v1.PatronService.CreateBorrower:input_type -> library.v1.CreateBorrowerRequest
+	3,  // 11: library.v1.PatronService.GetBorrower:input_type -> library.v1.GetBorrowerRequest
+	4,  // 12: library.v1.PatronService.ListBorrowers:input_type -> library.v1.ListBorrowersRequest
+	6,  // 13: library.v1.PatronService.UpdateBorrower:input_type -> library.v1.UpdateBorrowerRequest
+	7,  // 14: library.v1.PatronService.DeleteBorrower:input_type -> library.v1.DeleteBorrowerRequest
+	0,  // 15: library.v1.PatronService.CreateBorrower:output_type -> library.v1.Borrower
+	0,  // 16: library.v1.PatronService.GetBorrower:output_type -> library.v1.Borrower
+	5,  // 17: library.v1.PatronService.ListBorrowers:output_type -> library.v1.ListBorrowersResponse
+	0,  // 18: library.v1.PatronService.UpdateBorrower:output_type -> library.v1.Borrower
+	11, // 19: library.v1.PatronService.DeleteBorrower:output_type -> google.protobuf.Empty
+	15, // [15:20] is the sub-list for method output_type
+	10, // [10:15] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_library_v1_patrons_proto_init() }
+func file_library_v1_patrons_proto_init() {
+	if File_library_v1_patrons_proto != nil {
+		return
+	}
+	file_library_v1_common_proto_init()
+	file_library_v1_patrons_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_library_v1_patrons_proto_rawDesc), len(file_library_v1_patrons_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_library_v1_patrons_proto_goTypes,
+		DependencyIndexes: file_library_v1_patrons_proto_depIdxs,
+		MessageInfos:      file_library_v1_patrons_proto_msgTypes,
+	}.Build()
+	File_library_v1_patrons_proto = out.File
+	file_library_v1_patrons_proto_goTypes = nil
+	file_library_v1_patrons_proto_depIdxs = nil
+}
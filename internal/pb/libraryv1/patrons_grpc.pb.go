@@ -0,0 +1,280 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: library/v1/patrons.proto
+
+package libraryv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PatronService_CreateBorrower_FullMethodName = "/library.v1.PatronService/CreateBorrower"
+	PatronService_GetBorrower_FullMethodName    = "/library.v1.PatronService/GetBorrower"
+	PatronService_ListBorrowers_FullMethodName  = "/library.v1.PatronService/ListBorrowers"
+	PatronService_UpdateBorrower_FullMethodName = "/library.v1.PatronService/UpdateBorrower"
+	PatronService_DeleteBorrower_FullMethodName = "/library.v1.PatronService/DeleteBorrower"
+)
+
+// PatronServiceClient is the client API for PatronService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PatronService manages library members. It mirrors BorrowerService and
+// the /borrowers REST routes.
+type PatronServiceClient interface {
+	CreateBorrower(ctx context.Context, in *CreateBorrowerRequest, opts ...grpc.CallOption) (*Borrower, error)
+	GetBorrower(ctx context.Context, in *GetBorrowerRequest, opts ...grpc.CallOption) (*Borrower, error)
+	ListBorrowers(ctx context.Context, in *ListBorrowersRequest, opts ...grpc.CallOption) (*ListBorrowersResponse, error)
+	UpdateBorrower(ctx context.Context, in *UpdateBorrowerRequest, opts ...grpc.CallOption) (*Borrower, error)
+	DeleteBorrower(ctx context.Context, in *DeleteBorrowerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type patronServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPatronServiceClient(cc grpc.ClientConnInterface) PatronServiceClient {
+	return &patronServiceClient{cc}
+}
+
+func (c *patronServiceClient) CreateBorrower(ctx context.Context, in *CreateBorrowerRequest, opts ...grpc.CallOption) (*Borrower, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Borrower)
+	err := c.cc.Invoke(ctx, PatronService_CreateBorrower_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *patronServiceClient) GetBorrower(ctx context.Context, in *GetBorrowerRequest, opts ...grpc.CallOption) (*Borrower, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Borrower)
+	err := c.cc.Invoke(ctx, PatronService_GetBorrower_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *patronServiceClient) ListBorrowers(ctx context.Context, in *ListBorrowersRequest, opts ...grpc.CallOption) (*ListBorrowersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBorrowersResponse)
+	err := c.cc.Invoke(ctx, PatronService_ListBorrowers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *patronServiceClient) UpdateBorrower(ctx context.Context, in *UpdateBorrowerRequest, opts ...grpc.CallOption) (*Borrower, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Borrower)
+	err := c.cc.Invoke(ctx, PatronService_UpdateBorrower_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *patronServiceClient) DeleteBorrower(ctx context.Context, in *DeleteBorrowerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PatronService_DeleteBorrower_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PatronServiceServer is the server API for PatronService service.
+// All implementations must embed UnimplementedPatronServiceServer
+// for forward compatibility.
+//
+// PatronService manages library members. It mirrors BorrowerService and
+// the /borrowers REST routes.
+type PatronServiceServer interface {
+	CreateBorrower(context.Context, *CreateBorrowerRequest) (*Borrower, error)
+	GetBorrower(context.Context, *GetBorrowerRequest) (*Borrower, error)
+	ListBorrowers(context.Context, *ListBorrowersRequest) (*ListBorrowersResponse, error)
+	UpdateBorrower(context.Context, *UpdateBorrowerRequest) (*Borrower, error)
+	DeleteBorrower(context.Context, *DeleteBorrowerRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedPatronServiceServer()
+}
+
+// UnimplementedPatronServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPatronServiceServer struct{}
+
+func (UnimplementedPatronServiceServer) CreateBorrower(context.Context, *CreateBorrowerRequest) (*Borrower, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBorrower not implemented")
+}
+func (UnimplementedPatronServiceServer) GetBorrower(context.Context, *GetBorrowerRequest) (*Borrower, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBorrower not implemented")
+}
+func (UnimplementedPatronServiceServer) ListBorrowers(context.Context, *ListBorrowersRequest) (*ListBorrowersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBorrowers not implemented")
+}
+func (UnimplementedPatronServiceServer) UpdateBorrower(context.Context, *UpdateBorrowerRequest) (*Borrower, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBorrower not implemented")
+}
+func (UnimplementedPatronServiceServer) DeleteBorrower(context.Context, *DeleteBorrowerRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBorrower not implemented")
+}
+func (UnimplementedPatronServiceServer) mustEmbedUnimplementedPatronServiceServer() {}
+func (UnimplementedPatronServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafePatronServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PatronServiceServer will
+// result in compilation errors.
+type UnsafePatronServiceServer interface {
+	mustEmbedUnimplementedPatronServiceServer()
+}
+
+func RegisterPatronServiceServer(s grpc.ServiceRegistrar, srv PatronServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPatronServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PatronService_ServiceDesc, srv)
+}
+
+func _PatronService_CreateBorrower_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBorrowerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatronServiceServer).CreateBorrower(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PatronService_CreateBorrower_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatronServiceServer).CreateBorrower(ctx, req.(*CreateBorrowerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PatronService_GetBorrower_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBorrowerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatronServiceServer).GetBorrower(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PatronService_GetBorrower_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatronServiceServer).GetBorrower(ctx, req.(*GetBorrowerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PatronService_ListBorrowers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBorrowersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatronServiceServer).ListBorrowers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PatronService_ListBorrowers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatronServiceServer).ListBorrowers(ctx, req.(*ListBorrowersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PatronService_UpdateBorrower_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBorrowerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatronServiceServer).UpdateBorrower(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PatronService_UpdateBorrower_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatronServiceServer).UpdateBorrower(ctx, req.(*UpdateBorrowerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PatronService_DeleteBorrower_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBorrowerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatronServiceServer).DeleteBorrower(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PatronService_DeleteBorrower_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatronServiceServer).DeleteBorrower(ctx, req.(*DeleteBorrowerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PatronService_ServiceDesc is the grpc.ServiceDesc for PatronService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PatronService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "library.v1.PatronService",
+	HandlerType: (*PatronServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateBorrower",
+			Handler:    _PatronService_CreateBorrower_Handler,
+		},
+		{
+			MethodName: "GetBorrower",
+			Handler:    _PatronService_GetBorrower_Handler,
+		},
+		{
+			MethodName: "ListBorrowers",
+			Handler:    _PatronService_ListBorrowers_Handler,
+		},
+		{
+			MethodName: "UpdateBorrower",
+			Handler:    _PatronService_UpdateBorrower_Handler,
+		},
+		{
+			MethodName: "DeleteBorrower",
+			Handler:    _PatronService_DeleteBorrower_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "library/v1/patrons.proto",
+}
@@ -0,0 +1,195 @@
+// Package pdf writes simple PDF documents: pages of filled rectangles and
+// single lines of text in the standard Helvetica font, which every PDF
+// reader provides, so no fonts are embedded.
+//
+// Coordinates are in points (1/72 inch) from the bottom left corner of the
+// page, as in PDF itself.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Page sizes in points
+const (
+	LetterWidth  = 612.0
+	LetterHeight = 792.0
+	A4Width      = 595.28
+	A4Height     = 841.89
+)
+
+// MM and Inch convert lengths to points.
+const (
+	MM   = 72 / 25.4
+	Inch = 72.0
+)
+
+// Document is a PDF document being built page by page.
+type Document struct {
+	width, height float64
+	pages         []*Page
+}
+
+// New returns an empty document whose pages are width by height points.
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+// Page is a page of a document, drawn on in order.
+type Page struct {
+	content bytes.Buffer
+}
+
+// AddPage appends a blank page to the document.
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// num formats n compactly for a content stream.
+func num(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// Rect fills the rectangle whose bottom left corner is at x, y in black.
+func (p *Page) Rect(x, y, width, height float64) {
+	fmt.Fprintf(&p.content, "%s %s %s %s re f\n", num(x), num(y), num(width), num(height))
+}
+
+// Text writes s in Helvetica of size points with its baseline starting at
+// x, y. Characters outside the Windows-1252 character set are replaced by
+// question marks.
+func (p *Page) Text(x, y, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /F1 %s Tf %s %s Td (%s) Tj ET\n", num(size), num(x), num(y), escape(encode(s)))
+}
+
+// encode converts s to Windows-1252, the encoding of the standard fonts.
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x20:
+			out = append(out, ' ')
+		case r < 0x7f, r >= 0xa0 && r <= 0xff:
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsi[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+// winAnsi maps the characters Windows-1252 places in 0x80-0x9f.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b,
+	'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// escape escapes the delimiters of a PDF string literal.
+func escape(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		if c == '(' || c == ')' || c == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// TextWidth returns the width in points of s set in Helvetica of size
+// points.
+func TextWidth(s string, size float64) float64 {
+	units := 0
+	for _, c := range encode(s) {
+		units += charWidth(c)
+	}
+	return float64(units) * size / 1000
+}
+
+// charWidth returns the width of a Windows-1252 character in Helvetica, in
+// thousandths of the font size.
+func charWidth(c byte) int {
+	if c >= 0x20 && c < 0x7f {
+		return helveticaWidths[c-0x20]
+	}
+	if w, ok := punctuationWidths[c]; ok {
+		return w
+	}
+	// Accented letters are close enough to the average lowercase width
+	return 556
+}
+
+// helveticaWidths are the widths of the printable ASCII characters in
+// Helvetica, from its font metrics.
+var helveticaWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space-/
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0-?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @-O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P-_
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // `-o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p-~
+}
+
+// punctuationWidths are the widths in Helvetica of the punctuation
+// Windows-1252 places in 0x80-0x9f that differs most from the average.
+var punctuationWidths = map[byte]int{
+	0x82: 222, 0x84: 333, 0x85: 1000, 0x89: 1000, 0x8b: 333, 0x91: 222, 0x92: 222,
+	0x93: 333, 0x94: 333, 0x95: 350, 0x97: 1000, 0x98: 333, 0x99: 1000, 0x9b: 333,
+}
+
+// Bytes returns the document as a PDF file.
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	var offsets []int
+
+	// Objects are numbered from 1 in the order written: the catalog, the
+	// page tree, the font, then each page followed by its content
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>",
+		strings.Join(kids, " "), len(d.pages), num(d.width), num(d.height)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+
+	for i, p := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 5+2*i))
+
+		var content bytes.Buffer
+		zw := zlib.NewWriter(&content)
+		if _, err := zw.Write(p.content.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", content.Len(), content.Bytes()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes(), nil
+}
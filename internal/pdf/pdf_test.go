@@ -0,0 +1,74 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocument(t *testing.T) {
+	doc := New(LetterWidth, LetterHeight)
+	first := doc.AddPage()
+	first.Rect(10, 20, 1.5, 30)
+	first.Text(72, 700, 12, "Café (1998) \\ 50 €")
+	doc.AddPage().Text(72, 700, 12, "Page two")
+
+	data, err := doc.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+
+	// Every cross-reference entry points at its object
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(data[xref:], []byte("xref\n0 8\n")) {
+		t.Fatalf("startxref %d does not point at an xref table of 8 entries", xref)
+	}
+	entries := strings.Split(string(data[xref:]), "\n")[3:10]
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[:10])
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(data[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q", i+1, data[offset:offset+10])
+		}
+	}
+	if !bytes.Contains(data, []byte("/Count 2 /MediaBox [0 0 612 792]")) {
+		t.Error("expected two Letter pages")
+	}
+
+	// The first page's content stream draws the rectangle and text
+	start := bytes.Index(data, []byte("stream\n")) + len("stream\n")
+	zr, err := zlib.NewReader(bytes.NewReader(data[start:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "10 20 1.5 30 re f\nBT /F1 12 Tf 72 700 Td (Caf\xe9 \\(1998\\) \\\\ 50 \x80) Tj ET\n"
+	if string(content) != want {
+		t.Fatalf("content stream is %q, want %q", content, want)
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	// H 722, e 556, l 222, l 222, o 556 thousandths of the font size
+	if got := TextWidth("Hello", 10); math.Abs(got-22.78) > 1e-9 {
+		t.Fatalf("TextWidth = %v, want 22.78", got)
+	}
+	if TextWidth("é", 10) != TextWidth("e", 10) {
+		t.Fatal("expected accented letters to be measured")
+	}
+}
@@ -0,0 +1,357 @@
+// Package receipt renders loan receipts as plain text for thermal receipt
+// printers, as HTML for the browser and email, and as PDF.
+//
+// All three formats are laid out from the same list of lines, so they
+// always carry the same information in the same order.
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"unicode/utf8"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/pdf"
+)
+
+// Text widths in characters. 42 columns fit an 80 mm roll in the
+// printers' default font, 32 a 58 mm roll.
+const (
+	DefaultWidth = 42
+	MinWidth     = 24
+	MaxWidth     = 80
+)
+
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02 15:04"
+)
+
+var titles = map[string]string{
+	models.ReceiptCheckout: "Checkout receipt",
+	models.ReceiptRenewal:  "Renewal receipt",
+	models.ReceiptReturn:   "Return receipt",
+}
+
+// Title names the kind of receipt, such as "Return receipt".
+func Title(r *models.Receipt) string {
+	return titles[r.Kind]
+}
+
+type style int
+
+const (
+	heading style = iota // centered, larger where the format allows
+	centered
+	rule
+	paragraph // wrapped to the width
+	field     // a label with its value aligned right
+)
+
+type line struct {
+	style        style
+	label, value string
+}
+
+// Amount formats a fine in the currency's minor unit, such as 150 as
+// "1.50".
+func Amount(minor int64) string {
+	return fmt.Sprintf("%d.%02d", minor/100, minor%100)
+}
+
+// layout lists the lines of r.
+func layout(r *models.Receipt) []line {
+	library := r.Library
+	if library == "" {
+		library = "Library"
+	}
+	lines := []line{{style: heading, value: library}}
+	if r.Branch != "" {
+		lines = append(lines, line{style: centered, value: r.Branch})
+	}
+	lines = append(lines,
+		line{style: centered, value: Title(r)},
+		line{style: centered, value: r.Date.Local().Format(dateTimeLayout)},
+		line{style: rule},
+	)
+
+	if r.BorrowerName != "" {
+		lines = append(lines, line{style: field, label: "Borrower", value: r.BorrowerName})
+	}
+	if r.CardNumber != "" {
+		lines = append(lines, line{style: field, label: "Card", value: r.CardNumber})
+	}
+	if r.BorrowerName != "" || r.CardNumber != "" {
+		lines = append(lines, line{style: rule})
+	}
+
+	item := r.Item
+	lines = append(lines, itemLines(item)...)
+	switch r.Kind {
+	case models.ReceiptCheckout:
+		lines = append(lines, line{style: field, label: "Due", value: item.DueDate.Local().Format(dateLayout)})
+	case models.ReceiptRenewal:
+		lines = append(lines,
+			line{style: field, label: "Renewals", value: fmt.Sprint(item.RenewalCount)},
+			line{style: field, label: "Now due", value: item.DueDate.Local().Format(dateLayout)},
+		)
+	case models.ReceiptReturn:
+		lines = append(lines, line{style: field, label: "Was due", value: item.DueDate.Local().Format(dateLayout)})
+		if r.Fine > 0 {
+			lines = append(lines, line{style: field, label: "Overdue fine", value: Amount(r.Fine)})
+		}
+	}
+
+	if len(r.OnLoan) > 0 {
+		lines = append(lines, line{style: rule}, line{style: paragraph, value: fmt.Sprintf("Still on loan (%d)", len(r.OnLoan))})
+		for _, loan := range r.OnLoan {
+			due := loan.DueDate.Local().Format(dateLayout)
+			if loan.Overdue {
+				due += " OVERDUE"
+			}
+			lines = append(lines,
+				line{style: paragraph, value: loan.Title},
+				line{style: field, label: "Due", value: due},
+			)
+		}
+	}
+	if r.Balance > 0 {
+		lines = append(lines, line{style: rule}, line{style: field, label: "Fines owed", value: Amount(r.Balance)})
+	}
+
+	return append(lines,
+		line{style: rule},
+		line{style: centered, value: "Loan " + r.BorrowingID.String()},
+		line{style: centered, value: "Thank you"},
+	)
+}
+
+// itemLines lists the title and identifiers of item.
+func itemLines(item models.ReceiptItem) []line {
+	lines := []line{
+		{style: paragraph, value: item.Title},
+		{style: field, label: "Barcode", value: item.Barcode},
+	}
+	if item.CallNumber != "" {
+		lines = append(lines, line{style: field, label: "Call number", value: item.CallNumber})
+	}
+	return lines
+}
+
+// wrap breaks s into lines at spaces so each passes fits, splitting words
+// that do not fit on a line of their own.
+func wrap(s string, fits func(string) bool) []string {
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if fits(candidate) {
+			current = candidate
+			continue
+		}
+		if current != "" {
+			lines = append(lines, current)
+		}
+		current = ""
+		// Break the word itself if it is too long for a line, keeping as
+		// much of it as fits and at least one character
+		for !fits(word) {
+			n := 0
+			for i := range word {
+				if i > 0 {
+					if !fits(word[:i]) {
+						break
+					}
+					n = i
+				}
+			}
+			if n == 0 {
+				_, n = utf8.DecodeRuneInString(word)
+			}
+			lines = append(lines, word[:n])
+			word = word[n:]
+		}
+		current = word
+	}
+	if current != "" || len(lines) == 0 {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// Text renders r in width columns.
+func Text(r *models.Receipt, width int) []byte {
+	fits := func(s string) bool { return utf8.RuneCountInString(s) <= width }
+	pad := func(n int) string {
+		if n < 0 {
+			n = 0
+		}
+		return strings.Repeat(" ", n)
+	}
+
+	var buf bytes.Buffer
+	for _, l := range layout(r) {
+		switch l.style {
+		case heading, centered:
+			for _, s := range wrap(l.value, fits) {
+				buf.WriteString(pad((width-utf8.RuneCountInString(s))/2) + s + "\n")
+			}
+		case rule:
+			buf.WriteString(strings.Repeat("-", width) + "\n")
+		case paragraph:
+			for _, s := range wrap(l.value, fits) {
+				buf.WriteString(s + "\n")
+			}
+		case field:
+			label, value := utf8.RuneCountInString(l.label), utf8.RuneCountInString(l.value)
+			if label+1+value <= width {
+				buf.WriteString(l.label + pad(width-label-value) + l.value + "\n")
+				continue
+			}
+			// The value goes on the lines below its label
+			buf.WriteString(l.label + "\n")
+			for _, s := range wrap(l.value, fits) {
+				buf.WriteString(pad(width-utf8.RuneCountInString(s)) + s + "\n")
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+var htmlTemplate = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; }
+.receipt { max-width: 22em; margin: 1em auto; }
+.receipt p { margin: 0.2em 0; }
+.heading { font-size: 1.3em; font-weight: bold; }
+.heading, .centered { text-align: center; }
+.field { display: flex; justify-content: space-between; gap: 1em; }
+.field span:last-child { text-align: right; }
+hr { border: 0; border-top: 1px dashed #000; }
+@media print { .receipt { margin: 0; } }
+</style>
+</head>
+<body>
+<div class="receipt">
+{{- range .Lines}}
+{{if eq .Style "rule"}}<hr>{{else if eq .Style "field"}}<p class="field"><span>{{.Label}}</span><span>{{.Value}}</span></p>{{else}}<p class="{{.Style}}">{{.Value}}</p>{{end}}
+{{- end}}
+</div>
+</body>
+</html>
+`))
+
+var styleClasses = map[style]string{
+	heading:   "heading",
+	centered:  "centered",
+	rule:      "rule",
+	paragraph: "paragraph",
+	field:     "field",
+}
+
+// HTML renders r as a standalone page, styled to print on a receipt
+// printer or a sheet of paper.
+func HTML(r *models.Receipt) ([]byte, error) {
+	type htmlLine struct{ Style, Label, Value string }
+	data := struct {
+		Title string
+		Lines []htmlLine
+	}{Title: Title(r)}
+	for _, l := range layout(r) {
+		data.Lines = append(data.Lines, htmlLine{Style: styleClasses[l.style], Label: l.label, Value: l.value})
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PDF page geometry, in points: an 80 mm roll, as long as the receipt
+const (
+	pdfWidth    = 80 * pdf.MM
+	pdfMargin   = 5 * pdf.MM
+	headingSize = 12.0
+	textSize    = 8.0
+	leading     = 1.4
+)
+
+// PDF renders r on a single page the width of a receipt roll.
+func PDF(r *models.Receipt) ([]byte, error) {
+	type cell struct {
+		x    float64
+		text string
+	}
+	type row struct {
+		size  float64
+		rule  bool
+		cells []cell
+	}
+	inner := pdfWidth - 2*pdfMargin
+	fitsAt := func(size float64) func(string) bool {
+		return func(s string) bool { return pdf.TextWidth(s, size) <= inner }
+	}
+
+	// Lay the receipt out top down first, as the page height depends on
+	// how many rows it takes
+	var rows []row
+	for _, l := range layout(r) {
+		size := textSize
+		if l.style == heading {
+			size = headingSize
+		}
+		right := func(s string) float64 { return pdfWidth - pdfMargin - pdf.TextWidth(s, size) }
+
+		switch l.style {
+		case heading, centered:
+			for _, s := range wrap(l.value, fitsAt(size)) {
+				rows = append(rows, row{size: size, cells: []cell{{(pdfWidth - pdf.TextWidth(s, size)) / 2, s}}})
+			}
+		case rule:
+			rows = append(rows, row{size: size, rule: true})
+		case paragraph:
+			for _, s := range wrap(l.value, fitsAt(size)) {
+				rows = append(rows, row{size: size, cells: []cell{{pdfMargin, s}}})
+			}
+		case field:
+			if pdf.TextWidth(l.label+"  "+l.value, size) <= inner {
+				rows = append(rows, row{size: size, cells: []cell{{pdfMargin, l.label}, {right(l.value), l.value}}})
+				continue
+			}
+			// The value goes on the rows below its label
+			rows = append(rows, row{size: size, cells: []cell{{pdfMargin, l.label}}})
+			for _, s := range wrap(l.value, fitsAt(size)) {
+				rows = append(rows, row{size: size, cells: []cell{{right(s), s}}})
+			}
+		}
+	}
+
+	height := 2 * pdfMargin
+	for _, row := range rows {
+		height += row.size * leading
+	}
+
+	doc := pdf.New(pdfWidth, height)
+	page := doc.AddPage()
+	y := height - pdfMargin
+	for _, row := range rows {
+		y -= row.size * leading
+		if row.rule {
+			page.Rect(pdfMargin, y+row.size*0.4, inner, 0.5)
+		}
+		for _, c := range row.cells {
+			page.Text(c.x, y, row.size, c.text)
+		}
+	}
+	return doc.Bytes()
+}
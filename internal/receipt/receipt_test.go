@@ -0,0 +1,103 @@
+package receipt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func sampleReceipt() *models.Receipt {
+	returned := time.Date(2026, 10, 20, 15, 30, 0, 0, time.Local)
+	return &models.Receipt{
+		Kind:         models.ReceiptReturn,
+		BorrowingID:  uuid.MustParse("8f14e45f-ceea-467f-a0e6-9d2b6a1d8c3e"),
+		Library:      "Springfield Public Library",
+		Branch:       "Main Street",
+		Date:         returned,
+		BorrowerName: "Ada Lovelace",
+		CardNumber:   "****1234",
+		Item: models.ReceiptItem{
+			Title:      "The Hitchhiker's Guide to the Galaxy <Illustrated Edition>",
+			Barcode:    "30000000000018",
+			CallNumber: "823.914 ADA",
+			DueDate:    time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local),
+			ReturnedAt: &returned,
+		},
+		Fine:    150,
+		OnLoan:  []models.ReceiptItem{{Title: "Dune", DueDate: time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local), Overdue: true}},
+		Balance: 1225,
+	}
+}
+
+func TestText(t *testing.T) {
+	text := string(Text(sampleReceipt(), 32))
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		if n := utf8.RuneCountInString(line); n > 32 {
+			t.Errorf("line %q is %d columns wide", line, n)
+		}
+	}
+
+	for _, want := range []string{
+		"   Springfield Public Library\n",
+		"        Return receipt\n",
+		"Card                    ****1234\n",
+		"The Hitchhiker's Guide to the\nGalaxy <Illustrated Edition>\n",
+		"Was due               2026-10-14\n",
+		"Overdue fine                1.50\n",
+		"Due           2026-10-18 OVERDUE\n",
+		"Fines owed                 12.25\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("receipt lacks %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	fits := func(s string) bool { return len(s) <= 5 }
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", []string{""}},
+		{"a b c", []string{"a b c"}},
+		{"ab cd ef", []string{"ab cd", "ef"}},
+		{"abcdefghijkl m", []string{"abcde", "fghij", "kl m"}},
+	}
+	for _, tt := range tests {
+		got := wrap(tt.in, fits)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("wrap(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHTML(t *testing.T) {
+	data, err := HTML(sampleReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	if !strings.Contains(html, "Galaxy &lt;Illustrated Edition&gt;") {
+		t.Fatal("expected the title to be escaped")
+	}
+	if !strings.Contains(html, `<p class="field"><span>Overdue fine</span><span>1.50</span></p>`) {
+		t.Fatalf("fine missing from\n%s", html)
+	}
+}
+
+func TestPDF(t *testing.T) {
+	data, err := PDF(sampleReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) || !bytes.Contains(data, []byte("/Count 1 ")) {
+		t.Fatal("expected a one page PDF")
+	}
+}
@@ -0,0 +1,88 @@
+package gormstore
+
+import (
+	"encoding/json"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/reqctx"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func (r *auditLogRepository) ListForEntities(entityType string, ids []uuid.UUID) ([]models.AuditLog, error) {
+	var entries []models.AuditLog
+	if len(ids) == 0 {
+		return entries, nil
+	}
+	err := r.db.Where("entity_type = ? AND entity_id IN ?", entityType, entityIDs(ids)).
+		Order("created_at ASC").Find(&entries).Error
+	return entries, err
+}
+
+func (r *auditLogRepository) ListByActor(actor string) ([]models.AuditLog, error) {
+	var entries []models.AuditLog
+	err := r.db.Where("actor = ?", actor).Order("created_at ASC").Find(&entries).Error
+	return entries, err
+}
+
+func (r *auditLogRepository) ScrubFields(entityType string, ids []uuid.UUID, fields ...string) error {
+	entries, err := r.ListForEntities(entityType, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		var changes map[string]json.RawMessage
+		if err := json.Unmarshal(entry.Changes, &changes); err != nil {
+			return err
+		}
+		scrubbed := false
+		for _, field := range fields {
+			if _, ok := changes[field]; ok {
+				delete(changes, field)
+				scrubbed = true
+			}
+		}
+		if !scrubbed {
+			continue
+		}
+
+		body, err := json.Marshal(changes)
+		if err != nil {
+			return err
+		}
+		if err := r.db.Model(&entry).Update("changes", json.RawMessage(body)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scrubBorrower removes every trace of the borrower from the given records'
+// audit entries: their ID from the recorded changes, and their identity from
+// entries they made through the self-service API.
+func (r *auditLogRepository) scrubBorrower(entityType string, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.ScrubFields(entityType, ids, "borrower_id"); err != nil {
+		return err
+	}
+	return r.db.Model(&models.AuditLog{}).
+		Where("entity_type = ? AND entity_id IN ? AND actor LIKE ?", entityType, entityIDs(ids), reqctx.BorrowerActorPrefix+"%").
+		Update("actor", reqctx.AnonymousBorrowerActor).Error
+}
+
+// entityIDs formats ids the way AuditLog.EntityID stores them.
+func entityIDs(ids []uuid.UUID) []string {
+	formatted := make([]string, len(ids))
+	for i, id := range ids {
+		formatted[i] = id.String()
+	}
+	return formatted
+}
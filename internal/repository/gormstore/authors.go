@@ -0,0 +1,70 @@
+package gormstore
+
+import (
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type authorRepository struct {
+	*trash[models.Author]
+	db      *gorm.DB
+	dialect dialect
+}
+
+func newAuthorRepository(db *gorm.DB, d dialect) *authorRepository {
+	return &authorRepository{
+		trash: &trash[models.Author]{
+			db:           db,
+			unreferenced: "NOT EXISTS (SELECT 1 FROM book_contributors WHERE book_contributors.author_id = authors.id)",
+		},
+		db:      db,
+		dialect: d,
+	}
+}
+
+func (r *authorRepository) Create(author *models.Author) error {
+	return r.db.Create(author).Error
+}
+
+func (r *authorRepository) Get(id uuid.UUID) (*models.Author, error) {
+	var author models.Author
+	if err := r.db.First(&author, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &author, nil
+}
+
+func (r *authorRepository) List(offset, limit int) ([]models.Author, int64, error) {
+	return paginate[models.Author](r.db.Session(&gorm.Session{}), offset, limit, "name, id")
+}
+
+func (r *authorRepository) search(query string) *gorm.DB {
+	searchQuery := "%" + query + "%"
+	return r.db.Where("name "+r.dialect.like+" ? OR biography "+r.dialect.like+" ?", searchQuery, searchQuery)
+}
+
+func (r *authorRepository) Search(query string, offset, limit int) ([]models.Author, int64, error) {
+	return paginate[models.Author](r.search(query).Session(&gorm.Session{}), offset, limit, "")
+}
+
+func (r *authorRepository) Update(author *models.Author) error {
+	return save(r.db, author)
+}
+
+func (r *authorRepository) Delete(author *models.Author) error {
+	return r.db.Delete(author).Error
+}
+
+func (r *authorRepository) Export(query string, batchSize int, fn func([]models.Author) error) error {
+	db := r.db
+	if query != "" {
+		db = r.search(query)
+	}
+
+	var authors []models.Author
+	return db.FindInBatches(&authors, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(authors)
+	}).Error
+}
@@ -0,0 +1,341 @@
+package gormstore
+
+import (
+	"strings"
+
+	"library-management-go/internal/callnumber"
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type bookRepository struct {
+	*trash[models.Book]
+	db      *gorm.DB
+	dialect dialect
+}
+
+// unreferencedBook holds for books no loan, hold or transfer points at.
+const unreferencedBook = "NOT EXISTS (SELECT 1 FROM borrowings WHERE borrowings.book_id = books.id)" +
+	" AND NOT EXISTS (SELECT 1 FROM holds WHERE holds.book_id = books.id)" +
+	" AND NOT EXISTS (SELECT 1 FROM transfers WHERE transfers.book_id = books.id)"
+
+func newBookRepository(db *gorm.DB, d dialect) *bookRepository {
+	return &bookRepository{
+		trash: &trash[models.Book]{
+			db:           db,
+			unreferenced: unreferencedBook,
+		},
+		db:      db,
+		dialect: d,
+	}
+}
+
+// preloadContributors loads the credits of the books at path ("" for the
+// books queried, "Book." for a loan's book) in order, with their authors.
+func preloadContributors(db *gorm.DB, path string) *gorm.DB {
+	return db.Preload(path+"Contributors", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).Preload(path + "Contributors.Author")
+}
+
+// Create inserts book together with its contributors.
+func (r *bookRepository) Create(book *models.Book) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(book).Error; err != nil {
+			return err
+		}
+		return createContributors(tx, book.ID, book.Contributors)
+	})
+}
+
+func createContributors(db *gorm.DB, bookID uuid.UUID, contributors []models.BookContributor) error {
+	if len(contributors) == 0 {
+		return nil
+	}
+	for i := range contributors {
+		contributors[i].BookID = bookID
+	}
+	return db.Omit(clause.Associations).Create(&contributors).Error
+}
+
+// preloadSubjects loads the books' subjects in name order.
+func preloadSubjects(db *gorm.DB) *gorm.DB {
+	return db.Preload("Subjects", func(db *gorm.DB) *gorm.DB { return db.Order("name") })
+}
+
+// preloadEdition loads the books' publisher, series and work.
+func preloadEdition(db *gorm.DB) *gorm.DB {
+	return db.Preload("Publisher").Preload("Series").Preload("Work")
+}
+
+func (r *bookRepository) SetSubjects(bookID uuid.UUID, subjectIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("book_id = ?", bookID).Delete(&models.BookSubject{}).Error; err != nil {
+			return err
+		}
+		if len(subjectIDs) == 0 {
+			return nil
+		}
+		tags := make([]models.BookSubject, len(subjectIDs))
+		for i, subjectID := range subjectIDs {
+			tags[i] = models.BookSubject{BookID: bookID, SubjectID: subjectID}
+		}
+		return tx.Create(&tags).Error
+	})
+}
+
+func (r *bookRepository) AddSubject(bookID, subjectID uuid.UUID) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.BookSubject{BookID: bookID, SubjectID: subjectID}).Error
+}
+
+func (r *bookRepository) SetContributors(bookID uuid.UUID, contributors []models.BookContributor) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("book_id = ?", bookID).Delete(&models.BookContributor{}).Error; err != nil {
+			return err
+		}
+		return createContributors(tx, bookID, contributors)
+	})
+}
+
+func (r *bookRepository) Get(id uuid.UUID) (*models.Book, error) {
+	var book models.Book
+	err := preloadEdition(preloadSubjects(preloadContributors(r.db, ""))).Preload("HomeLocation.Branch").Preload("CurrentLocation.Branch").
+		First(&book, "id = ?", id).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &book, nil
+}
+
+func (r *bookRepository) FindByISBN(isbn string, excludeID uuid.UUID) (*models.Book, error) {
+	var book models.Book
+	if err := r.db.Where("isbn = ? AND id != ?", isbn, excludeID).First(&book).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &book, nil
+}
+
+func (r *bookRepository) FindByBarcode(barcode string, excludeID uuid.UUID) (*models.Book, error) {
+	var book models.Book
+	if err := r.db.Where("barcode = ? AND id != ?", barcode, excludeID).First(&book).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &book, nil
+}
+
+// shelfOrder lists books by call number, one scheme after another, with
+// unclassified books last.
+const shelfOrder = "books.call_number_sort = '', books.classification_scheme, books.call_number_sort, books.title, books.id"
+
+// find applies filter to the book query.
+func (r *bookRepository) find(filter models.BookFilter) *gorm.DB {
+	db := r.db
+	if filter.Search != "" {
+		searchQuery := "%" + filter.Search + "%"
+		// Any contributor's name matches, whatever their role, and a
+		// scanned barcode finds its item
+		db = db.Where("books.title "+r.dialect.like+" ? OR books.isbn "+r.dialect.like+" ? OR books.barcode = ? OR books.id IN "+
+			"(SELECT book_contributors.book_id FROM book_contributors JOIN authors ON authors.id = book_contributors.author_id "+
+			"WHERE authors.name "+r.dialect.like+" ?)",
+			searchQuery, searchQuery, filter.Search, searchQuery)
+	}
+	if filter.AuthorID != uuid.Nil {
+		db = db.Where("books.id IN (SELECT book_id FROM book_contributors WHERE author_id = ?)", filter.AuthorID)
+	}
+	if filter.BranchID != uuid.Nil {
+		db = db.Where("books.current_location_id IN (SELECT id FROM locations WHERE branch_id = ?)", filter.BranchID)
+	}
+	if filter.HomeBranchID != uuid.Nil {
+		db = db.Where("books.home_location_id IN (SELECT id FROM locations WHERE branch_id = ?)", filter.HomeBranchID)
+	}
+	if filter.SubjectID != uuid.Nil {
+		db = db.Where("books.id IN (SELECT book_id FROM book_subjects WHERE subject_id IN ("+subjectTree+"))", filter.SubjectID)
+	}
+	if filter.PublisherID != uuid.Nil {
+		db = db.Where("books.publisher_id = ?", filter.PublisherID)
+	}
+	if filter.SeriesID != uuid.Nil {
+		db = db.Where("books.series_id = ?", filter.SeriesID)
+	}
+	if filter.WorkID != uuid.Nil {
+		db = db.Where("books.work_id = ?", filter.WorkID)
+	}
+	if filter.Format != "" {
+		db = db.Where("books.format = ?", filter.Format)
+	}
+	if filter.Language != "" {
+		db = db.Where("LOWER(books.language) = LOWER(?)", filter.Language)
+	}
+	if filter.CallNumberFrom != "" || filter.CallNumberTo != "" {
+		// Keys only compare within a scheme
+		scheme, start, end, err := callnumber.Range(filter.Classification, filter.CallNumberFrom, filter.CallNumberTo)
+		if err != nil {
+			db.AddError(err)
+			return db
+		}
+		db = db.Where("books.classification_scheme = ?", scheme)
+		if start != "" {
+			db = db.Where("books.call_number_sort >= ?", start)
+		}
+		if end != "" {
+			db = db.Where("books.call_number_sort < ?", end)
+		}
+	} else if filter.Classification != "" {
+		db = db.Where("books.classification_scheme = ?", filter.Classification)
+	}
+	if filter.Query != nil {
+		condition, args := r.query(filter.Query)
+		db = db.Where(condition, args...)
+	}
+	return db
+}
+
+// likeEscaper escapes the pattern characters of LIKE, so that only the
+// wildcards a query asks for match more than themselves.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// query compiles q into a condition on books and its arguments.
+func (r *bookRepository) query(q *models.BookQuery) (string, []interface{}) {
+	switch q.Op {
+	case models.QueryAnd, models.QueryOr, models.QueryNot:
+		left, args := r.query(q.Left)
+		right, rightArgs := r.query(q.Right)
+		op := " AND "
+		if q.Op == models.QueryOr {
+			op = " OR "
+		} else if q.Op == models.QueryNot {
+			op = " AND NOT "
+		}
+		return "(" + left + op + right + ")", append(args, rightArgs...)
+	}
+
+	term := q.Term
+	if q.Field == models.SearchISBN {
+		term = strings.NewReplacer("-", "", " ", "").Replace(term)
+	}
+	pattern := strings.ReplaceAll(likeEscaper.Replace(term), "*", "%")
+	if !q.Exact {
+		pattern = "%" + pattern + "%"
+	}
+
+	like := " " + r.dialect.like + ` ? ESCAPE '\'`
+	title := "books.title" + like
+	// ISBNs match with or without their hyphens
+	isbn := "REPLACE(REPLACE(books.isbn, '-', ''), ' ', '')" + like
+	author := "books.id IN (SELECT book_contributors.book_id FROM book_contributors JOIN authors ON authors.id = book_contributors.author_id " +
+		"WHERE authors.name" + like + ")"
+	subject := "books.id IN (SELECT book_subjects.book_id FROM book_subjects JOIN subjects ON subjects.id = book_subjects.subject_id " +
+		"WHERE subjects.deleted_at IS NULL AND subjects.name" + like + ")"
+	switch q.Field {
+	case models.SearchTitle:
+		return title, []interface{}{pattern}
+	case models.SearchISBN:
+		return isbn, []interface{}{pattern}
+	case models.SearchAuthor:
+		return author, []interface{}{pattern}
+	case models.SearchSubject:
+		return subject, []interface{}{pattern}
+	}
+	return "(" + title + " OR " + isbn + " OR " + author + " OR " + subject + ")", []interface{}{pattern, pattern, pattern, pattern}
+}
+
+func (r *bookRepository) Find(filter models.BookFilter, offset, limit int) ([]models.Book, int64, error) {
+	// A series is listed in volume order
+	order := ""
+	if filter.SeriesID != uuid.Nil {
+		order = "books.series_volume, books.title"
+	}
+	if filter.ShelfOrder {
+		order = shelfOrder
+	}
+	if filter.NewestFirst {
+		order = "books.created_at DESC, books.id"
+	}
+	query := preloadEdition(preloadSubjects(preloadContributors(r.find(filter), "")))
+	return paginate[models.Book](query.Session(&gorm.Session{}), offset, limit, order)
+}
+
+func (r *bookRepository) Update(book *models.Book) error {
+	return save(r.db, book)
+}
+
+func (r *bookRepository) CheckOut(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&models.Book{}).Where("id = ? AND available = ?", id, true).
+		Updates(map[string]interface{}{"available": false, "current_location_id": nil})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *bookRepository) Delete(book *models.Book) error {
+	return r.db.Delete(book).Error
+}
+
+func (r *bookRepository) CountByAuthor(authorID uuid.UUID, includeDeleted bool) (int64, error) {
+	db := r.db
+	if includeDeleted {
+		db = db.Unscoped()
+	}
+
+	var count int64
+	err := db.Model(&models.Book{}).
+		Where("id IN (SELECT book_id FROM book_contributors WHERE author_id = ?)", authorID).
+		Count(&count).Error
+	return count, err
+}
+
+// deleted preloads the credits of deleted books in order, including
+// deleted authors, their subjects and their edition metadata.
+func (r *bookRepository) deleted() *gorm.DB {
+	return preloadEdition(r.trash.deleted()).
+		Preload("Contributors", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Contributors.Author", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Subjects", func(db *gorm.DB) *gorm.DB { return db.Order("name") })
+}
+
+func (r *bookRepository) ListDeleted(offset, limit int) ([]models.Book, int64, error) {
+	return paginate[models.Book](r.deleted().Session(&gorm.Session{}), offset, limit, "deleted_at DESC")
+}
+
+func (r *bookRepository) GetDeleted(id uuid.UUID) (*models.Book, error) {
+	var book models.Book
+	if err := r.deleted().First(&book, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &book, nil
+}
+
+func (r *bookRepository) CountByLocation(locationID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Book{}).
+		Where("home_location_id = ? OR current_location_id = ?", locationID, locationID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *bookRepository) Export(filter models.BookFilter, batchSize int, fn func([]models.Book) error) error {
+	var books []models.Book
+	query := preloadEdition(preloadSubjects(preloadContributors(r.find(filter), ""))).Session(&gorm.Session{})
+	if filter.ShelfOrder {
+		// FindInBatches pages by primary key, so shelf order is paged by offset
+		for offset := 0; ; offset += batchSize {
+			var page []models.Book
+			if err := query.Order(shelfOrder).Offset(offset).Limit(batchSize).Find(&page).Error; err != nil {
+				return err
+			}
+			if len(page) > 0 {
+				if err := fn(page); err != nil {
+					return err
+				}
+			}
+			if len(page) < batchSize {
+				return nil
+			}
+		}
+	}
+	return query.FindInBatches(&books, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(books)
+	}).Error
+}
@@ -0,0 +1,43 @@
+package gormstore
+
+import (
+	"time"
+
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type borrowerBlockRepository struct {
+	db *gorm.DB
+}
+
+func (r *borrowerBlockRepository) Create(block *models.BorrowerBlock) error {
+	return r.db.Create(block).Error
+}
+
+func (r *borrowerBlockRepository) Get(borrowerID, id uuid.UUID) (*models.BorrowerBlock, error) {
+	var block models.BorrowerBlock
+	if err := r.db.First(&block, "id = ? AND borrower_id = ?", id, borrowerID).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &block, nil
+}
+
+func (r *borrowerBlockRepository) ListByBorrower(borrowerID uuid.UUID) ([]models.BorrowerBlock, error) {
+	var blocks []models.BorrowerBlock
+	err := r.db.Where("borrower_id = ?", borrowerID).Order("created_at DESC").Find(&blocks).Error
+	return blocks, err
+}
+
+func (r *borrowerBlockRepository) ListActive(borrowerID uuid.UUID, now time.Time) ([]models.BorrowerBlock, error) {
+	var blocks []models.BorrowerBlock
+	err := r.db.Where("borrower_id = ? AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", borrowerID, now).
+		Order("created_at DESC").Find(&blocks).Error
+	return blocks, err
+}
+
+func (r *borrowerBlockRepository) Update(block *models.BorrowerBlock) error {
+	return save(r.db, block)
+}
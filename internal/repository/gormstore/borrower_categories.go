@@ -0,0 +1,44 @@
+package gormstore
+
+import (
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type borrowerCategoryRepository struct {
+	db *gorm.DB
+}
+
+func (r *borrowerCategoryRepository) Create(category *models.BorrowerCategory) error {
+	return r.db.Create(category).Error
+}
+
+func (r *borrowerCategoryRepository) Get(id uuid.UUID) (*models.BorrowerCategory, error) {
+	var category models.BorrowerCategory
+	if err := r.db.First(&category, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &category, nil
+}
+
+func (r *borrowerCategoryRepository) FindByCode(code string, excludeID uuid.UUID) (*models.BorrowerCategory, error) {
+	var category models.BorrowerCategory
+	if err := r.db.Where("code = ? AND id != ?", code, excludeID).First(&category).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &category, nil
+}
+
+func (r *borrowerCategoryRepository) List(offset, limit int) ([]models.BorrowerCategory, int64, error) {
+	return paginate[models.BorrowerCategory](r.db.Session(&gorm.Session{}), offset, limit, "code ASC")
+}
+
+func (r *borrowerCategoryRepository) Update(category *models.BorrowerCategory) error {
+	return save(r.db, category)
+}
+
+func (r *borrowerCategoryRepository) Delete(category *models.BorrowerCategory) error {
+	return r.db.Delete(category).Error
+}
@@ -0,0 +1,137 @@
+package gormstore
+
+import (
+	"time"
+
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type borrowerRepository struct {
+	*trash[models.Borrower]
+	db      *gorm.DB
+	dialect dialect
+}
+
+// unreferencedBorrower holds for borrowers no loan, hold, fine or block
+// points at.
+const unreferencedBorrower = "NOT EXISTS (SELECT 1 FROM borrowings WHERE borrowings.borrower_id = borrowers.id)" +
+	" AND NOT EXISTS (SELECT 1 FROM holds WHERE holds.borrower_id = borrowers.id)" +
+	" AND NOT EXISTS (SELECT 1 FROM fines WHERE fines.borrower_id = borrowers.id)" +
+	" AND NOT EXISTS (SELECT 1 FROM borrower_blocks WHERE borrower_blocks.borrower_id = borrowers.id)"
+
+func newBorrowerRepository(db *gorm.DB, d dialect) *borrowerRepository {
+	return &borrowerRepository{
+		trash: &trash[models.Borrower]{
+			db:           db,
+			unreferenced: unreferencedBorrower,
+		},
+		db:      db,
+		dialect: d,
+	}
+}
+
+func (r *borrowerRepository) Create(borrower *models.Borrower) error {
+	return r.db.Create(borrower).Error
+}
+
+func (r *borrowerRepository) Get(id uuid.UUID) (*models.Borrower, error) {
+	var borrower models.Borrower
+	if err := r.db.Preload("Category").First(&borrower, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &borrower, nil
+}
+
+func (r *borrowerRepository) GetIncludingDeleted(id uuid.UUID) (*models.Borrower, error) {
+	var borrower models.Borrower
+	if err := r.db.Unscoped().Preload("Category").First(&borrower, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &borrower, nil
+}
+
+func (r *borrowerRepository) FindByEmail(email string, excludeID uuid.UUID) (*models.Borrower, error) {
+	var borrower models.Borrower
+	if err := r.db.Where("email = ? AND id != ?", email, excludeID).First(&borrower).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &borrower, nil
+}
+
+func (r *borrowerRepository) FindByCardNumber(cardNumber string) (*models.Borrower, error) {
+	var borrower models.Borrower
+	if err := r.db.Preload("Category").Where("card_number = ?", cardNumber).First(&borrower).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &borrower, nil
+}
+
+func (r *borrowerRepository) CountByCategory(categoryID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Borrower{}).Where("category_id = ?", categoryID).Count(&count).Error
+	return count, err
+}
+
+func (r *borrowerRepository) MarkExpired(now time.Time) error {
+	return r.db.Model(&models.Borrower{}).
+		Where("status = ? AND membership_expires_at < ?", models.BorrowerActive, now).
+		Update("status", models.BorrowerExpired).Error
+}
+
+// RecordPINFailure increments the count in the database rather than saving
+// a loaded borrower, so concurrent attempts are all counted.
+func (r *borrowerRepository) RecordPINFailure(id uuid.UUID, maxAttempts int, lockedUntil time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Borrower{ID: id}).
+			UpdateColumn("failed_pin_attempts", gorm.Expr("failed_pin_attempts + 1")).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.Borrower{ID: id}).
+			Where("failed_pin_attempts >= ?", maxAttempts).
+			UpdateColumns(map[string]interface{}{"failed_pin_attempts": 0, "pin_locked_until": lockedUntil}).Error
+	})
+}
+
+func (r *borrowerRepository) ResetPINFailures(id uuid.UUID) error {
+	return r.db.Model(&models.Borrower{ID: id}).
+		Where("failed_pin_attempts <> 0").
+		UpdateColumn("failed_pin_attempts", 0).Error
+}
+
+func (r *borrowerRepository) List(offset, limit int) ([]models.Borrower, int64, error) {
+	return paginate[models.Borrower](r.db.Session(&gorm.Session{}), offset, limit, "")
+}
+
+func (r *borrowerRepository) search(query string) *gorm.DB {
+	searchQuery := "%" + query + "%"
+	return r.db.Where("name "+r.dialect.like+" ? OR email "+r.dialect.like+" ? OR phone "+r.dialect.like+" ? OR card_number = ?",
+		searchQuery, searchQuery, searchQuery, query)
+}
+
+func (r *borrowerRepository) Search(query string, offset, limit int) ([]models.Borrower, int64, error) {
+	return paginate[models.Borrower](r.search(query).Session(&gorm.Session{}), offset, limit, "")
+}
+
+func (r *borrowerRepository) Update(borrower *models.Borrower) error {
+	return save(r.db, borrower)
+}
+
+func (r *borrowerRepository) Delete(borrower *models.Borrower) error {
+	return r.db.Delete(borrower).Error
+}
+
+func (r *borrowerRepository) Export(query string, batchSize int, fn func([]models.Borrower) error) error {
+	db := r.db
+	if query != "" {
+		db = r.search(query)
+	}
+
+	var borrowers []models.Borrower
+	return db.FindInBatches(&borrowers, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(borrowers)
+	}).Error
+}
@@ -0,0 +1,173 @@
+package gormstore
+
+import (
+	"time"
+
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type borrowingRepository struct {
+	db *gorm.DB
+}
+
+func (r *borrowingRepository) withRelations() *gorm.DB {
+	return preloadContributors(r.db, "Book.").Preload("Borrower")
+}
+
+func (r *borrowingRepository) Create(borrowing *models.Borrowing) error {
+	return r.db.Create(borrowing).Error
+}
+
+func (r *borrowingRepository) Get(id uuid.UUID) (*models.Borrowing, error) {
+	var borrowing models.Borrowing
+	if err := r.withRelations().First(&borrowing, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &borrowing, nil
+}
+
+func (r *borrowingRepository) Update(borrowing *models.Borrowing) error {
+	return save(r.db, borrowing)
+}
+
+// find applies filter to the borrowing query.
+func (r *borrowingRepository) find(filter models.BorrowingFilter) *gorm.DB {
+	db := r.withRelations()
+	if filter.BorrowerID != uuid.Nil {
+		db = db.Where("borrower_id = ?", filter.BorrowerID)
+	}
+	if filter.BookID != uuid.Nil {
+		db = db.Where("book_id = ?", filter.BookID)
+	}
+	if filter.BranchID != uuid.Nil {
+		db = db.Where("branch_id = ?", filter.BranchID)
+	}
+	if filter.OverdueOnly {
+		db = db.Where("status = 'borrowed' AND due_date < ?", time.Now())
+	}
+	if filter.ActiveOnly {
+		db = db.Where("returned_at IS NULL")
+	}
+	return db
+}
+
+func (r *borrowingRepository) Find(filter models.BorrowingFilter, offset, limit int) ([]models.Borrowing, int64, error) {
+	order := "created_at DESC"
+	if filter.OverdueOnly {
+		order = "due_date ASC"
+	}
+	return paginate[models.Borrowing](r.find(filter).Session(&gorm.Session{}), offset, limit, order)
+}
+
+func (r *borrowingRepository) count(query string, args ...interface{}) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Borrowing{}).Where(query, args...).Count(&count).Error
+	return count, err
+}
+
+func (r *borrowingRepository) CountActiveByBorrower(borrowerID uuid.UUID) (int64, error) {
+	return r.count("borrower_id = ? AND status = 'borrowed'", borrowerID)
+}
+
+func (r *borrowingRepository) CountOverdueByBorrower(borrowerID uuid.UUID, now time.Time) (int64, error) {
+	return r.count("borrower_id = ? AND status = 'borrowed' AND due_date < ?", borrowerID, now)
+}
+
+func (r *borrowingRepository) HasActiveForBook(bookID uuid.UUID) (bool, error) {
+	count, err := r.count("book_id = ? AND status = 'borrowed'", bookID)
+	return count > 0, err
+}
+
+func (r *borrowingRepository) CountByBook(bookID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Borrowing{}).Where("book_id = ?", bookID).Count(&count).Error
+	return count, err
+}
+
+func (r *borrowingRepository) CountByBorrower(borrowerID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Borrowing{}).Where("borrower_id = ?", borrowerID).Count(&count).Error
+	return count, err
+}
+
+func (r *borrowingRepository) MarkOverdue(now time.Time) error {
+	return r.db.Model(&models.Borrowing{}).
+		Where("status = 'borrowed' AND due_date < ?", now).
+		Update("status", "overdue").Error
+}
+
+func (r *borrowingRepository) Export(filter models.BorrowingFilter, batchSize int, fn func([]models.Borrowing) error) error {
+	var borrowings []models.Borrowing
+	return r.find(filter).FindInBatches(&borrowings, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(borrowings)
+	}).Error
+}
+
+// anonymizeBatchSize bounds the IN lists of anonymize.
+const anonymizeBatchSize = 500
+
+func (r *borrowingRepository) AnonymizeReturnedBefore(cutoff, now time.Time) (int64, error) {
+	db := r.db.Unscoped().Session(&gorm.Session{})
+	keepers := db.Model(&models.Borrower{}).Select("id").Where("keep_history = ?", true)
+	unpaid := db.Model(&models.Fine{}).Select("borrowing_id").
+		Where("status = ? AND borrowing_id IS NOT NULL", models.FineOutstanding)
+
+	return r.anonymize(now, func(query *gorm.DB) *gorm.DB {
+		return query.Where("returned_at < ?", cutoff).
+			Where("borrower_id NOT IN (?) AND id NOT IN (?)", keepers, unpaid)
+	})
+}
+
+func (r *borrowingRepository) AnonymizeByBorrower(borrowerID uuid.UUID, now time.Time) (int64, error) {
+	return r.anonymize(now, func(query *gorm.DB) *gorm.DB {
+		return query.Where("returned_at IS NOT NULL AND borrower_id = ?", borrowerID)
+	})
+}
+
+// anonymize detaches the returned loans selected by filter from their
+// borrowers, in batches.
+func (r *borrowingRepository) anonymize(now time.Time, filter func(*gorm.DB) *gorm.DB) (int64, error) {
+	db := r.db.Unscoped().Session(&gorm.Session{})
+	audit := &auditLogRepository{db: db}
+
+	var total int64
+	for {
+		// Anonymized rows drop out of the query, so every pass picks up
+		// where the last one stopped
+		var ids []uuid.UUID
+		query := filter(db.Model(&models.Borrowing{}).Where("borrower_id IS NOT NULL"))
+		if err := query.Limit(anonymizeBatchSize).Pluck("id", &ids).Error; err != nil || len(ids) == 0 {
+			return total, err
+		}
+
+		if err := db.Model(&models.Borrowing{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"borrower_id": nil, "anonymized_at": now}).Error; err != nil {
+			return total, err
+		}
+
+		// Settled fines would otherwise still tie the loan to the borrower
+		var fineIDs []uuid.UUID
+		if err := db.Model(&models.Fine{}).Where("borrowing_id IN ?", ids).Pluck("id", &fineIDs).Error; err != nil {
+			return total, err
+		}
+		if len(fineIDs) > 0 {
+			if err := db.Model(&models.Fine{}).Where("id IN ?", fineIDs).Update("borrowing_id", nil).Error; err != nil {
+				return total, err
+			}
+		}
+
+		// So would the audit trail of either, including renewals the
+		// borrower made themselves
+		if err := audit.scrubBorrower("borrowing", ids); err != nil {
+			return total, err
+		}
+		if err := audit.ScrubFields("fine", fineIDs, "borrowing_id"); err != nil {
+			return total, err
+		}
+
+		total += int64(len(ids))
+	}
+}
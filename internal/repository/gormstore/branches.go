@@ -0,0 +1,89 @@
+package gormstore
+
+import (
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type branchRepository struct {
+	db *gorm.DB
+}
+
+func (r *branchRepository) Create(branch *models.Branch) error {
+	return r.db.Create(branch).Error
+}
+
+func (r *branchRepository) Get(id uuid.UUID) (*models.Branch, error) {
+	var branch models.Branch
+	if err := r.db.First(&branch, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &branch, nil
+}
+
+func (r *branchRepository) FindByCode(code string, excludeID uuid.UUID) (*models.Branch, error) {
+	var branch models.Branch
+	if err := r.db.Where("code = ? AND id != ?", code, excludeID).First(&branch).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &branch, nil
+}
+
+func (r *branchRepository) List(offset, limit int) ([]models.Branch, int64, error) {
+	return paginate[models.Branch](r.db.Session(&gorm.Session{}), offset, limit, "code ASC")
+}
+
+func (r *branchRepository) Update(branch *models.Branch) error {
+	return save(r.db, branch)
+}
+
+func (r *branchRepository) Delete(branch *models.Branch) error {
+	return r.db.Delete(branch).Error
+}
+
+type locationRepository struct {
+	db *gorm.DB
+}
+
+func (r *locationRepository) Create(location *models.Location) error {
+	return r.db.Create(location).Error
+}
+
+func (r *locationRepository) Get(id uuid.UUID) (*models.Location, error) {
+	var location models.Location
+	if err := r.db.Preload("Branch").First(&location, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &location, nil
+}
+
+func (r *locationRepository) FindByCode(branchID uuid.UUID, code string, excludeID uuid.UUID) (*models.Location, error) {
+	var location models.Location
+	err := r.db.Where("branch_id = ? AND code = ? AND id != ?", branchID, code, excludeID).First(&location).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &location, nil
+}
+
+func (r *locationRepository) ListByBranch(branchID uuid.UUID) ([]models.Location, error) {
+	var locations []models.Location
+	err := r.db.Where("branch_id = ?", branchID).Order("code ASC").Find(&locations).Error
+	return locations, err
+}
+
+func (r *locationRepository) CountByBranch(branchID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Location{}).Where("branch_id = ?", branchID).Count(&count).Error
+	return count, err
+}
+
+func (r *locationRepository) Update(location *models.Location) error {
+	return save(r.db, location)
+}
+
+func (r *locationRepository) Delete(location *models.Location) error {
+	return r.db.Delete(location).Error
+}
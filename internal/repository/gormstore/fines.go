@@ -0,0 +1,46 @@
+package gormstore
+
+import (
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fineRepository struct {
+	db *gorm.DB
+}
+
+func (r *fineRepository) Create(fine *models.Fine) error {
+	return r.db.Create(fine).Error
+}
+
+func (r *fineRepository) Get(id uuid.UUID) (*models.Fine, error) {
+	var fine models.Fine
+	if err := r.db.First(&fine, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &fine, nil
+}
+
+func (r *fineRepository) ListByBorrower(borrowerID uuid.UUID, status string) ([]models.Fine, error) {
+	db := r.db.Where("borrower_id = ?", borrowerID)
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+
+	var fines []models.Fine
+	err := db.Order("created_at DESC").Find(&fines).Error
+	return fines, err
+}
+
+func (r *fineRepository) SumOutstanding(borrowerID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.Fine{}).Where("borrower_id = ? AND status = ?", borrowerID, models.FineOutstanding).
+		Select("COALESCE(SUM(amount), 0)").Scan(&total).Error
+	return total, err
+}
+
+func (r *fineRepository) Update(fine *models.Fine) error {
+	return save(r.db, fine)
+}
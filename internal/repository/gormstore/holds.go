@@ -0,0 +1,121 @@
+package gormstore
+
+import (
+	"time"
+
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type holdRepository struct {
+	db *gorm.DB
+}
+
+func (r *holdRepository) Create(hold *models.Hold) error {
+	return r.db.Create(hold).Error
+}
+
+func (r *holdRepository) Get(id uuid.UUID) (*models.Hold, error) {
+	var hold models.Hold
+	if err := preloadContributors(r.db, "Book.").First(&hold, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &hold, nil
+}
+
+func (r *holdRepository) ListByBorrower(borrowerID uuid.UUID, waitingOnly bool) ([]models.Hold, error) {
+	db := preloadContributors(r.db, "Book.").Where("borrower_id = ?", borrowerID)
+	if waitingOnly {
+		db = db.Where("status = ?", models.HoldWaiting)
+	}
+
+	var holds []models.Hold
+	err := db.Order("placed_at DESC").Find(&holds).Error
+	return holds, err
+}
+
+// activeBorrowers selects the ids of borrowers who are not deleted, whose
+// holds still count towards the queue.
+func (r *holdRepository) activeBorrowers() *gorm.DB {
+	return r.db.Session(&gorm.Session{}).Model(&models.Borrower{}).Select("id")
+}
+
+func (r *holdRepository) ListWaiting(bookID uuid.UUID) ([]models.Hold, error) {
+	var holds []models.Hold
+	err := r.db.Where("book_id = ? AND status = ?", bookID, models.HoldWaiting).
+		Where("borrower_id IN (?)", r.activeBorrowers()).
+		Order("placed_at ASC").Find(&holds).Error
+	return holds, err
+}
+
+func (r *holdRepository) CountWaiting(bookIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts := map[uuid.UUID]int64{}
+	if len(bookIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		BookID uuid.UUID
+		Count  int64
+	}
+	err := r.db.Model(&models.Hold{}).Select("book_id, COUNT(*) AS count").
+		Where("book_id IN ? AND status = ?", bookIDs, models.HoldWaiting).
+		Where("borrower_id IN (?)", r.activeBorrowers()).
+		Group("book_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.BookID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *holdRepository) Update(hold *models.Hold) error {
+	return save(r.db, hold)
+}
+
+func (r *holdRepository) AnonymizeClosedBefore(cutoff, now time.Time) (int64, error) {
+	keepers := r.db.Unscoped().Session(&gorm.Session{}).Model(&models.Borrower{}).
+		Select("id").Where("keep_history = ?", true)
+
+	return r.anonymize(now, func(query *gorm.DB) *gorm.DB {
+		return query.Where("(status = ? AND fulfilled_at < ?) OR (status = ? AND cancelled_at < ?)",
+			models.HoldFulfilled, cutoff, models.HoldCancelled, cutoff).
+			Where("borrower_id NOT IN (?)", keepers)
+	})
+}
+
+func (r *holdRepository) AnonymizeByBorrower(borrowerID uuid.UUID, now time.Time) (int64, error) {
+	return r.anonymize(now, func(query *gorm.DB) *gorm.DB {
+		return query.Where("borrower_id = ?", borrowerID)
+	})
+}
+
+// anonymize detaches the closed holds selected by filter from their
+// borrowers, in batches of anonymizeBatchSize.
+func (r *holdRepository) anonymize(now time.Time, filter func(*gorm.DB) *gorm.DB) (int64, error) {
+	db := r.db.Session(&gorm.Session{})
+	audit := &auditLogRepository{db: db}
+
+	var total int64
+	for {
+		var ids []uuid.UUID
+		query := filter(db.Model(&models.Hold{}).Where("borrower_id IS NOT NULL AND status <> ?", models.HoldWaiting))
+		if err := query.Limit(anonymizeBatchSize).Pluck("id", &ids).Error; err != nil || len(ids) == 0 {
+			return total, err
+		}
+
+		if err := db.Model(&models.Hold{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"borrower_id": nil, "anonymized_at": now}).Error; err != nil {
+			return total, err
+		}
+		if err := audit.scrubBorrower("hold", ids); err != nil {
+			return total, err
+		}
+
+		total += int64(len(ids))
+	}
+}
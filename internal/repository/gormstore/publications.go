@@ -0,0 +1,145 @@
+package gormstore
+
+import (
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type publisherRepository struct {
+	db      *gorm.DB
+	dialect dialect
+}
+
+func (r *publisherRepository) Create(publisher *models.Publisher) error {
+	return r.db.Create(publisher).Error
+}
+
+func (r *publisherRepository) Get(id uuid.UUID) (*models.Publisher, error) {
+	var publisher models.Publisher
+	if err := r.db.First(&publisher, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &publisher, nil
+}
+
+func (r *publisherRepository) FindByName(name string, excludeID uuid.UUID) (*models.Publisher, error) {
+	var publisher models.Publisher
+	if err := r.db.Where("LOWER(name) = LOWER(?) AND id != ?", name, excludeID).First(&publisher).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &publisher, nil
+}
+
+func (r *publisherRepository) Find(search string, offset, limit int) ([]models.Publisher, int64, error) {
+	db := r.db
+	if search != "" {
+		db = db.Where("name "+r.dialect.like+" ?", "%"+search+"%")
+	}
+	return paginate[models.Publisher](db.Session(&gorm.Session{}), offset, limit, "name ASC")
+}
+
+func (r *publisherRepository) CountBooks(id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Book{}).Where("publisher_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *publisherRepository) Update(publisher *models.Publisher) error {
+	return save(r.db, publisher)
+}
+
+func (r *publisherRepository) Delete(publisher *models.Publisher) error {
+	return r.db.Delete(publisher).Error
+}
+
+type seriesRepository struct {
+	db      *gorm.DB
+	dialect dialect
+}
+
+func (r *seriesRepository) Create(series *models.Series) error {
+	return r.db.Create(series).Error
+}
+
+func (r *seriesRepository) Get(id uuid.UUID) (*models.Series, error) {
+	var series models.Series
+	if err := r.db.First(&series, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &series, nil
+}
+
+func (r *seriesRepository) FindByName(name string, excludeID uuid.UUID) (*models.Series, error) {
+	var series models.Series
+	if err := r.db.Where("LOWER(name) = LOWER(?) AND id != ?", name, excludeID).First(&series).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &series, nil
+}
+
+func (r *seriesRepository) Find(search string, offset, limit int) ([]models.Series, int64, error) {
+	db := r.db
+	if search != "" {
+		db = db.Where("name "+r.dialect.like+" ?", "%"+search+"%")
+	}
+	return paginate[models.Series](db.Session(&gorm.Session{}), offset, limit, "name ASC")
+}
+
+func (r *seriesRepository) CountBooks(id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Book{}).Where("series_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *seriesRepository) Update(series *models.Series) error {
+	return save(r.db, series)
+}
+
+func (r *seriesRepository) Delete(series *models.Series) error {
+	return r.db.Delete(series).Error
+}
+
+type workRepository struct {
+	db      *gorm.DB
+	dialect dialect
+}
+
+func (r *workRepository) Create(work *models.Work) error {
+	return r.db.Create(work).Error
+}
+
+func (r *workRepository) Get(id uuid.UUID) (*models.Work, error) {
+	var work models.Work
+	err := preloadContributors(r.db, "Editions.").
+		Preload("Editions", func(db *gorm.DB) *gorm.DB { return db.Order("published_at, title") }).
+		Preload("Editions.Publisher").
+		First(&work, "id = ?", id).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &work, nil
+}
+
+func (r *workRepository) Find(search string, offset, limit int) ([]models.Work, int64, error) {
+	db := r.db
+	if search != "" {
+		db = db.Where("title "+r.dialect.like+" ?", "%"+search+"%")
+	}
+	return paginate[models.Work](db.Session(&gorm.Session{}), offset, limit, "title ASC")
+}
+
+func (r *workRepository) CountBooks(id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Book{}).Where("work_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *workRepository) Update(work *models.Work) error {
+	return save(r.db, work)
+}
+
+func (r *workRepository) Delete(work *models.Work) error {
+	return r.db.Delete(work).Error
+}
@@ -0,0 +1,49 @@
+package gormstore
+
+import (
+	"library-management-go/internal/models"
+	"library-management-go/internal/reqctx"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type sipTerminalRepository struct {
+	db *gorm.DB
+}
+
+func (r *sipTerminalRepository) Create(terminal *models.SIPTerminal) error {
+	return r.db.Create(terminal).Error
+}
+
+func (r *sipTerminalRepository) Get(id uuid.UUID) (*models.SIPTerminal, error) {
+	var terminal models.SIPTerminal
+	if err := r.db.First(&terminal, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &terminal, nil
+}
+
+func (r *sipTerminalRepository) FindByLogin(login string, excludeID uuid.UUID) (*models.SIPTerminal, error) {
+	// Terminals sign in before the server knows their tenant, so the lookup
+	// is not scoped to one
+	db := r.db.WithContext(reqctx.WithTenantID(r.db.Statement.Context, uuid.Nil))
+
+	var terminal models.SIPTerminal
+	if err := db.Where("login = ? AND id != ?", login, excludeID).First(&terminal).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &terminal, nil
+}
+
+func (r *sipTerminalRepository) List(offset, limit int) ([]models.SIPTerminal, int64, error) {
+	return paginate[models.SIPTerminal](r.db.Session(&gorm.Session{}), offset, limit, "login ASC")
+}
+
+func (r *sipTerminalRepository) Update(terminal *models.SIPTerminal) error {
+	return save(r.db, terminal)
+}
+
+func (r *sipTerminalRepository) Delete(terminal *models.SIPTerminal) error {
+	return r.db.Delete(terminal).Error
+}
@@ -0,0 +1,212 @@
+// Package gormstore implements the repository interfaces on top of GORM.
+// The same code backs both PostgreSQL and SQLite; the few SQL differences
+// between them are captured by dialect.
+package gormstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"library-management-go/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dialect holds the SQL fragments that differ between backends.
+type dialect struct {
+	// like is the case-insensitive pattern match operator.
+	like string
+}
+
+var (
+	postgresDialect = dialect{like: "ILIKE"}
+	// SQLite's LIKE is already case-insensitive for ASCII.
+	sqliteDialect = dialect{like: "LIKE"}
+)
+
+type Store struct {
+	db      *gorm.DB
+	dialect dialect
+}
+
+// New returns a Store for db, choosing the SQL dialect from db's driver.
+func New(db *gorm.DB) *Store {
+	d := postgresDialect
+	if db.Dialector.Name() == "sqlite" {
+		d = sqliteDialect
+	}
+	return &Store{db: db, dialect: d}
+}
+
+func (s *Store) Authors() repository.AuthorRepository {
+	return newAuthorRepository(s.db, s.dialect)
+}
+
+func (s *Store) Books() repository.BookRepository {
+	return newBookRepository(s.db, s.dialect)
+}
+
+func (s *Store) Subjects() repository.SubjectRepository {
+	return &subjectRepository{db: s.db, dialect: s.dialect}
+}
+
+func (s *Store) Publishers() repository.PublisherRepository {
+	return &publisherRepository{db: s.db, dialect: s.dialect}
+}
+
+func (s *Store) Series() repository.SeriesRepository {
+	return &seriesRepository{db: s.db, dialect: s.dialect}
+}
+
+func (s *Store) Works() repository.WorkRepository {
+	return &workRepository{db: s.db, dialect: s.dialect}
+}
+
+func (s *Store) Borrowers() repository.BorrowerRepository {
+	return newBorrowerRepository(s.db, s.dialect)
+}
+
+func (s *Store) BorrowerCategories() repository.BorrowerCategoryRepository {
+	return &borrowerCategoryRepository{db: s.db}
+}
+
+func (s *Store) BorrowerBlocks() repository.BorrowerBlockRepository {
+	return &borrowerBlockRepository{db: s.db}
+}
+
+func (s *Store) Borrowings() repository.BorrowingRepository {
+	return &borrowingRepository{db: s.db}
+}
+
+func (s *Store) Holds() repository.HoldRepository {
+	return &holdRepository{db: s.db}
+}
+
+func (s *Store) Fines() repository.FineRepository {
+	return &fineRepository{db: s.db}
+}
+
+func (s *Store) Branches() repository.BranchRepository {
+	return &branchRepository{db: s.db}
+}
+
+func (s *Store) Locations() repository.LocationRepository {
+	return &locationRepository{db: s.db}
+}
+
+func (s *Store) Transfers() repository.TransferRepository {
+	return &transferRepository{db: s.db}
+}
+
+func (s *Store) Tenants() repository.TenantRepository {
+	return &tenantRepository{db: s.db}
+}
+
+func (s *Store) SIPTerminals() repository.SIPTerminalRepository {
+	return &sipTerminalRepository{db: s.db}
+}
+
+func (s *Store) AuditLogs() repository.AuditLogRepository {
+	return &auditLogRepository{db: s.db}
+}
+
+func (s *Store) Transaction(fn func(tx repository.Store) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&Store{db: tx, dialect: s.dialect})
+	})
+}
+
+func (s *Store) WithContext(ctx context.Context) repository.Store {
+	return &Store{db: s.db.WithContext(ctx), dialect: s.dialect}
+}
+
+// notFound translates GORM's not-found error into repository.ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repository.ErrNotFound
+	}
+	return err
+}
+
+// paginate counts the rows matched by query and loads one page of them into
+// dest. query must be a new session so it can be reused for both queries.
+func paginate[T any](query *gorm.DB, offset, limit int, order string) ([]T, int64, error) {
+	var records []T
+	var total int64
+
+	if err := query.Model(new(T)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	find := query.Offset(offset).Limit(limit)
+	if order != "" {
+		find = find.Order(order)
+	}
+	if err := find.Find(&records).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return records, total, nil
+}
+
+// save updates every column of record without touching loaded associations.
+func save(db *gorm.DB, record interface{}) error {
+	return db.Omit(clause.Associations).Save(record).Error
+}
+
+// trash implements repository.Trash for soft-deleted models of type T.
+type trash[T any] struct {
+	db *gorm.DB
+	// preload lists the associations loaded with deleted records.
+	preload []string
+	// unreferenced is a condition that holds when no other row points at
+	// the record, so it can be purged without breaking foreign keys.
+	unreferenced string
+}
+
+func (t *trash[T]) deleted() *gorm.DB {
+	return t.db.Unscoped().Where("deleted_at IS NOT NULL").Session(&gorm.Session{})
+}
+
+func (t *trash[T]) ListDeleted(offset, limit int) ([]T, int64, error) {
+	query := t.deleted()
+	for _, association := range t.preload {
+		query = query.Preload(association, func(db *gorm.DB) *gorm.DB { return db.Unscoped() })
+	}
+	return paginate[T](query.Session(&gorm.Session{}), offset, limit, "deleted_at DESC")
+}
+
+func (t *trash[T]) GetDeleted(id uuid.UUID) (*T, error) {
+	var record T
+	if err := t.deleted().First(&record, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &record, nil
+}
+
+func (t *trash[T]) Restore(record *T) error {
+	return t.db.Unscoped().Model(record).Update("deleted_at", nil).Error
+}
+
+func (t *trash[T]) Purge(record *T) error {
+	return t.db.Unscoped().Delete(record).Error
+}
+
+func (t *trash[T]) Referenced(id uuid.UUID) (bool, error) {
+	var count int64
+	err := t.db.Unscoped().Model(new(T)).Where("id = ?", id).Where(t.unreferenced).Count(&count).Error
+	return count == 0, err
+}
+
+func (t *trash[T]) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	result := t.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Where(t.unreferenced).
+		Delete(new(T))
+	return result.RowsAffected, result.Error
+}
+
+var _ repository.Store = (*Store)(nil)
@@ -0,0 +1,66 @@
+package gormstore_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"library-management-go/internal/database"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/repository/gormstore"
+	"library-management-go/internal/repository/repositorytest"
+	"library-management-go/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+func TestSQLiteConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repository.Store {
+		return testutil.NewStore(t)
+	})
+}
+
+// TestPostgresConformance runs the suite against the database named by
+// TEST_DATABASE_URL. Its tables are emptied before every case.
+func TestPostgresConformance(t *testing.T) {
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	repositorytest.Run(t, func(t *testing.T) repository.Store {
+		db := testutil.OpenDB(t, databaseURL)
+		if err := db.Exec("TRUNCATE " + strings.Join(tables(t, db), ", ") + " CASCADE").Error; err != nil {
+			t.Fatalf("reset tables: %v", err)
+		}
+		return gormstore.New(db)
+	})
+}
+
+// tables returns the tables of every migrated model, including many2many
+// join tables, so new models are emptied without updating a list here.
+func tables(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+
+	var names []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, model := range database.Models() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatalf("parse %T: %v", model, err)
+		}
+		add(stmt.Schema.Table)
+		for _, rel := range stmt.Schema.Relationships.Relations {
+			if rel.JoinTable != nil {
+				add(rel.JoinTable.Table)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,103 @@
+package gormstore
+
+import (
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// subjectTree selects the IDs of a subject and every active subject below
+// it. UNION rather than UNION ALL stops at a cycle, should one exist.
+const subjectTree = `WITH RECURSIVE tree(id) AS (
+	SELECT id FROM subjects WHERE id = ?
+	UNION
+	SELECT subjects.id FROM subjects JOIN tree ON subjects.parent_id = tree.id
+	WHERE subjects.deleted_at IS NULL
+) SELECT id FROM tree`
+
+type subjectRepository struct {
+	db      *gorm.DB
+	dialect dialect
+}
+
+func (r *subjectRepository) Create(subject *models.Subject) error {
+	return r.db.Create(subject).Error
+}
+
+func (r *subjectRepository) Get(id uuid.UUID) (*models.Subject, error) {
+	var subject models.Subject
+	err := r.db.Preload("Parent").
+		Preload("Children", func(db *gorm.DB) *gorm.DB { return db.Order("name") }).
+		First(&subject, "id = ?", id).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &subject, nil
+}
+
+func (r *subjectRepository) FindByName(parentID *uuid.UUID, name string, excludeID uuid.UUID) (*models.Subject, error) {
+	db := r.db.Where("LOWER(name) = LOWER(?) AND id != ?", name, excludeID)
+	if parentID == nil {
+		db = db.Where("parent_id IS NULL")
+	} else {
+		db = db.Where("parent_id = ?", *parentID)
+	}
+
+	var subject models.Subject
+	if err := db.First(&subject).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &subject, nil
+}
+
+func (r *subjectRepository) Find(filter models.SubjectFilter, offset, limit int) ([]models.Subject, int64, error) {
+	db := r.db
+	if filter.Search != "" {
+		db = db.Where("name "+r.dialect.like+" ?", "%"+filter.Search+"%")
+	}
+	if filter.ParentID != uuid.Nil {
+		db = db.Where("parent_id = ?", filter.ParentID)
+	}
+	if filter.TopLevel {
+		db = db.Where("parent_id IS NULL")
+	}
+	return paginate[models.Subject](db.Session(&gorm.Session{}), offset, limit, "name ASC")
+}
+
+func (r *subjectRepository) Descendants(id uuid.UUID) ([]uuid.UUID, error) {
+	var tree []uuid.UUID
+	if err := r.db.Raw(subjectTree, id).Scan(&tree).Error; err != nil {
+		return nil, err
+	}
+
+	descendants := tree[:0]
+	for _, subjectID := range tree {
+		if subjectID != id {
+			descendants = append(descendants, subjectID)
+		}
+	}
+	return descendants, nil
+}
+
+func (r *subjectRepository) CountChildren(id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Subject{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *subjectRepository) CountBooks(id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Book{}).
+		Where("id IN (SELECT book_id FROM book_subjects WHERE subject_id = ?)", id).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *subjectRepository) Update(subject *models.Subject) error {
+	return save(r.db, subject)
+}
+
+func (r *subjectRepository) Delete(subject *models.Subject) error {
+	return r.db.Delete(subject).Error
+}
@@ -0,0 +1,50 @@
+package gormstore
+
+import (
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/reqctx"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type tenantRepository struct {
+	db *gorm.DB
+}
+
+func (r *tenantRepository) Create(tenant *models.Tenant) error {
+	return r.db.Create(tenant).Error
+}
+
+func (r *tenantRepository) Get(id uuid.UUID) (*models.Tenant, error) {
+	var tenant models.Tenant
+	if err := r.db.First(&tenant, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &tenant, nil
+}
+
+func (r *tenantRepository) GetBySlug(slug string) (*models.Tenant, error) {
+	var tenant models.Tenant
+	if err := r.db.First(&tenant, "slug = ?", slug).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &tenant, nil
+}
+
+func (r *tenantRepository) List(offset, limit int) ([]models.Tenant, int64, error) {
+	return paginate[models.Tenant](r.db.Session(&gorm.Session{}), offset, limit, "slug ASC")
+}
+
+func (r *tenantRepository) Update(tenant *models.Tenant) error {
+	return save(r.db, tenant)
+}
+
+func (r *tenantRepository) Current() (*models.Tenant, error) {
+	tenantID := reqctx.TenantID(r.db.Statement.Context)
+	if tenantID == uuid.Nil {
+		return nil, repository.ErrNotFound
+	}
+	return r.Get(tenantID)
+}
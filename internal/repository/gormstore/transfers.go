@@ -0,0 +1,46 @@
+package gormstore
+
+import (
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type transferRepository struct {
+	db *gorm.DB
+}
+
+func (r *transferRepository) withRelations() *gorm.DB {
+	return preloadContributors(r.db, "Book.").Preload("FromBranch").Preload("ToBranch")
+}
+
+func (r *transferRepository) Create(transfer *models.Transfer) error {
+	return r.db.Create(transfer).Error
+}
+
+func (r *transferRepository) Get(id uuid.UUID) (*models.Transfer, error) {
+	var transfer models.Transfer
+	if err := r.withRelations().First(&transfer, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &transfer, nil
+}
+
+func (r *transferRepository) Find(filter models.TransferFilter, offset, limit int) ([]models.Transfer, int64, error) {
+	db := r.withRelations()
+	if filter.FromBranchID != uuid.Nil {
+		db = db.Where("from_branch_id = ?", filter.FromBranchID)
+	}
+	if filter.ToBranchID != uuid.Nil {
+		db = db.Where("to_branch_id = ?", filter.ToBranchID)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	return paginate[models.Transfer](db.Session(&gorm.Session{}), offset, limit, "sent_at DESC")
+}
+
+func (r *transferRepository) Update(transfer *models.Transfer) error {
+	return save(r.db, transfer)
+}
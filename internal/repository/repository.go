@@ -0,0 +1,344 @@
+// Package repository defines the storage interfaces the services package is
+// built on, so the same business rules run against any supported backend.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFound is returned by lookups that match no record.
+var ErrNotFound = errors.New("record not found")
+
+// Store groups the repositories of one backend.
+type Store interface {
+	Authors() AuthorRepository
+	Books() BookRepository
+	Subjects() SubjectRepository
+	Publishers() PublisherRepository
+	Series() SeriesRepository
+	Works() WorkRepository
+	Borrowers() BorrowerRepository
+	BorrowerCategories() BorrowerCategoryRepository
+	BorrowerBlocks() BorrowerBlockRepository
+	Borrowings() BorrowingRepository
+	Holds() HoldRepository
+	Fines() FineRepository
+	Branches() BranchRepository
+	Locations() LocationRepository
+	Transfers() TransferRepository
+	Tenants() TenantRepository
+	SIPTerminals() SIPTerminalRepository
+	AuditLogs() AuditLogRepository
+
+	// Transaction runs fn against a Store bound to a single transaction,
+	// committing when fn returns nil and rolling back otherwise.
+	Transaction(fn func(tx Store) error) error
+
+	// WithContext returns a Store whose operations carry ctx.
+	WithContext(ctx context.Context) Store
+}
+
+// Trash holds the soft-delete operations shared by every entity that can be
+// deleted and later restored.
+type Trash[T any] interface {
+	ListDeleted(offset, limit int) ([]T, int64, error)
+	GetDeleted(id uuid.UUID) (*T, error)
+	Restore(record *T) error
+	Purge(record *T) error
+	// Referenced reports whether other rows still point at the record, in
+	// which case Purge would break their foreign keys.
+	Referenced(id uuid.UUID) (bool, error)
+	// PurgeDeletedBefore permanently removes records deleted before cutoff
+	// that no other row references.
+	PurgeDeletedBefore(cutoff time.Time) (int64, error)
+}
+
+type AuthorRepository interface {
+	Create(author *models.Author) error
+	Get(id uuid.UUID) (*models.Author, error)
+	// List lists authors by name.
+	List(offset, limit int) ([]models.Author, int64, error)
+	Search(query string, offset, limit int) ([]models.Author, int64, error)
+	Update(author *models.Author) error
+	Delete(author *models.Author) error
+	// Export streams the authors matching query (all when empty) to fn in
+	// batches of batchSize.
+	Export(query string, batchSize int, fn func([]models.Author) error) error
+
+	Trash[models.Author]
+}
+
+type BookRepository interface {
+	// Create inserts book together with its contributors.
+	Create(book *models.Book) error
+	// Get loads the book with its contributors and their authors.
+	Get(id uuid.UUID) (*models.Book, error)
+	// FindByISBN returns the active book with isbn other than excludeID.
+	FindByISBN(isbn string, excludeID uuid.UUID) (*models.Book, error)
+	// FindByBarcode returns the active book with barcode other than
+	// excludeID.
+	FindByBarcode(barcode string, excludeID uuid.UUID) (*models.Book, error)
+	Find(filter models.BookFilter, offset, limit int) ([]models.Book, int64, error)
+	Update(book *models.Book) error
+	// CheckOut marks the book on loan and off the shelf if it is still
+	// available, reporting whether it was, so two checkouts of the same
+	// item cannot both succeed.
+	CheckOut(id uuid.UUID) (bool, error)
+	Delete(book *models.Book) error
+	// SetContributors replaces the credits of the book.
+	SetContributors(bookID uuid.UUID, contributors []models.BookContributor) error
+	// SetSubjects replaces the subjects the book is tagged with.
+	SetSubjects(bookID uuid.UUID, subjectIDs []uuid.UUID) error
+	// AddSubject tags the book with the subject unless it already is.
+	AddSubject(bookID, subjectID uuid.UUID) error
+	// CountByAuthor counts the books the author is credited on in any role.
+	CountByAuthor(authorID uuid.UUID, includeDeleted bool) (int64, error)
+	// CountByLocation counts active books whose home or current location
+	// is locationID.
+	CountByLocation(locationID uuid.UUID) (int64, error)
+	Export(filter models.BookFilter, batchSize int, fn func([]models.Book) error) error
+
+	Trash[models.Book]
+}
+
+type SubjectRepository interface {
+	Create(subject *models.Subject) error
+	// Get loads the subject with its parent and children.
+	Get(id uuid.UUID) (*models.Subject, error)
+	// FindByName returns the active subject called name (ignoring case)
+	// under parentID, or at the top level when parentID is nil, other than
+	// excludeID.
+	FindByName(parentID *uuid.UUID, name string, excludeID uuid.UUID) (*models.Subject, error)
+	Find(filter models.SubjectFilter, offset, limit int) ([]models.Subject, int64, error)
+	// Descendants returns the IDs of every subject below id.
+	Descendants(id uuid.UUID) ([]uuid.UUID, error)
+	CountChildren(id uuid.UUID) (int64, error)
+	// CountBooks counts the active books tagged with the subject itself.
+	CountBooks(id uuid.UUID) (int64, error)
+	Update(subject *models.Subject) error
+	Delete(subject *models.Subject) error
+}
+
+type PublisherRepository interface {
+	Create(publisher *models.Publisher) error
+	Get(id uuid.UUID) (*models.Publisher, error)
+	// FindByName returns the active publisher called name (ignoring case)
+	// other than excludeID.
+	FindByName(name string, excludeID uuid.UUID) (*models.Publisher, error)
+	// Find lists publishers whose name matches search, or all when it is
+	// empty.
+	Find(search string, offset, limit int) ([]models.Publisher, int64, error)
+	// CountBooks counts the active books issued by the publisher.
+	CountBooks(id uuid.UUID) (int64, error)
+	Update(publisher *models.Publisher) error
+	Delete(publisher *models.Publisher) error
+}
+
+type SeriesRepository interface {
+	Create(series *models.Series) error
+	Get(id uuid.UUID) (*models.Series, error)
+	// FindByName returns the active series called name (ignoring case)
+	// other than excludeID.
+	FindByName(name string, excludeID uuid.UUID) (*models.Series, error)
+	Find(search string, offset, limit int) ([]models.Series, int64, error)
+	// CountBooks counts the active books in the series.
+	CountBooks(id uuid.UUID) (int64, error)
+	Update(series *models.Series) error
+	Delete(series *models.Series) error
+}
+
+type WorkRepository interface {
+	Create(work *models.Work) error
+	// Get loads the work with its editions, oldest first.
+	Get(id uuid.UUID) (*models.Work, error)
+	Find(search string, offset, limit int) ([]models.Work, int64, error)
+	// CountBooks counts the work's active editions.
+	CountBooks(id uuid.UUID) (int64, error)
+	Update(work *models.Work) error
+	Delete(work *models.Work) error
+}
+
+type BorrowerRepository interface {
+	Create(borrower *models.Borrower) error
+	// Get loads the borrower with their category.
+	Get(id uuid.UUID) (*models.Borrower, error)
+	// GetIncludingDeleted is Get for a borrower who may be in the trash.
+	GetIncludingDeleted(id uuid.UUID) (*models.Borrower, error)
+	// FindByEmail returns the active borrower with email other than excludeID.
+	FindByEmail(email string, excludeID uuid.UUID) (*models.Borrower, error)
+	FindByCardNumber(cardNumber string) (*models.Borrower, error)
+	CountByCategory(categoryID uuid.UUID) (int64, error)
+	// MarkExpired flags active borrowers whose membership expired before
+	// now as expired.
+	MarkExpired(now time.Time) error
+	// RecordPINFailure counts a wrong PIN against the borrower, locking
+	// them out until lockedUntil once maxAttempts have accumulated.
+	RecordPINFailure(id uuid.UUID, maxAttempts int, lockedUntil time.Time) error
+	// ResetPINFailures clears the borrower's count of wrong PINs.
+	ResetPINFailures(id uuid.UUID) error
+	List(offset, limit int) ([]models.Borrower, int64, error)
+	Search(query string, offset, limit int) ([]models.Borrower, int64, error)
+	Update(borrower *models.Borrower) error
+	Delete(borrower *models.Borrower) error
+	Export(query string, batchSize int, fn func([]models.Borrower) error) error
+
+	Trash[models.Borrower]
+}
+
+type BorrowerCategoryRepository interface {
+	Create(category *models.BorrowerCategory) error
+	Get(id uuid.UUID) (*models.BorrowerCategory, error)
+	// FindByCode returns the active category with code other than excludeID.
+	FindByCode(code string, excludeID uuid.UUID) (*models.BorrowerCategory, error)
+	List(offset, limit int) ([]models.BorrowerCategory, int64, error)
+	Update(category *models.BorrowerCategory) error
+	Delete(category *models.BorrowerCategory) error
+}
+
+type BorrowerBlockRepository interface {
+	Create(block *models.BorrowerBlock) error
+	// Get returns the block id placed on borrowerID.
+	Get(borrowerID, id uuid.UUID) (*models.BorrowerBlock, error)
+	// ListByBorrower lists all of the borrower's blocks, including lifted
+	// and expired ones, newest first.
+	ListByBorrower(borrowerID uuid.UUID) ([]models.BorrowerBlock, error)
+	// ListActive lists the borrower's blocks in force at now, newest first.
+	ListActive(borrowerID uuid.UUID, now time.Time) ([]models.BorrowerBlock, error)
+	Update(block *models.BorrowerBlock) error
+}
+
+type BorrowingRepository interface {
+	Create(borrowing *models.Borrowing) error
+	// Get loads the borrowing with its book (and the book's author) and borrower.
+	Get(id uuid.UUID) (*models.Borrowing, error)
+	Update(borrowing *models.Borrowing) error
+	// Find lists borrowings newest first, or longest overdue first when
+	// filter.OverdueOnly is set.
+	Find(filter models.BorrowingFilter, offset, limit int) ([]models.Borrowing, int64, error)
+	CountActiveByBorrower(borrowerID uuid.UUID) (int64, error)
+	CountOverdueByBorrower(borrowerID uuid.UUID, now time.Time) (int64, error)
+	HasActiveForBook(bookID uuid.UUID) (bool, error)
+	// CountByBook and CountByBorrower include soft-deleted history.
+	CountByBook(bookID uuid.UUID) (int64, error)
+	CountByBorrower(borrowerID uuid.UUID) (int64, error)
+	// MarkOverdue flags active borrowings due before now as overdue.
+	MarkOverdue(now time.Time) error
+	// AnonymizeReturnedBefore detaches loans returned before cutoff from
+	// their borrowers, unless the borrower keeps their history or the loan
+	// has an outstanding fine, and returns how many it anonymized. Fines
+	// and audit entries naming the loan are detached too, and renewals the
+	// borrower made themselves no longer name them as actor.
+	AnonymizeReturnedBefore(cutoff, now time.Time) (int64, error)
+	// AnonymizeByBorrower detaches all of the borrower's returned loans,
+	// with their fines and audit entries, and returns how many it
+	// anonymized.
+	AnonymizeByBorrower(borrowerID uuid.UUID, now time.Time) (int64, error)
+	Export(filter models.BorrowingFilter, batchSize int, fn func([]models.Borrowing) error) error
+}
+
+type HoldRepository interface {
+	Create(hold *models.Hold) error
+	// Get loads the hold with its book.
+	Get(id uuid.UUID) (*models.Hold, error)
+	// ListByBorrower lists the borrower's holds with their books, newest
+	// first, optionally only those still waiting.
+	ListByBorrower(borrowerID uuid.UUID, waitingOnly bool) ([]models.Hold, error)
+	// ListWaiting lists the waiting holds on a book in queue order, leaving
+	// out those of deleted borrowers.
+	ListWaiting(bookID uuid.UUID) ([]models.Hold, error)
+	// CountWaiting counts the waiting holds on each of the books, as
+	// ListWaiting would list them, leaving out books with none.
+	CountWaiting(bookIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+	Update(hold *models.Hold) error
+	// AnonymizeClosedBefore detaches holds fulfilled or cancelled before
+	// cutoff from their borrowers, unless the borrower keeps their history,
+	// along with their audit entries, and returns how many it anonymized.
+	AnonymizeClosedBefore(cutoff, now time.Time) (int64, error)
+	// AnonymizeByBorrower detaches all of the borrower's closed holds and
+	// their audit entries, and returns how many it anonymized.
+	AnonymizeByBorrower(borrowerID uuid.UUID, now time.Time) (int64, error)
+}
+
+type FineRepository interface {
+	Create(fine *models.Fine) error
+	Get(id uuid.UUID) (*models.Fine, error)
+	// ListByBorrower lists the borrower's fines newest first, optionally
+	// only those with status.
+	ListByBorrower(borrowerID uuid.UUID, status string) ([]models.Fine, error)
+	// SumOutstanding totals the borrower's outstanding fines.
+	SumOutstanding(borrowerID uuid.UUID) (int64, error)
+	Update(fine *models.Fine) error
+}
+
+type BranchRepository interface {
+	Create(branch *models.Branch) error
+	Get(id uuid.UUID) (*models.Branch, error)
+	// FindByCode returns the active branch with code other than excludeID.
+	FindByCode(code string, excludeID uuid.UUID) (*models.Branch, error)
+	List(offset, limit int) ([]models.Branch, int64, error)
+	Update(branch *models.Branch) error
+	Delete(branch *models.Branch) error
+}
+
+type LocationRepository interface {
+	Create(location *models.Location) error
+	// Get loads the location with its branch.
+	Get(id uuid.UUID) (*models.Location, error)
+	// FindByCode returns the active location of branchID with code other
+	// than excludeID.
+	FindByCode(branchID uuid.UUID, code string, excludeID uuid.UUID) (*models.Location, error)
+	ListByBranch(branchID uuid.UUID) ([]models.Location, error)
+	CountByBranch(branchID uuid.UUID) (int64, error)
+	Update(location *models.Location) error
+	Delete(location *models.Location) error
+}
+
+type TransferRepository interface {
+	Create(transfer *models.Transfer) error
+	// Get loads the transfer with its book and branches.
+	Get(id uuid.UUID) (*models.Transfer, error)
+	// Find lists transfers, most recently sent first.
+	Find(filter models.TransferFilter, offset, limit int) ([]models.Transfer, int64, error)
+	Update(transfer *models.Transfer) error
+}
+
+type TenantRepository interface {
+	Create(tenant *models.Tenant) error
+	Get(id uuid.UUID) (*models.Tenant, error)
+	GetBySlug(slug string) (*models.Tenant, error)
+	List(offset, limit int) ([]models.Tenant, int64, error)
+	Update(tenant *models.Tenant) error
+	// Current returns the tenant the store's context is scoped to, or
+	// ErrNotFound when it is not scoped to one.
+	Current() (*models.Tenant, error)
+}
+
+type SIPTerminalRepository interface {
+	Create(terminal *models.SIPTerminal) error
+	Get(id uuid.UUID) (*models.SIPTerminal, error)
+	// FindByLogin returns the terminal with login other than excludeID,
+	// whichever tenant it belongs to.
+	FindByLogin(login string, excludeID uuid.UUID) (*models.SIPTerminal, error)
+	List(offset, limit int) ([]models.SIPTerminal, int64, error)
+	Update(terminal *models.SIPTerminal) error
+	Delete(terminal *models.SIPTerminal) error
+}
+
+// AuditLogRepository reads and redacts the audit entries written by package
+// audit.
+type AuditLogRepository interface {
+	// ListForEntities lists the audit entries of the given records of
+	// entityType, oldest first.
+	ListForEntities(entityType string, ids []uuid.UUID) ([]models.AuditLog, error)
+	// ListByActor lists the audit entries recorded for actor, oldest first.
+	ListByActor(actor string) ([]models.AuditLog, error)
+	// ScrubFields removes fields from the recorded changes of the given
+	// records' audit entries.
+	ScrubFields(entityType string, ids []uuid.UUID, fields ...string) error
+}
@@ -0,0 +1,2137 @@
+// Package repositorytest is a conformance suite for repository.Store
+// implementations. Every backend runs the same cases so the services behave
+// identically whichever database they are deployed on.
+package repositorytest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"library-management-go/internal/audit"
+	"library-management-go/internal/callnumber"
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/reqctx"
+
+	"github.com/google/uuid"
+)
+
+// OpenFunc returns an empty, migrated Store for a single test.
+type OpenFunc func(t *testing.T) repository.Store
+
+// Run runs the conformance suite against the stores returned by open.
+func Run(t *testing.T, open OpenFunc) {
+	cases := []struct {
+		name string
+		fn   func(t *testing.T, store repository.Store)
+	}{
+		{"AuthorCRUD", testAuthorCRUD},
+		{"AuthorSearch", testAuthorSearch},
+		{"AuthorTrash", testAuthorTrash},
+		{"BookCRUD", testBookCRUD},
+		{"BookISBN", testBookISBN},
+		{"BookBarcode", testBookBarcode},
+		{"BookSearch", testBookSearch},
+		{"BookCountByAuthor", testBookCountByAuthor},
+		{"BookContributors", testBookContributors},
+		{"BookTrash", testBookTrash},
+		{"BorrowerCRUD", testBorrowerCRUD},
+		{"BorrowerEmail", testBorrowerEmail},
+		{"BorrowerSearch", testBorrowerSearch},
+		{"BorrowerCategoryCRUD", testBorrowerCategoryCRUD},
+		{"BorrowerMembership", testBorrowerMembership},
+		{"BorrowerBlocks", testBorrowerBlocks},
+		{"Holds", testHolds},
+		{"Fines", testFines},
+		{"BorrowingQueries", testBorrowingQueries},
+		{"BorrowingMarkOverdue", testBorrowingMarkOverdue},
+		{"BorrowingExport", testBorrowingExport},
+		{"BorrowingBranchFilter", testBorrowingBranchFilter},
+		{"BorrowingAnonymize", testBorrowingAnonymize},
+		{"HoldAnonymize", testHoldAnonymize},
+		{"AuditLogs", testAuditLogs},
+		{"BranchCRUD", testBranchCRUD},
+		{"LocationCRUD", testLocationCRUD},
+		{"BookBranchFilter", testBookBranchFilter},
+		{"Subjects", testSubjects},
+		{"BookSubjectFilter", testBookSubjectFilter},
+		{"Publications", testPublications},
+		{"BookEditionFilter", testBookEditionFilter},
+		{"BookShelfOrder", testBookShelfOrder},
+		{"BookAuthorFilter", testBookAuthorFilter},
+		{"BookQuery", testBookQuery},
+		{"Transfers", testTransfers},
+		{"Tenants", testTenants},
+		{"TenantIsolation", testTenantIsolation},
+		{"SIPTerminals", testSIPTerminals},
+		{"PurgeDeletedBefore", testPurgeDeletedBefore},
+		{"Pagination", testPagination},
+		{"Transaction", testTransaction},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.fn(t, open(t))
+		})
+	}
+}
+
+func createAuthor(t *testing.T, store repository.Store, name string) *models.Author {
+	t.Helper()
+	author := &models.Author{Name: name, Biography: "Biography of " + name}
+	if err := store.Authors().Create(author); err != nil {
+		t.Fatalf("create author: %v", err)
+	}
+	return author
+}
+
+func createBook(t *testing.T, store repository.Store, author *models.Author, title, isbn string) *models.Book {
+	t.Helper()
+	book := &models.Book{
+		Title:        title,
+		ISBN:         isbn,
+		Contributors: []models.BookContributor{{AuthorID: author.ID, Role: models.RoleAuthor}},
+		Available:    true,
+	}
+	if err := store.Books().Create(book); err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	return book
+}
+
+func createBorrower(t *testing.T, store repository.Store, name, email string) *models.Borrower {
+	t.Helper()
+	borrower := &models.Borrower{Name: name, Email: email, Phone: "555-0100"}
+	if err := store.Borrowers().Create(borrower); err != nil {
+		t.Fatalf("create borrower: %v", err)
+	}
+	return borrower
+}
+
+func createBorrowing(t *testing.T, store repository.Store, book *models.Book, borrower *models.Borrower, due time.Time) *models.Borrowing {
+	t.Helper()
+	borrowing := &models.Borrowing{
+		BookID:     book.ID,
+		BorrowerID: &borrower.ID,
+		BorrowedAt: time.Now(),
+		DueDate:    due,
+		Status:     "borrowed",
+	}
+	if err := store.Borrowings().Create(borrowing); err != nil {
+		t.Fatalf("create borrowing: %v", err)
+	}
+	return borrowing
+}
+
+func createBranch(t *testing.T, store repository.Store, code, name string) *models.Branch {
+	t.Helper()
+	branch := &models.Branch{Code: code, Name: name}
+	if err := store.Branches().Create(branch); err != nil {
+		t.Fatalf("create branch: %v", err)
+	}
+	return branch
+}
+
+func createLocation(t *testing.T, store repository.Store, branch *models.Branch, code string) *models.Location {
+	t.Helper()
+	location := &models.Location{BranchID: branch.ID, Code: code, Name: "Shelf " + code}
+	if err := store.Locations().Create(location); err != nil {
+		t.Fatalf("create location: %v", err)
+	}
+	return location
+}
+
+func createTenant(t *testing.T, store repository.Store, slug string) *models.Tenant {
+	t.Helper()
+	tenant := &models.Tenant{Slug: slug, Name: "Library " + slug, Active: true, Settings: models.DefaultTenantSettings}
+	if err := store.Tenants().Create(tenant); err != nil {
+		t.Fatalf("create tenant: %v", err)
+	}
+	return tenant
+}
+
+func expectNotFound(t *testing.T, err error) {
+	t.Helper()
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func expectNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func expectCount(t *testing.T, what string, got, want int64) {
+	t.Helper()
+	if got != want {
+		t.Fatalf("%s: got %d, want %d", what, got, want)
+	}
+}
+
+func testAuthorCRUD(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Ursula K. Le Guin")
+	if author.ID == uuid.Nil {
+		t.Fatal("expected Create to assign an ID")
+	}
+
+	got, err := store.Authors().Get(author.ID)
+	expectNoError(t, err)
+	if got.Name != author.Name || got.Biography != author.Biography {
+		t.Fatalf("got %+v, want %+v", got, author)
+	}
+
+	got.Name = "Ursula Le Guin"
+	expectNoError(t, store.Authors().Update(got))
+	got, err = store.Authors().Get(author.ID)
+	expectNoError(t, err)
+	if got.Name != "Ursula Le Guin" {
+		t.Fatalf("update not persisted: %q", got.Name)
+	}
+
+	expectNoError(t, store.Authors().Delete(got))
+	_, err = store.Authors().Get(author.ID)
+	expectNotFound(t, err)
+
+	_, err = store.Authors().Get(uuid.New())
+	expectNotFound(t, err)
+}
+
+func testAuthorSearch(t *testing.T, store repository.Store) {
+	createAuthor(t, store, "Octavia Butler")
+	createAuthor(t, store, "Ted Chiang")
+
+	// Matching is case-insensitive on every backend
+	authors, total, err := store.Authors().Search("octavia", 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "total", total, 1)
+	if len(authors) != 1 || authors[0].Name != "Octavia Butler" {
+		t.Fatalf("unexpected results: %+v", authors)
+	}
+
+	// The biography is searched too
+	_, total, err = store.Authors().Search("BIOGRAPHY OF TED", 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "total", total, 1)
+
+	var exported []string
+	expectNoError(t, store.Authors().Export("", 1, func(batch []models.Author) error {
+		if len(batch) > 1 {
+			return fmt.Errorf("batch of %d exceeds batch size", len(batch))
+		}
+		for _, a := range batch {
+			exported = append(exported, a.Name)
+		}
+		return nil
+	}))
+	if len(exported) != 2 {
+		t.Fatalf("exported %v, want 2 authors", exported)
+	}
+}
+
+func testAuthorTrash(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Iain M. Banks")
+
+	_, err := store.Authors().GetDeleted(author.ID)
+	expectNotFound(t, err)
+
+	expectNoError(t, store.Authors().Delete(author))
+
+	deleted, total, err := store.Authors().ListDeleted(0, 10)
+	expectNoError(t, err)
+	expectCount(t, "deleted", total, 1)
+	if deleted[0].ID != author.ID {
+		t.Fatalf("unexpected deleted author %v", deleted[0].ID)
+	}
+
+	trashed, err := store.Authors().GetDeleted(author.ID)
+	expectNoError(t, err)
+	expectNoError(t, store.Authors().Restore(trashed))
+
+	if _, err := store.Authors().Get(author.ID); err != nil {
+		t.Fatalf("restored author not found: %v", err)
+	}
+	_, total, err = store.Authors().ListDeleted(0, 10)
+	expectNoError(t, err)
+	expectCount(t, "deleted after restore", total, 0)
+
+	expectNoError(t, store.Authors().Delete(author))
+	expectNoError(t, store.Authors().Purge(author))
+	_, err = store.Authors().GetDeleted(author.ID)
+	expectNotFound(t, err)
+}
+
+func testBookCRUD(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Frank Herbert")
+	book := createBook(t, store, author, "Dune", "9780441013593")
+
+	got, err := store.Books().Get(book.ID)
+	expectNoError(t, err)
+	if len(got.Contributors) != 1 || got.Contributors[0].Author.Name != author.Name {
+		t.Fatalf("expected author to be loaded, got %+v", got.Contributors)
+	}
+	if !got.Available {
+		t.Fatal("expected book to be available")
+	}
+
+	got.Title = "Dune (Deluxe Edition)"
+	got.Available = false
+	expectNoError(t, store.Books().Update(got))
+	got, err = store.Books().Get(book.ID)
+	expectNoError(t, err)
+	if got.Title != "Dune (Deluxe Edition)" || got.Available {
+		t.Fatalf("update not persisted: %+v", got)
+	}
+
+	// Only an available book can be checked out, and only once
+	shelved := createBook(t, store, author, "Children of Dune", "9780441104024")
+	location := createLocation(t, store, createBranch(t, store, "DUNE", "Arrakeen"), "A")
+	shelved.CurrentLocationID = &location.ID
+	expectNoError(t, store.Books().Update(shelved))
+	for i, want := range []bool{true, false} {
+		checkedOut, err := store.Books().CheckOut(shelved.ID)
+		expectNoError(t, err)
+		if checkedOut != want {
+			t.Fatalf("checkout %d = %v, want %v", i+1, checkedOut, want)
+		}
+	}
+	shelved, err = store.Books().Get(shelved.ID)
+	expectNoError(t, err)
+	if shelved.Available || shelved.CurrentLocationID != nil {
+		t.Fatalf("checked out book still on the shelf: %+v", shelved)
+	}
+
+	expectNoError(t, store.Books().Delete(got))
+	_, err = store.Books().Get(book.ID)
+	expectNotFound(t, err)
+}
+
+func testBookISBN(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Neal Stephenson")
+	book := createBook(t, store, author, "Anathem", "9780061474095")
+
+	found, err := store.Books().FindByISBN("9780061474095", uuid.Nil)
+	expectNoError(t, err)
+	if found.ID != book.ID {
+		t.Fatalf("found %v, want %v", found.ID, book.ID)
+	}
+
+	_, err = store.Books().FindByISBN("9780061474095", book.ID)
+	expectNotFound(t, err)
+
+	// A soft-deleted book frees its ISBN for reuse
+	expectNoError(t, store.Books().Delete(book))
+	_, err = store.Books().FindByISBN("9780061474095", uuid.Nil)
+	expectNotFound(t, err)
+	createBook(t, store, author, "Anathem (Reissue)", "9780061474095")
+}
+
+func testBookBarcode(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Ursula K. Le Guin")
+	// Books without a barcode do not collide
+	book := createBook(t, store, author, "The Dispossessed", "9780061054884")
+	createBook(t, store, author, "The Left Hand of Darkness", "9780441478125")
+
+	book.Barcode = "30000000000018"
+	expectNoError(t, store.Books().Update(book))
+	found, err := store.Books().FindByBarcode("30000000000018", uuid.Nil)
+	expectNoError(t, err)
+	if found.ID != book.ID {
+		t.Fatalf("found %v, want %v", found.ID, book.ID)
+	}
+	_, err = store.Books().FindByBarcode("30000000000018", book.ID)
+	expectNotFound(t, err)
+
+	duplicate := &models.Book{Title: "The Dispossessed", ISBN: "9780061054885", Barcode: "30000000000018",
+		Contributors: []models.BookContributor{{AuthorID: author.ID, Role: models.RoleAuthor}}}
+	if err := store.Books().Create(duplicate); err == nil {
+		t.Fatal("expected a duplicate barcode to be rejected")
+	}
+
+	_, total, err := store.Books().Find(models.BookFilter{Search: "30000000000018"}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "books by barcode", total, 1)
+
+	// A soft-deleted book frees its barcode for reuse
+	expectNoError(t, store.Books().Delete(book))
+	_, err = store.Books().FindByBarcode("30000000000018", uuid.Nil)
+	expectNotFound(t, err)
+}
+
+func testBookSearch(t *testing.T, store repository.Store) {
+	gibson := createAuthor(t, store, "William Gibson")
+	leckie := createAuthor(t, store, "Ann Leckie")
+	createBook(t, store, gibson, "Neuromancer", "9780441569595")
+	createBook(t, store, leckie, "Ancillary Justice", "9780316246620")
+
+	cases := []struct {
+		query string
+		want  int64
+	}{
+		{"neuromancer", 1},
+		{"LECKIE", 1},
+		{"978", 2},
+		{"nothing matches", 0},
+	}
+	for _, tc := range cases {
+		books, total, err := store.Books().Find(models.BookFilter{Search: tc.query}, 0, 10)
+		expectNoError(t, err)
+		expectCount(t, "search "+tc.query, total, tc.want)
+		for _, book := range books {
+			if len(book.Contributors) == 0 || book.Contributors[0].Author.Name == "" {
+				t.Fatalf("expected author to be loaded for %q", book.Title)
+			}
+		}
+	}
+
+	var exported int
+	expectNoError(t, store.Books().Export(models.BookFilter{Search: "gibson"}, 10, func(batch []models.Book) error {
+		exported += len(batch)
+		return nil
+	}))
+	if exported != 1 {
+		t.Fatalf("exported %d books, want 1", exported)
+	}
+}
+
+func testBookCountByAuthor(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Becky Chambers")
+	createBook(t, store, author, "The Long Way to a Small, Angry Planet", "9781473619791")
+	deleted := createBook(t, store, author, "Record of a Spaceborn Few", "9781473647640")
+	expectNoError(t, store.Books().Delete(deleted))
+
+	count, err := store.Books().CountByAuthor(author.ID, false)
+	expectNoError(t, err)
+	expectCount(t, "active books", count, 1)
+
+	count, err = store.Books().CountByAuthor(author.ID, true)
+	expectNoError(t, err)
+	expectCount(t, "all books", count, 2)
+}
+
+func testBookContributors(t *testing.T, store repository.Store) {
+	pratchett := createAuthor(t, store, "Terry Pratchett")
+	gaiman := createAuthor(t, store, "Neil Gaiman")
+	kirby := createAuthor(t, store, "Josh Kirby")
+	book := &models.Book{
+		Title: "Good Omens",
+		ISBN:  "9780060853983",
+		Contributors: []models.BookContributor{
+			{AuthorID: gaiman.ID, Role: models.RoleAuthor, Position: 1},
+			{AuthorID: pratchett.ID, Role: models.RoleAuthor, Position: 0},
+			{AuthorID: kirby.ID, Role: models.RoleIllustrator, Position: 2},
+		},
+		Available: true,
+	}
+	expectNoError(t, store.Books().Create(book))
+
+	got, err := store.Books().Get(book.ID)
+	expectNoError(t, err)
+	var names []string
+	for _, c := range got.Contributors {
+		names = append(names, c.Author.Name+"/"+c.Role)
+	}
+	if strings.Join(names, ", ") != "Terry Pratchett/author, Neil Gaiman/author, Josh Kirby/illustrator" {
+		t.Fatalf("contributors out of order: %v", names)
+	}
+
+	// Every contributor matches a search, without repeating the book
+	books, total, err := store.Books().Find(models.BookFilter{Search: "i"}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "search", total, 1)
+	expectCount(t, "rows", int64(len(books)), 1)
+	_, total, err = store.Books().Find(models.BookFilter{Search: "kirby"}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "illustrator search", total, 1)
+
+	count, err := store.Books().CountByAuthor(kirby.ID, false)
+	expectNoError(t, err)
+	expectCount(t, "illustrated books", count, 1)
+
+	// Replacing the credits drops the illustrator
+	expectNoError(t, store.Books().SetContributors(book.ID, []models.BookContributor{
+		{AuthorID: pratchett.ID, Role: models.RoleAuthor, Position: 0},
+		{AuthorID: gaiman.ID, Role: models.RoleAuthor, Position: 1},
+	}))
+	count, err = store.Books().CountByAuthor(kirby.ID, true)
+	expectNoError(t, err)
+	expectCount(t, "illustrated books after replace", count, 0)
+
+	// Purging the book removes its credits, so the authors can go too
+	expectNoError(t, store.Books().Delete(book))
+	trashed, err := store.Books().GetDeleted(book.ID)
+	expectNoError(t, err)
+	if len(trashed.Contributors) != 2 {
+		t.Fatalf("expected contributors on deleted book, got %+v", trashed.Contributors)
+	}
+	expectNoError(t, store.Books().Purge(trashed))
+	count, err = store.Books().CountByAuthor(gaiman.ID, true)
+	expectNoError(t, err)
+	expectCount(t, "books after purge", count, 0)
+	expectNoError(t, store.Authors().Delete(gaiman))
+	deleted, err := store.Authors().GetDeleted(gaiman.ID)
+	expectNoError(t, err)
+	expectNoError(t, store.Authors().Purge(deleted))
+}
+
+func testBookTrash(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "N. K. Jemisin")
+	book := createBook(t, store, author, "The Fifth Season", "9780316229296")
+	expectNoError(t, store.Books().Delete(book))
+
+	deleted, total, err := store.Books().ListDeleted(0, 10)
+	expectNoError(t, err)
+	expectCount(t, "deleted", total, 1)
+	if len(deleted[0].Contributors) != 1 || deleted[0].Contributors[0].Author.ID != author.ID {
+		t.Fatal("expected author to be loaded on deleted books")
+	}
+
+	trashed, err := store.Books().GetDeleted(book.ID)
+	expectNoError(t, err)
+	expectNoError(t, store.Books().Restore(trashed))
+	if _, err := store.Books().Get(book.ID); err != nil {
+		t.Fatalf("restored book not found: %v", err)
+	}
+}
+
+func testBorrowerCRUD(t *testing.T, store repository.Store) {
+	borrower := createBorrower(t, store, "Ada Lovelace", "ada@example.com")
+
+	got, err := store.Borrowers().Get(borrower.ID)
+	expectNoError(t, err)
+	if got.Email != "ada@example.com" {
+		t.Fatalf("unexpected borrower %+v", got)
+	}
+
+	got.Address = "12 St James's Square"
+	expectNoError(t, store.Borrowers().Update(got))
+	got, err = store.Borrowers().Get(borrower.ID)
+	expectNoError(t, err)
+	if got.Address != "12 St James's Square" {
+		t.Fatalf("update not persisted: %+v", got)
+	}
+
+	expectNoError(t, store.Borrowers().Delete(got))
+	_, err = store.Borrowers().Get(borrower.ID)
+	expectNotFound(t, err)
+	_, err = store.Borrowers().GetIncludingDeleted(borrower.ID)
+	expectNoError(t, err)
+	_, err = store.Borrowers().GetIncludingDeleted(uuid.New())
+	expectNotFound(t, err)
+
+	trashed, err := store.Borrowers().GetDeleted(borrower.ID)
+	expectNoError(t, err)
+	expectNoError(t, store.Borrowers().Restore(trashed))
+	_, err = store.Borrowers().Get(borrower.ID)
+	expectNoError(t, err)
+}
+
+func testBorrowerEmail(t *testing.T, store repository.Store) {
+	borrower := createBorrower(t, store, "Grace Hopper", "grace@example.com")
+
+	found, err := store.Borrowers().FindByEmail("grace@example.com", uuid.Nil)
+	expectNoError(t, err)
+	if found.ID != borrower.ID {
+		t.Fatalf("found %v, want %v", found.ID, borrower.ID)
+	}
+
+	_, err = store.Borrowers().FindByEmail("grace@example.com", borrower.ID)
+	expectNotFound(t, err)
+
+	// A soft-deleted borrower frees the email for reuse
+	expectNoError(t, store.Borrowers().Delete(borrower))
+	createBorrower(t, store, "Grace Brewster Hopper", "grace@example.com")
+}
+
+func testBorrowerSearch(t *testing.T, store repository.Store) {
+	createBorrower(t, store, "Alan Turing", "alan@example.com")
+	createBorrower(t, store, "Katherine Johnson", "katherine@example.com")
+
+	_, total, err := store.Borrowers().Search("TURING", 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "name match", total, 1)
+
+	_, total, err = store.Borrowers().Search("example.com", 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "email match", total, 2)
+
+	var exported int
+	expectNoError(t, store.Borrowers().Export("katherine", 10, func(batch []models.Borrower) error {
+		exported += len(batch)
+		return nil
+	}))
+	if exported != 1 {
+		t.Fatalf("exported %d borrowers, want 1", exported)
+	}
+}
+
+func testBorrowerCategoryCRUD(t *testing.T, store repository.Store) {
+	adult := &models.BorrowerCategory{Code: "ADULT", Name: "Adult", MembershipDays: 365}
+	expectNoError(t, store.BorrowerCategories().Create(adult))
+	expectNoError(t, store.BorrowerCategories().Create(&models.BorrowerCategory{Code: "CHILD", Name: "Child", MaxActiveLoans: 3}))
+
+	got, err := store.BorrowerCategories().Get(adult.ID)
+	expectNoError(t, err)
+	if got.MembershipDays != 365 {
+		t.Fatalf("got membership days %d, want 365", got.MembershipDays)
+	}
+
+	_, err = store.BorrowerCategories().FindByCode("ADULT", uuid.Nil)
+	expectNoError(t, err)
+	_, err = store.BorrowerCategories().FindByCode("ADULT", adult.ID)
+	expectNotFound(t, err)
+
+	categories, total, err := store.BorrowerCategories().List(0, 10)
+	expectNoError(t, err)
+	expectCount(t, "categories", total, 2)
+	if categories[0].Code != "ADULT" {
+		t.Fatalf("expected categories ordered by code, got %q first", categories[0].Code)
+	}
+
+	got.MaxActiveLoans = 10
+	expectNoError(t, store.BorrowerCategories().Update(got))
+	expectNoError(t, store.BorrowerCategories().Delete(got))
+	_, err = store.BorrowerCategories().Get(adult.ID)
+	expectNotFound(t, err)
+}
+
+func testBorrowerMembership(t *testing.T, store repository.Store) {
+	staff := &models.BorrowerCategory{Code: "STAFF", Name: "Staff"}
+	expectNoError(t, store.BorrowerCategories().Create(staff))
+
+	lapsed := time.Now().Add(-time.Hour)
+	current := time.Now().Add(time.Hour)
+	expired := &models.Borrower{Name: "Lapsed", Email: "lapsed@example.com", CardNumber: "20000000000014",
+		Status: models.BorrowerActive, MembershipExpiresAt: &lapsed, CategoryID: &staff.ID}
+	expectNoError(t, store.Borrowers().Create(expired))
+	valid := &models.Borrower{Name: "Current", Email: "current@example.com", CardNumber: "20000000000022",
+		Status: models.BorrowerActive, MembershipExpiresAt: &current}
+	expectNoError(t, store.Borrowers().Create(valid))
+	suspended := &models.Borrower{Name: "Suspended", Email: "suspended@example.com", CardNumber: "20000000000030",
+		Status: models.BorrowerSuspended, MembershipExpiresAt: &lapsed}
+	expectNoError(t, store.Borrowers().Create(suspended))
+	createBorrower(t, store, "No Card", "nocard@example.com")
+
+	got, err := store.Borrowers().FindByCardNumber("20000000000014")
+	expectNoError(t, err)
+	if got.ID != expired.ID || got.Category == nil || got.Category.Code != "STAFF" {
+		t.Fatalf("expected borrower with category, got %+v", got)
+	}
+	_, err = store.Borrowers().FindByCardNumber("20000000000048")
+	expectNotFound(t, err)
+
+	_, total, err := store.Borrowers().Search("20000000000022", 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "search by card number", total, 1)
+
+	count, err := store.Borrowers().CountByCategory(staff.ID)
+	expectNoError(t, err)
+	expectCount(t, "staff borrowers", count, 1)
+
+	expectNoError(t, store.Borrowers().MarkExpired(time.Now()))
+	for _, tc := range []struct {
+		borrower *models.Borrower
+		want     string
+	}{
+		{expired, models.BorrowerExpired},
+		{valid, models.BorrowerActive},
+		{suspended, models.BorrowerSuspended},
+	} {
+		got, err := store.Borrowers().Get(tc.borrower.ID)
+		expectNoError(t, err)
+		if got.Status != tc.want {
+			t.Fatalf("%s: status %q, want %q", tc.borrower.Name, got.Status, tc.want)
+		}
+	}
+}
+
+func testBorrowerBlocks(t *testing.T, store repository.Store) {
+	borrower := createBorrower(t, store, "Blocked", "blocked@example.com")
+	other := createBorrower(t, store, "Other", "other@example.com")
+
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	permanent := &models.BorrowerBlock{BorrowerID: borrower.ID, Reason: models.BlockLostCard, CreatedBy: "desk"}
+	expectNoError(t, store.BorrowerBlocks().Create(permanent))
+	temporary := &models.BorrowerBlock{BorrowerID: borrower.ID, Reason: models.BlockBehaviour, CreatedBy: "desk",
+		Overridable: true, ExpiresAt: &future}
+	expectNoError(t, store.BorrowerBlocks().Create(temporary))
+	lapsed := &models.BorrowerBlock{BorrowerID: borrower.ID, Reason: models.BlockUnpaidFees, CreatedBy: "desk", ExpiresAt: &past}
+	expectNoError(t, store.BorrowerBlocks().Create(lapsed))
+
+	got, err := store.BorrowerBlocks().Get(borrower.ID, temporary.ID)
+	expectNoError(t, err)
+	if !got.Overridable || got.ExpiresAt == nil {
+		t.Fatalf("expected overridable block with expiry, got %+v", got)
+	}
+	_, err = store.BorrowerBlocks().Get(other.ID, temporary.ID)
+	expectNotFound(t, err)
+
+	active, err := store.BorrowerBlocks().ListActive(borrower.ID, now)
+	expectNoError(t, err)
+	expectCount(t, "active blocks", int64(len(active)), 2)
+
+	permanent.LiftedAt = &now
+	permanent.LiftedBy = "desk"
+	expectNoError(t, store.BorrowerBlocks().Update(permanent))
+
+	active, err = store.BorrowerBlocks().ListActive(borrower.ID, now)
+	expectNoError(t, err)
+	if len(active) != 1 || active[0].ID != temporary.ID {
+		t.Fatalf("expected only the temporary block to be active, got %+v", active)
+	}
+
+	all, err := store.BorrowerBlocks().ListByBorrower(borrower.ID)
+	expectNoError(t, err)
+	expectCount(t, "all blocks", int64(len(all)), 3)
+
+	none, err := store.BorrowerBlocks().ListActive(other.ID, now)
+	expectNoError(t, err)
+	expectCount(t, "other borrower's blocks", int64(len(none)), 0)
+}
+
+func testHolds(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Tove Jansson")
+	book := createBook(t, store, author, "Comet in Moominland", "9780000000401")
+	other := createBook(t, store, author, "Finn Family Moomintroll", "9780000000402")
+	first := createBorrower(t, store, "First", "first@example.com")
+	second := createBorrower(t, store, "Second", "second@example.com")
+
+	now := time.Now()
+	place := func(book *models.Book, borrower *models.Borrower, placedAt time.Time) *models.Hold {
+		hold := &models.Hold{BookID: book.ID, BorrowerID: &borrower.ID, Status: models.HoldWaiting, PlacedAt: placedAt}
+		expectNoError(t, store.Holds().Create(hold))
+		return hold
+	}
+	late := place(book, second, now)
+	early := place(book, first, now.Add(-time.Hour))
+	elsewhere := place(other, first, now)
+
+	got, err := store.Holds().Get(early.ID)
+	expectNoError(t, err)
+	if got.Book == nil || got.Book.Contributors[0].Author.ID != author.ID {
+		t.Fatalf("expected hold with book and author, got %+v", got)
+	}
+	_, err = store.Holds().Get(uuid.New())
+	expectNotFound(t, err)
+
+	queue, err := store.Holds().ListWaiting(book.ID)
+	expectNoError(t, err)
+	if len(queue) != 2 || queue[0].ID != early.ID || queue[1].ID != late.ID {
+		t.Fatalf("expected queue in placement order, got %+v", queue)
+	}
+
+	elsewhere.Status = models.HoldCancelled
+	expectNoError(t, store.Holds().Update(elsewhere))
+
+	waiting, err := store.Holds().ListByBorrower(first.ID, true)
+	expectNoError(t, err)
+	if len(waiting) != 1 || waiting[0].ID != early.ID {
+		t.Fatalf("expected one waiting hold, got %+v", waiting)
+	}
+	all, err := store.Holds().ListByBorrower(first.ID, false)
+	expectNoError(t, err)
+	expectCount(t, "all holds", int64(len(all)), 2)
+
+	counts, err := store.Holds().CountWaiting([]uuid.UUID{book.ID, other.ID})
+	expectNoError(t, err)
+	if len(counts) != 1 || counts[book.ID] != 2 {
+		t.Fatalf("expected two waiting holds on one book, got %v", counts)
+	}
+	counts, err = store.Holds().CountWaiting(nil)
+	expectNoError(t, err)
+	expectCount(t, "holds on no books", int64(len(counts)), 0)
+
+	// Deleted borrowers leave the queue
+	expectNoError(t, store.Borrowers().Delete(first))
+	queue, err = store.Holds().ListWaiting(book.ID)
+	expectNoError(t, err)
+	if len(queue) != 1 || queue[0].ID != late.ID {
+		t.Fatalf("expected only the remaining borrower's hold, got %+v", queue)
+	}
+	counts, err = store.Holds().CountWaiting([]uuid.UUID{book.ID})
+	expectNoError(t, err)
+	expectCount(t, "waiting holds after deletion", counts[book.ID], 1)
+}
+
+func testFines(t *testing.T, store repository.Store) {
+	borrower := createBorrower(t, store, "Debtor", "debtor@example.com")
+	other := createBorrower(t, store, "Other", "other@example.com")
+
+	charge := func(borrower *models.Borrower, amount int64, status string) *models.Fine {
+		fine := &models.Fine{BorrowerID: borrower.ID, Amount: amount, Reason: "overdue", Status: status}
+		expectNoError(t, store.Fines().Create(fine))
+		return fine
+	}
+	unpaid := charge(borrower, 125, models.FineOutstanding)
+	charge(borrower, 50, models.FineOutstanding)
+	charge(borrower, 75, models.FinePaid)
+	charge(other, 300, models.FineOutstanding)
+
+	total, err := store.Fines().SumOutstanding(borrower.ID)
+	expectNoError(t, err)
+	expectCount(t, "outstanding total", total, 175)
+
+	outstanding, err := store.Fines().ListByBorrower(borrower.ID, models.FineOutstanding)
+	expectNoError(t, err)
+	expectCount(t, "outstanding fines", int64(len(outstanding)), 2)
+	all, err := store.Fines().ListByBorrower(borrower.ID, "")
+	expectNoError(t, err)
+	expectCount(t, "all fines", int64(len(all)), 3)
+
+	unpaid.Status = models.FineWaived
+	expectNoError(t, store.Fines().Update(unpaid))
+	got, err := store.Fines().Get(unpaid.ID)
+	expectNoError(t, err)
+	if got.Status != models.FineWaived {
+		t.Fatalf("status = %q, want waived", got.Status)
+	}
+
+	total, err = store.Fines().SumOutstanding(borrower.ID)
+	expectNoError(t, err)
+	expectCount(t, "outstanding total after waiving", total, 50)
+
+	none, err := store.Fines().SumOutstanding(uuid.New())
+	expectNoError(t, err)
+	expectCount(t, "outstanding total without fines", none, 0)
+}
+
+func testBorrowingAnonymize(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Jane Austen")
+	borrower := createBorrower(t, store, "Reader", "reader@example.com")
+	keeper := createBorrower(t, store, "Keeper", "keeper@example.com")
+	keeper.KeepHistory = true
+	expectNoError(t, store.Borrowers().Update(keeper))
+
+	now := time.Now()
+	loan := func(isbn string, borrower *models.Borrower, returnedAgo time.Duration) *models.Borrowing {
+		book := createBook(t, store, author, "Book "+isbn, isbn)
+		borrowing := createBorrowing(t, store, book, borrower, now.Add(-returnedAgo))
+		if returnedAgo > 0 {
+			returnedAt := now.Add(-returnedAgo)
+			borrowing.ReturnedAt = &returnedAt
+			borrowing.Status = "returned"
+			expectNoError(t, store.Borrowings().Update(borrowing))
+		}
+		return borrowing
+	}
+	old := loan("9780141439518", borrower, 100*24*time.Hour)
+	recent := loan("9780141439587", borrower, 24*time.Hour)
+	active := loan("9780141439662", borrower, 0)
+	kept := loan("9780141439808", keeper, 100*24*time.Hour)
+	owing := loan("9780141439600", borrower, 100*24*time.Hour)
+
+	settled := &models.Fine{BorrowerID: borrower.ID, BorrowingID: &old.ID, Amount: 50, Reason: "overdue", Status: models.FinePaid}
+	expectNoError(t, store.Fines().Create(settled))
+	unpaid := &models.Fine{BorrowerID: borrower.ID, BorrowingID: &owing.ID, Amount: 50, Reason: "overdue", Status: models.FineOutstanding}
+	expectNoError(t, store.Fines().Create(unpaid))
+
+	// A renewal the borrower made themselves names them as actor
+	self := store.WithContext(reqctx.WithActor(context.Background(), reqctx.BorrowerActor(borrower.ID)))
+	old.RenewalCount = 1
+	expectNoError(t, self.Borrowings().Update(old))
+
+	count, err := store.Borrowings().AnonymizeReturnedBefore(now.Add(-30*24*time.Hour), now)
+	expectNoError(t, err)
+	expectCount(t, "anonymized loans", count, 1)
+
+	entries, err := store.AuditLogs().ListByActor(reqctx.BorrowerActor(borrower.ID))
+	expectNoError(t, err)
+	if len(entries) != 0 {
+		t.Fatalf("expected no audit entries to name the borrower, got %+v", entries)
+	}
+	entries, err = store.AuditLogs().ListForEntities("borrowing", []uuid.UUID{old.ID})
+	expectNoError(t, err)
+	for _, entry := range entries {
+		if entry.Action == audit.ActionUpdate && strings.Contains(string(entry.Changes), "renewal_count") && entry.Actor != reqctx.AnonymousBorrowerActor {
+			t.Fatalf("renewal recorded for %q, want %q", entry.Actor, reqctx.AnonymousBorrowerActor)
+		}
+	}
+	// The anonymization is audited itself, without the ID it removed
+	if last := entries[len(entries)-1]; !strings.Contains(string(last.Changes), "anonymized_at") ||
+		strings.Contains(string(last.Changes), "borrower_id") {
+		t.Fatalf("unexpected last audit entry %s", last.Changes)
+	}
+
+	got, err := store.Borrowings().Get(old.ID)
+	expectNoError(t, err)
+	if got.BorrowerID != nil || got.Borrower != nil || got.AnonymizedAt == nil || got.Book.ID != old.BookID {
+		t.Fatalf("expected the loan to be detached from its borrower only, got %+v", got)
+	}
+	fine, err := store.Fines().Get(settled.ID)
+	expectNoError(t, err)
+	if fine.BorrowingID != nil || fine.BorrowerID != borrower.ID {
+		t.Fatalf("expected the settled fine to be detached from the loan, got %+v", fine)
+	}
+
+	for _, b := range []*models.Borrowing{recent, active, kept, owing} {
+		got, err := store.Borrowings().Get(b.ID)
+		expectNoError(t, err)
+		if got.BorrowerID == nil {
+			t.Fatalf("loan %v should not have been anonymized", b.ID)
+		}
+	}
+
+	// The statistics are intact
+	total, err := store.Borrowings().CountByBorrower(borrower.ID)
+	expectNoError(t, err)
+	expectCount(t, "borrower's loans", total, 3)
+	total, err = store.Borrowings().CountByBook(old.BookID)
+	expectNoError(t, err)
+	expectCount(t, "book's loans", total, 1)
+
+	count, err = store.Borrowings().AnonymizeReturnedBefore(now.Add(-30*24*time.Hour), now)
+	expectNoError(t, err)
+	expectCount(t, "anonymized loans on the second run", count, 0)
+
+	// Anonymizing a borrower's history ignores age and preference but
+	// leaves loans still out
+	count, err = store.Borrowings().AnonymizeByBorrower(borrower.ID, now)
+	expectNoError(t, err)
+	expectCount(t, "anonymized loans of the borrower", count, 2)
+	got, err = store.Borrowings().Get(active.ID)
+	expectNoError(t, err)
+	if got.BorrowerID == nil {
+		t.Fatal("expected the active loan to keep its borrower")
+	}
+}
+
+func testHoldAnonymize(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Jane Austen")
+	book := createBook(t, store, author, "Emma", "9780141439587")
+	borrower := createBorrower(t, store, "Reader", "reader@example.com")
+	keeper := createBorrower(t, store, "Keeper", "keeper@example.com")
+	keeper.KeepHistory = true
+	expectNoError(t, store.Borrowers().Update(keeper))
+	self := store.WithContext(reqctx.WithActor(context.Background(), reqctx.BorrowerActor(borrower.ID)))
+
+	now := time.Now()
+	hold := func(borrower *models.Borrower, status string, closedAgo time.Duration) *models.Hold {
+		hold := &models.Hold{BookID: book.ID, BorrowerID: &borrower.ID, Status: models.HoldWaiting, PlacedAt: now.Add(-closedAgo)}
+		expectNoError(t, self.Holds().Create(hold))
+		closedAt := now.Add(-closedAgo)
+		switch status {
+		case models.HoldFulfilled:
+			hold.FulfilledAt = &closedAt
+		case models.HoldCancelled:
+			hold.CancelledAt = &closedAt
+		}
+		hold.Status = status
+		expectNoError(t, store.Holds().Update(hold))
+		return hold
+	}
+	fulfilled := hold(borrower, models.HoldFulfilled, 100*24*time.Hour)
+	cancelled := hold(borrower, models.HoldCancelled, 100*24*time.Hour)
+	recent := hold(borrower, models.HoldFulfilled, 24*time.Hour)
+	waiting := hold(borrower, models.HoldWaiting, 100*24*time.Hour)
+	kept := hold(keeper, models.HoldCancelled, 100*24*time.Hour)
+
+	count, err := store.Holds().AnonymizeClosedBefore(now.Add(-30*24*time.Hour), now)
+	expectNoError(t, err)
+	expectCount(t, "anonymized holds", count, 2)
+
+	for _, h := range []*models.Hold{fulfilled, cancelled} {
+		got, err := store.Holds().Get(h.ID)
+		expectNoError(t, err)
+		if got.BorrowerID != nil || got.AnonymizedAt == nil || got.BookID != book.ID {
+			t.Fatalf("expected the hold to be detached from its borrower only, got %+v", got)
+		}
+	}
+	for _, h := range []*models.Hold{recent, waiting, kept} {
+		got, err := store.Holds().Get(h.ID)
+		expectNoError(t, err)
+		if got.BorrowerID == nil {
+			t.Fatalf("hold %v should not have been anonymized", h.ID)
+		}
+	}
+
+	// Nor does the audit trail still name the borrower
+	entries, err := store.AuditLogs().ListForEntities("hold", []uuid.UUID{fulfilled.ID})
+	expectNoError(t, err)
+	for _, entry := range entries {
+		if entry.Actor == reqctx.BorrowerActor(borrower.ID) || strings.Contains(string(entry.Changes), borrower.ID.String()) {
+			t.Fatalf("audit entry still names the borrower: %+v", entry)
+		}
+	}
+
+	// Anonymizing a borrower's holds ignores age but leaves waiting ones
+	count, err = store.Holds().AnonymizeByBorrower(borrower.ID, now)
+	expectNoError(t, err)
+	expectCount(t, "anonymized holds of the borrower", count, 1)
+	got, err := store.Holds().Get(waiting.ID)
+	expectNoError(t, err)
+	if got.BorrowerID == nil {
+		t.Fatal("expected the waiting hold to keep its borrower")
+	}
+}
+
+func testAuditLogs(t *testing.T, store repository.Store) {
+	desk := store.WithContext(reqctx.WithActor(context.Background(), "desk"))
+	borrower := &models.Borrower{Name: "Ada", Email: "ada@example.com", Phone: "555-0100"}
+	expectNoError(t, desk.Borrowers().Create(borrower))
+	borrower.Phone = "555-0199"
+	expectNoError(t, store.Borrowers().Update(borrower))
+	other := createBorrower(t, store, "Other", "other@example.com")
+
+	entries, err := store.AuditLogs().ListForEntities("borrower", []uuid.UUID{borrower.ID})
+	expectNoError(t, err)
+	if len(entries) != 2 || entries[0].Action != "create" || entries[1].Action != "update" {
+		t.Fatalf("expected the create and update entries oldest first, got %+v", entries)
+	}
+	none, err := store.AuditLogs().ListForEntities("borrower", nil)
+	expectNoError(t, err)
+	expectCount(t, "entries without IDs", int64(len(none)), 0)
+
+	byDesk, err := store.AuditLogs().ListByActor("desk")
+	expectNoError(t, err)
+	if len(byDesk) != 1 || byDesk[0].EntityID != borrower.ID.String() {
+		t.Fatalf("expected the entry made by desk, got %+v", byDesk)
+	}
+
+	expectNoError(t, store.AuditLogs().ScrubFields("borrower", []uuid.UUID{borrower.ID}, "email", "phone"))
+	entries, err = store.AuditLogs().ListForEntities("borrower", []uuid.UUID{borrower.ID, other.ID})
+	expectNoError(t, err)
+	expectCount(t, "entries", int64(len(entries)), 3)
+	for _, entry := range entries {
+		var changes map[string]interface{}
+		if err := json.Unmarshal(entry.Changes, &changes); err != nil {
+			t.Fatal(err)
+		}
+		_, hasEmail := changes["email"]
+		_, hasPhone := changes["phone"]
+		scrubbed := entry.EntityID == borrower.ID.String()
+		if scrubbed == (hasEmail || hasPhone) {
+			t.Fatalf("unexpected changes for %s: %s", entry.EntityID, entry.Changes)
+		}
+		if entry.Action == "create" && changes["name"] == nil {
+			t.Fatalf("expected other fields to be kept: %s", entry.Changes)
+		}
+	}
+}
+
+func testBorrowingQueries(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Mary Shelley")
+	frankenstein := createBook(t, store, author, "Frankenstein", "9780141439471")
+	lastMan := createBook(t, store, author, "The Last Man", "9780199552351")
+	borrower := createBorrower(t, store, "Percy Shelley", "percy@example.com")
+	other := createBorrower(t, store, "Lord Byron", "byron@example.com")
+
+	now := time.Now()
+	current := createBorrowing(t, store, frankenstein, borrower, now.Add(14*24*time.Hour))
+	overdue := createBorrowing(t, store, lastMan, borrower, now.Add(-24*time.Hour))
+
+	got, err := store.Borrowings().Get(current.ID)
+	expectNoError(t, err)
+	if got.Book.Contributors[0].Author.Name != "Mary Shelley" || got.Borrower.Name != "Percy Shelley" {
+		t.Fatalf("expected relations to be loaded, got %+v", got)
+	}
+	_, err = store.Borrowings().Get(uuid.New())
+	expectNotFound(t, err)
+
+	count, err := store.Borrowings().CountActiveByBorrower(borrower.ID)
+	expectNoError(t, err)
+	expectCount(t, "active", count, 2)
+
+	count, err = store.Borrowings().CountOverdueByBorrower(borrower.ID, now)
+	expectNoError(t, err)
+	expectCount(t, "overdue", count, 1)
+
+	count, err = store.Borrowings().CountActiveByBorrower(other.ID)
+	expectNoError(t, err)
+	expectCount(t, "other borrower", count, 0)
+
+	active, err := store.Borrowings().HasActiveForBook(frankenstein.ID)
+	expectNoError(t, err)
+	if !active {
+		t.Fatal("expected an active borrowing for the book")
+	}
+
+	listed, total, err := store.Borrowings().Find(models.BorrowingFilter{OverdueOnly: true}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "listed overdue", total, 1)
+	if listed[0].ID != overdue.ID {
+		t.Fatalf("listed %v, want %v", listed[0].ID, overdue.ID)
+	}
+
+	_, total, err = store.Borrowings().Find(models.BorrowingFilter{BorrowerID: borrower.ID}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "by borrower", total, 2)
+
+	_, total, err = store.Borrowings().Find(models.BorrowingFilter{}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "all", total, 2)
+
+	// Returning closes the loan but keeps the history
+	returnedAt := time.Now()
+	got.Status = "returned"
+	got.ReturnedAt = &returnedAt
+	expectNoError(t, store.Borrowings().Update(got))
+
+	active, err = store.Borrowings().HasActiveForBook(frankenstein.ID)
+	expectNoError(t, err)
+	if active {
+		t.Fatal("expected no active borrowing after return")
+	}
+
+	_, total, err = store.Borrowings().Find(models.BorrowingFilter{BorrowerID: borrower.ID, ActiveOnly: true}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "active by borrower", total, 1)
+
+	_, total, err = store.Borrowings().Find(models.BorrowingFilter{BookID: frankenstein.ID}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "by book", total, 1)
+
+	count, err = store.Borrowings().CountByBook(frankenstein.ID)
+	expectNoError(t, err)
+	expectCount(t, "history by book", count, 1)
+
+	count, err = store.Borrowings().CountByBorrower(borrower.ID)
+	expectNoError(t, err)
+	expectCount(t, "history by borrower", count, 2)
+}
+
+func testBorrowingMarkOverdue(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "H. G. Wells")
+	book := createBook(t, store, author, "The Time Machine", "9780141439976")
+	onTime := createBook(t, store, author, "The War of the Worlds", "9780141441030")
+	borrower := createBorrower(t, store, "Weena", "weena@example.com")
+
+	now := time.Now()
+	late := createBorrowing(t, store, book, borrower, now.Add(-time.Hour))
+	current := createBorrowing(t, store, onTime, borrower, now.Add(time.Hour))
+
+	expectNoError(t, store.Borrowings().MarkOverdue(now))
+
+	got, err := store.Borrowings().Get(late.ID)
+	expectNoError(t, err)
+	if got.Status != "overdue" {
+		t.Fatalf("late borrowing status = %q, want overdue", got.Status)
+	}
+
+	got, err = store.Borrowings().Get(current.ID)
+	expectNoError(t, err)
+	if got.Status != "borrowed" {
+		t.Fatalf("current borrowing status = %q, want borrowed", got.Status)
+	}
+}
+
+func testBorrowingExport(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Jules Verne")
+	first := createBook(t, store, author, "Twenty Thousand Leagues Under the Seas", "9780199539277")
+	second := createBook(t, store, author, "Around the World in Eighty Days", "9780199552528")
+	third := createBook(t, store, author, "Journey to the Centre of the Earth", "9780199538072")
+	nemo := createBorrower(t, store, "Captain Nemo", "nemo@example.com")
+	fogg := createBorrower(t, store, "Phileas Fogg", "fogg@example.com")
+
+	now := time.Now()
+	createBorrowing(t, store, first, nemo, now.Add(-time.Hour))
+	createBorrowing(t, store, second, fogg, now.Add(80*24*time.Hour))
+	createBorrowing(t, store, third, fogg, now.Add(-time.Hour))
+
+	cases := []struct {
+		name   string
+		filter models.BorrowingFilter
+		want   int
+	}{
+		{"all", models.BorrowingFilter{}, 3},
+		{"by borrower", models.BorrowingFilter{BorrowerID: fogg.ID}, 2},
+		{"overdue", models.BorrowingFilter{OverdueOnly: true}, 2},
+		{"overdue by borrower", models.BorrowingFilter{BorrowerID: fogg.ID, OverdueOnly: true}, 1},
+	}
+	for _, tc := range cases {
+		var exported int
+		expectNoError(t, store.Borrowings().Export(tc.filter, 2, func(batch []models.Borrowing) error {
+			for _, b := range batch {
+				if b.Borrower.ID == uuid.Nil || b.Book.Contributors[0].Author.ID == uuid.Nil {
+					return fmt.Errorf("relations not loaded for %v", b.ID)
+				}
+			}
+			exported += len(batch)
+			return nil
+		}))
+		if exported != tc.want {
+			t.Fatalf("%s: exported %d, want %d", tc.name, exported, tc.want)
+		}
+	}
+}
+
+func testBorrowingBranchFilter(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Arthur Conan Doyle")
+	first := createBook(t, store, author, "A Study in Scarlet", "9780140439083")
+	second := createBook(t, store, author, "The Sign of Four", "9780140439076")
+	holmes := createBorrower(t, store, "Sherlock Holmes", "holmes@example.com")
+	baker := createBranch(t, store, "BKR", "Baker Street")
+	other := createBranch(t, store, "WHT", "Whitehall")
+
+	now := time.Now()
+	borrowing := createBorrowing(t, store, first, holmes, now.Add(-time.Hour))
+	borrowing.BranchID = &baker.ID
+	expectNoError(t, store.Borrowings().Update(borrowing))
+	createBorrowing(t, store, second, holmes, now.Add(time.Hour))
+
+	cases := []struct {
+		name   string
+		filter models.BorrowingFilter
+		want   int64
+	}{
+		{"branch", models.BorrowingFilter{BranchID: baker.ID}, 1},
+		{"overdue at branch", models.BorrowingFilter{BranchID: baker.ID, OverdueOnly: true}, 1},
+		{"other branch", models.BorrowingFilter{BranchID: other.ID}, 0},
+	}
+	for _, tc := range cases {
+		_, total, err := store.Borrowings().Find(tc.filter, 0, 10)
+		expectNoError(t, err)
+		expectCount(t, tc.name, total, tc.want)
+	}
+}
+
+func testBranchCRUD(t *testing.T, store repository.Store) {
+	branch := createBranch(t, store, "CEN", "Central")
+	if branch.ID == uuid.Nil {
+		t.Fatal("expected Create to assign an ID")
+	}
+	createBranch(t, store, "EST", "East")
+
+	got, err := store.Branches().Get(branch.ID)
+	expectNoError(t, err)
+	if got.Name != "Central" {
+		t.Fatalf("got name %q, want Central", got.Name)
+	}
+
+	found, err := store.Branches().FindByCode("CEN", uuid.Nil)
+	expectNoError(t, err)
+	if found.ID != branch.ID {
+		t.Fatalf("FindByCode returned %v, want %v", found.ID, branch.ID)
+	}
+	_, err = store.Branches().FindByCode("CEN", branch.ID)
+	expectNotFound(t, err)
+
+	branches, total, err := store.Branches().List(0, 10)
+	expectNoError(t, err)
+	expectCount(t, "branches", total, 2)
+	if branches[0].Code != "CEN" {
+		t.Fatalf("expected branches ordered by code, got %q first", branches[0].Code)
+	}
+
+	got.Address = "1 Library Way"
+	expectNoError(t, store.Branches().Update(got))
+	got, err = store.Branches().Get(branch.ID)
+	expectNoError(t, err)
+	if got.Address != "1 Library Way" {
+		t.Fatalf("update not persisted, address %q", got.Address)
+	}
+
+	expectNoError(t, store.Branches().Delete(got))
+	_, err = store.Branches().Get(branch.ID)
+	expectNotFound(t, err)
+
+	// A deleted branch's code can be reused
+	createBranch(t, store, "CEN", "New Central")
+}
+
+func testLocationCRUD(t *testing.T, store repository.Store) {
+	central := createBranch(t, store, "CEN", "Central")
+	east := createBranch(t, store, "EST", "East")
+	fiction := createLocation(t, store, central, "FIC")
+	createLocation(t, store, central, "REF")
+	createLocation(t, store, east, "FIC")
+
+	got, err := store.Locations().Get(fiction.ID)
+	expectNoError(t, err)
+	if got.Branch == nil || got.Branch.Code != "CEN" {
+		t.Fatalf("expected branch to be loaded, got %+v", got.Branch)
+	}
+
+	_, err = store.Locations().FindByCode(central.ID, "FIC", uuid.Nil)
+	expectNoError(t, err)
+	_, err = store.Locations().FindByCode(central.ID, "FIC", fiction.ID)
+	expectNotFound(t, err)
+
+	locations, err := store.Locations().ListByBranch(central.ID)
+	expectNoError(t, err)
+	if len(locations) != 2 || locations[0].Code != "FIC" {
+		t.Fatalf("unexpected locations %+v", locations)
+	}
+
+	count, err := store.Locations().CountByBranch(east.ID)
+	expectNoError(t, err)
+	expectCount(t, "east locations", count, 1)
+
+	got.Name = "Fiction A-Z"
+	expectNoError(t, store.Locations().Update(got))
+	expectNoError(t, store.Locations().Delete(got))
+	_, err = store.Locations().Get(fiction.ID)
+	expectNotFound(t, err)
+
+	count, err = store.Locations().CountByBranch(central.ID)
+	expectNoError(t, err)
+	expectCount(t, "central locations", count, 1)
+}
+
+func testBookBranchFilter(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Italo Calvino")
+	central := createBranch(t, store, "CEN", "Central")
+	east := createBranch(t, store, "EST", "East")
+	centralShelf := createLocation(t, store, central, "FIC")
+	eastShelf := createLocation(t, store, east, "FIC")
+
+	// Home and current location differ for the second book
+	home := createBook(t, store, author, "Invisible Cities", "9780156453806")
+	home.HomeLocationID = &centralShelf.ID
+	home.CurrentLocationID = &centralShelf.ID
+	expectNoError(t, store.Books().Update(home))
+	away := createBook(t, store, author, "Cosmicomics", "9780156226004")
+	away.HomeLocationID = &centralShelf.ID
+	away.CurrentLocationID = &eastShelf.ID
+	expectNoError(t, store.Books().Update(away))
+	createBook(t, store, author, "The Baron in the Trees", "9780156106801")
+
+	got, err := store.Books().Get(away.ID)
+	expectNoError(t, err)
+	if got.HomeLocation == nil || got.HomeLocation.Branch == nil || got.HomeLocation.Branch.Code != "CEN" {
+		t.Fatalf("expected home location and branch to be loaded, got %+v", got.HomeLocation)
+	}
+	if got.CurrentLocation == nil || got.CurrentLocation.Branch.Code != "EST" {
+		t.Fatalf("expected current location and branch to be loaded, got %+v", got.CurrentLocation)
+	}
+
+	cases := []struct {
+		name   string
+		filter models.BookFilter
+		want   int64
+	}{
+		{"all", models.BookFilter{}, 3},
+		{"at central", models.BookFilter{BranchID: central.ID}, 1},
+		{"at east", models.BookFilter{BranchID: east.ID}, 1},
+		{"home central", models.BookFilter{HomeBranchID: central.ID}, 2},
+		{"home east", models.BookFilter{HomeBranchID: east.ID}, 0},
+		{"search at east", models.BookFilter{Search: "cosmi", BranchID: east.ID}, 1},
+	}
+	for _, tc := range cases {
+		_, total, err := store.Books().Find(tc.filter, 0, 10)
+		expectNoError(t, err)
+		expectCount(t, tc.name, total, tc.want)
+	}
+
+	count, err := store.Books().CountByLocation(centralShelf.ID)
+	expectNoError(t, err)
+	expectCount(t, "books at central shelf", count, 2)
+	count, err = store.Books().CountByLocation(eastShelf.ID)
+	expectNoError(t, err)
+	expectCount(t, "books at east shelf", count, 1)
+}
+
+func createSubject(t *testing.T, store repository.Store, parent *models.Subject, name string) *models.Subject {
+	t.Helper()
+	subject := &models.Subject{Name: name, Kind: models.SubjectTopic}
+	if parent != nil {
+		subject.ParentID = &parent.ID
+	}
+	if err := store.Subjects().Create(subject); err != nil {
+		t.Fatalf("create subject: %v", err)
+	}
+	return subject
+}
+
+func testSubjects(t *testing.T, store repository.Store) {
+	science := createSubject(t, store, nil, "Science")
+	physics := createSubject(t, store, science, "Physics")
+	optics := createSubject(t, store, physics, "Optics")
+	biology := createSubject(t, store, science, "Biology")
+	history := createSubject(t, store, nil, "History")
+
+	got, err := store.Subjects().Get(science.ID)
+	expectNoError(t, err)
+	if len(got.Children) != 2 || got.Children[0].Name != "Biology" || got.Children[1].Name != "Physics" {
+		t.Fatalf("expected children in name order, got %+v", got.Children)
+	}
+	got, err = store.Subjects().Get(optics.ID)
+	expectNoError(t, err)
+	if got.Parent == nil || got.Parent.ID != physics.ID {
+		t.Fatalf("expected parent to be loaded, got %+v", got.Parent)
+	}
+
+	// Names are matched case-insensitively within a parent
+	found, err := store.Subjects().FindByName(&science.ID, "PHYSICS", uuid.Nil)
+	expectNoError(t, err)
+	if found.ID != physics.ID {
+		t.Fatalf("found %s, want physics", found.Name)
+	}
+	_, err = store.Subjects().FindByName(nil, "physics", uuid.Nil)
+	expectNotFound(t, err)
+	_, err = store.Subjects().FindByName(&science.ID, "physics", physics.ID)
+	expectNotFound(t, err)
+
+	descendants, err := store.Subjects().Descendants(science.ID)
+	expectNoError(t, err)
+	expectCount(t, "descendants", int64(len(descendants)), 3)
+	descendants, err = store.Subjects().Descendants(history.ID)
+	expectNoError(t, err)
+	expectCount(t, "leaf descendants", int64(len(descendants)), 0)
+
+	cases := []struct {
+		name   string
+		filter models.SubjectFilter
+		want   int64
+	}{
+		{"all", models.SubjectFilter{}, 5},
+		{"top level", models.SubjectFilter{TopLevel: true}, 2},
+		{"children", models.SubjectFilter{ParentID: science.ID}, 2},
+		{"search", models.SubjectFilter{Search: "o"}, 3},
+	}
+	for _, tc := range cases {
+		_, total, err := store.Subjects().Find(tc.filter, 0, 10)
+		expectNoError(t, err)
+		expectCount(t, tc.name, total, tc.want)
+	}
+
+	count, err := store.Subjects().CountChildren(physics.ID)
+	expectNoError(t, err)
+	expectCount(t, "children of physics", count, 1)
+
+	// A deleted subject drops out of the tree
+	expectNoError(t, store.Subjects().Delete(optics))
+	descendants, err = store.Subjects().Descendants(science.ID)
+	expectNoError(t, err)
+	expectCount(t, "descendants after delete", int64(len(descendants)), 2)
+
+	biology.Name = "Life sciences"
+	expectNoError(t, store.Subjects().Update(biology))
+	got, err = store.Subjects().Get(biology.ID)
+	expectNoError(t, err)
+	if got.Name != "Life sciences" {
+		t.Fatalf("expected rename, got %q", got.Name)
+	}
+}
+
+func testBookSubjectFilter(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Carl Sagan")
+	science := createSubject(t, store, nil, "Science")
+	astronomy := createSubject(t, store, science, "Astronomy")
+	planets := createSubject(t, store, astronomy, "Planets")
+	fiction := createSubject(t, store, nil, "Fiction")
+
+	cosmos := createBook(t, store, author, "Cosmos", "9780345539434")
+	dot := createBook(t, store, author, "Pale Blue Dot", "9780345376596")
+	contact := createBook(t, store, author, "Contact", "9781501197987")
+
+	expectNoError(t, store.Books().SetSubjects(cosmos.ID, []uuid.UUID{astronomy.ID, science.ID}))
+	expectNoError(t, store.Books().AddSubject(dot.ID, planets.ID))
+	expectNoError(t, store.Books().AddSubject(contact.ID, fiction.ID))
+	expectNoError(t, store.Books().AddSubject(contact.ID, astronomy.ID))
+	// Tagging twice is harmless
+	expectNoError(t, store.Books().AddSubject(contact.ID, fiction.ID))
+
+	got, err := store.Books().Get(cosmos.ID)
+	expectNoError(t, err)
+	if len(got.Subjects) != 2 || got.Subjects[0].Name != "Astronomy" || got.Subjects[1].Name != "Science" {
+		t.Fatalf("expected subjects in name order, got %+v", got.Subjects)
+	}
+
+	cases := []struct {
+		name   string
+		filter models.BookFilter
+		want   int64
+	}{
+		{"broad subject includes narrower", models.BookFilter{SubjectID: science.ID}, 3},
+		{"middle of the tree", models.BookFilter{SubjectID: astronomy.ID}, 3},
+		{"leaf", models.BookFilter{SubjectID: planets.ID}, 1},
+		{"other tree", models.BookFilter{SubjectID: fiction.ID}, 1},
+		{"with search", models.BookFilter{SubjectID: science.ID, Search: "contact"}, 1},
+	}
+	for _, tc := range cases {
+		_, total, err := store.Books().Find(tc.filter, 0, 10)
+		expectNoError(t, err)
+		expectCount(t, tc.name, total, tc.want)
+	}
+
+	count, err := store.Subjects().CountBooks(astronomy.ID)
+	expectNoError(t, err)
+	expectCount(t, "books tagged astronomy", count, 2)
+
+	// Replacing and clearing the subjects
+	expectNoError(t, store.Books().SetSubjects(cosmos.ID, []uuid.UUID{planets.ID}))
+	_, total, err := store.Books().Find(models.BookFilter{SubjectID: planets.ID}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "planets after replace", total, 2)
+	expectNoError(t, store.Books().SetSubjects(cosmos.ID, nil))
+	got, err = store.Books().Get(cosmos.ID)
+	expectNoError(t, err)
+	if len(got.Subjects) != 0 {
+		t.Fatalf("expected subjects to be cleared, got %+v", got.Subjects)
+	}
+
+	// Purging a book removes its tags
+	expectNoError(t, store.Books().Delete(dot))
+	trashed, err := store.Books().GetDeleted(dot.ID)
+	expectNoError(t, err)
+	if len(trashed.Subjects) != 1 {
+		t.Fatalf("expected subjects on deleted book, got %+v", trashed.Subjects)
+	}
+	expectNoError(t, store.Books().Purge(trashed))
+	count, err = store.Subjects().CountBooks(planets.ID)
+	expectNoError(t, err)
+	expectCount(t, "books tagged planets after purge", count, 0)
+}
+
+func testPublications(t *testing.T, store repository.Store) {
+	penguin := &models.Publisher{Name: "Penguin Books", Place: "London"}
+	expectNoError(t, store.Publishers().Create(penguin))
+	expectNoError(t, store.Publishers().Create(&models.Publisher{Name: "Faber & Faber"}))
+
+	found, err := store.Publishers().FindByName("PENGUIN books", uuid.Nil)
+	expectNoError(t, err)
+	if found.ID != penguin.ID {
+		t.Fatalf("found %s, want Penguin", found.Name)
+	}
+	_, err = store.Publishers().FindByName("Penguin Books", penguin.ID)
+	expectNotFound(t, err)
+
+	publishers, total, err := store.Publishers().Find("", 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "publishers", total, 2)
+	if publishers[0].Name != "Faber & Faber" {
+		t.Fatalf("expected publishers in name order, got %s first", publishers[0].Name)
+	}
+	_, total, err = store.Publishers().Find("pengu", 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "publisher search", total, 1)
+
+	discworld := &models.Series{Name: "Discworld"}
+	expectNoError(t, store.Series().Create(discworld))
+	foundSeries, err := store.Series().FindByName("discworld", uuid.Nil)
+	expectNoError(t, err)
+	if foundSeries.ID != discworld.ID {
+		t.Fatalf("found %s, want Discworld", foundSeries.Name)
+	}
+
+	// A work's editions are loaded oldest first
+	work := &models.Work{Title: "The Colour of Magic"}
+	expectNoError(t, store.Works().Create(work))
+	author := createAuthor(t, store, "Terry Pratchett")
+	for i, isbn := range []string{"9780552166591", "9780861401205"} {
+		book := createBook(t, store, author, "The Colour of Magic", isbn)
+		book.WorkID = &work.ID
+		book.PublisherID = &penguin.ID
+		book.SeriesID = &discworld.ID
+		book.SeriesVolume = 1
+		book.PublishedAt = time.Date(2012-i*29, 1, 1, 0, 0, 0, 0, time.UTC)
+		expectNoError(t, store.Books().Update(book))
+	}
+	got, err := store.Works().Get(work.ID)
+	expectNoError(t, err)
+	if len(got.Editions) != 2 || got.Editions[0].ISBN != "9780861401205" {
+		t.Fatalf("expected editions oldest first, got %+v", got.Editions)
+	}
+	if got.Editions[0].Publisher == nil || len(got.Editions[0].Contributors) != 1 {
+		t.Fatalf("expected edition publisher and contributors to be loaded, got %+v", got.Editions[0])
+	}
+
+	count, err := store.Publishers().CountBooks(penguin.ID)
+	expectNoError(t, err)
+	expectCount(t, "books by publisher", count, 2)
+	count, err = store.Series().CountBooks(discworld.ID)
+	expectNoError(t, err)
+	expectCount(t, "books in series", count, 2)
+	count, err = store.Works().CountBooks(work.ID)
+	expectNoError(t, err)
+	expectCount(t, "editions", count, 2)
+
+	work.Title = "Colour of Magic"
+	expectNoError(t, store.Works().Update(work))
+	expectNoError(t, store.Works().Delete(work))
+	_, err = store.Works().Get(work.ID)
+	expectNotFound(t, err)
+}
+
+func testBookEditionFilter(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Philip Pullman")
+	scholastic := &models.Publisher{Name: "Scholastic"}
+	expectNoError(t, store.Publishers().Create(scholastic))
+	trilogy := &models.Series{Name: "His Dark Materials"}
+	expectNoError(t, store.Series().Create(trilogy))
+	work := &models.Work{Title: "Northern Lights"}
+	expectNoError(t, store.Works().Create(work))
+
+	// Created out of volume order
+	volumes := []struct {
+		title, isbn, format, language string
+		volume                        int
+	}{
+		{"The Amber Spyglass", "9780440418566", models.FormatPaperback, "en", 3},
+		{"Northern Lights", "9780590660549", models.FormatHardcover, "en-GB", 1},
+		{"The Subtle Knife", "9780679879251", models.FormatPaperback, "en", 2},
+	}
+	var northern *models.Book
+	for _, v := range volumes {
+		book := createBook(t, store, author, v.title, v.isbn)
+		book.PublisherID = &scholastic.ID
+		book.SeriesID = &trilogy.ID
+		book.SeriesVolume = v.volume
+		book.Format = v.format
+		book.Language = v.language
+		expectNoError(t, store.Books().Update(book))
+		if v.volume == 1 {
+			northern = book
+		}
+	}
+	translation := createBook(t, store, author, "Les Royaumes du Nord", "9782070518371")
+	translation.WorkID = &work.ID
+	translation.Language = "fr"
+	translation.Format = models.FormatPaperback
+	expectNoError(t, store.Books().Update(translation))
+	northern.WorkID = &work.ID
+	expectNoError(t, store.Books().Update(northern))
+
+	got, err := store.Books().Get(northern.ID)
+	expectNoError(t, err)
+	if got.Publisher == nil || got.Series == nil || got.Work == nil || got.Work.Title != "Northern Lights" {
+		t.Fatalf("expected edition metadata to be loaded, got %+v", got)
+	}
+
+	books, total, err := store.Books().Find(models.BookFilter{SeriesID: trilogy.ID}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "series", total, 3)
+	for i, book := range books {
+		if book.SeriesVolume != i+1 {
+			t.Fatalf("expected series in volume order, got volume %d at %d", book.SeriesVolume, i)
+		}
+	}
+
+	cases := []struct {
+		name   string
+		filter models.BookFilter
+		want   int64
+	}{
+		{"publisher", models.BookFilter{PublisherID: scholastic.ID}, 3},
+		{"work", models.BookFilter{WorkID: work.ID}, 2},
+		{"format", models.BookFilter{Format: models.FormatPaperback}, 3},
+		{"language", models.BookFilter{Language: "EN"}, 2},
+		{"format and language", models.BookFilter{Format: models.FormatPaperback, Language: "fr"}, 1},
+		{"series with search", models.BookFilter{SeriesID: trilogy.ID, Search: "knife"}, 1},
+	}
+	for _, tc := range cases {
+		_, total, err := store.Books().Find(tc.filter, 0, 10)
+		expectNoError(t, err)
+		expectCount(t, tc.name, total, tc.want)
+	}
+}
+
+func testBookShelfOrder(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Donald Knuth")
+	// Created out of shelf order; the unclassified book lists last
+	shelved := []struct {
+		title, isbn, callNumber string
+	}{
+		{"Concrete Mathematics", "9780201558029", "QA39.2 .G733 1994"},
+		{"Unclassified", "9780000000019", ""},
+		{"Literate Programming", "9780937073803", "QA76.6 .K644 1992"},
+		{"The TeXbook", "9780201134476", "Z253.4.T47 K58 1984"},
+		{"Selected Papers", "9781575865829", "QA76.9 .K58 2003"},
+		{"Fundamental Algorithms", "9780201896831", "QA76.6 .K64 1997 v.1"},
+		{"Surreal Numbers", "9780201038125", "512.7 KNU"},
+	}
+	for _, s := range shelved {
+		book := createBook(t, store, author, s.title, s.isbn)
+		if s.callNumber == "" {
+			continue
+		}
+		book.CallNumber = s.callNumber
+		book.ClassificationScheme = callnumber.Detect(s.callNumber)
+		key, err := callnumber.SortKey(book.ClassificationScheme, s.callNumber)
+		expectNoError(t, err)
+		book.CallNumberSort = key
+		expectNoError(t, store.Books().Update(book))
+	}
+
+	books, _, err := store.Books().Find(models.BookFilter{ShelfOrder: true}, 0, 10)
+	expectNoError(t, err)
+	want := []string{"Surreal Numbers", "Concrete Mathematics", "Fundamental Algorithms", "Literate Programming",
+		"Selected Papers", "The TeXbook", "Unclassified"}
+	var got []string
+	for _, book := range books {
+		got = append(got, book.Title)
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("shelf order = %v, want %v", got, want)
+	}
+
+	var exported []string
+	err = store.Books().Export(models.BookFilter{ShelfOrder: true}, 3, func(batch []models.Book) error {
+		for _, book := range batch {
+			exported = append(exported, book.Title)
+		}
+		return nil
+	})
+	expectNoError(t, err)
+	if strings.Join(exported, "|") != strings.Join(want, "|") {
+		t.Fatalf("exported shelf order = %v, want %v", exported, want)
+	}
+
+	cases := []struct {
+		name   string
+		filter models.BookFilter
+		want   int64
+	}{
+		{"range", models.BookFilter{CallNumberFrom: "QA76", CallNumberTo: "QA76.6 .K64"}, 1},
+		{"range through class", models.BookFilter{CallNumberFrom: "QA76", CallNumberTo: "QA76.9"}, 3},
+		{"open start", models.BookFilter{CallNumberTo: "QA50"}, 1},
+		{"open end", models.BookFilter{CallNumberFrom: "QA76.7"}, 2},
+		{"dewey range", models.BookFilter{CallNumberFrom: "500", CallNumberTo: "599"}, 1},
+		{"scheme", models.BookFilter{Classification: callnumber.LC}, 5},
+	}
+	for _, tc := range cases {
+		_, total, err := store.Books().Find(tc.filter, 0, 10)
+		expectNoError(t, err)
+		expectCount(t, tc.name, total, tc.want)
+	}
+}
+
+func testTransfers(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Jorge Luis Borges")
+	book := createBook(t, store, author, "Labyrinths", "9780811216999")
+	other := createBook(t, store, author, "Ficciones", "9780802130303")
+	central := createBranch(t, store, "CEN", "Central")
+	east := createBranch(t, store, "EST", "East")
+	shelf := createLocation(t, store, central, "FIC")
+
+	now := time.Now()
+	transfer := &models.Transfer{
+		BookID:       book.ID,
+		FromBranchID: east.ID,
+		ToBranchID:   central.ID,
+		ToLocationID: shelf.ID,
+		Status:       models.TransferInTransit,
+		SentAt:       now,
+	}
+	expectNoError(t, store.Transfers().Create(transfer))
+	expectNoError(t, store.Transfers().Create(&models.Transfer{
+		BookID:       other.ID,
+		FromBranchID: central.ID,
+		ToBranchID:   east.ID,
+		ToLocationID: shelf.ID,
+		Status:       models.TransferReceived,
+		SentAt:       now.Add(-time.Hour),
+	}))
+
+	got, err := store.Transfers().Get(transfer.ID)
+	expectNoError(t, err)
+	if got.Book.Contributors[0].Author.Name != "Jorge Luis Borges" || got.FromBranch == nil || got.ToBranch == nil {
+		t.Fatalf("expected relations to be loaded, got %+v", got)
+	}
+	_, err = store.Transfers().Get(uuid.New())
+	expectNotFound(t, err)
+
+	cases := []struct {
+		name   string
+		filter models.TransferFilter
+		want   int64
+	}{
+		{"all", models.TransferFilter{}, 2},
+		{"from east", models.TransferFilter{FromBranchID: east.ID}, 1},
+		{"to east", models.TransferFilter{ToBranchID: east.ID}, 1},
+		{"in transit", models.TransferFilter{Status: models.TransferInTransit}, 1},
+		{"in transit to east", models.TransferFilter{ToBranchID: east.ID, Status: models.TransferInTransit}, 0},
+	}
+	for _, tc := range cases {
+		_, total, err := store.Transfers().Find(tc.filter, 0, 10)
+		expectNoError(t, err)
+		expectCount(t, tc.name, total, tc.want)
+	}
+
+	receivedAt := time.Now()
+	got.Status = models.TransferReceived
+	got.ReceivedAt = &receivedAt
+	expectNoError(t, store.Transfers().Update(got))
+	_, total, err := store.Transfers().Find(models.TransferFilter{Status: models.TransferInTransit}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "in transit after receipt", total, 0)
+}
+
+func testTenants(t *testing.T, store repository.Store) {
+	north := createTenant(t, store, "north")
+	createTenant(t, store, "east")
+
+	got, err := store.Tenants().GetBySlug("north")
+	expectNoError(t, err)
+	if got.ID != north.ID || got.Settings.MaxActiveLoans != 5 {
+		t.Fatalf("unexpected tenant %+v", got)
+	}
+	_, err = store.Tenants().GetBySlug("south")
+	expectNotFound(t, err)
+
+	tenants, total, err := store.Tenants().List(0, 10)
+	expectNoError(t, err)
+	expectCount(t, "tenants", total, 2)
+	if tenants[0].Slug != "east" {
+		t.Fatalf("expected tenants ordered by slug, got %q first", tenants[0].Slug)
+	}
+
+	got.Active = false
+	got.Settings.BlockOverdueBorrowers = false
+	expectNoError(t, store.Tenants().Update(got))
+	got, err = store.Tenants().Get(north.ID)
+	expectNoError(t, err)
+	if got.Active || got.Settings.BlockOverdueBorrowers {
+		t.Fatalf("update not persisted: %+v", got)
+	}
+
+	// Only a store scoped to a tenant has a current tenant
+	_, err = store.Tenants().Current()
+	expectNotFound(t, err)
+	current, err := store.WithContext(reqctx.WithTenantID(context.Background(), north.ID)).Tenants().Current()
+	expectNoError(t, err)
+	if current.ID != north.ID {
+		t.Fatalf("Current returned %v, want %v", current.ID, north.ID)
+	}
+}
+
+func testTenantIsolation(t *testing.T, store repository.Store) {
+	northID := createTenant(t, store, "north").ID
+	eastID := createTenant(t, store, "east").ID
+	north := store.WithContext(reqctx.WithTenantID(context.Background(), northID))
+	east := store.WithContext(reqctx.WithTenantID(context.Background(), eastID))
+
+	// Both libraries may use the same ISBN, email and branch code
+	northAuthor := createAuthor(t, north, "Tove Jansson")
+	northBook := createBook(t, north, northAuthor, "Moominsummer Madness", "9780374453060")
+	northBorrower := createBorrower(t, north, "Snufkin", "snufkin@example.com")
+	createBorrowing(t, north, northBook, northBorrower, time.Now().Add(-time.Hour))
+	createBranch(t, north, "CEN", "North Central")
+	eastAuthor := createAuthor(t, east, "Tove Jansson")
+	createBook(t, east, eastAuthor, "Moominsummer Madness", "9780374453060")
+	createBorrower(t, east, "Snufkin", "snufkin@example.com")
+	createBranch(t, east, "CEN", "East Central")
+
+	if northBook.TenantID != northID {
+		t.Fatalf("book tenant = %v, want %v", northBook.TenantID, northID)
+	}
+
+	// Lookups by ID do not cross tenants
+	_, err := east.Books().Get(northBook.ID)
+	expectNotFound(t, err)
+	_, err = east.Borrowers().Get(northBorrower.ID)
+	expectNotFound(t, err)
+	_, err = east.Books().FindByISBN("9780374453060", uuid.Nil)
+	expectNoError(t, err)
+
+	// Neither do lists, searches and counts
+	_, total, err := east.Borrowers().List(0, 10)
+	expectNoError(t, err)
+	expectCount(t, "east borrowers", total, 1)
+	_, total, err = east.Books().Find(models.BookFilter{Search: "jansson"}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "east books by author", total, 1)
+	_, total, err = east.Borrowings().Find(models.BorrowingFilter{}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "east borrowings", total, 0)
+	count, err := east.Borrowings().CountOverdueByBorrower(northBorrower.ID, time.Now())
+	expectNoError(t, err)
+	expectCount(t, "north overdue seen from east", count, 0)
+
+	// Updates and deletes cannot reach another tenant's rows
+	expectNoError(t, east.Borrowings().MarkOverdue(time.Now()))
+	_, total, err = north.Borrowings().Find(models.BorrowingFilter{OverdueOnly: true}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "north overdue", total, 1)
+	loans, _, err := north.Borrowings().Find(models.BorrowingFilter{}, 0, 10)
+	expectNoError(t, err)
+	if loans[0].Status != "borrowed" {
+		t.Fatalf("east MarkOverdue changed a north loan to %q", loans[0].Status)
+	}
+	expectNoError(t, east.Borrowers().Delete(northBorrower))
+	_, err = north.Borrowers().Get(northBorrower.ID)
+	expectNoError(t, err)
+
+	// An unscoped store sees every tenant
+	_, total, err = store.Borrowers().List(0, 10)
+	expectNoError(t, err)
+	expectCount(t, "all borrowers", total, 2)
+}
+
+func testPurgeDeletedBefore(t *testing.T, store repository.Store) {
+	referenced := createAuthor(t, store, "Referenced Author")
+	orphan := createAuthor(t, store, "Orphan Author")
+	book := createBook(t, store, referenced, "Kept Book", "9780000000001")
+	borrowedBook := createBook(t, store, referenced, "Borrowed Book", "9780000000002")
+	borrower := createBorrower(t, store, "Kept Borrower", "kept@example.com")
+	unused := createBorrower(t, store, "Unused Borrower", "unused@example.com")
+	createBorrowing(t, store, borrowedBook, borrower, time.Now().Add(time.Hour))
+
+	// Blocks, fines, holds and transfers keep their borrower or book too
+	heldBook := createBook(t, store, referenced, "Held Book", "9780000000003")
+	movedBook := createBook(t, store, referenced, "Moved Book", "9780000000004")
+	blocked := createBorrower(t, store, "Blocked Borrower", "blocked@example.com")
+	fined := createBorrower(t, store, "Fined Borrower", "fined@example.com")
+	expectNoError(t, store.BorrowerBlocks().Create(&models.BorrowerBlock{BorrowerID: blocked.ID, Reason: models.BlockLostCard, CreatedBy: "librarian"}))
+	expectNoError(t, store.Fines().Create(&models.Fine{BorrowerID: fined.ID, Amount: 100, Reason: "overdue", Status: models.FinePaid}))
+	expectNoError(t, store.Holds().Create(&models.Hold{BookID: heldBook.ID, BorrowerID: &fined.ID, Status: models.HoldCancelled, PlacedAt: time.Now()}))
+	branch := createBranch(t, store, "MAIN", "Main")
+	expectNoError(t, store.Transfers().Create(&models.Transfer{
+		BookID: movedBook.ID, FromBranchID: branch.ID, ToBranchID: branch.ID,
+		ToLocationID: createLocation(t, store, branch, "A").ID, Status: models.TransferReceived, SentAt: time.Now(),
+	}))
+
+	expectNoError(t, store.Authors().Delete(referenced))
+	expectNoError(t, store.Authors().Delete(orphan))
+	expectNoError(t, store.Books().Delete(book))
+	expectNoError(t, store.Books().Delete(borrowedBook))
+	expectNoError(t, store.Borrowers().Delete(borrower))
+	expectNoError(t, store.Borrowers().Delete(unused))
+	expectNoError(t, store.Books().Delete(heldBook))
+	expectNoError(t, store.Books().Delete(movedBook))
+	expectNoError(t, store.Borrowers().Delete(blocked))
+	expectNoError(t, store.Borrowers().Delete(fined))
+
+	for _, id := range []uuid.UUID{borrowedBook.ID, heldBook.ID, movedBook.ID} {
+		referenced, err := store.Books().Referenced(id)
+		expectNoError(t, err)
+		if !referenced {
+			t.Fatalf("book %s should be referenced", id)
+		}
+	}
+	for _, id := range []uuid.UUID{borrower.ID, blocked.ID, fined.ID} {
+		referenced, err := store.Borrowers().Referenced(id)
+		expectNoError(t, err)
+		if !referenced {
+			t.Fatalf("borrower %s should be referenced", id)
+		}
+	}
+	if referenced, err := store.Borrowers().Referenced(unused.ID); err != nil || referenced {
+		t.Fatalf("unused borrower referenced: %v, %v", referenced, err)
+	}
+
+	// Nothing is old enough yet
+	count, err := store.Authors().PurgeDeletedBefore(time.Now().Add(-time.Hour))
+	expectNoError(t, err)
+	expectCount(t, "authors purged early", count, 0)
+
+	cutoff := time.Now().Add(time.Hour)
+
+	count, err = store.Borrowers().PurgeDeletedBefore(cutoff)
+	expectNoError(t, err)
+	expectCount(t, "borrowers purged", count, 1)
+
+	count, err = store.Books().PurgeDeletedBefore(cutoff)
+	expectNoError(t, err)
+	expectCount(t, "books purged", count, 1)
+
+	// The referenced author still has the borrowed book
+	count, err = store.Authors().PurgeDeletedBefore(cutoff)
+	expectNoError(t, err)
+	expectCount(t, "authors purged", count, 1)
+
+	_, err = store.Authors().GetDeleted(orphan.ID)
+	expectNotFound(t, err)
+	_, err = store.Authors().GetDeleted(referenced.ID)
+	expectNoError(t, err)
+	_, err = store.Borrowers().GetDeleted(unused.ID)
+	expectNotFound(t, err)
+	_, err = store.Borrowers().GetDeleted(borrower.ID)
+	expectNoError(t, err)
+	_, err = store.Borrowers().GetDeleted(blocked.ID)
+	expectNoError(t, err)
+	_, err = store.Books().GetDeleted(movedBook.ID)
+	expectNoError(t, err)
+}
+
+func testPagination(t *testing.T, store repository.Store) {
+	for i := 0; i < 7; i++ {
+		createAuthor(t, store, fmt.Sprintf("Author %d", i))
+	}
+
+	cases := []struct {
+		offset, limit, want int
+	}{
+		{0, 5, 5},
+		{5, 5, 2},
+		{10, 5, 0},
+	}
+	for _, tc := range cases {
+		authors, total, err := store.Authors().List(tc.offset, tc.limit)
+		expectNoError(t, err)
+		expectCount(t, "total", total, 7)
+		if len(authors) != tc.want {
+			t.Fatalf("offset %d limit %d: got %d authors, want %d", tc.offset, tc.limit, len(authors), tc.want)
+		}
+	}
+}
+
+func testTransaction(t *testing.T, store repository.Store) {
+	errRollback := errors.New("rollback")
+
+	err := store.Transaction(func(tx repository.Store) error {
+		createAuthor(t, tx, "Rolled Back")
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	expectNoError(t, store.Transaction(func(tx repository.Store) error {
+		createAuthor(t, tx, "Committed")
+		return nil
+	}))
+
+	authors, total, err := store.Authors().List(0, 10)
+	expectNoError(t, err)
+	expectCount(t, "authors", total, 1)
+	if authors[0].Name != "Committed" {
+		t.Fatalf("unexpected author %q", authors[0].Name)
+	}
+}
+
+func testSIPTerminals(t *testing.T, store repository.Store) {
+	northID := createTenant(t, store, "north").ID
+	eastID := createTenant(t, store, "east").ID
+	north := store.WithContext(reqctx.WithTenantID(context.Background(), northID))
+	east := store.WithContext(reqctx.WithTenantID(context.Background(), eastID))
+
+	kiosk := &models.SIPTerminal{Name: "Lobby kiosk", Login: "north-kiosk", PasswordHash: "hash", Active: true}
+	expectNoError(t, north.SIPTerminals().Create(kiosk))
+	gate := &models.SIPTerminal{Name: "Exit gate", Login: "east-gate", PasswordHash: "hash", Active: true}
+	expectNoError(t, east.SIPTerminals().Create(gate))
+	if kiosk.TenantID != northID {
+		t.Fatalf("terminal tenant = %v, want %v", kiosk.TenantID, northID)
+	}
+
+	// Logins are found whichever tenant the store is scoped to
+	found, err := east.SIPTerminals().FindByLogin("north-kiosk", uuid.Nil)
+	expectNoError(t, err)
+	if found.ID != kiosk.ID || found.TenantID != northID {
+		t.Fatalf("FindByLogin returned %+v", found)
+	}
+	_, err = north.SIPTerminals().FindByLogin("north-kiosk", kiosk.ID)
+	expectNotFound(t, err)
+
+	// Everything else is scoped as usual
+	_, err = east.SIPTerminals().Get(kiosk.ID)
+	expectNotFound(t, err)
+	terminals, total, err := north.SIPTerminals().List(0, 10)
+	expectNoError(t, err)
+	expectCount(t, "terminals", total, 1)
+	if terminals[0].ID != kiosk.ID {
+		t.Fatalf("listed %v, want %v", terminals[0].ID, kiosk.ID)
+	}
+
+	now := time.Now()
+	kiosk.LastLoginAt = &now
+	expectNoError(t, north.SIPTerminals().Update(kiosk))
+	got, err := north.SIPTerminals().Get(kiosk.ID)
+	expectNoError(t, err)
+	if got.LastLoginAt == nil {
+		t.Fatal("update not persisted")
+	}
+
+	expectNoError(t, north.SIPTerminals().Delete(got))
+	_, err = store.SIPTerminals().FindByLogin("north-kiosk", uuid.Nil)
+	expectNotFound(t, err)
+}
+
+func testBookAuthorFilter(t *testing.T, store repository.Store) {
+	author := createAuthor(t, store, "Ursula K. Le Guin")
+	translator := createAuthor(t, store, "Ursula K. Le Guin (translator)")
+	other := createAuthor(t, store, "Iain M. Banks")
+
+	// Added a day apart, oldest first
+	added := time.Now().Add(-72 * time.Hour)
+	add := func(title, isbn string, contributors ...models.BookContributor) *models.Book {
+		added = added.Add(24 * time.Hour)
+		book := &models.Book{Title: title, ISBN: isbn, Contributors: contributors, Available: true, CreatedAt: added}
+		expectNoError(t, store.Books().Create(book))
+		return book
+	}
+	add("A Wizard of Earthsea", "9780547773742", models.BookContributor{AuthorID: author.ID, Role: models.RoleAuthor})
+	add("Consider Phlebas", "9780316005388", models.BookContributor{AuthorID: other.ID, Role: models.RoleAuthor})
+	add("Tao Te Ching", "9781590304464", models.BookContributor{AuthorID: translator.ID, Role: models.RoleTranslator},
+		models.BookContributor{AuthorID: author.ID, Role: models.RoleEditor})
+
+	// Any credit counts, newest first
+	books, total, err := store.Books().Find(models.BookFilter{AuthorID: author.ID, NewestFirst: true}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "books by author", total, 2)
+	if books[0].Title != "Tao Te Ching" || books[1].Title != "A Wizard of Earthsea" {
+		t.Fatalf("expected newest first, got %q, %q", books[0].Title, books[1].Title)
+	}
+
+	books, _, err = store.Books().Find(models.BookFilter{NewestFirst: true}, 0, 1)
+	expectNoError(t, err)
+	if books[0].Title != "Tao Te Ching" {
+		t.Fatalf("newest book = %q", books[0].Title)
+	}
+
+	authors, _, err := store.Authors().List(0, 10)
+	expectNoError(t, err)
+	if len(authors) != 3 || authors[0].ID != other.ID || authors[1].ID != author.ID {
+		t.Fatalf("expected authors by name, got %+v", authors)
+	}
+}
+
+func testBookQuery(t *testing.T, store repository.Store) {
+	leGuin := createAuthor(t, store, "Ursula K. Le Guin")
+	banks := createAuthor(t, store, "Iain M. Banks")
+	earthsea := createBook(t, store, leGuin, "A Wizard of Earthsea", "978-0-547-77374-2")
+	createBook(t, store, leGuin, "The Left Hand of Darkness", "9780441478125")
+	createBook(t, store, banks, "Consider Phlebas", "9780316005388")
+	percent := createBook(t, store, banks, "100% Culture", "9780000000001")
+	fantasy := createSubject(t, store, nil, "Fantasy fiction")
+	expectNoError(t, store.Books().AddSubject(earthsea.ID, fantasy.ID))
+
+	term := func(field, value string) *models.BookQuery {
+		return &models.BookQuery{Field: field, Term: value}
+	}
+	exact := func(field, value string) *models.BookQuery {
+		return &models.BookQuery{Field: field, Term: value, Exact: true}
+	}
+	op := func(op string, left, right *models.BookQuery) *models.BookQuery {
+		return &models.BookQuery{Op: op, Left: left, Right: right}
+	}
+
+	tests := []struct {
+		name  string
+		query *models.BookQuery
+		want  int64
+	}{
+		{"title", term(models.SearchTitle, "EARTHSEA"), 1},
+		{"author", term(models.SearchAuthor, "le guin"), 2},
+		{"hyphenated ISBN", exact(models.SearchISBN, "9780547773742"), 1},
+		{"ISBN with hyphens", exact(models.SearchISBN, "978-0441-478125"), 1},
+		{"subject", term(models.SearchSubject, "fantasy"), 1},
+		{"any field", term(models.SearchAny, "phlebas"), 1},
+		{"exact title", exact(models.SearchTitle, "consider phlebas"), 1},
+		{"exact title is not a substring", exact(models.SearchTitle, "consider"), 0},
+		{"wildcard", exact(models.SearchTitle, "the * of darkness"), 1},
+		{"percent is literal", term(models.SearchTitle, "0%"), 1},
+		{"underscore is literal", term(models.SearchTitle, "_"), 0},
+		{"and", op(models.QueryAnd, term(models.SearchAuthor, "le guin"), term(models.SearchTitle, "darkness")), 1},
+		{"or", op(models.QueryOr, term(models.SearchTitle, "earthsea"), term(models.SearchAuthor, "banks")), 3},
+		{"not", op(models.QueryNot, term(models.SearchAuthor, "le guin"), term(models.SearchSubject, "fantasy")), 1},
+		{"nested", op(models.QueryAnd, op(models.QueryOr, term(models.SearchAuthor, "banks"), term(models.SearchAuthor, "le guin")),
+			term(models.SearchTitle, "of")), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, total, err := store.Books().Find(models.BookFilter{Query: tt.query}, 0, 10)
+			expectNoError(t, err)
+			expectCount(t, "books", total, tt.want)
+		})
+	}
+
+	// Queries combine with the rest of the filter
+	_, total, err := store.Books().Find(models.BookFilter{AuthorID: banks.ID, Query: term(models.SearchAny, "culture")}, 0, 10)
+	expectNoError(t, err)
+	expectCount(t, "books by author matching query", total, 1)
+	books, _, err := store.Books().Find(models.BookFilter{Query: exact(models.SearchISBN, "9780000000001")}, 0, 10)
+	expectNoError(t, err)
+	if len(books) != 1 || books[0].ID != percent.ID {
+		t.Fatalf("unexpected books %+v", books)
+	}
+}
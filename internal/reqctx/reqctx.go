@@ -0,0 +1,94 @@
+// Package reqctx carries per-request metadata (actor, request ID, tenant,
+// signed-in borrower, permissions)
+// through context.Context from the transport layer down to database callbacks.
+package reqctx
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIDKey
+	tenantKey
+	borrowerKey
+	blockOverrideKey
+)
+
+// AnonymousActor is recorded when a request does not identify its caller.
+const AnonymousActor = "anonymous"
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Actor returns the caller recorded in ctx, or AnonymousActor.
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+func WithTenantID(ctx context.Context, tenantID uuid.UUID) context.Context {
+	return context.WithValue(ctx, tenantKey, tenantID)
+}
+
+// TenantID returns the tenant the request is scoped to, or uuid.Nil when it
+// is not scoped to one.
+func TenantID(ctx context.Context) uuid.UUID {
+	tenantID, _ := ctx.Value(tenantKey).(uuid.UUID)
+	return tenantID
+}
+
+// WithBorrowerID records the borrower signed in to the self-service API.
+func WithBorrowerID(ctx context.Context, borrowerID uuid.UUID) context.Context {
+	return context.WithValue(ctx, borrowerKey, borrowerID)
+}
+
+// BorrowerActorPrefix starts the actor recorded for changes a borrower
+// makes through the self-service API, see BorrowerActor.
+const BorrowerActorPrefix = "borrower:"
+
+// AnonymousBorrowerActor replaces BorrowerActor on the audit entries of
+// anonymized loans and holds, so they no longer name the borrower.
+const AnonymousBorrowerActor = "borrower"
+
+// BorrowerActor is the actor recorded for changes a borrower makes
+// through the self-service API.
+func BorrowerActor(borrowerID uuid.UUID) string {
+	return BorrowerActorPrefix + borrowerID.String()
+}
+
+// BorrowerID returns the signed-in borrower recorded in ctx, or uuid.Nil.
+func BorrowerID(ctx context.Context) uuid.UUID {
+	if id, ok := ctx.Value(borrowerKey).(uuid.UUID); ok {
+		return id
+	}
+	return uuid.Nil
+}
+
+// WithBlockOverride records that the caller presented the credential
+// allowing staff to override borrower blocks.
+func WithBlockOverride(ctx context.Context) context.Context {
+	return context.WithValue(ctx, blockOverrideKey, true)
+}
+
+// CanOverrideBlocks reports whether the caller may override borrower blocks.
+func CanOverrideBlocks(ctx context.Context) bool {
+	allowed, _ := ctx.Value(blockOverrideKey).(bool)
+	return allowed
+}
@@ -1,62 +1,278 @@
 package routes
 
 import (
+	"strings"
+
+	"library-management-go/internal/config"
 	"library-management-go/internal/handlers"
+	"library-management-go/internal/mail"
+	"library-management-go/internal/middleware"
+	"library-management-go/internal/repository/gormstore"
 	"library-management-go/internal/services"
+	"library-management-go/internal/storage"
+	"library-management-go/internal/tenant"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(router *gin.Engine, db *gorm.DB) {
+// newStorage returns the storage for cover images chosen by cfg.
+func newStorage(cfg *config.Config) storage.Storage {
+	if cfg.StorageBackend == "s3" {
+		return storage.NewS3(storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PublicURL:       cfg.S3PublicURL,
+		}, nil)
+	}
+	return storage.NewLocal(cfg.MediaDir, cfg.MediaURL)
+}
+
+// newMailSender returns the sender for emailed receipts chosen by cfg, or
+// nil if email is not configured.
+func newMailSender(cfg *config.Config) mail.Sender {
+	if cfg.SMTPHost == "" {
+		return nil
+	}
+	return mail.NewSMTP(mail.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.MailFrom,
+	})
+}
+
+func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
+	store := gormstore.New(db)
+
+	// Local media is served by the application itself, unless MediaURL
+	// points elsewhere
+	if cfg.StorageBackend != "s3" && strings.HasPrefix(cfg.MediaURL, "/") {
+		router.Static(cfg.MediaURL, cfg.MediaDir)
+	}
+
 	// Initialize services
-	bookService := services.NewBookService(db)
-	authorService := services.NewAuthorService(db)
-	borrowerService := services.NewBorrowerService(db)
-	borrowingService := services.NewBorrowingService(db)
+	bookService := services.NewBookService(store)
+	authorService := services.NewAuthorService(store)
+	subjectService := services.NewSubjectService(store)
+	publicationService := services.NewPublicationService(store)
+	borrowerService := services.NewBorrowerService(store)
+	borrowingService := services.NewBorrowingService(store)
+	branchService := services.NewBranchService(store)
+	transferService := services.NewTransferService(store)
+	idempotencyService := services.NewIdempotencyService(db, cfg.IdempotencyRetention)
+	auditService := services.NewAuditService(db)
+	trashService := services.NewTrashService(store, cfg.TrashRetention)
+	tenantService := services.NewTenantService(store)
+	holdService := services.NewHoldService(store)
+	fineService := services.NewFineService(store)
+	privacyService := services.NewPrivacyService(store, cfg.HistoryRetention)
+	coverService := services.NewCoverService(store, newStorage(cfg))
+	labelService := services.NewLabelService(store)
+	receiptService := services.NewReceiptService(store, newMailSender(cfg))
+	terminalService := services.NewTerminalService(store)
 
 	// Initialize handlers
 	bookHandler := handlers.NewBookHandler(bookService)
 	authorHandler := handlers.NewAuthorHandler(authorService)
+	subjectHandler := handlers.NewSubjectHandler(subjectService)
+	publicationHandler := handlers.NewPublicationHandler(publicationService)
 	borrowerHandler := handlers.NewBorrowerHandler(borrowerService)
 	borrowingHandler := handlers.NewBorrowingHandler(borrowingService)
+	branchHandler := handlers.NewBranchHandler(branchService)
+	transferHandler := handlers.NewTransferHandler(transferService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	trashHandler := handlers.NewTrashHandler(trashService)
+	tenantHandler := handlers.NewTenantHandler(tenantService)
+	holdHandler := handlers.NewHoldHandler(holdService)
+	fineHandler := handlers.NewFineHandler(fineService)
+	privacyHandler := handlers.NewPrivacyHandler(privacyService)
+	coverHandler := handlers.NewCoverHandler(coverService)
+	labelHandler := handlers.NewLabelHandler(labelService)
+	receiptHandler := handlers.NewReceiptHandler(receiptService)
+	terminalHandler := handlers.NewTerminalHandler(terminalService)
+	opdsHandler := handlers.NewOPDSHandler(bookService, authorService, subjectService, holdService, tenantService)
+	sruHandler := handlers.NewSRUHandler(bookService, tenantService)
+	meHandler := handlers.NewMeHandler(borrowerService, borrowingService, holdService, fineService, tenantService, cfg.JWTSecret)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
+	v1.Use(middleware.RequestContext(), middleware.BlockOverride(cfg.BlockOverrideToken))
+
+	// Health check and tenant provisioning are registered before the tenant
+	// middleware, so they are not scoped to a tenant
+	v1.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "ok", "message": "Library Management API is running"})
+	})
+
+	tenants := v1.Group("/tenants")
+	tenants.Use(middleware.AdminToken(cfg.TenantAdminToken))
+	{
+		tenants.POST("", tenantHandler.CreateTenant)
+		tenants.GET("", tenantHandler.GetAllTenants)
+		tenants.GET("/:id", tenantHandler.GetTenant)
+		tenants.PUT("/:id", tenantHandler.UpdateTenant)
+	}
+
+	if cfg.MultiTenant {
+		resolver := &tenant.Resolver{Domain: cfg.TenantDomain, Secret: cfg.JWTSecret}
+		v1.Use(middleware.Tenant(resolver, tenantService))
+	}
+	v1.Use(middleware.Idempotency(idempotencyService))
 	{
-		// Health check
-		v1.GET("/health", func(c *gin.Context) {
-			c.JSON(200, gin.H{"status": "ok", "message": "Library Management API is running"})
-		})
+		// Current tenant and its configuration
+		v1.GET("/tenant", tenantHandler.GetCurrentTenant)
 
 		// Author routes
 		authors := v1.Group("/authors")
 		{
 			authors.POST("", authorHandler.CreateAuthor)
 			authors.GET("", authorHandler.GetAllAuthors)
+			authors.GET("/export", authorHandler.ExportAuthors)
 			authors.GET("/:id", authorHandler.GetAuthor)
 			authors.PUT("/:id", authorHandler.UpdateAuthor)
 			authors.DELETE("/:id", authorHandler.DeleteAuthor)
+			authors.GET("/trash", authorHandler.GetDeletedAuthors)
+			authors.POST("/:id/restore", authorHandler.RestoreAuthor)
+			authors.DELETE("/:id/purge", authorHandler.PurgeAuthor)
 		}
 
 		// Book routes
 		books := v1.Group("/books")
 		{
 			books.POST("", bookHandler.CreateBook)
+			books.POST("/batch", bookHandler.BatchBooks)
 			books.GET("", bookHandler.GetAllBooks)
+			books.GET("/export", bookHandler.ExportBooks)
 			books.GET("/:id", bookHandler.GetBook)
 			books.PUT("/:id", bookHandler.UpdateBook)
 			books.DELETE("/:id", bookHandler.DeleteBook)
+			books.GET("/trash", bookHandler.GetDeletedBooks)
+			books.POST("/:id/restore", bookHandler.RestoreBook)
+			books.DELETE("/:id/purge", bookHandler.PurgeBook)
+			books.GET("/:id/holds", holdHandler.GetBookHolds)
+			books.POST("/:id/cover", coverHandler.UploadCover)
+			books.DELETE("/:id/cover", coverHandler.DeleteCover)
+			books.GET("/:id/barcode", labelHandler.GetItemBarcode)
+		}
+
+		// Subject routes
+		subjects := v1.Group("/subjects")
+		{
+			subjects.POST("", subjectHandler.CreateSubject)
+			subjects.GET("", subjectHandler.GetSubjects)
+			subjects.POST("/import", subjectHandler.ImportMARC)
+			subjects.GET("/:id", subjectHandler.GetSubject)
+			subjects.PUT("/:id", subjectHandler.UpdateSubject)
+			subjects.DELETE("/:id", subjectHandler.DeleteSubject)
+		}
+
+		// Publisher routes
+		publishers := v1.Group("/publishers")
+		{
+			publishers.POST("", publicationHandler.CreatePublisher)
+			publishers.GET("", publicationHandler.GetPublishers)
+			publishers.GET("/:id", publicationHandler.GetPublisher)
+			publishers.PUT("/:id", publicationHandler.UpdatePublisher)
+			publishers.DELETE("/:id", publicationHandler.DeletePublisher)
+		}
+
+		// Series routes
+		series := v1.Group("/series")
+		{
+			series.POST("", publicationHandler.CreateSeries)
+			series.GET("", publicationHandler.GetAllSeries)
+			series.GET("/:id", publicationHandler.GetSeries)
+			series.PUT("/:id", publicationHandler.UpdateSeries)
+			series.DELETE("/:id", publicationHandler.DeleteSeries)
+		}
+
+		// Work routes
+		works := v1.Group("/works")
+		{
+			works.POST("", publicationHandler.CreateWork)
+			works.GET("", publicationHandler.GetWorks)
+			works.GET("/:id", publicationHandler.GetWork)
+			works.PUT("/:id", publicationHandler.UpdateWork)
+			works.DELETE("/:id", publicationHandler.DeleteWork)
+		}
+
+		// OPDS catalog routes, the same feeds as OPDS 1.2 and 2.0
+		opds1 := v1.Group("/opds/v1.2")
+		opds1.Use(opdsHandler.Catalog(handlers.OPDS1, opds1.BasePath()))
+		{
+			opds1.GET("", opdsHandler.Start)
+			opds1.GET("/opensearch.xml", opdsHandler.OpenSearch)
+		}
+		opds2 := v1.Group("/opds/v2")
+		opds2.Use(opdsHandler.Catalog(handlers.OPDS2, opds2.BasePath()))
+		{
+			opds2.GET("", opdsHandler.Start)
+		}
+		for _, catalog := range []*gin.RouterGroup{opds1, opds2} {
+			catalog.GET("/new", opdsHandler.New)
+			catalog.GET("/authors", opdsHandler.Authors)
+			catalog.GET("/authors/:id", opdsHandler.AuthorBooks)
+			catalog.GET("/subjects", opdsHandler.Subjects)
+			catalog.GET("/subjects/:id", opdsHandler.SubjectBooks)
+			catalog.GET("/search", opdsHandler.Search)
+			catalog.GET("/books/:id", opdsHandler.GetBook)
 		}
 
-		// Borrower routes
+		// SRU explain and searchRetrieve, by query string or form POST
+		v1.GET("/sru", sruHandler.SRU)
+		v1.POST("/sru", sruHandler.SRU)
+
+		// Borrower routes. Setting a PIN would let the caller sign in as the
+		// borrower, so it is limited to staff like the data export and
+		// erasure
+		staff := middleware.StaffToken(cfg.StaffToken)
 		borrowers := v1.Group("/borrowers")
 		{
 			borrowers.POST("", borrowerHandler.CreateBorrower)
+			borrowers.POST("/batch", borrowerHandler.BatchBorrowers)
 			borrowers.GET("", borrowerHandler.GetAllBorrowers)
+			borrowers.GET("/export", borrowerHandler.ExportBorrowers)
+			borrowers.GET("/card/:number", borrowerHandler.GetBorrowerByCard)
+			borrowers.PUT("/update-expired", borrowerHandler.UpdateExpiredStatus)
 			borrowers.GET("/:id", borrowerHandler.GetBorrower)
 			borrowers.PUT("/:id", borrowerHandler.UpdateBorrower)
 			borrowers.DELETE("/:id", borrowerHandler.DeleteBorrower)
+			borrowers.GET("/trash", borrowerHandler.GetDeletedBorrowers)
+			borrowers.POST("/:id/restore", borrowerHandler.RestoreBorrower)
+			borrowers.DELETE("/:id/purge", borrowerHandler.PurgeBorrower)
+			borrowers.POST("/:id/renew", borrowerHandler.RenewMembership)
+			borrowers.GET("/:id/blocks", borrowerHandler.GetBlocks)
+			borrowers.POST("/:id/blocks", borrowerHandler.CreateBlock)
+			borrowers.POST("/:id/blocks/:blockId/lift", borrowerHandler.LiftBlock)
+			borrowers.PUT("/:id/pin", staff, borrowerHandler.SetPIN)
+			borrowers.GET("/:id/holds", holdHandler.GetBorrowerHolds)
+			borrowers.GET("/:id/fines", fineHandler.GetBorrowerFines)
+			borrowers.GET("/:id/data-export", staff, privacyHandler.ExportBorrowerData)
+			borrowers.POST("/:id/erase", staff, privacyHandler.EraseBorrower)
+			borrowers.GET("/:id/barcode", labelHandler.GetCardBarcode)
+		}
+
+		// Label sheet routes
+		labels := v1.Group("/labels")
+		{
+			labels.GET("/layouts", labelHandler.GetLayouts)
+			labels.POST("/books", labelHandler.PrintBookLabels)
+			labels.POST("/borrowers", labelHandler.PrintBorrowerLabels)
+		}
+
+		// Borrower category routes
+		categories := v1.Group("/borrower-categories")
+		{
+			categories.POST("", borrowerHandler.CreateCategory)
+			categories.GET("", borrowerHandler.GetCategories)
+			categories.GET("/:id", borrowerHandler.GetCategory)
+			categories.PUT("/:id", borrowerHandler.UpdateCategory)
+			categories.DELETE("/:id", borrowerHandler.DeleteCategory)
 		}
 
 		// Borrowing routes
@@ -65,10 +281,90 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB) {
 			borrowings.POST("/borrow", borrowingHandler.BorrowBook)
 			borrowings.POST("/return", borrowingHandler.ReturnBook)
 			borrowings.GET("", borrowingHandler.GetAllBorrowings)
+			borrowings.GET("/export", borrowingHandler.ExportBorrowings)
 			borrowings.GET("/:id", borrowingHandler.GetBorrowing)
+			borrowings.POST("/:id/renew", borrowingHandler.RenewBorrowing)
+			borrowings.GET("/:id/receipt", receiptHandler.GetReceipt)
+			borrowings.POST("/:id/receipt/email", receiptHandler.EmailReceipt)
 			borrowings.GET("/borrower/:borrowerId", borrowingHandler.GetBorrowingsByBorrower)
 			borrowings.GET("/overdue", borrowingHandler.GetOverdueBorrowings)
 			borrowings.PUT("/update-overdue", borrowingHandler.UpdateOverdueStatus)
+			borrowings.POST("/anonymize", privacyHandler.AnonymizeHistory)
+		}
+
+		// Hold routes
+		holds := v1.Group("/holds")
+		{
+			holds.POST("", holdHandler.PlaceHold)
+			holds.GET("/:id", holdHandler.GetHold)
+			holds.POST("/:id/cancel", holdHandler.CancelHold)
+		}
+
+		// Fine routes
+		fines := v1.Group("/fines")
+		{
+			fines.POST("/:id/pay", fineHandler.PayFine)
+			fines.POST("/:id/waive", fineHandler.WaiveFine)
+		}
+
+		// Self-service routes for signed-in borrowers
+		v1.POST("/me/login", meHandler.Login)
+		self := v1.Group("/me")
+		self.Use(middleware.Borrower(cfg.JWTSecret, borrowerService))
+		{
+			self.GET("", meHandler.GetProfile)
+			self.PUT("", meHandler.UpdateProfile)
+			self.GET("/loans", meHandler.GetLoans)
+			self.GET("/history", meHandler.GetHistory)
+			self.POST("/loans/:id/renew", meHandler.RenewLoan)
+			self.GET("/holds", meHandler.GetHolds)
+			self.POST("/holds", meHandler.PlaceHold)
+			self.POST("/holds/:id/cancel", meHandler.CancelHold)
+			self.GET("/fines", meHandler.GetFines)
+		}
+
+		// Branch routes
+		branches := v1.Group("/branches")
+		{
+			branches.POST("", branchHandler.CreateBranch)
+			branches.GET("", branchHandler.GetAllBranches)
+			branches.GET("/:id", branchHandler.GetBranch)
+			branches.PUT("/:id", branchHandler.UpdateBranch)
+			branches.DELETE("/:id", branchHandler.DeleteBranch)
+			branches.GET("/:id/locations", branchHandler.GetLocations)
+			branches.POST("/:id/locations", branchHandler.CreateLocation)
+		}
+
+		// Location routes
+		locations := v1.Group("/locations")
+		{
+			locations.GET("/:id", branchHandler.GetLocation)
+			locations.PUT("/:id", branchHandler.UpdateLocation)
+			locations.DELETE("/:id", branchHandler.DeleteLocation)
 		}
+
+		// Transfer routes
+		transfers := v1.Group("/transfers")
+		{
+			transfers.GET("", transferHandler.GetTransfers)
+			transfers.GET("/:id", transferHandler.GetTransfer)
+			transfers.POST("/:id/receive", transferHandler.ReceiveTransfer)
+		}
+
+		// SIP2 terminal routes
+		terminals := v1.Group("/sip-terminals")
+		{
+			terminals.POST("", terminalHandler.CreateTerminal)
+			terminals.GET("", terminalHandler.GetAllTerminals)
+			terminals.GET("/:id", terminalHandler.GetTerminal)
+			terminals.PUT("/:id", terminalHandler.UpdateTerminal)
+			terminals.DELETE("/:id", terminalHandler.DeleteTerminal)
+		}
+
+		// Audit routes
+		v1.GET("/audit", auditHandler.GetAuditLogs)
+
+		// Trash routes
+		v1.POST("/trash/purge", trashHandler.PurgeExpired)
 	}
 }
@@ -1,20 +1,27 @@
 package services
 
 import (
+	"context"
 	"errors"
 
 	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
 
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
 type AuthorService struct {
-	db *gorm.DB
+	store repository.Store
 }
 
-func NewAuthorService(db *gorm.DB) *AuthorService {
-	return &AuthorService{db: db}
+func NewAuthorService(store repository.Store) *AuthorService {
+	return &AuthorService{store: store}
+}
+
+// WithContext returns a copy of the service whose queries carry ctx, so the
+// request's actor and ID reach the audit log.
+func (s *AuthorService) WithContext(ctx context.Context) *AuthorService {
+	return &AuthorService{store: s.store.WithContext(ctx)}
 }
 
 func (s *AuthorService) CreateAuthor(req *models.CreateAuthorRequest) (*models.Author, error) {
@@ -23,7 +30,7 @@ func (s *AuthorService) CreateAuthor(req *models.CreateAuthorRequest) (*models.A
 		Biography: req.Biography,
 	}
 
-	if err := s.db.Create(author).Error; err != nil {
+	if err := s.store.Authors().Create(author); err != nil {
 		return nil, err
 	}
 
@@ -31,41 +38,24 @@ func (s *AuthorService) CreateAuthor(req *models.CreateAuthorRequest) (*models.A
 }
 
 func (s *AuthorService) GetAuthor(id uuid.UUID) (*models.Author, error) {
-	var author models.Author
-	if err := s.db.First(&author, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("author not found")
+	author, err := s.store.Authors().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrAuthorNotFound
 		}
 		return nil, err
 	}
-	return &author, nil
+	return author, nil
 }
 
 func (s *AuthorService) GetAllAuthors(page, limit int) ([]models.Author, int64, error) {
-	var authors []models.Author
-	var total int64
-
 	offset := (page - 1) * limit
-
-	// Count total records
-	if err := s.db.Model(&models.Author{}).Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	// Get authors with pagination
-	if err := s.db.Offset(offset).Limit(limit).Find(&authors).Error; err != nil {
-		return nil, 0, err
-	}
-
-	return authors, total, nil
+	return s.store.Authors().List(offset, limit)
 }
 
 func (s *AuthorService) UpdateAuthor(id uuid.UUID, req *models.UpdateAuthorRequest) (*models.Author, error) {
-	var author models.Author
-	if err := s.db.First(&author, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("author not found")
-		}
+	author, err := s.GetAuthor(id)
+	if err != nil {
 		return nil, err
 	}
 
@@ -77,60 +67,87 @@ func (s *AuthorService) UpdateAuthor(id uuid.UUID, req *models.UpdateAuthorReque
 		author.Biography = req.Biography
 	}
 
-	if err := s.db.Save(&author).Error; err != nil {
+	if err := s.store.Authors().Update(author); err != nil {
 		return nil, err
 	}
 
-	return &author, nil
+	return author, nil
 }
 
 func (s *AuthorService) DeleteAuthor(id uuid.UUID) error {
-	var author models.Author
-	if err := s.db.First(&author, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("author not found")
-		}
+	author, err := s.GetAuthor(id)
+	if err != nil {
 		return err
 	}
 
 	// Check if author has books
-	var bookCount int64
-	if err := s.db.Model(&models.Book{}).Where("author_id = ?", id).Count(&bookCount).Error; err != nil {
+	bookCount, err := s.store.Books().CountByAuthor(id, false)
+	if err != nil {
 		return err
 	}
 
 	if bookCount > 0 {
-		return errors.New("cannot delete author with existing books")
-	}
-
-	if err := s.db.Delete(&author).Error; err != nil {
-		return err
+		return ErrAuthorHasBooks
 	}
 
-	return nil
+	return s.store.Authors().Delete(author)
 }
 
 func (s *AuthorService) SearchAuthors(query string, page, limit int) ([]models.Author, int64, error) {
-	var authors []models.Author
-	var total int64
+	offset := (page - 1) * limit
+	return s.store.Authors().Search(query, offset, limit)
+}
 
+// ExportAuthors streams the authors matching query (all authors when empty)
+// to fn in batches of exportBatchSize.
+func (s *AuthorService) ExportAuthors(query string, fn func([]models.Author) error) error {
+	return s.store.Authors().Export(query, exportBatchSize, fn)
+}
+
+func (s *AuthorService) GetDeletedAuthors(page, limit int) ([]models.Author, int64, error) {
 	offset := (page - 1) * limit
-	searchQuery := "%" + query + "%"
+	return s.store.Authors().ListDeleted(offset, limit)
+}
 
-	// Count total records
-	if err := s.db.Model(&models.Author{}).
-		Where("name ILIKE ? OR biography ILIKE ?", searchQuery, searchQuery).
-		Count(&total).Error; err != nil {
-		return nil, 0, err
+func (s *AuthorService) getDeletedAuthor(id uuid.UUID) (*models.Author, error) {
+	author, err := s.store.Authors().GetDeleted(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrAuthorNotInTrash
+		}
+		return nil, err
+	}
+	return author, nil
+}
+
+func (s *AuthorService) RestoreAuthor(id uuid.UUID) (*models.Author, error) {
+	author, err := s.getDeletedAuthor(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Authors().Restore(author); err != nil {
+		return nil, err
 	}
 
-	// Get authors with pagination
-	if err := s.db.Where("name ILIKE ? OR biography ILIKE ?", searchQuery, searchQuery).
-		Offset(offset).
-		Limit(limit).
-		Find(&authors).Error; err != nil {
-		return nil, 0, err
+	return s.GetAuthor(id)
+}
+
+func (s *AuthorService) PurgeAuthor(id uuid.UUID) error {
+	author, err := s.getDeletedAuthor(id)
+	if err != nil {
+		return err
+	}
+
+	// Books keep a foreign key to their author even once deleted
+	bookCount, err := s.store.Books().CountByAuthor(id, true)
+	if err != nil {
+		return err
+	}
+
+	if bookCount > 0 {
+		return ErrAuthorHasBookHistory
 	}
 
-	return authors, total, nil
+	return s.store.Authors().Purge(author)
 }
@@ -0,0 +1,298 @@
+package services_test
+
+import (
+	"testing"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateAuthor(t *testing.T) {
+	store, _ := setup(t)
+	svc := services.NewAuthorService(store)
+
+	author, err := svc.CreateAuthor(&models.CreateAuthorRequest{Name: "Toni Morrison", Biography: "Nobel laureate"})
+	checkErr(t, err, nil)
+	if author.ID == uuid.Nil {
+		t.Fatal("expected an ID to be assigned")
+	}
+
+	got, err := svc.GetAuthor(author.ID)
+	checkErr(t, err, nil)
+	if got.Name != "Toni Morrison" || got.Biography != "Nobel laureate" {
+		t.Fatalf("unexpected author %+v", got)
+	}
+}
+
+func TestGetAuthor(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewAuthorService(store)
+	author := fx.Author()
+	deleted := fx.Author()
+	fx.SoftDelete(deleted)
+
+	tests := []struct {
+		name string
+		id   uuid.UUID
+		want error
+	}{
+		{"existing", author.ID, nil},
+		{"unknown", uuid.New(), services.ErrAuthorNotFound},
+		{"deleted", deleted.ID, services.ErrAuthorNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.GetAuthor(tt.id)
+			checkErr(t, err, tt.want)
+			if tt.want == nil && got.ID != tt.id {
+				t.Fatalf("got author %v, want %v", got.ID, tt.id)
+			}
+		})
+	}
+}
+
+func TestGetAllAuthors(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewAuthorService(store)
+	for i := 0; i < 12; i++ {
+		fx.Author()
+	}
+	fx.SoftDelete(fx.Author())
+
+	tests := []struct {
+		page, limit, want int
+	}{
+		{1, 10, 10},
+		{2, 10, 2},
+		{3, 10, 0},
+		{1, 5, 5},
+	}
+	for _, tt := range tests {
+		authors, total, err := svc.GetAllAuthors(tt.page, tt.limit)
+		checkErr(t, err, nil)
+		if total != 12 {
+			t.Fatalf("total = %d, want 12", total)
+		}
+		if len(authors) != tt.want {
+			t.Fatalf("page %d limit %d: got %d authors, want %d", tt.page, tt.limit, len(authors), tt.want)
+		}
+	}
+}
+
+func TestUpdateAuthor(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           models.UpdateAuthorRequest
+		wantName      string
+		wantBiography string
+	}{
+		{"name only", models.UpdateAuthorRequest{Name: "New Name"}, "New Name", "Original biography"},
+		{"biography only", models.UpdateAuthorRequest{Biography: "New biography"}, "Original", "New biography"},
+		{"both", models.UpdateAuthorRequest{Name: "New Name", Biography: "New biography"}, "New Name", "New biography"},
+		{"empty keeps values", models.UpdateAuthorRequest{}, "Original", "Original biography"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewAuthorService(store)
+			author := fx.Author(func(a *models.Author) {
+				a.Name = "Original"
+				a.Biography = "Original biography"
+			})
+
+			updated, err := svc.UpdateAuthor(author.ID, &tt.req)
+			checkErr(t, err, nil)
+
+			got, err := svc.GetAuthor(author.ID)
+			checkErr(t, err, nil)
+			for _, a := range []*models.Author{updated, got} {
+				if a.Name != tt.wantName || a.Biography != tt.wantBiography {
+					t.Fatalf("got %q/%q, want %q/%q", a.Name, a.Biography, tt.wantName, tt.wantBiography)
+				}
+			}
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		store, _ := setup(t)
+		_, err := services.NewAuthorService(store).UpdateAuthor(uuid.New(), &models.UpdateAuthorRequest{Name: "x"})
+		checkErr(t, err, services.ErrAuthorNotFound)
+	})
+}
+
+func TestDeleteAuthor(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(fx *testutil.Fixtures, author *models.Author)
+		want    error
+	}{
+		{"without books", func(*testutil.Fixtures, *models.Author) {}, nil},
+		{"with books", func(fx *testutil.Fixtures, author *models.Author) {
+			fx.Book(author)
+		}, services.ErrAuthorHasBooks},
+		{"with only deleted books", func(fx *testutil.Fixtures, author *models.Author) {
+			fx.SoftDelete(fx.Book(author))
+		}, nil},
+		{"credited as translator", func(fx *testutil.Fixtures, author *models.Author) {
+			fx.Book(nil, testutil.Credited(author, models.RoleTranslator))
+		}, services.ErrAuthorHasBooks},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewAuthorService(store)
+			author := fx.Author()
+			tt.prepare(fx, author)
+
+			checkErr(t, svc.DeleteAuthor(author.ID), tt.want)
+
+			_, err := svc.GetAuthor(author.ID)
+			if tt.want == nil {
+				checkErr(t, err, services.ErrAuthorNotFound)
+			} else {
+				checkErr(t, err, nil)
+			}
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		store, _ := setup(t)
+		checkErr(t, services.NewAuthorService(store).DeleteAuthor(uuid.New()), services.ErrAuthorNotFound)
+	})
+}
+
+func TestSearchAuthors(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewAuthorService(store)
+	fx.Author(func(a *models.Author) { a.Name = "Gabriel García Márquez"; a.Biography = "Colombian novelist" })
+	fx.Author(func(a *models.Author) { a.Name = "Isabel Allende"; a.Biography = "Chilean novelist" })
+	fx.Author(func(a *models.Author) { a.Name = "Jorge Luis Borges"; a.Biography = "Argentine writer" })
+
+	tests := []struct {
+		query string
+		want  int64
+	}{
+		{"allende", 1},
+		{"NOVELIST", 2},
+		{"Borges", 1},
+		{"Tolstoy", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			authors, total, err := svc.SearchAuthors(tt.query, 1, 10)
+			checkErr(t, err, nil)
+			if total != tt.want || int64(len(authors)) != tt.want {
+				t.Fatalf("got %d (%d rows), want %d", total, len(authors), tt.want)
+			}
+		})
+	}
+}
+
+func TestExportAuthors(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewAuthorService(store)
+	fx.Author(func(a *models.Author) { a.Name = "Chinua Achebe" })
+	fx.Author(func(a *models.Author) { a.Name = "Wole Soyinka" })
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", 2},
+		{"achebe", 1},
+		{"nobody", 0},
+	}
+	for _, tt := range tests {
+		var exported int
+		err := svc.ExportAuthors(tt.query, func(batch []models.Author) error {
+			exported += len(batch)
+			return nil
+		})
+		checkErr(t, err, nil)
+		if exported != tt.want {
+			t.Fatalf("query %q: exported %d, want %d", tt.query, exported, tt.want)
+		}
+	}
+}
+
+func TestGetDeletedAuthors(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewAuthorService(store)
+	fx.Author()
+	deleted := fx.Author()
+	fx.SoftDelete(deleted)
+
+	authors, total, err := svc.GetDeletedAuthors(1, 10)
+	checkErr(t, err, nil)
+	if total != 1 || authors[0].ID != deleted.ID {
+		t.Fatalf("got %d deleted authors %+v", total, authors)
+	}
+}
+
+func TestRestoreAuthor(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewAuthorService(store)
+	active := fx.Author()
+	deleted := fx.Author()
+	fx.SoftDelete(deleted)
+
+	tests := []struct {
+		name string
+		id   uuid.UUID
+		want error
+	}{
+		{"deleted", deleted.ID, nil},
+		{"already restored", deleted.ID, services.ErrAuthorNotInTrash},
+		{"active", active.ID, services.ErrAuthorNotInTrash},
+		{"unknown", uuid.New(), services.ErrAuthorNotInTrash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restored, err := svc.RestoreAuthor(tt.id)
+			checkErr(t, err, tt.want)
+			if tt.want == nil && restored.ID != tt.id {
+				t.Fatalf("restored %v, want %v", restored.ID, tt.id)
+			}
+		})
+	}
+}
+
+func TestPurgeAuthor(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(fx *testutil.Fixtures) uuid.UUID
+		want    error
+	}{
+		{"deleted", func(fx *testutil.Fixtures) uuid.UUID {
+			author := fx.Author()
+			fx.SoftDelete(author)
+			return author.ID
+		}, nil},
+		{"referenced by deleted book", func(fx *testutil.Fixtures) uuid.UUID {
+			author := fx.Author()
+			fx.SoftDelete(fx.Book(author))
+			fx.SoftDelete(author)
+			return author.ID
+		}, services.ErrAuthorHasBookHistory},
+		{"active", func(fx *testutil.Fixtures) uuid.UUID {
+			return fx.Author().ID
+		}, services.ErrAuthorNotInTrash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewAuthorService(store)
+			id := tt.prepare(fx)
+
+			checkErr(t, svc.PurgeAuthor(id), tt.want)
+
+			if tt.want == nil {
+				_, err := svc.RestoreAuthor(id)
+				checkErr(t, err, services.ErrAuthorNotInTrash)
+			}
+		})
+	}
+}
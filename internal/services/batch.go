@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+)
+
+// MaxBatchOperations is the most operations one batch may carry, matching
+// the binding on the batch request DTOs.
+const MaxBatchOperations = 1000
+
+var (
+	ErrBatchMissingID      = newError(KindInvalid, "id is required for update and delete operations")
+	ErrBatchMissingPayload = newError(KindInvalid, "operation payload is required")
+	ErrBatchSize           = newError(KindInvalid, fmt.Sprintf("batch must contain between 1 and %d operations", MaxBatchOperations))
+)
+
+// batchInternalError is the message clients see for an operation that
+// failed with an error of unknown kind, such as a database error.
+const batchInternalError = "internal error"
+
+// BatchOperationError reports which operation aborted an atomic batch. Its
+// message only carries Err's message if Err is a service error, so
+// database details do not reach clients; Unwrap returns Err itself.
+type BatchOperationError struct {
+	Index int
+	Op    string
+	Err   error
+}
+
+func (e *BatchOperationError) Error() string {
+	return fmt.Sprintf("operation %d (%s): %s", e.Index, e.Op, batchErrorMessage(e.Err))
+}
+
+func (e *BatchOperationError) Unwrap() error {
+	return e.Err
+}
+
+// batchErrorMessage returns the message of err if it is a service error,
+// and a generic one otherwise.
+func batchErrorMessage(err error) string {
+	var svcErr *Error
+	if errors.As(err, &svcErr) {
+		return svcErr.Message
+	}
+	return batchInternalError
+}
+
+// batchApplyFunc applies the operation at index i using services bound to tx.
+type batchApplyFunc func(tx repository.Store, i int) (interface{}, error)
+
+// runBatch applies ops either inside a single transaction (atomic mode) or
+// each inside its own transaction (independent mode). Every operation goes
+// through the regular single-item service methods so the same business
+// rules apply.
+func runBatch(store repository.Store, mode string, ops []string, apply batchApplyFunc) (*models.BatchResponse, error) {
+	if len(ops) == 0 || len(ops) > MaxBatchOperations {
+		return nil, ErrBatchSize
+	}
+	if mode == "" {
+		mode = models.BatchModeAtomic
+	}
+
+	resp := &models.BatchResponse{Mode: mode, Results: make([]models.BatchItemResult, 0, len(ops))}
+
+	if mode == models.BatchModeAtomic {
+		err := store.Transaction(func(tx repository.Store) error {
+			for i, op := range ops {
+				data, err := apply(tx, i)
+				if err != nil {
+					if KindOf(err) == KindInternal {
+						log.Printf("batch operation %d (%s) failed: %v", i, op, err)
+					}
+					return &BatchOperationError{Index: i, Op: op, Err: err}
+				}
+				resp.Results = append(resp.Results, models.BatchItemResult{Index: i, Op: op, Success: true, Data: data})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		resp.Succeeded = len(ops)
+		return resp, nil
+	}
+
+	for i, op := range ops {
+		var data interface{}
+		err := store.Transaction(func(tx repository.Store) error {
+			var err error
+			data, err = apply(tx, i)
+			return err
+		})
+
+		result := models.BatchItemResult{Index: i, Op: op}
+		if err != nil {
+			if KindOf(err) == KindInternal {
+				log.Printf("batch operation %d (%s) failed: %v", i, op, err)
+			}
+			result.Error = batchErrorMessage(err)
+			resp.Failed++
+		} else {
+			result.Success = true
+			result.Data = data
+			resp.Succeeded++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	return resp, nil
+}
@@ -1,122 +1,425 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"strings"
 
+	"library-management-go/internal/callnumber"
+	"library-management-go/internal/cardnumber"
 	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
 
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
 type BookService struct {
-	db *gorm.DB
+	store repository.Store
 }
 
-func NewBookService(db *gorm.DB) *BookService {
-	return &BookService{db: db}
+func NewBookService(store repository.Store) *BookService {
+	return &BookService{store: store}
 }
 
-func (s *BookService) CreateBook(req *models.CreateBookRequest) (*models.Book, error) {
-	// Check if author exists
-	var author models.Author
-	if err := s.db.First(&author, req.AuthorID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("author not found")
+// WithContext returns a copy of the service whose queries carry ctx, so the
+// request's actor and ID reach the audit log.
+func (s *BookService) WithContext(ctx context.Context) *BookService {
+	return &BookService{store: s.store.WithContext(ctx)}
+}
+
+// checkAuthor returns ErrAuthorNotFound unless the author exists.
+func (s *BookService) checkAuthor(id uuid.UUID) error {
+	if _, err := s.store.Authors().Get(id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAuthorNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// contributorRoles are the roles an author can be credited with.
+var contributorRoles = map[string]bool{
+	models.RoleAuthor:      true,
+	models.RoleEditor:      true,
+	models.RoleTranslator:  true,
+	models.RoleIllustrator: true,
+}
+
+// resolveContributors turns the requested credits into contributors
+// positioned in the order given. Every author must exist, and may be
+// credited in several roles but only once in each.
+func (s *BookService) resolveContributors(reqs []models.ContributorRequest) ([]models.BookContributor, error) {
+	if len(reqs) == 0 {
+		return nil, ErrNoContributors
+	}
+
+	type credit struct {
+		authorID uuid.UUID
+		role     string
+	}
+	seen := map[credit]bool{}
+	checked := map[uuid.UUID]bool{}
+	contributors := make([]models.BookContributor, 0, len(reqs))
+	for i, req := range reqs {
+		role := req.Role
+		if role == "" {
+			role = models.RoleAuthor
 		}
+		if !contributorRoles[role] {
+			return nil, ErrInvalidContributorRole
+		}
+
+		key := credit{req.AuthorID, role}
+		if seen[key] {
+			return nil, ErrDuplicateContributor
+		}
+		seen[key] = true
+
+		// Check if author exists
+		if !checked[req.AuthorID] {
+			if err := s.checkAuthor(req.AuthorID); err != nil {
+				return nil, err
+			}
+			checked[req.AuthorID] = true
+		}
+
+		contributors = append(contributors, models.BookContributor{
+			AuthorID: req.AuthorID,
+			Role:     role,
+			Position: i,
+		})
+	}
+	return contributors, nil
+}
+
+// checkSubjects returns ErrSubjectNotFound unless every subject exists, and
+// the IDs without duplicates.
+func (s *BookService) checkSubjects(ids []uuid.UUID) ([]uuid.UUID, error) {
+	seen := map[uuid.UUID]bool{}
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if _, err := s.store.Subjects().Get(id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrSubjectNotFound
+			}
+			return nil, err
+		}
+		unique = append(unique, id)
+	}
+	return unique, nil
+}
+
+// checkISBN returns ErrDuplicateISBN if an active book other than excludeID
+// already uses isbn.
+func (s *BookService) checkISBN(isbn string, excludeID uuid.UUID) error {
+	if _, err := s.store.Books().FindByISBN(isbn, excludeID); err == nil {
+		return ErrDuplicateISBN
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// checkBarcode returns ErrDuplicateBarcode if an active book other than
+// excludeID already uses barcode.
+func (s *BookService) checkBarcode(barcode string, excludeID uuid.UUID) error {
+	if _, err := s.store.Books().FindByBarcode(barcode, excludeID); err == nil {
+		return ErrDuplicateBarcode
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// newBarcode generates an item barcode no other book holds.
+func (s *BookService) newBarcode() (string, error) {
+	for attempt := 0; attempt < 5; attempt++ {
+		barcode, err := cardnumber.GenerateItem()
+		if err != nil {
+			return "", err
+		}
+		if err := s.checkBarcode(barcode, uuid.Nil); err == nil {
+			return barcode, nil
+		} else if !errors.Is(err, ErrDuplicateBarcode) {
+			return "", err
+		}
+	}
+	return "", errors.New("failed to generate a unique item barcode")
+}
+
+// checkLocation returns ErrLocationNotFound unless the location exists.
+func (s *BookService) checkLocation(id uuid.UUID) error {
+	if _, err := s.store.Locations().Get(id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrLocationNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// checkPublisher returns ErrPublisherNotFound unless the publisher exists.
+func (s *BookService) checkPublisher(id uuid.UUID) error {
+	if _, err := s.store.Publishers().Get(id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrPublisherNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// checkSeries returns ErrSeriesNotFound unless the series exists.
+func (s *BookService) checkSeries(id uuid.UUID) error {
+	if _, err := s.store.Series().Get(id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrSeriesNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// checkWork returns ErrWorkNotFound unless the work exists.
+func (s *BookService) checkWork(id uuid.UUID) error {
+	if _, err := s.store.Works().Get(id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrWorkNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// setEditionRefs points book at the publisher, series and work given,
+// leaving those that are uuid.Nil unchanged. Each must exist.
+func (s *BookService) setEditionRefs(book *models.Book, publisherID, seriesID, workID uuid.UUID) error {
+	if publisherID != uuid.Nil {
+		if err := s.checkPublisher(publisherID); err != nil {
+			return err
+		}
+		book.PublisherID = &publisherID
+	}
+	if seriesID != uuid.Nil {
+		if err := s.checkSeries(seriesID); err != nil {
+			return err
+		}
+		book.SeriesID = &seriesID
+	}
+	if workID != uuid.Nil {
+		if err := s.checkWork(workID); err != nil {
+			return err
+		}
+		book.WorkID = &workID
+	}
+	return nil
+}
+
+// classify gives book callNumber in scheme, detecting the scheme from the
+// call number when empty, and the key that puts it in shelf order.
+func classify(book *models.Book, callNumber, scheme string) error {
+	callNumber = strings.TrimSpace(callNumber)
+	if callNumber == "" {
+		return ErrSchemeWithoutCallNumber
+	}
+	if scheme == "" {
+		scheme = callnumber.Detect(callNumber)
+	}
+	key, err := callnumber.SortKey(scheme, callNumber)
+	if err != nil {
+		return ErrInvalidCallNumber
+	}
+	book.CallNumber = callNumber
+	book.ClassificationScheme = scheme
+	book.CallNumberSort = key
+	return nil
+}
+
+func (s *BookService) CreateBook(req *models.CreateBookRequest) (*models.Book, error) {
+	contributors, err := s.resolveContributors(req.Contributors)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if subjects exist
+	subjectIDs, err := s.checkSubjects(req.SubjectIDs)
+	if err != nil {
 		return nil, err
 	}
 
 	// Check if ISBN already exists
-	var existingBook models.Book
-	if err := s.db.Where("isbn = ?", req.ISBN).First(&existingBook).Error; err == nil {
-		return nil, errors.New("book with this ISBN already exists")
+	if err := s.checkISBN(req.ISBN, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	// Check if barcode already exists (if provided); otherwise generate one
+	barcode := strings.TrimSpace(req.Barcode)
+	if barcode != "" {
+		err = s.checkBarcode(barcode, uuid.Nil)
+	} else {
+		barcode, err = s.newBarcode()
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	book := &models.Book{
-		Title:       req.Title,
-		ISBN:        req.ISBN,
-		Description: req.Description,
-		AuthorID:    req.AuthorID,
-		PublishedAt: req.PublishedAt,
-		Available:   true,
+		Title:        req.Title,
+		ISBN:         req.ISBN,
+		Barcode:      barcode,
+		Description:  req.Description,
+		Contributors: contributors,
+		PublishedAt:  req.PublishedAt,
+		Available:    true,
+		Edition:      req.Edition,
+		Format:       req.Format,
+		Pages:        req.Pages,
+		Language:     req.Language,
+		SeriesVolume: req.SeriesVolume,
 	}
 
-	if err := s.db.Create(book).Error; err != nil {
+	// Check if publisher, series and work exist (if provided)
+	if err := s.setEditionRefs(book, req.PublisherID, req.SeriesID, req.WorkID); err != nil {
 		return nil, err
 	}
+	if book.SeriesVolume > 0 && book.SeriesID == nil {
+		return nil, ErrVolumeWithoutSeries
+	}
 
-	// Load the author relationship
-	if err := s.db.Preload("Author").First(book, book.ID).Error; err != nil {
+	if req.CallNumber != "" || req.ClassificationScheme != "" {
+		if err := classify(book, req.CallNumber, req.ClassificationScheme); err != nil {
+			return nil, err
+		}
+	}
+
+	// New items start out shelved at their home location
+	if req.HomeLocationID != uuid.Nil {
+		if err := s.checkLocation(req.HomeLocationID); err != nil {
+			return nil, err
+		}
+		home := req.HomeLocationID
+		book.HomeLocationID = &home
+		book.CurrentLocationID = &home
+	}
+
+	err = s.store.Transaction(func(tx repository.Store) error {
+		if err := tx.Books().Create(book); err != nil {
+			return err
+		}
+		return tx.Books().SetSubjects(book.ID, subjectIDs)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return book, nil
+	// Load the contributors' authors and subjects
+	return s.GetBook(book.ID)
 }
 
 func (s *BookService) GetBook(id uuid.UUID) (*models.Book, error) {
-	var book models.Book
-	if err := s.db.Preload("Author").First(&book, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("book not found")
+	book, err := s.store.Books().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBookNotFound
 		}
 		return nil, err
 	}
-	return &book, nil
+	return book, nil
 }
 
-func (s *BookService) GetAllBooks(page, limit int) ([]models.Book, int64, error) {
-	var books []models.Book
-	var total int64
+// GetBookByBarcode looks an item up by the barcode on its label.
+func (s *BookService) GetBookByBarcode(barcode string) (*models.Book, error) {
+	if barcode == "" {
+		return nil, ErrBookNotFound
+	}
 
-	offset := (page - 1) * limit
+	book, err := s.store.Books().FindByBarcode(barcode, uuid.Nil)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBookNotFound
+		}
+		return nil, err
+	}
+	return s.GetBook(book.ID)
+}
 
-	// Count total records
-	if err := s.db.Model(&models.Book{}).Count(&total).Error; err != nil {
-		return nil, 0, err
+func (s *BookService) GetAllBooks(page, limit int) ([]models.Book, int64, error) {
+	return s.FindBooks(models.BookFilter{}, page, limit)
+}
+
+// checkCallNumberRange returns ErrInvalidCallNumber unless the call number
+// bounds of filter are in its classification scheme, or in the same scheme
+// as each other when it has none.
+func checkCallNumberRange(filter models.BookFilter) error {
+	if filter.CallNumberFrom == "" && filter.CallNumberTo == "" {
+		return nil
+	}
+	if _, _, _, err := callnumber.Range(filter.Classification, filter.CallNumberFrom, filter.CallNumberTo); err != nil {
+		return ErrInvalidCallNumber
 	}
+	return nil
+}
+
+// FindBooks lists the books matching filter.
+func (s *BookService) FindBooks(filter models.BookFilter, page, limit int) ([]models.Book, int64, error) {
+	return s.FindBooksFrom(filter, (page-1)*limit, limit)
+}
 
-	// Get books with pagination
-	if err := s.db.Preload("Author").
-		Offset(offset).
-		Limit(limit).
-		Find(&books).Error; err != nil {
+// FindBooksFrom lists the books matching filter from offset, for clients
+// that page by record position rather than page number.
+func (s *BookService) FindBooksFrom(filter models.BookFilter, offset, limit int) ([]models.Book, int64, error) {
+	if err := checkCallNumberRange(filter); err != nil {
 		return nil, 0, err
 	}
-
-	return books, total, nil
+	return s.store.Books().Find(filter, offset, limit)
 }
 
 func (s *BookService) UpdateBook(id uuid.UUID, req *models.UpdateBookRequest) (*models.Book, error) {
-	var book models.Book
-	if err := s.db.First(&book, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("book not found")
-		}
+	book, err := s.GetBook(id)
+	if err != nil {
 		return nil, err
 	}
 
-	// Check if author exists (if provided)
-	if req.AuthorID != uuid.Nil {
-		var author models.Author
-		if err := s.db.First(&author, req.AuthorID).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, errors.New("author not found")
-			}
+	// Replace the credits (if provided)
+	var contributors []models.BookContributor
+	if len(req.Contributors) > 0 {
+		contributors, err = s.resolveContributors(req.Contributors)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Replace the subjects (if provided)
+	var subjectIDs []uuid.UUID
+	if req.SubjectIDs != nil {
+		subjectIDs, err = s.checkSubjects(req.SubjectIDs)
+		if err != nil {
 			return nil, err
 		}
-		book.AuthorID = req.AuthorID
 	}
 
 	// Check if ISBN already exists (if provided and different)
 	if req.ISBN != "" && req.ISBN != book.ISBN {
-		var existingBook models.Book
-		if err := s.db.Where("isbn = ? AND id != ?", req.ISBN, id).First(&existingBook).Error; err == nil {
-			return nil, errors.New("book with this ISBN already exists")
+		if err := s.checkISBN(req.ISBN, id); err != nil {
+			return nil, err
 		}
 		book.ISBN = req.ISBN
 	}
 
+	// Check if barcode already exists (if provided and different)
+	if barcode := strings.TrimSpace(req.Barcode); barcode != "" && barcode != book.Barcode {
+		if err := s.checkBarcode(barcode, id); err != nil {
+			return nil, err
+		}
+		book.Barcode = barcode
+	}
+
 	// Update fields
 	if req.Title != "" {
 		book.Title = req.Title
@@ -127,67 +430,216 @@ func (s *BookService) UpdateBook(id uuid.UUID, req *models.UpdateBookRequest) (*
 	if !req.PublishedAt.IsZero() {
 		book.PublishedAt = req.PublishedAt
 	}
+	if req.Edition != "" {
+		book.Edition = req.Edition
+	}
+	if req.Format != "" {
+		book.Format = req.Format
+	}
+	if req.Pages != nil {
+		book.Pages = *req.Pages
+	}
+	if req.Language != "" {
+		book.Language = req.Language
+	}
+	if req.SeriesVolume != nil {
+		book.SeriesVolume = *req.SeriesVolume
+	}
 
-	if err := s.db.Save(&book).Error; err != nil {
+	// Check if publisher, series and work exist (if provided)
+	if err := s.setEditionRefs(book, req.PublisherID, req.SeriesID, req.WorkID); err != nil {
 		return nil, err
 	}
+	if book.SeriesVolume > 0 && book.SeriesID == nil {
+		return nil, ErrVolumeWithoutSeries
+	}
+
+	// Reclassify (if provided); a scheme alone applies to the current call number
+	if req.CallNumber != "" || req.ClassificationScheme != "" {
+		callNumber := req.CallNumber
+		if callNumber == "" {
+			callNumber = book.CallNumber
+		}
+		if err := classify(book, callNumber, req.ClassificationScheme); err != nil {
+			return nil, err
+		}
+	}
 
-	// Load the author relationship
-	if err := s.db.Preload("Author").First(&book, book.ID).Error; err != nil {
+	// Check if home location exists (if provided)
+	if req.HomeLocationID != uuid.Nil {
+		if err := s.checkLocation(req.HomeLocationID); err != nil {
+			return nil, err
+		}
+		// An item on the shelf at its old home moves with it
+		if shelvedAtHome(book) {
+			current := req.HomeLocationID
+			book.CurrentLocationID = &current
+		}
+		home := req.HomeLocationID
+		book.HomeLocationID = &home
+	}
+
+	err = s.store.Transaction(func(tx repository.Store) error {
+		if err := tx.Books().Update(book); err != nil {
+			return err
+		}
+		if contributors != nil {
+			if err := tx.Books().SetContributors(book.ID, contributors); err != nil {
+				return err
+			}
+		}
+		if subjectIDs != nil {
+			return tx.Books().SetSubjects(book.ID, subjectIDs)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &book, nil
+	// Load the contributors' authors and subjects
+	return s.GetBook(book.ID)
+}
+
+// shelvedAtHome reports whether book is on the shelf at its home location,
+// treating an available item with no locations yet as shelved.
+func shelvedAtHome(book *models.Book) bool {
+	if book.HomeLocationID == nil {
+		return book.Available && !book.InTransit && book.CurrentLocationID == nil
+	}
+	return book.CurrentLocationID != nil && *book.CurrentLocationID == *book.HomeLocationID
 }
 
 func (s *BookService) DeleteBook(id uuid.UUID) error {
-	var book models.Book
-	if err := s.db.First(&book, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("book not found")
-		}
+	book, err := s.GetBook(id)
+	if err != nil {
 		return err
 	}
 
 	// Check if book is currently borrowed
-	var borrowing models.Borrowing
-	if err := s.db.Where("book_id = ? AND status = 'borrowed'", id).First(&borrowing).Error; err == nil {
-		return errors.New("cannot delete book that is currently borrowed")
+	borrowed, err := s.store.Borrowings().HasActiveForBook(id)
+	if err != nil {
+		return err
 	}
 
-	if err := s.db.Delete(&book).Error; err != nil {
-		return err
+	if borrowed {
+		return ErrBookCurrentlyBorrowed
 	}
 
-	return nil
+	return s.store.Books().Delete(book)
 }
 
 func (s *BookService) SearchBooks(query string, page, limit int) ([]models.Book, int64, error) {
-	var books []models.Book
-	var total int64
+	return s.FindBooks(models.BookFilter{Search: query}, page, limit)
+}
+
+func (s *BookService) BatchBooks(req *models.BookBatchRequest) (*models.BatchResponse, error) {
+	ops := make([]string, len(req.Operations))
+	for i, op := range req.Operations {
+		ops[i] = op.Op
+	}
+
+	return runBatch(s.store, req.Mode, ops, func(tx repository.Store, i int) (interface{}, error) {
+		op := req.Operations[i]
+		svc := NewBookService(tx)
+
+		switch op.Op {
+		case "create":
+			if op.Create == nil {
+				return nil, ErrBatchMissingPayload
+			}
+			return svc.CreateBook(op.Create)
+		case "update":
+			if op.ID == uuid.Nil {
+				return nil, ErrBatchMissingID
+			}
+			if op.Update == nil {
+				return nil, ErrBatchMissingPayload
+			}
+			return svc.UpdateBook(op.ID, op.Update)
+		default:
+			if op.ID == uuid.Nil {
+				return nil, ErrBatchMissingID
+			}
+			return nil, svc.DeleteBook(op.ID)
+		}
+	})
+}
 
+// ExportBooks streams the books matching filter to fn in batches of
+// exportBatchSize.
+func (s *BookService) ExportBooks(filter models.BookFilter, fn func([]models.Book) error) error {
+	if err := checkCallNumberRange(filter); err != nil {
+		return err
+	}
+	return s.store.Books().Export(filter, exportBatchSize, fn)
+}
+
+func (s *BookService) GetDeletedBooks(page, limit int) ([]models.Book, int64, error) {
 	offset := (page - 1) * limit
-	searchQuery := "%" + query + "%"
-
-	// Count total records
-	if err := s.db.Model(&models.Book{}).
-		Joins("JOIN authors ON books.author_id = authors.id").
-		Where("books.title ILIKE ? OR books.isbn ILIKE ? OR authors.name ILIKE ?",
-			searchQuery, searchQuery, searchQuery).
-		Count(&total).Error; err != nil {
-		return nil, 0, err
+	return s.store.Books().ListDeleted(offset, limit)
+}
+
+func (s *BookService) getDeletedBook(id uuid.UUID) (*models.Book, error) {
+	book, err := s.store.Books().GetDeleted(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBookNotInTrash
+		}
+		return nil, err
+	}
+	return book, nil
+}
+
+func (s *BookService) RestoreBook(id uuid.UUID) (*models.Book, error) {
+	book, err := s.getDeletedBook(id)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get books with pagination
-	if err := s.db.Preload("Author").
-		Joins("JOIN authors ON books.author_id = authors.id").
-		Where("books.title ILIKE ? OR books.isbn ILIKE ? OR authors.name ILIKE ?",
-			searchQuery, searchQuery, searchQuery).
-		Offset(offset).
-		Limit(limit).
-		Find(&books).Error; err != nil {
-		return nil, 0, err
+	// Every contributor must still be active
+	for _, contributor := range book.Contributors {
+		if err := s.checkAuthor(contributor.AuthorID); err != nil {
+			if errors.Is(err, ErrAuthorNotFound) {
+				return nil, ErrRestoreAuthorDeleted
+			}
+			return nil, err
+		}
+	}
+
+	// The ISBN may have been reused since the book was deleted
+	if err := s.checkISBN(book.ISBN, book.ID); err != nil {
+		return nil, err
+	}
+	if book.Barcode != "" {
+		if err := s.checkBarcode(book.Barcode, book.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.store.Books().Restore(book); err != nil {
+		return nil, err
+	}
+
+	// Load the contributors' authors
+	return s.GetBook(id)
+}
+
+func (s *BookService) PurgeBook(id uuid.UUID) error {
+	book, err := s.getDeletedBook(id)
+	if err != nil {
+		return err
+	}
+
+	// Loans, holds and transfers reference the book and must be kept
+	referenced, err := s.store.Books().Referenced(id)
+	if err != nil {
+		return err
+	}
+
+	if referenced {
+		return ErrBookHasBorrowings
 	}
 
-	return books, total, nil
+	return s.store.Books().Purge(book)
 }
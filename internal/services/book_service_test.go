@@ -0,0 +1,842 @@
+package services_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository/gormstore"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestCreateBook(t *testing.T) {
+	tests := []struct {
+		name string
+		req  func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest
+		want error
+	}{
+		{"valid", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{{AuthorID: author.ID}}}
+		}, nil},
+		{"unknown author", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{{AuthorID: uuid.New()}}}
+		}, services.ErrAuthorNotFound},
+		{"deleted author", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			deleted := fx.Author()
+			fx.SoftDelete(deleted)
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{{AuthorID: deleted.ID}}}
+		}, services.ErrAuthorNotFound},
+		{"duplicate ISBN", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			existing := fx.Book(author)
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: existing.ISBN, Contributors: []models.ContributorRequest{{AuthorID: author.ID}}}
+		}, services.ErrDuplicateISBN},
+		{"given barcode", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Barcode: "LIB-000123", Contributors: []models.ContributorRequest{{AuthorID: author.ID}}}
+		}, nil},
+		{"duplicate barcode", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			existing := fx.Book(author)
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Barcode: existing.Barcode, Contributors: []models.ContributorRequest{{AuthorID: author.ID}}}
+		}, services.ErrDuplicateBarcode},
+		{"ISBN of deleted book", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			deleted := fx.Book(author)
+			fx.SoftDelete(deleted)
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: deleted.ISBN, Contributors: []models.ContributorRequest{{AuthorID: author.ID}}}
+		}, nil},
+		{"several contributors", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{
+				{AuthorID: author.ID},
+				{AuthorID: fx.Author().ID, Role: models.RoleAuthor},
+				{AuthorID: fx.Author().ID, Role: models.RoleTranslator},
+				{AuthorID: author.ID, Role: models.RoleIllustrator},
+			}}
+		}, nil},
+		{"no contributors", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416"}
+		}, services.ErrNoContributors},
+		{"unknown role", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{{AuthorID: author.ID, Role: "narrator"}}}
+		}, services.ErrInvalidContributorRole},
+		{"credited twice in a role", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{
+				{AuthorID: author.ID},
+				{AuthorID: author.ID, Role: models.RoleAuthor},
+			}}
+		}, services.ErrDuplicateContributor},
+		{"shelved at a location", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{{AuthorID: author.ID}}, HomeLocationID: fx.Location(nil).ID}
+		}, nil},
+		{"unknown location", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{{AuthorID: author.ID}}, HomeLocationID: uuid.New()}
+		}, services.ErrLocationNotFound},
+		{"with subjects", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			broad := fx.Subject(nil)
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{{AuthorID: author.ID}},
+				SubjectIDs: []uuid.UUID{broad.ID, fx.Subject(broad).ID}}
+		}, nil},
+		{"unknown subject", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{{AuthorID: author.ID}},
+				SubjectIDs: []uuid.UUID{uuid.New()}}
+		}, services.ErrSubjectNotFound},
+		{"with edition metadata", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{{AuthorID: author.ID}},
+				PublisherID: fx.Publisher().ID, Edition: "1st Vintage International ed.", Format: models.FormatPaperback, Pages: 324,
+				Language: "en", SeriesID: fx.Series().ID, SeriesVolume: 2, WorkID: fx.Work().ID}
+		}, nil},
+		{"unknown publisher", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{{AuthorID: author.ID}},
+				PublisherID: uuid.New()}
+		}, services.ErrPublisherNotFound},
+		{"unknown series", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{{AuthorID: author.ID}},
+				SeriesID: uuid.New()}
+		}, services.ErrSeriesNotFound},
+		{"unknown work", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{{AuthorID: author.ID}},
+				WorkID: uuid.New()}
+		}, services.ErrWorkNotFound},
+		{"volume without series", func(fx *testutil.Fixtures, author *models.Author) *models.CreateBookRequest {
+			return &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{{AuthorID: author.ID}},
+				SeriesVolume: 1}
+		}, services.ErrVolumeWithoutSeries},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewBookService(store)
+			author := fx.Author()
+			req := tt.req(fx, author)
+
+			book, err := svc.CreateBook(req)
+			checkErr(t, err, tt.want)
+			if tt.want != nil {
+				return
+			}
+			if !book.Available {
+				t.Fatal("expected new book to be available")
+			}
+			// A barcode is generated unless one is given
+			if book.Barcode == "" || (req.Barcode != "" && book.Barcode != req.Barcode) {
+				t.Fatalf("barcode = %q, want %q or a generated one", book.Barcode, req.Barcode)
+			}
+			if req.HomeLocationID != uuid.Nil && (book.CurrentLocation == nil || book.CurrentLocation.ID != req.HomeLocationID ||
+				book.HomeLocation == nil || book.HomeLocation.Branch == nil) {
+				t.Fatalf("expected book shelved at its home location, got home=%+v current=%+v", book.HomeLocation, book.CurrentLocation)
+			}
+			if len(book.Contributors) != len(req.Contributors) {
+				t.Fatalf("got %d contributors, want %d", len(book.Contributors), len(req.Contributors))
+			}
+			for i, c := range book.Contributors {
+				role := req.Contributors[i].Role
+				if role == "" {
+					role = models.RoleAuthor
+				}
+				if c.Position != i || c.AuthorID != req.Contributors[i].AuthorID || c.Role != role || c.Author.Name == "" {
+					t.Fatalf("contributor %d = %+v, want %+v", i, c, req.Contributors[i])
+				}
+			}
+			if len(book.Subjects) != len(req.SubjectIDs) {
+				t.Fatalf("got %d subjects, want %d", len(book.Subjects), len(req.SubjectIDs))
+			}
+			if (req.PublisherID != uuid.Nil) != (book.Publisher != nil) || (req.SeriesID != uuid.Nil) != (book.Series != nil) ||
+				(req.WorkID != uuid.Nil) != (book.Work != nil) {
+				t.Fatalf("expected publisher, series and work to match the request, got %+v", book)
+			}
+			if book.Format != req.Format || book.Pages != req.Pages || book.Language != req.Language || book.SeriesVolume != req.SeriesVolume {
+				t.Fatalf("edition metadata not saved: %+v", book)
+			}
+		})
+	}
+}
+
+func TestGetBook(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBookService(store)
+	book := fx.Book(nil)
+	deleted := fx.Book(nil)
+	fx.SoftDelete(deleted)
+
+	tests := []struct {
+		name string
+		id   uuid.UUID
+		want error
+	}{
+		{"existing", book.ID, nil},
+		{"unknown", uuid.New(), services.ErrBookNotFound},
+		{"deleted", deleted.ID, services.ErrBookNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.GetBook(tt.id)
+			checkErr(t, err, tt.want)
+			if tt.want == nil && (len(got.Contributors) != 1 || got.Contributors[0].Author.ID != book.Contributors[0].AuthorID) {
+				t.Fatalf("expected author to be loaded, got %+v", got.Contributors)
+			}
+		})
+	}
+}
+
+func TestGetAllBooks(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBookService(store)
+	author := fx.Author()
+	for i := 0; i < 7; i++ {
+		fx.Book(author)
+	}
+
+	tests := []struct {
+		page, limit, want int
+	}{
+		{1, 5, 5},
+		{2, 5, 2},
+		{1, 10, 7},
+	}
+	for _, tt := range tests {
+		books, total, err := svc.GetAllBooks(tt.page, tt.limit)
+		checkErr(t, err, nil)
+		if total != 7 || len(books) != tt.want {
+			t.Fatalf("page %d limit %d: got %d of %d, want %d of 7", tt.page, tt.limit, len(books), total, tt.want)
+		}
+		for _, book := range books {
+			if book.Contributors[0].Author.ID != author.ID {
+				t.Fatalf("expected author to be loaded on %q", book.Title)
+			}
+		}
+	}
+}
+
+func TestUpdateBook(t *testing.T) {
+	published := time.Date(1987, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		req   func(fx *testutil.Fixtures, book *models.Book) *models.UpdateBookRequest
+		want  error
+		check func(t *testing.T, before, after *models.Book)
+	}{
+		{"title", func(*testutil.Fixtures, *models.Book) *models.UpdateBookRequest {
+			return &models.UpdateBookRequest{Title: "New Title"}
+		}, nil, func(t *testing.T, before, after *models.Book) {
+			if after.Title != "New Title" || after.ISBN != before.ISBN || after.Description != before.Description {
+				t.Fatalf("unexpected book %+v", after)
+			}
+		}},
+		{"description and published date", func(*testutil.Fixtures, *models.Book) *models.UpdateBookRequest {
+			return &models.UpdateBookRequest{Description: "Updated", PublishedAt: published}
+		}, nil, func(t *testing.T, before, after *models.Book) {
+			if after.Description != "Updated" || !after.PublishedAt.Equal(published) || after.Title != before.Title {
+				t.Fatalf("unexpected book %+v", after)
+			}
+		}},
+		{"replace contributors", func(fx *testutil.Fixtures, book *models.Book) *models.UpdateBookRequest {
+			return &models.UpdateBookRequest{Contributors: []models.ContributorRequest{
+				{AuthorID: fx.Author(func(a *models.Author) { a.Name = "Corrected" }).ID},
+				{AuthorID: book.Contributors[0].AuthorID, Role: models.RoleEditor},
+			}}
+		}, nil, func(t *testing.T, before, after *models.Book) {
+			if len(after.Contributors) != 2 || after.Contributors[0].Author.Name != "Corrected" ||
+				after.Contributors[1].AuthorID != before.Contributors[0].AuthorID || after.Contributors[1].Role != models.RoleEditor {
+				t.Fatalf("contributors not replaced: %+v", after.Contributors)
+			}
+		}},
+		{"contributors unchanged", func(*testutil.Fixtures, *models.Book) *models.UpdateBookRequest {
+			return &models.UpdateBookRequest{Title: "Retitled"}
+		}, nil, func(t *testing.T, before, after *models.Book) {
+			if len(after.Contributors) != 1 || after.Contributors[0].AuthorID != before.Contributors[0].AuthorID {
+				t.Fatalf("contributors changed: %+v", after.Contributors)
+			}
+		}},
+		{"unknown author", func(*testutil.Fixtures, *models.Book) *models.UpdateBookRequest {
+			return &models.UpdateBookRequest{Contributors: []models.ContributorRequest{{AuthorID: uuid.New()}}}
+		}, services.ErrAuthorNotFound, nil},
+		{"new ISBN", func(*testutil.Fixtures, *models.Book) *models.UpdateBookRequest {
+			return &models.UpdateBookRequest{ISBN: "9780000000999"}
+		}, nil, func(t *testing.T, _, after *models.Book) {
+			if after.ISBN != "9780000000999" {
+				t.Fatalf("ISBN = %q", after.ISBN)
+			}
+		}},
+		{"same ISBN", func(_ *testutil.Fixtures, book *models.Book) *models.UpdateBookRequest {
+			return &models.UpdateBookRequest{ISBN: book.ISBN}
+		}, nil, nil},
+		{"ISBN of another book", func(fx *testutil.Fixtures, _ *models.Book) *models.UpdateBookRequest {
+			return &models.UpdateBookRequest{ISBN: fx.Book(nil).ISBN}
+		}, services.ErrDuplicateISBN, nil},
+		{"home location of a shelved book", func(fx *testutil.Fixtures, _ *models.Book) *models.UpdateBookRequest {
+			return &models.UpdateBookRequest{HomeLocationID: fx.Location(nil).ID}
+		}, nil, func(t *testing.T, _, after *models.Book) {
+			if after.HomeLocationID == nil || after.CurrentLocationID == nil || *after.CurrentLocationID != *after.HomeLocationID {
+				t.Fatalf("expected shelved book to move with its home, got home=%v current=%v", after.HomeLocationID, after.CurrentLocationID)
+			}
+		}},
+		{"unknown home location", func(*testutil.Fixtures, *models.Book) *models.UpdateBookRequest {
+			return &models.UpdateBookRequest{HomeLocationID: uuid.New()}
+		}, services.ErrLocationNotFound, nil},
+		{"replace subjects", func(fx *testutil.Fixtures, book *models.Book) *models.UpdateBookRequest {
+			fx.Tag(book, fx.Subject(nil))
+			subject := fx.Subject(nil, func(s *models.Subject) { s.Name = "Replacement" })
+			return &models.UpdateBookRequest{SubjectIDs: []uuid.UUID{subject.ID, subject.ID}}
+		}, nil, func(t *testing.T, _, after *models.Book) {
+			if len(after.Subjects) != 1 || after.Subjects[0].Name != "Replacement" {
+				t.Fatalf("subjects not replaced: %+v", after.Subjects)
+			}
+		}},
+		{"clear subjects", func(fx *testutil.Fixtures, book *models.Book) *models.UpdateBookRequest {
+			fx.Tag(book, fx.Subject(nil))
+			return &models.UpdateBookRequest{SubjectIDs: []uuid.UUID{}}
+		}, nil, func(t *testing.T, _, after *models.Book) {
+			if len(after.Subjects) != 0 {
+				t.Fatalf("subjects not cleared: %+v", after.Subjects)
+			}
+		}},
+		{"subjects unchanged", func(fx *testutil.Fixtures, book *models.Book) *models.UpdateBookRequest {
+			fx.Tag(book, fx.Subject(nil))
+			return &models.UpdateBookRequest{Title: "Retitled"}
+		}, nil, func(t *testing.T, _, after *models.Book) {
+			if len(after.Subjects) != 1 {
+				t.Fatalf("subjects changed: %+v", after.Subjects)
+			}
+		}},
+		{"unknown subject", func(*testutil.Fixtures, *models.Book) *models.UpdateBookRequest {
+			return &models.UpdateBookRequest{SubjectIDs: []uuid.UUID{uuid.New()}}
+		}, services.ErrSubjectNotFound, nil},
+		{"edition metadata", func(fx *testutil.Fixtures, _ *models.Book) *models.UpdateBookRequest {
+			pages, volume := 412, 3
+			return &models.UpdateBookRequest{Edition: "2nd ed.", Format: models.FormatHardcover, Pages: &pages,
+				Language: "de", SeriesID: fx.Series().ID, SeriesVolume: &volume, PublisherID: fx.Publisher().ID}
+		}, nil, func(t *testing.T, _, after *models.Book) {
+			if after.Edition != "2nd ed." || after.Format != models.FormatHardcover || after.Pages != 412 || after.Language != "de" ||
+				after.SeriesVolume != 3 || after.Series == nil || after.Publisher == nil {
+				t.Fatalf("edition metadata not updated: %+v", after)
+			}
+		}},
+		{"clear series volume", func(fx *testutil.Fixtures, book *models.Book) *models.UpdateBookRequest {
+			zero := 0
+			return &models.UpdateBookRequest{SeriesVolume: &zero}
+		}, nil, func(t *testing.T, _, after *models.Book) {
+			if after.SeriesVolume != 0 {
+				t.Fatalf("series volume = %d", after.SeriesVolume)
+			}
+		}},
+		{"volume without series", func(*testutil.Fixtures, *models.Book) *models.UpdateBookRequest {
+			volume := 1
+			return &models.UpdateBookRequest{SeriesVolume: &volume}
+		}, services.ErrVolumeWithoutSeries, nil},
+		{"unknown work", func(*testutil.Fixtures, *models.Book) *models.UpdateBookRequest {
+			return &models.UpdateBookRequest{WorkID: uuid.New()}
+		}, services.ErrWorkNotFound, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewBookService(store)
+			book := fx.Book(nil)
+
+			updated, err := svc.UpdateBook(book.ID, tt.req(fx, book))
+			checkErr(t, err, tt.want)
+			if tt.check == nil {
+				return
+			}
+			tt.check(t, book, updated)
+
+			stored, err := svc.GetBook(book.ID)
+			checkErr(t, err, nil)
+			tt.check(t, book, stored)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		store, _ := setup(t)
+		_, err := services.NewBookService(store).UpdateBook(uuid.New(), &models.UpdateBookRequest{Title: "x"})
+		checkErr(t, err, services.ErrBookNotFound)
+	})
+
+	t.Run("home location of a book on loan", func(t *testing.T) {
+		store, fx := setup(t)
+		loan := fx.Borrowing(fx.Book(nil, testutil.ShelvedAt(fx.Location(nil))), nil)
+		home := fx.Location(nil)
+
+		updated, err := services.NewBookService(store).UpdateBook(loan.BookID, &models.UpdateBookRequest{HomeLocationID: home.ID})
+		checkErr(t, err, nil)
+		if *updated.HomeLocationID != home.ID || updated.CurrentLocationID != nil {
+			t.Fatalf("expected only the home to change, got home=%v current=%v", updated.HomeLocationID, updated.CurrentLocationID)
+		}
+	})
+}
+
+func TestFindBooks(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBookService(store)
+	central := fx.Branch()
+	east := fx.Branch()
+	centralShelf := fx.Location(central)
+	fx.Book(nil, testutil.ShelvedAt(centralShelf), func(b *models.Book) { b.Title = "Shelved" })
+	fx.Borrowing(fx.Book(nil, testutil.ShelvedAt(centralShelf)), nil)
+	fx.Book(nil, testutil.ShelvedAt(fx.Location(east)))
+	fx.Book(nil)
+
+	tests := []struct {
+		name   string
+		filter models.BookFilter
+		want   int64
+	}{
+		{"all", models.BookFilter{}, 4},
+		{"at central", models.BookFilter{BranchID: central.ID}, 1},
+		{"belonging to central", models.BookFilter{HomeBranchID: central.ID}, 2},
+		{"belonging to east", models.BookFilter{HomeBranchID: east.ID}, 1},
+		{"search within branch", models.BookFilter{Search: "shelved", HomeBranchID: central.ID}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, total, err := svc.FindBooks(tt.filter, 1, 10)
+			checkErr(t, err, nil)
+			if total != tt.want {
+				t.Fatalf("got %d books, want %d", total, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookCallNumbers(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBookService(store)
+	author := fx.Author()
+
+	book, err := svc.CreateBook(&models.CreateBookRequest{Title: "Go", ISBN: "9780134190440",
+		Contributors: []models.ContributorRequest{{AuthorID: author.ID}}, CallNumber: " QA76.73.G63 D58 2016 "})
+	checkErr(t, err, nil)
+	if book.CallNumber != "QA76.73.G63 D58 2016" || book.ClassificationScheme != "lc" {
+		t.Fatalf("unexpected classification %q in %q", book.CallNumber, book.ClassificationScheme)
+	}
+
+	// A scheme alone reclassifies the current call number
+	_, err = svc.UpdateBook(book.ID, &models.UpdateBookRequest{ClassificationScheme: "dewey"})
+	checkErr(t, err, services.ErrInvalidCallNumber)
+	book, err = svc.UpdateBook(book.ID, &models.UpdateBookRequest{ClassificationScheme: "local"})
+	checkErr(t, err, nil)
+	if book.ClassificationScheme != "local" || book.CallNumber != "QA76.73.G63 D58 2016" {
+		t.Fatalf("unexpected classification %q in %q", book.CallNumber, book.ClassificationScheme)
+	}
+	book, err = svc.UpdateBook(book.ID, &models.UpdateBookRequest{CallNumber: "005.133 DON"})
+	checkErr(t, err, nil)
+	if book.ClassificationScheme != "dewey" {
+		t.Fatalf("scheme = %q, want dewey", book.ClassificationScheme)
+	}
+
+	_, err = svc.CreateBook(&models.CreateBookRequest{Title: "Untitled", ISBN: "9780134190441",
+		Contributors: []models.ContributorRequest{{AuthorID: author.ID}}, ClassificationScheme: "lc"})
+	checkErr(t, err, services.ErrSchemeWithoutCallNumber)
+
+	fx.Book(nil, testutil.CallNumber("QA76.9 .K58"))
+	fx.Book(nil, testutil.CallNumber("QA76.73 .G63"))
+	books, total, err := svc.FindBooks(models.BookFilter{CallNumberFrom: "QA76", ShelfOrder: true}, 1, 10)
+	checkErr(t, err, nil)
+	if total != 2 || books[0].CallNumber != "QA76.73 .G63" {
+		t.Fatalf("unexpected shelf list %+v", books)
+	}
+
+	_, _, err = svc.FindBooks(models.BookFilter{Classification: "dewey", CallNumberTo: "QA76"}, 1, 10)
+	checkErr(t, err, services.ErrInvalidCallNumber)
+	checkErr(t, svc.ExportBooks(models.BookFilter{CallNumberFrom: "823", CallNumberTo: "QA76"}, func([]models.Book) error { return nil }),
+		services.ErrInvalidCallNumber)
+}
+
+func TestBookBarcodes(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBookService(store)
+	book := fx.Book(nil)
+	other := fx.Book(nil)
+
+	_, err := svc.UpdateBook(book.ID, &models.UpdateBookRequest{Barcode: other.Barcode})
+	checkErr(t, err, services.ErrDuplicateBarcode)
+	updated, err := svc.UpdateBook(book.ID, &models.UpdateBookRequest{Barcode: " 31234000567890 "})
+	checkErr(t, err, nil)
+	if updated.Barcode != "31234000567890" {
+		t.Fatalf("barcode = %q", updated.Barcode)
+	}
+
+	// Searching for a scanned barcode finds the item, but not a fragment
+	books, total, err := svc.SearchBooks("31234000567890", 1, 10)
+	checkErr(t, err, nil)
+	if total != 1 || books[0].ID != book.ID {
+		t.Fatalf("unexpected search result %+v", books)
+	}
+	_, total, err = svc.SearchBooks("3123400", 1, 10)
+	checkErr(t, err, nil)
+	if total != 0 {
+		t.Fatalf("expected a partial barcode to find nothing, found %d", total)
+	}
+
+	// Terminals look items up by the barcode alone
+	got, err := svc.GetBookByBarcode("31234000567890")
+	checkErr(t, err, nil)
+	if got.ID != book.ID {
+		t.Fatalf("GetBookByBarcode returned %v, want %v", got.ID, book.ID)
+	}
+	_, err = svc.GetBookByBarcode("3123400")
+	checkErr(t, err, services.ErrBookNotFound)
+	_, err = svc.GetBookByBarcode("")
+	checkErr(t, err, services.ErrBookNotFound)
+}
+
+func TestDeleteBook(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(fx *testutil.Fixtures, book *models.Book)
+		want    error
+	}{
+		{"never borrowed", func(*testutil.Fixtures, *models.Book) {}, nil},
+		{"currently borrowed", func(fx *testutil.Fixtures, book *models.Book) {
+			fx.Borrowing(book, nil)
+		}, services.ErrBookCurrentlyBorrowed},
+		{"previously borrowed", func(fx *testutil.Fixtures, book *models.Book) {
+			fx.Borrowing(book, nil, testutil.Returned)
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewBookService(store)
+			book := fx.Book(nil)
+			tt.prepare(fx, book)
+
+			checkErr(t, svc.DeleteBook(book.ID), tt.want)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		store, _ := setup(t)
+		checkErr(t, services.NewBookService(store).DeleteBook(uuid.New()), services.ErrBookNotFound)
+	})
+}
+
+func TestSearchBooks(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBookService(store)
+	tolkien := fx.Author(func(a *models.Author) { a.Name = "J. R. R. Tolkien" })
+	pratchett := fx.Author(func(a *models.Author) { a.Name = "Terry Pratchett" })
+	fx.Book(tolkien, func(b *models.Book) { b.Title = "The Hobbit"; b.ISBN = "9780547928227" })
+	christopher := fx.Author(func(a *models.Author) { a.Name = "Christopher Tolkien" })
+	gaiman := fx.Author(func(a *models.Author) { a.Name = "Neil Gaiman" })
+	fx.Book(tolkien, testutil.Credited(christopher, models.RoleEditor),
+		func(b *models.Book) { b.Title = "The Silmarillion"; b.ISBN = "9780544338012" })
+	fx.Book(pratchett, func(b *models.Book) { b.Title = "Small Gods"; b.ISBN = "9780062237378" })
+	fx.Book(pratchett, testutil.Credited(gaiman, models.RoleAuthor),
+		func(b *models.Book) { b.Title = "Good Omens"; b.ISBN = "9780060853983" })
+
+	tests := []struct {
+		name, query string
+		want        int64
+	}{
+		{"title", "hobbit", 1},
+		{"author", "TOLKIEN", 2},
+		{"co-author", "gaiman", 1},
+		{"either co-author", "pratchett", 2},
+		{"editor", "christopher", 1},
+		{"isbn", "9780062237378", 1},
+		{"shared word", "the", 2},
+		{"no match", "Discworld", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			books, total, err := svc.SearchBooks(tt.query, 1, 10)
+			checkErr(t, err, nil)
+			if total != tt.want || int64(len(books)) != tt.want {
+				t.Fatalf("got %d (%d rows), want %d", total, len(books), tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchBooks(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          string
+		ops           func(fx *testutil.Fixtures) []models.BookBatchOperation
+		wantErr       error
+		wantSucceeded int
+		wantFailed    int
+		wantBooks     int64
+	}{
+		{"atomic success", models.BatchModeAtomic, func(fx *testutil.Fixtures) []models.BookBatchOperation {
+			author := fx.Author()
+			existing := fx.Book(author)
+			return []models.BookBatchOperation{
+				{Op: "create", Create: &models.CreateBookRequest{Title: "A", ISBN: "9781000000001", Contributors: []models.ContributorRequest{{AuthorID: author.ID}}}},
+				{Op: "update", ID: existing.ID, Update: &models.UpdateBookRequest{Title: "Renamed"}},
+			}
+		}, nil, 2, 0, 2},
+		{"atomic rollback", models.BatchModeAtomic, func(fx *testutil.Fixtures) []models.BookBatchOperation {
+			author := fx.Author()
+			return []models.BookBatchOperation{
+				{Op: "create", Create: &models.CreateBookRequest{Title: "A", ISBN: "9781000000001", Contributors: []models.ContributorRequest{{AuthorID: author.ID}}}},
+				{Op: "delete", ID: uuid.New()},
+			}
+		}, services.ErrBookNotFound, 0, 0, 0},
+		{"default mode is atomic", "", func(fx *testutil.Fixtures) []models.BookBatchOperation {
+			return []models.BookBatchOperation{{Op: "update", ID: uuid.New()}}
+		}, services.ErrBatchMissingPayload, 0, 0, 0},
+		{"independent partial failure", models.BatchModeIndependent, func(fx *testutil.Fixtures) []models.BookBatchOperation {
+			author := fx.Author()
+			borrowed := fx.Book(author)
+			fx.Borrowing(borrowed, nil)
+			return []models.BookBatchOperation{
+				{Op: "create", Create: &models.CreateBookRequest{Title: "A", ISBN: "9781000000001", Contributors: []models.ContributorRequest{{AuthorID: author.ID}}}},
+				{Op: "delete", ID: borrowed.ID},
+				{Op: "delete"},
+				{Op: "create"},
+			}
+		}, nil, 1, 3, 2},
+		{"empty batch", models.BatchModeAtomic, func(*testutil.Fixtures) []models.BookBatchOperation {
+			return nil
+		}, services.ErrBatchSize, 0, 0, 0},
+		{"too many operations", models.BatchModeIndependent, func(*testutil.Fixtures) []models.BookBatchOperation {
+			return make([]models.BookBatchOperation, services.MaxBatchOperations+1)
+		}, services.ErrBatchSize, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewBookService(store)
+			ops := tt.ops(fx)
+			_, before, err := svc.GetAllBooks(1, 1)
+			checkErr(t, err, nil)
+
+			resp, err := svc.BatchBooks(&models.BookBatchRequest{Mode: tt.mode, Operations: ops})
+			checkErr(t, err, tt.wantErr)
+
+			_, after, err := svc.GetAllBooks(1, 1)
+			checkErr(t, err, nil)
+			if tt.wantErr != nil {
+				if after != before {
+					t.Fatalf("atomic batch left %d books, want %d", after, before)
+				}
+				return
+			}
+			if resp.Succeeded != tt.wantSucceeded || resp.Failed != tt.wantFailed || len(resp.Results) != len(ops) {
+				t.Fatalf("got %+v", resp)
+			}
+			if after != tt.wantBooks {
+				t.Fatalf("got %d books, want %d", after, tt.wantBooks)
+			}
+		})
+	}
+}
+
+func TestBatchBookResults(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBookService(store)
+	existing := fx.Book(nil)
+	author := fx.Author()
+	ops := []models.BookBatchOperation{
+		{Op: "update", ID: existing.ID, Update: &models.UpdateBookRequest{Title: "Renamed"}},
+		{Op: "create", Create: &models.CreateBookRequest{Title: "Copy", ISBN: existing.ISBN, Contributors: []models.ContributorRequest{{AuthorID: author.ID}}}},
+	}
+
+	// An atomic batch names the failing operation and undoes the others
+	_, err := svc.BatchBooks(&models.BookBatchRequest{Mode: models.BatchModeAtomic, Operations: ops})
+	checkErr(t, err, services.ErrDuplicateISBN)
+	var opErr *services.BatchOperationError
+	if !errors.As(err, &opErr) || opErr.Index != 1 || opErr.Op != "create" {
+		t.Fatalf("expected operation 1 to be reported, got %v", err)
+	}
+	book, err := svc.GetBook(existing.ID)
+	checkErr(t, err, nil)
+	if book.Title != existing.Title {
+		t.Fatalf("title = %q, want the update rolled back", book.Title)
+	}
+
+	// An independent batch reports each operation's outcome
+	resp, err := svc.BatchBooks(&models.BookBatchRequest{Mode: models.BatchModeIndependent, Operations: ops})
+	checkErr(t, err, nil)
+	if first := resp.Results[0]; !first.Success || first.Index != 0 || first.Error != "" {
+		t.Fatalf("unexpected result %+v", first)
+	}
+	if second := resp.Results[1]; second.Success || second.Index != 1 || second.Error != services.ErrDuplicateISBN.Error() {
+		t.Fatalf("unexpected result %+v", second)
+	}
+	book, err = svc.GetBook(existing.ID)
+	checkErr(t, err, nil)
+	if book.Title != "Renamed" {
+		t.Fatalf("title = %q, want Renamed", book.Title)
+	}
+}
+
+func TestBatchHidesInternalErrors(t *testing.T) {
+	db := testutil.NewDB(t)
+	store := gormstore.New(db)
+	svc := services.NewBookService(store)
+	author := testutil.NewFixtures(t, store).Author()
+
+	err := db.Callback().Create().Before("gorm:create").Register("test:fail_books", func(tx *gorm.DB) {
+		if tx.Statement.Table == "books" {
+			tx.AddError(errors.New(`pq: duplicate key value violates unique constraint "books_pkey"`))
+		}
+	})
+	checkErr(t, err, nil)
+	ops := []models.BookBatchOperation{
+		{Op: "create", Create: &models.CreateBookRequest{Title: "Beloved", ISBN: "9781400033416", Contributors: []models.ContributorRequest{{AuthorID: author.ID}}}},
+		{Op: "delete", ID: uuid.New()},
+	}
+
+	// Database errors are reported generically; service errors as they are
+	resp, err := svc.BatchBooks(&models.BookBatchRequest{Mode: models.BatchModeIndependent, Operations: ops})
+	checkErr(t, err, nil)
+	if got := resp.Results[0].Error; got != "internal error" {
+		t.Fatalf("database error reported as %q", got)
+	}
+	if got := resp.Results[1].Error; got != services.ErrBookNotFound.Error() {
+		t.Fatalf("service error reported as %q", got)
+	}
+
+	_, err = svc.BatchBooks(&models.BookBatchRequest{Mode: models.BatchModeAtomic, Operations: ops})
+	if err == nil || strings.Contains(err.Error(), "books_pkey") || services.KindOf(err) != services.KindInternal {
+		t.Fatalf("atomic batch failed with %v", err)
+	}
+}
+
+func TestExportBooks(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBookService(store)
+	author := fx.Author(func(a *models.Author) { a.Name = "Italo Calvino" })
+	fx.Book(author, func(b *models.Book) { b.Title = "Invisible Cities" })
+	fx.Book(nil, func(b *models.Book) { b.Title = "Other" })
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", 2},
+		{"calvino", 1},
+		{"cities", 1},
+	}
+	for _, tt := range tests {
+		var exported int
+		err := svc.ExportBooks(models.BookFilter{Search: tt.query}, func(batch []models.Book) error {
+			exported += len(batch)
+			return nil
+		})
+		checkErr(t, err, nil)
+		if exported != tt.want {
+			t.Fatalf("query %q: exported %d, want %d", tt.query, exported, tt.want)
+		}
+	}
+}
+
+func TestGetDeletedBooks(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBookService(store)
+	author := fx.Author()
+	fx.Book(author)
+	deleted := fx.Book(author)
+	fx.SoftDelete(deleted)
+	// The author of a trashed book may itself be in the trash
+	fx.SoftDelete(author)
+
+	books, total, err := svc.GetDeletedBooks(1, 10)
+	checkErr(t, err, nil)
+	if total != 1 || books[0].ID != deleted.ID {
+		t.Fatalf("got %d deleted books %+v", total, books)
+	}
+	if books[0].Contributors[0].Author.ID != author.ID {
+		t.Fatal("expected deleted author to be loaded")
+	}
+}
+
+func TestRestoreBook(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(fx *testutil.Fixtures) uuid.UUID
+		want    error
+	}{
+		{"deleted", func(fx *testutil.Fixtures) uuid.UUID {
+			book := fx.Book(nil)
+			fx.SoftDelete(book)
+			return book.ID
+		}, nil},
+		{"active", func(fx *testutil.Fixtures) uuid.UUID {
+			return fx.Book(nil).ID
+		}, services.ErrBookNotInTrash},
+		{"author deleted", func(fx *testutil.Fixtures) uuid.UUID {
+			author := fx.Author()
+			book := fx.Book(author)
+			fx.SoftDelete(book)
+			fx.SoftDelete(author)
+			return book.ID
+		}, services.ErrRestoreAuthorDeleted},
+		{"illustrator deleted", func(fx *testutil.Fixtures) uuid.UUID {
+			illustrator := fx.Author()
+			book := fx.Book(nil, testutil.Credited(illustrator, models.RoleIllustrator))
+			fx.SoftDelete(book)
+			fx.SoftDelete(illustrator)
+			return book.ID
+		}, services.ErrRestoreAuthorDeleted},
+		{"ISBN reused", func(fx *testutil.Fixtures) uuid.UUID {
+			book := fx.Book(nil)
+			fx.SoftDelete(book)
+			fx.Book(nil, func(b *models.Book) { b.ISBN = book.ISBN })
+			return book.ID
+		}, services.ErrDuplicateISBN},
+		{"barcode reused", func(fx *testutil.Fixtures) uuid.UUID {
+			book := fx.Book(nil)
+			fx.SoftDelete(book)
+			fx.Book(nil, func(b *models.Book) { b.Barcode = book.Barcode })
+			return book.ID
+		}, services.ErrDuplicateBarcode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewBookService(store)
+			id := tt.prepare(fx)
+
+			restored, err := svc.RestoreBook(id)
+			checkErr(t, err, tt.want)
+			if tt.want == nil && (len(restored.Contributors) == 0 || restored.Contributors[0].Author.ID == uuid.Nil) {
+				t.Fatal("expected author to be loaded")
+			}
+		})
+	}
+}
+
+func TestPurgeBook(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(fx *testutil.Fixtures) uuid.UUID
+		want    error
+	}{
+		{"deleted", func(fx *testutil.Fixtures) uuid.UUID {
+			book := fx.Book(nil)
+			fx.SoftDelete(book)
+			return book.ID
+		}, nil},
+		{"with borrowing history", func(fx *testutil.Fixtures) uuid.UUID {
+			book := fx.Book(nil)
+			fx.Borrowing(book, nil, testutil.Returned)
+			fx.SoftDelete(book)
+			return book.ID
+		}, services.ErrBookHasBorrowings},
+		{"with a hold", func(fx *testutil.Fixtures) uuid.UUID {
+			book := fx.Book(nil)
+			fx.Hold(book, nil, func(h *models.Hold) { h.Status = models.HoldCancelled })
+			fx.SoftDelete(book)
+			return book.ID
+		}, services.ErrBookHasBorrowings},
+		{"active", func(fx *testutil.Fixtures) uuid.UUID {
+			return fx.Book(nil).ID
+		}, services.ErrBookNotInTrash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewBookService(store)
+
+			checkErr(t, svc.PurgeBook(tt.prepare(fx)), tt.want)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/reqctx"
+	"library-management-go/internal/services"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateBlock(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBorrowerService(store).WithContext(reqctx.WithActor(context.Background(), "desk-1"))
+	borrower := fx.Borrower()
+
+	tests := []struct {
+		name       string
+		borrowerID uuid.UUID
+		req        models.CreateBorrowerBlockRequest
+		want       error
+	}{
+		{"permanent", borrower.ID, models.CreateBorrowerBlockRequest{Reason: models.BlockLostCard}, nil},
+		{"with expiry", borrower.ID, models.CreateBorrowerBlockRequest{Reason: models.BlockUnpaidFees, Overridable: true,
+			ExpiresAt: ptr(time.Now().Add(24 * time.Hour))}, nil},
+		{"expiry in the past", borrower.ID, models.CreateBorrowerBlockRequest{Reason: models.BlockBehaviour,
+			ExpiresAt: ptr(time.Now().Add(-time.Hour))}, services.ErrInvalidBlockExpiry},
+		{"unknown borrower", uuid.New(), models.CreateBorrowerBlockRequest{Reason: models.BlockOther}, services.ErrBorrowerNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block, err := svc.CreateBlock(tt.borrowerID, &tt.req)
+			checkErr(t, err, tt.want)
+			if tt.want != nil {
+				return
+			}
+			if block.CreatedBy != "desk-1" || block.Reason != tt.req.Reason || block.Overridable != tt.req.Overridable {
+				t.Fatalf("unexpected block %+v", block)
+			}
+		})
+	}
+
+	got, err := svc.GetBorrower(borrower.ID)
+	checkErr(t, err, nil)
+	if len(got.Blocks) != 2 {
+		t.Fatalf("expected the borrower to carry 2 blocks, got %d", len(got.Blocks))
+	}
+}
+
+func TestLiftBlock(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBorrowerService(store).WithContext(reqctx.WithActor(context.Background(), "desk-2"))
+	borrower := fx.Borrower()
+	block := fx.Block(borrower)
+	expired := fx.Block(borrower, func(b *models.BorrowerBlock) { b.ExpiresAt = ptr(time.Now().Add(-time.Hour)) })
+
+	lifted, err := svc.LiftBlock(borrower.ID, block.ID)
+	checkErr(t, err, nil)
+	if lifted.LiftedAt == nil || lifted.LiftedBy != "desk-2" {
+		t.Fatalf("expected block lifted by desk-2, got %+v", lifted)
+	}
+
+	_, err = svc.LiftBlock(borrower.ID, block.ID)
+	checkErr(t, err, services.ErrBlockNotActive)
+	_, err = svc.LiftBlock(borrower.ID, expired.ID)
+	checkErr(t, err, services.ErrBlockNotActive)
+	_, err = svc.LiftBlock(fx.Borrower().ID, block.ID)
+	checkErr(t, err, services.ErrBlockNotFound)
+
+	got, err := svc.GetBorrower(borrower.ID)
+	checkErr(t, err, nil)
+	if len(got.Blocks) != 0 {
+		t.Fatalf("expected no blocks in force, got %+v", got.Blocks)
+	}
+
+	blocks, err := svc.GetBlocks(borrower.ID)
+	checkErr(t, err, nil)
+	if len(blocks) != 2 {
+		t.Fatalf("expected block history of 2, got %d", len(blocks))
+	}
+}
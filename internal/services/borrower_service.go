@@ -1,37 +1,113 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"time"
 
+	"library-management-go/internal/cardnumber"
 	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/reqctx"
 
 	"github.com/google/uuid"
-	"gorm.io/gorm"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type BorrowerService struct {
-	db *gorm.DB
+	store repository.Store
+	ctx   context.Context
 }
 
-func NewBorrowerService(db *gorm.DB) *BorrowerService {
-	return &BorrowerService{db: db}
+func NewBorrowerService(store repository.Store) *BorrowerService {
+	return &BorrowerService{store: store, ctx: context.Background()}
+}
+
+// WithContext returns a copy of the service whose queries carry ctx, so the
+// request's actor and ID reach the audit log.
+func (s *BorrowerService) WithContext(ctx context.Context) *BorrowerService {
+	return &BorrowerService{store: s.store.WithContext(ctx), ctx: ctx}
+}
+
+// checkEmail returns ErrDuplicateEmail if an active borrower other than
+// excludeID already uses email.
+func (s *BorrowerService) checkEmail(email string, excludeID uuid.UUID) error {
+	if _, err := s.store.Borrowers().FindByEmail(email, excludeID); err == nil {
+		return ErrDuplicateEmail
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// newCardNumber generates a card number no other borrower holds.
+func (s *BorrowerService) newCardNumber() (string, error) {
+	for attempt := 0; attempt < 5; attempt++ {
+		number, err := cardnumber.Generate()
+		if err != nil {
+			return "", err
+		}
+		if _, err := s.store.Borrowers().FindByCardNumber(number); errors.Is(err, repository.ErrNotFound) {
+			return number, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+	return "", errors.New("failed to generate a unique card number")
+}
+
+// membershipTerm returns the expiry of a membership in category starting
+// at start, or nil if memberships in the category do not expire.
+func membershipTerm(category *models.BorrowerCategory, start time.Time) *time.Time {
+	if category == nil || category.MembershipDays == 0 {
+		return nil
+	}
+	expiresAt := start.AddDate(0, 0, category.MembershipDays)
+	return &expiresAt
 }
 
 func (s *BorrowerService) CreateBorrower(req *models.CreateBorrowerRequest) (*models.Borrower, error) {
 	// Check if email already exists
-	var existingBorrower models.Borrower
-	if err := s.db.Where("email = ?", req.Email).First(&existingBorrower).Error; err == nil {
-		return nil, errors.New("borrower with this email already exists")
+	if err := s.checkEmail(req.Email, uuid.Nil); err != nil {
+		return nil, err
 	}
 
+	now := time.Now()
 	borrower := &models.Borrower{
-		Name:    req.Name,
-		Email:   req.Email,
-		Phone:   req.Phone,
-		Address: req.Address,
+		Name:            req.Name,
+		Email:           req.Email,
+		Phone:           req.Phone,
+		Address:         req.Address,
+		Status:          models.BorrowerActive,
+		MembershipStart: now,
+		KeepHistory:     req.KeepHistory,
+	}
+
+	// Check if category exists (if provided); it sets the membership term
+	if req.CategoryID != uuid.Nil {
+		category, err := s.GetCategory(req.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		borrower.CategoryID = &category.ID
+		borrower.Category = category
+		borrower.MembershipExpiresAt = membershipTerm(category, now)
 	}
 
-	if err := s.db.Create(borrower).Error; err != nil {
+	if req.MembershipExpiresAt != nil {
+		if !req.MembershipExpiresAt.After(now) {
+			return nil, ErrInvalidMembershipExpiry
+		}
+		borrower.MembershipExpiresAt = req.MembershipExpiresAt
+	}
+
+	cardNumber, err := s.newCardNumber()
+	if err != nil {
+		return nil, err
+	}
+	borrower.CardNumber = cardNumber
+
+	if err := s.store.Borrowers().Create(borrower); err != nil {
 		return nil, err
 	}
 
@@ -39,49 +115,116 @@ func (s *BorrowerService) CreateBorrower(req *models.CreateBorrowerRequest) (*mo
 }
 
 func (s *BorrowerService) GetBorrower(id uuid.UUID) (*models.Borrower, error) {
-	var borrower models.Borrower
-	if err := s.db.First(&borrower, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("borrower not found")
+	borrower, err := s.store.Borrowers().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBorrowerNotFound
 		}
 		return nil, err
 	}
-	return &borrower, nil
+	return s.withBlocks(borrower)
 }
 
-func (s *BorrowerService) GetAllBorrowers(page, limit int) ([]models.Borrower, int64, error) {
-	var borrowers []models.Borrower
-	var total int64
+// withBlocks loads the blocks currently in force on borrower.
+func (s *BorrowerService) withBlocks(borrower *models.Borrower) (*models.Borrower, error) {
+	blocks, err := s.store.BorrowerBlocks().ListActive(borrower.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	borrower.Blocks = blocks
+	return borrower, nil
+}
 
-	offset := (page - 1) * limit
+// GetBorrowerByCard looks a borrower up by the card number scanned at the
+// desk, rejecting numbers whose check digit is wrong.
+func (s *BorrowerService) GetBorrowerByCard(cardNumber string) (*models.Borrower, error) {
+	if !cardnumber.Valid(cardNumber) {
+		return nil, ErrInvalidCardNumber
+	}
+
+	borrower, err := s.store.Borrowers().FindByCardNumber(cardNumber)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBorrowerNotFound
+		}
+		return nil, err
+	}
+	return s.withBlocks(borrower)
+}
 
-	// Count total records
-	if err := s.db.Model(&models.Borrower{}).Count(&total).Error; err != nil {
-		return nil, 0, err
+// GetStanding reports whether the borrower may borrow and renew, applying
+// the checks BorrowBook and RenewBorrowing make of every borrower, along
+// with the loans and fines that count against them.
+func (s *BorrowerService) GetStanding(id uuid.UUID) (*models.BorrowerStanding, error) {
+	borrower, err := s.GetBorrower(id)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := tenantSettings(s.store)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	standing := &models.BorrowerStanding{MaxActiveLoans: settings.MaxActiveLoans}
+	if borrower.Category != nil && borrower.Category.MaxActiveLoans > 0 {
+		standing.MaxActiveLoans = borrower.Category.MaxActiveLoans
+	}
+	for _, block := range borrower.Blocks {
+		if block.Reason == models.BlockLostCard {
+			standing.CardLost = true
+		}
+	}
+	if standing.ActiveLoans, err = s.store.Borrowings().CountActiveByBorrower(id); err != nil {
+		return nil, err
+	}
+	if standing.OverdueLoans, err = s.store.Borrowings().CountOverdueByBorrower(id, now); err != nil {
+		return nil, err
+	}
+	if standing.OutstandingFines, err = s.store.Fines().SumOutstanding(id); err != nil {
+		return nil, err
 	}
 
-	// Get borrowers with pagination
-	if err := s.db.Offset(offset).Limit(limit).Find(&borrowers).Error; err != nil {
-		return nil, 0, err
+	// Check the borrower in the order BorrowBook does; renewals only
+	// depend on membership and blocks
+	reason := checkMembership(borrower, now)
+	if reason == nil {
+		reason = checkBlocks(borrower.Blocks, false)
+	}
+	standing.CanRenew = reason == nil
+	if reason == nil && settings.BlockOverdueBorrowers && standing.OverdueLoans > 0 {
+		reason = ErrBorrowerHasOverdueBooks
+	}
+	if reason == nil && standing.ActiveLoans >= int64(standing.MaxActiveLoans) {
+		reason = ErrBorrowingLimitReached
+	}
+	standing.CanBorrow = reason == nil
+	if reason != nil {
+		standing.Reason = reason.Error()
 	}
 
-	return borrowers, total, nil
+	return standing, nil
+}
+
+func (s *BorrowerService) GetAllBorrowers(page, limit int) ([]models.Borrower, int64, error) {
+	offset := (page - 1) * limit
+	return s.store.Borrowers().List(offset, limit)
 }
 
 func (s *BorrowerService) UpdateBorrower(id uuid.UUID, req *models.UpdateBorrowerRequest) (*models.Borrower, error) {
-	var borrower models.Borrower
-	if err := s.db.First(&borrower, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("borrower not found")
-		}
+	borrower, err := s.GetBorrower(id)
+	if err != nil {
 		return nil, err
 	}
+	if borrower.Status == models.BorrowerErased {
+		return nil, ErrBorrowerErased
+	}
 
 	// Check if email already exists (if provided and different)
 	if req.Email != "" && req.Email != borrower.Email {
-		var existingBorrower models.Borrower
-		if err := s.db.Where("email = ? AND id != ?", req.Email, id).First(&existingBorrower).Error; err == nil {
-			return nil, errors.New("borrower with this email already exists")
+		if err := s.checkEmail(req.Email, id); err != nil {
+			return nil, err
 		}
 		borrower.Email = req.Email
 	}
@@ -96,61 +239,485 @@ func (s *BorrowerService) UpdateBorrower(id uuid.UUID, req *models.UpdateBorrowe
 	if req.Address != "" {
 		borrower.Address = req.Address
 	}
+	if req.Status != "" {
+		borrower.Status = req.Status
+	}
+	if req.KeepHistory != nil {
+		borrower.KeepHistory = *req.KeepHistory
+	}
+
+	// Check if category exists (if provided and different)
+	if req.CategoryID != uuid.Nil && (borrower.CategoryID == nil || *borrower.CategoryID != req.CategoryID) {
+		category, err := s.GetCategory(req.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		borrower.CategoryID = &category.ID
+		borrower.Category = category
+	}
 
-	if err := s.db.Save(&borrower).Error; err != nil {
+	if err := s.store.Borrowers().Update(borrower); err != nil {
 		return nil, err
 	}
 
-	return &borrower, nil
+	return borrower, nil
 }
 
-func (s *BorrowerService) DeleteBorrower(id uuid.UUID) error {
-	var borrower models.Borrower
-	if err := s.db.First(&borrower, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("borrower not found")
+// UpdateContact changes the contact details and reading-history choice
+// borrowers may maintain themselves through the self-service API.
+func (s *BorrowerService) UpdateContact(id uuid.UUID, req *models.UpdateContactRequest) (*models.Borrower, error) {
+	return s.UpdateBorrower(id, &models.UpdateBorrowerRequest{
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Address:     req.Address,
+		KeepHistory: req.KeepHistory,
+	})
+}
+
+// SetPIN sets the PIN the borrower signs in to the self-service API with.
+func (s *BorrowerService) SetPIN(id uuid.UUID, req *models.SetPINRequest) error {
+	borrower, err := s.GetBorrower(id)
+	if err != nil {
+		return err
+	}
+	if borrower.Status == models.BorrowerErased {
+		return ErrBorrowerErased
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.PIN), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	borrower.PINHash = string(hash)
+	// A new PIN signs out existing sessions and lifts any lockout
+	borrower.PINVersion++
+	borrower.FailedPINAttempts = 0
+	borrower.PINLockedUntil = nil
+
+	return s.store.Borrowers().Update(borrower)
+}
+
+const (
+	// MaxPINAttempts wrong PINs in a row lock a card out of PIN checks for
+	// PINLockout, so short PINs cannot be guessed.
+	MaxPINAttempts = 5
+	PINLockout     = 15 * time.Minute
+)
+
+// Authenticate returns the borrower holding the card if pin is theirs.
+// Every failure, including a locked-out card, is reported as
+// ErrInvalidCredentials so callers cannot probe for card numbers.
+func (s *BorrowerService) Authenticate(req *models.LoginRequest) (*models.Borrower, error) {
+	if !cardnumber.Valid(req.CardNumber) {
+		return nil, ErrInvalidCredentials
+	}
+
+	borrower, err := s.store.Borrowers().FindByCardNumber(req.CardNumber)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if borrower.PINHash == "" || (borrower.PINLockedUntil != nil && now.Before(*borrower.PINLockedUntil)) {
+		return nil, ErrInvalidCredentials
+	}
+	if bcrypt.CompareHashAndPassword([]byte(borrower.PINHash), []byte(req.PIN)) != nil {
+		if err := s.store.Borrowers().RecordPINFailure(borrower.ID, MaxPINAttempts, now.Add(PINLockout)); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+	if borrower.FailedPINAttempts > 0 {
+		if err := s.store.Borrowers().ResetPINFailures(borrower.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return borrower, nil
+}
+
+// CheckSession returns ErrInvalidCredentials unless a self-service session
+// begun at pinVersion is still good: the borrower must still exist with a
+// PIN, not be erased, and not have changed PIN since.
+func (s *BorrowerService) CheckSession(id uuid.UUID, pinVersion int) error {
+	borrower, err := s.store.Borrowers().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidCredentials
 		}
 		return err
 	}
+	if borrower.Status == models.BorrowerErased || borrower.PINHash == "" || borrower.PINVersion != pinVersion {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+func (s *BorrowerService) DeleteBorrower(id uuid.UUID) error {
+	borrower, err := s.GetBorrower(id)
+	if err != nil {
+		return err
+	}
 
 	// Check if borrower has active borrowings
-	var borrowingCount int64
-	if err := s.db.Model(&models.Borrowing{}).Where("borrower_id = ? AND status = 'borrowed'", id).Count(&borrowingCount).Error; err != nil {
+	borrowingCount, err := s.store.Borrowings().CountActiveByBorrower(id)
+	if err != nil {
 		return err
 	}
 
 	if borrowingCount > 0 {
-		return errors.New("cannot delete borrower with active borrowings")
+		return ErrBorrowerHasActiveBorrowings
+	}
+
+	// Waiting holds would otherwise keep the book for a borrower who can
+	// no longer collect it
+	return s.store.Transaction(func(tx repository.Store) error {
+		holds, err := tx.Holds().ListByBorrower(id, true)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for i := range holds {
+			holds[i].Status = models.HoldCancelled
+			holds[i].CancelledAt = &now
+			if err := tx.Holds().Update(&holds[i]); err != nil {
+				return err
+			}
+		}
+		return tx.Borrowers().Delete(borrower)
+	})
+}
+
+func (s *BorrowerService) SearchBorrowers(query string, page, limit int) ([]models.Borrower, int64, error) {
+	offset := (page - 1) * limit
+	return s.store.Borrowers().Search(query, offset, limit)
+}
+
+func (s *BorrowerService) BatchBorrowers(req *models.BorrowerBatchRequest) (*models.BatchResponse, error) {
+	ops := make([]string, len(req.Operations))
+	for i, op := range req.Operations {
+		ops[i] = op.Op
 	}
 
-	if err := s.db.Delete(&borrower).Error; err != nil {
+	return runBatch(s.store, req.Mode, ops, func(tx repository.Store, i int) (interface{}, error) {
+		op := req.Operations[i]
+		svc := NewBorrowerService(tx)
+
+		switch op.Op {
+		case "create":
+			if op.Create == nil {
+				return nil, ErrBatchMissingPayload
+			}
+			return svc.CreateBorrower(op.Create)
+		case "update":
+			if op.ID == uuid.Nil {
+				return nil, ErrBatchMissingID
+			}
+			if op.Update == nil {
+				return nil, ErrBatchMissingPayload
+			}
+			return svc.UpdateBorrower(op.ID, op.Update)
+		default:
+			if op.ID == uuid.Nil {
+				return nil, ErrBatchMissingID
+			}
+			return nil, svc.DeleteBorrower(op.ID)
+		}
+	})
+}
+
+// ExportBorrowers streams the borrowers matching query (all borrowers when
+// empty) to fn in batches of exportBatchSize.
+func (s *BorrowerService) ExportBorrowers(query string, fn func([]models.Borrower) error) error {
+	return s.store.Borrowers().Export(query, exportBatchSize, fn)
+}
+
+func (s *BorrowerService) GetDeletedBorrowers(page, limit int) ([]models.Borrower, int64, error) {
+	offset := (page - 1) * limit
+	return s.store.Borrowers().ListDeleted(offset, limit)
+}
+
+func (s *BorrowerService) getDeletedBorrower(id uuid.UUID) (*models.Borrower, error) {
+	borrower, err := s.store.Borrowers().GetDeleted(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBorrowerNotInTrash
+		}
+		return nil, err
+	}
+	return borrower, nil
+}
+
+func (s *BorrowerService) RestoreBorrower(id uuid.UUID) (*models.Borrower, error) {
+	borrower, err := s.getDeletedBorrower(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The email may have been reused since the borrower was deleted
+	if err := s.checkEmail(borrower.Email, borrower.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Borrowers().Restore(borrower); err != nil {
+		return nil, err
+	}
+
+	return s.GetBorrower(id)
+}
+
+func (s *BorrowerService) PurgeBorrower(id uuid.UUID) error {
+	borrower, err := s.getDeletedBorrower(id)
+	if err != nil {
+		return err
+	}
+
+	// Loans, holds, fines and blocks reference the borrower and must be kept
+	referenced, err := s.store.Borrowers().Referenced(id)
+	if err != nil {
 		return err
 	}
 
+	if referenced {
+		return ErrBorrowerHasBorrowings
+	}
+
+	return s.store.Borrowers().Purge(borrower)
+}
+
+// RenewMembership extends the borrower's membership by one term of their
+// category, counted from the current expiry or from today if it has
+// already passed, or to req.ExpiresAt when given. An expired borrower
+// becomes active again; a suspended one stays suspended.
+func (s *BorrowerService) RenewMembership(id uuid.UUID, req *models.RenewMembershipRequest) (*models.Borrower, error) {
+	borrower, err := s.GetBorrower(id)
+	if err != nil {
+		return nil, err
+	}
+	if borrower.Status == models.BorrowerErased {
+		return nil, ErrBorrowerErased
+	}
+
+	now := time.Now()
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			return nil, ErrInvalidMembershipExpiry
+		}
+		borrower.MembershipExpiresAt = req.ExpiresAt
+	} else {
+		from := now
+		if borrower.MembershipExpiresAt != nil && borrower.MembershipExpiresAt.After(now) {
+			from = *borrower.MembershipExpiresAt
+		}
+		borrower.MembershipExpiresAt = membershipTerm(borrower.Category, from)
+	}
+
+	if borrower.Status == models.BorrowerExpired {
+		borrower.Status = models.BorrowerActive
+	}
+
+	if err := s.store.Borrowers().Update(borrower); err != nil {
+		return nil, err
+	}
+
+	return borrower, nil
+}
+
+// UpdateExpiredStatus marks active borrowers whose membership has expired.
+func (s *BorrowerService) UpdateExpiredStatus() error {
+	return s.store.Borrowers().MarkExpired(time.Now())
+}
+
+// checkMembership returns an error unless borrower's membership is in good
+// standing at now.
+func checkMembership(borrower *models.Borrower, now time.Time) error {
+	switch {
+	case borrower.Status == models.BorrowerSuspended:
+		return ErrBorrowerSuspended
+	case borrower.Status == models.BorrowerExpired:
+		return ErrMembershipExpired
+	case borrower.Status == models.BorrowerErased:
+		return ErrBorrowerErased
+	case borrower.MembershipExpiresAt != nil && !now.Before(*borrower.MembershipExpiresAt):
+		return ErrMembershipExpired
+	}
 	return nil
 }
 
-func (s *BorrowerService) SearchBorrowers(query string, page, limit int) ([]models.Borrower, int64, error) {
-	var borrowers []models.Borrower
-	var total int64
+// checkCategoryCode returns ErrDuplicateCategoryCode if an active category
+// other than excludeID already uses code.
+func (s *BorrowerService) checkCategoryCode(code string, excludeID uuid.UUID) error {
+	if _, err := s.store.BorrowerCategories().FindByCode(code, excludeID); err == nil {
+		return ErrDuplicateCategoryCode
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return nil
+}
 
+func (s *BorrowerService) CreateCategory(req *models.CreateBorrowerCategoryRequest) (*models.BorrowerCategory, error) {
+	// Check if code already exists
+	if err := s.checkCategoryCode(req.Code, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	category := &models.BorrowerCategory{
+		Code:           req.Code,
+		Name:           req.Name,
+		MembershipDays: req.MembershipDays,
+		MaxActiveLoans: req.MaxActiveLoans,
+	}
+
+	if err := s.store.BorrowerCategories().Create(category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *BorrowerService) GetCategory(id uuid.UUID) (*models.BorrowerCategory, error) {
+	category, err := s.store.BorrowerCategories().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+func (s *BorrowerService) GetCategories(page, limit int) ([]models.BorrowerCategory, int64, error) {
 	offset := (page - 1) * limit
-	searchQuery := "%" + query + "%"
+	return s.store.BorrowerCategories().List(offset, limit)
+}
+
+// UpdateCategory changes a category's rules. Existing memberships keep
+// their expiry until they are next renewed.
+func (s *BorrowerService) UpdateCategory(id uuid.UUID, req *models.UpdateBorrowerCategoryRequest) (*models.BorrowerCategory, error) {
+	category, err := s.GetCategory(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if code already exists (if provided and different)
+	if req.Code != "" && req.Code != category.Code {
+		if err := s.checkCategoryCode(req.Code, id); err != nil {
+			return nil, err
+		}
+		category.Code = req.Code
+	}
+
+	// Update fields
+	if req.Name != "" {
+		category.Name = req.Name
+	}
+	if req.MembershipDays != nil {
+		category.MembershipDays = *req.MembershipDays
+	}
+	if req.MaxActiveLoans != nil {
+		category.MaxActiveLoans = *req.MaxActiveLoans
+	}
 
-	// Count total records
-	if err := s.db.Model(&models.Borrower{}).
-		Where("name ILIKE ? OR email ILIKE ? OR phone ILIKE ?", searchQuery, searchQuery, searchQuery).
-		Count(&total).Error; err != nil {
-		return nil, 0, err
+	if err := s.store.BorrowerCategories().Update(category); err != nil {
+		return nil, err
 	}
 
-	// Get borrowers with pagination
-	if err := s.db.Where("name ILIKE ? OR email ILIKE ? OR phone ILIKE ?", searchQuery, searchQuery, searchQuery).
-		Offset(offset).
-		Limit(limit).
-		Find(&borrowers).Error; err != nil {
-		return nil, 0, err
+	return category, nil
+}
+
+func (s *BorrowerService) DeleteCategory(id uuid.UUID) error {
+	category, err := s.GetCategory(id)
+	if err != nil {
+		return err
 	}
 
-	return borrowers, total, nil
+	// Check if any borrower belongs to the category
+	borrowerCount, err := s.store.Borrowers().CountByCategory(id)
+	if err != nil {
+		return err
+	}
+
+	if borrowerCount > 0 {
+		return ErrCategoryInUse
+	}
+
+	return s.store.BorrowerCategories().Delete(category)
+}
+
+// CreateBlock places a block on the borrower, recording the request's actor
+// as its creator.
+func (s *BorrowerService) CreateBlock(borrowerID uuid.UUID, req *models.CreateBorrowerBlockRequest) (*models.BorrowerBlock, error) {
+	// Check if borrower exists
+	if _, err := s.GetBorrower(borrowerID); err != nil {
+		return nil, err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidBlockExpiry
+	}
+
+	block := &models.BorrowerBlock{
+		BorrowerID:  borrowerID,
+		Reason:      req.Reason,
+		Note:        req.Note,
+		CreatedBy:   reqctx.Actor(s.ctx),
+		Overridable: req.Overridable,
+		ExpiresAt:   req.ExpiresAt,
+	}
+
+	if err := s.store.BorrowerBlocks().Create(block); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// GetBlocks lists every block placed on the borrower, including lifted and
+// expired ones.
+func (s *BorrowerService) GetBlocks(borrowerID uuid.UUID) ([]models.BorrowerBlock, error) {
+	// Check if borrower exists
+	if _, err := s.GetBorrower(borrowerID); err != nil {
+		return nil, err
+	}
+
+	return s.store.BorrowerBlocks().ListByBorrower(borrowerID)
+}
+
+// LiftBlock ends a block before its expiry. The block is kept, recording
+// who lifted it and when.
+func (s *BorrowerService) LiftBlock(borrowerID, blockID uuid.UUID) (*models.BorrowerBlock, error) {
+	block, err := s.store.BorrowerBlocks().Get(borrowerID, blockID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBlockNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if block.LiftedAt != nil || (block.ExpiresAt != nil && !now.Before(*block.ExpiresAt)) {
+		return nil, ErrBlockNotActive
+	}
+
+	block.LiftedAt = &now
+	block.LiftedBy = reqctx.Actor(s.ctx)
+	if err := s.store.BorrowerBlocks().Update(block); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// checkBlocks returns ErrBorrowerBlocked if any of blocks stands in the way.
+// With override set, only blocks that cannot be overridden do.
+func checkBlocks(blocks []models.BorrowerBlock, override bool) error {
+	for _, block := range blocks {
+		if !override || !block.Overridable {
+			return ErrBorrowerBlocked
+		}
+	}
+	return nil
 }
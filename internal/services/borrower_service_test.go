@@ -0,0 +1,456 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateBorrower(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		prepare func(fx *testutil.Fixtures)
+		want    error
+	}{
+		{"valid", "reader@example.com", func(*testutil.Fixtures) {}, nil},
+		{"duplicate email", "reader@example.com", func(fx *testutil.Fixtures) {
+			fx.Borrower(func(b *models.Borrower) { b.Email = "reader@example.com" })
+		}, services.ErrDuplicateEmail},
+		{"email of deleted borrower", "reader@example.com", func(fx *testutil.Fixtures) {
+			fx.SoftDelete(fx.Borrower(func(b *models.Borrower) { b.Email = "reader@example.com" }))
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewBorrowerService(store)
+			tt.prepare(fx)
+
+			borrower, err := svc.CreateBorrower(&models.CreateBorrowerRequest{
+				Name:    "Reader",
+				Email:   tt.email,
+				Phone:   "555-0199",
+				Address: "1 Main St",
+			})
+			checkErr(t, err, tt.want)
+			if tt.want == nil && (borrower.ID == uuid.Nil || borrower.Email != tt.email) {
+				t.Fatalf("unexpected borrower %+v", borrower)
+			}
+		})
+	}
+}
+
+func TestGetBorrower(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBorrowerService(store)
+	borrower := fx.Borrower()
+	deleted := fx.Borrower()
+	fx.SoftDelete(deleted)
+
+	tests := []struct {
+		name string
+		id   uuid.UUID
+		want error
+	}{
+		{"existing", borrower.ID, nil},
+		{"unknown", uuid.New(), services.ErrBorrowerNotFound},
+		{"deleted", deleted.ID, services.ErrBorrowerNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.GetBorrower(tt.id)
+			checkErr(t, err, tt.want)
+			if tt.want == nil && got.Email != borrower.Email {
+				t.Fatalf("unexpected borrower %+v", got)
+			}
+		})
+	}
+}
+
+func TestGetAllBorrowers(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBorrowerService(store)
+	for i := 0; i < 3; i++ {
+		fx.Borrower()
+	}
+
+	tests := []struct {
+		page, limit, want int
+	}{
+		{1, 2, 2},
+		{2, 2, 1},
+		{1, 10, 3},
+	}
+	for _, tt := range tests {
+		borrowers, total, err := svc.GetAllBorrowers(tt.page, tt.limit)
+		checkErr(t, err, nil)
+		if total != 3 || len(borrowers) != tt.want {
+			t.Fatalf("page %d limit %d: got %d of %d, want %d of 3", tt.page, tt.limit, len(borrowers), total, tt.want)
+		}
+	}
+}
+
+func TestUpdateBorrower(t *testing.T) {
+	tests := []struct {
+		name  string
+		req   func(fx *testutil.Fixtures) *models.UpdateBorrowerRequest
+		want  error
+		check func(t *testing.T, before, after *models.Borrower)
+	}{
+		{"contact details", func(*testutil.Fixtures) *models.UpdateBorrowerRequest {
+			return &models.UpdateBorrowerRequest{Phone: "555-9999", Address: "2 New Rd"}
+		}, nil, func(t *testing.T, before, after *models.Borrower) {
+			if after.Phone != "555-9999" || after.Address != "2 New Rd" || after.Name != before.Name || after.Email != before.Email {
+				t.Fatalf("unexpected borrower %+v", after)
+			}
+		}},
+		{"name", func(*testutil.Fixtures) *models.UpdateBorrowerRequest {
+			return &models.UpdateBorrowerRequest{Name: "Renamed"}
+		}, nil, func(t *testing.T, _, after *models.Borrower) {
+			if after.Name != "Renamed" {
+				t.Fatalf("name = %q", after.Name)
+			}
+		}},
+		{"new email", func(*testutil.Fixtures) *models.UpdateBorrowerRequest {
+			return &models.UpdateBorrowerRequest{Email: "new@example.com"}
+		}, nil, func(t *testing.T, _, after *models.Borrower) {
+			if after.Email != "new@example.com" {
+				t.Fatalf("email = %q", after.Email)
+			}
+		}},
+		{"email of another borrower", func(fx *testutil.Fixtures) *models.UpdateBorrowerRequest {
+			return &models.UpdateBorrowerRequest{Email: fx.Borrower().Email}
+		}, services.ErrDuplicateEmail, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewBorrowerService(store)
+			borrower := fx.Borrower()
+
+			updated, err := svc.UpdateBorrower(borrower.ID, tt.req(fx))
+			checkErr(t, err, tt.want)
+			if tt.check == nil {
+				return
+			}
+			tt.check(t, borrower, updated)
+
+			stored, err := svc.GetBorrower(borrower.ID)
+			checkErr(t, err, nil)
+			tt.check(t, borrower, stored)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		store, _ := setup(t)
+		_, err := services.NewBorrowerService(store).UpdateBorrower(uuid.New(), &models.UpdateBorrowerRequest{Name: "x"})
+		checkErr(t, err, services.ErrBorrowerNotFound)
+	})
+}
+
+func TestDeleteBorrower(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(fx *testutil.Fixtures, borrower *models.Borrower)
+		want    error
+	}{
+		{"no borrowings", func(*testutil.Fixtures, *models.Borrower) {}, nil},
+		{"active borrowing", func(fx *testutil.Fixtures, borrower *models.Borrower) {
+			fx.Borrowing(nil, borrower)
+		}, services.ErrBorrowerHasActiveBorrowings},
+		{"returned borrowing", func(fx *testutil.Fixtures, borrower *models.Borrower) {
+			fx.Borrowing(nil, borrower, testutil.Returned)
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewBorrowerService(store)
+			borrower := fx.Borrower()
+			tt.prepare(fx, borrower)
+
+			checkErr(t, svc.DeleteBorrower(borrower.ID), tt.want)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		store, _ := setup(t)
+		checkErr(t, services.NewBorrowerService(store).DeleteBorrower(uuid.New()), services.ErrBorrowerNotFound)
+	})
+
+	t.Run("waiting hold", func(t *testing.T) {
+		store, fx := setup(t)
+		book := fx.Book(nil)
+		holder := fx.Borrower()
+		hold := fx.Hold(book, holder)
+
+		checkErr(t, services.NewBorrowerService(store).DeleteBorrower(holder.ID), nil)
+		cancelled, err := store.Holds().Get(hold.ID)
+		checkErr(t, err, nil)
+		if cancelled.Status != models.HoldCancelled || cancelled.CancelledAt == nil {
+			t.Fatalf("hold left %s", cancelled.Status)
+		}
+
+		// The book is free for the next borrower
+		_, err = services.NewBorrowingService(store).BorrowBook(&models.BorrowBookRequest{
+			BookID: book.ID, BorrowerID: fx.Borrower().ID, DueDate: time.Now().Add(24 * time.Hour),
+		})
+		checkErr(t, err, nil)
+	})
+}
+
+func TestSearchBorrowers(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBorrowerService(store)
+	fx.Borrower(func(b *models.Borrower) { b.Name = "Hermione Granger"; b.Email = "hermione@hogwarts.example" })
+	fx.Borrower(func(b *models.Borrower) { b.Name = "Matilda Wormwood"; b.Phone = "555-1988" })
+
+	tests := []struct {
+		name, query string
+		want        int64
+	}{
+		{"name", "granger", 1},
+		{"email", "HOGWARTS", 1},
+		{"phone", "1988", 1},
+		{"no match", "Dursley", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			borrowers, total, err := svc.SearchBorrowers(tt.query, 1, 10)
+			checkErr(t, err, nil)
+			if total != tt.want || int64(len(borrowers)) != tt.want {
+				t.Fatalf("got %d (%d rows), want %d", total, len(borrowers), tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchBorrowers(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          string
+		ops           func(fx *testutil.Fixtures) []models.BorrowerBatchOperation
+		wantErr       error
+		wantSucceeded int
+		wantFailed    int
+		wantBorrowers int64
+	}{
+		{"atomic delete", models.BatchModeAtomic, func(fx *testutil.Fixtures) []models.BorrowerBatchOperation {
+			return []models.BorrowerBatchOperation{
+				{Op: "delete", ID: fx.Borrower().ID},
+				{Op: "delete", ID: fx.Borrower().ID},
+			}
+		}, nil, 2, 0, 0},
+		{"atomic rollback on rule violation", models.BatchModeAtomic, func(fx *testutil.Fixtures) []models.BorrowerBatchOperation {
+			active := fx.Borrower()
+			fx.Borrowing(nil, active)
+			return []models.BorrowerBatchOperation{
+				{Op: "delete", ID: fx.Borrower().ID},
+				{Op: "delete", ID: active.ID},
+			}
+		}, services.ErrBorrowerHasActiveBorrowings, 0, 0, 2},
+		{"independent", models.BatchModeIndependent, func(fx *testutil.Fixtures) []models.BorrowerBatchOperation {
+			existing := fx.Borrower()
+			return []models.BorrowerBatchOperation{
+				{Op: "create", Create: &models.CreateBorrowerRequest{Name: "New", Email: "new@example.com"}},
+				{Op: "create", Create: &models.CreateBorrowerRequest{Name: "Dup", Email: existing.Email}},
+				{Op: "update", ID: existing.ID, Update: &models.UpdateBorrowerRequest{Name: "Updated"}},
+			}
+		}, nil, 2, 1, 2},
+		{"empty batch", models.BatchModeIndependent, func(*testutil.Fixtures) []models.BorrowerBatchOperation {
+			return nil
+		}, services.ErrBatchSize, 0, 0, 0},
+		{"too many operations", models.BatchModeAtomic, func(*testutil.Fixtures) []models.BorrowerBatchOperation {
+			return make([]models.BorrowerBatchOperation, services.MaxBatchOperations+1)
+		}, services.ErrBatchSize, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewBorrowerService(store)
+			ops := tt.ops(fx)
+
+			resp, err := svc.BatchBorrowers(&models.BorrowerBatchRequest{Mode: tt.mode, Operations: ops})
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr == nil && (resp.Succeeded != tt.wantSucceeded || resp.Failed != tt.wantFailed) {
+				t.Fatalf("got %+v", resp)
+			}
+
+			_, total, err := svc.GetAllBorrowers(1, 1)
+			checkErr(t, err, nil)
+			if total != tt.wantBorrowers {
+				t.Fatalf("got %d borrowers, want %d", total, tt.wantBorrowers)
+			}
+		})
+	}
+}
+
+func TestExportBorrowers(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBorrowerService(store)
+	fx.Borrower(func(b *models.Borrower) { b.Name = "Pippi Longstocking" })
+	fx.Borrower()
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", 2},
+		{"pippi", 1},
+	}
+	for _, tt := range tests {
+		var exported int
+		err := svc.ExportBorrowers(tt.query, func(batch []models.Borrower) error {
+			exported += len(batch)
+			return nil
+		})
+		checkErr(t, err, nil)
+		if exported != tt.want {
+			t.Fatalf("query %q: exported %d, want %d", tt.query, exported, tt.want)
+		}
+	}
+}
+
+func TestGetDeletedBorrowers(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBorrowerService(store)
+	fx.Borrower()
+	deleted := fx.Borrower()
+	fx.SoftDelete(deleted)
+
+	borrowers, total, err := svc.GetDeletedBorrowers(1, 10)
+	checkErr(t, err, nil)
+	if total != 1 || borrowers[0].ID != deleted.ID {
+		t.Fatalf("got %d deleted borrowers %+v", total, borrowers)
+	}
+}
+
+func TestRestoreBorrower(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(fx *testutil.Fixtures) uuid.UUID
+		want    error
+	}{
+		{"deleted", func(fx *testutil.Fixtures) uuid.UUID {
+			borrower := fx.Borrower()
+			fx.SoftDelete(borrower)
+			return borrower.ID
+		}, nil},
+		{"active", func(fx *testutil.Fixtures) uuid.UUID {
+			return fx.Borrower().ID
+		}, services.ErrBorrowerNotInTrash},
+		{"email reused", func(fx *testutil.Fixtures) uuid.UUID {
+			borrower := fx.Borrower()
+			fx.SoftDelete(borrower)
+			fx.Borrower(func(b *models.Borrower) { b.Email = borrower.Email })
+			return borrower.ID
+		}, services.ErrDuplicateEmail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewBorrowerService(store)
+			id := tt.prepare(fx)
+
+			_, err := svc.RestoreBorrower(id)
+			checkErr(t, err, tt.want)
+			if tt.want == nil {
+				_, err := svc.GetBorrower(id)
+				checkErr(t, err, nil)
+			}
+		})
+	}
+}
+
+func TestPurgeBorrower(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(fx *testutil.Fixtures) uuid.UUID
+		want    error
+	}{
+		{"deleted", func(fx *testutil.Fixtures) uuid.UUID {
+			borrower := fx.Borrower()
+			fx.SoftDelete(borrower)
+			return borrower.ID
+		}, nil},
+		{"with borrowing history", func(fx *testutil.Fixtures) uuid.UUID {
+			borrower := fx.Borrower()
+			fx.Borrowing(nil, borrower, testutil.Returned)
+			fx.SoftDelete(borrower)
+			return borrower.ID
+		}, services.ErrBorrowerHasBorrowings},
+		{"with a lifted block", func(fx *testutil.Fixtures) uuid.UUID {
+			borrower := fx.Borrower()
+			fx.Block(borrower, func(b *models.BorrowerBlock) {
+				liftedAt := time.Now()
+				b.LiftedAt = &liftedAt
+			})
+			fx.SoftDelete(borrower)
+			return borrower.ID
+		}, services.ErrBorrowerHasBorrowings},
+		{"with a fine", func(fx *testutil.Fixtures) uuid.UUID {
+			borrower := fx.Borrower()
+			fx.Fine(borrower, 100)
+			fx.SoftDelete(borrower)
+			return borrower.ID
+		}, services.ErrBorrowerHasBorrowings},
+		{"with a hold", func(fx *testutil.Fixtures) uuid.UUID {
+			borrower := fx.Borrower()
+			fx.Hold(nil, borrower, func(h *models.Hold) { h.Status = models.HoldCancelled })
+			fx.SoftDelete(borrower)
+			return borrower.ID
+		}, services.ErrBorrowerHasBorrowings},
+		{"active", func(fx *testutil.Fixtures) uuid.UUID {
+			return fx.Borrower().ID
+		}, services.ErrBorrowerNotInTrash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewBorrowerService(store)
+
+			checkErr(t, svc.PurgeBorrower(tt.prepare(fx)), tt.want)
+		})
+	}
+}
+
+func TestGetStanding(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBorrowerService(store)
+
+	borrower := fx.Borrower()
+	fx.Borrowing(nil, borrower)
+	fx.Fine(borrower, 150)
+	standing, err := svc.GetStanding(borrower.ID)
+	checkErr(t, err, nil)
+	want := models.BorrowerStanding{CanBorrow: true, CanRenew: true, ActiveLoans: 1, MaxActiveLoans: 5, OutstandingFines: 150}
+	if *standing != want {
+		t.Fatalf("standing = %+v, want %+v", *standing, want)
+	}
+
+	// Overdue loans stop borrowing but not renewing
+	fx.Borrowing(nil, borrower, testutil.Overdue(time.Hour))
+	standing, err = svc.GetStanding(borrower.ID)
+	checkErr(t, err, nil)
+	if standing.CanBorrow || !standing.CanRenew || standing.OverdueLoans != 1 || standing.Reason != services.ErrBorrowerHasOverdueBooks.Error() {
+		t.Fatalf("unexpected standing %+v", standing)
+	}
+
+	// Blocks stop both
+	blocked := fx.Borrower()
+	fx.Block(blocked)
+	standing, err = svc.GetStanding(blocked.ID)
+	checkErr(t, err, nil)
+	if standing.CanBorrow || standing.CanRenew || !standing.CardLost || standing.Reason != services.ErrBorrowerBlocked.Error() {
+		t.Fatalf("unexpected standing %+v", standing)
+	}
+
+	_, err = svc.GetStanding(uuid.New())
+	checkErr(t, err, services.ErrBorrowerNotFound)
+}
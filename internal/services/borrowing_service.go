@@ -1,108 +1,217 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"time"
 
 	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/reqctx"
 
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
 type BorrowingService struct {
-	db *gorm.DB
+	store repository.Store
+	ctx   context.Context
 }
 
-func NewBorrowingService(db *gorm.DB) *BorrowingService {
-	return &BorrowingService{db: db}
+func NewBorrowingService(store repository.Store) *BorrowingService {
+	return &BorrowingService{store: store, ctx: context.Background()}
+}
+
+// WithContext returns a copy of the service whose queries carry ctx, so the
+// request's actor and ID reach the audit log.
+func (s *BorrowingService) WithContext(ctx context.Context) *BorrowingService {
+	return &BorrowingService{store: s.store.WithContext(ctx), ctx: ctx}
+}
+
+// checkOverride refuses requests to override borrower blocks from callers
+// without the override permission.
+func (s *BorrowingService) checkOverride(override bool) error {
+	if override && !reqctx.CanOverrideBlocks(s.ctx) {
+		return ErrOverrideNotPermitted
+	}
+	return nil
+}
+
+// getBranch loads a branch, returning ErrBranchNotFound if it does not exist.
+func (s *BorrowingService) getBranch(id uuid.UUID) (*models.Branch, error) {
+	branch, err := s.store.Branches().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBranchNotFound
+		}
+		return nil, err
+	}
+	return branch, nil
 }
 
 func (s *BorrowingService) BorrowBook(req *models.BorrowBookRequest) (*models.Borrowing, error) {
+	if err := s.checkOverride(req.OverrideBlocks); err != nil {
+		return nil, err
+	}
+
 	// Check if book exists and is available
-	var book models.Book
-	if err := s.db.First(&book, req.BookID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("book not found")
+	book, err := s.store.Books().Get(req.BookID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBookNotFound
 		}
 		return nil, err
 	}
 
 	if !book.Available {
-		return nil, errors.New("book is not available for borrowing")
+		return nil, ErrBookNotAvailable
 	}
 
 	// Check if borrower exists
-	var borrower models.Borrower
-	if err := s.db.First(&borrower, req.BorrowerID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("borrower not found")
+	borrower, err := s.store.Borrowers().Get(req.BorrowerID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBorrowerNotFound
 		}
 		return nil, err
 	}
 
-	// Check if borrower has any overdue books
-	var overdueCount int64
-	if err := s.db.Model(&models.Borrowing{}).
-		Where("borrower_id = ? AND status = 'borrowed' AND due_date < ?", req.BorrowerID, time.Now()).
-		Count(&overdueCount).Error; err != nil {
+	// Check if borrower's membership is active
+	if err := checkMembership(borrower, time.Now()); err != nil {
 		return nil, err
 	}
 
-	if overdueCount > 0 {
-		return nil, errors.New("borrower has overdue books and cannot borrow new books")
+	// Check if borrower is blocked; staff may override overridable blocks
+	blocks, err := s.store.BorrowerBlocks().ListActive(req.BorrowerID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBlocks(blocks, req.OverrideBlocks); err != nil {
+		return nil, err
+	}
+
+	// Check if the book is held for someone else; the borrower at the head
+	// of the queue collects it
+	holds, err := s.store.Holds().ListWaiting(req.BookID)
+	if err != nil {
+		return nil, err
+	}
+	var hold *models.Hold
+	if len(holds) > 0 {
+		if *holds[0].BorrowerID != req.BorrowerID {
+			return nil, ErrBookOnHold
+		}
+		hold = &holds[0]
 	}
 
-	// Check if borrower has reached maximum borrowing limit (e.g., 5 books)
-	var activeBorrowingCount int64
-	if err := s.db.Model(&models.Borrowing{}).
-		Where("borrower_id = ? AND status = 'borrowed'", req.BorrowerID).
-		Count(&activeBorrowingCount).Error; err != nil {
+	// The loan limits are configured per tenant
+	settings, err := tenantSettings(s.store)
+	if err != nil {
 		return nil, err
 	}
 
-	if activeBorrowingCount >= 5 {
-		return nil, errors.New("borrower has reached maximum borrowing limit")
+	// Check if borrower has any overdue books
+	if settings.BlockOverdueBorrowers {
+		overdueCount, err := s.store.Borrowings().CountOverdueByBorrower(req.BorrowerID, time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		if overdueCount > 0 {
+			return nil, ErrBorrowerHasOverdueBooks
+		}
+	}
+
+	// Check if borrower has reached maximum borrowing limit; the borrower's
+	// category may override the tenant's limit
+	maxActiveLoans := settings.MaxActiveLoans
+	if borrower.Category != nil && borrower.Category.MaxActiveLoans > 0 {
+		maxActiveLoans = borrower.Category.MaxActiveLoans
+	}
+	activeBorrowingCount, err := s.store.Borrowings().CountActiveByBorrower(req.BorrowerID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create borrowing record
+	if activeBorrowingCount >= int64(maxActiveLoans) {
+		return nil, ErrBorrowingLimitReached
+	}
+
+	// Create borrowing record; loans without a due date run for the
+	// tenant's loan period
+	now := time.Now()
+	dueDate := req.DueDate
+	if dueDate.IsZero() {
+		dueDate = now.AddDate(0, 0, settings.LoanPeriodDays)
+	}
 	borrowing := &models.Borrowing{
 		BookID:     req.BookID,
-		BorrowerID: req.BorrowerID,
-		BorrowedAt: time.Now(),
-		DueDate:    req.DueDate,
+		BorrowerID: &borrower.ID,
+		BorrowedAt: now,
+		DueDate:    dueDate,
 		Status:     "borrowed",
 	}
 
-	if err := s.db.Create(borrowing).Error; err != nil {
-		return nil, err
+	// Check if checkout branch exists (if provided)
+	if req.BranchID != uuid.Nil {
+		if _, err := s.getBranch(req.BranchID); err != nil {
+			return nil, err
+		}
+		branchID := req.BranchID
+		borrowing.BranchID = &branchID
 	}
 
-	// Update book availability
-	book.Available = false
-	if err := s.db.Save(&book).Error; err != nil {
-		return nil, err
-	}
+	err = s.store.Transaction(func(tx repository.Store) error {
+		// The item is off the shelf while on loan. Only one of two
+		// concurrent checkouts finds it still available
+		available, err := tx.Books().CheckOut(book.ID)
+		if err != nil {
+			return err
+		}
+		if !available {
+			return ErrBookNotAvailable
+		}
 
-	// Load relationships
-	if err := s.db.Preload("Book.Author").Preload("Borrower").First(borrowing, borrowing.ID).Error; err != nil {
+		if err := tx.Borrowings().Create(borrowing); err != nil {
+			return err
+		}
+
+		// The borrower's hold on the book has been fulfilled
+		if hold != nil {
+			hold.Status = models.HoldFulfilled
+			hold.FulfilledAt = &borrowing.BorrowedAt
+			if err := tx.Holds().Update(hold); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return borrowing, nil
+	// Load relationships
+	return s.store.Borrowings().Get(borrowing.ID)
 }
 
+// ReturnBook checks an item in. An item returned at a branch other than its
+// home branch is sent home in transit rather than becoming available.
 func (s *BorrowingService) ReturnBook(req *models.ReturnBookRequest) (*models.Borrowing, error) {
-	var borrowing models.Borrowing
-	if err := s.db.Preload("Book").Preload("Borrower").First(&borrowing, req.BorrowingID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("borrowing record not found")
-		}
+	borrowing, err := s.GetBorrowing(req.BorrowingID)
+	if err != nil {
 		return nil, err
 	}
 
 	if borrowing.Status != "borrowed" {
-		return nil, errors.New("book is not currently borrowed")
+		return nil, ErrBookNotBorrowed
+	}
+
+	// Check if check-in branch exists (if provided)
+	if req.BranchID != uuid.Nil {
+		if _, err := s.getBranch(req.BranchID); err != nil {
+			return nil, err
+		}
+		branchID := req.BranchID
+		borrowing.ReturnBranchID = &branchID
 	}
 
 	// Update borrowing record
@@ -115,110 +224,206 @@ func (s *BorrowingService) ReturnBook(req *models.ReturnBookRequest) (*models.Bo
 		borrowing.Status = "overdue"
 	}
 
-	if err := s.db.Save(&borrowing).Error; err != nil {
+	// Late returns are fined per day, as configured for the tenant
+	settings, err := tenantSettings(s.store)
+	if err != nil {
 		return nil, err
 	}
+	fine := overdueFine(borrowing, settings.FinePerDay, now)
+
+	err = s.store.Transaction(func(tx repository.Store) error {
+		if err := tx.Borrowings().Update(borrowing); err != nil {
+			return err
+		}
 
-	// Update book availability
-	borrowing.Book.Available = true
-	if err := s.db.Save(&borrowing.Book).Error; err != nil {
+		if fine != nil {
+			if err := tx.Fines().Create(fine); err != nil {
+				return err
+			}
+		}
+
+		book := &borrowing.Book
+		transfer, err := checkIn(tx, book, req.BranchID, now)
+		if err != nil {
+			return err
+		}
+		if transfer != nil {
+			transfer.BorrowingID = &borrowing.ID
+			if err := tx.Transfers().Create(transfer); err != nil {
+				return err
+			}
+		}
+
+		// Update book availability
+		return tx.Books().Update(book)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &borrowing, nil
+	return borrowing, nil
 }
 
-func (s *BorrowingService) GetBorrowing(id uuid.UUID) (*models.Borrowing, error) {
-	var borrowing models.Borrowing
-	if err := s.db.Preload("Book.Author").Preload("Borrower").First(&borrowing, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("borrowing record not found")
-		}
-		return nil, err
+// overdueFine returns the fine for returning borrowing at now, charging
+// perDay for each day or part of a day it is late, or nil if it is not.
+func overdueFine(borrowing *models.Borrowing, perDay int64, now time.Time) *models.Fine {
+	if perDay <= 0 || !now.After(borrowing.DueDate) {
+		return nil
+	}
+
+	days := int64(now.Sub(borrowing.DueDate) / (24 * time.Hour))
+	if now.Sub(borrowing.DueDate)%(24*time.Hour) > 0 {
+		days++
+	}
+	return &models.Fine{
+		BorrowerID:  *borrowing.BorrowerID,
+		BorrowingID: &borrowing.ID,
+		Amount:      days * perDay,
+		Reason:      "overdue",
+		Status:      models.FineOutstanding,
 	}
-	return &borrowing, nil
 }
 
-func (s *BorrowingService) GetAllBorrowings(page, limit int) ([]models.Borrowing, int64, error) {
-	var borrowings []models.Borrowing
-	var total int64
+// RenewBorrowing extends a loan by the tenant's loan period, counted from
+// today but never moving the due date earlier. Overdue loans, loans that
+// have used up their renewals and loans of books others are waiting for
+// cannot be renewed.
+func (s *BorrowingService) RenewBorrowing(id uuid.UUID, req *models.RenewBorrowingRequest) (*models.Borrowing, error) {
+	if err := s.checkOverride(req.OverrideBlocks); err != nil {
+		return nil, err
+	}
 
-	offset := (page - 1) * limit
+	borrowing, err := s.GetBorrowing(id)
+	if err != nil {
+		return nil, err
+	}
 
-	// Count total records
-	if err := s.db.Model(&models.Borrowing{}).Count(&total).Error; err != nil {
-		return nil, 0, err
+	if borrowing.Status != "borrowed" {
+		return nil, ErrBookNotBorrowed
 	}
 
-	// Get borrowings with pagination
-	if err := s.db.Preload("Book.Author").Preload("Borrower").
-		Offset(offset).Limit(limit).
-		Order("created_at DESC").
-		Find(&borrowings).Error; err != nil {
-		return nil, 0, err
+	now := time.Now()
+	if now.After(borrowing.DueDate) {
+		return nil, ErrLoanOverdue
 	}
 
-	return borrowings, total, nil
-}
+	// Check if borrower's membership is active and they are not blocked
+	if err := checkMembership(borrowing.Borrower, now); err != nil {
+		return nil, err
+	}
+	blocks, err := s.store.BorrowerBlocks().ListActive(*borrowing.BorrowerID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBlocks(blocks, req.OverrideBlocks); err != nil {
+		return nil, err
+	}
 
-func (s *BorrowingService) GetBorrowingsByBorrower(borrowerID uuid.UUID, page, limit int) ([]models.Borrowing, int64, error) {
-	var borrowings []models.Borrowing
-	var total int64
+	settings, err := tenantSettings(s.store)
+	if err != nil {
+		return nil, err
+	}
+	if borrowing.RenewalCount >= settings.MaxRenewals {
+		return nil, ErrRenewalLimitReached
+	}
 
-	offset := (page - 1) * limit
+	// Check if anyone is waiting for the book
+	holds, err := s.store.Holds().ListWaiting(borrowing.BookID)
+	if err != nil {
+		return nil, err
+	}
+	if len(holds) > 0 {
+		return nil, ErrBookOnHold
+	}
 
-	// Count total records
-	if err := s.db.Model(&models.Borrowing{}).Where("borrower_id = ?", borrowerID).Count(&total).Error; err != nil {
-		return nil, 0, err
+	if due := now.AddDate(0, 0, settings.LoanPeriodDays); due.After(borrowing.DueDate) {
+		borrowing.DueDate = due
 	}
+	borrowing.RenewalCount++
+	borrowing.RenewedAt = &now
 
-	// Get borrowings with pagination
-	if err := s.db.Preload("Book.Author").Preload("Borrower").
-		Where("borrower_id = ?", borrowerID).
-		Offset(offset).Limit(limit).
-		Order("created_at DESC").
-		Find(&borrowings).Error; err != nil {
-		return nil, 0, err
+	if err := s.store.Borrowings().Update(borrowing); err != nil {
+		return nil, err
 	}
 
-	return borrowings, total, nil
+	return borrowing, nil
 }
 
-func (s *BorrowingService) GetOverdueBorrowings(page, limit int) ([]models.Borrowing, int64, error) {
-	var borrowings []models.Borrowing
-	var total int64
+// GetActiveBorrowingsByBorrower lists the borrower's loans that have not
+// been returned.
+func (s *BorrowingService) GetActiveBorrowingsByBorrower(borrowerID uuid.UUID, page, limit int) ([]models.Borrowing, int64, error) {
+	return s.FindBorrowings(models.BorrowingFilter{BorrowerID: borrowerID, ActiveOnly: true}, page, limit)
+}
 
-	offset := (page - 1) * limit
-	now := time.Now()
+// checkIn shelves book at its home location, or marks it in transit and
+// returns the transfer to create when branchID is not its home branch.
+func checkIn(tx repository.Store, book *models.Book, branchID uuid.UUID, now time.Time) (*models.Transfer, error) {
+	if book.HomeLocationID == nil {
+		book.Available = true
+		return nil, nil
+	}
 
-	// Count total records
-	if err := s.db.Model(&models.Borrowing{}).
-		Where("status = 'borrowed' AND due_date < ?", now).
-		Count(&total).Error; err != nil {
-		return nil, 0, err
+	home, err := tx.Locations().Get(*book.HomeLocationID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get overdue borrowings with pagination
-	if err := s.db.Preload("Book.Author").Preload("Borrower").
-		Where("status = 'borrowed' AND due_date < ?", now).
-		Offset(offset).Limit(limit).
-		Order("due_date ASC").
-		Find(&borrowings).Error; err != nil {
-		return nil, 0, err
+	if branchID == uuid.Nil || branchID == home.BranchID {
+		book.Available = true
+		book.InTransit = false
+		book.CurrentLocationID = &home.ID
+		return nil, nil
 	}
 
-	return borrowings, total, nil
+	book.Available = false
+	book.InTransit = true
+	book.CurrentLocationID = nil
+	return &models.Transfer{
+		BookID:       book.ID,
+		FromBranchID: branchID,
+		ToBranchID:   home.BranchID,
+		ToLocationID: home.ID,
+		Status:       models.TransferInTransit,
+		SentAt:       now,
+	}, nil
+}
+
+func (s *BorrowingService) GetBorrowing(id uuid.UUID) (*models.Borrowing, error) {
+	borrowing, err := s.store.Borrowings().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBorrowingNotFound
+		}
+		return nil, err
+	}
+	return borrowing, nil
+}
+
+func (s *BorrowingService) GetAllBorrowings(page, limit int) ([]models.Borrowing, int64, error) {
+	return s.FindBorrowings(models.BorrowingFilter{}, page, limit)
+}
+
+// FindBorrowings lists the borrowings matching filter.
+func (s *BorrowingService) FindBorrowings(filter models.BorrowingFilter, page, limit int) ([]models.Borrowing, int64, error) {
+	offset := (page - 1) * limit
+	return s.store.Borrowings().Find(filter, offset, limit)
+}
+
+func (s *BorrowingService) GetBorrowingsByBorrower(borrowerID uuid.UUID, page, limit int) ([]models.Borrowing, int64, error) {
+	return s.FindBorrowings(models.BorrowingFilter{BorrowerID: borrowerID}, page, limit)
+}
+
+func (s *BorrowingService) GetOverdueBorrowings(page, limit int) ([]models.Borrowing, int64, error) {
+	return s.FindBorrowings(models.BorrowingFilter{OverdueOnly: true}, page, limit)
 }
 
 func (s *BorrowingService) UpdateOverdueStatus() error {
-	now := time.Now()
-	
 	// Update borrowings that are now overdue
-	if err := s.db.Model(&models.Borrowing{}).
-		Where("status = 'borrowed' AND due_date < ?", now).
-		Update("status", "overdue").Error; err != nil {
-		return err
-	}
+	return s.store.Borrowings().MarkOverdue(time.Now())
+}
 
-	return nil
+// ExportBorrowings streams the borrowings matching filter to fn in batches
+// of exportBatchSize.
+func (s *BorrowingService) ExportBorrowings(filter models.BorrowingFilter, fn func([]models.Borrowing) error) error {
+	return s.store.Borrowings().Export(filter, exportBatchSize, fn)
 }
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// BranchService manages branches and the shelf locations within them.
+type BranchService struct {
+	store repository.Store
+}
+
+func NewBranchService(store repository.Store) *BranchService {
+	return &BranchService{store: store}
+}
+
+// WithContext returns a copy of the service whose queries carry ctx, so the
+// request's actor and ID reach the audit log.
+func (s *BranchService) WithContext(ctx context.Context) *BranchService {
+	return &BranchService{store: s.store.WithContext(ctx)}
+}
+
+// checkBranchCode returns ErrDuplicateBranchCode if an active branch other
+// than excludeID already uses code.
+func (s *BranchService) checkBranchCode(code string, excludeID uuid.UUID) error {
+	if _, err := s.store.Branches().FindByCode(code, excludeID); err == nil {
+		return ErrDuplicateBranchCode
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// checkLocationCode returns ErrDuplicateLocationCode if an active location
+// of branchID other than excludeID already uses code.
+func (s *BranchService) checkLocationCode(branchID uuid.UUID, code string, excludeID uuid.UUID) error {
+	if _, err := s.store.Locations().FindByCode(branchID, code, excludeID); err == nil {
+		return ErrDuplicateLocationCode
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (s *BranchService) CreateBranch(req *models.CreateBranchRequest) (*models.Branch, error) {
+	// Check if code already exists
+	if err := s.checkBranchCode(req.Code, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	branch := &models.Branch{
+		Code:    req.Code,
+		Name:    req.Name,
+		Address: req.Address,
+		Phone:   req.Phone,
+	}
+
+	if err := s.store.Branches().Create(branch); err != nil {
+		return nil, err
+	}
+
+	return branch, nil
+}
+
+func (s *BranchService) GetBranch(id uuid.UUID) (*models.Branch, error) {
+	branch, err := s.store.Branches().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBranchNotFound
+		}
+		return nil, err
+	}
+	return branch, nil
+}
+
+func (s *BranchService) GetAllBranches(page, limit int) ([]models.Branch, int64, error) {
+	offset := (page - 1) * limit
+	return s.store.Branches().List(offset, limit)
+}
+
+func (s *BranchService) UpdateBranch(id uuid.UUID, req *models.UpdateBranchRequest) (*models.Branch, error) {
+	branch, err := s.GetBranch(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if code already exists (if provided and different)
+	if req.Code != "" && req.Code != branch.Code {
+		if err := s.checkBranchCode(req.Code, id); err != nil {
+			return nil, err
+		}
+		branch.Code = req.Code
+	}
+
+	// Update fields
+	if req.Name != "" {
+		branch.Name = req.Name
+	}
+	if req.Address != "" {
+		branch.Address = req.Address
+	}
+	if req.Phone != "" {
+		branch.Phone = req.Phone
+	}
+
+	if err := s.store.Branches().Update(branch); err != nil {
+		return nil, err
+	}
+
+	return branch, nil
+}
+
+func (s *BranchService) DeleteBranch(id uuid.UUID) error {
+	branch, err := s.GetBranch(id)
+	if err != nil {
+		return err
+	}
+
+	// Check if branch has locations
+	locationCount, err := s.store.Locations().CountByBranch(id)
+	if err != nil {
+		return err
+	}
+
+	if locationCount > 0 {
+		return ErrBranchHasLocations
+	}
+
+	return s.store.Branches().Delete(branch)
+}
+
+func (s *BranchService) CreateLocation(branchID uuid.UUID, req *models.CreateLocationRequest) (*models.Location, error) {
+	// Check if branch exists
+	if _, err := s.GetBranch(branchID); err != nil {
+		return nil, err
+	}
+
+	// Check if code already exists in the branch
+	if err := s.checkLocationCode(branchID, req.Code, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	location := &models.Location{
+		BranchID: branchID,
+		Code:     req.Code,
+		Name:     req.Name,
+	}
+
+	if err := s.store.Locations().Create(location); err != nil {
+		return nil, err
+	}
+
+	// Load the branch relationship
+	return s.GetLocation(location.ID)
+}
+
+func (s *BranchService) GetLocation(id uuid.UUID) (*models.Location, error) {
+	location, err := s.store.Locations().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrLocationNotFound
+		}
+		return nil, err
+	}
+	return location, nil
+}
+
+// GetLocations lists every location of a branch, ordered by code.
+func (s *BranchService) GetLocations(branchID uuid.UUID) ([]models.Location, error) {
+	// Check if branch exists
+	if _, err := s.GetBranch(branchID); err != nil {
+		return nil, err
+	}
+
+	return s.store.Locations().ListByBranch(branchID)
+}
+
+func (s *BranchService) UpdateLocation(id uuid.UUID, req *models.UpdateLocationRequest) (*models.Location, error) {
+	location, err := s.GetLocation(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if code already exists in the branch (if provided and different)
+	if req.Code != "" && req.Code != location.Code {
+		if err := s.checkLocationCode(location.BranchID, req.Code, id); err != nil {
+			return nil, err
+		}
+		location.Code = req.Code
+	}
+
+	// Update fields
+	if req.Name != "" {
+		location.Name = req.Name
+	}
+
+	if err := s.store.Locations().Update(location); err != nil {
+		return nil, err
+	}
+
+	return location, nil
+}
+
+func (s *BranchService) DeleteLocation(id uuid.UUID) error {
+	location, err := s.GetLocation(id)
+	if err != nil {
+		return err
+	}
+
+	// Check if any book is homed or shelved here
+	bookCount, err := s.store.Books().CountByLocation(id)
+	if err != nil {
+		return err
+	}
+
+	if bookCount > 0 {
+		return ErrLocationInUse
+	}
+
+	return s.store.Locations().Delete(location)
+}
@@ -0,0 +1,208 @@
+package services_test
+
+import (
+	"testing"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateBranch(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBranchService(store)
+	existing := fx.Branch()
+	deleted := fx.Branch()
+	checkErr(t, svc.DeleteBranch(deleted.ID), nil)
+
+	tests := []struct {
+		name string
+		code string
+		want error
+	}{
+		{"new code", "NEW", nil},
+		{"duplicate code", existing.Code, services.ErrDuplicateBranchCode},
+		{"code of deleted branch", deleted.Code, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			branch, err := svc.CreateBranch(&models.CreateBranchRequest{Code: tt.code, Name: "Branch"})
+			checkErr(t, err, tt.want)
+			if tt.want == nil && branch.ID == uuid.Nil {
+				t.Fatal("expected branch to be assigned an ID")
+			}
+		})
+	}
+}
+
+func TestUpdateBranch(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(fx *testutil.Fixtures) (uuid.UUID, *models.UpdateBranchRequest)
+		want    error
+	}{
+		{"rename", func(fx *testutil.Fixtures) (uuid.UUID, *models.UpdateBranchRequest) {
+			return fx.Branch().ID, &models.UpdateBranchRequest{Name: "Renamed", Phone: "555-0199"}
+		}, nil},
+		{"same code", func(fx *testutil.Fixtures) (uuid.UUID, *models.UpdateBranchRequest) {
+			branch := fx.Branch()
+			return branch.ID, &models.UpdateBranchRequest{Code: branch.Code}
+		}, nil},
+		{"code taken", func(fx *testutil.Fixtures) (uuid.UUID, *models.UpdateBranchRequest) {
+			taken := fx.Branch()
+			return fx.Branch().ID, &models.UpdateBranchRequest{Code: taken.Code}
+		}, services.ErrDuplicateBranchCode},
+		{"unknown", func(*testutil.Fixtures) (uuid.UUID, *models.UpdateBranchRequest) {
+			return uuid.New(), &models.UpdateBranchRequest{Name: "Nobody"}
+		}, services.ErrBranchNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewBranchService(store)
+			id, req := tt.prepare(fx)
+
+			_, err := svc.UpdateBranch(id, req)
+			checkErr(t, err, tt.want)
+		})
+	}
+}
+
+func TestDeleteBranch(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(fx *testutil.Fixtures) uuid.UUID
+		want    error
+	}{
+		{"empty branch", func(fx *testutil.Fixtures) uuid.UUID {
+			return fx.Branch().ID
+		}, nil},
+		{"branch with locations", func(fx *testutil.Fixtures) uuid.UUID {
+			return fx.Location(nil).BranchID
+		}, services.ErrBranchHasLocations},
+		{"unknown", func(*testutil.Fixtures) uuid.UUID {
+			return uuid.New()
+		}, services.ErrBranchNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewBranchService(store)
+			id := tt.prepare(fx)
+
+			checkErr(t, svc.DeleteBranch(id), tt.want)
+			if tt.want == nil {
+				_, err := svc.GetBranch(id)
+				checkErr(t, err, services.ErrBranchNotFound)
+			}
+		})
+	}
+}
+
+func TestCreateLocation(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBranchService(store)
+	branch := fx.Branch()
+	other := fx.Branch()
+	existing := fx.Location(branch)
+
+	tests := []struct {
+		name     string
+		branchID uuid.UUID
+		code     string
+		want     error
+	}{
+		{"new code", branch.ID, "NEW", nil},
+		{"duplicate code", branch.ID, existing.Code, services.ErrDuplicateLocationCode},
+		{"same code in another branch", other.ID, existing.Code, nil},
+		{"unknown branch", uuid.New(), "ANY", services.ErrBranchNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location, err := svc.CreateLocation(tt.branchID, &models.CreateLocationRequest{Code: tt.code, Name: "Shelf"})
+			checkErr(t, err, tt.want)
+			if tt.want == nil && (location.Branch == nil || location.Branch.ID != tt.branchID) {
+				t.Fatalf("expected branch to be loaded, got %+v", location.Branch)
+			}
+		})
+	}
+}
+
+func TestGetLocations(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBranchService(store)
+	branch := fx.Branch()
+	fx.Location(branch)
+	fx.Location(branch)
+	fx.Location(nil)
+
+	locations, err := svc.GetLocations(branch.ID)
+	checkErr(t, err, nil)
+	if len(locations) != 2 {
+		t.Fatalf("got %d locations, want 2", len(locations))
+	}
+
+	_, err = svc.GetLocations(uuid.New())
+	checkErr(t, err, services.ErrBranchNotFound)
+}
+
+func TestUpdateLocation(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBranchService(store)
+	branch := fx.Branch()
+	location := fx.Location(branch)
+	taken := fx.Location(branch)
+
+	tests := []struct {
+		name string
+		id   uuid.UUID
+		req  models.UpdateLocationRequest
+		want error
+	}{
+		{"rename", location.ID, models.UpdateLocationRequest{Name: "Renamed"}, nil},
+		{"code taken", location.ID, models.UpdateLocationRequest{Code: taken.Code}, services.ErrDuplicateLocationCode},
+		{"unknown", uuid.New(), models.UpdateLocationRequest{Name: "Nowhere"}, services.ErrLocationNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.UpdateLocation(tt.id, &tt.req)
+			checkErr(t, err, tt.want)
+		})
+	}
+}
+
+func TestDeleteLocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(fx *testutil.Fixtures) uuid.UUID
+		want    error
+	}{
+		{"empty location", func(fx *testutil.Fixtures) uuid.UUID {
+			return fx.Location(nil).ID
+		}, nil},
+		{"home of a book", func(fx *testutil.Fixtures) uuid.UUID {
+			location := fx.Location(nil)
+			fx.Book(nil, testutil.ShelvedAt(location))
+			return location.ID
+		}, services.ErrLocationInUse},
+		{"home of a book on loan", func(fx *testutil.Fixtures) uuid.UUID {
+			location := fx.Location(nil)
+			fx.Borrowing(fx.Book(nil, testutil.ShelvedAt(location)), nil)
+			return location.ID
+		}, services.ErrLocationInUse},
+		{"unknown", func(*testutil.Fixtures) uuid.UUID {
+			return uuid.New()
+		}, services.ErrLocationNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewBranchService(store)
+			id := tt.prepare(fx)
+
+			checkErr(t, svc.DeleteLocation(id), tt.want)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+
+	"library-management-go/internal/imaging"
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+// MaxCoverSize is the largest cover image accepted, in bytes.
+const MaxCoverSize = 10 << 20
+
+// coverThumbnails are the thumbnail sizes generated for every cover, as
+// the length of their longer side in pixels.
+var coverThumbnails = []struct {
+	name string
+	size int
+}{
+	{"small", 96},
+	{"medium", 240},
+	{"large", 480},
+}
+
+// CoverService stores books' cover images and the thumbnails made from
+// them.
+type CoverService struct {
+	store   repository.Store
+	storage storage.Storage
+	ctx     context.Context
+}
+
+func NewCoverService(store repository.Store, storage storage.Storage) *CoverService {
+	return &CoverService{store: store, storage: storage, ctx: context.Background()}
+}
+
+// WithContext returns a copy of the service whose queries carry ctx, so the
+// request's actor and ID reach the audit log. Storage requests are made
+// with ctx too.
+func (s *CoverService) WithContext(ctx context.Context) *CoverService {
+	return &CoverService{store: s.store.WithContext(ctx), storage: s.storage, ctx: ctx}
+}
+
+func (s *CoverService) getBook(id uuid.UUID) (*models.Book, error) {
+	book, err := s.store.Books().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBookNotFound
+		}
+		return nil, err
+	}
+	return book, nil
+}
+
+// coverKeys returns the storage keys of a cover under prefix.
+func coverKeys(prefix, contentType string) []string {
+	keys := []string{prefix + "/original" + imaging.Extension(contentType)}
+	for _, t := range coverThumbnails {
+		keys = append(keys, prefix+"/"+t.name+".jpg")
+	}
+	return keys
+}
+
+// remove deletes the objects under keys. The book no longer refers to
+// them, so a failure only leaves garbage behind and is logged.
+func (s *CoverService) remove(keys []string) {
+	for _, key := range keys {
+		if err := s.storage.Delete(s.ctx, key); err != nil {
+			log.Printf("Failed to delete cover object %s: %v", key, err)
+		}
+	}
+}
+
+// SetCover makes data, a JPEG, PNG or GIF image, the book's cover and
+// generates its thumbnails, replacing any cover the book had. The type is
+// sniffed from the data rather than trusted from the client.
+func (s *CoverService) SetCover(bookID uuid.UUID, data []byte) (*models.Book, error) {
+	book, err := s.getBook(bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > MaxCoverSize {
+		return nil, ErrCoverTooLarge
+	}
+	contentType, err := imaging.ContentType(data)
+	if err != nil {
+		return nil, ErrUnsupportedCoverType
+	}
+	img, err := imaging.Decode(data)
+	if errors.Is(err, imaging.ErrTooLarge) {
+		return nil, ErrCoverTooLarge
+	}
+	if err != nil {
+		return nil, ErrInvalidCoverImage
+	}
+
+	// Name the objects after the image, so a new cover gets URLs that no
+	// cache has seen
+	sum := sha256.Sum256(data)
+	prefix := fmt.Sprintf("covers/%s/%s", book.ID, hex.EncodeToString(sum[:8]))
+	keys := coverKeys(prefix, contentType)
+
+	cover := &models.BookCover{
+		URL:         s.storage.URL(keys[0]),
+		ContentType: contentType,
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+		Thumbnails:  map[string]string{},
+	}
+	if err := s.storage.Put(s.ctx, keys[0], contentType, data); err != nil {
+		return nil, err
+	}
+	sizes := make([]int, len(coverThumbnails))
+	for i, t := range coverThumbnails {
+		sizes[i] = t.size
+	}
+	thumbnails := imaging.Thumbnails(img, sizes...)
+	for i, t := range coverThumbnails {
+		thumbnail, err := imaging.EncodeJPEG(thumbnails[i])
+		if err == nil {
+			err = s.storage.Put(s.ctx, keys[i+1], "image/jpeg", thumbnail)
+		}
+		if err != nil {
+			s.remove(keys[:i+1])
+			return nil, err
+		}
+		cover.Thumbnails[t.name] = s.storage.URL(keys[i+1])
+	}
+
+	oldKey, oldCover := book.CoverKey, book.Cover
+	book.CoverKey = prefix
+	book.Cover = cover
+	if err := s.store.Books().Update(book); err != nil {
+		// Uploading the same image again reuses the current objects
+		if prefix != oldKey {
+			s.remove(keys)
+		}
+		return nil, err
+	}
+
+	if oldKey != "" && oldKey != prefix {
+		s.remove(coverKeys(oldKey, oldCover.ContentType))
+	}
+	return book, nil
+}
+
+// DeleteCover removes the book's cover and its thumbnails.
+func (s *CoverService) DeleteCover(bookID uuid.UUID) error {
+	book, err := s.getBook(bookID)
+	if err != nil {
+		return err
+	}
+	if book.CoverKey == "" {
+		return ErrNoCover
+	}
+
+	keys := coverKeys(book.CoverKey, book.Cover.ContentType)
+	book.CoverKey = ""
+	book.Cover = nil
+	if err := s.store.Books().Update(book); err != nil {
+		return err
+	}
+
+	s.remove(keys)
+	return nil
+}
@@ -0,0 +1,102 @@
+package services_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"library-management-go/internal/services"
+	"library-management-go/internal/storage"
+	"library-management-go/internal/storage/storagetest"
+
+	"github.com/google/uuid"
+)
+
+// coverImage encodes a w×h PNG filled with c.
+func coverImage(t *testing.T, w, h int, c color.Color) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestSetCover(t *testing.T) {
+	store, fx := setup(t)
+	s3 := storagetest.NewS3Server(t, "covers")
+	svc := services.NewCoverService(store, storage.NewS3(s3.Config(), nil))
+	book := fx.Book(nil)
+
+	updated, err := svc.SetCover(book.ID, coverImage(t, 600, 900, color.White))
+	checkErr(t, err, nil)
+	cover := updated.Cover
+	if cover == nil || cover.ContentType != "image/png" || cover.Width != 600 || cover.Height != 900 || len(cover.Thumbnails) != 3 {
+		t.Fatalf("unexpected cover %+v", cover)
+	}
+	if s3.Len() != 4 {
+		t.Fatalf("stored %d objects, want the original and 3 thumbnails", s3.Len())
+	}
+
+	// Thumbnails are JPEGs fitted into their size
+	key := cover.Thumbnails["medium"][len(s3.URL+"/covers/"):]
+	data, contentType, ok := s3.Object(key)
+	if !ok || contentType != "image/jpeg" {
+		t.Fatalf("medium thumbnail %q: found %v, type %q", key, ok, contentType)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width != 160 || config.Height != 240 {
+		t.Fatalf("medium thumbnail is %dx%d (%v), want 160x240", config.Width, config.Height, err)
+	}
+
+	// A new cover replaces the old one's objects
+	replaced, err := svc.SetCover(book.ID, coverImage(t, 100, 100, color.Black))
+	checkErr(t, err, nil)
+	if replaced.Cover.URL == cover.URL || s3.Len() != 4 {
+		t.Fatalf("expected the old cover to be replaced, have %d objects", s3.Len())
+	}
+	got, err := services.NewBookService(store).GetBook(book.ID)
+	checkErr(t, err, nil)
+	if got.Cover == nil || got.Cover.URL != replaced.Cover.URL {
+		t.Fatalf("cover not saved, got %+v", got.Cover)
+	}
+
+	checkErr(t, svc.DeleteCover(book.ID), nil)
+	if s3.Len() != 0 {
+		t.Fatalf("%d objects left after deleting the cover", s3.Len())
+	}
+	checkErr(t, svc.DeleteCover(book.ID), services.ErrNoCover)
+}
+
+func TestSetCoverRejects(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewCoverService(store, storage.NewLocal(t.TempDir(), "/media"))
+	book := fx.Book(nil)
+	valid := coverImage(t, 10, 10, color.White)
+
+	tests := []struct {
+		name string
+		id   uuid.UUID
+		data []byte
+		want error
+	}{
+		{"unknown book", uuid.New(), valid, services.ErrBookNotFound},
+		{"not an image", book.ID, []byte("%PDF-1.7"), services.ErrUnsupportedCoverType},
+		{"truncated image", book.ID, valid[:len(valid)/2], services.ErrInvalidCoverImage},
+		{"too large", book.ID, make([]byte, services.MaxCoverSize+1), services.ErrCoverTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.SetCover(tt.id, tt.data)
+			checkErr(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import "errors"
+
+// ErrorKind classifies service errors so every transport (REST, gRPC)
+// can map them onto its own status codes the same way.
+type ErrorKind int
+
+const (
+	KindInternal ErrorKind = iota
+	KindInvalid
+	KindNotFound
+	KindConflict
+	KindFailedPrecondition
+	KindUnauthenticated
+	KindPermissionDenied
+)
+
+// Error is a service error carrying its kind alongside the message
+// returned to clients.
+type Error struct {
+	Kind    ErrorKind
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func newError(kind ErrorKind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+var (
+	ErrAuthorNotFound    = newError(KindNotFound, "author not found")
+	ErrBookNotFound      = newError(KindNotFound, "book not found")
+	ErrBorrowerNotFound  = newError(KindNotFound, "borrower not found")
+	ErrBorrowingNotFound = newError(KindNotFound, "borrowing record not found")
+	ErrBranchNotFound    = newError(KindNotFound, "branch not found")
+	ErrLocationNotFound  = newError(KindNotFound, "location not found")
+	ErrTransferNotFound  = newError(KindNotFound, "transfer not found")
+	ErrTenantNotFound    = newError(KindNotFound, "tenant not found")
+	ErrCategoryNotFound  = newError(KindNotFound, "borrower category not found")
+	ErrBlockNotFound     = newError(KindNotFound, "borrower block not found")
+	ErrHoldNotFound      = newError(KindNotFound, "hold not found")
+	ErrFineNotFound      = newError(KindNotFound, "fine not found")
+	ErrSubjectNotFound   = newError(KindNotFound, "subject not found")
+	ErrParentNotFound    = newError(KindNotFound, "parent subject not found")
+	ErrPublisherNotFound = newError(KindNotFound, "publisher not found")
+	ErrSeriesNotFound    = newError(KindNotFound, "series not found")
+	ErrWorkNotFound      = newError(KindNotFound, "work not found")
+	ErrNoCover           = newError(KindNotFound, "book has no cover")
+	ErrLayoutNotFound    = newError(KindNotFound, "label layout not found")
+	ErrTerminalNotFound  = newError(KindNotFound, "SIP terminal not found")
+
+	ErrInvalidTenantSlug       = newError(KindInvalid, "tenant slug must be lowercase letters, digits and hyphens")
+	ErrInvalidCardNumber       = newError(KindInvalid, "invalid library card number")
+	ErrInvalidMembershipExpiry = newError(KindInvalid, "membership expiry must be in the future")
+	ErrInvalidBlockExpiry      = newError(KindInvalid, "block expiry must be in the future")
+	ErrInvalidFineStatus       = newError(KindInvalid, "fine status must be outstanding, paid or waived")
+	ErrInvalidContributorRole  = newError(KindInvalid, "contributor role must be author, editor, translator or illustrator")
+	ErrDuplicateContributor    = newError(KindInvalid, "author is credited twice in the same role")
+	ErrNoContributors          = newError(KindInvalid, "book must have at least one contributor")
+	ErrSubjectCycle            = newError(KindInvalid, "subject cannot be moved under itself or a narrower subject")
+	ErrVolumeWithoutSeries     = newError(KindInvalid, "series volume requires a series")
+	ErrInvalidCallNumber       = newError(KindInvalid, "call number does not follow its classification scheme")
+	ErrSchemeWithoutCallNumber = newError(KindInvalid, "classification scheme requires a call number")
+	ErrUnsupportedCoverType    = newError(KindInvalid, "cover must be a JPEG, PNG or GIF image")
+	ErrInvalidCoverImage       = newError(KindInvalid, "cover image could not be decoded")
+	ErrCoverTooLarge           = newError(KindInvalid, "cover image must be at most 10 MB and 40 megapixels")
+	ErrUnsupportedSymbology    = newError(KindInvalid, "barcode symbology must be code128 or code39")
+	ErrUnencodableBarcode      = newError(KindInvalid, "barcode contains characters the symbology cannot encode")
+	ErrInvalidLabelLayout      = newError(KindInvalid, "labels in the layout overlap or do not fit on the page")
+	ErrInvalidLabelSkip        = newError(KindInvalid, "skip must be less than the number of labels on a sheet")
+	ErrBarcodeTooWide          = newError(KindInvalid, "barcode is too wide to scan on this label layout")
+	ErrInvalidReceiptKind      = newError(KindInvalid, "receipt kind must be checkout, renewal or return")
+
+	ErrInvalidCredentials         = newError(KindUnauthenticated, "invalid card number or PIN")
+	ErrInvalidTerminalCredentials = newError(KindUnauthenticated, "invalid terminal login or password")
+
+	ErrOverrideNotPermitted = newError(KindPermissionDenied, "not permitted to override borrower blocks")
+
+	ErrDuplicateISBN    = newError(KindConflict, "book with this ISBN already exists")
+	ErrDuplicateBarcode = newError(KindConflict, "book with this barcode already exists")
+	ErrDuplicateEmail   = newError(KindConflict, "borrower with this email already exists")
+
+	ErrDuplicateBranchCode   = newError(KindConflict, "branch with this code already exists")
+	ErrDuplicateLocationCode = newError(KindConflict, "location with this code already exists in the branch")
+	ErrDuplicateTenantSlug   = newError(KindConflict, "tenant with this slug already exists")
+	ErrDuplicateCategoryCode = newError(KindConflict, "borrower category with this code already exists")
+	ErrDuplicateHold         = newError(KindConflict, "borrower already has a hold on this book")
+	ErrDuplicateSubject      = newError(KindConflict, "subject with this name already exists under the same parent")
+	ErrDuplicatePublisher    = newError(KindConflict, "publisher with this name already exists")
+	ErrDuplicateSeries       = newError(KindConflict, "series with this name already exists")
+	ErrDuplicateSIPLogin     = newError(KindConflict, "SIP terminal with this login already exists")
+
+	ErrAuthorHasBooks              = newError(KindFailedPrecondition, "cannot delete author with existing books")
+	ErrBookCurrentlyBorrowed       = newError(KindFailedPrecondition, "cannot delete book that is currently borrowed")
+	ErrBorrowerHasActiveBorrowings = newError(KindFailedPrecondition, "cannot delete borrower with active borrowings")
+	ErrBookNotAvailable            = newError(KindFailedPrecondition, "book is not available for borrowing")
+	ErrBorrowerHasOverdueBooks     = newError(KindFailedPrecondition, "borrower has overdue books and cannot borrow new books")
+	ErrBorrowingLimitReached       = newError(KindFailedPrecondition, "borrower has reached maximum borrowing limit")
+	ErrBookNotBorrowed             = newError(KindFailedPrecondition, "book is not currently borrowed")
+	ErrBranchHasLocations          = newError(KindFailedPrecondition, "cannot delete branch with existing locations")
+	ErrLocationInUse               = newError(KindFailedPrecondition, "cannot delete location that holds books")
+	ErrTransferNotInTransit        = newError(KindFailedPrecondition, "transfer is not in transit")
+	ErrCategoryInUse               = newError(KindFailedPrecondition, "cannot delete borrower category that has borrowers")
+	ErrBorrowerSuspended           = newError(KindFailedPrecondition, "borrower is suspended and cannot borrow books")
+	ErrMembershipExpired           = newError(KindFailedPrecondition, "borrower's membership has expired")
+	ErrBorrowerBlocked             = newError(KindFailedPrecondition, "borrower is blocked from borrowing")
+	ErrBlockNotActive              = newError(KindFailedPrecondition, "borrower block has already been lifted or expired")
+	ErrBookOnHold                  = newError(KindFailedPrecondition, "book is on hold for another borrower")
+	ErrLoanOverdue                 = newError(KindFailedPrecondition, "overdue loans cannot be renewed")
+	ErrRenewalLimitReached         = newError(KindFailedPrecondition, "loan has reached the maximum number of renewals")
+	ErrAlreadyBorrowing            = newError(KindFailedPrecondition, "borrower already has this book on loan")
+	ErrHoldNotWaiting              = newError(KindFailedPrecondition, "hold has already been fulfilled or cancelled")
+	ErrFineNotOutstanding          = newError(KindFailedPrecondition, "fine has already been settled")
+	ErrBorrowerErased              = newError(KindFailedPrecondition, "borrower has been erased")
+	ErrErasureActiveLoans          = newError(KindFailedPrecondition, "cannot erase borrower with loans that have not been returned")
+	ErrErasureOutstandingFines     = newError(KindFailedPrecondition, "cannot erase borrower with outstanding fines")
+	ErrSubjectHasChildren          = newError(KindFailedPrecondition, "cannot delete subject with narrower subjects")
+	ErrSubjectInUse                = newError(KindFailedPrecondition, "cannot delete subject that books are tagged with")
+	ErrPublisherInUse              = newError(KindFailedPrecondition, "cannot delete publisher with existing books")
+	ErrSeriesInUse                 = newError(KindFailedPrecondition, "cannot delete series with existing books")
+	ErrWorkInUse                   = newError(KindFailedPrecondition, "cannot delete work with existing editions")
+	ErrNoItemBarcode               = newError(KindFailedPrecondition, "book has no barcode")
+	ErrNoCardNumber                = newError(KindFailedPrecondition, "borrower has no library card")
+	ErrLoanNotRenewed              = newError(KindFailedPrecondition, "loan has not been renewed")
+	ErrLoanNotReturned             = newError(KindFailedPrecondition, "loan has not been returned")
+	ErrNoReceiptRecipient          = newError(KindFailedPrecondition, "loan is no longer linked to a borrower to email")
+	ErrEmailNotConfigured          = newError(KindFailedPrecondition, "email is not configured")
+
+	ErrAuthorNotInTrash      = newError(KindNotFound, "author not found in trash")
+	ErrBookNotInTrash        = newError(KindNotFound, "book not found in trash")
+	ErrBorrowerNotInTrash    = newError(KindNotFound, "borrower not found in trash")
+	ErrRestoreAuthorDeleted  = newError(KindFailedPrecondition, "cannot restore book whose contributor is deleted")
+	ErrAuthorHasBookHistory  = newError(KindFailedPrecondition, "cannot purge author referenced by books")
+	ErrBookHasBorrowings     = newError(KindFailedPrecondition, "cannot purge book with borrowing history, holds or transfers")
+	ErrBorrowerHasBorrowings = newError(KindFailedPrecondition, "cannot purge borrower with borrowing history, holds, fines or blocks")
+)
+
+// KindOf returns the kind of err, or KindInternal if err did not
+// originate from this package.
+func KindOf(err error) ErrorKind {
+	var svcErr *Error
+	if errors.As(err, &svcErr) {
+		return svcErr.Kind
+	}
+	return KindInternal
+}
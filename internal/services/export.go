@@ -0,0 +1,5 @@
+package services
+
+// exportBatchSize is the number of rows loaded per query while streaming an
+// export, which bounds memory use regardless of the table size.
+const exportBatchSize = 1000
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/reqctx"
+
+	"github.com/google/uuid"
+)
+
+type FineService struct {
+	store repository.Store
+	ctx   context.Context
+}
+
+func NewFineService(store repository.Store) *FineService {
+	return &FineService{store: store, ctx: context.Background()}
+}
+
+// WithContext returns a copy of the service whose queries carry ctx, so the
+// request's actor and ID reach the audit log.
+func (s *FineService) WithContext(ctx context.Context) *FineService {
+	return &FineService{store: s.store.WithContext(ctx), ctx: ctx}
+}
+
+// GetFinesByBorrower lists the borrower's fines newest first, optionally
+// only those with status, along with the total still outstanding.
+func (s *FineService) GetFinesByBorrower(borrowerID uuid.UUID, status string) ([]models.Fine, int64, error) {
+	switch status {
+	case "", models.FineOutstanding, models.FinePaid, models.FineWaived:
+	default:
+		return nil, 0, ErrInvalidFineStatus
+	}
+
+	fines, err := s.store.Fines().ListByBorrower(borrowerID, status)
+	if err != nil {
+		return nil, 0, err
+	}
+	outstanding, err := s.store.Fines().SumOutstanding(borrowerID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return fines, outstanding, nil
+}
+
+func (s *FineService) GetFine(id uuid.UUID) (*models.Fine, error) {
+	fine, err := s.store.Fines().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrFineNotFound
+		}
+		return nil, err
+	}
+	return fine, nil
+}
+
+// PayFine records an outstanding fine as paid in full.
+func (s *FineService) PayFine(id uuid.UUID) (*models.Fine, error) {
+	return s.settle(id, models.FinePaid)
+}
+
+// WaiveFine writes an outstanding fine off.
+func (s *FineService) WaiveFine(id uuid.UUID) (*models.Fine, error) {
+	return s.settle(id, models.FineWaived)
+}
+
+func (s *FineService) settle(id uuid.UUID, status string) (*models.Fine, error) {
+	fine, err := s.GetFine(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if fine.Status != models.FineOutstanding {
+		return nil, ErrFineNotOutstanding
+	}
+
+	now := time.Now()
+	fine.Status = status
+	fine.SettledAt = &now
+	fine.SettledBy = reqctx.Actor(s.ctx)
+	if err := s.store.Fines().Update(fine); err != nil {
+		return nil, err
+	}
+
+	return fine, nil
+}
@@ -0,0 +1,67 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/reqctx"
+	"library-management-go/internal/services"
+
+	"github.com/google/uuid"
+)
+
+func TestGetFinesByBorrower(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewFineService(store)
+	borrower := fx.Borrower()
+	fx.Fine(borrower, 100)
+	fx.Fine(borrower, 40)
+	fx.Fine(borrower, 60, func(f *models.Fine) { f.Status = models.FinePaid })
+	fx.Fine(fx.Borrower(), 500)
+
+	fines, outstanding, err := svc.GetFinesByBorrower(borrower.ID, "")
+	checkErr(t, err, nil)
+	if len(fines) != 3 || outstanding != 140 {
+		t.Fatalf("got %d fines and %d outstanding, want 3 and 140", len(fines), outstanding)
+	}
+
+	fines, _, err = svc.GetFinesByBorrower(borrower.ID, models.FinePaid)
+	checkErr(t, err, nil)
+	if len(fines) != 1 || fines[0].Amount != 60 {
+		t.Fatalf("expected the paid fine, got %+v", fines)
+	}
+
+	_, _, err = svc.GetFinesByBorrower(borrower.ID, "forgiven")
+	checkErr(t, err, services.ErrInvalidFineStatus)
+}
+
+func TestSettleFine(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewFineService(store).WithContext(reqctx.WithActor(context.Background(), "cashier"))
+	borrower := fx.Borrower()
+	paid := fx.Fine(borrower, 100)
+	waived := fx.Fine(borrower, 40)
+
+	fine, err := svc.PayFine(paid.ID)
+	checkErr(t, err, nil)
+	if fine.Status != models.FinePaid || fine.SettledAt == nil || fine.SettledBy != "cashier" {
+		t.Fatalf("unexpected fine %+v", fine)
+	}
+	fine, err = svc.WaiveFine(waived.ID)
+	checkErr(t, err, nil)
+	if fine.Status != models.FineWaived {
+		t.Fatalf("status = %q, want waived", fine.Status)
+	}
+
+	_, err = svc.WaiveFine(paid.ID)
+	checkErr(t, err, services.ErrFineNotOutstanding)
+	_, err = svc.PayFine(uuid.New())
+	checkErr(t, err, services.ErrFineNotFound)
+
+	_, outstanding, err := svc.GetFinesByBorrower(borrower.ID, "")
+	checkErr(t, err, nil)
+	if outstanding != 0 {
+		t.Fatalf("outstanding = %d, want 0", outstanding)
+	}
+}
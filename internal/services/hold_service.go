@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type HoldService struct {
+	store repository.Store
+}
+
+func NewHoldService(store repository.Store) *HoldService {
+	return &HoldService{store: store}
+}
+
+// WithContext returns a copy of the service whose queries carry ctx, so the
+// request's actor and ID reach the audit log.
+func (s *HoldService) WithContext(ctx context.Context) *HoldService {
+	return &HoldService{store: s.store.WithContext(ctx)}
+}
+
+// PlaceHold queues the borrower for the book. Borrowers who could not
+// borrow it (suspended, expired or blocked) cannot place a hold either.
+func (s *HoldService) PlaceHold(req *models.PlaceHoldRequest) (*models.Hold, error) {
+	// Check if book exists
+	if _, err := s.store.Books().Get(req.BookID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBookNotFound
+		}
+		return nil, err
+	}
+
+	// Check if borrower exists and may borrow
+	borrower, err := s.store.Borrowers().Get(req.BorrowerID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBorrowerNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := checkMembership(borrower, now); err != nil {
+		return nil, err
+	}
+	blocks, err := s.store.BorrowerBlocks().ListActive(req.BorrowerID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBlocks(blocks, false); err != nil {
+		return nil, err
+	}
+
+	// Check if borrower already has the book on loan or on hold
+	_, onLoan, err := s.store.Borrowings().Find(models.BorrowingFilter{
+		BorrowerID: req.BorrowerID, BookID: req.BookID, ActiveOnly: true,
+	}, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	if onLoan > 0 {
+		return nil, ErrAlreadyBorrowing
+	}
+
+	holds, err := s.store.Holds().ListWaiting(req.BookID)
+	if err != nil {
+		return nil, err
+	}
+	for _, hold := range holds {
+		if *hold.BorrowerID == req.BorrowerID {
+			return nil, ErrDuplicateHold
+		}
+	}
+
+	hold := &models.Hold{
+		BookID:     req.BookID,
+		BorrowerID: &req.BorrowerID,
+		Status:     models.HoldWaiting,
+		PlacedAt:   now,
+	}
+	if err := s.store.Holds().Create(hold); err != nil {
+		return nil, err
+	}
+
+	return s.GetHold(hold.ID)
+}
+
+func (s *HoldService) GetHold(id uuid.UUID) (*models.Hold, error) {
+	hold, err := s.store.Holds().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrHoldNotFound
+		}
+		return nil, err
+	}
+	return hold, nil
+}
+
+// GetHoldsByBorrower lists the borrower's holds, newest first, optionally
+// only those still waiting.
+func (s *HoldService) GetHoldsByBorrower(borrowerID uuid.UUID, waitingOnly bool) ([]models.Hold, error) {
+	return s.store.Holds().ListByBorrower(borrowerID, waitingOnly)
+}
+
+// GetHoldQueue lists the waiting holds on a book in the order they will be
+// served.
+func (s *HoldService) GetHoldQueue(bookID uuid.UUID) ([]models.Hold, error) {
+	// Check if book exists
+	if _, err := s.store.Books().Get(bookID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBookNotFound
+		}
+		return nil, err
+	}
+
+	return s.store.Holds().ListWaiting(bookID)
+}
+
+// CountWaiting counts the waiting holds on each of the books; books with
+// none are left out.
+func (s *HoldService) CountWaiting(bookIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	return s.store.Holds().CountWaiting(bookIDs)
+}
+
+func (s *HoldService) CancelHold(id uuid.UUID) (*models.Hold, error) {
+	hold, err := s.GetHold(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if hold.Status != models.HoldWaiting {
+		return nil, ErrHoldNotWaiting
+	}
+
+	now := time.Now()
+	hold.Status = models.HoldCancelled
+	hold.CancelledAt = &now
+	if err := s.store.Holds().Update(hold); err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
@@ -0,0 +1,103 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestPlaceHold(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(fx *testutil.Fixtures) *models.PlaceHoldRequest
+		want    error
+	}{
+		{"book on loan", func(fx *testutil.Fixtures) *models.PlaceHoldRequest {
+			loan := fx.Borrowing(nil, nil)
+			return &models.PlaceHoldRequest{BookID: loan.BookID, BorrowerID: fx.Borrower().ID}
+		}, nil},
+		{"joins the queue", func(fx *testutil.Fixtures) *models.PlaceHoldRequest {
+			hold := fx.Hold(nil, nil)
+			return &models.PlaceHoldRequest{BookID: hold.BookID, BorrowerID: fx.Borrower().ID}
+		}, nil},
+		{"already waiting", func(fx *testutil.Fixtures) *models.PlaceHoldRequest {
+			hold := fx.Hold(nil, nil)
+			return &models.PlaceHoldRequest{BookID: hold.BookID, BorrowerID: *hold.BorrowerID}
+		}, services.ErrDuplicateHold},
+		{"after cancelling", func(fx *testutil.Fixtures) *models.PlaceHoldRequest {
+			hold := fx.Hold(nil, nil, func(h *models.Hold) { h.Status = models.HoldCancelled })
+			return &models.PlaceHoldRequest{BookID: hold.BookID, BorrowerID: *hold.BorrowerID}
+		}, nil},
+		{"already borrowing", func(fx *testutil.Fixtures) *models.PlaceHoldRequest {
+			loan := fx.Borrowing(nil, nil)
+			return &models.PlaceHoldRequest{BookID: loan.BookID, BorrowerID: *loan.BorrowerID}
+		}, services.ErrAlreadyBorrowing},
+		{"blocked", func(fx *testutil.Fixtures) *models.PlaceHoldRequest {
+			borrower := fx.Borrower()
+			fx.Block(borrower, func(b *models.BorrowerBlock) { b.Overridable = true })
+			return &models.PlaceHoldRequest{BookID: fx.Book(nil).ID, BorrowerID: borrower.ID}
+		}, services.ErrBorrowerBlocked},
+		{"membership expired", func(fx *testutil.Fixtures) *models.PlaceHoldRequest {
+			borrower := fx.Borrower(testutil.MembershipExpired(time.Hour))
+			return &models.PlaceHoldRequest{BookID: fx.Book(nil).ID, BorrowerID: borrower.ID}
+		}, services.ErrMembershipExpired},
+		{"unknown book", func(fx *testutil.Fixtures) *models.PlaceHoldRequest {
+			return &models.PlaceHoldRequest{BookID: uuid.New(), BorrowerID: fx.Borrower().ID}
+		}, services.ErrBookNotFound},
+		{"unknown borrower", func(fx *testutil.Fixtures) *models.PlaceHoldRequest {
+			return &models.PlaceHoldRequest{BookID: fx.Book(nil).ID, BorrowerID: uuid.New()}
+		}, services.ErrBorrowerNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewHoldService(store)
+			req := tt.prepare(fx)
+
+			hold, err := svc.PlaceHold(req)
+			checkErr(t, err, tt.want)
+			if tt.want != nil {
+				return
+			}
+
+			if hold.Status != models.HoldWaiting || hold.Book == nil || hold.Book.ID != req.BookID {
+				t.Fatalf("unexpected hold %+v", hold)
+			}
+			queue, err := svc.GetHoldQueue(req.BookID)
+			checkErr(t, err, nil)
+			if queue[len(queue)-1].ID != hold.ID {
+				t.Fatal("expected the new hold at the back of the queue")
+			}
+		})
+	}
+}
+
+func TestCancelHold(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewHoldService(store)
+	hold := fx.Hold(nil, nil)
+
+	cancelled, err := svc.CancelHold(hold.ID)
+	checkErr(t, err, nil)
+	if cancelled.Status != models.HoldCancelled || cancelled.CancelledAt == nil {
+		t.Fatalf("unexpected hold %+v", cancelled)
+	}
+
+	_, err = svc.CancelHold(hold.ID)
+	checkErr(t, err, services.ErrHoldNotWaiting)
+	_, err = svc.CancelHold(uuid.New())
+	checkErr(t, err, services.ErrHoldNotFound)
+
+	queue, err := svc.GetHoldQueue(hold.BookID)
+	checkErr(t, err, nil)
+	if len(queue) != 0 {
+		t.Fatalf("expected an empty queue, got %+v", queue)
+	}
+	_, err = svc.GetHoldQueue(uuid.New())
+	checkErr(t, err, services.ErrBookNotFound)
+}
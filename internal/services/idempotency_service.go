@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"library-management-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrIdempotencyKeyMismatch   = newError(KindInvalid, "idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = newError(KindConflict, "a request with this idempotency key is still being processed")
+)
+
+type IdempotencyService struct {
+	db        *gorm.DB
+	retention time.Duration
+}
+
+func NewIdempotencyService(db *gorm.DB, retention time.Duration) *IdempotencyService {
+	return &IdempotencyService{db: db, retention: retention}
+}
+
+// Reserve claims key for a request. If the key was already used for the same
+// request and has completed, the stored record is returned so the caller can
+// replay it; a nil record means the caller owns the key and must Complete or
+// Release it.
+func (s *IdempotencyService) Reserve(key, method, path, requestHash string) (*models.IdempotencyKey, error) {
+	now := time.Now()
+
+	// Expired keys behave as if they were never used
+	if err := s.db.Where("key = ? AND expires_at < ?", key, now).Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return nil, err
+	}
+
+	record := &models.IdempotencyKey{
+		Key:         key,
+		Method:      method,
+		Path:        path,
+		RequestHash: requestHash,
+		ExpiresAt:   now.Add(s.retention),
+	}
+
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var existing models.IdempotencyKey
+	if err := s.db.First(&existing, "key = ?", key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrIdempotencyKeyInProgress
+		}
+		return nil, err
+	}
+
+	if existing.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyMismatch
+	}
+
+	if existing.StatusCode == 0 {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	return &existing, nil
+}
+
+// Complete stores the response produced for a reserved key.
+func (s *IdempotencyService) Complete(key string, statusCode int, contentType string, body []byte) error {
+	return s.db.Model(&models.IdempotencyKey{}).
+		Where("key = ?", key).
+		Updates(map[string]interface{}{"status_code": statusCode, "content_type": contentType, "response_body": body}).Error
+}
+
+// Release frees a reserved key without storing a response, so the request
+// can be retried.
+func (s *IdempotencyService) Release(key string) error {
+	return s.db.Where("key = ?", key).Delete(&models.IdempotencyKey{}).Error
+}
+
+// PurgeExpired deletes keys whose retention window has passed.
+func (s *IdempotencyService) PurgeExpired() (int64, error) {
+	result := s.db.Where("expires_at < ?", time.Now()).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"library-management-go/internal/barcode"
+	"library-management-go/internal/labels"
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// LabelService renders the barcodes of items and library cards, and sheets
+// of labels to print them on.
+type LabelService struct {
+	store repository.Store
+}
+
+func NewLabelService(store repository.Store) *LabelService {
+	return &LabelService{store: store}
+}
+
+// WithContext returns a copy of the service whose queries carry ctx, so the
+// request's actor and ID reach the audit log.
+func (s *LabelService) WithContext(ctx context.Context) *LabelService {
+	return &LabelService{store: s.store.WithContext(ctx)}
+}
+
+// encodeBarcode encodes text in symbology, Code 128 when empty.
+func encodeBarcode(symbology, text string) (*barcode.Barcode, error) {
+	if symbology == "" {
+		symbology = barcode.Code128
+	}
+	b, err := barcode.Encode(symbology, text)
+	switch {
+	case errors.Is(err, barcode.ErrUnsupportedSymbology):
+		return nil, ErrUnsupportedSymbology
+	case errors.Is(err, barcode.ErrInvalidData):
+		return nil, ErrUnencodableBarcode
+	}
+	return b, err
+}
+
+func (s *LabelService) getBook(id uuid.UUID) (*models.Book, error) {
+	book, err := s.store.Books().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBookNotFound
+		}
+		return nil, err
+	}
+	if book.Barcode == "" {
+		return nil, ErrNoItemBarcode
+	}
+	return book, nil
+}
+
+func (s *LabelService) getBorrower(id uuid.UUID) (*models.Borrower, error) {
+	borrower, err := s.store.Borrowers().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBorrowerNotFound
+		}
+		return nil, err
+	}
+	// Erasure removes the card
+	if borrower.CardNumber == "" {
+		return nil, ErrNoCardNumber
+	}
+	return borrower, nil
+}
+
+// ItemBarcode encodes the book's barcode in symbology.
+func (s *LabelService) ItemBarcode(bookID uuid.UUID, symbology string) (*barcode.Barcode, error) {
+	book, err := s.getBook(bookID)
+	if err != nil {
+		return nil, err
+	}
+	return encodeBarcode(symbology, book.Barcode)
+}
+
+// CardBarcode encodes the borrower's library card number in symbology.
+func (s *LabelService) CardBarcode(borrowerID uuid.UUID, symbology string) (*barcode.Barcode, error) {
+	borrower, err := s.getBorrower(borrowerID)
+	if err != nil {
+		return nil, err
+	}
+	return encodeBarcode(symbology, borrower.CardNumber)
+}
+
+// Layouts lists the built-in label sheets.
+func (s *LabelService) Layouts() []models.LabelLayout {
+	all := labels.Layouts()
+	layouts := make([]models.LabelLayout, len(all))
+	for i, layout := range all {
+		layouts[i] = models.LabelLayout(layout)
+	}
+	return layouts
+}
+
+// render prints sheet on the layout opts choose.
+func render(opts models.LabelSheetOptions, sheet []labels.Label) ([]byte, error) {
+	var layout labels.Layout
+	if opts.CustomLayout != nil {
+		layout = labels.Layout(*opts.CustomLayout)
+	} else {
+		name := opts.Layout
+		if name == "" {
+			name = labels.DefaultLayout
+		}
+		var ok bool
+		if layout, ok = labels.Lookup(name); !ok {
+			return nil, ErrLayoutNotFound
+		}
+	}
+
+	data, err := labels.Render(layout, sheet, opts.Skip)
+	switch {
+	case errors.Is(err, labels.ErrInvalidLayout):
+		return nil, ErrInvalidLabelLayout
+	case errors.Is(err, labels.ErrInvalidSkip):
+		return nil, ErrInvalidLabelSkip
+	case errors.Is(err, labels.ErrBarcodeTooWide):
+		return nil, ErrBarcodeTooWide
+	}
+	return data, err
+}
+
+// BookLabels prints a PDF sheet of item labels, one for each book in the
+// order given, with the title, call number and barcode. A book listed
+// more than once gets as many labels.
+func (s *LabelService) BookLabels(req *models.BookLabelsRequest) ([]byte, error) {
+	sheet := make([]labels.Label, 0, len(req.BookIDs))
+	for _, id := range req.BookIDs {
+		book, err := s.getBook(id)
+		if err != nil {
+			return nil, err
+		}
+		b, err := encodeBarcode(req.Symbology, book.Barcode)
+		if err != nil {
+			return nil, err
+		}
+		lines := []string{book.Title}
+		if book.CallNumber != "" {
+			lines = append(lines, book.CallNumber)
+		}
+		sheet = append(sheet, labels.Label{Lines: lines, Barcode: b})
+	}
+	return render(req.LabelSheetOptions, sheet)
+}
+
+// BorrowerLabels prints a PDF sheet of library card labels, one for each
+// borrower in the order given, with their name and card number barcode.
+func (s *LabelService) BorrowerLabels(req *models.BorrowerLabelsRequest) ([]byte, error) {
+	sheet := make([]labels.Label, 0, len(req.BorrowerIDs))
+	for _, id := range req.BorrowerIDs {
+		borrower, err := s.getBorrower(id)
+		if err != nil {
+			return nil, err
+		}
+		b, err := encodeBarcode(req.Symbology, borrower.CardNumber)
+		if err != nil {
+			return nil, err
+		}
+		sheet = append(sheet, labels.Label{Lines: []string{borrower.Name}, Barcode: b})
+	}
+	return render(req.LabelSheetOptions, sheet)
+}
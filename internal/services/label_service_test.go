@@ -0,0 +1,98 @@
+package services_test
+
+import (
+	"bytes"
+	"testing"
+
+	"library-management-go/internal/barcode"
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestItemBarcode(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewLabelService(store)
+	book := fx.Book(nil)
+	local := fx.Book(nil, func(b *models.Book) { b.Barcode = "lib-42" })
+	erased := fx.Borrower(func(b *models.Borrower) { b.CardNumber = "" })
+
+	b, err := svc.ItemBarcode(book.ID, "")
+	checkErr(t, err, nil)
+	if b.Symbology != barcode.Code128 || b.Text != book.Barcode {
+		t.Fatalf("unexpected barcode %+v", b)
+	}
+
+	// Code 39 has no lowercase letters
+	_, err = svc.ItemBarcode(local.ID, barcode.Code39)
+	checkErr(t, err, services.ErrUnencodableBarcode)
+	_, err = svc.ItemBarcode(book.ID, "qr")
+	checkErr(t, err, services.ErrUnsupportedSymbology)
+	_, err = svc.ItemBarcode(uuid.New(), "")
+	checkErr(t, err, services.ErrBookNotFound)
+
+	_, err = svc.CardBarcode(fx.Borrower().ID, barcode.Code39)
+	checkErr(t, err, nil)
+	_, err = svc.CardBarcode(erased.ID, "")
+	checkErr(t, err, services.ErrNoCardNumber)
+}
+
+func TestBookLabels(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewLabelService(store)
+	first := fx.Book(nil, testutil.CallNumber("QA76.73 .G63"))
+	second := fx.Book(nil)
+
+	data, err := svc.BookLabels(&models.BookLabelsRequest{BookIDs: []uuid.UUID{first.ID, second.ID, first.ID}})
+	checkErr(t, err, nil)
+	if !bytes.HasPrefix(data, []byte("%PDF-")) || !bytes.Contains(data, []byte("/Count 1 ")) {
+		t.Fatal("expected a one page PDF")
+	}
+
+	custom := &models.LabelLayout{PageWidth: 100, PageHeight: 50, Columns: 2, Rows: 1, LabelWidth: 48, LabelHeight: 48, ColumnPitch: 50}
+	tests := []struct {
+		name string
+		opts models.LabelSheetOptions
+		ids  []uuid.UUID
+		want error
+	}{
+		{"named layout", models.LabelSheetOptions{Layout: "avery-l7160", Skip: 20}, nil, nil},
+		{"custom layout", models.LabelSheetOptions{CustomLayout: custom}, nil, nil},
+		{"unknown layout", models.LabelSheetOptions{Layout: "avery-9999"}, nil, services.ErrLayoutNotFound},
+		{"labels off the page", models.LabelSheetOptions{CustomLayout: &models.LabelLayout{PageWidth: 100, PageHeight: 50,
+			Columns: 3, Rows: 1, LabelWidth: 48, LabelHeight: 48, ColumnPitch: 50}}, nil, services.ErrInvalidLabelLayout},
+		{"skip a whole sheet", models.LabelSheetOptions{CustomLayout: custom, Skip: 2}, nil, services.ErrInvalidLabelSkip},
+		{"Code 39 on mini labels", models.LabelSheetOptions{Layout: "avery-l7651", Symbology: barcode.Code39}, nil, services.ErrBarcodeTooWide},
+		{"unknown book", models.LabelSheetOptions{}, []uuid.UUID{uuid.New()}, services.ErrBookNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := tt.ids
+			if ids == nil {
+				ids = []uuid.UUID{first.ID}
+			}
+			_, err := svc.BookLabels(&models.BookLabelsRequest{BookIDs: ids, LabelSheetOptions: tt.opts})
+			checkErr(t, err, tt.want)
+		})
+	}
+}
+
+func TestBorrowerLabels(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewLabelService(store)
+	borrower := fx.Borrower()
+	erased := fx.Borrower(func(b *models.Borrower) { b.CardNumber = "" })
+
+	data, err := svc.BorrowerLabels(&models.BorrowerLabelsRequest{BorrowerIDs: []uuid.UUID{borrower.ID}})
+	checkErr(t, err, nil)
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		t.Fatal("expected a PDF")
+	}
+
+	_, err = svc.BorrowerLabels(&models.BorrowerLabelsRequest{BorrowerIDs: []uuid.UUID{borrower.ID, erased.ID}})
+	checkErr(t, err, services.ErrNoCardNumber)
+	_, err = svc.BorrowerLabels(&models.BorrowerLabelsRequest{BorrowerIDs: []uuid.UUID{uuid.New()}})
+	checkErr(t, err, services.ErrBorrowerNotFound)
+}
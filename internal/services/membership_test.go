@@ -0,0 +1,289 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"library-management-go/internal/cardnumber"
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateBorrowerMembership(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBorrowerService(store)
+	yearly := fx.BorrowerCategory(func(c *models.BorrowerCategory) { c.MembershipDays = 365 })
+	lifetime := fx.BorrowerCategory(func(c *models.BorrowerCategory) { c.MembershipDays = 0 })
+	inAMonth := time.Now().AddDate(0, 1, 0)
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	tests := []struct {
+		name       string
+		req        models.CreateBorrowerRequest
+		wantExpiry *time.Time
+		want       error
+	}{
+		{"no category", models.CreateBorrowerRequest{}, nil, nil},
+		{"yearly category", models.CreateBorrowerRequest{CategoryID: yearly.ID}, ptr(time.Now().AddDate(1, 0, 0)), nil},
+		{"lifetime category", models.CreateBorrowerRequest{CategoryID: lifetime.ID}, nil, nil},
+		{"explicit expiry", models.CreateBorrowerRequest{CategoryID: yearly.ID, MembershipExpiresAt: &inAMonth}, &inAMonth, nil},
+		{"expiry in the past", models.CreateBorrowerRequest{MembershipExpiresAt: &yesterday}, nil, services.ErrInvalidMembershipExpiry},
+		{"unknown category", models.CreateBorrowerRequest{CategoryID: uuid.New()}, nil, services.ErrCategoryNotFound},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Name = "Reader"
+			tt.req.Email = "reader" + string(rune('a'+i)) + "@example.com"
+
+			borrower, err := svc.CreateBorrower(&tt.req)
+			checkErr(t, err, tt.want)
+			if tt.want != nil {
+				return
+			}
+			if !cardnumber.Valid(borrower.CardNumber) {
+				t.Fatalf("card number %q is not valid", borrower.CardNumber)
+			}
+			if borrower.Status != models.BorrowerActive || borrower.MembershipStart.IsZero() {
+				t.Fatalf("expected an active membership starting now, got %+v", borrower)
+			}
+			switch {
+			case tt.wantExpiry == nil && borrower.MembershipExpiresAt != nil:
+				t.Fatalf("expected no expiry, got %v", borrower.MembershipExpiresAt)
+			case tt.wantExpiry != nil && (borrower.MembershipExpiresAt == nil ||
+				borrower.MembershipExpiresAt.Sub(*tt.wantExpiry).Abs() > time.Minute):
+				t.Fatalf("expiry = %v, want %v", borrower.MembershipExpiresAt, *tt.wantExpiry)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestGetBorrowerByCard(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBorrowerService(store)
+	borrower := fx.Borrower()
+
+	// Change the last digit so the check digit no longer matches
+	mistyped := []byte(borrower.CardNumber)
+	mistyped[len(mistyped)-1] = '0' + (mistyped[len(mistyped)-1]-'0'+1)%10
+
+	tests := []struct {
+		name   string
+		number string
+		want   error
+	}{
+		{"existing", borrower.CardNumber, nil},
+		{"mistyped", string(mistyped), services.ErrInvalidCardNumber},
+		{"unknown", "20000000000014", services.ErrBorrowerNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.GetBorrowerByCard(tt.number)
+			checkErr(t, err, tt.want)
+			if tt.want == nil && got.ID != borrower.ID {
+				t.Fatalf("got borrower %v, want %v", got.ID, borrower.ID)
+			}
+		})
+	}
+}
+
+func TestRenewMembership(t *testing.T) {
+	inAMonth := time.Now().AddDate(0, 1, 0)
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	tests := []struct {
+		name       string
+		prepare    func(fx *testutil.Fixtures) *models.Borrower
+		req        models.RenewMembershipRequest
+		wantExpiry time.Time
+		wantStatus string
+		want       error
+	}{
+		{"lapsed membership renews from today", func(fx *testutil.Fixtures) *models.Borrower {
+			category := fx.BorrowerCategory(func(c *models.BorrowerCategory) { c.MembershipDays = 30 })
+			return fx.Borrower(testutil.InCategory(category), testutil.MembershipExpired(48*time.Hour),
+				func(b *models.Borrower) { b.Status = models.BorrowerExpired })
+		}, models.RenewMembershipRequest{}, time.Now().AddDate(0, 0, 30), models.BorrowerActive, nil},
+		{"current membership extends from expiry", func(fx *testutil.Fixtures) *models.Borrower {
+			category := fx.BorrowerCategory(func(c *models.BorrowerCategory) { c.MembershipDays = 30 })
+			return fx.Borrower(testutil.InCategory(category), func(b *models.Borrower) { b.MembershipExpiresAt = ptr(inAMonth) })
+		}, models.RenewMembershipRequest{}, inAMonth.AddDate(0, 0, 30), models.BorrowerActive, nil},
+		{"explicit expiry", func(fx *testutil.Fixtures) *models.Borrower {
+			return fx.Borrower(testutil.MembershipExpired(time.Hour))
+		}, models.RenewMembershipRequest{ExpiresAt: &inAMonth}, inAMonth, models.BorrowerActive, nil},
+		{"suspended stays suspended", func(fx *testutil.Fixtures) *models.Borrower {
+			return fx.Borrower(func(b *models.Borrower) { b.Status = models.BorrowerSuspended })
+		}, models.RenewMembershipRequest{ExpiresAt: &inAMonth}, inAMonth, models.BorrowerSuspended, nil},
+		{"expiry in the past", func(fx *testutil.Fixtures) *models.Borrower {
+			return fx.Borrower()
+		}, models.RenewMembershipRequest{ExpiresAt: &yesterday}, time.Time{}, "", services.ErrInvalidMembershipExpiry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			borrower := tt.prepare(fx)
+
+			renewed, err := services.NewBorrowerService(store).RenewMembership(borrower.ID, &tt.req)
+			checkErr(t, err, tt.want)
+			if tt.want != nil {
+				return
+			}
+			if renewed.Status != tt.wantStatus {
+				t.Fatalf("status = %q, want %q", renewed.Status, tt.wantStatus)
+			}
+			if renewed.MembershipExpiresAt == nil || renewed.MembershipExpiresAt.Sub(tt.wantExpiry).Abs() > time.Minute {
+				t.Fatalf("expiry = %v, want %v", renewed.MembershipExpiresAt, tt.wantExpiry)
+			}
+		})
+	}
+
+	t.Run("unknown borrower", func(t *testing.T) {
+		store, _ := setup(t)
+		_, err := services.NewBorrowerService(store).RenewMembership(uuid.New(), &models.RenewMembershipRequest{})
+		checkErr(t, err, services.ErrBorrowerNotFound)
+	})
+}
+
+func TestUpdateExpiredStatus(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBorrowerService(store)
+	lapsed := fx.Borrower(testutil.MembershipExpired(time.Hour))
+	current := fx.Borrower()
+
+	checkErr(t, svc.UpdateExpiredStatus(), nil)
+
+	got, err := svc.GetBorrower(lapsed.ID)
+	checkErr(t, err, nil)
+	if got.Status != models.BorrowerExpired {
+		t.Fatalf("lapsed borrower status = %q, want expired", got.Status)
+	}
+	got, err = svc.GetBorrower(current.ID)
+	checkErr(t, err, nil)
+	if got.Status != models.BorrowerActive {
+		t.Fatalf("current borrower status = %q, want active", got.Status)
+	}
+}
+
+func TestBorrowerCategories(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBorrowerService(store)
+
+	child, err := svc.CreateCategory(&models.CreateBorrowerCategoryRequest{Code: "CHILD", Name: "Child", MaxActiveLoans: 3})
+	checkErr(t, err, nil)
+	_, err = svc.CreateCategory(&models.CreateBorrowerCategoryRequest{Code: "CHILD", Name: "Another"})
+	checkErr(t, err, services.ErrDuplicateCategoryCode)
+
+	researcher, err := svc.CreateCategory(&models.CreateBorrowerCategoryRequest{Code: "RESEARCH", Name: "Visiting researcher", MembershipDays: 90})
+	checkErr(t, err, nil)
+	_, err = svc.UpdateCategory(researcher.ID, &models.UpdateBorrowerCategoryRequest{Code: "CHILD"})
+	checkErr(t, err, services.ErrDuplicateCategoryCode)
+	updated, err := svc.UpdateCategory(researcher.ID, &models.UpdateBorrowerCategoryRequest{MembershipDays: ptr(180)})
+	checkErr(t, err, nil)
+	if updated.MembershipDays != 180 || updated.Code != "RESEARCH" {
+		t.Fatalf("unexpected category after update: %+v", updated)
+	}
+
+	// A category with borrowers cannot be deleted
+	fx.Borrower(testutil.InCategory(child))
+	checkErr(t, svc.DeleteCategory(child.ID), services.ErrCategoryInUse)
+	checkErr(t, svc.DeleteCategory(researcher.ID), nil)
+	_, err = svc.GetCategory(researcher.ID)
+	checkErr(t, err, services.ErrCategoryNotFound)
+}
+
+func TestAuthenticate(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBorrowerService(store)
+	borrower := fx.Borrower()
+	withoutPIN := fx.Borrower()
+	checkErr(t, svc.SetPIN(borrower.ID, &models.SetPINRequest{PIN: "4321"}), nil)
+
+	tests := []struct {
+		name string
+		req  models.LoginRequest
+		want error
+	}{
+		{"correct PIN", models.LoginRequest{CardNumber: borrower.CardNumber, PIN: "4321"}, nil},
+		{"wrong PIN", models.LoginRequest{CardNumber: borrower.CardNumber, PIN: "1234"}, services.ErrInvalidCredentials},
+		{"no PIN set", models.LoginRequest{CardNumber: withoutPIN.CardNumber, PIN: ""}, services.ErrInvalidCredentials},
+		{"unknown card", models.LoginRequest{CardNumber: "20000000000014", PIN: "4321"}, services.ErrInvalidCredentials},
+		{"malformed card", models.LoginRequest{CardNumber: "12345", PIN: "4321"}, services.ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.Authenticate(&tt.req)
+			checkErr(t, err, tt.want)
+			if tt.want == nil && got.ID != borrower.ID {
+				t.Fatalf("authenticated %v, want %v", got.ID, borrower.ID)
+			}
+		})
+	}
+
+	checkErr(t, svc.SetPIN(uuid.New(), &models.SetPINRequest{PIN: "4321"}), services.ErrBorrowerNotFound)
+}
+
+func TestAuthenticateLockout(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBorrowerService(store)
+	borrower := fx.Borrower()
+	checkErr(t, svc.SetPIN(borrower.ID, &models.SetPINRequest{PIN: "4321"}), nil)
+	right := &models.LoginRequest{CardNumber: borrower.CardNumber, PIN: "4321"}
+	wrong := &models.LoginRequest{CardNumber: borrower.CardNumber, PIN: "0000"}
+
+	// Signing in clears earlier failures
+	for i := 0; i < services.MaxPINAttempts-1; i++ {
+		_, err := svc.Authenticate(wrong)
+		checkErr(t, err, services.ErrInvalidCredentials)
+	}
+	_, err := svc.Authenticate(right)
+	checkErr(t, err, nil)
+
+	for i := 0; i < services.MaxPINAttempts; i++ {
+		_, err := svc.Authenticate(wrong)
+		checkErr(t, err, services.ErrInvalidCredentials)
+	}
+	_, err = svc.Authenticate(right)
+	checkErr(t, err, services.ErrInvalidCredentials)
+
+	// The lockout passes
+	locked, err := store.Borrowers().Get(borrower.ID)
+	checkErr(t, err, nil)
+	if locked.PINLockedUntil == nil || locked.PINLockedUntil.Before(time.Now().Add(services.PINLockout-time.Minute)) {
+		t.Fatalf("locked until %v", locked.PINLockedUntil)
+	}
+	past := time.Now().Add(-time.Minute)
+	locked.PINLockedUntil = &past
+	checkErr(t, store.Borrowers().Update(locked), nil)
+	_, err = svc.Authenticate(right)
+	checkErr(t, err, nil)
+
+	// So does setting a new PIN
+	for i := 0; i < services.MaxPINAttempts; i++ {
+		_, err := svc.Authenticate(wrong)
+		checkErr(t, err, services.ErrInvalidCredentials)
+	}
+	checkErr(t, svc.SetPIN(borrower.ID, &models.SetPINRequest{PIN: "8765"}), nil)
+	_, err = svc.Authenticate(&models.LoginRequest{CardNumber: borrower.CardNumber, PIN: "8765"})
+	checkErr(t, err, nil)
+}
+
+func TestCheckSession(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewBorrowerService(store)
+	borrower := fx.Borrower()
+	withoutPIN := fx.Borrower()
+	checkErr(t, svc.SetPIN(borrower.ID, &models.SetPINRequest{PIN: "4321"}), nil)
+	signedIn, err := svc.Authenticate(&models.LoginRequest{CardNumber: borrower.CardNumber, PIN: "4321"})
+	checkErr(t, err, nil)
+
+	checkErr(t, svc.CheckSession(borrower.ID, signedIn.PINVersion), nil)
+	checkErr(t, svc.CheckSession(withoutPIN.ID, 0), services.ErrInvalidCredentials)
+	checkErr(t, svc.CheckSession(uuid.New(), 0), services.ErrInvalidCredentials)
+
+	checkErr(t, svc.SetPIN(borrower.ID, &models.SetPINRequest{PIN: "8765"}), nil)
+	checkErr(t, svc.CheckSession(borrower.ID, signedIn.PINVersion), services.ErrInvalidCredentials)
+}
@@ -0,0 +1,18 @@
+package services
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// NormalizePagination applies the page defaults and bounds shared by every
+// list endpoint, whichever transport it is served over.
+func NormalizePagination(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > MaxPageSize {
+		limit = DefaultPageSize
+	}
+	return page, limit
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/reqctx"
+
+	"github.com/google/uuid"
+)
+
+// PrivacyService enforces the library's reading-history policy: returned
+// loans are detached from their borrower once they are older than the
+// retention period, unless the borrower has opted in to keeping their
+// history. The loans themselves are kept, so circulation statistics per
+// book, branch and period are unaffected. It also answers borrowers'
+// requests to see or erase the data held about them.
+type PrivacyService struct {
+	store     repository.Store
+	ctx       context.Context
+	retention time.Duration
+}
+
+func NewPrivacyService(store repository.Store, retention time.Duration) *PrivacyService {
+	return &PrivacyService{store: store, ctx: context.Background(), retention: retention}
+}
+
+func (s *PrivacyService) WithContext(ctx context.Context) *PrivacyService {
+	return &PrivacyService{store: s.store.WithContext(ctx), ctx: ctx, retention: s.retention}
+}
+
+// AnonymizeHistory anonymizes loans returned, and holds fulfilled or
+// cancelled, before the retention cutoff and returns how many of each were
+// anonymized. Loans with outstanding fines are left until the fine is
+// settled.
+func (s *PrivacyService) AnonymizeHistory() (loans, holds int64, err error) {
+	now := time.Now()
+	cutoff := now.Add(-s.retention)
+
+	err = s.store.Transaction(func(tx repository.Store) error {
+		var err error
+		if loans, err = tx.Borrowings().AnonymizeReturnedBefore(cutoff, now); err != nil {
+			return err
+		}
+		holds, err = tx.Holds().AnonymizeClosedBefore(cutoff, now)
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return loans, holds, nil
+}
+
+// GetBorrowerData gathers everything held about the borrower: their
+// profile, loans, fines, holds and blocks, and the audit entries about any
+// of them or made by the borrower through the self-service API. Borrowers
+// in the trash are included, since their data is kept until it is purged.
+func (s *PrivacyService) GetBorrowerData(id uuid.UUID) (*models.BorrowerData, error) {
+	borrower, err := s.store.Borrowers().GetIncludingDeleted(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBorrowerNotFound
+		}
+		return nil, err
+	}
+
+	data := &models.BorrowerData{GeneratedAt: time.Now(), Borrower: borrower}
+
+	err = s.store.Borrowings().Export(models.BorrowingFilter{BorrowerID: id}, exportBatchSize, func(batch []models.Borrowing) error {
+		for _, loan := range batch {
+			// The borrower is already at the top of the export
+			loan.Borrower = nil
+			data.Loans = append(data.Loans, loan)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data.Fines, err = s.store.Fines().ListByBorrower(id, ""); err != nil {
+		return nil, err
+	}
+	if data.Holds, err = s.store.Holds().ListByBorrower(id, false); err != nil {
+		return nil, err
+	}
+	if data.Blocks, err = s.store.BorrowerBlocks().ListByBorrower(id); err != nil {
+		return nil, err
+	}
+
+	if data.AuditLog, err = s.auditTrail(data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// auditTrail collects the audit entries about the records in data or made
+// by its borrower, oldest first.
+func (s *PrivacyService) auditTrail(data *models.BorrowerData) ([]models.AuditLog, error) {
+	entities := map[string][]uuid.UUID{"borrower": {data.Borrower.ID}}
+	for _, loan := range data.Loans {
+		entities["borrowing"] = append(entities["borrowing"], loan.ID)
+	}
+	for _, fine := range data.Fines {
+		entities["fine"] = append(entities["fine"], fine.ID)
+	}
+	for _, hold := range data.Holds {
+		entities["hold"] = append(entities["hold"], hold.ID)
+	}
+	for _, block := range data.Blocks {
+		entities["borrower_block"] = append(entities["borrower_block"], block.ID)
+	}
+
+	seen := map[uuid.UUID]bool{}
+	trail := []models.AuditLog{}
+	collect := func(entries []models.AuditLog) {
+		for _, entry := range entries {
+			if !seen[entry.ID] {
+				seen[entry.ID] = true
+				trail = append(trail, entry)
+			}
+		}
+	}
+
+	for entityType, ids := range entities {
+		entries, err := s.store.AuditLogs().ListForEntities(entityType, ids)
+		if err != nil {
+			return nil, err
+		}
+		collect(entries)
+	}
+	entries, err := s.store.AuditLogs().ListByActor(reqctx.BorrowerActor(data.Borrower.ID))
+	if err != nil {
+		return nil, err
+	}
+	collect(entries)
+
+	sort.SliceStable(trail, func(i, j int) bool { return trail[i].CreatedAt.Before(trail[j].CreatedAt) })
+	return trail, nil
+}
+
+// EraseBorrower removes the borrower's personal data on request while
+// keeping the circulation records that refer to them: waiting holds are
+// cancelled, returned loans and closed holds anonymized, blocks lifted and
+// their notes cleared, and their contact details, card number and PIN
+// removed from the borrower record and its audit entries. Borrowers with
+// loans still out or outstanding fines cannot be erased. Borrowers in the
+// trash can be erased too.
+func (s *PrivacyService) EraseBorrower(id uuid.UUID) (*models.Borrower, error) {
+	var erased *models.Borrower
+
+	err := s.store.Transaction(func(tx repository.Store) error {
+		borrower, err := tx.Borrowers().GetIncludingDeleted(id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrBorrowerNotFound
+			}
+			return err
+		}
+		if borrower.Status == models.BorrowerErased {
+			return ErrBorrowerErased
+		}
+
+		// Check if the borrower still has books or owes money
+		_, active, err := tx.Borrowings().Find(models.BorrowingFilter{BorrowerID: id, ActiveOnly: true}, 0, 1)
+		if err != nil {
+			return err
+		}
+		if active > 0 {
+			return ErrErasureActiveLoans
+		}
+		owed, err := tx.Fines().SumOutstanding(id)
+		if err != nil {
+			return err
+		}
+		if owed > 0 {
+			return ErrErasureOutstandingFines
+		}
+
+		now := time.Now()
+		actor := reqctx.Actor(s.ctx)
+
+		holds, err := tx.Holds().ListByBorrower(id, true)
+		if err != nil {
+			return err
+		}
+		for i := range holds {
+			holds[i].Status = models.HoldCancelled
+			holds[i].CancelledAt = &now
+			if err := tx.Holds().Update(&holds[i]); err != nil {
+				return err
+			}
+		}
+
+		blocks, err := tx.BorrowerBlocks().ListByBorrower(id)
+		if err != nil {
+			return err
+		}
+		blockIDs := make([]uuid.UUID, len(blocks))
+		for i := range blocks {
+			blockIDs[i] = blocks[i].ID
+			blocks[i].Note = ""
+			if blocks[i].LiftedAt == nil {
+				blocks[i].LiftedAt = &now
+				blocks[i].LiftedBy = actor
+			}
+			if err := tx.BorrowerBlocks().Update(&blocks[i]); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.Borrowings().AnonymizeByBorrower(id, now); err != nil {
+			return err
+		}
+		if _, err := tx.Holds().AnonymizeByBorrower(id, now); err != nil {
+			return err
+		}
+
+		borrower.Name = "Erased borrower"
+		// Emails must stay unique, and the .invalid domain never resolves
+		borrower.Email = fmt.Sprintf("erased-%s@erased.invalid", id)
+		borrower.Phone = ""
+		borrower.Address = ""
+		borrower.CardNumber = ""
+		borrower.PINHash = ""
+		borrower.KeepHistory = false
+		borrower.Status = models.BorrowerErased
+		borrower.ErasedAt = &now
+		if err := tx.Borrowers().Update(borrower); err != nil {
+			return err
+		}
+
+		// Runs last so it also covers the audit entries written above
+		if err := tx.AuditLogs().ScrubFields("borrower", []uuid.UUID{id}, "name", "email", "phone", "address", "card_number"); err != nil {
+			return err
+		}
+		if err := tx.AuditLogs().ScrubFields("borrower_block", blockIDs, "note"); err != nil {
+			return err
+		}
+
+		erased = borrower
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return erased, nil
+}
@@ -0,0 +1,198 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestAnonymizeHistory(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewPrivacyService(store, 30*24*time.Hour)
+	borrower := fx.Borrower()
+	keeper := fx.Borrower(func(b *models.Borrower) { b.KeepHistory = true })
+
+	old := fx.Borrowing(nil, borrower, testutil.ReturnedAgo(60*24*time.Hour))
+	fx.Borrowing(nil, borrower, testutil.ReturnedAgo(24*time.Hour))
+	fx.Borrowing(nil, borrower)
+	fx.Borrowing(nil, keeper, testutil.ReturnedAgo(60*24*time.Hour))
+
+	oldHold := fx.Hold(nil, borrower, func(h *models.Hold) {
+		h.Status = models.HoldFulfilled
+		h.FulfilledAt = ptr(time.Now().AddDate(0, -2, 0))
+	})
+	fx.Hold(nil, borrower)
+
+	anonymized, holds, err := svc.AnonymizeHistory()
+	checkErr(t, err, nil)
+	if anonymized != 1 || holds != 1 {
+		t.Fatalf("anonymized %d loans and %d holds, want 1 of each", anonymized, holds)
+	}
+	if got, err := store.Holds().Get(oldHold.ID); err != nil || got.BorrowerID != nil {
+		t.Fatalf("expected the old hold to be anonymized, got %+v, %v", got, err)
+	}
+
+	borrowings := services.NewBorrowingService(store)
+	history, total, err := borrowings.GetBorrowingsByBorrower(borrower.ID, 1, 10)
+	checkErr(t, err, nil)
+	if total != 2 {
+		t.Fatalf("history total = %d, want 2", total)
+	}
+	for _, b := range history {
+		if b.ID == old.ID {
+			t.Fatal("expected the old loan to be gone from the borrower's history")
+		}
+	}
+	got, err := borrowings.GetBorrowing(old.ID)
+	checkErr(t, err, nil)
+	if got.BorrowerID != nil || got.AnonymizedAt == nil {
+		t.Fatalf("unexpected loan %+v", got)
+	}
+
+	// Opting out later lets the job catch up
+	_, err = services.NewBorrowerService(store).UpdateBorrower(keeper.ID, &models.UpdateBorrowerRequest{KeepHistory: ptr(false)})
+	checkErr(t, err, nil)
+	anonymized, _, err = svc.AnonymizeHistory()
+	checkErr(t, err, nil)
+	if anonymized != 1 {
+		t.Fatalf("anonymized %d loans after opting out, want 1", anonymized)
+	}
+}
+
+func TestGetBorrowerData(t *testing.T) {
+	store, fx := setup(t)
+	borrower := fx.Borrower()
+	loan := fx.Borrowing(nil, borrower)
+	fx.Borrowing(nil, nil)
+	fx.Fine(borrower, 100)
+	fx.Hold(nil, borrower)
+	fx.Block(borrower)
+	svc := services.NewPrivacyService(store, time.Hour)
+
+	data, err := svc.GetBorrowerData(borrower.ID)
+	checkErr(t, err, nil)
+	if data.Borrower.ID != borrower.ID || len(data.Loans) != 1 || len(data.Fines) != 1 ||
+		len(data.Holds) != 1 || len(data.Blocks) != 1 {
+		t.Fatalf("unexpected data %+v", data)
+	}
+	if data.Loans[0].ID != loan.ID || data.Loans[0].Borrower != nil {
+		t.Fatalf("unexpected loan %+v", data.Loans[0])
+	}
+
+	// One entry for the borrower and each of their records
+	entities := map[string]int{}
+	for i, entry := range data.AuditLog {
+		entities[entry.EntityType]++
+		if i > 0 && entry.CreatedAt.Before(data.AuditLog[i-1].CreatedAt) {
+			t.Fatal("expected the audit log oldest first")
+		}
+	}
+	for _, entity := range []string{"borrower", "borrowing", "fine", "hold", "borrower_block"} {
+		if entities[entity] == 0 {
+			t.Fatalf("expected audit entries for %s, got %v", entity, entities)
+		}
+	}
+
+	_, err = svc.GetBorrowerData(uuid.New())
+	checkErr(t, err, services.ErrBorrowerNotFound)
+}
+
+func TestEraseBorrower(t *testing.T) {
+	var hold *models.Hold
+	history := func(fx *testutil.Fixtures, borrower *models.Borrower) {
+		loan := fx.Borrowing(nil, borrower, testutil.Returned)
+		fx.Fine(borrower, 100, func(f *models.Fine) { f.BorrowingID = &loan.ID; f.Status = models.FinePaid })
+		hold = fx.Hold(nil, borrower)
+		fx.Block(borrower, func(b *models.BorrowerBlock) { b.Note = "shouted at staff" })
+	}
+	tests := []struct {
+		name    string
+		prepare func(fx *testutil.Fixtures, borrower *models.Borrower)
+		want    error
+	}{
+		{"returned loans and settled fines", history, nil},
+		{"borrower in the trash", func(fx *testutil.Fixtures, borrower *models.Borrower) {
+			history(fx, borrower)
+			fx.SoftDelete(borrower)
+		}, nil},
+		{"loan still out", func(fx *testutil.Fixtures, borrower *models.Borrower) {
+			fx.Borrowing(nil, borrower, testutil.Overdue(time.Hour))
+		}, services.ErrErasureActiveLoans},
+		{"outstanding fine", func(fx *testutil.Fixtures, borrower *models.Borrower) {
+			fx.Fine(borrower, 100)
+		}, services.ErrErasureOutstandingFines},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewPrivacyService(store, time.Hour)
+			borrower := fx.Borrower()
+			tt.prepare(fx, borrower)
+
+			erased, err := svc.EraseBorrower(borrower.ID)
+			checkErr(t, err, tt.want)
+			if tt.want != nil {
+				return
+			}
+
+			if erased.Status != models.BorrowerErased || erased.ErasedAt == nil || erased.Name == borrower.Name ||
+				erased.Email == borrower.Email || erased.CardNumber != "" || erased.Phone != "" {
+				t.Fatalf("expected personal fields to be removed, got %+v", erased)
+			}
+
+			data, err := svc.GetBorrowerData(borrower.ID)
+			checkErr(t, err, nil)
+			if len(data.Loans) != 0 {
+				t.Fatalf("expected loans to be anonymized, got %+v", data.Loans)
+			}
+			if len(data.Fines) != 1 || data.Fines[0].BorrowingID != nil {
+				t.Fatalf("expected the fine to be kept but detached, got %+v", data.Fines)
+			}
+			if len(data.Holds) != 0 {
+				t.Fatalf("expected holds to be anonymized, got %+v", data.Holds)
+			}
+			cancelled, err := store.Holds().Get(hold.ID)
+			checkErr(t, err, nil)
+			if cancelled.Status != models.HoldCancelled || cancelled.BorrowerID != nil {
+				t.Fatalf("expected the hold to be cancelled and anonymized, got %+v", cancelled)
+			}
+			if data.Blocks[0].Note != "" || data.Blocks[0].LiftedAt == nil {
+				t.Fatalf("expected the block to be lifted and its note cleared, got %+v", data.Blocks[0])
+			}
+			for _, entry := range data.AuditLog {
+				for _, value := range []string{borrower.Name, borrower.Email, borrower.CardNumber, "shouted at staff"} {
+					if strings.Contains(string(entry.Changes), value) {
+						t.Fatalf("audit entry %v still records %q: %s", entry.ID, value, entry.Changes)
+					}
+				}
+			}
+
+			_, err = svc.EraseBorrower(borrower.ID)
+			checkErr(t, err, services.ErrBorrowerErased)
+			if borrower.DeletedAt.Valid {
+				// Erasure leaves the borrower in the trash
+				trashed, err := store.Borrowers().GetDeleted(borrower.ID)
+				checkErr(t, err, nil)
+				if trashed.Status != models.BorrowerErased || trashed.Email == borrower.Email {
+					t.Fatalf("expected the erasure to be stored, got %+v", trashed)
+				}
+				return
+			}
+			_, err = services.NewBorrowerService(store).RenewMembership(borrower.ID, &models.RenewMembershipRequest{})
+			checkErr(t, err, services.ErrBorrowerErased)
+			_, err = services.NewBorrowingService(store).BorrowBook(&models.BorrowBookRequest{
+				BookID: fx.Book(nil).ID, BorrowerID: borrower.ID, DueDate: time.Now().Add(24 * time.Hour),
+			})
+			checkErr(t, err, services.ErrBorrowerErased)
+		})
+	}
+
+	_, err := services.NewPrivacyService(testutil.NewStore(t), time.Hour).EraseBorrower(uuid.New())
+	checkErr(t, err, services.ErrBorrowerNotFound)
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// PublicationService manages the publishers, series and works that books'
+// edition metadata refers to.
+type PublicationService struct {
+	store repository.Store
+}
+
+func NewPublicationService(store repository.Store) *PublicationService {
+	return &PublicationService{store: store}
+}
+
+// WithContext returns a copy of the service whose queries carry ctx, so the
+// request's actor and ID reach the audit log.
+func (s *PublicationService) WithContext(ctx context.Context) *PublicationService {
+	return &PublicationService{store: s.store.WithContext(ctx)}
+}
+
+// checkPublisherName returns ErrDuplicatePublisher if an active publisher
+// other than excludeID is already called name.
+func (s *PublicationService) checkPublisherName(name string, excludeID uuid.UUID) error {
+	if _, err := s.store.Publishers().FindByName(name, excludeID); err == nil {
+		return ErrDuplicatePublisher
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// checkSeriesName returns ErrDuplicateSeries if an active series other than
+// excludeID is already called name.
+func (s *PublicationService) checkSeriesName(name string, excludeID uuid.UUID) error {
+	if _, err := s.store.Series().FindByName(name, excludeID); err == nil {
+		return ErrDuplicateSeries
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (s *PublicationService) CreatePublisher(req *models.CreatePublisherRequest) (*models.Publisher, error) {
+	// Check if name already exists
+	if err := s.checkPublisherName(req.Name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	publisher := &models.Publisher{
+		Name:    req.Name,
+		Place:   req.Place,
+		Website: req.Website,
+	}
+
+	if err := s.store.Publishers().Create(publisher); err != nil {
+		return nil, err
+	}
+
+	return publisher, nil
+}
+
+func (s *PublicationService) GetPublisher(id uuid.UUID) (*models.Publisher, error) {
+	publisher, err := s.store.Publishers().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrPublisherNotFound
+		}
+		return nil, err
+	}
+	return publisher, nil
+}
+
+// FindPublishers lists the publishers whose name matches search in name
+// order.
+func (s *PublicationService) FindPublishers(search string, page, limit int) ([]models.Publisher, int64, error) {
+	offset := (page - 1) * limit
+	return s.store.Publishers().Find(search, offset, limit)
+}
+
+func (s *PublicationService) UpdatePublisher(id uuid.UUID, req *models.UpdatePublisherRequest) (*models.Publisher, error) {
+	publisher, err := s.GetPublisher(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if name already exists (if provided and different)
+	if req.Name != "" && req.Name != publisher.Name {
+		if err := s.checkPublisherName(req.Name, id); err != nil {
+			return nil, err
+		}
+		publisher.Name = req.Name
+	}
+
+	// Update fields
+	if req.Place != "" {
+		publisher.Place = req.Place
+	}
+	if req.Website != "" {
+		publisher.Website = req.Website
+	}
+
+	if err := s.store.Publishers().Update(publisher); err != nil {
+		return nil, err
+	}
+
+	return publisher, nil
+}
+
+func (s *PublicationService) DeletePublisher(id uuid.UUID) error {
+	publisher, err := s.GetPublisher(id)
+	if err != nil {
+		return err
+	}
+
+	// Check if publisher has books
+	bookCount, err := s.store.Publishers().CountBooks(id)
+	if err != nil {
+		return err
+	}
+	if bookCount > 0 {
+		return ErrPublisherInUse
+	}
+
+	return s.store.Publishers().Delete(publisher)
+}
+
+func (s *PublicationService) CreateSeries(req *models.CreateSeriesRequest) (*models.Series, error) {
+	// Check if name already exists
+	if err := s.checkSeriesName(req.Name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	series := &models.Series{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := s.store.Series().Create(series); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+func (s *PublicationService) GetSeries(id uuid.UUID) (*models.Series, error) {
+	series, err := s.store.Series().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrSeriesNotFound
+		}
+		return nil, err
+	}
+	return series, nil
+}
+
+// FindSeries lists the series whose name matches search in name order.
+func (s *PublicationService) FindSeries(search string, page, limit int) ([]models.Series, int64, error) {
+	offset := (page - 1) * limit
+	return s.store.Series().Find(search, offset, limit)
+}
+
+func (s *PublicationService) UpdateSeries(id uuid.UUID, req *models.UpdateSeriesRequest) (*models.Series, error) {
+	series, err := s.GetSeries(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if name already exists (if provided and different)
+	if req.Name != "" && req.Name != series.Name {
+		if err := s.checkSeriesName(req.Name, id); err != nil {
+			return nil, err
+		}
+		series.Name = req.Name
+	}
+
+	// Update fields
+	if req.Description != "" {
+		series.Description = req.Description
+	}
+
+	if err := s.store.Series().Update(series); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+func (s *PublicationService) DeleteSeries(id uuid.UUID) error {
+	series, err := s.GetSeries(id)
+	if err != nil {
+		return err
+	}
+
+	// Check if series has books
+	bookCount, err := s.store.Series().CountBooks(id)
+	if err != nil {
+		return err
+	}
+	if bookCount > 0 {
+		return ErrSeriesInUse
+	}
+
+	return s.store.Series().Delete(series)
+}
+
+func (s *PublicationService) CreateWork(req *models.CreateWorkRequest) (*models.Work, error) {
+	work := &models.Work{Title: req.Title}
+
+	if err := s.store.Works().Create(work); err != nil {
+		return nil, err
+	}
+
+	return work, nil
+}
+
+// GetWork returns the work with its editions, oldest first.
+func (s *PublicationService) GetWork(id uuid.UUID) (*models.Work, error) {
+	work, err := s.store.Works().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWorkNotFound
+		}
+		return nil, err
+	}
+	return work, nil
+}
+
+// FindWorks lists the works whose title matches search in title order.
+func (s *PublicationService) FindWorks(search string, page, limit int) ([]models.Work, int64, error) {
+	offset := (page - 1) * limit
+	return s.store.Works().Find(search, offset, limit)
+}
+
+func (s *PublicationService) UpdateWork(id uuid.UUID, req *models.UpdateWorkRequest) (*models.Work, error) {
+	work, err := s.GetWork(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields
+	if req.Title != "" {
+		work.Title = req.Title
+	}
+
+	if err := s.store.Works().Update(work); err != nil {
+		return nil, err
+	}
+
+	return work, nil
+}
+
+func (s *PublicationService) DeleteWork(id uuid.UUID) error {
+	work, err := s.GetWork(id)
+	if err != nil {
+		return err
+	}
+
+	// Check if work has editions
+	bookCount, err := s.store.Works().CountBooks(id)
+	if err != nil {
+		return err
+	}
+	if bookCount > 0 {
+		return ErrWorkInUse
+	}
+
+	return s.store.Works().Delete(work)
+}
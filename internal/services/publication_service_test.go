@@ -0,0 +1,145 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestCreatePublisher(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewPublicationService(store)
+	existing := fx.Publisher()
+	deleted := fx.Publisher()
+	checkErr(t, svc.DeletePublisher(deleted.ID), nil)
+
+	tests := []struct {
+		name string
+		req  models.CreatePublisherRequest
+		want error
+	}{
+		{"new name", models.CreatePublisherRequest{Name: "Bloomsbury", Place: "London"}, nil},
+		{"duplicate name", models.CreatePublisherRequest{Name: strings.ToLower(existing.Name)}, services.ErrDuplicatePublisher},
+		{"name of deleted publisher", models.CreatePublisherRequest{Name: deleted.Name}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher, err := svc.CreatePublisher(&tt.req)
+			checkErr(t, err, tt.want)
+			if tt.want == nil && publisher.ID == uuid.Nil {
+				t.Fatal("expected publisher to be assigned an ID")
+			}
+		})
+	}
+}
+
+func TestUpdatePublisher(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewPublicationService(store)
+	publisher := fx.Publisher()
+	taken := fx.Publisher()
+
+	updated, err := svc.UpdatePublisher(publisher.ID, &models.UpdatePublisherRequest{Place: "New York"})
+	checkErr(t, err, nil)
+	if updated.Place != "New York" || updated.Name != publisher.Name {
+		t.Fatalf("unexpected publisher %+v", updated)
+	}
+
+	_, err = svc.UpdatePublisher(publisher.ID, &models.UpdatePublisherRequest{Name: taken.Name})
+	checkErr(t, err, services.ErrDuplicatePublisher)
+	_, err = svc.UpdatePublisher(uuid.New(), &models.UpdatePublisherRequest{Name: "Nobody"})
+	checkErr(t, err, services.ErrPublisherNotFound)
+}
+
+func TestCreateSeries(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewPublicationService(store)
+	existing := fx.Series()
+
+	_, err := svc.CreateSeries(&models.CreateSeriesRequest{Name: "Earthsea"})
+	checkErr(t, err, nil)
+	_, err = svc.CreateSeries(&models.CreateSeriesRequest{Name: strings.ToUpper(existing.Name)})
+	checkErr(t, err, services.ErrDuplicateSeries)
+
+	updated, err := svc.UpdateSeries(existing.ID, &models.UpdateSeriesRequest{Description: "Five novels"})
+	checkErr(t, err, nil)
+	if updated.Description != "Five novels" || updated.Name != existing.Name {
+		t.Fatalf("unexpected series %+v", updated)
+	}
+	_, err = svc.UpdateSeries(existing.ID, &models.UpdateSeriesRequest{Name: "Earthsea"})
+	checkErr(t, err, services.ErrDuplicateSeries)
+}
+
+func TestGetWork(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewPublicationService(store)
+	work, err := svc.CreateWork(&models.CreateWorkRequest{Title: "Dracula"})
+	checkErr(t, err, nil)
+	fx.Book(nil, testutil.EditionOf(work))
+	fx.Book(nil, testutil.EditionOf(work))
+	fx.Book(nil)
+
+	got, err := svc.GetWork(work.ID)
+	checkErr(t, err, nil)
+	if len(got.Editions) != 2 {
+		t.Fatalf("got %d editions, want 2", len(got.Editions))
+	}
+
+	_, err = svc.GetWork(uuid.New())
+	checkErr(t, err, services.ErrWorkNotFound)
+}
+
+func TestDeletePublications(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(fx *testutil.Fixtures) func(svc *services.PublicationService) error
+		want    error
+	}{
+		{"unused publisher", func(fx *testutil.Fixtures) func(svc *services.PublicationService) error {
+			id := fx.Publisher().ID
+			return func(svc *services.PublicationService) error { return svc.DeletePublisher(id) }
+		}, nil},
+		{"publisher with books", func(fx *testutil.Fixtures) func(svc *services.PublicationService) error {
+			publisher := fx.Publisher()
+			fx.Book(nil, func(b *models.Book) { b.PublisherID = &publisher.ID })
+			return func(svc *services.PublicationService) error { return svc.DeletePublisher(publisher.ID) }
+		}, services.ErrPublisherInUse},
+		{"unused series", func(fx *testutil.Fixtures) func(svc *services.PublicationService) error {
+			id := fx.Series().ID
+			return func(svc *services.PublicationService) error { return svc.DeleteSeries(id) }
+		}, nil},
+		{"series with books", func(fx *testutil.Fixtures) func(svc *services.PublicationService) error {
+			series := fx.Series()
+			fx.Book(nil, testutil.Volume(series, 1))
+			return func(svc *services.PublicationService) error { return svc.DeleteSeries(series.ID) }
+		}, services.ErrSeriesInUse},
+		{"unused work", func(fx *testutil.Fixtures) func(svc *services.PublicationService) error {
+			id := fx.Work().ID
+			return func(svc *services.PublicationService) error { return svc.DeleteWork(id) }
+		}, nil},
+		{"work with editions", func(fx *testutil.Fixtures) func(svc *services.PublicationService) error {
+			work := fx.Work()
+			fx.Book(nil, testutil.EditionOf(work))
+			return func(svc *services.PublicationService) error { return svc.DeleteWork(work.ID) }
+		}, services.ErrWorkInUse},
+		{"unknown publisher", func(*testutil.Fixtures) func(svc *services.PublicationService) error {
+			return func(svc *services.PublicationService) error { return svc.DeletePublisher(uuid.New()) }
+		}, services.ErrPublisherNotFound},
+		{"unknown series", func(*testutil.Fixtures) func(svc *services.PublicationService) error {
+			return func(svc *services.PublicationService) error { return svc.DeleteSeries(uuid.New()) }
+		}, services.ErrSeriesNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			del := tt.prepare(fx)
+
+			checkErr(t, del(services.NewPublicationService(store)), tt.want)
+		})
+	}
+}
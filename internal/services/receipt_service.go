@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"errors"
+	netmail "net/mail"
+	"time"
+
+	"library-management-go/internal/mail"
+	"library-management-go/internal/models"
+	"library-management-go/internal/receipt"
+	"library-management-go/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// maxReceiptLoans caps the other loans listed on a receipt.
+const maxReceiptLoans = 50
+
+// ReceiptService builds the receipts for checking out, renewing and
+// returning items and emails them to borrowers. Receipts are not stored:
+// each is built from its loan when asked for, so reprints reflect any
+// later renewal, fine payment or anonymization.
+type ReceiptService struct {
+	store  repository.Store
+	ctx    context.Context
+	sender mail.Sender
+}
+
+// NewReceiptService returns a service that emails receipts with sender, or
+// cannot email them if sender is nil.
+func NewReceiptService(store repository.Store, sender mail.Sender) *ReceiptService {
+	return &ReceiptService{store: store, ctx: context.Background(), sender: sender}
+}
+
+func (s *ReceiptService) WithContext(ctx context.Context) *ReceiptService {
+	return &ReceiptService{store: s.store.WithContext(ctx), ctx: ctx, sender: s.sender}
+}
+
+// GetReceipt returns the receipt for the loan's transaction of kind, or for
+// its latest transaction when kind is empty.
+func (s *ReceiptService) GetReceipt(borrowingID uuid.UUID, kind string) (*models.Receipt, error) {
+	borrowing, err := s.getBorrowing(borrowingID)
+	if err != nil {
+		return nil, err
+	}
+	return s.build(borrowing, kind)
+}
+
+// EmailReceipt sends the receipt GetReceipt returns to the borrower, as
+// plain text with an HTML alternative.
+func (s *ReceiptService) EmailReceipt(borrowingID uuid.UUID, kind string) (*models.Receipt, error) {
+	if s.sender == nil {
+		return nil, ErrEmailNotConfigured
+	}
+
+	borrowing, err := s.getBorrowing(borrowingID)
+	if err != nil {
+		return nil, err
+	}
+	// Anonymized loans have no borrower, and erased borrowers no address
+	if borrowing.Borrower == nil || borrowing.Borrower.Status == models.BorrowerErased {
+		return nil, ErrNoReceiptRecipient
+	}
+
+	r, err := s.build(borrowing, kind)
+	if err != nil {
+		return nil, err
+	}
+	html, err := receipt.HTML(r)
+	if err != nil {
+		return nil, err
+	}
+	subject := receipt.Title(r)
+	if r.Library != "" {
+		subject += " from " + r.Library
+	}
+
+	to := netmail.Address{Name: borrowing.Borrower.Name, Address: borrowing.Borrower.Email}
+	err = s.sender.Send(s.ctx, &mail.Message{
+		To:      to.String(),
+		Subject: subject,
+		Text:    string(receipt.Text(r, receipt.DefaultWidth)),
+		HTML:    string(html),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (s *ReceiptService) getBorrowing(id uuid.UUID) (*models.Borrowing, error) {
+	borrowing, err := s.store.Borrowings().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBorrowingNotFound
+		}
+		return nil, err
+	}
+	return borrowing, nil
+}
+
+// latestReceiptKind returns the kind of the loan's latest transaction.
+func latestReceiptKind(borrowing *models.Borrowing) string {
+	switch {
+	case borrowing.ReturnedAt != nil:
+		return models.ReceiptReturn
+	case borrowing.RenewalCount > 0:
+		return models.ReceiptRenewal
+	}
+	return models.ReceiptCheckout
+}
+
+// build assembles the receipt for the loan's transaction of kind.
+func (s *ReceiptService) build(borrowing *models.Borrowing, kind string) (*models.Receipt, error) {
+	if kind == "" {
+		kind = latestReceiptKind(borrowing)
+	}
+
+	r := &models.Receipt{
+		Kind:        kind,
+		BorrowingID: borrowing.ID,
+		Item: models.ReceiptItem{
+			Title:        borrowing.Book.Title,
+			Barcode:      borrowing.Book.Barcode,
+			CallNumber:   borrowing.Book.CallNumber,
+			DueDate:      borrowing.DueDate,
+			ReturnedAt:   borrowing.ReturnedAt,
+			RenewalCount: borrowing.RenewalCount,
+		},
+		OnLoan: []models.ReceiptItem{},
+	}
+
+	// Check if the loan has had the transaction, and where it took place
+	branchID := borrowing.BranchID
+	switch kind {
+	case models.ReceiptCheckout:
+		r.Date = borrowing.BorrowedAt
+	case models.ReceiptRenewal:
+		if borrowing.RenewalCount == 0 {
+			return nil, ErrLoanNotRenewed
+		}
+		// Loans renewed before the renewal time was recorded fall back to
+		// their last update
+		r.Date = borrowing.UpdatedAt
+		if borrowing.RenewedAt != nil {
+			r.Date = *borrowing.RenewedAt
+		}
+	case models.ReceiptReturn:
+		if borrowing.ReturnedAt == nil {
+			return nil, ErrLoanNotReturned
+		}
+		r.Date = *borrowing.ReturnedAt
+		branchID = borrowing.ReturnBranchID
+	default:
+		return nil, ErrInvalidReceiptKind
+	}
+
+	tenant, err := s.store.Tenants().Current()
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if tenant != nil {
+		r.Library = tenant.Name
+	}
+	if branchID != nil {
+		branch, err := s.store.Branches().Get(*branchID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		if branch != nil {
+			r.Branch = branch.Name
+		}
+	}
+
+	borrower := borrowing.Borrower
+	if borrower == nil {
+		return r, nil
+	}
+	r.BorrowerName = borrower.Name
+	if n := len(borrower.CardNumber); n > 4 {
+		r.CardNumber = "****" + borrower.CardNumber[n-4:]
+	}
+
+	now := time.Now()
+	loans, _, err := s.store.Borrowings().Find(models.BorrowingFilter{BorrowerID: borrower.ID, ActiveOnly: true}, 0, maxReceiptLoans)
+	if err != nil {
+		return nil, err
+	}
+	for _, loan := range loans {
+		if loan.ID == borrowing.ID {
+			continue
+		}
+		r.OnLoan = append(r.OnLoan, models.ReceiptItem{
+			Title:        loan.Book.Title,
+			Barcode:      loan.Book.Barcode,
+			CallNumber:   loan.Book.CallNumber,
+			DueDate:      loan.DueDate,
+			RenewalCount: loan.RenewalCount,
+			Overdue:      now.After(loan.DueDate),
+		})
+	}
+
+	if kind == models.ReceiptReturn {
+		fines, err := s.store.Fines().ListByBorrower(borrower.ID, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, fine := range fines {
+			if fine.BorrowingID != nil && *fine.BorrowingID == borrowing.ID {
+				r.Fine += fine.Amount
+			}
+		}
+	}
+	if r.Balance, err = s.store.Fines().SumOutstanding(borrower.ID); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
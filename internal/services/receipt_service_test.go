@@ -0,0 +1,132 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"library-management-go/internal/mail"
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+// outbox records the messages sent through it.
+type outbox struct {
+	sent []*mail.Message
+}
+
+func (o *outbox) Send(_ context.Context, msg *mail.Message) error {
+	o.sent = append(o.sent, msg)
+	return nil
+}
+
+func TestGetReceipt(t *testing.T) {
+	store, _ := setup(t)
+	scoped, fx := scopedTo(t, store, "receipts", models.DefaultTenantSettings)
+	svc := services.NewReceiptService(scoped, nil)
+	borrowing := services.NewBorrowingService(scoped)
+
+	branch := fx.Branch(func(b *models.Branch) { b.Name = "Main Street" })
+	borrower := fx.Borrower()
+	book := fx.Book(nil, testutil.CallNumber("QA76.73 .G63"))
+	loan, err := borrowing.BorrowBook(&models.BorrowBookRequest{BookID: book.ID, BorrowerID: borrower.ID,
+		DueDate: time.Now().Add(7 * 24 * time.Hour), BranchID: branch.ID})
+	checkErr(t, err, nil)
+	other := fx.Borrowing(nil, borrower, testutil.Overdue(time.Hour))
+
+	r, err := svc.GetReceipt(loan.ID, "")
+	checkErr(t, err, nil)
+	if r.Kind != models.ReceiptCheckout || r.Library != "receipts" || r.Branch != "Main Street" {
+		t.Fatalf("unexpected receipt %+v", r)
+	}
+	if r.Item.Title != book.Title || r.Item.Barcode != book.Barcode || r.Item.CallNumber != "QA76.73 .G63" {
+		t.Fatalf("unexpected item %+v", r.Item)
+	}
+	if r.CardNumber != "****"+borrower.CardNumber[len(borrower.CardNumber)-4:] {
+		t.Fatalf("card number %q is not masked", r.CardNumber)
+	}
+	if len(r.OnLoan) != 1 || r.OnLoan[0].Title != other.Book.Title || !r.OnLoan[0].Overdue {
+		t.Fatalf("unexpected other loans %+v", r.OnLoan)
+	}
+
+	_, err = svc.GetReceipt(loan.ID, models.ReceiptRenewal)
+	checkErr(t, err, services.ErrLoanNotRenewed)
+	_, err = svc.GetReceipt(loan.ID, models.ReceiptReturn)
+	checkErr(t, err, services.ErrLoanNotReturned)
+	_, err = svc.GetReceipt(loan.ID, "refund")
+	checkErr(t, err, services.ErrInvalidReceiptKind)
+	_, err = svc.GetReceipt(uuid.New(), "")
+	checkErr(t, err, services.ErrBorrowingNotFound)
+
+	// Returning the overdue loan charges a fine, which the return receipt
+	// shows alongside the balance
+	_, err = borrowing.ReturnBook(&models.ReturnBookRequest{BorrowingID: other.ID})
+	checkErr(t, err, nil)
+	fx.Fine(borrower, 100)
+	r, err = svc.GetReceipt(other.ID, "")
+	checkErr(t, err, nil)
+	if r.Kind != models.ReceiptReturn || r.Fine != 25 || r.Balance != 125 || r.Item.ReturnedAt == nil {
+		t.Fatalf("unexpected return receipt %+v", r)
+	}
+	if len(r.OnLoan) != 1 || r.OnLoan[0].Title != book.Title {
+		t.Fatalf("unexpected other loans %+v", r.OnLoan)
+	}
+	// The checkout receipt can still be reprinted
+	_, err = svc.GetReceipt(other.ID, models.ReceiptCheckout)
+	checkErr(t, err, nil)
+}
+
+func TestGetRenewalReceipt(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewReceiptService(store, nil)
+	loan := fx.Borrowing(nil, nil)
+
+	renewed, err := services.NewBorrowingService(store).RenewBorrowing(loan.ID, &models.RenewBorrowingRequest{})
+	checkErr(t, err, nil)
+
+	r, err := svc.GetReceipt(loan.ID, "")
+	checkErr(t, err, nil)
+	if r.Kind != models.ReceiptRenewal || r.Item.RenewalCount != 1 || !r.Item.DueDate.Equal(renewed.DueDate) {
+		t.Fatalf("unexpected renewal receipt %+v", r)
+	}
+	if !r.Date.Equal(*renewed.RenewedAt) {
+		t.Fatalf("receipt dated %v, renewed at %v", r.Date, renewed.RenewedAt)
+	}
+	// Requests not scoped to a tenant have no library name
+	if r.Library != "" {
+		t.Fatalf("library = %q", r.Library)
+	}
+}
+
+func TestEmailReceipt(t *testing.T) {
+	store, fx := setup(t)
+	sent := &outbox{}
+	svc := services.NewReceiptService(store, sent)
+	borrower := fx.Borrower(func(b *models.Borrower) { b.Name = "Ada Lovelace"; b.Email = "ada@example.com" })
+	loan := fx.Borrowing(nil, borrower)
+
+	r, err := svc.EmailReceipt(loan.ID, "")
+	checkErr(t, err, nil)
+	if len(sent.sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(sent.sent))
+	}
+	msg := sent.sent[0]
+	if msg.To != `"Ada Lovelace" <ada@example.com>` || msg.Subject != "Checkout receipt" {
+		t.Fatalf("sent %q to %q", msg.Subject, msg.To)
+	}
+	if !strings.Contains(msg.Text, r.Item.Title) || !strings.Contains(msg.HTML, r.Item.Title) {
+		t.Fatal("expected the title in both parts")
+	}
+
+	// Anonymized loans no longer say whom to send them to
+	anonymous := fx.Borrowing(nil, nil, testutil.Returned, func(b *models.Borrowing) { b.BorrowerID = nil })
+	_, err = svc.EmailReceipt(anonymous.ID, "")
+	checkErr(t, err, services.ErrNoReceiptRecipient)
+
+	_, err = services.NewReceiptService(store, nil).EmailReceipt(loan.ID, "")
+	checkErr(t, err, services.ErrEmailNotConfigured)
+}
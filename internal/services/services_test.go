@@ -0,0 +1,104 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/repository/gormstore"
+	"library-management-go/internal/reqctx"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+// setup returns an empty store and fixtures writing to it.
+func setup(t *testing.T) (repository.Store, *testutil.Fixtures) {
+	t.Helper()
+	store := testutil.NewStore(t)
+	return store, testutil.NewFixtures(t, store)
+}
+
+// checkErr fails the test unless err matches want; a nil want expects no
+// error.
+func checkErr(t *testing.T, err, want error) {
+	t.Helper()
+	if want == nil {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	if !errors.Is(err, want) {
+		t.Fatalf("got error %v, want %v", err, want)
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		entity string
+		create func(ctx context.Context, store repository.Store) (uuid.UUID, error)
+	}{
+		{"AuthorService", "author", func(ctx context.Context, store repository.Store) (uuid.UUID, error) {
+			author, err := services.NewAuthorService(store).WithContext(ctx).
+				CreateAuthor(&models.CreateAuthorRequest{Name: "Audited"})
+			if err != nil {
+				return uuid.Nil, err
+			}
+			return author.ID, nil
+		}},
+		{"BookService", "book", func(ctx context.Context, store repository.Store) (uuid.UUID, error) {
+			author := testutil.NewFixtures(t, store).Author()
+			book, err := services.NewBookService(store).WithContext(ctx).
+				CreateBook(&models.CreateBookRequest{Title: "Audited", ISBN: "9780000000000", Contributors: []models.ContributorRequest{{AuthorID: author.ID}}})
+			if err != nil {
+				return uuid.Nil, err
+			}
+			return book.ID, nil
+		}},
+		{"BorrowerService", "borrower", func(ctx context.Context, store repository.Store) (uuid.UUID, error) {
+			borrower, err := services.NewBorrowerService(store).WithContext(ctx).
+				CreateBorrower(&models.CreateBorrowerRequest{Name: "Audited", Email: "audited@example.com"})
+			if err != nil {
+				return uuid.Nil, err
+			}
+			return borrower.ID, nil
+		}},
+		{"BorrowingService", "borrowing", func(ctx context.Context, store repository.Store) (uuid.UUID, error) {
+			fx := testutil.NewFixtures(t, store)
+			borrowing, err := services.NewBorrowingService(store).WithContext(ctx).BorrowBook(&models.BorrowBookRequest{
+				BookID:     fx.Book(nil).ID,
+				BorrowerID: fx.Borrower().ID,
+				DueDate:    time.Now().Add(time.Hour),
+			})
+			if err != nil {
+				return uuid.Nil, err
+			}
+			return borrowing.ID, nil
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testutil.NewDB(t)
+			ctx := reqctx.WithRequestID(reqctx.WithActor(context.Background(), "librarian@example.com"), "req-1")
+
+			id, err := tt.create(ctx, gormstore.New(db))
+			checkErr(t, err, nil)
+
+			// The audit entry carries the actor and request ID from ctx
+			logs, total, err := services.NewAuditService(db).GetAuditLogs(&models.AuditLogFilter{
+				EntityType: tt.entity,
+				EntityID:   id.String(),
+			}, 1, 10)
+			checkErr(t, err, nil)
+			if total != 1 || logs[0].Actor != "librarian@example.com" || logs[0].RequestID != "req-1" {
+				t.Fatalf("unexpected audit entries %+v", logs)
+			}
+		})
+	}
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+
+	"library-management-go/internal/marc"
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// SubjectService manages the subject and genre vocabulary books are
+// classified by.
+type SubjectService struct {
+	store repository.Store
+}
+
+func NewSubjectService(store repository.Store) *SubjectService {
+	return &SubjectService{store: store}
+}
+
+// WithContext returns a copy of the service whose queries carry ctx, so the
+// request's actor and ID reach the audit log.
+func (s *SubjectService) WithContext(ctx context.Context) *SubjectService {
+	return &SubjectService{store: s.store.WithContext(ctx)}
+}
+
+// checkSubjectName returns ErrDuplicateSubject if another active subject
+// under parentID other than excludeID is already called name.
+func (s *SubjectService) checkSubjectName(parentID *uuid.UUID, name string, excludeID uuid.UUID) error {
+	if _, err := s.store.Subjects().FindByName(parentID, name, excludeID); err == nil {
+		return ErrDuplicateSubject
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// getParent returns the subject a new or moved subject goes under.
+func (s *SubjectService) getParent(id uuid.UUID) (*models.Subject, error) {
+	parent, err := s.store.Subjects().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrParentNotFound
+		}
+		return nil, err
+	}
+	return parent, nil
+}
+
+func (s *SubjectService) CreateSubject(req *models.CreateSubjectRequest) (*models.Subject, error) {
+	subject := &models.Subject{
+		Name:   req.Name,
+		Kind:   req.Kind,
+		Source: req.Source,
+	}
+	if subject.Kind == "" {
+		subject.Kind = models.SubjectTopic
+	}
+
+	// Check if parent exists (if provided)
+	if req.ParentID != uuid.Nil {
+		if _, err := s.getParent(req.ParentID); err != nil {
+			return nil, err
+		}
+		parentID := req.ParentID
+		subject.ParentID = &parentID
+	}
+
+	// Check if the name is already used under the parent
+	if err := s.checkSubjectName(subject.ParentID, req.Name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Subjects().Create(subject); err != nil {
+		return nil, err
+	}
+
+	return s.GetSubject(subject.ID)
+}
+
+func (s *SubjectService) GetSubject(id uuid.UUID) (*models.Subject, error) {
+	subject, err := s.store.Subjects().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrSubjectNotFound
+		}
+		return nil, err
+	}
+	return subject, nil
+}
+
+// FindSubjects lists the subjects matching filter in name order.
+func (s *SubjectService) FindSubjects(filter models.SubjectFilter, page, limit int) ([]models.Subject, int64, error) {
+	offset := (page - 1) * limit
+	return s.store.Subjects().Find(filter, offset, limit)
+}
+
+func (s *SubjectService) UpdateSubject(id uuid.UUID, req *models.UpdateSubjectRequest) (*models.Subject, error) {
+	subject, err := s.GetSubject(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Move under another subject, or to the top level (if provided)
+	if req.ParentID != nil {
+		if *req.ParentID == uuid.Nil {
+			subject.ParentID = nil
+		} else {
+			if err := s.checkMove(id, *req.ParentID); err != nil {
+				return nil, err
+			}
+			parentID := *req.ParentID
+			subject.ParentID = &parentID
+		}
+	}
+
+	// Update fields
+	if req.Name != "" {
+		subject.Name = req.Name
+	}
+	if req.Kind != "" {
+		subject.Kind = req.Kind
+	}
+	if req.Source != nil {
+		subject.Source = *req.Source
+	}
+
+	// Check if the name is already used under the (new) parent
+	if err := s.checkSubjectName(subject.ParentID, subject.Name, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Subjects().Update(subject); err != nil {
+		return nil, err
+	}
+
+	return s.GetSubject(id)
+}
+
+// checkMove returns an error unless the subject id can be moved under
+// parentID without creating a cycle.
+func (s *SubjectService) checkMove(id, parentID uuid.UUID) error {
+	if parentID == id {
+		return ErrSubjectCycle
+	}
+	if _, err := s.getParent(parentID); err != nil {
+		return err
+	}
+
+	descendants, err := s.store.Subjects().Descendants(id)
+	if err != nil {
+		return err
+	}
+	for _, descendant := range descendants {
+		if descendant == parentID {
+			return ErrSubjectCycle
+		}
+	}
+	return nil
+}
+
+func (s *SubjectService) DeleteSubject(id uuid.UUID) error {
+	subject, err := s.GetSubject(id)
+	if err != nil {
+		return err
+	}
+
+	// Check if subject has narrower subjects
+	childCount, err := s.store.Subjects().CountChildren(id)
+	if err != nil {
+		return err
+	}
+	if childCount > 0 {
+		return ErrSubjectHasChildren
+	}
+
+	// Check if books are tagged with it
+	bookCount, err := s.store.Subjects().CountBooks(id)
+	if err != nil {
+		return err
+	}
+	if bookCount > 0 {
+		return ErrSubjectInUse
+	}
+
+	return s.store.Subjects().Delete(subject)
+}
+
+// subjectSources maps the second indicator of a 650 field to the thesaurus
+// its heading comes from. Indicator 7 names the source in subfield 2
+// instead, and 4 leaves it unspecified.
+var subjectSources = map[byte]string{
+	'0': "lcsh",
+	'1': "lcshac",
+	'2': "mesh",
+	'3': "nal",
+	'5': "cash",
+	'6': "rvm",
+}
+
+// term is one level of a subject heading.
+type term struct {
+	name string
+	kind string
+}
+
+// headingTerms splits a 650 topical subject heading into its main term
+// ($a) and subdivisions, broadest first. Form subdivisions ($v) are
+// genres; general ($x), chronological ($y) and geographic ($z) ones are
+// topics.
+func headingTerms(field marc.Field) []term {
+	var terms []term
+	for _, sf := range field.Subfields {
+		var kind string
+		switch sf.Code {
+		case 'a', 'x', 'y', 'z':
+			kind = models.SubjectTopic
+		case 'v':
+			kind = models.SubjectGenre
+		default:
+			continue
+		}
+		// A heading is a single main term followed by subdivisions
+		if (sf.Code == 'a') != (len(terms) == 0) {
+			continue
+		}
+		if name := cleanHeading(sf.Value); name != "" {
+			terms = append(terms, term{name: name, kind: kind})
+		}
+	}
+	return terms
+}
+
+// cleanHeading strips the punctuation cataloguing rules add at the end of
+// a subfield, keeping the full stop of an abbreviation such as "U.S.".
+func cleanHeading(value string) string {
+	value = strings.TrimRight(strings.TrimSpace(value), " ,;:")
+	if n := len(value); n > 1 && value[n-1] == '.' && !unicode.IsUpper(rune(value[n-2])) {
+		value = value[:n-1]
+	}
+	return strings.TrimSpace(value)
+}
+
+// recordISBNs returns the ISBNs in a record's 020 fields, each as written
+// and without hyphens, dropping qualifiers such as "(pbk.)".
+func recordISBNs(record *marc.Record) []string {
+	var isbns []string
+	for _, field := range record.FieldsByTag("020") {
+		parts := strings.Fields(field.Subfield('a'))
+		if len(parts) == 0 {
+			continue
+		}
+		isbns = append(isbns, parts[0])
+		if plain := strings.ReplaceAll(parts[0], "-", ""); plain != parts[0] {
+			isbns = append(isbns, plain)
+		}
+	}
+	return isbns
+}
+
+// ImportMARC adds the topical subject headings (650 fields) of records to
+// the vocabulary, creating each term under the one before it, and tags the
+// catalogue's book with the same ISBN with the narrowest term of each
+// heading. Terms already in the vocabulary are reused.
+func (s *SubjectService) ImportMARC(records []*marc.Record) (*models.SubjectImportResult, error) {
+	result := &models.SubjectImportResult{Records: len(records)}
+	tagged := map[uuid.UUID]bool{}
+
+	err := s.store.Transaction(func(tx repository.Store) error {
+		svc := NewSubjectService(tx)
+		for _, record := range records {
+			book, err := svc.findBook(record)
+			if err != nil {
+				return err
+			}
+
+			for _, field := range record.FieldsByTag("650") {
+				terms := headingTerms(field)
+				if len(terms) == 0 {
+					continue
+				}
+				result.Headings++
+
+				source := subjectSources[field.Ind2]
+				if field.Ind2 == '7' {
+					source = field.Subfield('2')
+				}
+
+				var parentID *uuid.UUID
+				for _, t := range terms {
+					subject, created, err := svc.findOrCreate(parentID, t, source)
+					if err != nil {
+						return err
+					}
+					if created {
+						result.SubjectsCreated++
+					}
+					parentID = &subject.ID
+				}
+
+				if book != nil {
+					if err := tx.Books().AddSubject(book.ID, *parentID); err != nil {
+						return err
+					}
+					tagged[book.ID] = true
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.BooksTagged = len(tagged)
+	return result, nil
+}
+
+// findBook returns the active book sharing an ISBN with record, or nil.
+func (s *SubjectService) findBook(record *marc.Record) (*models.Book, error) {
+	for _, isbn := range recordISBNs(record) {
+		book, err := s.store.Books().FindByISBN(isbn, uuid.Nil)
+		if err == nil {
+			return book, nil
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// findOrCreate returns the subject called t.name under parentID, creating
+// it if there is none.
+func (s *SubjectService) findOrCreate(parentID *uuid.UUID, t term, source string) (*models.Subject, bool, error) {
+	subject, err := s.store.Subjects().FindByName(parentID, t.name, uuid.Nil)
+	if err == nil {
+		return subject, false, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, false, err
+	}
+
+	subject = &models.Subject{ParentID: parentID, Name: t.name, Kind: t.kind, Source: source}
+	if err := s.store.Subjects().Create(subject); err != nil {
+		return nil, false, err
+	}
+	return subject, true, nil
+}
@@ -0,0 +1,251 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"library-management-go/internal/marc"
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateSubject(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewSubjectService(store)
+	parent := fx.Subject(nil)
+	existing := fx.Subject(parent)
+	deleted := fx.Subject(nil)
+	checkErr(t, svc.DeleteSubject(deleted.ID), nil)
+
+	tests := []struct {
+		name string
+		req  models.CreateSubjectRequest
+		want error
+	}{
+		{"top level", models.CreateSubjectRequest{Name: "Poetry"}, nil},
+		{"under a parent", models.CreateSubjectRequest{Name: "Sonnets", ParentID: parent.ID}, nil},
+		{"genre", models.CreateSubjectRequest{Name: "Ghost stories", Kind: models.SubjectGenre, Source: "lcgft"}, nil},
+		{"duplicate name under parent", models.CreateSubjectRequest{Name: strings.ToUpper(existing.Name), ParentID: parent.ID}, services.ErrDuplicateSubject},
+		{"same name at top level", models.CreateSubjectRequest{Name: existing.Name}, nil},
+		{"name of deleted subject", models.CreateSubjectRequest{Name: deleted.Name}, nil},
+		{"unknown parent", models.CreateSubjectRequest{Name: "Orphan", ParentID: uuid.New()}, services.ErrParentNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject, err := svc.CreateSubject(&tt.req)
+			checkErr(t, err, tt.want)
+			if tt.want != nil {
+				return
+			}
+			if subject.Kind == "" {
+				t.Fatal("expected kind to default")
+			}
+			if tt.req.ParentID != uuid.Nil && (subject.Parent == nil || subject.Parent.ID != tt.req.ParentID) {
+				t.Fatalf("expected parent to be loaded, got %+v", subject.Parent)
+			}
+		})
+	}
+}
+
+func TestUpdateSubject(t *testing.T) {
+	topLevel := uuid.Nil
+
+	tests := []struct {
+		name  string
+		req   func(fx *testutil.Fixtures, subject *models.Subject) *models.UpdateSubjectRequest
+		want  error
+		check func(t *testing.T, after *models.Subject)
+	}{
+		{"rename", func(*testutil.Fixtures, *models.Subject) *models.UpdateSubjectRequest {
+			return &models.UpdateSubjectRequest{Name: "Renamed", Kind: models.SubjectGenre}
+		}, nil, func(t *testing.T, after *models.Subject) {
+			if after.Name != "Renamed" || after.Kind != models.SubjectGenre {
+				t.Fatalf("unexpected subject %+v", after)
+			}
+		}},
+		{"move under another subject", func(fx *testutil.Fixtures, _ *models.Subject) *models.UpdateSubjectRequest {
+			parent := fx.Subject(nil)
+			return &models.UpdateSubjectRequest{ParentID: &parent.ID}
+		}, nil, func(t *testing.T, after *models.Subject) {
+			if after.Parent == nil {
+				t.Fatal("expected subject to have a parent")
+			}
+		}},
+		{"move to top level", func(*testutil.Fixtures, *models.Subject) *models.UpdateSubjectRequest {
+			return &models.UpdateSubjectRequest{ParentID: &topLevel}
+		}, nil, func(t *testing.T, after *models.Subject) {
+			if after.ParentID != nil {
+				t.Fatalf("expected top-level subject, got parent %v", after.ParentID)
+			}
+		}},
+		{"under itself", func(_ *testutil.Fixtures, subject *models.Subject) *models.UpdateSubjectRequest {
+			return &models.UpdateSubjectRequest{ParentID: &subject.ID}
+		}, services.ErrSubjectCycle, nil},
+		{"under a narrower subject", func(fx *testutil.Fixtures, subject *models.Subject) *models.UpdateSubjectRequest {
+			grandchild := fx.Subject(fx.Subject(subject))
+			return &models.UpdateSubjectRequest{ParentID: &grandchild.ID}
+		}, services.ErrSubjectCycle, nil},
+		{"unknown parent", func(*testutil.Fixtures, *models.Subject) *models.UpdateSubjectRequest {
+			parentID := uuid.New()
+			return &models.UpdateSubjectRequest{ParentID: &parentID}
+		}, services.ErrParentNotFound, nil},
+		{"name taken by a sibling", func(fx *testutil.Fixtures, subject *models.Subject) *models.UpdateSubjectRequest {
+			sibling := fx.Subject(subject.Parent)
+			return &models.UpdateSubjectRequest{Name: sibling.Name}
+		}, services.ErrDuplicateSubject, nil},
+		{"name taken under the new parent", func(fx *testutil.Fixtures, subject *models.Subject) *models.UpdateSubjectRequest {
+			parent := fx.Subject(nil)
+			fx.Subject(parent, func(s *models.Subject) { s.Name = subject.Name })
+			return &models.UpdateSubjectRequest{ParentID: &parent.ID}
+		}, services.ErrDuplicateSubject, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewSubjectService(store)
+			subject := fx.Subject(fx.Subject(nil))
+			subject.Parent = &models.Subject{ID: *subject.ParentID}
+
+			updated, err := svc.UpdateSubject(subject.ID, tt.req(fx, subject))
+			checkErr(t, err, tt.want)
+			if tt.check != nil {
+				tt.check(t, updated)
+			}
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		store, _ := setup(t)
+		_, err := services.NewSubjectService(store).UpdateSubject(uuid.New(), &models.UpdateSubjectRequest{Name: "x"})
+		checkErr(t, err, services.ErrSubjectNotFound)
+	})
+}
+
+func TestDeleteSubject(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(fx *testutil.Fixtures) uuid.UUID
+		want    error
+	}{
+		{"unused subject", func(fx *testutil.Fixtures) uuid.UUID {
+			return fx.Subject(nil).ID
+		}, nil},
+		{"subject with narrower subjects", func(fx *testutil.Fixtures) uuid.UUID {
+			return *fx.Subject(fx.Subject(nil)).ParentID
+		}, services.ErrSubjectHasChildren},
+		{"subject books are tagged with", func(fx *testutil.Fixtures) uuid.UUID {
+			subject := fx.Subject(nil)
+			fx.Tag(fx.Book(nil), subject)
+			return subject.ID
+		}, services.ErrSubjectInUse},
+		{"unknown", func(*testutil.Fixtures) uuid.UUID {
+			return uuid.New()
+		}, services.ErrSubjectNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fx := setup(t)
+			svc := services.NewSubjectService(store)
+			id := tt.prepare(fx)
+
+			checkErr(t, svc.DeleteSubject(id), tt.want)
+			if tt.want == nil {
+				_, err := svc.GetSubject(id)
+				checkErr(t, err, services.ErrSubjectNotFound)
+			}
+		})
+	}
+}
+
+func TestImportMARC(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewSubjectService(store)
+	catcher := fx.Book(nil, func(b *models.Book) { b.ISBN = "9780316769488" })
+	other := fx.Book(nil)
+
+	// Existing terms are reused rather than duplicated
+	existing, err := svc.CreateSubject(&models.CreateSubjectRequest{Name: "Teenagers"})
+	checkErr(t, err, nil)
+
+	doc := `<collection xmlns="http://www.loc.gov/MARC21/slim">
+  <record>
+    <datafield tag="020" ind1=" " ind2=" "><subfield code="a">978-0-316-76948-8 (pbk.)</subfield></datafield>
+    <datafield tag="650" ind1=" " ind2="0">
+      <subfield code="a">Teenagers</subfield>
+      <subfield code="v">Fiction.</subfield>
+    </datafield>
+    <datafield tag="650" ind1=" " ind2="0">
+      <subfield code="a">New York (N.Y.)</subfield>
+      <subfield code="x">Social life and customs</subfield>
+      <subfield code="y">20th century</subfield>
+      <subfield code="v">Fiction.</subfield>
+    </datafield>
+    <datafield tag="650" ind1=" " ind2="7">
+      <subfield code="a">Runaway teenagers.</subfield>
+      <subfield code="2">fast</subfield>
+    </datafield>
+    <datafield tag="651" ind1=" " ind2="0"><subfield code="a">Ignored</subfield></datafield>
+  </record>
+  <record>
+    <datafield tag="020" ind1=" " ind2=" "><subfield code="a">9999999999999</subfield></datafield>
+    <datafield tag="650" ind1=" " ind2="0"><subfield code="a">Teenagers</subfield></datafield>
+  </record>
+</collection>`
+	records, err := marc.ReadXML(strings.NewReader(doc))
+	checkErr(t, err, nil)
+
+	result, err := svc.ImportMARC(records)
+	checkErr(t, err, nil)
+	want := models.SubjectImportResult{Records: 2, Headings: 4, SubjectsCreated: 6, BooksTagged: 1}
+	if *result != want {
+		t.Fatalf("result = %+v, want %+v", *result, want)
+	}
+
+	book, err := services.NewBookService(store).GetBook(catcher.ID)
+	checkErr(t, err, nil)
+	var names []string
+	for _, s := range book.Subjects {
+		names = append(names, s.Name)
+	}
+	if strings.Join(names, "|") != "Fiction|Fiction|Runaway teenagers" {
+		t.Fatalf("book tagged with %v", names)
+	}
+
+	teenagers, err := svc.GetSubject(existing.ID)
+	checkErr(t, err, nil)
+	if len(teenagers.Children) != 1 || teenagers.Children[0].Kind != models.SubjectGenre {
+		t.Fatalf("expected a genre subdivision under the existing term, got %+v", teenagers.Children)
+	}
+	runaway, _, err := svc.FindSubjects(models.SubjectFilter{Search: "Runaway"}, 1, 10)
+	checkErr(t, err, nil)
+	if len(runaway) != 1 || runaway[0].Source != "fast" {
+		t.Fatalf("expected source from subfield 2, got %+v", runaway)
+	}
+	city, _, err := svc.FindSubjects(models.SubjectFilter{Search: "New York"}, 1, 10)
+	checkErr(t, err, nil)
+	if len(city) != 1 || city[0].Name != "New York (N.Y.)" || city[0].Source != "lcsh" {
+		t.Fatalf("expected LCSH heading to keep its full stop, got %+v", city)
+	}
+
+	// Filtering by the broad term finds the book through its subdivision
+	books, _, err := services.NewBookService(store).FindBooks(models.BookFilter{SubjectID: existing.ID}, 1, 10)
+	checkErr(t, err, nil)
+	if len(books) != 1 || books[0].ID != catcher.ID {
+		t.Fatalf("expected only the tagged book, got %d books", len(books))
+	}
+
+	// Importing again creates and tags nothing new
+	result, err = svc.ImportMARC(records)
+	checkErr(t, err, nil)
+	if result.SubjectsCreated != 0 {
+		t.Fatalf("expected re-import to reuse terms, created %d", result.SubjectsCreated)
+	}
+	book, err = services.NewBookService(store).GetBook(other.ID)
+	checkErr(t, err, nil)
+	if len(book.Subjects) != 0 {
+		t.Fatalf("expected untouched book to have no subjects, got %+v", book.Subjects)
+	}
+}
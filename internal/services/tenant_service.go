@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// tenantSlug matches slugs that are valid DNS labels, so every tenant can
+// be reached on its own subdomain.
+var tenantSlug = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// TenantService provisions the libraries served by a multi-tenant
+// deployment and their configuration.
+type TenantService struct {
+	store repository.Store
+}
+
+func NewTenantService(store repository.Store) *TenantService {
+	return &TenantService{store: store}
+}
+
+// WithContext returns a copy of the service whose queries carry ctx, so the
+// request's actor and ID reach the audit log.
+func (s *TenantService) WithContext(ctx context.Context) *TenantService {
+	return &TenantService{store: s.store.WithContext(ctx)}
+}
+
+func (s *TenantService) CreateTenant(req *models.CreateTenantRequest) (*models.Tenant, error) {
+	if !tenantSlug.MatchString(req.Slug) {
+		return nil, ErrInvalidTenantSlug
+	}
+
+	// Check if slug already exists
+	if _, err := s.store.Tenants().GetBySlug(req.Slug); err == nil {
+		return nil, ErrDuplicateTenantSlug
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	tenant := &models.Tenant{
+		Slug:     req.Slug,
+		Name:     req.Name,
+		Active:   true,
+		Settings: models.DefaultTenantSettings,
+	}
+	applySettings(&tenant.Settings, &req.TenantSettingsRequest)
+
+	if err := s.store.Tenants().Create(tenant); err != nil {
+		return nil, err
+	}
+
+	return tenant, nil
+}
+
+func (s *TenantService) GetTenant(id uuid.UUID) (*models.Tenant, error) {
+	tenant, err := s.store.Tenants().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTenantNotFound
+		}
+		return nil, err
+	}
+	return tenant, nil
+}
+
+func (s *TenantService) GetTenantBySlug(slug string) (*models.Tenant, error) {
+	tenant, err := s.store.Tenants().GetBySlug(slug)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTenantNotFound
+		}
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// GetCurrentTenant returns the tenant the request is scoped to.
+func (s *TenantService) GetCurrentTenant() (*models.Tenant, error) {
+	tenant, err := s.store.Tenants().Current()
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTenantNotFound
+		}
+		return nil, err
+	}
+	return tenant, nil
+}
+
+func (s *TenantService) GetAllTenants(page, limit int) ([]models.Tenant, int64, error) {
+	offset := (page - 1) * limit
+	return s.store.Tenants().List(offset, limit)
+}
+
+func (s *TenantService) UpdateTenant(id uuid.UUID, req *models.UpdateTenantRequest) (*models.Tenant, error) {
+	tenant, err := s.GetTenant(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields if provided
+	if req.Name != "" {
+		tenant.Name = req.Name
+	}
+	if req.Active != nil {
+		tenant.Active = *req.Active
+	}
+	applySettings(&tenant.Settings, &req.TenantSettingsRequest)
+
+	if err := s.store.Tenants().Update(tenant); err != nil {
+		return nil, err
+	}
+
+	return tenant, nil
+}
+
+// applySettings overrides the settings that were provided.
+func applySettings(settings *models.TenantSettings, req *models.TenantSettingsRequest) {
+	if req.MaxActiveLoans != nil {
+		settings.MaxActiveLoans = *req.MaxActiveLoans
+	}
+	if req.BlockOverdueBorrowers != nil {
+		settings.BlockOverdueBorrowers = *req.BlockOverdueBorrowers
+	}
+	if req.LoanPeriodDays != nil {
+		settings.LoanPeriodDays = *req.LoanPeriodDays
+	}
+	if req.MaxRenewals != nil {
+		settings.MaxRenewals = *req.MaxRenewals
+	}
+	if req.FinePerDay != nil {
+		settings.FinePerDay = *req.FinePerDay
+	}
+}
+
+// tenantSettings returns the circulation rules of the tenant store is
+// scoped to, or the defaults when it is not scoped to one.
+func tenantSettings(store repository.Store) (models.TenantSettings, error) {
+	tenant, err := store.Tenants().Current()
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.DefaultTenantSettings, nil
+	}
+	if err != nil {
+		return models.TenantSettings{}, err
+	}
+	return tenant.Settings, nil
+}
@@ -0,0 +1,143 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/reqctx"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+// scopedTo returns store scoped to a new tenant with the given settings,
+// and fixtures that create records in that tenant.
+func scopedTo(t *testing.T, store repository.Store, slug string, settings models.TenantSettings) (repository.Store, *testutil.Fixtures) {
+	t.Helper()
+
+	tenant := &models.Tenant{Slug: slug, Name: slug, Active: true, Settings: settings}
+	if err := store.Tenants().Create(tenant); err != nil {
+		t.Fatalf("create tenant: %v", err)
+	}
+	scoped := store.WithContext(reqctx.WithTenantID(context.Background(), tenant.ID))
+	return scoped, testutil.NewFixtures(t, scoped)
+}
+
+func intPtr(v int) *int    { return &v }
+func boolPtr(v bool) *bool { return &v }
+
+func TestCreateTenant(t *testing.T) {
+	store, _ := setup(t)
+	svc := services.NewTenantService(store)
+	_, err := svc.CreateTenant(&models.CreateTenantRequest{Slug: "north", Name: "North Library"})
+	checkErr(t, err, nil)
+
+	tests := []struct {
+		name string
+		req  models.CreateTenantRequest
+		want error
+	}{
+		{"new slug", models.CreateTenantRequest{Slug: "east-side", Name: "East"}, nil},
+		{"duplicate slug", models.CreateTenantRequest{Slug: "north", Name: "Another North"}, services.ErrDuplicateTenantSlug},
+		{"uppercase slug", models.CreateTenantRequest{Slug: "West", Name: "West"}, services.ErrInvalidTenantSlug},
+		{"dotted slug", models.CreateTenantRequest{Slug: "south.branch", Name: "South"}, services.ErrInvalidTenantSlug},
+		{"leading hyphen", models.CreateTenantRequest{Slug: "-south", Name: "South"}, services.ErrInvalidTenantSlug},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant, err := svc.CreateTenant(&tt.req)
+			checkErr(t, err, tt.want)
+			if tt.want == nil {
+				if !tenant.Active || tenant.Settings != models.DefaultTenantSettings {
+					t.Fatalf("expected an active tenant with default settings, got %+v", tenant)
+				}
+			}
+		})
+	}
+
+	t.Run("custom settings", func(t *testing.T) {
+		tenant, err := svc.CreateTenant(&models.CreateTenantRequest{
+			Slug: "strict", Name: "Strict", TenantSettingsRequest: models.TenantSettingsRequest{
+				MaxActiveLoans: intPtr(2), BlockOverdueBorrowers: boolPtr(false), FinePerDay: ptr(int64(0)),
+			},
+		})
+		checkErr(t, err, nil)
+		want := models.DefaultTenantSettings
+		want.MaxActiveLoans, want.BlockOverdueBorrowers, want.FinePerDay = 2, false, 0
+		if tenant.Settings != want {
+			t.Fatalf("settings = %+v, want %+v", tenant.Settings, want)
+		}
+	})
+}
+
+func TestUpdateTenant(t *testing.T) {
+	store, _ := setup(t)
+	svc := services.NewTenantService(store)
+	tenant, err := svc.CreateTenant(&models.CreateTenantRequest{Slug: "north", Name: "North"})
+	checkErr(t, err, nil)
+
+	updated, err := svc.UpdateTenant(tenant.ID, &models.UpdateTenantRequest{
+		Active: boolPtr(false), TenantSettingsRequest: models.TenantSettingsRequest{MaxActiveLoans: intPtr(10)},
+	})
+	checkErr(t, err, nil)
+	if updated.Name != "North" || updated.Active || updated.Settings.MaxActiveLoans != 10 || !updated.Settings.BlockOverdueBorrowers {
+		t.Fatalf("unexpected tenant after update: %+v", updated)
+	}
+
+	_, err = svc.UpdateTenant(uuid.New(), &models.UpdateTenantRequest{Name: "Nobody"})
+	checkErr(t, err, services.ErrTenantNotFound)
+}
+
+func TestGetCurrentTenant(t *testing.T) {
+	store, _ := setup(t)
+	_, err := services.NewTenantService(store).GetCurrentTenant()
+	checkErr(t, err, services.ErrTenantNotFound)
+
+	scoped, _ := scopedTo(t, store, "north", models.DefaultTenantSettings)
+	tenant, err := services.NewTenantService(scoped).GetCurrentTenant()
+	checkErr(t, err, nil)
+	if tenant.Slug != "north" {
+		t.Fatalf("current tenant = %q, want north", tenant.Slug)
+	}
+}
+
+func TestBorrowBookTenantSettings(t *testing.T) {
+	due := time.Now().Add(14 * 24 * time.Hour)
+	store, _ := setup(t)
+
+	t.Run("loan limit", func(t *testing.T) {
+		scoped, fx := scopedTo(t, store, "small", models.TenantSettings{MaxActiveLoans: 1, BlockOverdueBorrowers: true})
+		borrower := fx.Borrower()
+		fx.Borrowing(nil, borrower)
+
+		_, err := services.NewBorrowingService(scoped).BorrowBook(&models.BorrowBookRequest{
+			BookID: fx.Book(nil).ID, BorrowerID: borrower.ID, DueDate: due,
+		})
+		checkErr(t, err, services.ErrBorrowingLimitReached)
+	})
+
+	t.Run("overdue borrowers allowed", func(t *testing.T) {
+		scoped, fx := scopedTo(t, store, "lenient", models.TenantSettings{MaxActiveLoans: 5, BlockOverdueBorrowers: false})
+		borrower := fx.Borrower()
+		fx.Borrowing(nil, borrower, testutil.Overdue(24*time.Hour))
+
+		_, err := services.NewBorrowingService(scoped).BorrowBook(&models.BorrowBookRequest{
+			BookID: fx.Book(nil).ID, BorrowerID: borrower.ID, DueDate: due,
+		})
+		checkErr(t, err, nil)
+	})
+
+	t.Run("borrower of another tenant", func(t *testing.T) {
+		_, other := scopedTo(t, store, "other", models.DefaultTenantSettings)
+		scoped, fx := scopedTo(t, store, "mine", models.DefaultTenantSettings)
+
+		_, err := services.NewBorrowingService(scoped).BorrowBook(&models.BorrowBookRequest{
+			BookID: fx.Book(nil).ID, BorrowerID: other.Borrower().ID, DueDate: due,
+		})
+		checkErr(t, err, services.ErrBorrowerNotFound)
+	})
+}
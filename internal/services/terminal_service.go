@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/reqctx"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TerminalService manages the self-check kiosks and gates that sign in to
+// the SIP2 server, and checks their credentials when they do.
+type TerminalService struct {
+	store repository.Store
+	ctx   context.Context
+}
+
+func NewTerminalService(store repository.Store) *TerminalService {
+	return &TerminalService{store: store, ctx: context.Background()}
+}
+
+// WithContext returns a copy of the service whose queries carry ctx, so the
+// request's actor and ID reach the audit log.
+func (s *TerminalService) WithContext(ctx context.Context) *TerminalService {
+	return &TerminalService{store: s.store.WithContext(ctx), ctx: ctx}
+}
+
+// checkLogin returns ErrDuplicateSIPLogin if a terminal other than
+// excludeID, in any tenant, already uses login.
+func (s *TerminalService) checkLogin(login string, excludeID uuid.UUID) error {
+	if _, err := s.store.SIPTerminals().FindByLogin(login, excludeID); err == nil {
+		return ErrDuplicateSIPLogin
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// checkBranch returns ErrBranchNotFound unless the branch exists.
+func (s *TerminalService) checkBranch(id uuid.UUID) error {
+	if _, err := s.store.Branches().Get(id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrBranchNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *TerminalService) CreateTerminal(req *models.CreateSIPTerminalRequest) (*models.SIPTerminal, error) {
+	// Check if login already exists
+	if err := s.checkLogin(req.Login, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	terminal := &models.SIPTerminal{
+		Name:   req.Name,
+		Login:  req.Login,
+		Active: true,
+	}
+
+	// Check if branch exists (if provided)
+	if req.BranchID != uuid.Nil {
+		if err := s.checkBranch(req.BranchID); err != nil {
+			return nil, err
+		}
+		branchID := req.BranchID
+		terminal.BranchID = &branchID
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	terminal.PasswordHash = string(hash)
+
+	if err := s.store.SIPTerminals().Create(terminal); err != nil {
+		return nil, err
+	}
+
+	return terminal, nil
+}
+
+func (s *TerminalService) GetTerminal(id uuid.UUID) (*models.SIPTerminal, error) {
+	terminal, err := s.store.SIPTerminals().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTerminalNotFound
+		}
+		return nil, err
+	}
+	return terminal, nil
+}
+
+func (s *TerminalService) GetTerminals(page, limit int) ([]models.SIPTerminal, int64, error) {
+	offset := (page - 1) * limit
+	return s.store.SIPTerminals().List(offset, limit)
+}
+
+func (s *TerminalService) UpdateTerminal(id uuid.UUID, req *models.UpdateSIPTerminalRequest) (*models.SIPTerminal, error) {
+	terminal, err := s.GetTerminal(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if login already exists (if provided and different)
+	if req.Login != "" && req.Login != terminal.Login {
+		if err := s.checkLogin(req.Login, id); err != nil {
+			return nil, err
+		}
+		terminal.Login = req.Login
+	}
+
+	// Check if branch exists (if provided)
+	if req.BranchID != uuid.Nil {
+		if err := s.checkBranch(req.BranchID); err != nil {
+			return nil, err
+		}
+		branchID := req.BranchID
+		terminal.BranchID = &branchID
+	}
+
+	// Update fields
+	if req.Name != "" {
+		terminal.Name = req.Name
+	}
+	if req.Active != nil {
+		terminal.Active = *req.Active
+	}
+	if req.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		terminal.PasswordHash = string(hash)
+	}
+
+	if err := s.store.SIPTerminals().Update(terminal); err != nil {
+		return nil, err
+	}
+
+	return terminal, nil
+}
+
+func (s *TerminalService) DeleteTerminal(id uuid.UUID) error {
+	terminal, err := s.GetTerminal(id)
+	if err != nil {
+		return err
+	}
+	return s.store.SIPTerminals().Delete(terminal)
+}
+
+// Authenticate returns the active terminal with login if password is its
+// own, recording when it signed in. Terminals are looked up across
+// tenants; one whose library has been deactivated cannot sign in. Every
+// failure is reported as ErrInvalidTerminalCredentials.
+func (s *TerminalService) Authenticate(login, password string) (*models.SIPTerminal, error) {
+	terminal, err := s.store.SIPTerminals().FindByLogin(login, uuid.Nil)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidTerminalCredentials
+		}
+		return nil, err
+	}
+
+	if !terminal.Active || bcrypt.CompareHashAndPassword([]byte(terminal.PasswordHash), []byte(password)) != nil {
+		return nil, ErrInvalidTerminalCredentials
+	}
+
+	if terminal.TenantID != uuid.Nil {
+		tenant, err := s.store.Tenants().Get(terminal.TenantID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		if tenant == nil || !tenant.Active {
+			return nil, ErrInvalidTerminalCredentials
+		}
+	}
+
+	// The sign-in is recorded in the terminal's own tenant
+	now := time.Now()
+	terminal.LastLoginAt = &now
+	scoped := s.store.WithContext(reqctx.WithTenantID(s.ctx, terminal.TenantID))
+	if err := scoped.SIPTerminals().Update(terminal); err != nil {
+		return nil, err
+	}
+
+	return terminal, nil
+}
@@ -0,0 +1,88 @@
+package services_test
+
+import (
+	"testing"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateTerminal(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewTerminalService(store)
+	branch := fx.Branch()
+
+	terminal, err := svc.CreateTerminal(&models.CreateSIPTerminalRequest{
+		Name: "Lobby kiosk", Login: "kiosk1", Password: "correct horse", BranchID: branch.ID,
+	})
+	checkErr(t, err, nil)
+	if !terminal.Active || terminal.BranchID == nil || *terminal.BranchID != branch.ID {
+		t.Fatalf("unexpected terminal %+v", terminal)
+	}
+	if terminal.PasswordHash == "" || terminal.PasswordHash == "correct horse" {
+		t.Fatal("expected the password to be hashed")
+	}
+
+	_, err = svc.CreateTerminal(&models.CreateSIPTerminalRequest{Name: "Again", Login: "kiosk1", Password: "password"})
+	checkErr(t, err, services.ErrDuplicateSIPLogin)
+	_, err = svc.CreateTerminal(&models.CreateSIPTerminalRequest{Name: "Gate", Login: "gate1", Password: "password", BranchID: uuid.New()})
+	checkErr(t, err, services.ErrBranchNotFound)
+
+	// Logins are unique across tenants, since they decide the tenant
+	scoped, _ := scopedTo(t, store, "north", models.DefaultTenantSettings)
+	_, err = services.NewTerminalService(scoped).CreateTerminal(&models.CreateSIPTerminalRequest{Name: "North", Login: "kiosk1", Password: "password"})
+	checkErr(t, err, services.ErrDuplicateSIPLogin)
+
+	updated, err := svc.UpdateTerminal(terminal.ID, &models.UpdateSIPTerminalRequest{Login: "kiosk2", Password: "new password"})
+	checkErr(t, err, nil)
+	if updated.Login != "kiosk2" || updated.PasswordHash == terminal.PasswordHash {
+		t.Fatalf("unexpected update %+v", updated)
+	}
+
+	checkErr(t, svc.DeleteTerminal(terminal.ID), nil)
+	_, err = svc.GetTerminal(terminal.ID)
+	checkErr(t, err, services.ErrTerminalNotFound)
+
+	entries, err := store.AuditLogs().ListForEntities("sip_terminal", []uuid.UUID{terminal.ID})
+	checkErr(t, err, nil)
+	if len(entries) != 3 {
+		t.Fatalf("expected create, update and delete to be audited, got %d entries", len(entries))
+	}
+}
+
+func TestAuthenticateTerminal(t *testing.T) {
+	store, _ := setup(t)
+	north, _ := scopedTo(t, store, "north", models.DefaultTenantSettings)
+	terminals := services.NewTerminalService(north)
+	terminal, err := terminals.CreateTerminal(&models.CreateSIPTerminalRequest{Name: "Kiosk", Login: "north-kiosk", Password: "password"})
+	checkErr(t, err, nil)
+
+	// Terminals sign in before anything tells the server their tenant
+	svc := services.NewTerminalService(store)
+	got, err := svc.Authenticate("north-kiosk", "password")
+	checkErr(t, err, nil)
+	if got.ID != terminal.ID || got.TenantID != terminal.TenantID || got.LastLoginAt == nil {
+		t.Fatalf("unexpected terminal %+v", got)
+	}
+
+	_, err = svc.Authenticate("north-kiosk", "wrong")
+	checkErr(t, err, services.ErrInvalidTerminalCredentials)
+	_, err = svc.Authenticate("south-kiosk", "password")
+	checkErr(t, err, services.ErrInvalidTerminalCredentials)
+
+	// Deactivated terminals, and terminals of deactivated libraries, are
+	// turned away
+	_, err = terminals.UpdateTerminal(terminal.ID, &models.UpdateSIPTerminalRequest{Active: boolPtr(false)})
+	checkErr(t, err, nil)
+	_, err = svc.Authenticate("north-kiosk", "password")
+	checkErr(t, err, services.ErrInvalidTerminalCredentials)
+
+	_, err = terminals.UpdateTerminal(terminal.ID, &models.UpdateSIPTerminalRequest{Active: boolPtr(true)})
+	checkErr(t, err, nil)
+	_, err = services.NewTenantService(store).UpdateTenant(terminal.TenantID, &models.UpdateTenantRequest{Active: boolPtr(false)})
+	checkErr(t, err, nil)
+	_, err = svc.Authenticate("north-kiosk", "password")
+	checkErr(t, err, services.ErrInvalidTerminalCredentials)
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// TransferService tracks items travelling between branches after an
+// away-from-home check-in.
+type TransferService struct {
+	store repository.Store
+}
+
+func NewTransferService(store repository.Store) *TransferService {
+	return &TransferService{store: store}
+}
+
+// WithContext returns a copy of the service whose queries carry ctx, so the
+// request's actor and ID reach the audit log.
+func (s *TransferService) WithContext(ctx context.Context) *TransferService {
+	return &TransferService{store: s.store.WithContext(ctx)}
+}
+
+func (s *TransferService) GetTransfer(id uuid.UUID) (*models.Transfer, error) {
+	transfer, err := s.store.Transfers().Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTransferNotFound
+		}
+		return nil, err
+	}
+	return transfer, nil
+}
+
+func (s *TransferService) GetTransfers(filter models.TransferFilter, page, limit int) ([]models.Transfer, int64, error) {
+	offset := (page - 1) * limit
+	return s.store.Transfers().Find(filter, offset, limit)
+}
+
+// ReceiveTransfer records the item's arrival at its home branch and puts it
+// back on the shelf.
+func (s *TransferService) ReceiveTransfer(id uuid.UUID) (*models.Transfer, error) {
+	transfer, err := s.GetTransfer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.Status != models.TransferInTransit {
+		return nil, ErrTransferNotInTransit
+	}
+
+	// The book may have been deleted while in transit
+	if transfer.Book.ID == uuid.Nil {
+		return nil, ErrBookNotFound
+	}
+
+	now := time.Now()
+	transfer.Status = models.TransferReceived
+	transfer.ReceivedAt = &now
+
+	err = s.store.Transaction(func(tx repository.Store) error {
+		if err := tx.Transfers().Update(transfer); err != nil {
+			return err
+		}
+
+		// Shelve the book at its home location
+		book := &transfer.Book
+		location := transfer.ToLocationID
+		book.CurrentLocationID = &location
+		book.InTransit = false
+		book.Available = true
+		return tx.Books().Update(book)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
@@ -0,0 +1,118 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/services"
+	"library-management-go/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+// sendAway lends a book homed at home and returns it at away, producing an
+// in-transit transfer.
+func sendAway(t *testing.T, store repository.Store, fx *testutil.Fixtures, home *models.Location, away *models.Branch) *models.Transfer {
+	t.Helper()
+
+	loan := fx.Borrowing(fx.Book(nil, testutil.ShelvedAt(home)), nil)
+	_, err := services.NewBorrowingService(store).ReturnBook(&models.ReturnBookRequest{BorrowingID: loan.ID, BranchID: away.ID})
+	checkErr(t, err, nil)
+
+	transfers, _, err := services.NewTransferService(store).GetTransfers(models.TransferFilter{}, 1, 100)
+	checkErr(t, err, nil)
+	for _, transfer := range transfers {
+		if transfer.BookID == loan.BookID {
+			return &transfer
+		}
+	}
+	t.Fatal("expected the away return to create a transfer")
+	return nil
+}
+
+func TestGetTransfers(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewTransferService(store)
+	central := fx.Branch()
+	east := fx.Branch()
+	west := fx.Branch()
+	shelf := fx.Location(central)
+
+	sendAway(t, store, fx, shelf, east)
+	received := sendAway(t, store, fx, shelf, west)
+	_, err := svc.ReceiveTransfer(received.ID)
+	checkErr(t, err, nil)
+
+	tests := []struct {
+		name   string
+		filter models.TransferFilter
+		want   int64
+	}{
+		{"all", models.TransferFilter{}, 2},
+		{"from east", models.TransferFilter{FromBranchID: east.ID}, 1},
+		{"to central", models.TransferFilter{ToBranchID: central.ID}, 2},
+		{"in transit", models.TransferFilter{Status: models.TransferInTransit}, 1},
+		{"received", models.TransferFilter{Status: models.TransferReceived}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, total, err := svc.GetTransfers(tt.filter, 1, 10)
+			checkErr(t, err, nil)
+			if total != tt.want {
+				t.Fatalf("got %d transfers, want %d", total, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTransfer(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewTransferService(store)
+	transfer := sendAway(t, store, fx, fx.Location(nil), fx.Branch())
+
+	got, err := svc.GetTransfer(transfer.ID)
+	checkErr(t, err, nil)
+	if got.FromBranch == nil || got.ToBranch == nil || got.Book.ID != transfer.BookID {
+		t.Fatalf("expected relations to be loaded, got %+v", got)
+	}
+
+	_, err = svc.GetTransfer(uuid.New())
+	checkErr(t, err, services.ErrTransferNotFound)
+}
+
+func TestReceiveTransfer(t *testing.T) {
+	store, fx := setup(t)
+	svc := services.NewTransferService(store)
+	home := fx.Location(nil)
+	transfer := sendAway(t, store, fx, home, fx.Branch())
+
+	received, err := svc.ReceiveTransfer(transfer.ID)
+	checkErr(t, err, nil)
+	if received.Status != models.TransferReceived || received.ReceivedAt == nil {
+		t.Fatalf("status = %q, received_at = %v", received.Status, received.ReceivedAt)
+	}
+
+	book, err := services.NewBookService(store).GetBook(transfer.BookID)
+	checkErr(t, err, nil)
+	if !book.Available || book.InTransit || book.CurrentLocationID == nil || *book.CurrentLocationID != home.ID {
+		t.Fatalf("expected book back on its home shelf, got available=%v in_transit=%v current=%v",
+			book.Available, book.InTransit, book.CurrentLocationID)
+	}
+
+	// A transfer can only be received once
+	_, err = svc.ReceiveTransfer(transfer.ID)
+	checkErr(t, err, services.ErrTransferNotInTransit)
+
+	_, err = svc.ReceiveTransfer(uuid.New())
+	checkErr(t, err, services.ErrTransferNotFound)
+
+	// The received book circulates again
+	_, err = services.NewBorrowingService(store).BorrowBook(&models.BorrowBookRequest{
+		BookID:     book.ID,
+		BorrowerID: fx.Borrower().ID,
+		DueDate:    time.Now().Add(time.Hour),
+	})
+	checkErr(t, err, nil)
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"library-management-go/internal/repository"
+)
+
+// TrashService permanently removes soft-deleted records once they have
+// been in the trash longer than the retention period.
+type TrashService struct {
+	store     repository.Store
+	retention time.Duration
+}
+
+func NewTrashService(store repository.Store, retention time.Duration) *TrashService {
+	return &TrashService{store: store, retention: retention}
+}
+
+func (s *TrashService) WithContext(ctx context.Context) *TrashService {
+	return &TrashService{store: s.store.WithContext(ctx), retention: s.retention}
+}
+
+// PurgeExpired deletes records soft-deleted before the retention cutoff and
+// returns how many rows were removed per entity. Records still referenced
+// by other rows (books with loans or holds, borrowers with loans, fines or
+// blocks, authors with books) are kept.
+func (s *TrashService) PurgeExpired() (map[string]int64, error) {
+	cutoff := time.Now().Add(-s.retention)
+	purged := map[string]int64{}
+
+	err := s.store.Transaction(func(tx repository.Store) error {
+		count, err := tx.Borrowers().PurgeDeletedBefore(cutoff)
+		if err != nil {
+			return err
+		}
+		purged["borrowers"] = count
+
+		count, err = tx.Books().PurgeDeletedBefore(cutoff)
+		if err != nil {
+			return err
+		}
+		purged["books"] = count
+
+		// Runs after books so authors whose last book was just purged go too
+		count, err = tx.Authors().PurgeDeletedBefore(cutoff)
+		if err != nil {
+			return err
+		}
+		purged["authors"] = count
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,402 @@
+package sip2
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// dueDateLayout formats the due dates shown to patrons in the AH field.
+const dueDateLayout = "2006-01-02"
+
+// mediaType is reported for every item: books.
+const mediaType = "001"
+
+// Circulation statuses of an item information response.
+const (
+	statusOther     = "01"
+	statusAvailable = "03"
+	statusCharged   = "04"
+	statusOnHold    = "08"
+	statusInTransit = "10"
+)
+
+// Alert types of a checkin response: the item is wanted for a hold here,
+// or must be sent back to its home branch.
+const (
+	alertHold     = "01"
+	alertTransfer = "04"
+)
+
+// The remaining fixed field values: items carry no security marker the
+// server knows of, fees are of no particular type, and whether an item
+// is magnetic media is unknown.
+const (
+	securityNone    = "01"
+	feeTypeOther    = "01"
+	magneticUnknown = "U"
+)
+
+const (
+	notFoundMessage  = "Item not recognised"
+	notLoanedMessage = "Item is not checked out"
+)
+
+// branchID returns the branch the terminal stands in, or uuid.Nil.
+func (h *handler) branchID() uuid.UUID {
+	if h.terminal.BranchID == nil {
+		return uuid.Nil
+	}
+	return *h.terminal.BranchID
+}
+
+// lookupItem returns the book with the barcode in the AB field, or nil if
+// there is none.
+func (h *handler) lookupItem(msg *Message) (*models.Book, error) {
+	book, err := h.books().GetBookByBarcode(msg.Get("AB"))
+	if errors.Is(err, services.ErrBookNotFound) {
+		return nil, nil
+	}
+	return book, err
+}
+
+// activeLoan returns the loan the book is out on, or nil if it is not on
+// loan.
+func (h *handler) activeLoan(bookID uuid.UUID) (*models.Borrowing, error) {
+	loans, _, err := h.borrowings().FindBorrowings(models.BorrowingFilter{BookID: bookID, ActiveOnly: true}, 1, 1)
+	if err != nil || len(loans) == 0 {
+		return nil, err
+	}
+	return &loans[0], nil
+}
+
+// onLoanTo reports whether loan is borrower's.
+func onLoanTo(loan *models.Borrowing, borrower *models.Borrower) bool {
+	return loan != nil && loan.BorrowerID != nil && *loan.BorrowerID == borrower.ID
+}
+
+// circulationResponse builds the checkout and renew responses, which
+// share their fields.
+func circulationResponse(command string, msg *Message, ok, renewed bool) *Message {
+	return NewMessage(command, digit(ok), flag(renewed), magneticUnknown, flag(ok), Timestamp(time.Now())).
+		Add("AO", msg.Get("AO")).
+		Add("AA", msg.Get("AA")).
+		Add("AB", msg.Get("AB"))
+}
+
+// checkout lends the item to the patron. An item the patron already has
+// is renewed instead when the terminal's renewal policy allows it.
+func (h *handler) checkout(msg *Message) *Message {
+	renewalPolicy := msg.Fixed[0] == 'Y'
+	fail := func(message string) *Message {
+		return circulationResponse(CheckoutResponse, msg, false, false).Add("AJ", "").Add("AH", "").Add("AF", message)
+	}
+
+	borrower, err := h.lookupPatron(msg)
+	if err != nil {
+		return fail(screenMessage(err))
+	}
+	if borrower == nil {
+		return fail("Library card not recognised")
+	}
+	if pin, err := h.checkPIN(borrower, msg); err != nil {
+		return fail(screenMessage(err))
+	} else if pin == "N" {
+		return fail("Incorrect PIN")
+	}
+
+	book, err := h.lookupItem(msg)
+	if err != nil {
+		return fail(screenMessage(err))
+	}
+	if book == nil {
+		return fail(notFoundMessage)
+	}
+
+	loan, err := h.activeLoan(book.ID)
+	if err != nil {
+		return fail(screenMessage(err))
+	}
+
+	renewed := false
+	switch {
+	case onLoanTo(loan, borrower) && renewalPolicy:
+		loan, err = h.borrowings().RenewBorrowing(loan.ID, &models.RenewBorrowingRequest{})
+		renewed = true
+	case onLoanTo(loan, borrower):
+		err = services.ErrAlreadyBorrowing
+	default:
+		// Loans run for the library's loan period
+		loan, err = h.borrowings().BorrowBook(&models.BorrowBookRequest{
+			BookID:     book.ID,
+			BorrowerID: borrower.ID,
+			BranchID:   h.branchID(),
+		})
+	}
+	if err != nil {
+		return fail(screenMessage(err))
+	}
+
+	return circulationResponse(CheckoutResponse, msg, true, renewed).
+		Add("AJ", book.Title).
+		Add("AH", loan.DueDate.Format(dueDateLayout)).
+		Add("CK", mediaType)
+}
+
+// renew renews the patron's loan of the item.
+func (h *handler) renew(msg *Message) *Message {
+	fail := func(message string) *Message {
+		return circulationResponse(RenewResponse, msg, false, false).Add("AJ", "").Add("AH", "").Add("AF", message)
+	}
+
+	borrower, err := h.lookupPatron(msg)
+	if err != nil {
+		return fail(screenMessage(err))
+	}
+	if borrower == nil {
+		return fail("Library card not recognised")
+	}
+	if pin, err := h.checkPIN(borrower, msg); err != nil {
+		return fail(screenMessage(err))
+	} else if pin == "N" {
+		return fail("Incorrect PIN")
+	}
+
+	book, err := h.lookupItem(msg)
+	if err != nil {
+		return fail(screenMessage(err))
+	}
+	if book == nil {
+		return fail(notFoundMessage)
+	}
+
+	loan, err := h.activeLoan(book.ID)
+	if err != nil {
+		return fail(screenMessage(err))
+	}
+	if !onLoanTo(loan, borrower) {
+		return fail(notLoanedMessage)
+	}
+
+	loan, err = h.borrowings().RenewBorrowing(loan.ID, &models.RenewBorrowingRequest{})
+	if err != nil {
+		return fail(screenMessage(err))
+	}
+
+	return circulationResponse(RenewResponse, msg, true, true).
+		Add("AJ", book.Title).
+		Add("AH", loan.DueDate.Format(dueDateLayout)).
+		Add("CK", mediaType)
+}
+
+// checkin returns the item at the terminal's branch, alerting the
+// terminal when the item is wanted for a hold or must travel back to its
+// home branch.
+func (h *handler) checkin(msg *Message) *Message {
+	respond := func(ok bool, alert string) *Message {
+		resp := NewMessage(CheckinResponse, digit(ok), flag(ok), magneticUnknown, flag(alert != ""), Timestamp(time.Now())).
+			Add("AO", msg.Get("AO")).
+			Add("AB", msg.Get("AB"))
+		if alert != "" {
+			resp.Add("CV", alert)
+		}
+		return resp
+	}
+
+	book, err := h.lookupItem(msg)
+	if err != nil {
+		return respond(false, "").Add("AF", screenMessage(err))
+	}
+	if book == nil {
+		return respond(false, "").Add("AF", notFoundMessage)
+	}
+
+	loan, err := h.activeLoan(book.ID)
+	if err != nil {
+		return respond(false, "").Add("AF", screenMessage(err))
+	}
+	if loan == nil {
+		return respond(false, "").Add("AJ", book.Title).Add("AF", notLoanedMessage)
+	}
+
+	if _, err := h.borrowings().ReturnBook(&models.ReturnBookRequest{BorrowingID: loan.ID, BranchID: h.branchID()}); err != nil {
+		return respond(false, "").Add("AJ", book.Title).Add("AF", screenMessage(err))
+	}
+
+	// Check if the item has to go back to its home branch or to the hold
+	// shelf
+	if book, err = h.books().GetBook(book.ID); err != nil {
+		return respond(true, "").Add("AF", screenMessage(err))
+	}
+	holds, err := h.holds().GetHoldQueue(book.ID)
+	if err != nil {
+		return respond(true, "").Add("AF", screenMessage(err))
+	}
+
+	var resp *Message
+	switch {
+	case book.InTransit:
+		resp = respond(true, alertTransfer)
+	case len(holds) > 0:
+		resp = respond(true, alertHold)
+	default:
+		resp = respond(true, "")
+	}
+	if book.HomeLocation != nil {
+		resp.Add("AQ", locationName(book.HomeLocation))
+	}
+	resp.Add("AJ", book.Title).Add("CK", mediaType)
+	if loan.Borrower != nil {
+		resp.Add("AA", loan.Borrower.CardNumber)
+	}
+	switch {
+	case book.InTransit && book.HomeLocation != nil:
+		resp.Add("AF", "Send to "+locationName(book.HomeLocation))
+	case len(holds) > 0:
+		resp.Add("AF", "Item is on hold, place on the hold shelf")
+	}
+	return resp
+}
+
+// locationName names a shelf location with its branch.
+func locationName(location *models.Location) string {
+	if location.Branch == nil {
+		return location.Name
+	}
+	return location.Branch.Name + ", " + location.Name
+}
+
+// itemInformation reports where an item is and whether it can be borrowed.
+func (h *handler) itemInformation(msg *Message) *Message {
+	respond := func(status string) *Message {
+		return NewMessage(ItemInfoResponse, status, securityNone, feeTypeOther, Timestamp(time.Now())).
+			Add("AB", msg.Get("AB"))
+	}
+
+	book, err := h.lookupItem(msg)
+	if err != nil {
+		return respond(statusOther).Add("AJ", "").Add("AF", screenMessage(err))
+	}
+	if book == nil {
+		return respond(statusOther).Add("AJ", "").Add("AF", notFoundMessage)
+	}
+
+	loan, err := h.activeLoan(book.ID)
+	if err != nil {
+		return respond(statusOther).Add("AJ", book.Title).Add("AF", screenMessage(err))
+	}
+	holds, err := h.holds().GetHoldQueue(book.ID)
+	if err != nil {
+		return respond(statusOther).Add("AJ", book.Title).Add("AF", screenMessage(err))
+	}
+
+	var resp *Message
+	switch {
+	case loan != nil:
+		resp = respond(statusCharged).Add("AJ", book.Title).Add("AH", loan.DueDate.Format(dueDateLayout))
+	case book.InTransit:
+		resp = respond(statusInTransit).Add("AJ", book.Title)
+	case book.Available && len(holds) > 0:
+		resp = respond(statusOnHold).Add("AJ", book.Title)
+	case book.Available:
+		resp = respond(statusAvailable).Add("AJ", book.Title)
+	default:
+		resp = respond(statusOther).Add("AJ", book.Title)
+	}
+
+	resp.Add("CF", strconv.Itoa(len(holds)))
+	if book.CurrentLocation != nil {
+		resp.Add("AP", locationName(book.CurrentLocation))
+	}
+	if book.HomeLocation != nil {
+		resp.Add("AQ", locationName(book.HomeLocation))
+	}
+	if book.CallNumber != "" {
+		resp.Add("CS", book.CallNumber)
+	}
+	return resp.Add("CK", mediaType)
+}
+
+// feePaid pays the fine named by the CG field, or with none named all the
+// patron's outstanding fines. The amount must settle the fines exactly
+// since fines cannot be part paid.
+func (h *handler) feePaid(msg *Message) *Message {
+	respond := func(accepted bool) *Message {
+		return NewMessage(FeePaidResponse, flag(accepted), Timestamp(time.Now())).
+			Add("AO", msg.Get("AO")).
+			Add("AA", msg.Get("AA")).
+			Add("BK", msg.Get("BK"))
+	}
+
+	borrower, err := h.lookupPatron(msg)
+	if err != nil {
+		return respond(false).Add("AF", screenMessage(err))
+	}
+	if borrower == nil {
+		return respond(false).Add("AF", "Library card not recognised")
+	}
+	if currency := msg.Fixed[22:25]; currency != h.server.Currency {
+		return respond(false).Add("AF", "Payments are only accepted in "+h.server.Currency)
+	}
+	amount, err := parseAmount(msg.Get("BV"))
+	if err != nil || amount <= 0 {
+		return respond(false).Add("AF", "Invalid amount")
+	}
+
+	// Find the fines being paid
+	var fines []models.Fine
+	if feeID := msg.Get("CG"); feeID != "" {
+		id, err := uuid.Parse(feeID)
+		if err != nil {
+			return respond(false).Add("AF", "Fee not recognised")
+		}
+		fine, err := h.fines().GetFine(id)
+		if errors.Is(err, services.ErrFineNotFound) || (err == nil && fine.BorrowerID != borrower.ID) {
+			return respond(false).Add("AF", "Fee not recognised")
+		}
+		if err != nil {
+			return respond(false).Add("AF", screenMessage(err))
+		}
+		if fine.Status != models.FineOutstanding {
+			return respond(false).Add("AF", screenMessage(services.ErrFineNotOutstanding))
+		}
+		fines = append(fines, *fine)
+	} else {
+		if fines, _, err = h.fines().GetFinesByBorrower(borrower.ID, models.FineOutstanding); err != nil {
+			return respond(false).Add("AF", screenMessage(err))
+		}
+	}
+
+	var total int64
+	for _, fine := range fines {
+		total += fine.Amount
+	}
+	if total == 0 {
+		return respond(false).Add("AF", "Nothing to pay")
+	}
+	if amount != total {
+		return respond(false).Add("AF", "Amount must be "+formatAmount(total)+" "+h.server.Currency)
+	}
+
+	// The fines are paid together or not at all
+	err = h.store.Transaction(func(tx repository.Store) error {
+		fineService := services.NewFineService(tx).WithContext(h.ctx)
+		for _, fine := range fines {
+			if _, err := fineService.PayFine(fine.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return respond(false).Add("AF", screenMessage(err))
+	}
+
+	return respond(true).Add("AF", "Paid "+formatAmount(total)+" "+h.server.Currency)
+}
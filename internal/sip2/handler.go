@@ -0,0 +1,129 @@
+package sip2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/services"
+)
+
+// Values of the fixed fields of the ACS status message: the server is
+// online, allows checkin, checkout and renewal, but neither takes status
+// updates nor accepts transactions made offline.
+const (
+	protocolVersion = "2.00"
+	timeoutPeriod   = "030"
+	retriesAllowed  = "003"
+	// supportedMessages answers the BX field with which of the 16 requests
+	// the server handles: patron status, checkout, checkin, SC/ACS status,
+	// resend, login, patron information, end patron session, fee paid,
+	// item information and renew
+	supportedMessages = "YYYNYYYYYYYNNNYN"
+)
+
+// handler answers the messages of a logged-in terminal.
+type handler struct {
+	server   *Server
+	terminal *models.SIPTerminal
+	ctx      context.Context
+	// store is scoped to the terminal's tenant
+	store repository.Store
+}
+
+func (h *handler) books() *services.BookService {
+	return services.NewBookService(h.server.store).WithContext(h.ctx)
+}
+
+func (h *handler) borrowers() *services.BorrowerService {
+	return services.NewBorrowerService(h.server.store).WithContext(h.ctx)
+}
+
+func (h *handler) borrowings() *services.BorrowingService {
+	return services.NewBorrowingService(h.server.store).WithContext(h.ctx)
+}
+
+func (h *handler) holds() *services.HoldService {
+	return services.NewHoldService(h.server.store).WithContext(h.ctx)
+}
+
+func (h *handler) fines() *services.FineService {
+	return services.NewFineService(h.server.store).WithContext(h.ctx)
+}
+
+// status answers an SC status message with what the server supports.
+func (h *handler) status(msg *Message) *Message {
+	var institution, library string
+	tenant, err := h.store.Tenants().Current()
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Printf("SIP2 status failed: %v", err)
+	}
+	if tenant != nil {
+		institution, library = tenant.Slug, tenant.Name
+	}
+
+	return NewMessage(ACSStatus,
+		"Y", "Y", "Y", "Y", "N", "N", timeoutPeriod, retriesAllowed, Timestamp(time.Now()), protocolVersion).
+		Add("AO", institution).
+		Add("AM", library).
+		Add("BX", supportedMessages).
+		Add("AN", h.terminal.Name)
+}
+
+// screenMessage returns what the terminal shows the patron when err stops
+// their request. Unexpected errors are logged rather than shown.
+func screenMessage(err error) string {
+	var svcErr *services.Error
+	if errors.As(err, &svcErr) {
+		return capitalize(svcErr.Message)
+	}
+	log.Printf("SIP2 request failed: %v", err)
+	return "Your request could not be completed, please ask at the desk"
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// formatAmount formats an amount in the currency's minor unit as SIP2's
+// decimal fee amounts.
+func formatAmount(minor int64) string {
+	sign := ""
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/100, minor%100)
+}
+
+// parseAmount parses a decimal fee amount such as "2.50" into the
+// currency's minor unit.
+func parseAmount(s string) (int64, error) {
+	whole, frac, _ := strings.Cut(strings.TrimSpace(s), ".")
+	if whole+frac == "" || len(frac) > 2 || !isDigits(whole) || !isDigits(frac) {
+		return 0, ErrMalformed
+	}
+	units, err := strconv.ParseInt("0"+whole, 10, 64)
+	if err != nil || units > (1<<62)/100 {
+		return 0, ErrMalformed
+	}
+	cents, _ := strconv.ParseInt(frac+strings.Repeat("0", 2-len(frac)), 10, 64)
+	return units*100 + cents, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
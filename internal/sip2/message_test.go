@@ -0,0 +1,111 @@
+package sip2
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	m, err := Parse("9300CNkiosk|COsecret|CPLobby|\r")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if m.Command != Login || m.Fixed != "00" || m.Sequence != -1 {
+		t.Fatalf("unexpected message %+v", m)
+	}
+	if m.Get("CN") != "kiosk" || m.Get("CO") != "secret" || m.Get("CP") != "Lobby" || m.Get("XX") != "" {
+		t.Fatalf("unexpected fields %+v", m.Fields)
+	}
+
+	// The last field may go without its separator, and fields may repeat
+	m, err = Parse("6300020240102    120000  Y       AOlib|AA21234000000019|BP1|BQ5")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if m.Fixed != "00020240102    120000  Y       " || m.Get("BQ") != "5" {
+		t.Fatalf("unexpected message %+v", m)
+	}
+
+	for _, line := range []string{"", "9", "11YN20240102"} {
+		if _, err := Parse(line); !errors.Is(err, ErrMalformed) {
+			t.Errorf("Parse(%q) error = %v, want ErrMalformed", line, err)
+		}
+	}
+}
+
+func TestEncodeWithErrorDetection(t *testing.T) {
+	m := NewMessage(LoginResponse, "1")
+	m.Sequence = 3
+	line := string(m.Encode())
+	if line[:len(line)-5] != "941AY3AZ" || line[len(line)-1] != '\r' {
+		t.Fatalf("Encode = %q", line)
+	}
+
+	// The checksum brings the sum of every byte up to it to zero
+	var sum uint16
+	for i := 0; i < len(line)-5; i++ {
+		sum += uint16(line[i])
+	}
+	var value uint16
+	for _, c := range line[len(line)-5 : len(line)-1] {
+		value = value<<4 | uint16(hexDigit(c))
+	}
+	if sum+value != 0 {
+		t.Fatalf("checksum %s does not cancel the sum %#x", line[len(line)-5:len(line)-1], sum)
+	}
+
+	got, err := Parse(line)
+	if err != nil || got.Sequence != 3 || got.Fixed != "1" || len(got.Fields) != 0 {
+		t.Fatalf("round trip gave %+v, %v", got, err)
+	}
+
+	corrupted := "951" + line[3:]
+	if _, err := Parse(corrupted); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("Parse(%q) error = %v, want ErrChecksum", corrupted, err)
+	}
+}
+
+func hexDigit(c rune) int {
+	if c >= 'A' {
+		return int(c-'A') + 10
+	}
+	return int(c - '0')
+}
+
+func TestEncodeSanitizesValues(t *testing.T) {
+	m := NewMessage(EndSessionResponse, "Y").Add("AF", "a|b\r\nc")
+	if got := string(m.Encode()); got != "36YAFa b  c|\r" {
+		t.Fatalf("Encode = %q", got)
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+		ok   bool
+	}{
+		{"2.50", 250, true},
+		{"2.5", 250, true},
+		{"2", 200, true},
+		{".75", 75, true},
+		{" 10.00 ", 1000, true},
+		{"", 0, false},
+		{".", 0, false},
+		{"1.234", 0, false},
+		{"-1.00", 0, false},
+		{"+1.00", 0, false},
+		{"1,00", 0, false},
+		{"99999999999999999999", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseAmount(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseAmount(%q) = %d, %v", tt.in, got, err)
+		}
+	}
+
+	if got := formatAmount(1205); got != "12.05" {
+		t.Fatalf("formatAmount(1205) = %q", got)
+	}
+}
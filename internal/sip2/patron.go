@@ -0,0 +1,272 @@
+package sip2
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/services"
+)
+
+// maxListedItems caps the items listed in a patron information response.
+const maxListedItems = 100
+
+// unknownPatronStatus denies every privilege to patrons who cannot be
+// found.
+const unknownPatronStatus = "YYYY          "
+
+// account is what the patron status and information responses report about
+// the patron whose card is in the AA field.
+type account struct {
+	borrower *models.Borrower
+	standing *models.BorrowerStanding
+	// pin is "Y" or "N" as the PIN in the AD field is right or wrong, or
+	// "" when the terminal sent none
+	pin string
+}
+
+// lookupPatron returns the borrower holding the card in the AA field, or
+// nil if there is none.
+func (h *handler) lookupPatron(msg *Message) (*models.Borrower, error) {
+	borrower, err := h.borrowers().GetBorrowerByCard(msg.Get("AA"))
+	if errors.Is(err, services.ErrBorrowerNotFound) || errors.Is(err, services.ErrInvalidCardNumber) {
+		return nil, nil
+	}
+	return borrower, err
+}
+
+// checkPIN returns "Y" or "N" as the PIN in the AD field is the
+// borrower's or not, or "" if the terminal sent none.
+func (h *handler) checkPIN(borrower *models.Borrower, msg *Message) (string, error) {
+	if !msg.Has("AD") {
+		return "", nil
+	}
+	_, err := h.borrowers().Authenticate(&models.LoginRequest{CardNumber: borrower.CardNumber, PIN: msg.Get("AD")})
+	if errors.Is(err, services.ErrInvalidCredentials) {
+		return "N", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return "Y", nil
+}
+
+// account looks up the patron and their standing, returning nil if there
+// is no such patron.
+func (h *handler) account(msg *Message) (*account, error) {
+	borrower, err := h.lookupPatron(msg)
+	if err != nil || borrower == nil {
+		return nil, err
+	}
+
+	acct := &account{borrower: borrower}
+	if acct.pin, err = h.checkPIN(borrower, msg); err != nil {
+		return nil, err
+	}
+	if acct.standing, err = h.borrowers().GetStanding(borrower.ID); err != nil {
+		return nil, err
+	}
+	return acct, nil
+}
+
+// statusFlags returns the 14 flags SIP2 reports a patron's standing with;
+// a 'Y' denies a privilege or flags a problem. Holds are denied along with
+// renewals, since both depend on membership and blocks alone.
+func statusFlags(acct *account) string {
+	if acct == nil {
+		return unknownPatronStatus
+	}
+
+	st := acct.standing
+	flags := []byte(strings.Repeat(" ", 14))
+	set := func(i int, on bool) {
+		if on {
+			flags[i] = 'Y'
+		}
+	}
+	set(0, !st.CanBorrow) // charge privileges denied
+	set(1, !st.CanRenew)  // renewal privileges denied
+	set(2, !st.CanBorrow) // recall privileges denied
+	set(3, !st.CanRenew)  // hold privileges denied
+	set(4, st.CardLost)
+	set(5, st.ActiveLoans >= int64(st.MaxActiveLoans)) // too many items charged
+	// Too many items overdue, when that is what stops the patron borrowing
+	set(6, st.OverdueLoans > 0 && !st.CanBorrow && st.CanRenew && st.ActiveLoans < int64(st.MaxActiveLoans))
+	return string(flags)
+}
+
+// addPatronFields adds the fields patron status and information responses
+// share.
+func (h *handler) addPatronFields(resp, msg *Message, acct *account, err error) {
+	resp.Add("AO", msg.Get("AO")).Add("AA", msg.Get("AA"))
+	switch {
+	case err != nil:
+		resp.Add("AE", "").Add("BL", "N").Add("AF", screenMessage(err))
+		return
+	case acct == nil:
+		resp.Add("AE", "").Add("BL", "N").Add("AF", "Library card not recognised")
+		return
+	}
+
+	resp.Add("AE", acct.borrower.Name).Add("BL", "Y")
+	if acct.pin != "" {
+		resp.Add("CQ", acct.pin)
+	}
+	resp.Add("BH", h.server.Currency).Add("BV", formatAmount(acct.standing.OutstandingFines))
+}
+
+// patronStatus answers a patron status request.
+func (h *handler) patronStatus(msg *Message) *Message {
+	acct, err := h.account(msg)
+	resp := NewMessage(PatronStatusResponse, statusFlags(acct), msg.Fixed[:3], Timestamp(time.Now()))
+	h.addPatronFields(resp, msg, acct, err)
+	if acct != nil && !acct.standing.CanBorrow {
+		resp.Add("AF", capitalize(acct.standing.Reason))
+	}
+	return resp
+}
+
+// patronItems are the items a patron information response counts and may
+// list, in the order of the request's summary field.
+type patronItems struct {
+	holds       []string // available holds
+	overdue     []string
+	charged     []string
+	fines       []string
+	unavailable []string // holds that are not yet available
+}
+
+// items gathers the patron's loans, holds and fines.
+func (h *handler) items(borrower *models.Borrower) (*patronItems, error) {
+	items := &patronItems{}
+
+	loans, _, err := h.borrowings().FindBorrowings(models.BorrowingFilter{BorrowerID: borrower.ID, ActiveOnly: true}, 1, maxListedItems)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, loan := range loans {
+		items.charged = append(items.charged, loan.Book.Barcode)
+		if now.After(loan.DueDate) {
+			items.overdue = append(items.overdue, loan.Book.Barcode)
+		}
+	}
+
+	// A hold is available once its book is back on the shelf and the
+	// patron is at the head of its queue
+	holds, err := h.holds().GetHoldsByBorrower(borrower.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, hold := range holds {
+		if hold.Book == nil {
+			continue
+		}
+		available := false
+		if hold.Book.Available && !hold.Book.InTransit {
+			queue, err := h.holds().GetHoldQueue(hold.BookID)
+			if err != nil {
+				return nil, err
+			}
+			available = len(queue) > 0 && queue[0].ID == hold.ID
+		}
+		if available {
+			items.holds = append(items.holds, hold.Book.Barcode)
+		} else {
+			items.unavailable = append(items.unavailable, hold.Book.Barcode)
+		}
+	}
+
+	fines, _, err := h.fines().GetFinesByBorrower(borrower.ID, models.FineOutstanding)
+	if err != nil {
+		return nil, err
+	}
+	for _, fine := range fines {
+		// The fine's ID is what a fee paid message names it by
+		items.fines = append(items.fines, fmt.Sprintf("%s %s %s", fine.ID, formatAmount(fine.Amount), fine.Reason))
+	}
+
+	return items, nil
+}
+
+// listRange returns the items from the 1-based start to end positions of
+// the BP and BQ fields, all of them by default.
+func listRange(items []string, msg *Message) []string {
+	start, end := 1, len(items)
+	if n, err := strconv.Atoi(msg.Get("BP")); err == nil && n > 0 {
+		start = n
+	}
+	if n, err := strconv.Atoi(msg.Get("BQ")); err == nil && n > 0 && n < end {
+		end = n
+	}
+	if start > end {
+		return nil
+	}
+	return items[start-1 : end]
+}
+
+// patronInformation answers a patron information request, listing the
+// kind of item the first 'Y' of its summary field asks for.
+func (h *handler) patronInformation(msg *Message) *Message {
+	acct, err := h.account(msg)
+	var items *patronItems
+	if err == nil && acct != nil {
+		items, err = h.items(acct.borrower)
+	}
+	if items == nil {
+		items = &patronItems{}
+	}
+
+	resp := NewMessage(PatronInfoResponse, statusFlags(acct), msg.Fixed[:3], Timestamp(time.Now()),
+		count(len(items.holds)), count(len(items.overdue)), count(len(items.charged)),
+		count(len(items.fines)), count(0), count(len(items.unavailable)))
+	h.addPatronFields(resp, msg, acct, err)
+	if err != nil || acct == nil {
+		return resp
+	}
+
+	resp.Add("CB", strconv.Itoa(acct.standing.MaxActiveLoans))
+	lists := []struct {
+		id    string
+		items []string
+	}{
+		{"AS", items.holds},
+		{"AT", items.overdue},
+		{"AU", items.charged},
+		{"AV", items.fines},
+		{"BU", nil},
+		{"CD", items.unavailable},
+	}
+	summary := strings.ToUpper(msg.Fixed[21:31])
+	if i := strings.IndexByte(summary, 'Y'); i >= 0 && i < len(lists) {
+		for _, item := range listRange(lists[i].items, msg) {
+			resp.Add(lists[i].id, item)
+		}
+	}
+
+	if acct.borrower.Email != "" {
+		resp.Add("BE", acct.borrower.Email)
+	}
+	if acct.borrower.Phone != "" {
+		resp.Add("BF", acct.borrower.Phone)
+	}
+	if acct.borrower.Address != "" {
+		resp.Add("BD", acct.borrower.Address)
+	}
+	if !acct.standing.CanBorrow {
+		resp.Add("AF", capitalize(acct.standing.Reason))
+	}
+	return resp
+}
+
+// endPatronSession answers the message a terminal sends when a patron
+// walks away. The server keeps no per-patron state, so there is nothing
+// to clear.
+func (h *handler) endPatronSession(msg *Message) *Message {
+	return NewMessage(EndSessionResponse, "Y", Timestamp(time.Now())).
+		Add("AO", msg.Get("AO")).
+		Add("AA", msg.Get("AA"))
+}
@@ -0,0 +1,262 @@
+package sip2
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/reqctx"
+	"library-management-go/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// ErrServerClosed is returned by Serve once Close has been called.
+var ErrServerClosed = errors.New("sip2: server closed")
+
+// maxMessageSize bounds the length of a single message.
+const maxMessageSize = 64 * 1024
+
+// DefaultIdleTimeout is how long a connection may stay silent before the
+// server closes it. Terminals send an SC status message every few minutes
+// to keep their connection open.
+const DefaultIdleTimeout = 10 * time.Minute
+
+// Server answers SIP2 requests from the terminals registered with
+// services.TerminalService, mapping them onto the borrower, borrowing,
+// hold and fine services. Each connection must first log in with its
+// terminal's login and password; everything it does afterwards is scoped
+// to the terminal's tenant, checked out and in at its branch, and
+// recorded in the audit log as the terminal.
+type Server struct {
+	store repository.Store
+	// Currency is the three-letter code fees are reported and paid in.
+	Currency string
+	// IdleTimeout closes connections that send nothing for that long.
+	IdleTimeout time.Duration
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// NewServer returns a server backed by store, reporting fees in currency.
+func NewServer(store repository.Store, currency string) *Server {
+	return &Server{
+		store:       store,
+		Currency:    currency,
+		IdleTimeout: DefaultIdleTimeout,
+		listeners:   map[net.Listener]struct{}{},
+		conns:       map[net.Conn]struct{}{},
+	}
+}
+
+// Serve accepts connections on l, serving each on its own goroutine, until
+// l fails or the server is closed.
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrServerClosed
+	}
+	s.listeners[l] = struct{}{}
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			delete(s.listeners, l)
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return ErrServerClosed
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(conn)
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// Close stops the server's listeners, closes its connections and waits
+// for their handlers to return.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for l := range s.listeners {
+		l.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+// session is the state of one terminal's connection.
+type session struct {
+	server *Server
+	conn   net.Conn
+	// terminal is nil until the connection has logged in
+	terminal *models.SIPTerminal
+	// ctx carries the terminal's tenant and actor
+	ctx context.Context
+	// last is the last response sent, which the terminal may ask to have
+	// sent again
+	last []byte
+}
+
+// scanLines splits SIP2 messages, which terminals end with a carriage
+// return, a line feed or both.
+func scanLines(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	sess := &session{server: s, conn: conn, ctx: context.Background()}
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 4096), maxMessageSize)
+	scanner.Split(scanLines)
+
+	for {
+		if s.IdleTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(s.IdleTimeout))
+		}
+		if !scanner.Scan() {
+			return
+		}
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		resp, ok := sess.handle(line)
+		if !ok {
+			return
+		}
+		if resp == nil {
+			continue
+		}
+		if _, err := conn.Write(resp); err != nil {
+			return
+		}
+	}
+}
+
+// handle answers one message, returning the encoded response, or nil if
+// there is none, and false if the connection should be closed.
+func (sess *session) handle(line string) ([]byte, bool) {
+	msg, err := Parse(line)
+	if errors.Is(err, ErrChecksum) {
+		// Ask the terminal to send the message again
+		return NewMessage(RequestSCResend).Encode(), true
+	}
+	if err != nil {
+		return nil, true
+	}
+
+	switch {
+	case msg.Command == RequestACSResend:
+		return sess.last, true
+	case msg.Command == Login:
+		resp := sess.login(msg)
+		return sess.send(msg, resp), true
+	case sess.terminal == nil:
+		// Terminals must log in before anything else
+		return nil, false
+	}
+
+	// Each message is a request of its own in the audit log
+	ctx := reqctx.WithRequestID(sess.ctx, uuid.NewString())
+	h := &handler{server: sess.server, terminal: sess.terminal, ctx: ctx, store: sess.server.store.WithContext(ctx)}
+
+	var resp *Message
+	switch msg.Command {
+	case SCStatus:
+		resp = h.status(msg)
+	case PatronStatusRequest:
+		resp = h.patronStatus(msg)
+	case PatronInformation:
+		resp = h.patronInformation(msg)
+	case EndPatronSession:
+		resp = h.endPatronSession(msg)
+	case Checkout:
+		resp = h.checkout(msg)
+	case Checkin:
+		resp = h.checkin(msg)
+	case Renew:
+		resp = h.renew(msg)
+	case ItemInformation:
+		resp = h.itemInformation(msg)
+	case FeePaid:
+		resp = h.feePaid(msg)
+	default:
+		// Messages the server does not support go unanswered
+		return nil, true
+	}
+	return sess.send(msg, resp), true
+}
+
+// send encodes resp, with error detection if req used it, and remembers it
+// in case the terminal asks for it again.
+func (sess *session) send(req, resp *Message) []byte {
+	resp.Sequence = req.Sequence
+	sess.last = resp.Encode()
+	return sess.last
+}
+
+// login checks the terminal's credentials and scopes the session to it.
+func (sess *session) login(msg *Message) *Message {
+	ctx := reqctx.WithRequestID(context.Background(), uuid.NewString())
+	terminals := services.NewTerminalService(sess.server.store).WithContext(ctx)
+
+	terminal, err := terminals.Authenticate(msg.Get("CN"), msg.Get("CO"))
+	if err != nil {
+		if services.KindOf(err) != services.KindUnauthenticated {
+			log.Printf("SIP2 login failed: %v", err)
+		}
+		sess.terminal = nil
+		return NewMessage(LoginResponse, "0")
+	}
+
+	sess.terminal = terminal
+	sess.ctx = reqctx.WithActor(reqctx.WithTenantID(context.Background(), terminal.TenantID), "sip:"+terminal.Login)
+	return NewMessage(LoginResponse, "1")
+}
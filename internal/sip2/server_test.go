@@ -0,0 +1,592 @@
+package sip2_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/reqctx"
+	"library-management-go/internal/services"
+	"library-management-go/internal/sip2"
+	"library-management-go/internal/testutil"
+)
+
+// now is a fixed-field timestamp for requests; the server ignores it.
+var now = sip2.Timestamp(time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC))
+
+// library is a tenant with a branch, a terminal standing in it, and a
+// server listening for that terminal.
+type library struct {
+	store    repository.Store
+	fx       *testutil.Fixtures
+	branch   *models.Branch
+	terminal *models.SIPTerminal
+	addr     string
+}
+
+// newLibrary starts a server over a fresh database with a tenant called
+// slug, whose terminal logs in as slug-kiosk with password "password".
+func newLibrary(t *testing.T) *library {
+	t.Helper()
+
+	root := testutil.NewStore(t)
+	lib := openLibrary(t, root, "north")
+	server := sip2.NewServer(root, "USD")
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	lib.addr = listener.Addr().String()
+	return lib
+}
+
+// openLibrary creates the tenant slug in root with a branch and a terminal.
+func openLibrary(t *testing.T, root repository.Store, slug string) *library {
+	t.Helper()
+
+	tenant := &models.Tenant{Slug: slug, Name: "Library " + slug, Active: true, Settings: models.DefaultTenantSettings}
+	if err := root.Tenants().Create(tenant); err != nil {
+		t.Fatalf("create tenant: %v", err)
+	}
+	store := root.WithContext(reqctx.WithTenantID(context.Background(), tenant.ID))
+	fx := testutil.NewFixtures(t, store)
+	branch := fx.Branch()
+
+	terminal, err := services.NewTerminalService(store).CreateTerminal(&models.CreateSIPTerminalRequest{
+		Name: "Lobby kiosk", Login: slug + "-kiosk", Password: "password", BranchID: branch.ID,
+	})
+	if err != nil {
+		t.Fatalf("create terminal: %v", err)
+	}
+	return &library{store: store, fx: fx, branch: branch, terminal: terminal}
+}
+
+// client is a terminal's connection to the server. It sends every message
+// with error detection, checking the responses' checksums in turn.
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+	seq  int
+}
+
+func (lib *library) dial(t *testing.T) *client {
+	t.Helper()
+	conn, err := net.Dial("tcp", lib.addr)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &client{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+// login dials the server and logs in as the library's terminal.
+func (lib *library) login(t *testing.T) *client {
+	t.Helper()
+	c := lib.dial(t)
+	resp := c.send(sip2.NewMessage(sip2.Login, "00").Add("CN", lib.terminal.Login).Add("CO", "password").Add("CP", "Lobby"))
+	if resp.Command != sip2.LoginResponse || resp.Fixed != "1" {
+		t.Fatalf("login failed: %+v", resp)
+	}
+	return c
+}
+
+// send sends m and returns the response.
+func (c *client) send(m *sip2.Message) *sip2.Message {
+	c.t.Helper()
+	m.Sequence = c.seq
+	c.seq = (c.seq + 1) % 10
+	c.write(string(m.Encode()))
+
+	resp, err := c.read()
+	if err != nil {
+		c.t.Fatalf("read response to %s: %v", m.Command, err)
+	}
+	if resp.Sequence != m.Sequence {
+		c.t.Fatalf("response sequence %d, want %d", resp.Sequence, m.Sequence)
+	}
+	return resp
+}
+
+func (c *client) write(line string) {
+	c.t.Helper()
+	if _, err := c.conn.Write([]byte(line)); err != nil {
+		c.t.Fatalf("write: %v", err)
+	}
+}
+
+func (c *client) read() (*sip2.Message, error) {
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := c.r.ReadString('\r')
+	if err != nil {
+		return nil, err
+	}
+	return sip2.Parse(line)
+}
+
+// fixed returns the fixed field of length n at offset of resp, failing the
+// test if resp is too short.
+func fixed(t *testing.T, resp *sip2.Message, offset, n int) string {
+	t.Helper()
+	if len(resp.Fixed) < offset+n {
+		t.Fatalf("fixed fields %q too short", resp.Fixed)
+	}
+	return resp.Fixed[offset : offset+n]
+}
+
+func checkout(card, barcode string) *sip2.Message {
+	return sip2.NewMessage(sip2.Checkout, "N", "N", now, now).Add("AO", "north").Add("AA", card).Add("AB", barcode)
+}
+
+func checkin(barcode string) *sip2.Message {
+	return sip2.NewMessage(sip2.Checkin, "N", now, now).Add("AP", "Lobby").Add("AO", "north").Add("AB", barcode)
+}
+
+func TestLogin(t *testing.T) {
+	lib := newLibrary(t)
+
+	c := lib.dial(t)
+	resp := c.send(sip2.NewMessage(sip2.Login, "00").Add("CN", "north-kiosk").Add("CO", "wrong"))
+	if resp.Fixed != "0" {
+		t.Fatalf("expected the login to be refused, got %+v", resp)
+	}
+	// Nothing but another login is answered until the terminal logs in
+	c.write(string(sip2.NewMessage(sip2.SCStatus, "0", "080", "2.00").Encode()))
+	if _, err := c.read(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected the connection to be closed, got %v", err)
+	}
+
+	c = lib.login(t)
+	resp = c.send(sip2.NewMessage(sip2.SCStatus, "0", "080", "2.00"))
+	if resp.Command != sip2.ACSStatus || !strings.HasPrefix(resp.Fixed, "YYYYNN030003") || !strings.HasSuffix(resp.Fixed, "2.00") {
+		t.Fatalf("unexpected ACS status %+v", resp)
+	}
+	if resp.Get("AO") != "north" || resp.Get("AM") != "Library north" || resp.Get("BX") != "YYYNYYYYYYYNNNYN" {
+		t.Fatalf("unexpected ACS status fields %+v", resp.Fields)
+	}
+
+	got, err := services.NewTerminalService(lib.store).GetTerminal(lib.terminal.ID)
+	if err != nil || got.LastLoginAt == nil {
+		t.Fatalf("expected the login to be recorded, got %+v, %v", got, err)
+	}
+}
+
+func TestResend(t *testing.T) {
+	lib := newLibrary(t)
+	c := lib.login(t)
+
+	// A corrupted message is met with a request to send it again
+	m := sip2.NewMessage(sip2.SCStatus, "0", "080", "2.00")
+	m.Sequence = 0
+	c.write(strings.Replace(string(m.Encode()), "080", "081", 1))
+	resp, err := c.read()
+	if err != nil || resp.Command != sip2.RequestSCResend {
+		t.Fatalf("expected a resend request, got %+v, %v", resp, err)
+	}
+
+	status := c.send(sip2.NewMessage(sip2.SCStatus, "0", "080", "2.00"))
+	c.write("97\r")
+	again, err := c.read()
+	if err != nil || again.Command != sip2.ACSStatus || again.Fixed != status.Fixed {
+		t.Fatalf("expected the ACS status again, got %+v, %v", again, err)
+	}
+}
+
+func TestPatronStatus(t *testing.T) {
+	lib := newLibrary(t)
+	c := lib.login(t)
+	borrower := lib.fx.Borrower()
+	if err := services.NewBorrowerService(lib.store).SetPIN(borrower.ID, &models.SetPINRequest{PIN: "1234"}); err != nil {
+		t.Fatalf("set PIN: %v", err)
+	}
+	lib.fx.Fine(borrower, 250)
+
+	request := func(card, pin string) *sip2.Message {
+		m := sip2.NewMessage(sip2.PatronStatusRequest, "000", now).Add("AO", "north").Add("AA", card).Add("AC", "")
+		if pin != "" {
+			m.Add("AD", pin)
+		}
+		return c.send(m)
+	}
+
+	resp := request(borrower.CardNumber, "1234")
+	if resp.Command != sip2.PatronStatusResponse || fixed(t, resp, 0, 14) != strings.Repeat(" ", 14) {
+		t.Fatalf("unexpected patron status %+v", resp)
+	}
+	if resp.Get("BL") != "Y" || resp.Get("CQ") != "Y" || resp.Get("AE") != borrower.Name {
+		t.Fatalf("unexpected patron fields %+v", resp.Fields)
+	}
+	if resp.Get("BV") != "2.50" || resp.Get("BH") != "USD" {
+		t.Fatalf("fees %s %s, want 2.50 USD", resp.Get("BV"), resp.Get("BH"))
+	}
+
+	if resp = request(borrower.CardNumber, "9999"); resp.Get("CQ") != "N" {
+		t.Fatalf("expected the PIN to be rejected, got %+v", resp.Fields)
+	}
+
+	// Blocked patrons are denied borrowing, renewing and holds
+	lib.fx.Block(borrower)
+	resp = request(borrower.CardNumber, "")
+	if flags := fixed(t, resp, 0, 14); flags != "YYYYY         " {
+		t.Fatalf("patron status %q, want charge, renewal, recall and hold privileges denied and card lost", flags)
+	}
+	if resp.Has("CQ") || resp.Get("AF") == "" {
+		t.Fatalf("unexpected patron fields %+v", resp.Fields)
+	}
+
+	resp = request("29999999999999", "")
+	if resp.Get("BL") != "N" || fixed(t, resp, 0, 14) != "YYYY          " {
+		t.Fatalf("expected an unknown patron, got %+v", resp)
+	}
+}
+
+func TestPatronInformation(t *testing.T) {
+	lib := newLibrary(t)
+	c := lib.login(t)
+	borrower := lib.fx.Borrower()
+	loans := []*models.Borrowing{
+		lib.fx.Borrowing(nil, borrower),
+		lib.fx.Borrowing(nil, borrower),
+		lib.fx.Borrowing(nil, borrower, testutil.Overdue(time.Hour)),
+	}
+	fine := lib.fx.Fine(borrower, 125)
+	ready := lib.fx.Book(nil)
+	lib.fx.Hold(ready, borrower)
+	waiting := lib.fx.Book(nil, func(b *models.Book) { b.Available = false })
+	lib.fx.Hold(waiting, borrower)
+
+	request := func(summary string, fields ...string) *sip2.Message {
+		m := sip2.NewMessage(sip2.PatronInformation, "000", now, summary).Add("AO", "north").Add("AA", borrower.CardNumber)
+		for i := 0; i+1 < len(fields); i += 2 {
+			m.Add(fields[i], fields[i+1])
+		}
+		return c.send(m)
+	}
+
+	resp := request("          ")
+	counts := fixed(t, resp, 35, 24)
+	if counts != "000100010003000100000001" {
+		t.Fatalf("counts %q, want 1 hold, 1 overdue, 3 charged, 1 fine and 1 unavailable hold", counts)
+	}
+	if resp.Has("AU") || resp.Get("BE") != borrower.Email || resp.Get("CB") != "5" {
+		t.Fatalf("unexpected fields %+v", resp.Fields)
+	}
+
+	// The summary picks the list to return, and BP and BQ the range of it
+	if got := request("  Y       ").GetAll("AU"); len(got) != 3 {
+		t.Fatalf("charged items %v, want 3", got)
+	}
+	if got := request("  Y       ", "BP", "2", "BQ", "3").GetAll("AU"); len(got) != 2 {
+		t.Fatalf("charged items %v, want 2", got)
+	}
+	if got := request(" Y        ").GetAll("AT"); len(got) != 1 || got[0] != loans[2].Book.Barcode {
+		t.Fatalf("overdue items %v, want %s", got, loans[2].Book.Barcode)
+	}
+	if got := request("Y         ").GetAll("AS"); len(got) != 1 || got[0] != ready.Barcode {
+		t.Fatalf("hold items %v", got)
+	}
+	if got := request("     Y    ").GetAll("CD"); len(got) != 1 || got[0] != waiting.Barcode {
+		t.Fatalf("unavailable holds %v", got)
+	}
+	if got := request("   Y      ").GetAll("AV"); len(got) != 1 || !strings.HasPrefix(got[0], fine.ID.String()+" 1.25 ") {
+		t.Fatalf("fine items %v", got)
+	}
+
+	resp = c.send(sip2.NewMessage(sip2.EndPatronSession, now).Add("AO", "north").Add("AA", borrower.CardNumber))
+	if resp.Command != sip2.EndSessionResponse || fixed(t, resp, 0, 1) != "Y" {
+		t.Fatalf("unexpected end session response %+v", resp)
+	}
+}
+
+func TestCheckoutAndCheckin(t *testing.T) {
+	lib := newLibrary(t)
+	c := lib.login(t)
+	borrower := lib.fx.Borrower()
+	book := lib.fx.Book(nil)
+
+	resp := c.send(checkout(borrower.CardNumber, book.Barcode))
+	if resp.Command != sip2.CheckoutResponse || fixed(t, resp, 0, 4) != "1NUY" {
+		t.Fatalf("unexpected checkout response %+v", resp)
+	}
+	due := time.Now().AddDate(0, 0, models.DefaultTenantSettings.LoanPeriodDays).Format("2006-01-02")
+	if resp.Get("AJ") != book.Title || resp.Get("AH") != due {
+		t.Fatalf("checked out %q due %q, want %q due %q", resp.Get("AJ"), resp.Get("AH"), book.Title, due)
+	}
+
+	loans, _, err := services.NewBorrowingService(lib.store).FindBorrowings(models.BorrowingFilter{BookID: book.ID, ActiveOnly: true}, 1, 10)
+	if err != nil || len(loans) != 1 {
+		t.Fatalf("expected one loan, got %d, %v", len(loans), err)
+	}
+	if loans[0].BranchID == nil || *loans[0].BranchID != lib.branch.ID {
+		t.Fatal("expected the loan to be made at the terminal's branch")
+	}
+
+	// Checking out an item the patron already has renews it only if the
+	// terminal's renewal policy allows
+	resp = c.send(checkout(borrower.CardNumber, book.Barcode))
+	if fixed(t, resp, 0, 2) != "0N" || resp.Get("AF") == "" {
+		t.Fatalf("unexpected second checkout %+v", resp)
+	}
+	resp = c.send(sip2.NewMessage(sip2.Checkout, "Y", "N", now, now).Add("AO", "north").Add("AA", borrower.CardNumber).Add("AB", book.Barcode))
+	if fixed(t, resp, 0, 2) != "1Y" {
+		t.Fatalf("expected a renewal, got %+v", resp)
+	}
+
+	// Someone else cannot borrow it
+	other := lib.fx.Borrower()
+	resp = c.send(checkout(other.CardNumber, book.Barcode))
+	if fixed(t, resp, 0, 1) != "0" || resp.Get("AF") != "Book is not available for borrowing" {
+		t.Fatalf("unexpected checkout %+v", resp)
+	}
+
+	resp = c.send(checkin(book.Barcode))
+	if resp.Command != sip2.CheckinResponse || fixed(t, resp, 0, 4) != "1YUN" {
+		t.Fatalf("unexpected checkin response %+v", resp)
+	}
+	if resp.Get("AA") != borrower.CardNumber || resp.Get("AJ") != book.Title || resp.Has("CV") {
+		t.Fatalf("unexpected checkin fields %+v", resp.Fields)
+	}
+	got, err := services.NewBorrowingService(lib.store).GetBorrowing(loans[0].ID)
+	if err != nil || got.ReturnedAt == nil || got.ReturnBranchID == nil || *got.ReturnBranchID != lib.branch.ID {
+		t.Fatalf("expected the loan to be returned at the terminal's branch, got %+v, %v", got, err)
+	}
+
+	if resp = c.send(checkin(book.Barcode)); fixed(t, resp, 0, 1) != "0" || resp.Get("AF") != "Item is not checked out" {
+		t.Fatalf("unexpected second checkin %+v", resp)
+	}
+	if resp = c.send(checkin("39999999999999")); fixed(t, resp, 0, 1) != "0" || resp.Get("AF") != "Item not recognised" {
+		t.Fatalf("unexpected checkin of an unknown item %+v", resp)
+	}
+}
+
+func TestCheckoutChecksPatron(t *testing.T) {
+	lib := newLibrary(t)
+	c := lib.login(t)
+	borrower := lib.fx.Borrower()
+	if err := services.NewBorrowerService(lib.store).SetPIN(borrower.ID, &models.SetPINRequest{PIN: "1234"}); err != nil {
+		t.Fatalf("set PIN: %v", err)
+	}
+	book := lib.fx.Book(nil)
+
+	tests := []struct {
+		name    string
+		card    string
+		pin     string
+		barcode string
+		message string
+	}{
+		{"unknown card", "29999999999999", "", book.Barcode, "Library card not recognised"},
+		{"wrong PIN", borrower.CardNumber, "0000", book.Barcode, "Incorrect PIN"},
+		{"unknown item", borrower.CardNumber, "1234", "39999999999999", "Item not recognised"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := checkout(tt.card, tt.barcode)
+			if tt.pin != "" {
+				m.Add("AD", tt.pin)
+			}
+			resp := c.send(m)
+			if fixed(t, resp, 0, 1) != "0" || resp.Get("AF") != tt.message {
+				t.Fatalf("unexpected response %+v", resp)
+			}
+		})
+	}
+
+	// Blocked patrons are told why
+	lib.fx.Block(borrower)
+	resp := c.send(checkout(borrower.CardNumber, book.Barcode).Add("AD", "1234"))
+	if fixed(t, resp, 0, 1) != "0" || resp.Get("AF") != "Borrower is blocked from borrowing" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+}
+
+func TestCheckinAlerts(t *testing.T) {
+	lib := newLibrary(t)
+	c := lib.login(t)
+
+	// An item someone is waiting for goes to the hold shelf
+	held := lib.fx.Borrowing(nil, nil)
+	lib.fx.Hold(&held.Book, nil)
+	resp := c.send(checkin(held.Book.Barcode))
+	if fixed(t, resp, 0, 4) != "1YUY" || resp.Get("CV") != "01" {
+		t.Fatalf("expected a hold alert, got %+v", resp)
+	}
+
+	// An item returned away from its home branch is sent back
+	home := lib.fx.Location(nil)
+	away := lib.fx.Borrowing(lib.fx.Book(nil, testutil.ShelvedAt(home)), nil)
+	resp = c.send(checkin(away.Book.Barcode))
+	if fixed(t, resp, 0, 4) != "1YUY" || resp.Get("CV") != "04" {
+		t.Fatalf("expected a transfer alert, got %+v", resp)
+	}
+	if want := home.Branch.Name + ", " + home.Name; resp.Get("AQ") != want || !strings.Contains(resp.Get("AF"), want) {
+		t.Fatalf("expected the item to be sent to %q, got %+v", want, resp.Fields)
+	}
+
+	// The item now shows as in transit
+	resp = c.send(sip2.NewMessage(sip2.ItemInformation, now).Add("AO", "north").Add("AB", away.Book.Barcode))
+	if fixed(t, resp, 0, 2) != "10" {
+		t.Fatalf("expected the item in transit, got %+v", resp)
+	}
+}
+
+func TestItemInformation(t *testing.T) {
+	lib := newLibrary(t)
+	c := lib.login(t)
+	location := lib.fx.Location(lib.branch)
+	shelved := lib.fx.Book(nil, testutil.ShelvedAt(location))
+	onLoan := lib.fx.Borrowing(nil, nil)
+	held := lib.fx.Book(nil)
+	lib.fx.Hold(held, nil)
+	lib.fx.Hold(held, nil)
+
+	info := func(barcode string) *sip2.Message {
+		return c.send(sip2.NewMessage(sip2.ItemInformation, now).Add("AO", "north").Add("AB", barcode))
+	}
+
+	resp := info(shelved.Barcode)
+	if resp.Command != sip2.ItemInfoResponse || fixed(t, resp, 0, 2) != "03" || resp.Get("AJ") != shelved.Title {
+		t.Fatalf("unexpected item information %+v", resp)
+	}
+	if want := lib.branch.Name + ", " + location.Name; resp.Get("AP") != want || resp.Get("AQ") != want {
+		t.Fatalf("locations %q and %q, want %q", resp.Get("AP"), resp.Get("AQ"), want)
+	}
+
+	resp = info(onLoan.Book.Barcode)
+	if fixed(t, resp, 0, 2) != "04" || resp.Get("AH") != onLoan.DueDate.Format("2006-01-02") {
+		t.Fatalf("unexpected item information %+v", resp)
+	}
+	if resp = info(held.Barcode); fixed(t, resp, 0, 2) != "08" || resp.Get("CF") != "2" {
+		t.Fatalf("unexpected item information %+v", resp)
+	}
+	if resp = info("39999999999999"); fixed(t, resp, 0, 2) != "01" || resp.Get("AF") != "Item not recognised" {
+		t.Fatalf("unexpected item information %+v", resp)
+	}
+}
+
+func TestRenew(t *testing.T) {
+	lib := newLibrary(t)
+	c := lib.login(t)
+	loan := lib.fx.Borrowing(nil, nil)
+	other := lib.fx.Borrower()
+
+	renew := func(card string) *sip2.Message {
+		return c.send(sip2.NewMessage(sip2.Renew, "N", "N", now, now).Add("AO", "north").Add("AA", card).Add("AB", loan.Book.Barcode))
+	}
+
+	resp := renew(other.CardNumber)
+	if fixed(t, resp, 0, 1) != "0" || resp.Get("AF") != "Item is not checked out" {
+		t.Fatalf("expected another patron's renewal to fail, got %+v", resp)
+	}
+
+	resp = renew(loan.Borrower.CardNumber)
+	if resp.Command != sip2.RenewResponse || fixed(t, resp, 0, 2) != "1Y" {
+		t.Fatalf("unexpected renew response %+v", resp)
+	}
+	got, err := services.NewBorrowingService(lib.store).GetBorrowing(loan.ID)
+	if err != nil || got.RenewalCount != 1 || resp.Get("AH") != got.DueDate.Format("2006-01-02") {
+		t.Fatalf("expected the loan to be renewed, got %+v, %v", got, err)
+	}
+
+	// Items someone is waiting for cannot be renewed
+	lib.fx.Hold(&loan.Book, other)
+	if resp = renew(loan.Borrower.CardNumber); fixed(t, resp, 0, 1) != "0" || resp.Get("AF") != "Book is on hold for another borrower" {
+		t.Fatalf("unexpected renew response %+v", resp)
+	}
+}
+
+func TestFeePaid(t *testing.T) {
+	lib := newLibrary(t)
+	c := lib.login(t)
+	borrower := lib.fx.Borrower()
+	first := lib.fx.Fine(borrower, 100)
+	lib.fx.Fine(borrower, 250)
+
+	pay := func(currency, amount, feeID string) *sip2.Message {
+		m := sip2.NewMessage(sip2.FeePaid, now, "01", "00", currency).
+			Add("AO", "north").Add("AA", borrower.CardNumber).Add("BV", amount).Add("BK", "txn-1")
+		if feeID != "" {
+			m.Add("CG", feeID)
+		}
+		return c.send(m)
+	}
+
+	tests := []struct {
+		name     string
+		currency string
+		amount   string
+		feeID    string
+		message  string
+	}{
+		{"other currency", "EUR", "1.00", first.ID.String(), "Payments are only accepted in USD"},
+		{"part payment", "USD", "0.50", first.ID.String(), "Amount must be 1.00 USD"},
+		{"unknown fee", "USD", "1.00", "fine-1", "Fee not recognised"},
+		{"invalid amount", "USD", "one", "", "Invalid amount"},
+		{"part of the balance", "USD", "1.00", "", "Amount must be 3.50 USD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := pay(tt.currency, tt.amount, tt.feeID)
+			if resp.Command != sip2.FeePaidResponse || fixed(t, resp, 0, 1) != "N" || resp.Get("AF") != tt.message {
+				t.Fatalf("unexpected response %+v", resp)
+			}
+		})
+	}
+
+	// A named fee is paid on its own, by the terminal
+	resp := pay("USD", "1.00", first.ID.String())
+	if fixed(t, resp, 0, 1) != "Y" || resp.Get("BK") != "txn-1" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	fine, err := services.NewFineService(lib.store).GetFine(first.ID)
+	if err != nil || fine.Status != models.FinePaid || fine.SettledBy != "sip:north-kiosk" {
+		t.Fatalf("expected the fine to be paid by the terminal, got %+v, %v", fine, err)
+	}
+
+	// Without one the whole balance is paid
+	if resp = pay("USD", "2.50", ""); fixed(t, resp, 0, 1) != "Y" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	_, outstanding, err := services.NewFineService(lib.store).GetFinesByBorrower(borrower.ID, "")
+	if err != nil || outstanding != 0 {
+		t.Fatalf("outstanding %d, %v, want 0", outstanding, err)
+	}
+	if resp = pay("USD", "2.50", ""); fixed(t, resp, 0, 1) != "N" || resp.Get("AF") != "Nothing to pay" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+}
+
+func TestTenantIsolation(t *testing.T) {
+	lib := newLibrary(t)
+	root := lib.store.WithContext(context.Background())
+	south := openLibrary(t, root, "south")
+	borrower := south.fx.Borrower()
+	book := south.fx.Book(nil)
+
+	// North's terminal cannot see south's patrons or items
+	c := lib.login(t)
+	resp := c.send(sip2.NewMessage(sip2.PatronStatusRequest, "000", now).Add("AO", "north").Add("AA", borrower.CardNumber))
+	if resp.Get("BL") != "N" {
+		t.Fatalf("expected another library's patron to be unknown, got %+v", resp)
+	}
+	if resp = c.send(checkout(borrower.CardNumber, book.Barcode)); fixed(t, resp, 0, 1) != "0" {
+		t.Fatalf("expected the checkout to fail, got %+v", resp)
+	}
+
+	// South's terminal logs in to the same server and can
+	southTerminal := &library{terminal: south.terminal, addr: lib.addr}
+	c = southTerminal.login(t)
+	if resp = c.send(checkout(borrower.CardNumber, book.Barcode)); fixed(t, resp, 0, 1) != "1" {
+		t.Fatalf("unexpected checkout %+v", resp)
+	}
+}
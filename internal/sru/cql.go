@@ -0,0 +1,279 @@
+package sru
+
+import (
+	"strings"
+
+	"library-management-go/internal/models"
+)
+
+// index is a CQL index the catalog can search, and the field it searches.
+// Indexes without a context set prefix are the unprefixed names clients
+// commonly send.
+type index struct {
+	name  string
+	field string
+}
+
+var indexes = []index{
+	{"cql.serverChoice", models.SearchAny},
+	{"cql.anywhere", models.SearchAny},
+	{"cql.keywords", models.SearchAny},
+	{"serverChoice", models.SearchAny},
+	{"anywhere", models.SearchAny},
+	{"keywords", models.SearchAny},
+	{"dc.title", models.SearchTitle},
+	{"bath.title", models.SearchTitle},
+	{"title", models.SearchTitle},
+	{"dc.creator", models.SearchAuthor},
+	{"dc.contributor", models.SearchAuthor},
+	{"bath.author", models.SearchAuthor},
+	{"bath.name", models.SearchAuthor},
+	{"bath.personalName", models.SearchAuthor},
+	{"author", models.SearchAuthor},
+	{"creator", models.SearchAuthor},
+	{"dc.identifier", models.SearchISBN},
+	{"bath.isbn", models.SearchISBN},
+	{"isbn", models.SearchISBN},
+	{"dc.subject", models.SearchSubject},
+	{"bath.subject", models.SearchSubject},
+	{"subject", models.SearchSubject},
+}
+
+// findField returns the field the index called name searches, ignoring
+// case as CQL does.
+func findField(name string) (string, bool) {
+	for _, i := range indexes {
+		if strings.EqualFold(i.name, name) {
+			return i.field, true
+		}
+	}
+	return "", false
+}
+
+// allRecords is the index whose every clause matches the whole catalog.
+const allRecords = "cql.allRecords"
+
+// relations are the comparison symbols and named relations of CQL,
+// lowercased, whether or not the catalog supports them.
+var relations = map[string]bool{
+	"=": true, "==": true, "<>": true, "<": true, ">": true, "<=": true, ">=": true,
+	"adj": true, "all": true, "any": true, "exact": true, "within": true, "encloses": true, "scr": true,
+}
+
+// token is a lexical token of a CQL query: a word, a quoted string or one
+// of the symbols ( ) / and the comparison symbols.
+type token struct {
+	text   string
+	quoted bool
+}
+
+func (t token) is(symbol string) bool {
+	return !t.quoted && strings.EqualFold(t.text, symbol)
+}
+
+// lex splits query into tokens.
+func lex(query string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '/':
+			tokens = append(tokens, token{text: string(c)})
+			i++
+		case c == '=' || c == '<' || c == '>':
+			// The longest comparison symbol wins
+			n := 1
+			if i+1 < len(query) {
+				if two := query[i : i+2]; two == "==" || two == "<>" || two == "<=" || two == ">=" {
+					n = 2
+				}
+			}
+			tokens = append(tokens, token{text: query[i : i+n]})
+			i += n
+		case c == '"':
+			var b strings.Builder
+			i++
+			for ; i < len(query) && query[i] != '"'; i++ {
+				// A backslash escapes the character after it
+				if query[i] == '\\' && i+1 < len(query) {
+					i++
+				}
+				b.WriteByte(query[i])
+			}
+			if i == len(query) {
+				return nil, NewDiagnostic(DiagQuerySyntax, "unterminated quoted string")
+			}
+			tokens = append(tokens, token{text: b.String(), quoted: true})
+			i++
+		default:
+			start := i
+			for i < len(query) && !strings.ContainsRune(" \t\r\n()/=<>\"", rune(query[i])) {
+				i++
+			}
+			tokens = append(tokens, token{text: query[start:i]})
+		}
+	}
+	return tokens, nil
+}
+
+// parser parses CQL by recursive descent:
+//
+//	query   = clause { boolean clause } [ "sortby" ... ]
+//	boolean = ( "and" | "or" | "not" | "prox" ) { "/" modifier }
+//	clause  = "(" query ")" | [ index relation { "/" modifier } ] term
+//
+// Booleans are left-associative and of equal precedence.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// ParseCQL parses a CQL query into a search of the catalog. Queries the
+// catalog cannot run, such as ones sorting results or using unsupported
+// indexes or relations, return a Diagnostic.
+func ParseCQL(query string) (*models.BookQuery, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, NewDiagnostic(DiagQuerySyntax, "empty query")
+	}
+
+	p := &parser{tokens: tokens}
+	q, err := p.query()
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := p.peek(); ok {
+		if t.is("sortby") {
+			return nil, NewDiagnostic(DiagSortUnsupported, "")
+		}
+		return nil, NewDiagnostic(DiagQuerySyntax, "unexpected "+t.text)
+	}
+	return q, nil
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) next() (token, bool) {
+	t, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return t, ok
+}
+
+func (p *parser) query() (*models.BookQuery, error) {
+	left, err := p.clause()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.quoted {
+			return left, nil
+		}
+		op := strings.ToLower(t.text)
+		switch op {
+		case models.QueryAnd, models.QueryOr, models.QueryNot:
+		case "prox":
+			return nil, NewDiagnostic(DiagUnsupportedBoolean, t.text)
+		default:
+			return left, nil
+		}
+		p.pos++
+		if t, ok := p.peek(); ok && t.is("/") {
+			return nil, NewDiagnostic(DiagUnsupportedBooleanModifier, "")
+		}
+
+		right, err := p.clause()
+		if err != nil {
+			return nil, err
+		}
+		left = &models.BookQuery{Op: op, Left: left, Right: right}
+	}
+}
+
+func (p *parser) clause() (*models.BookQuery, error) {
+	t, ok := p.next()
+	if !ok {
+		return nil, NewDiagnostic(DiagQuerySyntax, "query ends where a search term was expected")
+	}
+	if t.is("(") {
+		q, err := p.query()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := p.next(); !ok || !t.is(")") {
+			return nil, NewDiagnostic(DiagQuerySyntax, "missing )")
+		}
+		return q, nil
+	}
+	if !t.quoted && strings.ContainsAny(t.text[:1], ")/=<>") {
+		return nil, NewDiagnostic(DiagQuerySyntax, "unexpected "+t.text)
+	}
+
+	// A word followed by a relation is an index; anything else a term
+	index, relation := "cql.serverChoice", "="
+	if r, ok := p.peek(); ok && !t.quoted && !r.quoted && relations[strings.ToLower(r.text)] {
+		index, relation = t.text, strings.ToLower(r.text)
+		p.pos++
+		if m, ok := p.peek(); ok && m.is("/") {
+			return nil, NewDiagnostic(DiagUnsupportedRelationModifier, "")
+		}
+		if t, ok = p.next(); !ok {
+			return nil, NewDiagnostic(DiagQuerySyntax, "query ends where a search term was expected")
+		}
+		if !t.quoted && strings.ContainsAny(t.text[:1], "()/=<>") {
+			return nil, NewDiagnostic(DiagQuerySyntax, "unexpected "+t.text)
+		}
+	}
+	return term(index, relation, t.text)
+}
+
+// term returns the search for a clause comparing index to value.
+func term(index, relation, value string) (*models.BookQuery, error) {
+	// Every book has a title, which an empty pattern matches
+	if strings.EqualFold(index, allRecords) {
+		return &models.BookQuery{Field: models.SearchTitle}, nil
+	}
+	field, ok := findField(index)
+	if !ok {
+		return nil, NewDiagnostic(DiagUnsupportedIndex, index)
+	}
+
+	switch relation {
+	case "=", "adj", "scr":
+		return &models.BookQuery{Field: field, Term: value}, nil
+	case "==", "exact":
+		return &models.BookQuery{Field: field, Term: value, Exact: true}, nil
+	case "all", "any":
+		// Each word is a term, all or any of which must match
+		op := models.QueryAnd
+		if relation == "any" {
+			op = models.QueryOr
+		}
+		var q *models.BookQuery
+		for _, word := range strings.Fields(value) {
+			w := &models.BookQuery{Field: field, Term: word}
+			if q == nil {
+				q = w
+			} else {
+				q = &models.BookQuery{Op: op, Left: q, Right: w}
+			}
+		}
+		if q == nil {
+			q = &models.BookQuery{Field: field}
+		}
+		return q, nil
+	}
+	return nil, NewDiagnostic(DiagUnsupportedRelation, relation)
+}
@@ -0,0 +1,96 @@
+package sru
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"library-management-go/internal/models"
+)
+
+// format writes q back in a compact prefix form for comparison.
+func format(q *models.BookQuery) string {
+	if q.Op != "" {
+		return "(" + q.Op + " " + format(q.Left) + " " + format(q.Right) + ")"
+	}
+	relation := "="
+	if q.Exact {
+		relation = "=="
+	}
+	return q.Field + relation + q.Term
+}
+
+func TestParseCQL(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"dune", "any=dune"},
+		{`"the left hand"`, "any=the left hand"},
+		{"title=dune", "title=dune"},
+		{"dc.title = dune", "title=dune"},
+		{"DC.Title=Dune", "title=Dune"},
+		{"bath.isbn == 978-0-441-17271-9", "isbn==978-0-441-17271-9"},
+		{"dc.creator exact \"Le Guin, Ursula\"", "author==Le Guin, Ursula"},
+		{`title="say \"hello\""`, `title=say "hello"`},
+		{"title=harry*", "title=harry*"},
+		{"subject any \"fantasy horror\"", "(or subject=fantasy subject=horror)"},
+		{"title all \"left hand darkness\"", "(and (and title=left title=hand) title=darkness)"},
+		{"title adj \"left hand\"", "title=left hand"},
+		{"dune and author=herbert", "(and any=dune author=herbert)"},
+		{"a OR b not c", "(not (or any=a any=b) any=c)"},
+		{"a and (b or c)", "(and any=a (or any=b any=c))"},
+		{"((title=a))", "title=a"},
+		{"cql.allRecords = 1", "title="},
+		{`title=""`, "title="},
+		{`"and"`, "any=and"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseCQL(tt.query)
+			if err != nil {
+				t.Fatalf("ParseCQL: %v", err)
+			}
+			if got := format(q); got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCQLDiagnostics(t *testing.T) {
+	tests := []struct {
+		query string
+		code  int
+	}{
+		{"", DiagQuerySyntax},
+		{"   ", DiagQuerySyntax},
+		{"title=", DiagQuerySyntax},
+		{"dune and", DiagQuerySyntax},
+		{"(dune", DiagQuerySyntax},
+		{"dune)", DiagQuerySyntax},
+		{"two words", DiagQuerySyntax},
+		{`"unterminated`, DiagQuerySyntax},
+		{"= dune", DiagQuerySyntax},
+		{">dc=\"info:srw/cql-context-set/1/dc-v1.1\" dc.title=dune", DiagQuerySyntax},
+		{"dc.date=1999", DiagUnsupportedIndex},
+		{"title < dune", DiagUnsupportedRelation},
+		{"title within \"a z\"", DiagUnsupportedRelation},
+		{"title =/stem dune", DiagUnsupportedRelationModifier},
+		{"a prox b", DiagUnsupportedBoolean},
+		{"a and/rel.algorithm=cori b", DiagUnsupportedBooleanModifier},
+		{"dune sortby title", DiagSortUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ParseCQL(tt.query)
+			var d *Diagnostic
+			if !errors.As(err, &d) || d.Code != tt.code {
+				t.Fatalf("got %v, want diagnostic %d", err, tt.code)
+			}
+			if !strings.HasPrefix(d.URI(), "info:srw/diagnostic/1/") {
+				t.Fatalf("URI = %q", d.URI())
+			}
+		})
+	}
+}
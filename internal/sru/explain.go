@@ -0,0 +1,165 @@
+package sru
+
+import (
+	"encoding/xml"
+	"sort"
+	"strconv"
+	"strings"
+
+	"library-management-go/internal/models"
+)
+
+// Server is where the SRU service is reached and what it is called.
+type Server struct {
+	Host     string
+	Port     int
+	Database string
+	Title    string
+}
+
+type explainRecord struct {
+	XMLName      xml.Name      `xml:"explain"`
+	Xmlns        string        `xml:"xmlns,attr"`
+	ServerInfo   serverInfo    `xml:"serverInfo"`
+	DatabaseInfo databaseInfo  `xml:"databaseInfo"`
+	IndexInfo    indexInfo     `xml:"indexInfo"`
+	SchemaInfo   []schemaInfo  `xml:"schemaInfo>schema"`
+	ConfigInfo   []configEntry `xml:"configInfo>x"`
+}
+
+type serverInfo struct {
+	Protocol  string `xml:"protocol,attr"`
+	Version   string `xml:"version,attr"`
+	Transport string `xml:"transport,attr"`
+	Host      string `xml:"host"`
+	Port      int    `xml:"port"`
+	Database  string `xml:"database"`
+}
+
+type databaseInfo struct {
+	Title       explainText `xml:"title"`
+	Description explainText `xml:"description"`
+}
+
+type explainText struct {
+	Lang    string `xml:"lang,attr"`
+	Primary bool   `xml:"primary,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type indexInfo struct {
+	Sets    []contextSet   `xml:"set"`
+	Indexes []explainIndex `xml:"index"`
+}
+
+type contextSet struct {
+	Name       string `xml:"name,attr"`
+	Identifier string `xml:"identifier,attr"`
+}
+
+type explainIndex struct {
+	Title string        `xml:"title"`
+	Names []explainName `xml:"map>name"`
+}
+
+type explainName struct {
+	Set  string `xml:"set,attr"`
+	Name string `xml:",chardata"`
+}
+
+type schemaInfo struct {
+	Identifier string `xml:"identifier,attr"`
+	Name       string `xml:"name,attr"`
+	Title      string `xml:"title"`
+}
+
+// configEntry is a default, setting or supports element of configInfo.
+type configEntry struct {
+	XMLName xml.Name
+	Type    string `xml:"type,attr"`
+	Value   string `xml:",chardata"`
+}
+
+// contextSets are the CQL context sets of the indexes.
+var contextSets = []contextSet{
+	{Name: "cql", Identifier: "info:srw/cql-context-set/1/cql-v1.2"},
+	{Name: "dc", Identifier: "info:srw/cql-context-set/1/dc-v1.1"},
+	{Name: "bath", Identifier: "http://zing.z3950.org/cql/bath/2.0/"},
+}
+
+// indexTitles title the indexes by the field they search.
+var indexTitles = map[string]string{
+	models.SearchAny:     "Any field",
+	models.SearchTitle:   "Title",
+	models.SearchAuthor:  "Author or other contributor",
+	models.SearchISBN:    "ISBN",
+	models.SearchSubject: "Subject",
+}
+
+// Explain renders the response to r describing server.
+func Explain(r *Request, server Server) ([]byte, error) {
+	explain := explainRecord{
+		Xmlns: explainNS,
+		ServerInfo: serverInfo{
+			Protocol:  "SRU",
+			Version:   r.Version,
+			Transport: "http",
+			Host:      server.Host,
+			Port:      server.Port,
+			Database:  server.Database,
+		},
+		DatabaseInfo: databaseInfo{
+			Title:       explainText{Lang: "en", Primary: true, Text: server.Title},
+			Description: explainText{Lang: "en", Primary: true, Text: "The catalog of " + server.Title},
+		},
+		IndexInfo: indexInfo{Sets: contextSets},
+	}
+
+	// Indexes are listed by field, each with the qualified names it has
+	byField := map[string][]explainName{}
+	for _, index := range indexes {
+		for _, set := range contextSets {
+			if name, ok := strings.CutPrefix(index.name, set.Name+"."); ok {
+				byField[index.field] = append(byField[index.field], explainName{Set: set.Name, Name: name})
+			}
+		}
+	}
+	for _, field := range []string{models.SearchAny, models.SearchTitle, models.SearchAuthor, models.SearchISBN, models.SearchSubject} {
+		names := byField[field]
+		sort.Slice(names, func(i, j int) bool {
+			if names[i].Set != names[j].Set {
+				return names[i].Set < names[j].Set
+			}
+			return names[i].Name < names[j].Name
+		})
+		explain.IndexInfo.Indexes = append(explain.IndexInfo.Indexes, explainIndex{Title: indexTitles[field], Names: names})
+	}
+
+	for _, s := range schemas {
+		explain.SchemaInfo = append(explain.SchemaInfo, schemaInfo{Identifier: s.Identifier, Name: s.Name, Title: s.Title})
+	}
+	config := func(element, kind, value string) {
+		explain.ConfigInfo = append(explain.ConfigInfo, configEntry{XMLName: xml.Name{Local: element}, Type: kind, Value: value})
+	}
+	config("default", "numberOfRecords", strconv.Itoa(DefaultMaximumRecords))
+	config("setting", "maximumRecords", strconv.Itoa(MaxMaximumRecords))
+	config("default", "contextSet", "cql")
+	config("default", "index", "cql.serverChoice")
+	config("default", "relation", "=")
+	for _, relation := range []string{"=", "==", "adj", "all", "any", "exact"} {
+		config("supports", "relation", relation)
+	}
+	for _, boolean := range []string{"and", "or", "not"} {
+		config("supports", "booleanOperator", boolean)
+	}
+	config("supports", "maskingCharacter", "*")
+	config("supports", "emptyTerm", "")
+
+	resp := newResponse(r, OpExplain)
+	rec, err := newRecord(r, explainNS, explain)
+	if err != nil {
+		return nil, err
+	}
+	resp.Record = &rec
+	return marshal(resp)
+}
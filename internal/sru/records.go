@@ -0,0 +1,238 @@
+package sru
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+
+	"library-management-go/internal/callnumber"
+	"library-management-go/internal/marc"
+	"library-management-go/internal/models"
+)
+
+// Schema is a record schema books can be retrieved in.
+type Schema struct {
+	// Name is the short name clients may ask for the schema by, Identifier
+	// its URI
+	Name       string
+	Identifier string
+	Title      string
+	record     func(book *models.Book) interface{}
+}
+
+// schemas are the record schemas the server returns, the default first.
+var schemas = []Schema{
+	{
+		Name:       "dc",
+		Identifier: "info:srw/schema/1/dc-v1.1",
+		Title:      "Dublin Core",
+		record:     func(book *models.Book) interface{} { return dublinCore(book) },
+	},
+	{
+		Name:       "marcxml",
+		Identifier: "info:srw/schema/1/marcxml-v1.1",
+		Title:      "MARCXML",
+		record:     func(book *models.Book) interface{} { return MARC(book) },
+	},
+}
+
+// schemaAliases are other names clients ask for the schemas by.
+var schemaAliases = map[string]string{
+	"info:srw/schema/1/dc-schema": "dc",
+	"oai_dc":                      "dc",
+	"marc21":                      "marcxml",
+	"marc":                        "marcxml",
+}
+
+// findSchema returns the schema called name, by short name or identifier.
+func findSchema(name string) *Schema {
+	if alias, ok := schemaAliases[strings.ToLower(name)]; ok {
+		name = alias
+	}
+	for i := range schemas {
+		if strings.EqualFold(schemas[i].Name, name) || schemas[i].Identifier == name {
+			return &schemas[i]
+		}
+	}
+	return nil
+}
+
+// SearchRetrieve renders the response to r listing books, the page of the
+// total matching books that starts at r.StartRecord.
+func SearchRetrieve(r *Request, books []models.Book, total int64) ([]byte, error) {
+	if total > 0 && int64(r.StartRecord) > total {
+		return Failure(r, NewDiagnostic(DiagFirstRecordOutOfRange, strconv.Itoa(r.StartRecord)))
+	}
+
+	resp := newResponse(r, OpSearchRetrieve)
+	resp.NumberOfRecords = &total
+	if len(books) > 0 {
+		resp.Records = &records{}
+	}
+	for i := range books {
+		rec, err := newRecord(r, r.Schema.Identifier, r.Schema.record(&books[i]))
+		if err != nil {
+			return nil, err
+		}
+		rec.Position = r.StartRecord + i
+		resp.Records.Records = append(resp.Records.Records, rec)
+	}
+	if next := r.StartRecord + len(books); len(books) > 0 && int64(next) <= total {
+		resp.NextRecordPosition = next
+	}
+	if r.Version == Version20 {
+		resp.ResultCountPrecision = exactCount
+	}
+	return marshal(resp)
+}
+
+const (
+	dcNS          = "http://purl.org/dc/elements/1.1/"
+	dcRecordNS    = "info:srw/schema/1/dc-schema"
+	publishedDate = "2006-01-02"
+)
+
+// dcRecord is a Dublin Core record in the SRU dc schema.
+type dcRecord struct {
+	XMLName     xml.Name `xml:"srw_dc:dc"`
+	XmlnsRecord string   `xml:"xmlns:srw_dc,attr"`
+	XmlnsDC     string   `xml:"xmlns:dc,attr"`
+	Title       string   `xml:"dc:title"`
+	Creators    []string `xml:"dc:creator"`
+	Contributor []string `xml:"dc:contributor"`
+	Subjects    []string `xml:"dc:subject"`
+	Description string   `xml:"dc:description,omitempty"`
+	Publisher   string   `xml:"dc:publisher,omitempty"`
+	Date        string   `xml:"dc:date,omitempty"`
+	Type        string   `xml:"dc:type"`
+	Format      string   `xml:"dc:format,omitempty"`
+	Identifiers []string `xml:"dc:identifier"`
+	Language    string   `xml:"dc:language,omitempty"`
+	Relation    string   `xml:"dc:relation,omitempty"`
+}
+
+func dublinCore(book *models.Book) *dcRecord {
+	dc := &dcRecord{
+		XmlnsRecord: dcRecordNS,
+		XmlnsDC:     dcNS,
+		Title:       book.Title,
+		Description: book.Description,
+		Type:        "Text",
+		Format:      book.Format,
+		Language:    book.Language,
+	}
+	for _, c := range book.Contributors {
+		if c.Role == models.RoleAuthor {
+			dc.Creators = append(dc.Creators, c.Author.Name)
+		} else {
+			dc.Contributor = append(dc.Contributor, c.Author.Name)
+		}
+	}
+	for _, s := range book.Subjects {
+		dc.Subjects = append(dc.Subjects, s.Name)
+	}
+	if book.Publisher != nil {
+		dc.Publisher = book.Publisher.Name
+	}
+	if !book.PublishedAt.IsZero() {
+		dc.Date = book.PublishedAt.Format(publishedDate)
+	}
+	if book.ISBN != "" {
+		dc.Identifiers = append(dc.Identifiers, "urn:isbn:"+book.ISBN)
+	}
+	dc.Identifiers = append(dc.Identifiers, "urn:uuid:"+book.ID.String())
+	if book.Series != nil {
+		dc.Relation = book.Series.Name
+	}
+	return dc
+}
+
+// leader is the leader of every MARC record: a new record of language
+// material, a monograph, in Unicode, without ISBD punctuation.
+const leader = "00000nam a2200000   4500"
+
+// MARC describes book as a MARC 21 bibliographic record.
+func MARC(book *models.Book) *marc.Record {
+	r := &marc.Record{Leader: leader}
+	control := func(tag, value string) {
+		r.Fields = append(r.Fields, marc.Field{Tag: tag, Value: value})
+	}
+	data := func(tag string, ind1, ind2 byte, subfields ...marc.Subfield) {
+		// Subfields without a value are left out, and fields without any
+		var kept []marc.Subfield
+		for _, sf := range subfields {
+			if sf.Value != "" {
+				kept = append(kept, sf)
+			}
+		}
+		if len(kept) > 0 {
+			r.Fields = append(r.Fields, marc.Field{Tag: tag, Ind1: ind1, Ind2: ind2, Subfields: kept})
+		}
+	}
+	sub := func(code byte, value string) marc.Subfield {
+		return marc.Subfield{Code: code, Value: value}
+	}
+
+	control("001", book.ID.String())
+	if !book.UpdatedAt.IsZero() {
+		control("005", book.UpdatedAt.UTC().Format("20060102150405")+".0")
+	}
+	data("020", ' ', ' ', sub('a', book.ISBN))
+	switch book.ClassificationScheme {
+	case callnumber.LC:
+		data("050", ' ', '4', sub('a', book.CallNumber))
+	case callnumber.Dewey:
+		data("082", '0', '4', sub('a', book.CallNumber))
+	case callnumber.Local:
+		data("099", ' ', '9', sub('a', book.CallNumber))
+	}
+
+	// The first author is the main entry; everyone else an added entry
+	var main *models.BookContributor
+	for i := range book.Contributors {
+		if book.Contributors[i].Role == models.RoleAuthor {
+			main = &book.Contributors[i]
+			data("100", '1', ' ', sub('a', main.Author.Name), sub('e', main.Role))
+			break
+		}
+	}
+	titleInd1 := byte('0')
+	if main != nil {
+		titleInd1 = '1'
+	}
+	data("245", titleInd1, '0', sub('a', book.Title))
+	data("250", ' ', ' ', sub('a', book.Edition))
+
+	var publisher, year string
+	if book.Publisher != nil {
+		publisher = book.Publisher.Name
+	}
+	if !book.PublishedAt.IsZero() {
+		year = strconv.Itoa(book.PublishedAt.Year())
+	}
+	data("264", ' ', '1', sub('b', publisher), sub('c', year))
+	if book.Pages > 0 {
+		data("300", ' ', ' ', sub('a', strconv.Itoa(book.Pages)+" pages"))
+	}
+	if book.Series != nil {
+		var volume string
+		if book.SeriesVolume > 0 {
+			volume = strconv.Itoa(book.SeriesVolume)
+		}
+		data("490", '0', ' ', sub('a', book.Series.Name), sub('v', volume))
+	}
+	data("520", ' ', ' ', sub('a', book.Description))
+	data("546", ' ', ' ', sub('a', book.Language))
+	for _, s := range book.Subjects {
+		data("650", ' ', '4', sub('a', s.Name))
+	}
+	for i := range book.Contributors {
+		if c := &book.Contributors[i]; c != main {
+			data("700", '1', ' ', sub('a', c.Author.Name), sub('e', c.Role))
+		}
+	}
+	if book.Cover != nil {
+		data("856", '4', '2', sub('3', "Cover image"), sub('u', book.Cover.URL))
+	}
+	return r
+}
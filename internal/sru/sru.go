@@ -0,0 +1,310 @@
+// Package sru serves the catalog over SRU (Search/Retrieve via URL),
+// versions 1.2 and 2.0, for union catalogs and interlibrary loan partners.
+//
+// A searchRetrieve request carries a CQL query, which ParseCQL turns into a
+// search of the catalog, and returns the matching books as Dublin Core or
+// MARCXML records. An explain request describes the server: the indexes
+// and relations its queries can use and the record schemas it returns.
+// Requests the server cannot answer get a response carrying a diagnostic
+// rather than an HTTP error, as SRU clients expect.
+package sru
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strconv"
+)
+
+// SRU versions. Version 1.1 requests are answered as 1.2, which is
+// compatible with it.
+const (
+	Version12 = "1.2"
+	Version20 = "2.0"
+)
+
+// Operations
+const (
+	OpSearchRetrieve = "searchRetrieve"
+	OpExplain        = "explain"
+)
+
+// ContentType is the media type of every response.
+const ContentType = "application/xml; charset=utf-8"
+
+// Result set sizes.
+const (
+	DefaultMaximumRecords = 10
+	MaxMaximumRecords     = 100
+)
+
+const (
+	ns12           = "http://www.loc.gov/zing/srw/"
+	ns20           = "http://docs.oasis-open.org/ns/search-ws/sruResponse"
+	diagnosticNS12 = "http://www.loc.gov/zing/srw/diagnostic/"
+	diagnosticNS20 = "http://docs.oasis-open.org/ns/search-ws/diagnostic"
+	explainNS      = "http://explain.z3950.org/dtd/2.0/"
+	// exactCount says numberOfRecords is exact rather than an estimate
+	exactCount = "info:srw/vocabulary/resultCountPrecision/1/exact"
+)
+
+// Diagnostic codes, from the SRU diagnostics list.
+const (
+	DiagGeneral                     = 1
+	DiagUnsupportedOperation        = 4
+	DiagUnsupportedVersion          = 5
+	DiagUnsupportedParameterValue   = 6
+	DiagMandatoryParameter          = 7
+	DiagQuerySyntax                 = 10
+	DiagUnsupportedIndex            = 16
+	DiagUnsupportedRelation         = 19
+	DiagUnsupportedRelationModifier = 20
+	DiagUnsupportedBoolean          = 37
+	DiagUnsupportedBooleanModifier  = 46
+	DiagFirstRecordOutOfRange       = 61
+	DiagUnknownSchema               = 66
+	DiagUnsupportedPacking          = 71
+	DiagSortUnsupported             = 80
+)
+
+var diagnosticMessages = map[int]string{
+	DiagGeneral:                     "General system error",
+	DiagUnsupportedOperation:        "Unsupported operation",
+	DiagUnsupportedVersion:          "Unsupported version",
+	DiagUnsupportedParameterValue:   "Unsupported parameter value",
+	DiagMandatoryParameter:          "Mandatory parameter not supplied",
+	DiagQuerySyntax:                 "Query syntax error",
+	DiagUnsupportedIndex:            "Unsupported index",
+	DiagUnsupportedRelation:         "Unsupported relation",
+	DiagUnsupportedRelationModifier: "Unsupported relation modifier",
+	DiagUnsupportedBoolean:          "Unsupported boolean operator",
+	DiagUnsupportedBooleanModifier:  "Unsupported boolean modifier",
+	DiagFirstRecordOutOfRange:       "First record position out of range",
+	DiagUnknownSchema:               "Unknown schema for retrieval",
+	DiagUnsupportedPacking:          "Unsupported record packing",
+	DiagSortUnsupported:             "Sort not supported",
+}
+
+// Diagnostic is an SRU diagnostic: why a request could not be answered.
+// Details says what in the request was at fault.
+type Diagnostic struct {
+	Code    int
+	Details string
+}
+
+// NewDiagnostic returns the diagnostic with code.
+func NewDiagnostic(code int, details string) *Diagnostic {
+	return &Diagnostic{Code: code, Details: details}
+}
+
+// URI identifies the diagnostic.
+func (d *Diagnostic) URI() string {
+	return "info:srw/diagnostic/1/" + strconv.Itoa(d.Code)
+}
+
+func (d *Diagnostic) Error() string {
+	message := diagnosticMessages[d.Code]
+	if d.Details != "" {
+		message += ": " + d.Details
+	}
+	return message
+}
+
+// Request is a parsed SRU request.
+type Request struct {
+	Version        string
+	Operation      string
+	Query          string
+	StartRecord    int
+	MaximumRecords int
+	Schema         *Schema
+	// Escaped records are sent as XML-escaped strings rather than XML
+	Escaped bool
+}
+
+// ParseRequest parses the parameters of an SRU request. A request that
+// cannot be answered returns a Diagnostic alongside as much of the request
+// as was parsed, which is enough to answer it in its version.
+func ParseRequest(params url.Values) (*Request, error) {
+	r := &Request{Version: Version20, StartRecord: 1, MaximumRecords: DefaultMaximumRecords, Schema: &schemas[0]}
+
+	// SRU 2.0 dropped the operation parameter; 1.2 requires it
+	operation := params.Get("operation")
+	switch version := params.Get("version"); version {
+	case "1.1", Version12:
+		r.Version = Version12
+	case Version20:
+	case "":
+		if operation != "" {
+			r.Version = Version12
+		}
+	default:
+		r.Version = Version12
+		return r, NewDiagnostic(DiagUnsupportedVersion, version)
+	}
+
+	r.Operation = operation
+	r.Query = params.Get("query")
+	if r.Operation == "" {
+		if r.Version == Version12 {
+			return r, NewDiagnostic(DiagMandatoryParameter, "operation")
+		}
+		r.Operation = OpExplain
+		if r.Query != "" {
+			r.Operation = OpSearchRetrieve
+		}
+	}
+	switch r.Operation {
+	case OpExplain:
+		return r, nil
+	case OpSearchRetrieve:
+	default:
+		return r, NewDiagnostic(DiagUnsupportedOperation, r.Operation)
+	}
+
+	if r.Query == "" {
+		return r, NewDiagnostic(DiagMandatoryParameter, "query")
+	}
+	if queryType := params.Get("queryType"); queryType != "" && queryType != "cql" {
+		return r, NewDiagnostic(DiagUnsupportedParameterValue, "queryType")
+	}
+	if params.Get("sortKeys") != "" {
+		return r, NewDiagnostic(DiagSortUnsupported, "")
+	}
+
+	var err error
+	if r.StartRecord, err = intParam(params, "startRecord", 1, 1); err != nil {
+		return r, err
+	}
+	if r.MaximumRecords, err = intParam(params, "maximumRecords", DefaultMaximumRecords, 0); err != nil {
+		return r, err
+	}
+	r.MaximumRecords = min(r.MaximumRecords, MaxMaximumRecords)
+
+	if name := params.Get("recordSchema"); name != "" {
+		if r.Schema = findSchema(name); r.Schema == nil {
+			return r, NewDiagnostic(DiagUnknownSchema, name)
+		}
+	}
+	packing := params.Get("recordPacking")
+	if r.Version == Version20 {
+		packing = params.Get("recordXMLEscaping")
+	}
+	switch packing {
+	case "", "xml":
+	case "string":
+		r.Escaped = true
+	default:
+		return r, NewDiagnostic(DiagUnsupportedPacking, packing)
+	}
+	return r, nil
+}
+
+// intParam reads the integer parameter name, at least minimum.
+func intParam(params url.Values, name string, value, minimum int) (int, error) {
+	if s := params.Get(name); s != "" {
+		var err error
+		if value, err = strconv.Atoi(s); err != nil || value < minimum {
+			return 0, NewDiagnostic(DiagUnsupportedParameterValue, name)
+		}
+	}
+	return value, nil
+}
+
+type response struct {
+	XMLName              xml.Name
+	Version              string       `xml:"version"`
+	NumberOfRecords      *int64       `xml:"numberOfRecords"`
+	Records              *records     `xml:"records"`
+	Record               *record      `xml:"record"`
+	NextRecordPosition   int          `xml:"nextRecordPosition,omitempty"`
+	Diagnostics          *diagnostics `xml:"diagnostics"`
+	ResultCountPrecision string       `xml:"resultCountPrecision,omitempty"`
+}
+
+type records struct {
+	Records []record `xml:"record"`
+}
+
+type diagnostics struct {
+	Diagnostics []xmlDiagnostic `xml:"diagnostic"`
+}
+
+type record struct {
+	Schema      string     `xml:"recordSchema"`
+	Packing     string     `xml:"recordPacking,omitempty"`
+	XMLEscaping string     `xml:"recordXMLEscaping,omitempty"`
+	Data        recordData `xml:"recordData"`
+	Position    int        `xml:"recordPosition,omitempty"`
+}
+
+type recordData struct {
+	XML  interface{}
+	Text string `xml:",chardata"`
+}
+
+type xmlDiagnostic struct {
+	XMLName xml.Name
+	URI     string `xml:"uri"`
+	Details string `xml:"details,omitempty"`
+	Message string `xml:"message"`
+}
+
+// newResponse starts the response to r, in its version.
+func newResponse(r *Request, operation string) *response {
+	ns := ns12
+	if r.Version == Version20 {
+		ns = ns20
+	}
+	return &response{XMLName: xml.Name{Space: ns, Local: operation + "Response"}, Version: r.Version}
+}
+
+// newRecord wraps data, a record in schema, packed as r asks.
+func newRecord(r *Request, schema string, data interface{}) (record, error) {
+	rec := record{Schema: schema, Data: recordData{XML: data}}
+	packing := "xml"
+	if r.Escaped {
+		text, err := xml.Marshal(data)
+		if err != nil {
+			return record{}, err
+		}
+		rec.Data, packing = recordData{Text: string(text)}, "string"
+	}
+	if r.Version == Version20 {
+		rec.XMLEscaping = packing
+	} else {
+		rec.Packing = packing
+	}
+	return rec, nil
+}
+
+// Failure renders the response to r reporting d.
+func Failure(r *Request, d *Diagnostic) ([]byte, error) {
+	operation := r.Operation
+	if operation != OpExplain {
+		operation = OpSearchRetrieve
+	}
+	resp := newResponse(r, operation)
+	if operation == OpSearchRetrieve {
+		var none int64
+		resp.NumberOfRecords = &none
+	}
+	ns := diagnosticNS12
+	if r.Version == Version20 {
+		ns = diagnosticNS20
+	}
+	resp.Diagnostics = &diagnostics{Diagnostics: []xmlDiagnostic{{
+		XMLName: xml.Name{Space: ns, Local: "diagnostic"},
+		URI:     d.URI(),
+		Details: d.Details,
+		Message: diagnosticMessages[d.Code],
+	}}}
+	return marshal(resp)
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,327 @@
+package sru
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"library-management-go/internal/marc"
+	"library-management-go/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestParseRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    string
+		version   string
+		operation string
+		code      int
+	}{
+		{"1.2 search", "version=1.2&operation=searchRetrieve&query=dune", Version12, OpSearchRetrieve, 0},
+		{"1.1 is answered as 1.2", "version=1.1&operation=explain", Version12, OpExplain, 0},
+		{"operation implies 1.2", "operation=explain", Version12, OpExplain, 0},
+		{"2.0 search", "query=dune", Version20, OpSearchRetrieve, 0},
+		{"2.0 explain", "", Version20, OpExplain, 0},
+		{"unknown version", "version=3.0&operation=explain", Version12, "", DiagUnsupportedVersion},
+		{"1.2 without operation", "version=1.2&query=dune", Version12, "", DiagMandatoryParameter},
+		{"scan", "version=1.2&operation=scan&scanClause=dune", Version12, "scan", DiagUnsupportedOperation},
+		{"search without query", "version=1.2&operation=searchRetrieve", Version12, OpSearchRetrieve, DiagMandatoryParameter},
+		{"bad start", "query=dune&startRecord=0", Version20, OpSearchRetrieve, DiagUnsupportedParameterValue},
+		{"bad maximum", "query=dune&maximumRecords=lots", Version20, OpSearchRetrieve, DiagUnsupportedParameterValue},
+		{"unknown schema", "query=dune&recordSchema=mods", Version20, OpSearchRetrieve, DiagUnknownSchema},
+		{"unknown packing", "version=1.2&operation=searchRetrieve&query=dune&recordPacking=json", Version12, OpSearchRetrieve, DiagUnsupportedPacking},
+		{"sort keys", "query=dune&sortKeys=title", Version20, OpSearchRetrieve, DiagSortUnsupported},
+		{"other query type", "query=dune&queryType=searchTerms", Version20, OpSearchRetrieve, DiagUnsupportedParameterValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, _ := url.ParseQuery(tt.params)
+			r, err := ParseRequest(params)
+			var d *Diagnostic
+			if tt.code == 0 && err != nil || tt.code != 0 && (!errors.As(err, &d) || d.Code != tt.code) {
+				t.Fatalf("got %v, want diagnostic %d", err, tt.code)
+			}
+			if r.Version != tt.version || r.Operation != tt.operation {
+				t.Fatalf("version %s, operation %s", r.Version, r.Operation)
+			}
+		})
+	}
+
+	params, _ := url.ParseQuery("query=dune&startRecord=11&maximumRecords=500&recordSchema=info:srw/schema/1/marcxml-v1.1&recordXMLEscaping=string")
+	r, err := ParseRequest(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.StartRecord != 11 || r.MaximumRecords != MaxMaximumRecords || r.Schema.Name != "marcxml" || !r.Escaped {
+		t.Fatalf("unexpected request %+v", r)
+	}
+}
+
+func sampleBook() models.Book {
+	author := models.Author{ID: uuid.New(), Name: "Frank Herbert"}
+	editor := models.Author{ID: uuid.New(), Name: "Jane Editor"}
+	return models.Book{
+		ID:          uuid.New(),
+		Title:       "Dune",
+		ISBN:        "9780441172719",
+		Description: "A desert planet & its spice",
+		Language:    "en",
+		Edition:     "40th anniversary edition",
+		Pages:       604,
+		PublishedAt: time.Date(1965, 8, 1, 0, 0, 0, 0, time.UTC),
+		Contributors: []models.BookContributor{
+			{AuthorID: author.ID, Author: author, Role: models.RoleAuthor},
+			{AuthorID: editor.ID, Author: editor, Role: models.RoleEditor},
+		},
+		Subjects:             []models.Subject{{Name: "Science fiction"}},
+		Publisher:            &models.Publisher{Name: "Ace"},
+		Series:               &models.Series{Name: "Dune Chronicles"},
+		SeriesVolume:         1,
+		CallNumber:           "813.54 HER",
+		ClassificationScheme: "dewey",
+		UpdatedAt:            time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestMARC(t *testing.T) {
+	book := sampleBook()
+	r := MARC(&book)
+
+	if len(r.Leader) != 24 {
+		t.Fatalf("leader %q is %d characters", r.Leader, len(r.Leader))
+	}
+	tests := []struct {
+		tag  string
+		code byte
+		want string
+	}{
+		{"020", 'a', "9780441172719"},
+		{"082", 'a', "813.54 HER"},
+		{"100", 'a', "Frank Herbert"},
+		{"245", 'a', "Dune"},
+		{"250", 'a', "40th anniversary edition"},
+		{"264", 'b', "Ace"},
+		{"264", 'c', "1965"},
+		{"300", 'a', "604 pages"},
+		{"490", 'v', "1"},
+		{"650", 'a', "Science fiction"},
+		{"700", 'a', "Jane Editor"},
+		{"700", 'e', "editor"},
+	}
+	for _, tt := range tests {
+		fields := r.FieldsByTag(tt.tag)
+		if len(fields) != 1 || fields[0].Subfield(tt.code) != tt.want {
+			t.Errorf("%s$%c = %+v, want %q", tt.tag, tt.code, fields, tt.want)
+		}
+	}
+	if got := r.FieldsByTag("001")[0].Value; got != book.ID.String() {
+		t.Errorf("001 = %q", got)
+	}
+	if got := r.FieldsByTag("005")[0].Value; got != "20261001123000.0" {
+		t.Errorf("005 = %q", got)
+	}
+	if title := r.FieldsByTag("245")[0]; title.Ind1 != '1' {
+		t.Errorf("245 indicators %c%c", title.Ind1, title.Ind2)
+	}
+	// Fields without values are left out
+	if fields := r.FieldsByTag("856"); len(fields) != 0 {
+		t.Errorf("856 = %+v", fields)
+	}
+}
+
+// parsedResponse reads back the parts of a response the tests check.
+type parsedResponse struct {
+	XMLName         xml.Name
+	Version         string `xml:"version"`
+	NumberOfRecords int64  `xml:"numberOfRecords"`
+	Records         []struct {
+		Schema   string `xml:"recordSchema"`
+		Packing  string `xml:"recordPacking"`
+		Escaping string `xml:"recordXMLEscaping"`
+		Data     struct {
+			Inner string `xml:",innerxml"`
+		} `xml:"recordData"`
+		Position int `xml:"recordPosition"`
+	} `xml:"records>record"`
+	NextRecordPosition int `xml:"nextRecordPosition"`
+	Diagnostics        []struct {
+		XMLName xml.Name
+		URI     string `xml:"uri"`
+		Details string `xml:"details"`
+	} `xml:"diagnostics>diagnostic"`
+}
+
+func parseResponse(t *testing.T, data []byte) parsedResponse {
+	t.Helper()
+
+	var resp parsedResponse
+	if err := xml.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("parse response: %v\n%s", err, data)
+	}
+	return resp
+}
+
+func TestSearchRetrieve(t *testing.T) {
+	books := []models.Book{sampleBook(), sampleBook()}
+
+	params, _ := url.ParseQuery("version=1.2&operation=searchRetrieve&query=dune&startRecord=3&maximumRecords=2")
+	r, err := ParseRequest(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := SearchRetrieve(r, books, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := parseResponse(t, data)
+	if resp.XMLName.Space != ns12 || resp.XMLName.Local != "searchRetrieveResponse" || resp.Version != Version12 {
+		t.Fatalf("response %v version %s", resp.XMLName, resp.Version)
+	}
+	if resp.NumberOfRecords != 5 || resp.NextRecordPosition != 5 || len(resp.Records) != 2 {
+		t.Fatalf("numberOfRecords %d, next %d, %d records", resp.NumberOfRecords, resp.NextRecordPosition, len(resp.Records))
+	}
+	rec := resp.Records[1]
+	if rec.Position != 4 || rec.Schema != "info:srw/schema/1/dc-v1.1" || rec.Packing != "xml" {
+		t.Fatalf("unexpected record %+v", rec)
+	}
+	var dc struct {
+		Title       string   `xml:"http://purl.org/dc/elements/1.1/ title"`
+		Creator     string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+		Contributor string   `xml:"http://purl.org/dc/elements/1.1/ contributor"`
+		Identifiers []string `xml:"http://purl.org/dc/elements/1.1/ identifier"`
+	}
+	if err := xml.Unmarshal([]byte(rec.Data.Inner), &dc); err != nil {
+		t.Fatal(err)
+	}
+	if dc.Title != "Dune" || dc.Creator != "Frank Herbert" || dc.Contributor != "Jane Editor" || dc.Identifiers[0] != "urn:isbn:9780441172719" {
+		t.Fatalf("unexpected Dublin Core record %+v", dc)
+	}
+
+	// The last page has no next position
+	r.StartRecord = 5
+	resp = parseResponse(t, mustRender(t, r, books[:1], 5))
+	if resp.NextRecordPosition != 0 {
+		t.Fatalf("next = %d on the last page", resp.NextRecordPosition)
+	}
+
+	// Starting past the end is a diagnostic; an empty result is not
+	r.StartRecord = 6
+	resp = parseResponse(t, mustRender(t, r, nil, 5))
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].URI != "info:srw/diagnostic/1/61" {
+		t.Fatalf("diagnostics = %+v", resp.Diagnostics)
+	}
+	resp = parseResponse(t, mustRender(t, r, nil, 0))
+	if len(resp.Diagnostics) != 0 || resp.NumberOfRecords != 0 {
+		t.Fatalf("unexpected empty result %+v", resp)
+	}
+}
+
+func mustRender(t *testing.T, r *Request, books []models.Book, total int64) []byte {
+	t.Helper()
+
+	data, err := SearchRetrieve(r, books, total)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestSearchRetrieveMARCXML(t *testing.T) {
+	books := []models.Book{sampleBook()}
+
+	params, _ := url.ParseQuery("query=dune&recordSchema=marcxml")
+	r, err := ParseRequest(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := parseResponse(t, mustRender(t, r, books, 1))
+	if resp.XMLName.Space != ns20 || resp.Version != Version20 {
+		t.Fatalf("response %v version %s", resp.XMLName, resp.Version)
+	}
+	rec := resp.Records[0]
+	if rec.Schema != "info:srw/schema/1/marcxml-v1.1" || rec.Escaping != "xml" || rec.Packing != "" {
+		t.Fatalf("unexpected record %+v", rec)
+	}
+	records, err := marc.ReadXML(strings.NewReader(rec.Data.Inner))
+	if err != nil || len(records) != 1 {
+		t.Fatalf("read MARCXML: %v, %d records", err, len(records))
+	}
+	if got := records[0].FieldsByTag("245")[0].Subfield('a'); got != "Dune" {
+		t.Fatalf("245$a = %q", got)
+	}
+
+	// Escaped records are the same XML as text
+	r.Escaped = true
+	resp = parseResponse(t, mustRender(t, r, books, 1))
+	rec = resp.Records[0]
+	if rec.Escaping != "string" || !strings.Contains(rec.Data.Inner, "&lt;record xmlns=") {
+		t.Fatalf("unexpected escaped record %+v", rec)
+	}
+}
+
+func TestFailure(t *testing.T) {
+	tests := []struct {
+		params    string
+		name      string
+		namespace string
+	}{
+		{"version=1.2&operation=searchRetrieve&query=dune", "searchRetrieveResponse", diagnosticNS12},
+		{"query=dune", "searchRetrieveResponse", diagnosticNS20},
+		{"version=1.2&operation=explain", "explainResponse", diagnosticNS12},
+	}
+	for _, tt := range tests {
+		params, _ := url.ParseQuery(tt.params)
+		r, _ := ParseRequest(params)
+		data, err := Failure(r, NewDiagnostic(DiagUnsupportedIndex, "dc.date"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp := parseResponse(t, data)
+		if resp.XMLName.Local != tt.name || len(resp.Diagnostics) != 1 {
+			t.Fatalf("%s: unexpected response %+v", tt.params, resp)
+		}
+		d := resp.Diagnostics[0]
+		if d.XMLName.Space != tt.namespace || d.URI != "info:srw/diagnostic/1/16" || d.Details != "dc.date" {
+			t.Fatalf("%s: unexpected diagnostic %+v", tt.params, d)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	r, _ := ParseRequest(url.Values{})
+	data, err := Explain(r, Server{Host: "library.example.org", Port: 443, Database: "api/v1/sru", Title: "Springfield"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp struct {
+		XMLName xml.Name
+		Explain struct {
+			Host     string `xml:"serverInfo>host"`
+			Database string `xml:"serverInfo>database"`
+			Indexes  []struct {
+				Names []string `xml:"map>name"`
+			} `xml:"indexInfo>index"`
+			Schemas []struct {
+				Name string `xml:"name,attr"`
+			} `xml:"schemaInfo>schema"`
+		} `xml:"record>recordData>explain"`
+	}
+	if err := xml.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.XMLName.Local != "explainResponse" || resp.Explain.Host != "library.example.org" || resp.Explain.Database != "api/v1/sru" {
+		t.Fatalf("unexpected explain response:\n%s", data)
+	}
+	if len(resp.Explain.Indexes) != 5 || len(resp.Explain.Schemas) != 2 {
+		t.Fatalf("%d indexes, %d schemas", len(resp.Explain.Indexes), len(resp.Explain.Schemas))
+	}
+	if !strings.Contains(string(data), `<name set="bath">personalName</name>`) {
+		t.Fatalf("explain does not list bath.personalName:\n%s", data)
+	}
+}
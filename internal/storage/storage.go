@@ -0,0 +1,26 @@
+// Package storage keeps binary objects, such as cover images, in a local
+// directory or an S3-compatible bucket, and tells clients where to fetch
+// them.
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound reports a key with no object stored under it.
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage stores objects under slash-separated keys such as
+// "covers/3f2a.../small.jpg".
+type Storage interface {
+	// Put stores data under key, replacing any object already there.
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Get returns the object stored under key.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the object under key; deleting a missing object is
+	// not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns where clients fetch the object under key.
+	URL(key string) string
+}
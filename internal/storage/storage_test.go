@@ -0,0 +1,30 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"library-management-go/internal/storage"
+	"library-management-go/internal/storage/storagetest"
+)
+
+func TestLocal(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		return storage.NewLocal(t.TempDir(), "/media")
+	})
+}
+
+func TestS3(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		return storage.NewS3(storagetest.NewS3Server(t, "covers").Config(), nil)
+	})
+}
+
+func TestLocalRejectsEscapingKeys(t *testing.T) {
+	s := storage.NewLocal(t.TempDir(), "/media")
+	for _, key := range []string{"../outside", "covers/../../outside", "/absolute", ""} {
+		if err := s.Put(context.Background(), key, "text/plain", []byte("x")); err == nil {
+			t.Fatalf("Put(%q) should be rejected", key)
+		}
+	}
+}
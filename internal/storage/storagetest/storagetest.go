@@ -0,0 +1,150 @@
+// Package storagetest is a conformance suite for storage.Storage
+// implementations, and an in-memory stand-in for an S3-compatible service
+// to run it, and the application, against.
+package storagetest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"library-management-go/internal/storage"
+)
+
+// Run exercises a storage returned by newStorage, which must start empty.
+func Run(t *testing.T, newStorage func(t *testing.T) storage.Storage) {
+	ctx := context.Background()
+
+	t.Run("PutGetDelete", func(t *testing.T) {
+		s := newStorage(t)
+		key := "covers/abc/small.jpg"
+		if err := s.Put(ctx, key, "image/jpeg", []byte("first")); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		if err := s.Put(ctx, key, "image/jpeg", []byte("second")); err != nil {
+			t.Fatalf("Put over existing object: %v", err)
+		}
+		data, err := s.Get(ctx, key)
+		if err != nil || string(data) != "second" {
+			t.Fatalf("Get = %q, %v; want the replaced object", data, err)
+		}
+
+		if err := s.Delete(ctx, key); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := s.Get(ctx, key); !errors.Is(err, storage.ErrNotFound) {
+			t.Fatalf("Get after Delete = %v, want ErrNotFound", err)
+		}
+		if err := s.Delete(ctx, key); err != nil {
+			t.Fatalf("Delete of missing object: %v", err)
+		}
+	})
+
+	t.Run("URL", func(t *testing.T) {
+		s := newStorage(t)
+		if url := s.URL("covers/abc/small.jpg"); !strings.HasSuffix(url, "/covers/abc/small.jpg") {
+			t.Fatalf("URL = %q", url)
+		}
+	})
+}
+
+type object struct {
+	contentType string
+	data        []byte
+}
+
+// S3Server is a minimal S3-compatible service keeping objects in memory.
+// It serves PUT, GET and DELETE on path-style object URLs for one bucket
+// and rejects requests that are unsigned or whose payload does not match
+// the hash they were signed with.
+type S3Server struct {
+	*httptest.Server
+	Bucket string
+
+	mu      sync.Mutex
+	objects map[string]object
+}
+
+// NewS3Server starts a stand-in for bucket, closed when t ends.
+func NewS3Server(t *testing.T, bucket string) *S3Server {
+	s := &S3Server{Bucket: bucket, objects: map[string]object{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Config returns the storage configuration for the stand-in.
+func (s *S3Server) Config() storage.S3Config {
+	return storage.S3Config{
+		Endpoint:        s.URL,
+		Region:          "us-east-1",
+		Bucket:          s.Bucket,
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+	}
+}
+
+// Object returns the object stored under key and its content type.
+func (s *S3Server) Object(key string) ([]byte, string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[key]
+	return obj.data, obj.contentType, ok
+}
+
+// Len returns the number of objects stored.
+func (s *S3Server) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.objects)
+}
+
+func (s *S3Server) serve(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=") {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+s.Bucket+"/")
+	if !ok || key == "" {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sum := sha256.Sum256(body)
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+		http.Error(w, "XAmzContentSHA256Mismatch", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		s.objects[key] = object{contentType: r.Header.Get("Content-Type"), data: bytes.Clone(body)}
+	case http.MethodGet:
+		obj, ok := s.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Write(obj.data)
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
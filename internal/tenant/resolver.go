@@ -0,0 +1,82 @@
+package tenant
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"library-management-go/internal/token"
+)
+
+var (
+	ErrInvalidToken   = token.ErrInvalid
+	ErrTenantMismatch = errors.New("token and request name different tenants")
+)
+
+// Resolver works out which tenant a request is addressed to.
+type Resolver struct {
+	// Domain is the base domain tenants are served under, so that
+	// eastside.library.example.org resolves to the tenant "eastside".
+	// Subdomains are ignored when it is empty.
+	Domain string
+	// Secret verifies HS256 bearer tokens carrying a "tenant" claim.
+	// Tokens are ignored when it is empty.
+	Secret string
+}
+
+// Slug returns the slug of the tenant named by a signed bearer token's
+// tenant claim, the tenant header, or the subdomain of host, in that order.
+// A header or subdomain naming a different tenant than the token is
+// rejected. Slug returns "" when nothing names a tenant.
+func (r *Resolver) Slug(host, header, authorization string) (string, error) {
+	claimed, err := r.tokenTenant(authorization)
+	if err != nil {
+		return "", err
+	}
+
+	requested := strings.ToLower(strings.TrimSpace(header))
+	if requested == "" {
+		requested = r.subdomain(host)
+	}
+
+	switch {
+	case claimed == "":
+		return requested, nil
+	case requested != "" && requested != claimed:
+		return "", ErrTenantMismatch
+	default:
+		return claimed, nil
+	}
+}
+
+func (r *Resolver) subdomain(host string) string {
+	if r.Domain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+
+	sub, ok := strings.CutSuffix(host, "."+strings.ToLower(r.Domain))
+	if !ok || strings.Contains(sub, ".") {
+		return ""
+	}
+	return sub
+}
+
+// tokenTenant returns the tenant claim of the bearer token in
+// authorization, or "" if there is no token or it has no such claim.
+func (r *Resolver) tokenTenant(authorization string) (string, error) {
+	raw := token.FromHeader(authorization)
+	if raw == "" || r.Secret == "" {
+		return "", nil
+	}
+
+	claims, err := token.Verify(raw, []byte(r.Secret), time.Now())
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(claims.String("tenant")), nil
+}
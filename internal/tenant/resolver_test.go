@@ -0,0 +1,77 @@
+package tenant
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"library-management-go/internal/token"
+)
+
+// sign returns an HS256 token with claims.
+func sign(t *testing.T, secret string, claims map[string]interface{}) string {
+	t.Helper()
+
+	signed, err := token.Sign(claims, []byte(secret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func TestResolverSlug(t *testing.T) {
+	resolver := &Resolver{Domain: "library.example.org", Secret: "secret"}
+	expires := time.Now().Add(time.Hour).Unix()
+	north := "Bearer " + sign(t, "secret", map[string]interface{}{"tenant": "north", "exp": expires})
+
+	tests := []struct {
+		name          string
+		host          string
+		header        string
+		authorization string
+		want          string
+		wantErr       error
+	}{
+		{"nothing", "localhost:8080", "", "", "", nil},
+		{"header", "localhost", "North", "", "north", nil},
+		{"subdomain", "east.library.example.org:8080", "", "", "east", nil},
+		{"header beats subdomain", "east.library.example.org", "north", "", "north", nil},
+		{"nested subdomain", "a.east.library.example.org", "", "", "", nil},
+		{"other domain", "east.example.com", "", "", "", nil},
+		{"token", "localhost", "", north, "north", nil},
+		{"token and matching header", "localhost", "north", north, "north", nil},
+		{"token and other header", "localhost", "east", north, "", ErrTenantMismatch},
+		{"token and other subdomain", "east.library.example.org", "", north, "", ErrTenantMismatch},
+		{"token without claim", "east.library.example.org", "",
+			"Bearer " + sign(t, "secret", map[string]interface{}{"sub": "someone", "exp": expires}), "east", nil},
+		{"no expiry", "localhost", "",
+			"Bearer " + sign(t, "secret", map[string]interface{}{"tenant": "north"}), "", ErrInvalidToken},
+		{"wrong secret", "localhost", "",
+			"Bearer " + sign(t, "other", map[string]interface{}{"tenant": "north"}), "", ErrInvalidToken},
+		{"expired", "localhost", "",
+			"Bearer " + sign(t, "secret", map[string]interface{}{"tenant": "north", "exp": time.Now().Add(-time.Minute).Unix()}), "", ErrInvalidToken},
+		{"malformed", "localhost", "", "Bearer not-a-token", "", ErrInvalidToken},
+		{"basic auth is ignored", "localhost", "north", "Basic dXNlcjpwYXNz", "north", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.Slug(tt.host, tt.header, tt.authorization)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("slug = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolverWithoutDomainOrSecret(t *testing.T) {
+	resolver := &Resolver{}
+	token := "Bearer " + sign(t, "secret", map[string]interface{}{"tenant": "north"})
+
+	got, err := resolver.Slug("east.library.example.org", "", token)
+	if err != nil || got != "" {
+		t.Fatalf("Slug = %q, %v; want no tenant", got, err)
+	}
+}
@@ -0,0 +1,86 @@
+// Package tenant keeps the libraries sharing a multi-tenant deployment
+// apart. Like package audit it hooks into GORM's callback chain: every
+// query, update and delete of a model with a tenant_id column is limited to
+// the tenant in the request context, and every insert is stamped with it,
+// so the services package cannot read or change another library's records
+// even by accident. Requests without a tenant (single-tenant deployments
+// and background jobs) are not scoped.
+package tenant
+
+import (
+	"reflect"
+
+	"library-management-go/internal/reqctx"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const column = "tenant_id"
+
+// Register installs the tenant callbacks on db. They must run before any
+// other callback that reads or writes rows, so register them before the
+// audit callbacks.
+func Register(db *gorm.DB) error {
+	cb := db.Callback()
+
+	if err := cb.Create().Before("gorm:create").Register("tenant:assign", assign); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("tenant:scope_query", scope); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("tenant:scope_row", scope); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:scope_update", scope); err != nil {
+		return err
+	}
+	return cb.Delete().Before("gorm:delete").Register("tenant:scope_delete", scope)
+}
+
+// tenantField returns the tenant_id field of the statement's model and the
+// tenant of its context, or false if either is missing.
+func tenantField(tx *gorm.DB) (*schema.Field, uuid.UUID, bool) {
+	tenantID := reqctx.TenantID(tx.Statement.Context)
+	if tenantID == uuid.Nil || tx.Statement.Schema == nil {
+		return nil, uuid.Nil, false
+	}
+	field, ok := tx.Statement.Schema.FieldsByDBName[column]
+	return field, tenantID, ok
+}
+
+// scope limits the statement to rows of the context's tenant.
+func scope(tx *gorm.DB) {
+	if _, tenantID, ok := tenantField(tx); ok && tx.Error == nil {
+		tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: column}, Value: tenantID},
+		}})
+	}
+}
+
+// assign stamps new records with the context's tenant, overriding whatever
+// the caller set.
+func assign(tx *gorm.DB) {
+	field, tenantID, ok := tenantField(tx)
+	if !ok || tx.Error != nil {
+		return
+	}
+
+	set := func(record reflect.Value) {
+		if err := field.Set(tx.Statement.Context, record, tenantID); err != nil {
+			tx.AddError(err)
+		}
+	}
+
+	switch rv := reflect.Indirect(tx.Statement.ReflectValue); rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			set(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		set(rv)
+	}
+}
@@ -0,0 +1,473 @@
+package testutil
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"library-management-go/internal/callnumber"
+	"library-management-go/internal/cardnumber"
+	"library-management-go/internal/models"
+	"library-management-go/internal/repository"
+)
+
+// Fixtures creates persisted models with sensible defaults. Every factory
+// takes optional functions that adjust the model before it is saved, and
+// generates unique ISBNs and emails so fixtures never collide.
+type Fixtures struct {
+	t     testing.TB
+	store repository.Store
+	seq   int
+}
+
+func NewFixtures(t testing.TB, store repository.Store) *Fixtures {
+	return &Fixtures{t: t, store: store}
+}
+
+func (f *Fixtures) next() int {
+	f.seq++
+	return f.seq
+}
+
+func (f *Fixtures) Author(opts ...func(*models.Author)) *models.Author {
+	f.t.Helper()
+
+	n := f.next()
+	author := &models.Author{
+		Name:      fmt.Sprintf("Author %d", n),
+		Biography: fmt.Sprintf("Biography %d", n),
+	}
+	for _, opt := range opts {
+		opt(author)
+	}
+
+	if err := f.store.Authors().Create(author); err != nil {
+		f.t.Fatalf("create author fixture: %v", err)
+	}
+	return author
+}
+
+// Book creates an available book credited to author. A new author is
+// created unless one is given.
+func (f *Fixtures) Book(author *models.Author, opts ...func(*models.Book)) *models.Book {
+	f.t.Helper()
+
+	if author == nil {
+		author = f.Author()
+	}
+	n := f.next()
+	book := &models.Book{
+		Title:       fmt.Sprintf("Book %d", n),
+		ISBN:        fmt.Sprintf("978%010d", n),
+		Barcode:     fmt.Sprintf("3%013d", n),
+		Description: fmt.Sprintf("Description %d", n),
+		Contributors: []models.BookContributor{
+			{AuthorID: author.ID, Author: *author, Role: models.RoleAuthor},
+		},
+		PublishedAt: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Available:   true,
+	}
+	for _, opt := range opts {
+		opt(book)
+	}
+
+	// Create replaces false with the column default, so save it separately
+	available := book.Available
+	if err := f.store.Books().Create(book); err != nil {
+		f.t.Fatalf("create book fixture: %v", err)
+	}
+	if !available {
+		book.Available = false
+		if err := f.store.Books().Update(book); err != nil {
+			f.t.Fatalf("mark book fixture unavailable: %v", err)
+		}
+	}
+	return book
+}
+
+// Credited adds author to a book fixture's contributors in role, after
+// those already credited.
+func Credited(author *models.Author, role string) func(*models.Book) {
+	return func(b *models.Book) {
+		b.Contributors = append(b.Contributors, models.BookContributor{
+			AuthorID: author.ID,
+			Author:   *author,
+			Role:     role,
+			Position: len(b.Contributors),
+		})
+	}
+}
+
+func (f *Fixtures) Borrower(opts ...func(*models.Borrower)) *models.Borrower {
+	f.t.Helper()
+
+	n := f.next()
+	cardNumber, err := cardnumber.Generate()
+	if err != nil {
+		f.t.Fatalf("generate card number: %v", err)
+	}
+	borrower := &models.Borrower{
+		Name:            fmt.Sprintf("Borrower %d", n),
+		Email:           fmt.Sprintf("borrower%d@example.com", n),
+		Phone:           fmt.Sprintf("555-%04d", n),
+		Address:         fmt.Sprintf("%d Library Lane", n),
+		CardNumber:      cardNumber,
+		Status:          models.BorrowerActive,
+		MembershipStart: time.Now(),
+	}
+	for _, opt := range opts {
+		opt(borrower)
+	}
+
+	if err := f.store.Borrowers().Create(borrower); err != nil {
+		f.t.Fatalf("create borrower fixture: %v", err)
+	}
+	return borrower
+}
+
+// InCategory puts a borrower fixture in category.
+func InCategory(category *models.BorrowerCategory) func(*models.Borrower) {
+	return func(b *models.Borrower) {
+		b.CategoryID = &category.ID
+		b.Category = category
+	}
+}
+
+// MembershipExpired makes a borrower fixture's membership lapse the given
+// duration ago without its status having been updated yet.
+func MembershipExpired(ago time.Duration) func(*models.Borrower) {
+	return func(b *models.Borrower) {
+		expiresAt := time.Now().Add(-ago)
+		b.MembershipExpiresAt = &expiresAt
+	}
+}
+
+func (f *Fixtures) BorrowerCategory(opts ...func(*models.BorrowerCategory)) *models.BorrowerCategory {
+	f.t.Helper()
+
+	n := f.next()
+	category := &models.BorrowerCategory{
+		Code:           fmt.Sprintf("CAT%d", n),
+		Name:           fmt.Sprintf("Category %d", n),
+		MembershipDays: 365,
+	}
+	for _, opt := range opts {
+		opt(category)
+	}
+
+	if err := f.store.BorrowerCategories().Create(category); err != nil {
+		f.t.Fatalf("create borrower category fixture: %v", err)
+	}
+	return category
+}
+
+// Block places a block on borrower, created by "librarian" for lost card
+// unless opts say otherwise.
+func (f *Fixtures) Block(borrower *models.Borrower, opts ...func(*models.BorrowerBlock)) *models.BorrowerBlock {
+	f.t.Helper()
+
+	block := &models.BorrowerBlock{
+		BorrowerID: borrower.ID,
+		Reason:     models.BlockLostCard,
+		CreatedBy:  "librarian",
+	}
+	for _, opt := range opts {
+		opt(block)
+	}
+
+	if err := f.store.BorrowerBlocks().Create(block); err != nil {
+		f.t.Fatalf("create borrower block fixture: %v", err)
+	}
+	return block
+}
+
+// Borrowing records an active loan of book to borrower due in two weeks and
+// marks the book unavailable and off the shelf, as BorrowBook would. A new
+// book or borrower is created for any that is nil.
+func (f *Fixtures) Borrowing(book *models.Book, borrower *models.Borrower, opts ...func(*models.Borrowing)) *models.Borrowing {
+	f.t.Helper()
+
+	if book == nil {
+		book = f.Book(nil)
+	}
+	if borrower == nil {
+		borrower = f.Borrower()
+	}
+	now := time.Now()
+	borrowing := &models.Borrowing{
+		BookID:     book.ID,
+		BorrowerID: &borrower.ID,
+		BorrowedAt: now,
+		DueDate:    now.Add(14 * 24 * time.Hour),
+		Status:     "borrowed",
+	}
+	for _, opt := range opts {
+		opt(borrowing)
+	}
+
+	if err := f.store.Borrowings().Create(borrowing); err != nil {
+		f.t.Fatalf("create borrowing fixture: %v", err)
+	}
+
+	if borrowing.Status == "borrowed" && book.Available {
+		book.Available = false
+		book.CurrentLocationID = nil
+		if err := f.store.Books().Update(book); err != nil {
+			f.t.Fatalf("mark book fixture unavailable: %v", err)
+		}
+	}
+	borrowing.Book = *book
+	borrowing.Borrower = borrower
+	return borrowing
+}
+
+// Overdue makes a borrowing fixture due the given duration ago.
+func Overdue(by time.Duration) func(*models.Borrowing) {
+	return func(b *models.Borrowing) {
+		b.BorrowedAt = time.Now().Add(-by - 14*24*time.Hour)
+		b.DueDate = time.Now().Add(-by)
+	}
+}
+
+// Returned makes a borrowing fixture a completed loan.
+func Returned(b *models.Borrowing) {
+	returnedAt := b.BorrowedAt.Add(time.Hour)
+	b.ReturnedAt = &returnedAt
+	b.Status = "returned"
+}
+
+// ReturnedAgo makes a borrowing fixture a loan returned on its due date,
+// the given duration ago.
+func ReturnedAgo(ago time.Duration) func(*models.Borrowing) {
+	return func(b *models.Borrowing) {
+		returnedAt := time.Now().Add(-ago)
+		b.BorrowedAt = returnedAt.Add(-14 * 24 * time.Hour)
+		b.DueDate = returnedAt
+		b.ReturnedAt = &returnedAt
+		b.Status = "returned"
+	}
+}
+
+// Hold queues borrower for book. A new book or borrower is created for any
+// that is nil.
+func (f *Fixtures) Hold(book *models.Book, borrower *models.Borrower, opts ...func(*models.Hold)) *models.Hold {
+	f.t.Helper()
+
+	if book == nil {
+		book = f.Book(nil)
+	}
+	if borrower == nil {
+		borrower = f.Borrower()
+	}
+	hold := &models.Hold{
+		BookID:     book.ID,
+		BorrowerID: &borrower.ID,
+		Status:     models.HoldWaiting,
+		PlacedAt:   time.Now(),
+	}
+	for _, opt := range opts {
+		opt(hold)
+	}
+
+	if err := f.store.Holds().Create(hold); err != nil {
+		f.t.Fatalf("create hold fixture: %v", err)
+	}
+	return hold
+}
+
+// Fine charges borrower an outstanding overdue fine of amount.
+func (f *Fixtures) Fine(borrower *models.Borrower, amount int64, opts ...func(*models.Fine)) *models.Fine {
+	f.t.Helper()
+
+	fine := &models.Fine{
+		BorrowerID: borrower.ID,
+		Amount:     amount,
+		Reason:     "overdue",
+		Status:     models.FineOutstanding,
+	}
+	for _, opt := range opts {
+		opt(fine)
+	}
+
+	if err := f.store.Fines().Create(fine); err != nil {
+		f.t.Fatalf("create fine fixture: %v", err)
+	}
+	return fine
+}
+
+func (f *Fixtures) Branch(opts ...func(*models.Branch)) *models.Branch {
+	f.t.Helper()
+
+	n := f.next()
+	branch := &models.Branch{
+		Code:    fmt.Sprintf("BR%d", n),
+		Name:    fmt.Sprintf("Branch %d", n),
+		Address: fmt.Sprintf("%d High Street", n),
+	}
+	for _, opt := range opts {
+		opt(branch)
+	}
+
+	if err := f.store.Branches().Create(branch); err != nil {
+		f.t.Fatalf("create branch fixture: %v", err)
+	}
+	return branch
+}
+
+// Location creates a shelf location. A new branch is created unless one is
+// given.
+func (f *Fixtures) Location(branch *models.Branch, opts ...func(*models.Location)) *models.Location {
+	f.t.Helper()
+
+	if branch == nil {
+		branch = f.Branch()
+	}
+	n := f.next()
+	location := &models.Location{
+		BranchID: branch.ID,
+		Code:     fmt.Sprintf("LOC%d", n),
+		Name:     fmt.Sprintf("Location %d", n),
+	}
+	for _, opt := range opts {
+		opt(location)
+	}
+
+	if err := f.store.Locations().Create(location); err != nil {
+		f.t.Fatalf("create location fixture: %v", err)
+	}
+	location.Branch = branch
+	return location
+}
+
+// ShelvedAt makes a book fixture belong to, and sit at, location.
+func ShelvedAt(location *models.Location) func(*models.Book) {
+	return func(b *models.Book) {
+		b.HomeLocationID = &location.ID
+		b.CurrentLocationID = &location.ID
+	}
+}
+
+// Subject creates a topic in the subject vocabulary, under parent if one is
+// given.
+func (f *Fixtures) Subject(parent *models.Subject, opts ...func(*models.Subject)) *models.Subject {
+	f.t.Helper()
+
+	n := f.next()
+	subject := &models.Subject{
+		Name: fmt.Sprintf("Subject %d", n),
+		Kind: models.SubjectTopic,
+	}
+	if parent != nil {
+		subject.ParentID = &parent.ID
+	}
+	for _, opt := range opts {
+		opt(subject)
+	}
+
+	if err := f.store.Subjects().Create(subject); err != nil {
+		f.t.Fatalf("create subject fixture: %v", err)
+	}
+	return subject
+}
+
+// Tag tags book with subjects.
+func (f *Fixtures) Tag(book *models.Book, subjects ...*models.Subject) {
+	f.t.Helper()
+
+	for _, subject := range subjects {
+		if err := f.store.Books().AddSubject(book.ID, subject.ID); err != nil {
+			f.t.Fatalf("tag book fixture: %v", err)
+		}
+	}
+}
+
+func (f *Fixtures) Publisher(opts ...func(*models.Publisher)) *models.Publisher {
+	f.t.Helper()
+
+	n := f.next()
+	publisher := &models.Publisher{
+		Name:  fmt.Sprintf("Publisher %d", n),
+		Place: "London",
+	}
+	for _, opt := range opts {
+		opt(publisher)
+	}
+
+	if err := f.store.Publishers().Create(publisher); err != nil {
+		f.t.Fatalf("create publisher fixture: %v", err)
+	}
+	return publisher
+}
+
+func (f *Fixtures) Series(opts ...func(*models.Series)) *models.Series {
+	f.t.Helper()
+
+	series := &models.Series{Name: fmt.Sprintf("Series %d", f.next())}
+	for _, opt := range opts {
+		opt(series)
+	}
+
+	if err := f.store.Series().Create(series); err != nil {
+		f.t.Fatalf("create series fixture: %v", err)
+	}
+	return series
+}
+
+func (f *Fixtures) Work(opts ...func(*models.Work)) *models.Work {
+	f.t.Helper()
+
+	work := &models.Work{Title: fmt.Sprintf("Work %d", f.next())}
+	for _, opt := range opts {
+		opt(work)
+	}
+
+	if err := f.store.Works().Create(work); err != nil {
+		f.t.Fatalf("create work fixture: %v", err)
+	}
+	return work
+}
+
+// Volume makes a book fixture the given volume of series.
+func Volume(series *models.Series, volume int) func(*models.Book) {
+	return func(b *models.Book) {
+		b.SeriesID = &series.ID
+		b.SeriesVolume = volume
+	}
+}
+
+// EditionOf makes a book fixture an edition of work.
+func EditionOf(work *models.Work) func(*models.Book) {
+	return func(b *models.Book) {
+		b.WorkID = &work.ID
+	}
+}
+
+// CallNumber gives a book fixture callNumber, classified under the scheme
+// detected from it.
+func CallNumber(callNumber string) func(*models.Book) {
+	return func(b *models.Book) {
+		b.CallNumber = callNumber
+		b.ClassificationScheme = callnumber.Detect(callNumber)
+		b.CallNumberSort, _ = callnumber.SortKey(b.ClassificationScheme, callNumber)
+	}
+}
+
+// SoftDelete deletes record through the store so it lands in the trash.
+func (f *Fixtures) SoftDelete(record interface{}) {
+	f.t.Helper()
+
+	var err error
+	switch r := record.(type) {
+	case *models.Author:
+		err = f.store.Authors().Delete(r)
+	case *models.Book:
+		err = f.store.Books().Delete(r)
+	case *models.Borrower:
+		err = f.store.Borrowers().Delete(r)
+	default:
+		f.t.Fatalf("cannot soft delete %T", record)
+	}
+	if err != nil {
+		f.t.Fatalf("soft delete %T fixture: %v", record, err)
+	}
+}
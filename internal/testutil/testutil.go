@@ -0,0 +1,59 @@
+// Package testutil provides ephemeral databases and model factories for
+// tests. Nothing outside _test.go files should import it.
+package testutil
+
+import (
+	"testing"
+
+	"library-management-go/internal/audit"
+	"library-management-go/internal/database"
+	"library-management-go/internal/repository"
+	"library-management-go/internal/repository/gormstore"
+	"library-management-go/internal/tenant"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// MemoryDatabaseURL names a fresh in-memory SQLite database.
+const MemoryDatabaseURL = "sqlite://:memory:"
+
+// OpenDB connects to databaseURL, installs the tenant and audit callbacks
+// and runs the migrations, closing the connection when the test ends.
+func OpenDB(t testing.TB, databaseURL string) *gorm.DB {
+	t.Helper()
+
+	db, err := database.Open(databaseURL, logger.Default.LogMode(logger.Silent))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := tenant.Register(db); err != nil {
+		t.Fatalf("register tenant callbacks: %v", err)
+	}
+	if err := audit.Register(db); err != nil {
+		t.Fatalf("register audit callbacks: %v", err)
+	}
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// NewDB returns an empty in-memory database private to the test.
+func NewDB(t testing.TB) *gorm.DB {
+	t.Helper()
+	return OpenDB(t, MemoryDatabaseURL)
+}
+
+// NewStore returns a Store over an empty in-memory database private to the
+// test.
+func NewStore(t testing.TB) repository.Store {
+	t.Helper()
+	return gormstore.New(NewDB(t))
+}
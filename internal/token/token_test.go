@@ -0,0 +1,59 @@
+package token
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Now()
+	valid, err := Sign(Claims{"sub": "reader", "exp": now.Add(time.Hour).Unix()}, []byte("secret"))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	expired, _ := Sign(Claims{"sub": "reader", "exp": now.Add(-time.Minute).Unix()}, []byte("secret"))
+	eternal, _ := Sign(Claims{"sub": "reader"}, []byte("secret"))
+	parts := strings.Split(valid, ".")
+	noneAlg := "eyJhbGciOiJub25lIn0." + parts[1] + "." + parts[2]
+
+	tests := []struct {
+		name   string
+		token  string
+		secret string
+		want   error
+	}{
+		{"valid", valid, "secret", nil},
+		{"wrong secret", valid, "other", ErrInvalid},
+		{"expired", expired, "secret", ErrInvalid},
+		{"no expiry", eternal, "secret", ErrInvalid},
+		{"unsigned algorithm", noneAlg, "secret", ErrInvalid},
+		{"malformed", "a.b", "secret", ErrInvalid},
+		{"tampered payload", parts[0] + ".e30." + parts[2], "secret", ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := Verify(tt.token, []byte(tt.secret), now)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Verify error = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && claims.String("sub") != "reader" {
+				t.Fatalf("sub = %q, want reader", claims.String("sub"))
+			}
+		})
+	}
+}
+
+func TestFromHeader(t *testing.T) {
+	for header, want := range map[string]string{
+		"Bearer abc.def.ghi":  "abc.def.ghi",
+		"Bearer  abc.def.ghi": "abc.def.ghi",
+		"Basic dXNlcjpwYXNz":  "",
+		"":                    "",
+	} {
+		if got := FromHeader(header); got != want {
+			t.Errorf("FromHeader(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
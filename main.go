@@ -2,11 +2,19 @@ package main
 
 import (
 	"log"
+	"net"
 	"os"
+	"time"
 
+	"library-management-go/internal/audit"
 	"library-management-go/internal/config"
 	"library-management-go/internal/database"
+	"library-management-go/internal/grpcserver"
+	"library-management-go/internal/repository/gormstore"
 	"library-management-go/internal/routes"
+	"library-management-go/internal/services"
+	"library-management-go/internal/sip2"
+	"library-management-go/internal/tenant"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -20,6 +28,9 @@ func main() {
 
 	// Load configuration
 	cfg := config.Load()
+	if cfg.JWTSecret == "" {
+		log.Println("JWT_SECRET is unset or the example value: self-service sign-in and tenant tokens are disabled")
+	}
 
 	// Initialize database
 	db, err := database.Initialize(cfg.DatabaseURL)
@@ -27,6 +38,17 @@ func main() {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	// Scope every query to the request's tenant; this must run before the
+	// audit callbacks so their reads are scoped too
+	if err := tenant.Register(db); err != nil {
+		log.Fatal("Failed to register tenant callbacks:", err)
+	}
+
+	// Record every mutation in the audit log
+	if err := audit.Register(db); err != nil {
+		log.Fatal("Failed to register audit callbacks:", err)
+	}
+
 	// Run migrations
 	if err := database.Migrate(db); err != nil {
 		log.Fatal("Failed to run migrations:", err)
@@ -41,7 +63,7 @@ func main() {
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key, X-Request-ID, X-Actor, X-Tenant, X-Admin-Token, X-Override-Token, X-Staff-Token")
 		
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -51,8 +73,77 @@ func main() {
 		c.Next()
 	})
 
+	// Purge expired idempotency keys in the background
+	idempotencyService := services.NewIdempotencyService(db, cfg.IdempotencyRetention)
+	go func() {
+		for range time.Tick(time.Hour) {
+			if _, err := idempotencyService.PurgeExpired(); err != nil {
+				log.Println("Failed to purge idempotency keys:", err)
+			}
+		}
+	}()
+
+	// Purge records that have been in the trash past the retention period
+	trashService := services.NewTrashService(gormstore.New(db), cfg.TrashRetention)
+	go func() {
+		for range time.Tick(24 * time.Hour) {
+			if _, err := trashService.PurgeExpired(); err != nil {
+				log.Println("Failed to purge trash:", err)
+			}
+		}
+	}()
+
+	// Mark borrowers whose membership has lapsed as expired
+	borrowerService := services.NewBorrowerService(gormstore.New(db))
+	go func() {
+		for range time.Tick(24 * time.Hour) {
+			if err := borrowerService.UpdateExpiredStatus(); err != nil {
+				log.Println("Failed to update expired memberships:", err)
+			}
+		}
+	}()
+
+	// Detach old returned loans from borrowers who have not opted in to
+	// keeping their reading history
+	privacyService := services.NewPrivacyService(gormstore.New(db), cfg.HistoryRetention)
+	go func() {
+		for range time.Tick(24 * time.Hour) {
+			if _, _, err := privacyService.AnonymizeHistory(); err != nil {
+				log.Println("Failed to anonymize reading history:", err)
+			}
+		}
+	}()
+
 	// Setup routes
-	routes.SetupRoutes(router, db)
+	routes.SetupRoutes(router, db, cfg)
+
+	// Start gRPC server
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		log.Fatal("Failed to listen for gRPC:", err)
+	}
+	grpcServer := grpcserver.New(db, cfg)
+	go func() {
+		log.Printf("gRPC server starting on port %s", cfg.GRPCPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatal("Failed to start gRPC server:", err)
+		}
+	}()
+
+	// Start SIP2 server for self-check kiosks and gates, if configured
+	if cfg.SIPPort != "" {
+		sipListener, err := net.Listen("tcp", ":"+cfg.SIPPort)
+		if err != nil {
+			log.Fatal("Failed to listen for SIP2:", err)
+		}
+		sipServer := sip2.NewServer(gormstore.New(db), cfg.SIPCurrency)
+		go func() {
+			log.Printf("SIP2 server starting on port %s", cfg.SIPPort)
+			if err := sipServer.Serve(sipListener); err != nil {
+				log.Fatal("Failed to start SIP2 server:", err)
+			}
+		}()
+	}
 
 	// Start server
 	port := os.Getenv("PORT")